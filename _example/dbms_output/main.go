@@ -1,12 +1,13 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
 
-	_ "github.com/mattn/go-oci8"
+	oci8 "github.com/mattn/go-oci8"
 )
 
 func main() {
@@ -14,25 +15,31 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	_, err = db.Exec(`BEGIN DBMS_OUTPUT.ENABLE(10000); END;`)
+	defer db.Close()
+
+	ctx := context.Background()
+	// DBMS_OUTPUT is buffered in the session, so use the same connection for all the statements
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	err = oci8.EnableServerOutput(ctx, conn, 10000)
 	if err != nil {
 		log.Fatal(err)
 	}
-	_, err = db.Exec(`BEGIN DBMS_OUTPUT.PUT_LINE('hello'); END;`)
+	_, err = conn.ExecContext(ctx, `BEGIN DBMS_OUTPUT.PUT_LINE('hello'); END;`)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	var lines string
-	var status int
-	_, err = db.Exec(`BEGIN DBMS_OUTPUT.GET_LINE(:lines, :status); END;`,
-		sql.Named("lines", sql.Out{Dest: &lines}),
-		sql.Named("status", sql.Out{Dest: &status, In: true}))
+	lines, err := oci8.GetServerOutput(ctx, conn)
 	if err != nil {
 		log.Fatal(err)
 	}
-	if status == 0 {
-		fmt.Println(lines)
+	for _, line := range lines {
+		fmt.Println(line)
 	}
 }
 
@@ -38,12 +38,11 @@ func main() {
 		fmt.Println(err)
 		return
 	}
-	lastInsertId, err := res.LastInsertId()
+	rowID, err := oci8.GetLastRowID(res)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	rowID := oci8.GetLastInsertId(lastInsertId)
 	var id string
 	err = db.QueryRow("select id from lastinsertid_example where rowid = :1", rowID).Scan(&id)
 	if err != nil {
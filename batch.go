@@ -0,0 +1,571 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+type (
+	// Batch is a bind value that executes a statement once for each of its rows, using array binds,
+	// so the rows are sent with one round trip per chunk instead of one round trip per row.
+	// Pass it as the only argument of ExecContext:
+	//
+	//	rows := [][]interface{}{{1, "a"}, {2, "b"}}
+	//	result, err := db.ExecContext(ctx, "insert into T ( A, B ) values ( :1, :2 )", oci8.Batch{Rows: rows})
+	//
+	// The rows are executed with OCI_BATCH_ERRORS, so a failing row does not stop the other rows,
+	// and all the failing rows are returned in a *BatchError.
+	// Without ContinueOnError the batch is all or nothing: when any row fails, the rows of the batch are rolled back,
+	// to a savepoint when in a transaction. With ContinueOnError the rows that succeeded are kept.
+	// Outside of a transaction the batch is committed once, after all the chunks.
+	//
+	// The values of a column must all be of the same type, or nil. Numbers, strings, []byte, and time.Time are supported,
+	// bools are bound as 1 or 0, and maps and structs with json tags are bound as JSON text.
+	// Strings and []byte up to 32767 bytes are supported, longer values need a LOB bind.
+	// Rows affected is the total for all the rows.
+	Batch struct {
+		// Rows are the bind values of each execute, every row must have the same number of values
+		Rows [][]interface{}
+		// ContinueOnError keeps the rows that succeeded when some rows fail
+		ContinueOnError bool
+		// ChunkSize is the max number of rows sent in one execute. Defaults to 1000 rows, lowered for wide rows
+		// so the array bind buffers of a chunk are at most 16 MB. Each column is one bind whatever the number of rows.
+		ChunkSize int
+	}
+
+	// BatchError is returned when rows of a Batch fail
+	BatchError struct {
+		// RowsAffected is the rows affected by the rows that succeeded, 0 when the batch was rolled back
+		RowsAffected int64
+		// Rows are the failed rows, in row order
+		Rows []BatchRowError
+	}
+
+	// BatchRowError is a failed row of a Batch
+	BatchRowError struct {
+		// Row is the index of the failed row in Batch.Rows
+		Row int
+		// Err is the error of the row
+		Err error
+	}
+
+	// batchBind is the array bind of a Batch column
+	batchBind struct {
+		bindStruct
+		descriptors []unsafe.Pointer // the OCIDateTime descriptors of a time column
+	}
+
+	// batchMixedNumber is the kind of a Batch column with both int64 and float64 values, bound as NUMBER
+	batchMixedNumber struct{}
+)
+
+const (
+	// defaultBatchChunkSize is the number of rows of a Batch execute when Batch.ChunkSize is not set
+	defaultBatchChunkSize = 1000
+	// maxBatchChunkBytes is the max size of the array bind buffers of a Batch execute when Batch.ChunkSize is not set
+	maxBatchChunkBytes = 16 << 20
+	// batchSavepoint is the savepoint of a Batch without ContinueOnError in a transaction
+	batchSavepoint = "OCI8_BATCH_EXEC"
+)
+
+// ErrBatchQuery is returned when a Batch is bound to a query, a Batch can only be executed
+var ErrBatchQuery = errors.New("a Batch can only be used with Exec")
+
+// Error returns the batch error string
+func (batchError *BatchError) Error() string {
+	if len(batchError.Rows) == 0 {
+		return "batch failed"
+	}
+	return strconv.Itoa(len(batchError.Rows)) + " batch rows failed, first row " +
+		strconv.Itoa(batchError.Rows[0].Row) + ": " + batchError.Rows[0].Err.Error()
+}
+
+// Unwrap returns the error of the first failed row
+func (batchError *BatchError) Unwrap() error {
+	if len(batchError.Rows) == 0 {
+		return nil
+	}
+	return batchError.Rows[0].Err
+}
+
+// batchFromNamedValues returns the Batch when it is the only named value
+func batchFromNamedValues(namedValues []driver.NamedValue) (Batch, bool) {
+	if len(namedValues) != 1 {
+		return Batch{}, false
+	}
+	batch, ok := namedValues[0].Value.(Batch)
+	return batch, ok
+}
+
+// execBatch executes the statement for all the rows of the batch, in chunks
+func (stmt *Stmt) execBatch(batch Batch) (driver.Result, error) {
+	if stmt.identityReturning {
+		// a Batch returns no identity values, it is executed with the statement prepared without the RETURNING clause
+		driverStmt, err := stmt.conn.prepare(stmt.ctx, stmt.queryText, false)
+		if err != nil {
+			return nil, err
+		}
+		defer driverStmt.Close()
+		batchStmt := driverStmt.(*Stmt)
+		batchStmt.ctx = stmt.ctx
+		return batchStmt.execBatch(batch)
+	}
+
+	result := &Result{stmt: stmt, rowidErr: ErrNoRowid, identityReturning: stmt.conn.identityReturning, identityErr: ErrNoIdentity}
+	if len(batch.Rows) == 0 {
+		return result, nil
+	}
+	defer stmt.conn.setRequestClientInfo(requestIDFromContext(stmt.ctx))()
+	columns := len(batch.Rows[0])
+	if columns > maxBindCount {
+		return nil, &BindError{Index: maxBindCount, Err: ErrTooManyBinds}
+	}
+	for i, row := range batch.Rows {
+		if len(row) != columns {
+			return nil, fmt.Errorf("batch row %v has %v values, expected %v", i, len(row), columns)
+		}
+	}
+
+	chunkSize := batchChunkSize(batch.ChunkSize, batchRowSize(batch.Rows))
+
+	inTransaction := stmt.conn.inTransaction
+	if !inTransaction {
+		// commit the group commit first, so rolling back the batch does not roll back its statements
+		err := stmt.conn.commitPending()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if inTransaction && !batch.ContinueOnError {
+		_, err := stmt.conn.exec(stmt.ctx, "savepoint "+batchSavepoint, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var batchError BatchError
+	for start := 0; start < len(batch.Rows); start += chunkSize {
+		end := start + chunkSize
+		if end > len(batch.Rows) {
+			end = len(batch.Rows)
+		}
+
+		rowsAffected, rowErrors, err := stmt.execBatchChunk(batch.Rows[start:end])
+		if err != nil {
+			// the error of the execute is returned, not a rollback error
+			_ = stmt.rollbackBatch(inTransaction, batch.ContinueOnError)
+			return nil, err
+		}
+		batchError.RowsAffected += rowsAffected
+		for _, rowError := range rowErrors {
+			rowError.Row += start
+			batchError.Rows = append(batchError.Rows, rowError)
+		}
+	}
+
+	if len(batchError.Rows) > 0 && !batch.ContinueOnError {
+		batchError.RowsAffected = 0
+		err := stmt.rollbackBatch(inTransaction, false)
+		if err != nil {
+			return nil, fmt.Errorf("%v - rollback error: %v", batchError.Error(), err)
+		}
+		return nil, &batchError
+	}
+
+	if !inTransaction {
+		err := stmt.conn.getError(C.OCITransCommit(stmt.conn.svc, stmt.conn.errHandle, 0))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(batchError.Rows) > 0 {
+		return nil, &batchError
+	}
+	result.rowsAffected = batchError.RowsAffected
+	if result.rowsAffected > 0 {
+		result.rowid, result.rowidErr = stmt.getRowid()
+	}
+	return result, nil
+}
+
+// batchChunkSize returns the chunk size of a Batch, or when not set the default chunk size,
+// lowered so the array bind buffers of a chunk with rowSize bytes per row are at most maxBatchChunkBytes
+func batchChunkSize(chunkSize int, rowSize int) int {
+	if chunkSize > 0 {
+		return chunkSize
+	}
+	chunkSize = defaultBatchChunkSize
+	if rowSize > 0 && chunkSize > maxBatchChunkBytes/rowSize {
+		chunkSize = maxBatchChunkBytes / rowSize
+	}
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	return chunkSize
+}
+
+// batchRowSize returns the bind buffer bytes of a row of the rows, the value, indicator, and length of each column.
+// Columns of strings and []byte take the band of their longest value, other columns at most the smallest band.
+func batchRowSize(rows [][]interface{}) int {
+	if len(rows) == 0 {
+		return 0
+	}
+	var rowSize int
+	for column := range rows[0] {
+		maxSize := bindLengthBand(1)
+		for _, row := range rows {
+			var length int
+			switch value := row[column].(type) {
+			case string:
+				length = len(value)
+			case []byte:
+				length = len(value)
+			}
+			if bindLengthBand(length) > maxSize {
+				maxSize = bindLengthBand(length)
+			}
+		}
+		rowSize += maxSize + C.sizeof_sb2 + C.sizeof_ub2
+	}
+	return rowSize
+}
+
+// rollbackBatch rolls back a failed batch, the transaction when not in one, otherwise to the batch savepoint.
+// With continueOnError in a transaction there is no savepoint, the caller decides.
+func (stmt *Stmt) rollbackBatch(inTransaction bool, continueOnError bool) error {
+	if !inTransaction {
+		return stmt.conn.getError(C.OCITransRollback(stmt.conn.svc, stmt.conn.errHandle, 0))
+	}
+	if continueOnError {
+		return nil
+	}
+	_, err := stmt.conn.exec(stmt.ctx, "rollback to savepoint "+batchSavepoint, nil)
+	return err
+}
+
+// execBatchChunk binds the rows as arrays and executes them with OCI_BATCH_ERRORS.
+// Returns the rows affected, the errors of the failed rows, and the error that stopped the execute.
+func (stmt *Stmt) execBatchChunk(rows [][]interface{}) (int64, []BatchRowError, error) {
+	if stmt.ctx.Err() != nil {
+		return 0, nil, stmt.ctx.Err()
+	}
+
+	binds := make([]batchBind, 0, len(rows[0]))
+	defer func() {
+		freeBatchBinds(binds)
+	}()
+	for column := range rows[0] {
+		bind, err := stmt.conn.batchBind(rows, column)
+		if err != nil {
+			freeBatchBinds([]batchBind{bind})
+			return 0, nil, &BindError{Index: column, Err: err}
+		}
+		binds = append(binds, bind)
+
+		err = stmt.ociBindByPos(C.ub4(column+1), &binds[len(binds)-1].bindStruct)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	stop := stmt.conn.watchBreak(stmt.ctx)
+	start := stmt.conn.beforeQuery(stmt.ctx, stmt.hookQuery())
+	err := stmt.ociStmtExecute(C.ub4(len(rows)), C.OCI_BATCH_ERRORS)
+	stop()
+
+	var rowErrors []BatchRowError
+	if err != nil && err != ErrOCISuccessWithInfo {
+		var rowErr error
+		rowErrors, rowErr = stmt.batchRowErrors()
+		if rowErr != nil || len(rowErrors) == 0 {
+			// the execute failed for all the rows, like a missing table
+			stmt.conn.afterExec(stmt.ctx, stmt.hookQuery(), stmt.queryFingerprint(), nil, start, err)
+			return 0, nil, err
+		}
+	} else if err == ErrOCISuccessWithInfo {
+		rowErrors, err = stmt.batchRowErrors()
+		if err != nil {
+			stmt.conn.afterExec(stmt.ctx, stmt.hookQuery(), stmt.queryFingerprint(), nil, start, err)
+			return 0, nil, err
+		}
+	}
+	stmt.conn.afterExec(stmt.ctx, stmt.hookQuery(), stmt.queryFingerprint(), nil, start, nil)
+
+	rowsAffected, err := stmt.rowsAffected()
+	if err != nil {
+		return 0, nil, err
+	}
+	return rowsAffected, rowErrors, nil
+}
+
+// batchRowErrors returns the row errors of an execute with OCI_BATCH_ERRORS
+func (stmt *Stmt) batchRowErrors() ([]BatchRowError, error) {
+	var count C.ub4
+	_, err := stmt.ociAttrGet(unsafe.Pointer(&count), C.OCI_ATTR_NUM_DML_ERRORS)
+	if err != nil || count == 0 {
+		return nil, err
+	}
+
+	handle, _, err := stmt.conn.ociHandleAlloc(C.OCI_HTYPE_ERROR, 0)
+	if err != nil {
+		return nil, err
+	}
+	rowErrHandle := (*C.OCIError)(*handle)
+	defer ociHandleFree(*handle, C.OCI_HTYPE_ERROR)
+
+	rowErrors := make([]BatchRowError, 0, count)
+	for i := C.ub4(0); i < count; i++ {
+		result := C.OCIParamGet(
+			unsafe.Pointer(stmt.conn.errHandle),              // the error handle with the batch errors
+			C.OCI_HTYPE_ERROR,                                // handle type
+			stmt.conn.errHandle,                              // error handle
+			(*unsafe.Pointer)(unsafe.Pointer(&rowErrHandle)), // the error handle of the row error
+			i, // zero based index of the row error
+		)
+		err = stmt.conn.getError(result)
+		if err != nil {
+			return nil, err
+		}
+
+		var offset C.ub4
+		result = C.OCIAttrGet(
+			unsafe.Pointer(rowErrHandle), // error handle of the row error
+			C.OCI_HTYPE_ERROR,            // handle type
+			unsafe.Pointer(&offset),      // the zero based row offset in the array
+			nil,                          // size of the attribute
+			C.OCI_ATTR_DML_ROW_OFFSET,    // attribute type
+			stmt.conn.errHandle,          // error handle
+		)
+		err = stmt.conn.getError(result)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = ociErrorGet(rowErrHandle, stmt.conn.utf16)
+		rowErrors = append(rowErrors, BatchRowError{Row: int(offset), Err: err})
+	}
+
+	return rowErrors, nil
+}
+
+// batchBind allocates the array bind of a column of the rows
+func (conn *Conn) batchBind(rows [][]interface{}, column int) (batchBind, error) {
+	var bind batchBind
+	count := len(rows)
+	values := make([]driver.Value, count)
+
+	// the column type is the type of the not null values, ints with floats are NUMBER,
+	// so ints over 2^53 and the decimal value of floats are exact
+	var kind driver.Value
+	maxSize := 1
+	for i, row := range rows {
+		var value driver.Value
+		text, ok, err := jsonBindValue(row[column])
+		if ok {
+			value = text
+		} else if err == nil {
+			value, err = driver.DefaultParameterConverter.ConvertValue(row[column])
+		}
+		if err != nil {
+			return bind, fmt.Errorf("row %v: %v", i, err)
+		}
+		if boolValue, ok := value.(bool); ok && conn.converter != nil {
+			value, err = conn.converter.ConvertBool(boolValue)
+			if err != nil {
+				return bind, fmt.Errorf("row %v convert bool: %v", i, err)
+			}
+		}
+		if boolValue, ok := value.(bool); ok && conn.yesNoBools {
+			value = yesNoText(boolValue)
+		}
+		if boolValue, ok := value.(bool); ok {
+			if boolValue {
+				value = int64(1)
+			} else {
+				value = int64(0)
+			}
+		}
+		if stringValue, ok := value.(string); ok && conn.utf16 {
+			// the text in the character set of the environment, the max size is in bytes
+			value = string(utf16Encode(stringValue))
+		}
+		values[i] = value
+
+		switch value := value.(type) {
+		case nil:
+			continue
+		case string:
+			if len(value) > maxSize {
+				maxSize = len(value)
+			}
+		case []byte:
+			if len(value) > maxSize {
+				maxSize = len(value)
+			}
+		}
+		switch {
+		case kind == nil:
+			kind = value
+		case fmt.Sprintf("%T", kind) == fmt.Sprintf("%T", value):
+		case isBatchNumber(kind) && isBatchNumber(value):
+			kind = batchMixedNumber{}
+		default:
+			return bind, fmt.Errorf("row %v is %T, other rows are %T", i, value, kind)
+		}
+	}
+	if maxSize > 32767 {
+		return bind, fmt.Errorf("value of %v bytes is longer than 32767 bytes", maxSize)
+	}
+
+	bind.indicator = (*C.sb2)(C.malloc(C.size_t(count) * C.sizeof_sb2))
+	bind.length = (*C.ub2)(C.malloc(C.size_t(count) * C.sizeof_ub2))
+	indicators := (*[1 << 28]C.sb2)(unsafe.Pointer(bind.indicator))[:count:count]
+	lengths := (*[1 << 28]C.ub2)(unsafe.Pointer(bind.length))[:count:count]
+
+	switch kind.(type) {
+	case int64, float64:
+		bind.maxSize = 8
+		if _, ok := kind.(int64); ok {
+			bind.dataType = C.SQLT_INT
+		} else {
+			bind.dataType = C.SQLT_BDOUBLE
+		}
+		bind.pbuf = C.malloc(C.size_t(count) * 8)
+		buffer := (*[1 << 30]byte)(bind.pbuf)[: count*8 : count*8]
+		for i, value := range values {
+			lengths[i] = 8
+			indicators[i] = 0
+			switch value := value.(type) {
+			case nil:
+				indicators[i] = -1
+			case int64:
+				binary.LittleEndian.PutUint64(buffer[i*8:], uint64(value))
+			case float64:
+				binary.LittleEndian.PutUint64(buffer[i*8:], math.Float64bits(value))
+			}
+		}
+
+	case batchMixedNumber:
+		bind.dataType = C.SQLT_NUM
+		bind.maxSize = 22
+		bind.pbuf = C.malloc(C.size_t(count) * 22)
+		buffer := (*[1 << 30]byte)(bind.pbuf)[: count*22 : count*22]
+		for i, value := range values {
+			indicators[i] = 0
+			number, err := batchNumber(value)
+			if err != nil {
+				return bind, fmt.Errorf("row %v: %v", i, err)
+			}
+			if number == nil {
+				indicators[i] = -1
+			}
+			copy(buffer[i*22:], number)
+			lengths[i] = C.ub2(len(number))
+		}
+
+	case time.Time:
+		bind.dataType = C.SQLT_TIMESTAMP_TZ
+		bind.maxSize = C.sb4(sizeOfNilPointer)
+		bind.pbuf = C.malloc(C.size_t(count) * C.size_t(sizeOfNilPointer))
+		pointers := (*[1 << 28]unsafe.Pointer)(bind.pbuf)[:count:count]
+		for i := range pointers {
+			pointers[i] = nil
+		}
+		for i, value := range values {
+			lengths[i] = C.ub2(sizeOfNilPointer)
+			indicators[i] = 0
+			aTime, ok := value.(time.Time)
+			if !ok {
+				indicators[i] = -1
+			}
+			dateTimePP, err := conn.timeToOCIDateTime(&aTime)
+			if err != nil {
+				return bind, fmt.Errorf("row %v: %v", i, err)
+			}
+			pointers[i] = *dateTimePP
+			bind.descriptors = append(bind.descriptors, *dateTimePP)
+		}
+
+	default: // string, []byte, or all null
+		if _, ok := kind.([]byte); ok {
+			bind.dataType = C.SQLT_BIN
+		} else {
+			bind.dataType = C.SQLT_CHR
+		}
+		maxSize = bindLengthBand(maxSize)
+		bind.maxSize = C.sb4(maxSize)
+		bind.pbuf = C.malloc(C.size_t(count) * C.size_t(maxSize))
+		buffer := (*[1 << 30]byte)(bind.pbuf)[: count*maxSize : count*maxSize]
+		for i, value := range values {
+			indicators[i] = 0
+			var data []byte
+			switch value := value.(type) {
+			case nil:
+				indicators[i] = -1
+			case string:
+				data = []byte(value)
+			case []byte:
+				data = value
+			}
+			copy(buffer[i*maxSize:], data)
+			lengths[i] = C.ub2(len(data))
+		}
+	}
+
+	return bind, nil
+}
+
+// isBatchNumber returns true for int64 and float64 values, and the kind of columns with both
+func isBatchNumber(value driver.Value) bool {
+	switch value.(type) {
+	case int64, float64, batchMixedNumber:
+		return true
+	}
+	return false
+}
+
+// batchNumber returns the Oracle NUMBER bytes of an int64 or float64 value, nil for nil.
+// Floats are converted from the shortest decimal that represents them.
+func batchNumber(value driver.Value) ([]byte, error) {
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		return encodeNumber(strconv.FormatInt(value, 10))
+	case float64:
+		number, ok := floatToNumber(value, -1)
+		if !ok {
+			return nil, fmt.Errorf("%v can not be stored in a NUMBER", value)
+		}
+		return number, nil
+	}
+	return nil, fmt.Errorf("%T is not a number", value)
+}
+
+// freeBatchBinds frees the arrays and descriptors of batch binds
+func freeBatchBinds(binds []batchBind) {
+	for _, bind := range binds {
+		for _, descriptor := range bind.descriptors {
+			ociDescriptorFree(descriptor, C.OCI_DTYPE_TIMESTAMP_TZ)
+		}
+		if bind.pbuf != nil {
+			C.free(bind.pbuf)
+		}
+		if bind.length != nil {
+			C.free(unsafe.Pointer(bind.length))
+		}
+		if bind.indicator != nil {
+			C.free(unsafe.Pointer(bind.indicator))
+		}
+	}
+}
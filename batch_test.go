@@ -0,0 +1,218 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDestructiveBatch tests Batch array inserts, with row errors, all or nothing, and continue on error
+func TestDestructiveBatch(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "BATCH_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A NUMBER(2), B VARCHAR2(10), C TIMESTAMP )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	aTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	var rows [][]interface{}
+	for i := 1; i <= 10; i++ {
+		value := int64(i)
+		if i == 4 || i == 9 {
+			// too large for NUMBER(2), ORA-01438
+			value = 1000
+		}
+		rows = append(rows, []interface{}{value, "row " + strconv.Itoa(i), aTime})
+	}
+	rows[1][1] = nil
+	rows[2][2] = nil
+	query := "insert into " + tableName + " ( A, B, C ) values ( :1, :2, :3 )"
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, query, Batch{Rows: rows, ChunkSize: 3})
+	cancel()
+	batchError, ok := err.(*BatchError)
+	if !ok {
+		t.Fatal("all or nothing - expected: *BatchError - received:", err)
+	}
+	if batchError.RowsAffected != 0 || len(batchError.Rows) != 2 || batchError.Rows[0].Row != 3 || batchError.Rows[1].Row != 8 {
+		t.Errorf("all or nothing - received: %+v", batchError)
+	}
+	for _, rowError := range batchError.Rows {
+		if !isOracleError(rowError.Err, 1438) {
+			t.Errorf("row error - expected: ORA-01438 - received: %v", rowError.Err)
+		}
+	}
+
+	queryResults := testQueryResults{
+		query:        "select count(1) from " + tableName,
+		queryResults: []testQueryResult{{results: [][]interface{}{{float64(0)}}}},
+	}
+	testRunQueryResults(t, queryResults)
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, query, Batch{Rows: rows, ChunkSize: 3, ContinueOnError: true})
+	cancel()
+	batchError, ok = err.(*BatchError)
+	if !ok {
+		t.Fatal("continue on error - expected: *BatchError - received:", err)
+	}
+	if batchError.RowsAffected != 8 || len(batchError.Rows) != 2 {
+		t.Errorf("continue on error - received: %+v", batchError)
+	}
+
+	queryResults = testQueryResults{
+		query: "select A, B, nvl2(C, 1, 0) from " + tableName + " where A < 4 order by A",
+		queryResults: []testQueryResult{{results: [][]interface{}{
+			{int64(1), "row 1", float64(1)},
+			{int64(2), nil, float64(1)},
+			{int64(3), "row 3", float64(0)},
+		}}},
+	}
+	testRunQueryResults(t, queryResults)
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	result, err := TestDB.ExecContext(ctx, query, Batch{Rows: [][]interface{}{{20, "a", nil}, {21, "b", aTime}}})
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	var rowsAffected int64
+	rowsAffected, err = result.RowsAffected()
+	if err != nil || rowsAffected != 2 {
+		t.Errorf("rows affected - expected: %v - received: %v %v", 2, rowsAffected, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.QueryContext(ctx, "select A from "+tableName+" where A = :1", Batch{Rows: rows})
+	cancel()
+	if !errors.Is(err, ErrBatchQuery) {
+		t.Errorf("query - expected: %v - received: %v", ErrBatchQuery, err)
+	}
+}
+
+// TestBatchBind tests the column types of Batch array binds and the batch errors
+func TestBatchBind(t *testing.T) {
+	t.Parallel()
+
+	conn := &Conn{}
+	var bindTests = []struct {
+		rows    [][]interface{}
+		maxSize int
+		err     bool
+	}{
+		{rows: [][]interface{}{{1}, {2}, {nil}}, maxSize: 8},
+		{rows: [][]interface{}{{1}, {2.5}}, maxSize: 22},
+		{rows: [][]interface{}{{int64(1) << 60}, {2.5}, {nil}}, maxSize: 22},
+		{rows: [][]interface{}{{1}, {math.NaN()}}, err: true},
+		{rows: [][]interface{}{{true}, {false}}, maxSize: 8},
+		{rows: [][]interface{}{{"a"}, {"abc"}, {nil}}, maxSize: 32},
+		{rows: [][]interface{}{{[]byte{1, 2}}, {[]byte{}}}, maxSize: 32},
+		{rows: [][]interface{}{{nil}, {nil}}, maxSize: 32},
+		{rows: [][]interface{}{{strings.Repeat("a", 129)}}, maxSize: 2000},
+		{rows: [][]interface{}{{strings.Repeat("a", 5000)}}, maxSize: 5000},
+		{rows: [][]interface{}{{1}, {"a"}}, err: true},
+		{rows: [][]interface{}{{strings.Repeat("a", 32768)}}, err: true},
+		{rows: [][]interface{}{{struct{}{}}}, err: true},
+	}
+	for _, tt := range bindTests {
+		bind, err := conn.batchBind(tt.rows, 0)
+		freeBatchBinds([]batchBind{bind})
+		if (err != nil) != tt.err {
+			t.Errorf("batchBind %v - expected error: %v - received: %v", tt.rows[0], tt.err, err)
+			continue
+		}
+		if err == nil && int(bind.maxSize) != tt.maxSize {
+			t.Errorf("batchBind %v maxSize - expected: %v - received: %v", tt.rows[0], tt.maxSize, bind.maxSize)
+		}
+	}
+
+	var numberTests = []struct {
+		value    driver.Value
+		expected string
+	}{
+		{value: int64(9007199254740993), expected: "9007199254740993"},
+		{value: 0.1, expected: "0.1"},
+	}
+	for _, tt := range numberTests {
+		number, err := batchNumber(tt.value)
+		if err != nil {
+			t.Errorf("batchNumber %v - expected error: %v - received: %v", tt.value, nil, err)
+			continue
+		}
+		text, err := decodeNumber(number)
+		if err != nil || text != tt.expected {
+			t.Errorf("batchNumber %v - expected: %v - received: %v %v", tt.value, tt.expected, text, err)
+		}
+	}
+
+	stmt := &Stmt{}
+	err := stmt.CheckNamedValue(&driver.NamedValue{Value: Batch{}})
+	if err != nil {
+		t.Errorf("CheckNamedValue Batch - expected: %v - received: %v", nil, err)
+	}
+
+	rowErr := errors.New("ORA-01438: value larger than specified precision allowed for this column")
+	batchError := &BatchError{Rows: []BatchRowError{{Row: 3, Err: rowErr}, {Row: 8, Err: rowErr}}}
+	expected := "2 batch rows failed, first row 3: " + rowErr.Error()
+	if batchError.Error() != expected {
+		t.Errorf("BatchError - expected: %v - received: %v", expected, batchError.Error())
+	}
+	if !errors.Is(batchError, rowErr) {
+		t.Errorf("BatchError errors.Is - expected: %v - received: %v", true, false)
+	}
+
+	var chunkTests = []struct {
+		chunkSize int
+		rows      [][]interface{}
+		expected  int
+	}{
+		{chunkSize: 0, rows: [][]interface{}{{1, "a", nil}}, expected: defaultBatchChunkSize},
+		{chunkSize: 0, rows: [][]interface{}{make([]interface{}, 100)}, expected: defaultBatchChunkSize},
+		{chunkSize: 0, rows: [][]interface{}{{strings.Repeat("a", 32767)}}, expected: 511},
+		{chunkSize: 0, rows: [][]interface{}{make([]interface{}, 1000)}, expected: 466},
+		{chunkSize: 5000, rows: [][]interface{}{{strings.Repeat("a", 32767)}}, expected: 5000},
+	}
+	for _, tt := range chunkTests {
+		rowSize := batchRowSize(tt.rows)
+		if chunkSize := batchChunkSize(tt.chunkSize, rowSize); chunkSize != tt.expected {
+			t.Errorf("batchChunkSize %v %v - expected: %v - received: %v", tt.chunkSize, rowSize, tt.expected, chunkSize)
+		}
+	}
+}
+
+// TestGetLastRowID tests the rowid of a result
+func TestGetLastRowID(t *testing.T) {
+	t.Parallel()
+
+	var rowidTests = []struct {
+		result driver.Result
+		rowid  string
+		err    error
+	}{
+		{&Result{rowid: "AAAR3sAAEAAAACXAAA"}, "AAAR3sAAEAAAACXAAA", nil},
+		{&Result{rowidErr: ErrNoRowid}, "", ErrNoRowid},
+		{driver.RowsAffected(1), "", errors.New("LastInsertId is not supported by this driver")},
+	}
+	for _, tt := range rowidTests {
+		rowid, err := GetLastRowID(tt.result)
+		if rowid != tt.rowid || (err == nil) != (tt.err == nil) {
+			t.Errorf("GetLastRowID %#v - expected: %v %v - received: %v %v", tt.result, tt.rowid, tt.err, rowid, err)
+		}
+	}
+}
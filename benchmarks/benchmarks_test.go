@@ -0,0 +1,374 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-oci8"
+)
+
+const (
+	// benchmarkWideRows is the number of rows in the wide table
+	benchmarkWideRows = 1000
+	// benchmarkWideColumns is the number of NUMBER and VARCHAR2 column pairs in the wide table, after the INTEGER key
+	benchmarkWideColumns = 10
+	// benchmarkInsertRows is the number of rows inserted by each insert benchmark iteration
+	benchmarkInsertRows = 100
+	// benchmarkLobSize is the size of the LOB read and written by each LOB benchmark iteration
+	benchmarkLobSize = 1 << 20
+)
+
+var (
+	benchmarkDSN                string
+	benchmarkDisableDestructive bool
+	benchmarkTimeout            time.Duration
+	benchmarkDB                 *sql.DB
+
+	benchmarkTimeString  = time.Now().UTC().Format("20060102150405")
+	benchmarkWideTable   = "BENCH_WIDE_" + benchmarkTimeString
+	benchmarkInsertTable = "BENCH_INSERT_" + benchmarkTimeString
+	benchmarkLobTable    = "BENCH_LOB_" + benchmarkTimeString
+	// benchmarkTables are the tables that were created, dropped after the benchmarks
+	benchmarkTables []string
+)
+
+// TestMain opens the database and creates the benchmark tables, when the dsn flag is set
+func TestMain(m *testing.M) {
+	flag.StringVar(&benchmarkDSN, "dsn", "", "the DSN of the Oracle database to benchmark, benchmarks are skipped when empty")
+	flag.BoolVar(&benchmarkDisableDestructive, "disableDestructive", false, "set to true to skip the benchmarks that create tables")
+	flag.DurationVar(&benchmarkTimeout, "contextTimeout", 60*time.Second, "the context timeout of setup and of each benchmark statement")
+	flag.Parse()
+
+	if benchmarkDSN == "" {
+		os.Exit(m.Run())
+	}
+
+	var err error
+	benchmarkDB, err = sql.Open("oci8", benchmarkDSN)
+	if err != nil {
+		fmt.Println("open error:", err)
+		os.Exit(2)
+	}
+
+	if !benchmarkDisableDestructive {
+		err = benchmarkSetup()
+		if err != nil {
+			fmt.Println("setup error:", err)
+			benchmarkTeardown()
+			os.Exit(2)
+		}
+	}
+
+	code := m.Run()
+
+	benchmarkTeardown()
+	os.Exit(code)
+}
+
+// benchmarkExec runs a statement with the benchmark timeout
+func benchmarkExec(query string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), benchmarkTimeout)
+	defer cancel()
+	_, err := benchmarkDB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%v - error: %v", query, err)
+	}
+	return nil
+}
+
+// benchmarkCreateTable creates a table and adds it to the tables to drop
+func benchmarkCreateTable(tableName string, columns string) error {
+	err := benchmarkExec("create table " + tableName + " ( " + columns + " )")
+	if err != nil {
+		return err
+	}
+	benchmarkTables = append(benchmarkTables, tableName)
+	return nil
+}
+
+// benchmarkSetup creates and fills the benchmark tables
+func benchmarkSetup() error {
+	columns := "A INTEGER"
+	values := "level"
+	for i := 1; i <= benchmarkWideColumns; i++ {
+		column := strconv.Itoa(i)
+		columns += ", N" + column + " NUMBER(12,2), S" + column + " VARCHAR2(100)"
+		values += ", level * " + column + " / 100, 'row ' || level || ' column " + column + "'"
+	}
+
+	err := benchmarkCreateTable(benchmarkWideTable, columns)
+	if err != nil {
+		return err
+	}
+	err = benchmarkExec("insert into " + benchmarkWideTable + " select " + values +
+		" from dual connect by level <= " + strconv.Itoa(benchmarkWideRows))
+	if err != nil {
+		return err
+	}
+
+	err = benchmarkCreateTable(benchmarkInsertTable, "A INTEGER, B VARCHAR2(100), C TIMESTAMP")
+	if err != nil {
+		return err
+	}
+
+	err = benchmarkCreateTable(benchmarkLobTable, "A INTEGER, B BLOB")
+	if err != nil {
+		return err
+	}
+	return benchmarkExec("insert into "+benchmarkLobTable+" ( A, B ) values ( 1, :1 )", bytes.Repeat([]byte{'a'}, benchmarkLobSize))
+}
+
+// benchmarkTeardown drops the benchmark tables and closes the database
+func benchmarkTeardown() {
+	for _, tableName := range benchmarkTables {
+		err := benchmarkExec("drop table " + tableName)
+		if err != nil {
+			fmt.Println("drop table error:", err)
+		}
+	}
+	err := benchmarkDB.Close()
+	if err != nil {
+		fmt.Println("close error:", err)
+	}
+}
+
+// benchmarkSkip skips the benchmark when there is no database, or when destructive is true and destructive benchmarks are disabled
+func benchmarkSkip(b *testing.B, destructive bool) {
+	if benchmarkDB == nil || (destructive && benchmarkDisableDestructive) {
+		b.SkipNow()
+	}
+}
+
+// BenchmarkConnect measures opening a new connection, the ping that logs on, and closing it
+func BenchmarkConnect(b *testing.B) {
+	benchmarkSkip(b, false)
+
+	for i := 0; i < b.N; i++ {
+		db, err := sql.Open("oci8", benchmarkDSN)
+		if err != nil {
+			b.Fatal("open error:", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), benchmarkTimeout)
+		err = db.PingContext(ctx)
+		cancel()
+		if err != nil {
+			db.Close()
+			b.Fatal("ping error:", err)
+		}
+
+		err = db.Close()
+		if err != nil {
+			b.Fatal("close error:", err)
+		}
+	}
+}
+
+// BenchmarkSelectDual measures a prepared single row, single column select
+func BenchmarkSelectDual(b *testing.B) {
+	benchmarkSkip(b, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), benchmarkTimeout)
+	stmt, err := benchmarkDB.PrepareContext(ctx, "select :1 from dual")
+	cancel()
+	if err != nil {
+		b.Fatal("prepare error:", err)
+	}
+	defer stmt.Close()
+
+	b.ResetTimer()
+
+	var result int64
+	for i := 0; i < b.N; i++ {
+		ctx, cancel = context.WithTimeout(context.Background(), benchmarkTimeout)
+		err = stmt.QueryRowContext(ctx, int64(i)).Scan(&result)
+		cancel()
+		if err != nil {
+			b.Fatal("scan error:", err)
+		}
+		if result != int64(i) {
+			b.Fatalf("result - expected: %v - received: %v", i, result)
+		}
+	}
+}
+
+// BenchmarkSelectWide measures fetching all the rows of a table with INTEGER, NUMBER, and VARCHAR2 columns
+func BenchmarkSelectWide(b *testing.B) {
+	benchmarkSkip(b, true)
+
+	values := make([]interface{}, 1+2*benchmarkWideColumns)
+	for i := range values {
+		switch {
+		case i == 0:
+			values[i] = new(int64)
+		case i%2 == 1:
+			values[i] = new(float64)
+		default:
+			values[i] = new(string)
+		}
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), benchmarkTimeout)
+		rows, err := benchmarkDB.QueryContext(ctx, "select * from "+benchmarkWideTable)
+		if err != nil {
+			cancel()
+			b.Fatal("query error:", err)
+		}
+
+		count := 0
+		for rows.Next() {
+			err = rows.Scan(values...)
+			if err != nil {
+				rows.Close()
+				cancel()
+				b.Fatal("scan error:", err)
+			}
+			count++
+		}
+		err = rows.Err()
+		if err != nil {
+			rows.Close()
+			cancel()
+			b.Fatal("rows error:", err)
+		}
+		err = rows.Close()
+		cancel()
+		if err != nil {
+			b.Fatal("rows close error:", err)
+		}
+
+		if count != benchmarkWideRows {
+			b.Fatalf("rows - expected: %v - received: %v", benchmarkWideRows, count)
+		}
+	}
+}
+
+// BenchmarkInsert measures inserting rows in a transaction, then rolling it back so the table does not grow.
+// Rows are inserted one execute at a time, compare with BenchmarkInsertBatch.
+func BenchmarkInsert(b *testing.B) {
+	benchmarkSkip(b, true)
+
+	now := time.Now()
+	query := "insert into " + benchmarkInsertTable + " ( A, B, C ) values ( :1, :2, :3 )"
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), benchmarkTimeout)
+		tx, err := benchmarkDB.BeginTx(ctx, nil)
+		if err != nil {
+			cancel()
+			b.Fatal("begin error:", err)
+		}
+
+		var stmt *sql.Stmt
+		stmt, err = tx.PrepareContext(ctx, query)
+		if err != nil {
+			tx.Rollback()
+			cancel()
+			b.Fatal("prepare error:", err)
+		}
+
+		for row := 0; row < benchmarkInsertRows; row++ {
+			_, err = stmt.ExecContext(ctx, int64(row), "row "+strconv.Itoa(row), now)
+			if err != nil {
+				stmt.Close()
+				tx.Rollback()
+				cancel()
+				b.Fatal("exec error:", err)
+			}
+		}
+
+		stmt.Close()
+		err = tx.Rollback()
+		cancel()
+		if err != nil {
+			b.Fatal("rollback error:", err)
+		}
+	}
+}
+
+// BenchmarkInsertBatch measures inserting the same rows as BenchmarkInsert with one oci8.Batch execute,
+// in a transaction that is rolled back so the table does not grow.
+func BenchmarkInsertBatch(b *testing.B) {
+	benchmarkSkip(b, true)
+
+	now := time.Now()
+	query := "insert into " + benchmarkInsertTable + " ( A, B, C ) values ( :1, :2, :3 )"
+	rows := make([][]interface{}, benchmarkInsertRows)
+	for row := range rows {
+		rows[row] = []interface{}{int64(row), "row " + strconv.Itoa(row), now}
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), benchmarkTimeout)
+		tx, err := benchmarkDB.BeginTx(ctx, nil)
+		if err != nil {
+			cancel()
+			b.Fatal("begin error:", err)
+		}
+
+		_, err = tx.ExecContext(ctx, query, oci8.Batch{Rows: rows})
+		if err != nil {
+			tx.Rollback()
+			cancel()
+			b.Fatal("exec error:", err)
+		}
+
+		err = tx.Rollback()
+		cancel()
+		if err != nil {
+			b.Fatal("rollback error:", err)
+		}
+	}
+}
+
+// BenchmarkLobRead measures selecting a BLOB into a []byte
+func BenchmarkLobRead(b *testing.B) {
+	benchmarkSkip(b, true)
+
+	b.SetBytes(benchmarkLobSize)
+	b.ResetTimer()
+
+	var result []byte
+	for i := 0; i < b.N; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), benchmarkTimeout)
+		err := benchmarkDB.QueryRowContext(ctx, "select B from "+benchmarkLobTable+" where A = 1").Scan(&result)
+		cancel()
+		if err != nil {
+			b.Fatal("scan error:", err)
+		}
+		if len(result) != benchmarkLobSize {
+			b.Fatalf("length - expected: %v - received: %v", benchmarkLobSize, len(result))
+		}
+	}
+}
+
+// BenchmarkLobWrite measures updating a BLOB from a []byte, which binds a temporary LOB
+func BenchmarkLobWrite(b *testing.B) {
+	benchmarkSkip(b, true)
+
+	data := bytes.Repeat([]byte{'b'}, benchmarkLobSize)
+
+	b.SetBytes(benchmarkLobSize)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err := benchmarkExec("update "+benchmarkLobTable+" set B = :1 where A = 1", data)
+		if err != nil {
+			b.Fatal("exec error:", err)
+		}
+	}
+}
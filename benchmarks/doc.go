@@ -0,0 +1,18 @@
+/*
+Package benchmarks has the oci8 driver benchmarks, so performance can be compared release to release.
+
+The benchmarks cover connecting, a simple select, a wide select, inserting rows, and LOB read and write.
+They run against the database of the dsn flag and are skipped when it is not set:
+
+	go test -run none -bench . ./benchmarks -args -dsn "scott/tiger@127.0.0.1:1521/ORCLPDB1"
+
+The select wide, insert, and LOB benchmarks create tables, they are skipped with -disableDestructive.
+The tables are named with a time stamp suffix and are dropped when the benchmarks finish.
+The contextTimeout flag sets the timeout of each statement, the default is 60 seconds.
+
+Compare runs with benchstat:
+
+	go test -run none -bench . -count 10 ./benchmarks -args -dsn "..." > new.txt
+	benchstat old.txt new.txt
+*/
+package benchmarks
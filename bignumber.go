@@ -0,0 +1,138 @@
+package oci8
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// bigNumberScale is the number of decimal places a big.Rat is rounded to, the smallest NUMBER is 1e-130
+const bigNumberScale = 130
+
+// isBigNumber returns true for a *big.Int, *big.Rat, or *big.Float
+func isBigNumber(value interface{}) bool {
+	switch value.(type) {
+	case *big.Int, *big.Rat, *big.Float:
+		return true
+	}
+	return false
+}
+
+// bigToNumber converts a *big.Int, *big.Rat, or *big.Float to Oracle NUMBER bytes, without going through float64.
+// Digits past the 40 digit NUMBER precision are rounded half up. Returns nil for a nil pointer.
+func bigToNumber(value interface{}) ([]byte, error) {
+	var number string
+	switch value := value.(type) {
+	case *big.Int:
+		if value == nil {
+			return nil, nil
+		}
+		number = value.String()
+	case *big.Rat:
+		if value == nil {
+			return nil, nil
+		}
+		if value.IsInt() {
+			number = value.Num().String()
+		} else {
+			number = value.FloatString(bigNumberScale)
+		}
+	case *big.Float:
+		if value == nil {
+			return nil, nil
+		}
+		if value.IsInf() {
+			return nil, ErrNumberOverflow
+		}
+		number = value.Text('e', -1)
+	default:
+		return nil, fmt.Errorf("can not convert %T to NUMBER", value)
+	}
+	return encodeNumber(number)
+}
+
+// setBigNumber sets a *big.Int, *big.Rat, or *big.Float to the decimal number string
+func setBigNumber(dest interface{}, number string) error {
+	switch dest := dest.(type) {
+	case *big.Int:
+		rat, ok := new(big.Rat).SetString(number)
+		if !ok {
+			return fmt.Errorf("can not convert %q to *big.Int", number)
+		}
+		if !rat.IsInt() {
+			return fmt.Errorf("can not convert %v to *big.Int without losing the fraction", number)
+		}
+		dest.Set(rat.Num())
+	case *big.Rat:
+		_, ok := dest.SetString(number)
+		if !ok {
+			return fmt.Errorf("can not convert %q to *big.Rat", number)
+		}
+	case *big.Float:
+		_, ok := dest.SetString(number)
+		if !ok {
+			return fmt.Errorf("can not convert %q to *big.Float", number)
+		}
+	default:
+		return fmt.Errorf("can not convert NUMBER to %T", dest)
+	}
+	return nil
+}
+
+// bigNumberScanner is the sql.Scanner of ScanBigNumber
+type bigNumberScanner struct {
+	dest  interface{}
+	valid *bool
+}
+
+// ScanBigNumber returns a sql.Scanner that scans a NUMBER column into dest, a *big.Int, *big.Rat, or *big.Float,
+// for exact arithmetic on values that do not fit in an int64 or float64, like amounts in financial applications:
+//
+//	amount := new(big.Rat)
+//	err = db.QueryRowContext(oci8.WithNumberStrings(ctx, true), "select amount from ledger where id = :1", id).
+//		Scan(oci8.ScanBigNumber(amount, nil))
+//
+// The NUMBER column must be fetched as an exact string, with the number_strings DSN parameter or WithNumberStrings,
+// otherwise columns with decimal places are fetched as float64 and scanning them returns an error.
+// Integer columns, fetched as int64, can be scanned either way.
+// A *big.Float keeps its precision, a *big.Float with zero precision is set to 64 bits, set a larger precision for more digits.
+// valid is set to false for null, and to true otherwise. Scanning null returns an error when valid is nil.
+//
+// The *big.Int, *big.Rat, and *big.Float types can be bound directly, as IN binds and as sql.Out destinations of PL/SQL NUMBER arguments.
+func ScanBigNumber(dest interface{}, valid *bool) sql.Scanner {
+	return &bigNumberScanner{dest: dest, valid: valid}
+}
+
+// Scan implements sql.Scanner
+func (scanner *bigNumberScanner) Scan(src interface{}) error {
+	if src == nil {
+		if scanner.valid == nil {
+			return fmt.Errorf("can not scan null into %T", scanner.dest)
+		}
+		*scanner.valid = false
+		return nil
+	}
+
+	var err error
+	switch src := src.(type) {
+	case string:
+		err = setBigNumber(scanner.dest, src)
+	case []byte:
+		err = setBigNumber(scanner.dest, string(src))
+	case int64:
+		err = setBigNumber(scanner.dest, strconv.FormatInt(src, 10))
+	case float64:
+		err = fmt.Errorf("can not scan float64 %v into %T exactly, fetch NUMBER columns as strings with number_strings or WithNumberStrings", src, scanner.dest)
+	default:
+		err = fmt.Errorf("can not scan %T into %T", src, scanner.dest)
+	}
+	if err != nil {
+		return err
+	}
+
+	if scanner.valid != nil {
+		*scanner.valid = true
+	}
+	return nil
+}
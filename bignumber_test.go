@@ -0,0 +1,130 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// TestBigToNumber tests math/big numbers to NUMBER bytes and back
+func TestBigToNumber(t *testing.T) {
+	t.Parallel()
+
+	bigInt, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	bigFloat, _ := new(big.Float).SetPrec(200).SetString("1.25e-100")
+
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{value: big.NewInt(0), expected: "0"},
+		{value: bigInt, expected: "-123456789012345678901234567890"},
+		{value: big.NewRat(1, 8), expected: "0.125"},
+		{value: big.NewRat(-20, 4), expected: "-5"},
+		{value: big.NewRat(2, 3), expected: "0.6666666666666666666666666666666666666667"},
+		{value: big.NewFloat(1.5), expected: "1.5"},
+		{value: bigFloat, expected: "0." + strings.Repeat("0", 99) + "125"},
+	}
+
+	for _, test := range tests {
+		number, err := bigToNumber(test.value)
+		if err != nil {
+			t.Errorf("bigToNumber %v - expected: %v - received: %v", test.value, nil, err)
+			continue
+		}
+		text, err := decodeNumber(number)
+		if err != nil || text != test.expected {
+			t.Errorf("decodeNumber %v - expected: %v - received: %v %v", test.value, test.expected, text, err)
+		}
+	}
+
+	number, err := bigToNumber((*big.Int)(nil))
+	if err != nil || number != nil {
+		t.Errorf("bigToNumber nil - expected: %v - received: %v %v", nil, number, err)
+	}
+	_, err = bigToNumber(new(big.Float).SetInf(false))
+	if err != ErrNumberOverflow {
+		t.Errorf("bigToNumber Inf - expected: %v - received: %v", ErrNumberOverflow, err)
+	}
+	_, err = bigToNumber(new(big.Int).Exp(big.NewInt(10), big.NewInt(126), nil))
+	if err != ErrNumberOverflow {
+		t.Errorf("bigToNumber 1e126 - expected: %v - received: %v", ErrNumberOverflow, err)
+	}
+
+	scanInt := new(big.Int)
+	var valid bool
+	scanner := ScanBigNumber(scanInt, &valid)
+	if err = scanner.Scan("123456789012345678901234567890"); err != nil || scanInt.String() != "123456789012345678901234567890" || !valid {
+		t.Errorf("Scan string - expected: %v - received: %v %v %v", "123456789012345678901234567890", scanInt, valid, err)
+	}
+	if err = scanner.Scan(int64(-7)); err != nil || scanInt.Int64() != -7 {
+		t.Errorf("Scan int64 - expected: %v - received: %v %v", -7, scanInt, err)
+	}
+	if err = scanner.Scan(nil); err != nil || valid {
+		t.Errorf("Scan nil - expected: %v - received: %v %v", false, valid, err)
+	}
+	for _, src := range []interface{}{"1.5", float64(1), "x"} {
+		if err = scanner.Scan(src); err == nil {
+			t.Errorf("Scan %v - expected: error - received: %v", src, err)
+		}
+	}
+	if err = ScanBigNumber(scanInt, nil).Scan(nil); err == nil {
+		t.Errorf("Scan nil without valid - expected: error - received: %v", err)
+	}
+}
+
+// TestBigNumber checks binding, scanning, and OUT binds of math/big numbers keep all the digits
+func TestBigNumber(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	ctx = WithNumberStrings(ctx, true)
+
+	bigInt, _ := new(big.Int).SetString("-12345678901234567890123456789012345678", 10)
+	bigRat, _ := new(big.Rat).SetString("1234567890123456789.0123456789")
+	bigFloat, _ := new(big.Float).SetPrec(128).SetString("0.1")
+
+	var text1, text2, text3 string
+	err := TestDB.QueryRowContext(ctx, "select to_char(:1, 'TM9'), to_char(:2, 'TM9'), to_char(:3 * 10, 'TM9') from dual", bigInt, bigRat, bigFloat).Scan(&text1, &text2, &text3)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if text1 != "-12345678901234567890123456789012345678" || text2 != "1234567890123456789.0123456789" || text3 != "1" {
+		t.Errorf("bind - expected: %v %v %v - received: %v %v %v", bigInt, bigRat.FloatString(10), 1, text1, text2, text3)
+	}
+
+	scanInt := new(big.Int)
+	scanRat := new(big.Rat)
+	scanFloat := new(big.Float).SetPrec(128)
+	var valid bool
+	err = TestDB.QueryRowContext(ctx, "select :1, :2, :3, cast (null as NUMBER) from dual", bigInt, bigRat, bigFloat).
+		Scan(ScanBigNumber(scanInt, nil), ScanBigNumber(scanRat, nil), ScanBigNumber(scanFloat, nil), ScanBigNumber(new(big.Int), &valid))
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if scanInt.Cmp(bigInt) != 0 || scanRat.Cmp(bigRat) != 0 || scanFloat.Cmp(bigFloat) != 0 || valid {
+		t.Errorf("scan - expected: %v %v %v false - received: %v %v %v %v", bigInt, bigRat, bigFloat, scanInt, scanRat, scanFloat, valid)
+	}
+
+	err = TestDB.QueryRowContext(WithNumberStrings(ctx, false), "select 1.5 from dual").Scan(ScanBigNumber(scanRat, nil))
+	if err == nil {
+		t.Error("scan float64 - expected: error - received: nil")
+	}
+
+	outRat := new(big.Rat)
+	_, err = TestDB.ExecContext(ctx, "begin :1 := :2 / 4; end;", sql.Out{Dest: outRat}, bigInt)
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	expected, _ := new(big.Rat).SetString("-3086419725308641972530864197253086419.5")
+	if outRat.Cmp(expected) != 0 {
+		t.Errorf("out - expected: %v - received: %v", expected, outRat)
+	}
+}
@@ -0,0 +1,70 @@
+package oci8
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestBindLengthBands checks binding strings and bytes with lengths around the bind length bands
+func TestBindLengthBands(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	stmt, err := TestDB.PrepareContext(ctx, "select :1, :2 from dual")
+	if err != nil {
+		t.Fatal("prepare error:", err)
+	}
+	defer stmt.Close()
+
+	for _, length := range []int{1, 31, 32, 33, 128, 129, 1999, 2000, 2001, 4000} {
+		text := strings.Repeat("a", length)
+		data := bytes.Repeat([]byte{1}, length)
+		var textResult string
+		var dataResult []byte
+		err = stmt.QueryRowContext(ctx, text, data).Scan(&textResult, &dataResult)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		if textResult != text {
+			t.Errorf("string of %v - expected: %v - received: %v", length, length, len(textResult))
+		}
+		if !bytes.Equal(dataResult, data) {
+			t.Errorf("bytes of %v - expected: %v - received: %v", length, length, len(dataResult))
+		}
+	}
+}
+
+// TestBindLengthBand tests the max sizes of character and RAW binds
+func TestBindLengthBand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		length int
+		band   int
+	}{
+		{length: 0, band: 32},
+		{length: 32, band: 32},
+		{length: 33, band: 128},
+		{length: 128, band: 128},
+		{length: 129, band: 2000},
+		{length: 2001, band: 4000},
+		{length: 4000, band: 4000},
+		{length: 4001, band: 4001},
+		{length: 32767, band: 32767},
+	}
+
+	for _, test := range tests {
+		band := bindLengthBand(test.length)
+		if band != test.band {
+			t.Errorf("bindLengthBand %v - expected: %v - received: %v", test.length, test.band, band)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Execer runs a statement, it is implemented by *sql.DB, *sql.Conn, and *sql.Tx
+	Execer interface {
+		ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	}
+
+	// BlockBatch queues DML statements and PL/SQL blocks and sends them to the server together,
+	// without waiting for a round trip for each statement.
+	// It does not use the OCI pipelining of Oracle 23ai, the statements are sent as a single anonymous PL/SQL block
+	// in one round trip, so this works with any server and client version. Each statement runs in a nested block
+	// of its own, so a statement error does not stop the statements after it.
+	// Queries that return rows and DDL can not be added to a batch.
+	// A BlockBatch is not safe for concurrent use.
+	BlockBatch struct {
+		execer     Execer
+		statements []blockBatchStatement
+	}
+
+	// BlockBatchResult is the result of a statement sent by a block batch
+	BlockBatchResult struct {
+		// RowsAffected is the number of rows affected by the statement
+		RowsAffected int64
+		// Err is the error of the statement, an *Error with the ORA code and message of SQLCODE and SQLERRM, nil on success
+		Err error
+	}
+
+	blockBatchStatement struct {
+		query string
+		args  []interface{}
+	}
+)
+
+// NewBlockBatch returns a new block batch that sends statements with execer
+func NewBlockBatch(execer Execer) *BlockBatch {
+	return &BlockBatch{execer: execer}
+}
+
+// Add queues a statement to be sent on the next Flush.
+// Bind placeholders are matched to args by name when using sql.Named,
+// otherwise in the order the placeholder names first appear in the statement. Each ? is a placeholder of its own.
+func (batch *BlockBatch) Add(query string, args ...interface{}) error {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	if query == "" {
+		return errors.New("empty block batch statement")
+	}
+
+	index := len(batch.statements) + 1
+	rewritten, names := renamePlaceholders(query, func(name string, position int) string {
+		return "p" + strconv.Itoa(index) + "_" + strconv.Itoa(position+1)
+	})
+
+	positions := make(map[string]int, len(names))
+	for i, name := range names {
+		positions[name] = i
+	}
+
+	statementArgs := make([]interface{}, len(names))
+	set := make([]bool, len(names))
+	for i, arg := range args {
+		position := i
+		if named, ok := arg.(sql.NamedArg); ok {
+			var found bool
+			position, found = positions[strings.ToUpper(named.Name)]
+			if !found {
+				return fmt.Errorf("block batch statement %v has no placeholder named %v", index, named.Name)
+			}
+			arg = named.Value
+		} else if position >= len(names) {
+			return fmt.Errorf("block batch statement %v has %v placeholders, received %v args", index, len(names), len(args))
+		}
+		statementArgs[position] = sql.Named("p"+strconv.Itoa(index)+"_"+strconv.Itoa(position+1), arg)
+		set[position] = true
+	}
+	for i := range set {
+		if !set[i] {
+			return fmt.Errorf("block batch statement %v has no arg for placeholder %v", index, names[i])
+		}
+	}
+
+	batch.statements = append(batch.statements, blockBatchStatement{query: rewritten, args: statementArgs})
+	return nil
+}
+
+// Len returns the number of queued statements
+func (batch *BlockBatch) Len() int {
+	return len(batch.statements)
+}
+
+// Flush sends the queued statements in one round trip and returns their results in the order they were added.
+// A statement error does not stop the following statements, it is returned in its result.
+// The returned error is for failures sending the statements, in which case no results are returned.
+// The queue is cleared in both cases.
+func (batch *BlockBatch) Flush(ctx context.Context) ([]BlockBatchResult, error) {
+	statements := batch.statements
+	batch.statements = nil
+	if len(statements) < 1 {
+		return nil, nil
+	}
+
+	type statementOutput struct {
+		rows    sql.NullInt64
+		code    sql.NullInt64
+		message sql.NullString
+	}
+	outputs := make([]statementOutput, len(statements))
+
+	var block strings.Builder
+	var args []interface{}
+	block.WriteString("begin\n")
+	for i, statement := range statements {
+		prefix := "p" + strconv.Itoa(i+1) + "_"
+		block.WriteString("begin\n")
+		block.WriteString(statement.query)
+		block.WriteString(";\n:" + prefix + "rows := sql%rowcount;\n")
+		block.WriteString("exception when others then\n:" + prefix + "code := sqlcode;\n:" + prefix + "message := sqlerrm;\nend;\n")
+		args = append(args, statement.args...)
+		args = append(args,
+			sql.Named(prefix+"rows", sql.Out{Dest: &outputs[i].rows}),
+			sql.Named(prefix+"code", sql.Out{Dest: &outputs[i].code}),
+			sql.Named(prefix+"message", sql.Out{Dest: &outputs[i].message}),
+		)
+	}
+	block.WriteString("end;")
+
+	_, err := batch.execer.ExecContext(ctx, block.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BlockBatchResult, len(statements))
+	for i := range outputs {
+		if outputs[i].code.Valid && outputs[i].code.Int64 != 0 {
+			results[i].Err = blockBatchError(outputs[i].code.Int64, outputs[i].message.String)
+			continue
+		}
+		results[i].RowsAffected = outputs[i].rows.Int64
+	}
+
+	return results, nil
+}
+
+// blockBatchError returns the *Error of the SQLCODE and SQLERRM of a block batch statement.
+// SQLCODE is the negative ORA code, except 100 for ORA-01403: no data found, and 1 for user-defined exceptions, which have no ORA code.
+func blockBatchError(sqlCode int64, message string) *Error {
+	code := int(-sqlCode)
+	switch {
+	case sqlCode == 100:
+		code = 1403
+	case sqlCode > 0:
+		code = 0
+	}
+	if message == "" {
+		message = fmt.Sprintf("ORA-%05d", code)
+	}
+	return &Error{Code: code, Message: message}
+}
+
+// renamePlaceholders replaces the bind placeholders in query with the names returned by rename,
+// skipping string literals, q-quoted literals, quoted identifiers, and comments. Each ? is a placeholder of its own.
+// Returns the new query and the distinct upper case placeholder names in the order they first appear,
+// rename is called with the name and its index in that order.
+func renamePlaceholders(query string, rename func(name string, position int) string) (string, []string) {
+	var builder strings.Builder
+	var names []string
+	positions := make(map[string]int)
+	placeholder := func(name string) {
+		position, ok := positions[name]
+		if !ok {
+			position = len(names)
+			positions[name] = position
+			names = append(names, name)
+		}
+		builder.WriteString(":" + rename(name, position))
+	}
+
+	runes := []rune(query)
+	questionMarks := 0
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case r == '\'':
+			i = skipStringLiteral(runes, i)
+
+		case (r == 'n' || r == 'N') && i+1 < len(runes) && runes[i+1] == '\'' && !isIdentifierRune(runes, i-1):
+			i = skipStringLiteral(runes, i+1)
+
+		case (r == 'q' || r == 'Q') && i+2 < len(runes) && runes[i+1] == '\'' && !isIdentifierRune(runes, i-1):
+			i = skipQuotedLiteral(runes, i+1)
+
+		case (r == 'n' || r == 'N') && i+3 < len(runes) && (runes[i+1] == 'q' || runes[i+1] == 'Q') &&
+			runes[i+2] == '\'' && !isIdentifierRune(runes, i-1):
+			i = skipQuotedLiteral(runes, i+2)
+
+		case r == '"':
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				i++
+			}
+			if i < len(runes) {
+				i++
+			}
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+			if i > len(runes) {
+				i = len(runes)
+			}
+
+		case r == ':' && i+1 < len(runes) && isIdentifierPart(runes[i+1]):
+			i++
+			for i < len(runes) && isIdentifierPart(runes[i]) {
+				i++
+			}
+			placeholder(strings.ToUpper(string(runes[start+1 : i])))
+			continue
+
+		case r == '?':
+			i++
+			questionMarks++
+			placeholder("?" + strconv.Itoa(questionMarks))
+			continue
+
+		default:
+			i++
+		}
+		builder.WriteString(string(runes[start:i]))
+	}
+
+	return builder.String(), names
+}
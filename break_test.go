@@ -0,0 +1,98 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestAfterBreakHook checks the AfterBreak hook is called when a context timeout breaks a call
+func TestAfterBreakHook(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	conn, err := db.Conn(ctx)
+	cancel()
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	infos := make(chan BreakInfo, 1)
+	err = conn.Raw(func(driverConn interface{}) error {
+		driverConn.(*Conn).hooks.AfterBreak = func(ctx context.Context, info BreakInfo) {
+			infos <- info
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("raw error:", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	_, err = conn.ExecContext(ctx, "begin SYS.DBMS_LOCK.SLEEP(1); end;")
+	cancel()
+	if err == nil {
+		t.Fatal("expected exec error")
+	}
+
+	select {
+	case info := <-infos:
+		if !info.Acknowledged || info.Err != nil {
+			t.Errorf("break info - received: %+v", info)
+		}
+	case <-time.After(TestContextTimeout):
+		t.Fatal("AfterBreak was not called")
+	}
+}
+
+// TestAfterBreak tests the AfterBreak hook reports if the cancelled call returned within the threshold
+func TestAfterBreak(t *testing.T) {
+	t.Parallel()
+
+	// zero value hooks do not wait
+	conn := &Conn{}
+	conn.afterBreak(context.Background(), make(chan struct{}), time.Now(), nil)
+
+	var infos []BreakInfo
+	conn.hooks = Hooks{
+		AfterBreak:     func(ctx context.Context, info BreakInfo) { infos = append(infos, info) },
+		BreakThreshold: 20 * time.Millisecond,
+	}
+
+	done := make(chan struct{})
+	close(done)
+	conn.afterBreak(context.Background(), done, time.Now(), nil)
+
+	testErr := errors.New("test error")
+	conn.afterBreak(context.Background(), make(chan struct{}), time.Now(), testErr)
+
+	if len(infos) != 2 {
+		t.Fatalf("infos - received: %+v", infos)
+	}
+	if !infos[0].Acknowledged || infos[0].Err != nil || infos[0].Duration >= 20*time.Millisecond {
+		t.Errorf("acknowledged - received: %+v", infos[0])
+	}
+	if infos[1].Acknowledged || infos[1].Err != testErr || infos[1].Duration < 20*time.Millisecond {
+		t.Errorf("not acknowledged - received: %+v", infos[1])
+	}
+}
@@ -73,10 +73,17 @@ func cGoStringN(s *C.OraText, size int) string {
 // freeDefines frees defines
 func freeDefines(defines []defineStruct) {
 	for i := 0; i < len(defines); i++ {
-		if len(defines[i].subDefines) > 0 {
-			freeDefines(defines[i].subDefines)
+		if defines[i].nestedRows != nil {
+			// before the statement handle of the nested cursor is freed
+			defines[i].nestedRows.Close()
+			defines[i].nestedRows = nil
+		}
+		if defines[i].objectConn != nil && defines[i].pbuf != nil {
+			defines[i].objectConn.freeObject(defines[i].pbuf)
+		}
+		if defines[i].arraySize > 0 {
+			freeDefineArrays(&defines[i])
 		}
-		defines[i].subDefines = nil
 		if defines[i].pbuf != nil {
 			freeBuffer(defines[i].pbuf, defines[i].dataType)
 			defines[i].pbuf = nil
@@ -93,11 +100,21 @@ func freeDefines(defines []defineStruct) {
 	}
 }
 
-// freeBinds frees binds
-func freeBinds(binds []bindStruct) {
+// freeBinds frees binds, and the temporary LOBs the driver created for them
+func (conn *Conn) freeBinds(binds []bindStruct) {
 	for _, bind := range binds {
 		if bind.pbuf != nil {
-			freeBuffer(bind.pbuf, bind.dataType)
+			if bind.borrowed {
+				C.free(bind.pbuf)
+			} else {
+				if bind.dataType == C.SQLT_BLOB || bind.dataType == C.SQLT_CLOB {
+					conn.freeTemporaryLob(*(**C.OCILobLocator)(bind.pbuf))
+				}
+				if bind.dataType == C.SQLT_NTY {
+					conn.freeObject(bind.pbuf)
+				}
+				freeBuffer(bind.pbuf, bind.dataType)
+			}
 			bind.pbuf = nil
 		}
 		if bind.length != nil {
@@ -108,28 +125,46 @@ func freeBinds(binds []bindStruct) {
 			C.free(unsafe.Pointer(bind.indicator))
 			bind.indicator = nil
 		}
+		if bind.currentLength != nil {
+			C.free(unsafe.Pointer(bind.currentLength))
+			bind.currentLength = nil
+		}
 		bind.bindHandle = nil // freed by oci statement close
 	}
 }
 
+// ociHandleFree calls OCIHandleFree and stops tracking the handle for the leak report
+func ociHandleFree(handle unsafe.Pointer, handleType C.ub4) C.sword {
+	leakFree(handle)
+	return C.OCIHandleFree(handle, handleType)
+}
+
+// ociDescriptorFree calls OCIDescriptorFree and stops tracking the descriptor for the leak report
+func ociDescriptorFree(descriptor unsafe.Pointer, descriptorType C.ub4) C.sword {
+	leakFree(descriptor)
+	return C.OCIDescriptorFree(descriptor, descriptorType)
+}
+
 // freeBuffer calles OCIDescriptorFree to free double pointer to buffer
 // or calles C free to free pointer to buffer
 func freeBuffer(buffer unsafe.Pointer, dataType C.ub2) {
 	switch dataType {
 	case C.SQLT_CLOB, C.SQLT_BLOB:
-		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_LOB)
+		ociDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_LOB)
 	case C.SQLT_TIMESTAMP:
-		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_TIMESTAMP)
+		ociDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_TIMESTAMP)
 	case C.SQLT_TIMESTAMP_TZ:
-		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_TIMESTAMP_TZ)
+		ociDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_TIMESTAMP_TZ)
 	case C.SQLT_TIMESTAMP_LTZ:
-		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_TIMESTAMP_LTZ)
+		ociDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_TIMESTAMP_LTZ)
 	case C.SQLT_INTERVAL_DS:
-		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_INTERVAL_DS)
+		ociDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_INTERVAL_DS)
 	case C.SQLT_INTERVAL_YM:
-		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_INTERVAL_YM)
+		ociDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_INTERVAL_YM)
 	case C.SQLT_RSET:
-		C.OCIDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_HTYPE_STMT)
+		ociHandleFree(*(*unsafe.Pointer)(buffer), C.OCI_HTYPE_STMT)
+	case C.SQLT_VEC:
+		ociDescriptorFree(*(*unsafe.Pointer)(buffer), C.OCI_DTYPE_VECTOR)
 	default:
 		C.free(buffer)
 	}
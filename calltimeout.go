@@ -0,0 +1,69 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"math"
+	"time"
+	"unsafe"
+)
+
+/*
+With an Oracle 18c or later client, the deadline of the context of a call also sets OCI_ATTR_CALL_TIMEOUT,
+the max time of each round trip of the call. A round trip that does not return in time fails with
+ORA-03156: OCI call timed out, even when the network is dead and the OCIBreak sent for the done context
+hangs itself. The call timeout is turned off again when the call returns, so the calls that are not watched,
+like commits and LOB reads, do not run under an old deadline. Contexts that are cancelled without a deadline
+are still only interrupted with OCIBreak, which is also all older clients use. Set the call_timeout DSN parameter to false to only use OCIBreak.
+*/
+
+// setCallTimeout sets the call timeout of the service context to the time left until the deadline of ctx
+// on the clock of the connection, rounded up to a millisecond. Does nothing when ctx has no deadline.
+func (conn *Conn) setCallTimeout(ctx context.Context) {
+	if !conn.callTimeouts {
+		return
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+
+	var timeout C.ub4
+	left := (deadline.Sub(conn.now()) + time.Millisecond - 1) / time.Millisecond
+	switch {
+	case left < 1:
+		// the watchdog breaks the call, a 0 call timeout would mean no timeout
+		timeout = 1
+	case left > math.MaxUint32:
+		timeout = math.MaxUint32
+	default:
+		timeout = C.ub4(left)
+	}
+	conn.ociSetCallTimeout(timeout)
+}
+
+// clearCallTimeout turns off the call timeout set for a watched call
+func (conn *Conn) clearCallTimeout() {
+	if !conn.callTimeouts {
+		return
+	}
+	conn.ociSetCallTimeout(0)
+}
+
+// ociSetCallTimeout sets the OCI_ATTR_CALL_TIMEOUT of the service context in milliseconds, 0 is no timeout
+func (conn *Conn) ociSetCallTimeout(timeout C.ub4) {
+	if timeout == conn.callTimeout {
+		return
+	}
+
+	err := conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&timeout), 0, C.OCI_ATTR_CALL_TIMEOUT)
+	if err != nil {
+		// like a client that reports a version it does not have, do not try again
+		conn.logger.Print("call timeout attribute set error: ", err)
+		conn.callTimeouts = false
+		return
+	}
+	conn.callTimeout = timeout
+}
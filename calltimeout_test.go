@@ -0,0 +1,43 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"testing"
+)
+
+// TestCallTimeoutCleared tests the call timeout of a call with a deadline is turned off when the call returns
+func TestCallTimeoutCleared(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	var result int64
+	err = conn.QueryRowContext(ctx, "select 1 from dual").Scan(&result)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		callTimeout := driverConn.(*Conn).callTimeout
+		if callTimeout != 0 {
+			t.Errorf("callTimeout - expected: %v - received: %v", 0, callTimeout)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("raw error:", err)
+	}
+}
@@ -0,0 +1,263 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type (
+	// ChangePollOptions are the options of PollChanges
+	ChangePollOptions struct {
+		// Columns are the columns to select, all columns when empty. Each must be a valid unquoted identifier.
+		Columns []string
+		// BatchSize is the max number of rows in a ChangeBatch, defaults to 1000.
+		// A batch has more rows when more rows than BatchSize have the same ORA_ROWSCN, as they can not be split.
+		BatchSize int
+		// Interval is the time to wait before polling again when there are no changed rows or polling failed, defaults to 1 second
+		Interval time.Duration
+		// LoadCheckpoint returns the checkpoint saved by SaveCheckpoint, to continue from it when polling starts.
+		// When nil polling starts from checkpoint 0, which selects all the rows.
+		LoadCheckpoint func(ctx context.Context) (int64, error)
+		// SaveCheckpoint saves the checkpoint of a batch, it is called by ChangeBatch.Commit
+		SaveCheckpoint func(ctx context.Context, checkpoint int64) error
+		// Clock is the source of time of the interval, nil uses the system clock
+		Clock Clock
+	}
+
+	// ChangeBatch is a batch of rows changed after the checkpoint of the previous batch, sent by PollChanges
+	ChangeBatch struct {
+		// Columns are the column names
+		Columns []string
+		// Rows are the changed rows in ORA_ROWSCN order, using the same values as Rows.Next
+		Rows [][]interface{}
+		// SCNs are the ORA_ROWSCN of each row
+		SCNs []int64
+		// Checkpoint is the highest ORA_ROWSCN of the rows, the next batch has the rows changed after it
+		Checkpoint int64
+		// Err is set when polling failed, polling is tried again after the interval
+		Err error
+
+		saveCheckpoint func(ctx context.Context, checkpoint int64) error
+	}
+)
+
+const (
+	// defaultChangeBatchSize is the number of rows in a ChangeBatch when ChangePollOptions.BatchSize is not set
+	defaultChangeBatchSize = 1000
+	// defaultChangeInterval is the time between polls when ChangePollOptions.Interval is not set
+	defaultChangeInterval = time.Second
+)
+
+// PollChanges polls a table for the rows with an ORA_ROWSCN greater than a checkpoint, for lightweight change capture
+// without GoldenGate, and sends them in batches on the returned channel. After a batch is processed, call its Commit
+// to save the checkpoint with SaveCheckpoint, so polling continues after it when restarted.
+// The rows are fetched with FetchBatch, and polling waits Interval when there are no changed rows.
+// The table name is a valid unquoted identifier, optionally with the owner, like OWNER.TABLE_NAME.
+// Needs Oracle 12c or later.
+//
+// ORA_ROWSCN is the commit SCN of the block of a row, or of the row for tables created with ROWDEPENDENCIES,
+// so rows that did not change are sent again when another row of their block changes,
+// and rows are sent again after a restart when their batch was not committed. Consumers must handle rows sent more than once.
+// Deleted rows are not seen.
+//
+// The channel is closed when ctx is done, the channel must be read until it is closed.
+// When polling fails a batch with Err set is sent, and polling is tried again after Interval.
+func PollChanges(ctx context.Context, db *sql.DB, tableName string, options ChangePollOptions) (<-chan ChangeBatch, error) {
+	for _, name := range strings.Split(tableName, ".") {
+		err := ValidateIdentifier(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	columns := "t.*"
+	if len(options.Columns) > 0 {
+		for _, column := range options.Columns {
+			err := ValidateIdentifier(column)
+			if err != nil {
+				return nil, err
+			}
+		}
+		columns = strings.Join(options.Columns, ", ")
+	}
+	if options.BatchSize < 1 {
+		options.BatchSize = defaultChangeBatchSize
+	}
+	if options.Interval <= 0 {
+		options.Interval = defaultChangeInterval
+	}
+
+	var checkpoint int64
+	if options.LoadCheckpoint != nil {
+		var err error
+		checkpoint, err = options.LoadCheckpoint(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	selectQuery := "select ora_rowscn, " + columns + " from " + tableName + " t"
+	afterQuery := selectQuery + " where ora_rowscn > :1 order by ora_rowscn fetch first :2 rows only"
+	equalQuery := selectQuery + " where ora_rowscn = :1"
+
+	batches := make(chan ChangeBatch)
+	go func() {
+		defer close(batches)
+		for {
+			batch, full, err := pollChangeBatch(ctx, db, afterQuery, equalQuery, checkpoint, options.BatchSize)
+			if err != nil {
+				batch = ChangeBatch{Err: err}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if batch.Err != nil || len(batch.Rows) > 0 {
+				batch.saveCheckpoint = options.SaveCheckpoint
+				select {
+				case batches <- batch:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if batch.Err == nil && full {
+				// there are probably more changed rows
+				checkpoint = batch.Checkpoint
+				continue
+			}
+			if batch.Err == nil && len(batch.Rows) > 0 {
+				checkpoint = batch.Checkpoint
+			}
+
+			timer := clockOrSystem(options.Clock).NewTimer(options.Interval)
+			select {
+			case <-timer.C():
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+
+	return batches, nil
+}
+
+// Commit saves the checkpoint of the batch with SaveCheckpoint, call it after the rows of the batch are processed.
+// Does nothing when SaveCheckpoint is nil or the batch has an error.
+func (batch ChangeBatch) Commit(ctx context.Context) error {
+	if batch.saveCheckpoint == nil || batch.Err != nil {
+		return nil
+	}
+	return batch.saveCheckpoint(ctx, batch.Checkpoint)
+}
+
+// pollChangeBatch selects the rows changed after the checkpoint, up to batchSize rows.
+// The rows with the highest ORA_ROWSCN of a full batch are left for the next batch, as there can be more rows with it,
+// unless all the rows have it, then all the rows with it are selected.
+// full is true when the select returned batchSize rows, so there are probably more changed rows to poll right away.
+func pollChangeBatch(ctx context.Context, db *sql.DB, afterQuery string, equalQuery string, checkpoint int64, batchSize int) (batch ChangeBatch, full bool, err error) {
+	batch, err = fetchChangeBatch(ctx, db, afterQuery, checkpoint, int64(batchSize))
+	if err != nil || len(batch.Rows) < batchSize {
+		return batch, false, err
+	}
+
+	keep := changeBatchKeep(batch.SCNs)
+	if keep < 1 {
+		batch, err = fetchChangeBatch(ctx, db, equalQuery, batch.SCNs[0])
+		return batch, true, err
+	}
+	batch.Rows = batch.Rows[:keep]
+	batch.SCNs = batch.SCNs[:keep]
+	batch.Checkpoint = batch.SCNs[keep-1]
+	return batch, true, nil
+}
+
+// changeBatchKeep returns the number of rows of a full batch to keep, leaving out the rows with the highest SCN.
+// Returns 0 when all the rows have the same SCN.
+func changeBatchKeep(scns []int64) int {
+	keep := len(scns)
+	for keep > 0 && scns[keep-1] == scns[len(scns)-1] {
+		keep--
+	}
+	return keep
+}
+
+// fetchChangeBatch runs a change query with FetchBatch and returns its rows, the first column is the ORA_ROWSCN
+func fetchChangeBatch(ctx context.Context, db *sql.DB, query string, args ...int64) (ChangeBatch, error) {
+	var batch ChangeBatch
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return batch, err
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		oci8Conn, ok := driverConn.(*Conn)
+		if !ok {
+			return fmt.Errorf("polling changes needs an oci8 connection, received %T", driverConn)
+		}
+
+		driverStmt, err := oci8Conn.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		stmt := driverStmt.(*Stmt)
+		defer stmt.Close()
+
+		namedValues := make([]driver.NamedValue, len(args))
+		for i, arg := range args {
+			namedValues[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+		}
+		driverRows, err := stmt.QueryContext(ctx, namedValues)
+		if err != nil {
+			return err
+		}
+		rows := driverRows.(*Rows)
+		defer rows.Close()
+
+		columnNames := rows.Columns()
+		batch.Columns = columnNames[1:]
+		scns := make([]int64, defaultChangeBatchSize)
+		columns := make([][]interface{}, len(batch.Columns))
+		columnArgs := make([]interface{}, len(columnNames))
+		columnArgs[0] = scns
+		for i := range columns {
+			columns[i] = make([]interface{}, defaultChangeBatchSize)
+			columnArgs[i+1] = columns[i]
+		}
+		for {
+			count, err := rows.FetchBatch(columnArgs...)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			for row := 0; row < count; row++ {
+				values := make([]interface{}, len(columns))
+				for i := range columns {
+					values[i] = columns[i][row]
+				}
+				batch.Rows = append(batch.Rows, values)
+				batch.SCNs = append(batch.SCNs, scns[row])
+			}
+			if count < len(scns) {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return ChangeBatch{}, err
+	}
+
+	if len(batch.SCNs) > 0 {
+		batch.Checkpoint = batch.SCNs[len(batch.SCNs)-1]
+	}
+	return batch, nil
+}
@@ -0,0 +1,151 @@
+package oci8
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testTimerCountClock is the system clock, counting the timers created
+type testTimerCountClock struct {
+	timers int64
+}
+
+func (clock *testTimerCountClock) Now() time.Time {
+	return time.Now()
+}
+
+func (clock *testTimerCountClock) NewTimer(d time.Duration) ClockTimer {
+	atomic.AddInt64(&clock.timers, 1)
+	return systemClock{}.NewTimer(d)
+}
+
+// TestDestructivePollChanges checks polling changed rows by ORA_ROWSCN with PollChanges
+func TestDestructivePollChanges(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	_, err := PollChanges(ctx, TestDB, "bad name", ChangePollOptions{})
+	if err == nil {
+		t.Error("PollChanges bad name - expected: error - received: nil")
+	}
+
+	tableName := "POLL_CHANGES_" + TestTimeString
+	err = testExec(t, "create table "+tableName+" ( ID INTEGER, NAME VARCHAR2(20) ) rowdependencies", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	// separate transactions, so each row has its own ORA_ROWSCN
+	for i := 1; i <= 3; i++ {
+		err = testExec(t, "insert into "+tableName+" ( ID, NAME ) values (:1, :2)", []interface{}{i, fmt.Sprint("name ", i)})
+		if err != nil {
+			t.Fatal("insert error:", err)
+		}
+	}
+
+	var saved int64
+	clock := &testTimerCountClock{}
+	pollCtx, pollCancel := context.WithCancel(ctx)
+	defer pollCancel()
+	batches, err := PollChanges(pollCtx, TestDB, tableName, ChangePollOptions{
+		Columns:   []string{"ID", "NAME"},
+		BatchSize: 2,
+		Interval:  10 * time.Millisecond,
+		Clock:     clock,
+		LoadCheckpoint: func(ctx context.Context) (int64, error) {
+			return 0, nil
+		},
+		SaveCheckpoint: func(ctx context.Context, checkpoint int64) error {
+			saved = checkpoint
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal("PollChanges error:", err)
+	}
+
+	receive := func(count int) []int64 {
+		var ids []int64
+		for len(ids) < count {
+			batch, ok := <-batches
+			if !ok {
+				t.Fatal("batches closed")
+			}
+			if batch.Err != nil {
+				t.Fatal("batch error:", batch.Err)
+			}
+			if !reflect.DeepEqual(batch.Columns, []string{"ID", "NAME"}) {
+				t.Errorf("batch columns - expected: %v - received: %v", []string{"ID", "NAME"}, batch.Columns)
+			}
+			for i, row := range batch.Rows {
+				ids = append(ids, row[0].(int64))
+				if batch.SCNs[i] > batch.Checkpoint {
+					t.Errorf("batch SCN - expected: <= %v - received: %v", batch.Checkpoint, batch.SCNs[i])
+				}
+			}
+			err := batch.Commit(ctx)
+			if err != nil {
+				t.Fatal("Commit error:", err)
+			}
+			if saved != batch.Checkpoint {
+				t.Errorf("saved checkpoint - expected: %v - received: %v", batch.Checkpoint, saved)
+			}
+		}
+		return ids
+	}
+
+	ids := receive(3)
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+		t.Errorf("ids - expected: %v - received: %v", []int64{1, 2, 3}, ids)
+	}
+	// the full batches are followed by the next poll right away, the interval is only waited after the last batch
+	if timers := atomic.LoadInt64(&clock.timers); timers > 1 {
+		t.Errorf("interval timers - expected: <= 1 - received: %v", timers)
+	}
+
+	err = testExec(t, "update "+tableName+" set NAME = 'changed' where ID = 2", nil)
+	if err != nil {
+		t.Fatal("update error:", err)
+	}
+
+	ids = receive(1)
+	if !reflect.DeepEqual(ids, []int64{2}) {
+		t.Errorf("ids - expected: %v - received: %v", []int64{2}, ids)
+	}
+
+	pollCancel()
+	for range batches {
+	}
+}
+
+// TestChangeBatchKeep tests leaving out the rows with the highest SCN of a full change batch
+func TestChangeBatchKeep(t *testing.T) {
+	tests := []struct {
+		scns []int64
+		keep int
+	}{
+		{scns: []int64{1, 2, 3}, keep: 2},
+		{scns: []int64{1, 2, 2}, keep: 1},
+		{scns: []int64{1, 1, 2, 2, 2}, keep: 2},
+		{scns: []int64{5, 5, 5}, keep: 0},
+		{scns: []int64{5}, keep: 0},
+	}
+
+	for _, test := range tests {
+		keep := changeBatchKeep(test.scns)
+		if keep != test.keep {
+			t.Errorf("changeBatchKeep %v - expected: %v - received: %v", test.scns, test.keep, keep)
+		}
+	}
+}
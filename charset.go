@@ -0,0 +1,63 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"unsafe"
+)
+
+// CharsetForm is the character set form of a column
+type CharsetForm int
+
+const (
+	// CharsetFormNone is the form of columns that are not character data
+	CharsetFormNone CharsetForm = 0
+	// CharsetFormImplicit is the form of columns in the database character set, like VARCHAR2, CHAR, and CLOB
+	CharsetFormImplicit CharsetForm = C.SQLCS_IMPLICIT
+	// CharsetFormNChar is the form of columns in the national character set, like NVARCHAR2, NCHAR, and NCLOB
+	CharsetFormNChar CharsetForm = C.SQLCS_NCHAR
+)
+
+// ColumnCharset is the character set information of a query column
+type ColumnCharset struct {
+	// ID is the Oracle character set ID, like 873 for AL32UTF8, 0 for columns that are not character data
+	ID int
+	// Name is the Oracle character set name, like AL32UTF8, empty for columns that are not character data
+	Name string
+	// Form is the character set form, database or national character set
+	Form CharsetForm
+	// CollationID is the Oracle collation ID of the column, needs Oracle 12.2 or later, 0 when not known
+	CollationID int
+}
+
+// ColumnTypeCharset returns the character set information of column i,
+// like for tools copying data between databases with different character sets.
+// Returns false when i is not a column of the rows.
+// Use sql.Conn.Raw to get the driver rows from a query of the driver Stmt.
+func (rows *Rows) ColumnTypeCharset(i int) (ColumnCharset, bool) {
+	if i < 0 || len(rows.defines) < i+1 {
+		return ColumnCharset{}, false
+	}
+
+	define := &rows.defines[i]
+	columnCharset := ColumnCharset{
+		ID:          int(define.charsetID),
+		Form:        CharsetForm(define.charsetForm),
+		CollationID: int(define.collationID),
+	}
+	if define.charsetID != 0 {
+		columnCharset.Name = rows.stmt.conn.charsetName(define.charsetID)
+	}
+	return columnCharset, true
+}
+
+// charsetName returns the Oracle character set name of the character set ID, empty if not found
+func (conn *Conn) charsetName(id C.ub2) string {
+	var buffer [100]C.oratext // OCI_NLS_MAXBUFSZ
+	result := C.OCINlsCharSetIdToName(unsafe.Pointer(conn.env), &buffer[0], C.size_t(len(buffer)), id)
+	if result != C.OCI_SUCCESS {
+		return ""
+	}
+	return conn.goTextTerminated((*[len(buffer)]byte)(unsafe.Pointer(&buffer))[:])
+}
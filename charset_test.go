@@ -0,0 +1,76 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+// TestColumnTypeCharset checks the character set information of columns
+func TestColumnTypeCharset(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	var charsets []ColumnCharset
+	err = conn.Raw(func(driverConn interface{}) error {
+		stmt, err := driverConn.(*Conn).PrepareContext(ctx, "select cast ('a' as VARCHAR2(10)), cast ('a' as NVARCHAR2(10)), 1 from dual")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		rows, err := stmt.(driver.StmtQueryContext).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for i := 0; i < 4; i++ {
+			charset, ok := rows.(*Rows).ColumnTypeCharset(i)
+			if ok != (i < 3) {
+				t.Errorf("column %v ok - expected: %v - received: %v", i, i < 3, ok)
+			}
+			if ok {
+				charsets = append(charsets, charset)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("raw error:", err)
+	}
+	if len(charsets) != 3 {
+		t.Fatalf("charsets len - expected: 3 - received: %v", len(charsets))
+	}
+
+	var formTests = []struct {
+		form      CharsetForm
+		character bool
+	}{
+		{CharsetFormImplicit, true},
+		{CharsetFormNChar, true},
+		{CharsetFormNone, false},
+	}
+	for i, tt := range formTests {
+		if charsets[i].Form != tt.form {
+			t.Errorf("column %v form - expected: %v - received: %v", i, tt.form, charsets[i].Form)
+		}
+		if (charsets[i].ID != 0) != tt.character || (charsets[i].Name != "") != tt.character {
+			t.Errorf("column %v charset - expected character: %v - received: %+v", i, tt.character, charsets[i])
+		}
+	}
+}
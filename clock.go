@@ -0,0 +1,78 @@
+package oci8
+
+import (
+	"time"
+)
+
+type (
+	// Clock is the source of time of the driver timers: the break threshold of cancelled calls, the waits of the
+	// ResourceBusy retries, the group commit interval, the durations passed to the hooks, the PollChanges interval,
+	// and the idle timeout and leak age of the watchdog pool.
+	// Set it on the Driver, a Connector, or ChangePollOptions to a fake clock to test timeout behavior without waiting,
+	// nil uses the system clock. It must be safe for concurrent use.
+	Clock interface {
+		// Now returns the current time
+		Now() time.Time
+		// NewTimer returns a timer that sends the current time on its channel after at least duration d
+		NewTimer(d time.Duration) ClockTimer
+	}
+
+	// ClockTimer is a timer created by a Clock, like a time.Timer
+	ClockTimer interface {
+		// C returns the channel the time is sent on when the timer fires
+		C() <-chan time.Time
+		// Stop prevents the timer from firing, returns false when it already fired or was stopped
+		Stop() bool
+	}
+
+	// systemClock is the Clock of the time package
+	systemClock struct{}
+
+	// systemTimer is the ClockTimer of a time.Timer
+	systemTimer struct {
+		timer *time.Timer
+	}
+)
+
+// Now returns time.Now
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer returns a time.NewTimer
+func (systemClock) NewTimer(d time.Duration) ClockTimer {
+	return systemTimer{timer: time.NewTimer(d)}
+}
+
+// C returns the channel of the timer
+func (timer systemTimer) C() <-chan time.Time {
+	return timer.timer.C
+}
+
+// Stop stops the timer
+func (timer systemTimer) Stop() bool {
+	return timer.timer.Stop()
+}
+
+// clockOrSystem returns clock, or the system clock when it is nil
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return systemClock{}
+	}
+	return clock
+}
+
+// now returns the current time on the clock of the connection
+func (conn *Conn) now() time.Time {
+	return clockOrSystem(conn.clock).Now()
+}
+
+// since returns the time elapsed since start on the clock of the connection
+func (conn *Conn) since(start time.Time) time.Duration {
+	return conn.now().Sub(start)
+}
+
+// newTimer returns a timer on the clock of the connection
+func (conn *Conn) newTimer(d time.Duration) ClockTimer {
+	return clockOrSystem(conn.clock).NewTimer(d)
+}
@@ -0,0 +1,49 @@
+package oci8
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestClock tests the break threshold and resource busy waits use the clock of the connection
+func TestClock(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+	var infos []BreakInfo
+	conn := &Conn{
+		clock: clock,
+		hooks: Hooks{
+			AfterBreak:     func(ctx context.Context, info BreakInfo) { infos = append(infos, info) },
+			BreakThreshold: time.Hour,
+			ResourceBusy:   ResourceBusyBackoff(3, time.Hour, 2*time.Hour),
+		},
+	}
+
+	started := time.Now()
+
+	conn.afterBreak(context.Background(), make(chan struct{}), conn.now(), nil)
+	if len(infos) != 1 || infos[0].Acknowledged || infos[0].Duration != time.Hour {
+		t.Errorf("not acknowledged - received: %+v", infos)
+	}
+
+	busyErr := errors.New("ORA-00054: resource busy and acquire with NOWAIT specified or timeout expired")
+	before := conn.now()
+	for attempt := 1; attempt <= 2; attempt++ {
+		if !conn.resourceBusyRetry(context.Background(), "lock table t", attempt, busyErr) {
+			t.Errorf("resourceBusyRetry attempt %v - expected: true - received: false", attempt)
+		}
+	}
+	if conn.resourceBusyRetry(context.Background(), "lock table t", 3, busyErr) {
+		t.Errorf("resourceBusyRetry attempt 3 - expected: false - received: true")
+	}
+	if conn.since(before) != 3*time.Hour {
+		t.Errorf("resource busy waits - expected: %v - received: %v", 3*time.Hour, conn.since(before))
+	}
+
+	if time.Since(started) > time.Minute {
+		t.Errorf("waited on the system clock - received: %v", time.Since(started))
+	}
+}
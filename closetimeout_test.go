@@ -0,0 +1,96 @@
+package oci8
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCloseContext checks closing a statement and connection with a context
+func TestCloseContext(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	driverConn, err := Driver.Open(testGetOpenString("?close_timeout=10s"))
+	if err != nil {
+		t.Fatal("open error:", err)
+	}
+	conn := driverConn.(*Conn)
+	if conn.closeTimeout != 10*time.Second {
+		t.Errorf("close timeout - expected: %v - received: %v", 10*time.Second, conn.closeTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	stmt, err := conn.PrepareContext(ctx, "select 1 from dual")
+	if err != nil {
+		t.Fatal("prepare error:", err)
+	}
+	err = stmt.(*Stmt).CloseContext(ctx)
+	if err != nil {
+		t.Error("stmt close error:", err)
+	}
+
+	err = conn.CloseContext(ctx)
+	if err != nil {
+		t.Error("conn close error:", err)
+	}
+	if conn.svc != nil || conn.env != nil {
+		t.Error("conn handles not freed")
+	}
+	err = conn.Close()
+	if err != nil {
+		t.Error("conn close again error:", err)
+	}
+}
+
+// TestBoundedCleanup tests cleanup is bounded by the context
+func TestBoundedCleanup(t *testing.T) {
+	t.Parallel()
+
+	conn := &Conn{}
+	cleanupErr := errors.New("cleanup error")
+	err := conn.boundedCleanup(context.Background(), func() error { return cleanupErr })
+	if err != cleanupErr {
+		t.Errorf("background - expected: %v - received: %v", cleanupErr, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	err = conn.boundedCleanup(ctx, func() error { return nil })
+	cancel()
+	if err != nil {
+		t.Errorf("before timeout - expected: nil - received: %v", err)
+	}
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	err = conn.boundedCleanup(ctx, func() error {
+		<-release
+		close(finished)
+		return cleanupErr
+	})
+	cancel()
+	if err != context.DeadlineExceeded {
+		t.Errorf("timeout - expected: %v - received: %v", context.DeadlineExceeded, err)
+	}
+
+	// cleanup keeps running in the background
+	close(release)
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Error("cleanup did not finish")
+	}
+
+	conn.closeTimeout = time.Minute
+	ctx, cancel = conn.closeContext()
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("close context - expected deadline")
+	}
+}
@@ -0,0 +1,224 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// maxCollectionLength is the max number of elements of the SYS.ODCI list types, which are VARRAY(32767)
+const maxCollectionLength = 32767
+
+type (
+	// NumberList binds a list of numbers as a SYS.ODCINUMBERLIST collection, so it can be queried with the table operator
+	// or passed to a pipelined table function, instead of building an IN list or a query per value:
+	//
+	//	rows, err := db.QueryContext(ctx, "select * from parts where id in (select column_value from table(:1))", oci8.NumberList{1, 2, 3})
+	//
+	// NaN elements are null. The list has at most 32767 elements.
+	NumberList []float64
+
+	// Int64List binds a list of integers as a SYS.ODCINUMBERLIST collection, like NumberList,
+	// without the loss of precision of float64 for integers over 2^53. The list has at most 32767 elements.
+	Int64List []int64
+
+	// StringList binds a list of strings as a SYS.ODCIVARCHAR2LIST collection, like NumberList.
+	// The strings are at most 4000 bytes, empty strings are null. The list has at most 32767 elements.
+	StringList []string
+)
+
+// collectionType returns the type descriptor of the SYS collection type name, pinned for the session the first time it is used
+func (conn *Conn) collectionType(name string) (*C.OCIType, error) {
+	if tdo, ok := conn.collectionTDOs[name]; ok {
+		return tdo, nil
+	}
+
+	schema, schemaLength := cText("SYS", conn.utf16)
+	defer C.free(unsafe.Pointer(schema))
+	typeName, typeNameLength := cText(name, conn.utf16)
+	defer C.free(unsafe.Pointer(typeName))
+
+	var tdo *C.OCIType
+	result := C.OCITypeByName(
+		conn.env,               // environment handle
+		conn.errHandle,         // error handle
+		conn.svc,               // service context handle
+		schema,                 // schema name
+		schemaLength,           // length of the schema name
+		typeName,               // type name
+		typeNameLength,         // length of the type name
+		nil,                    // version name, not used
+		0,                      // length of the version name
+		C.OCI_DURATION_SESSION, // pin duration
+		C.OCI_TYPEGET_HEADER,   // only the header of the type descriptor
+		&tdo,                   // the type descriptor
+	)
+	err := conn.getError(result)
+	if err != nil {
+		return nil, fmt.Errorf("%v type error: %v", name, err)
+	}
+	if conn.collectionTDOs == nil {
+		conn.collectionTDOs = make(map[string]*C.OCIType)
+	}
+	conn.collectionTDOs[name] = tdo
+	return tdo, nil
+}
+
+// collectionLength returns the number of elements of a NumberList, Int64List, or StringList, -1 for other values
+func collectionLength(value interface{}) int {
+	switch value := value.(type) {
+	case NumberList:
+		return len(value)
+	case Int64List:
+		return len(value)
+	case StringList:
+		return len(value)
+	}
+	return -1
+}
+
+// ociCollectionFromList creates a collection in the object cache with the elements of a NumberList, Int64List, or StringList.
+// Returns the buffer of the bind, which holds the pointer to the collection freed with freeObject, and the type descriptor.
+func (conn *Conn) ociCollectionFromList(value interface{}) (unsafe.Pointer, *C.OCIType, error) {
+	length := collectionLength(value)
+	if length > maxCollectionLength {
+		return nil, nil, fmt.Errorf("collection has %v elements, more than %v", length, maxCollectionLength)
+	}
+
+	typeName := "ODCINUMBERLIST"
+	if _, ok := value.(StringList); ok {
+		typeName = "ODCIVARCHAR2LIST"
+	}
+	tdo, err := conn.collectionType(typeName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the same layout as an object define buffer, the instance and its null indicator, so it is freed with freeObject
+	buffer := C.calloc(2, C.size_t(sizeOfNilPointer))
+	pointers := (*[2]unsafe.Pointer)(buffer)
+	result := C.OCIObjectNew(
+		conn.env,               // environment handle
+		conn.errHandle,         // error handle
+		conn.svc,               // service context handle
+		C.OCI_TYPECODE_VARRAY,  // the ODCI list types are VARRAY
+		tdo,                    // type descriptor of the collection
+		nil,                    // table, not used for transient instances
+		C.OCI_DURATION_SESSION, // duration, the collection is freed with the binds
+		C.TRUE,                 // value instance, not a reference
+		&pointers[0],           // the new collection
+	)
+	err = conn.getError(result)
+	if err != nil {
+		C.free(buffer)
+		return nil, nil, err
+	}
+
+	collection := (*C.OCIColl)(pointers[0])
+	switch value := value.(type) {
+	case NumberList:
+		for _, element := range value {
+			err = conn.ociCollAppendNumber(collection, func(number *C.OCINumber) C.sword {
+				return C.OCINumberFromReal(conn.errHandle, unsafe.Pointer(&element), C.sizeof_double, number)
+			}, math.IsNaN(element))
+			if err != nil {
+				break
+			}
+		}
+	case Int64List:
+		for _, element := range value {
+			err = conn.ociCollAppendNumber(collection, func(number *C.OCINumber) C.sword {
+				return C.OCINumberFromInt(conn.errHandle, unsafe.Pointer(&element), C.sizeof_sb8, C.OCI_NUMBER_SIGNED, number)
+			}, false)
+			if err != nil {
+				break
+			}
+		}
+	case StringList:
+		for _, element := range value {
+			err = conn.ociCollAppendString(collection, element)
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		conn.freeObject(buffer)
+		C.free(buffer)
+		return nil, nil, err
+	}
+
+	return buffer, tdo, nil
+}
+
+// ociCollAppendNumber appends a number, set by from, or a null number to the collection
+func (conn *Conn) ociCollAppendNumber(collection *C.OCIColl, from func(number *C.OCINumber) C.sword, null bool) error {
+	var number C.OCINumber
+	indicator := C.OCIInd(C.OCI_IND_NOTNULL)
+	if null {
+		indicator = C.OCI_IND_NULL
+	} else {
+		err := conn.getError(from(&number))
+		if err != nil {
+			return err
+		}
+	}
+
+	result := C.OCICollAppend(
+		conn.env,                   // environment handle
+		conn.errHandle,             // error handle
+		unsafe.Pointer(&number),    // the element, copied into the collection
+		unsafe.Pointer(&indicator), // null indicator of the element
+		collection,                 // the collection
+	)
+	return conn.getError(result)
+}
+
+// ociCollAppendString appends a string to the collection, an empty string as null
+func (conn *Conn) ociCollAppendString(collection *C.OCIColl, element string) error {
+	if len(element) > 4000 {
+		return fmt.Errorf("collection string of %v bytes is longer than 4000 bytes", len(element))
+	}
+
+	var text *C.OCIString
+	indicator := C.OCIInd(C.OCI_IND_NULL)
+	if element != "" {
+		indicator = C.OCI_IND_NOTNULL
+		textP, textLength := cText(element, conn.utf16)
+		result := C.OCIStringAssignText(conn.env, conn.errHandle, textP, textLength, &text)
+		C.free(unsafe.Pointer(textP))
+		err := conn.getError(result)
+		if err != nil {
+			return err
+		}
+		// the element is copied into the collection, resizing to 0 frees the string
+		defer C.OCIStringResize(conn.env, conn.errHandle, 0, &text)
+	}
+
+	result := C.OCICollAppend(
+		conn.env,                   // environment handle
+		conn.errHandle,             // error handle
+		unsafe.Pointer(text),       // the element, the string elements of a collection are OCIString pointers
+		unsafe.Pointer(&indicator), // null indicator of the element
+		collection,                 // the collection
+	)
+	return conn.getError(result)
+}
+
+// ociBindObject calls OCIBindObject to bind the collection of a SQLT_NTY bind, after it is bound by position or name
+func (stmt *Stmt) ociBindObject(bind *bindStruct) error {
+	pointers := (*[2]unsafe.Pointer)(bind.pbuf)
+	result := C.OCIBindObject(
+		bind.bindHandle,     // bind handle
+		stmt.conn.errHandle, // error handle
+		bind.tdo,            // type descriptor of the collection
+		&pointers[0],        // pointer to the collection pointer
+		nil,                 // size of the collection, not used
+		nil,                 // no null indicator struct, the collection is not null
+		nil,                 // size of the null indicator struct, not used
+	)
+	return stmt.conn.getError(result)
+}
@@ -0,0 +1,218 @@
+package oci8
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestCollectionLength tests the list bind values are passed to the driver and their lengths
+func TestCollectionLength(t *testing.T) {
+	t.Parallel()
+
+	var lengthTests = []struct {
+		value    interface{}
+		expected int
+	}{
+		{NumberList{1, 2, math.NaN()}, 3},
+		{Int64List{1}, 1},
+		{StringList(nil), 0},
+		{[]float64{1, 2}, -1},
+		{"a", -1},
+	}
+	for _, tt := range lengthTests {
+		length := collectionLength(tt.value)
+		if length != tt.expected {
+			t.Errorf("collectionLength(%#v) - expected: %v - received: %v", tt.value, tt.expected, length)
+		}
+
+		value, err := checkBindValue(tt.value, true)
+		if tt.expected >= 0 && (err != nil || collectionLength(value) != tt.expected) {
+			t.Errorf("checkBindValue(%#v) - expected: the list - received: %#v, %v", tt.value, value, err)
+		}
+	}
+}
+
+// TestCollectionBind tests binding lists as collections queried with table(:1)
+func TestCollectionBind(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	queryResults := testQueryResults{
+		query: "select column_value from table(:1)",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{NumberList{1, 2.5, math.NaN()}},
+				results: [][]interface{}{{float64(1)}, {float64(2.5)}, {nil}},
+			},
+			{
+				args:    []interface{}{NumberList{}},
+				results: [][]interface{}{},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	// integers over 2^53 are exact
+	queryResults = testQueryResults{
+		query: "select to_char(column_value) from table(:1)",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{Int64List{9007199254740993, -1}},
+				results: [][]interface{}{{"9007199254740993"}, {"-1"}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	queryResults = testQueryResults{
+		query: "select column_value from table(:1)",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{StringList{"a", "", "Größe"}},
+				results: [][]interface{}{{"a"}, {nil}, {"Größe"}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	queryResults = testQueryResults{
+		query: "select dummy from dual where 'X' in (select column_value from table(:1))",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{StringList{"A", "X"}},
+				results: [][]interface{}{{"X"}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	for _, value := range []interface{}{make(NumberList, maxCollectionLength+1), StringList{strings.Repeat("a", 4001)}} {
+		rows, err := TestDB.QueryContext(ctx, "select column_value from table(:1)", value)
+		if err == nil {
+			rows.Close()
+			t.Errorf("query %T - expected: error - received: %v", value, err)
+		}
+	}
+}
+
+// TestSelectPipelinedFunction checks streaming chained pipelined table functions and stopping early does not leak cursors
+func TestSelectPipelinedFunction(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	typeName := "PIPELINED_T_" + TestTimeString
+	numbersName := "PIPELINED_NUMBERS_" + TestTimeString
+	doubleName := "PIPELINED_DOUBLE_" + TestTimeString
+	listName := "PIPELINED_LIST_" + TestTimeString
+	queries := []string{
+		"create or replace type " + typeName + " as table of number",
+		"create or replace function " + numbersName + "(p_count number) return " + typeName + " pipelined is\n" +
+			"begin for i in 1 .. p_count loop pipe row(i); end loop; return; end;",
+		"create or replace function " + doubleName + "(p_cursor SYS_REFCURSOR) return " + typeName + " pipelined is\n" +
+			"l_number number;\n" +
+			"begin loop fetch p_cursor into l_number; exit when p_cursor%notfound; pipe row(l_number * 2); end loop; close p_cursor; return;\n" +
+			"exception when no_data_needed then close p_cursor; end;",
+		"create or replace function " + listName + "(p_list sys.odcinumberlist) return " + typeName + " pipelined is\n" +
+			"begin for i in 1 .. p_list.count loop pipe row(p_list(i) + 1); end loop; return; end;",
+	}
+	for _, query := range queries {
+		err := testExec(t, query, nil)
+		if err != nil {
+			t.Fatal("create error:", err)
+		}
+	}
+	defer func() {
+		for _, query := range []string{"drop function " + listName, "drop function " + doubleName, "drop function " + numbersName, "drop type " + typeName} {
+			err := testExec(t, query, nil)
+			if err != nil {
+				t.Error("drop error:", err)
+			}
+		}
+	}()
+
+	query := "select column_value from table(" + doubleName + "(cursor(select column_value from table(" + numbersName + "(:1)))))"
+	queryResults := testQueryResults{
+		query: query,
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{int64(3)},
+				results: [][]interface{}{{float64(2)}, {float64(4)}, {float64(6)}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	// a bound collection passed to a pipelined function and chained with table(:1)
+	queryResults = testQueryResults{
+		query: "select column_value from table(" + listName + "(:1))",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{NumberList{1, 2}},
+				results: [][]interface{}{{float64(2)}, {float64(3)}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+	queryResults = testQueryResults{
+		query: "select column_value from table(" + doubleName + "(cursor(select column_value from table(:1))))",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{Int64List{1, 2}},
+				results: [][]interface{}{{float64(2)}, {float64(4)}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	openCursors := func() int64 {
+		var count int64
+		err := conn.QueryRowContext(ctx, "select m.value from v$mystat m, v$statname n "+
+			"where m.statistic# = n.statistic# and n.name = 'opened cursors current'").Scan(&count)
+		if err != nil {
+			if isOracleError(err, 942) {
+				t.Skip("no access to v$mystat:", err)
+			}
+			t.Fatal("open cursors error:", err)
+		}
+		return count
+	}
+
+	// stop reading early, many times
+	readEarly := func() {
+		rows, err := conn.QueryContext(ctx, query, 1000000)
+		if err != nil {
+			t.Fatal("query error:", err)
+		}
+		defer rows.Close()
+		if !rows.Next() {
+			t.Fatal("no rows:", rows.Err())
+		}
+	}
+	readEarly()
+	before := openCursors()
+	for i := 0; i < 20; i++ {
+		readEarly()
+	}
+	after := openCursors()
+	if after > before+2 {
+		t.Errorf("open cursors - expected at most: %v - received: %v", before+2, after)
+	}
+}
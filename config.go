@@ -0,0 +1,290 @@
+//go:build go1.10
+// +build go1.10
+
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the effective configuration of a connector, the DSN parameters with the defaults applied
+// and the connector fields that override them, safe for logging as the password is not included
+type Config struct {
+	// Connect is the connect string
+	Connect string
+	// Username is the user name, empty for external credentials
+	Username string
+	// PasswordSet is true when a password is set, the password itself is not included
+	PasswordSet bool
+	// ClientVersion is the version of the Oracle client library
+	ClientVersion string
+	// Charset is the client character set name, like AL32UTF8, or the NLS_LANG and NLS_NCHAR environment variables
+	// like NLS_LANG=AMERICAN_AMERICA.WE8ISO8859P1 when the client takes the character set from them, or UTF16 with the utf16 parameter
+	Charset string
+	// UTF16 is the utf16 parameter
+	UTF16 bool
+	// IgnoreEnv is the ignore_env parameter
+	IgnoreEnv bool
+	// TNSAdmin is the tns_admin parameter, empty uses the TNS_ADMIN of the client
+	TNSAdmin string
+	// OracleHome is the oracle_home parameter, empty uses the ORACLE_HOME of the client
+	OracleHome string
+	// NLSLanguage is the nls_language parameter, empty keeps the session default
+	NLSLanguage string
+	// NLSTerritory is the nls_territory parameter, empty keeps the session default
+	NLSTerritory string
+	// TimeLocation is the loc parameter, the time location for reading timestamps without time zone
+	TimeLocation string
+	// TimeZone is the time_zone parameter, the session time zone, empty keeps the session default
+	TimeZone string
+	// LTZLocation is the ltz_loc parameter, the time location of TIMESTAMP WITH LOCAL TIME ZONE values, empty when not set
+	LTZLocation string
+	// Isolation is the isolation parameter: DEFAULT, READONLY, or SERIALIZABLE
+	Isolation string
+	// As is the as parameter: SYSDBA, SYSASM, SYSOPER, or empty for a normal session
+	As string
+	// QuestionPlaceholders is the questionph parameter
+	QuestionPlaceholders bool
+	// PrefetchRows is the prefetch_rows parameter
+	PrefetchRows uint32
+	// PrefetchMemory is the prefetch_memory parameter
+	PrefetchMemory uint32
+	// PrefetchTarget is the prefetch_target parameter, 0 when the prefetch rows are not tuned,
+	// like when prefetch_rows or prefetch_memory are set
+	PrefetchTarget int64
+	// StmtCacheSize is the stmt_cache_size parameter
+	StmtCacheSize uint32
+	// TempLobCache is the temp_lob_cache parameter
+	TempLobCache bool
+	// TempTablespace is the temp_tablespace parameter, empty keeps the temporary tablespace of the user
+	TempTablespace string
+	// LobPrefetchSize is the lob_prefetch_size parameter
+	LobPrefetchSize uint32
+	// FetchArraySize is the fetch_array_size parameter
+	FetchArraySize uint32
+	// CallTimeout is the call_timeout parameter, the call timeout is only set with an Oracle 18c or later client
+	CallTimeout bool
+	// FloatPrecision is the float_precision parameter: BINARY, SHORTEST, or a number of decimal places
+	FloatPrecision string
+	// DateRange is the date_range parameter: ERROR or CLAMP
+	DateRange string
+	// NumberStrings is the number_strings parameter
+	NumberStrings bool
+	// IntervalInt64 is the interval_int64 parameter
+	IntervalInt64 bool
+	// IdentityReturning is the identity_returning parameter
+	IdentityReturning bool
+	// MaxRows is the max_rows parameter, or the MaxRows field of the connector
+	MaxRows int64
+	// CloseTimeout is the close_timeout parameter, or the CloseTimeout field of the connector
+	CloseTimeout time.Duration
+	// DebugConcurrentUse is the debug_concurrent_use parameter
+	DebugConcurrentUse bool
+	// ReadOnly is the read_only parameter, or the ReadOnly field of the connector
+	ReadOnly bool
+	// DDLInTx is the ddl_in_tx parameter: ALLOW, WARN, or ERROR
+	DDLInTx string
+	// GroupCommitCount is the group_commit_count parameter, or the GroupCommitCount field of the connector
+	GroupCommitCount int
+	// GroupCommitInterval is the group_commit_interval parameter, or the GroupCommitInterval field of the connector
+	GroupCommitInterval time.Duration
+	// PoolMin is the pool_min parameter
+	PoolMin uint32
+	// PoolMax is the pool_max parameter, 0 when sessions are not taken from a session pool
+	PoolMax uint32
+	// PoolIncrement is the pool_increment parameter
+	PoolIncrement uint32
+	// HealthQuery is the HealthQuery field of the connector, empty when connections are validated with OCIPing
+	HealthQuery string
+	// HealthTimeout is the timeout of the health query
+	HealthTimeout time.Duration
+	// ResetStatement is the ResetStatement field of the connector
+	ResetStatement string
+}
+
+// Config returns the effective configuration of the connector, like for logging at startup
+// or to confirm during an incident what options the connections of a sql.DB are opened with.
+// With sql.Open, get the connector of the DSN with Driver.OpenConnector.
+func (connector *Connector) Config() Config {
+	config := connector.connectDSN().config(os.Getenv)
+	config.HealthQuery = connector.HealthQuery
+	config.HealthTimeout = connector.HealthTimeout
+	config.ResetStatement = connector.ResetStatement
+	if config.HealthTimeout == 0 {
+		config.HealthTimeout = defaultHealthTimeout
+	}
+	return config
+}
+
+// config returns the configuration of the DSN, getenv reads the environment variables that decide the character set
+func (dsn *DSN) config(getenv func(string) string) Config {
+	config := Config{
+		Connect:              dsn.Connect,
+		Username:             dsn.Username,
+		PasswordSet:          dsn.Password != "",
+		ClientVersion:        clientVersion(),
+		Charset:              dsn.charsetName(getenv),
+		UTF16:                dsn.utf16,
+		IgnoreEnv:            dsn.ignoreEnv,
+		TNSAdmin:             dsn.tnsAdmin,
+		OracleHome:           dsn.oracleHome,
+		NLSLanguage:          dsn.nlsLanguage,
+		NLSTerritory:         dsn.nlsTerritory,
+		TimeLocation:         dsn.timeLocation.String(),
+		TimeZone:             dsn.sessionTimeZone,
+		Isolation:            "DEFAULT",
+		QuestionPlaceholders: dsn.enableQMPlaceholders,
+		PrefetchRows:         uint32(dsn.prefetchRows),
+		PrefetchMemory:       uint32(dsn.prefetchMemory),
+		PrefetchTarget:       dsn.prefetchTargetBytes(),
+		StmtCacheSize:        uint32(dsn.stmtCacheSize),
+		TempLobCache:         dsn.tempLobCache,
+		TempTablespace:       dsn.tempTablespace,
+		LobPrefetchSize:      uint32(dsn.lobPrefetchSize),
+		FetchArraySize:       uint32(dsn.fetchArraySize),
+		CallTimeout:          !dsn.callTimeoutOff,
+		FloatPrecision:       "BINARY",
+		DateRange:            "ERROR",
+		NumberStrings:        dsn.numberStrings,
+		IntervalInt64:        dsn.intervalInt64,
+		IdentityReturning:    dsn.identityReturning,
+		MaxRows:              dsn.maxRows,
+		CloseTimeout:         dsn.closeTimeout,
+		DebugConcurrentUse:   dsn.debugConcurrentUse,
+		ReadOnly:             dsn.readOnly,
+		DDLInTx:              "ALLOW",
+		GroupCommitCount:     dsn.groupCommitCount,
+		GroupCommitInterval:  dsn.groupCommitInterval,
+		PoolMin:              uint32(dsn.poolMin),
+		PoolMax:              uint32(dsn.poolMax),
+		PoolIncrement:        uint32(dsn.poolIncrement),
+	}
+
+	switch dsn.transactionMode {
+	case C.OCI_TRANS_READONLY:
+		config.Isolation = "READONLY"
+	case C.OCI_TRANS_SERIALIZABLE:
+		config.Isolation = "SERIALIZABLE"
+	}
+
+	switch dsn.operationMode {
+	case C.OCI_SYSDBA:
+		config.As = "SYSDBA"
+	case C.OCI_SYSASM:
+		config.As = "SYSASM"
+	case C.OCI_SYSOPER:
+		config.As = "SYSOPER"
+	}
+
+	if dsn.ltzLocation != nil {
+		config.LTZLocation = dsn.ltzLocation.String()
+	}
+
+	if dsn.floatDecimal {
+		if dsn.floatPrecision < 0 {
+			config.FloatPrecision = "SHORTEST"
+		} else {
+			config.FloatPrecision = strconv.Itoa(dsn.floatPrecision)
+		}
+	}
+
+	if dsn.dateRangeClamp {
+		config.DateRange = "CLAMP"
+	}
+
+	switch dsn.ddlInTx {
+	case ddlInTxWarn:
+		config.DDLInTx = "WARN"
+	case ddlInTxError:
+		config.DDLInTx = "ERROR"
+	}
+
+	return config
+}
+
+// charsetName returns the name of the client character set the DSN connects with,
+// decided the same way as when connecting, see the charset parameter
+func (dsn *DSN) charsetName(getenv func(string) string) string {
+	if dsn.utf16 {
+		return "UTF16"
+	}
+	if dsn.charset != "" {
+		return dsn.charset
+	}
+	nlsLang := getenv("NLS_LANG")
+	nlsNChar := getenv("NLS_NCHAR")
+	if dsn.ignoreEnv || (nlsLang == "" && nlsNChar == "") {
+		return "AL32UTF8"
+	}
+
+	var env []string
+	if nlsLang != "" {
+		env = append(env, "NLS_LANG="+nlsLang)
+	}
+	if nlsNChar != "" {
+		env = append(env, "NLS_NCHAR="+nlsNChar)
+	}
+	return strings.Join(env, " ")
+}
+
+// String returns the configuration as one line of parameter=value pairs, named like the DSN parameters
+func (config Config) String() string {
+	password := "not set"
+	if config.PasswordSet {
+		password = "set"
+	}
+
+	pairs := []string{
+		"connect=" + strconv.Quote(config.Connect),
+		"username=" + strconv.Quote(config.Username),
+		"password=" + password,
+		"client_version=" + config.ClientVersion,
+		"charset=" + strconv.Quote(config.Charset),
+		"utf16=" + strconv.FormatBool(config.UTF16),
+		"ignore_env=" + strconv.FormatBool(config.IgnoreEnv),
+		"tns_admin=" + strconv.Quote(config.TNSAdmin),
+		"oracle_home=" + strconv.Quote(config.OracleHome),
+		"nls_language=" + strconv.Quote(config.NLSLanguage),
+		"nls_territory=" + strconv.Quote(config.NLSTerritory),
+		"loc=" + config.TimeLocation,
+		"time_zone=" + strconv.Quote(config.TimeZone),
+		"ltz_loc=" + config.LTZLocation,
+		"isolation=" + config.Isolation,
+		"as=" + config.As,
+		"questionph=" + strconv.FormatBool(config.QuestionPlaceholders),
+		"prefetch_rows=" + strconv.FormatUint(uint64(config.PrefetchRows), 10),
+		"prefetch_memory=" + strconv.FormatUint(uint64(config.PrefetchMemory), 10),
+		"prefetch_target=" + strconv.FormatInt(config.PrefetchTarget, 10),
+		"stmt_cache_size=" + strconv.FormatUint(uint64(config.StmtCacheSize), 10),
+		"temp_lob_cache=" + strconv.FormatBool(config.TempLobCache),
+		"temp_tablespace=" + strconv.Quote(config.TempTablespace),
+		"lob_prefetch_size=" + strconv.FormatUint(uint64(config.LobPrefetchSize), 10),
+		"fetch_array_size=" + strconv.FormatUint(uint64(config.FetchArraySize), 10),
+		"call_timeout=" + strconv.FormatBool(config.CallTimeout),
+		"float_precision=" + config.FloatPrecision,
+		"date_range=" + config.DateRange,
+		"number_strings=" + strconv.FormatBool(config.NumberStrings),
+		"interval_int64=" + strconv.FormatBool(config.IntervalInt64),
+		"identity_returning=" + strconv.FormatBool(config.IdentityReturning),
+		"max_rows=" + strconv.FormatInt(config.MaxRows, 10),
+		"close_timeout=" + config.CloseTimeout.String(),
+		"debug_concurrent_use=" + strconv.FormatBool(config.DebugConcurrentUse),
+		"read_only=" + strconv.FormatBool(config.ReadOnly),
+		"ddl_in_tx=" + config.DDLInTx,
+		"group_commit_count=" + strconv.Itoa(config.GroupCommitCount),
+		"group_commit_interval=" + config.GroupCommitInterval.String(),
+		"pool_min=" + strconv.FormatUint(uint64(config.PoolMin), 10),
+		"pool_max=" + strconv.FormatUint(uint64(config.PoolMax), 10),
+		"pool_increment=" + strconv.FormatUint(uint64(config.PoolIncrement), 10),
+		"health_query=" + strconv.Quote(config.HealthQuery),
+		"health_timeout=" + config.HealthTimeout.String(),
+		"reset_statement=" + strconv.Quote(config.ResetStatement),
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,68 @@
+package oci8
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestConnectorConfig tests the effective configuration of connectors
+func TestConnectorConfig(t *testing.T) {
+	t.Parallel()
+
+	connector, err := NewConnectorWithOptions(
+		ConnectorDSN("scott/tiger@dbhost:1521/ORCLPDB1?isolation=SERIALIZABLE&float_precision=SHORTEST&max_rows=10&pool_max=4"),
+		ConnectorIgnoreEnv(),
+	)
+	if err != nil {
+		t.Fatal("NewConnectorWithOptions error:", err)
+	}
+	connector.MaxRows = 20
+	connector.HealthQuery = "select 1 from dual"
+
+	config := connector.Config()
+	if config.Connect != "dbhost:1521/ORCLPDB1" || config.Username != "scott" || !config.PasswordSet {
+		t.Errorf("Config credentials - expected: dbhost:1521/ORCLPDB1, scott, true - received: %v, %v, %v", config.Connect, config.Username, config.PasswordSet)
+	}
+	if config.Charset != "AL32UTF8" || config.Isolation != "SERIALIZABLE" || config.FloatPrecision != "SHORTEST" {
+		t.Errorf("Config parameters - expected: AL32UTF8, SERIALIZABLE, SHORTEST - received: %v, %v, %v", config.Charset, config.Isolation, config.FloatPrecision)
+	}
+	if config.PrefetchMemory != 4096 || config.TimeLocation != "UTC" || config.TempTablespace != "" || config.DateRange != "ERROR" {
+		t.Errorf("Config defaults - expected: 4096, UTC, , ERROR - received: %v, %v, %v, %v", config.PrefetchMemory, config.TimeLocation, config.TempTablespace, config.DateRange)
+	}
+	if config.MaxRows != 20 || config.PoolMax != 4 || config.PoolIncrement != 1 || config.HealthTimeout != defaultHealthTimeout {
+		t.Errorf("Config overrides - expected: 20, 4, 1, %v - received: %v, %v, %v, %v", defaultHealthTimeout, config.MaxRows, config.PoolMax, config.PoolIncrement, config.HealthTimeout)
+	}
+
+	configString := config.String()
+	if strings.Contains(configString, "tiger") {
+		t.Errorf("Config String - expected: no password - received: %v", configString)
+	}
+	for _, pair := range []string{`connect="dbhost:1521/ORCLPDB1"`, "password=set", "isolation=SERIALIZABLE", "max_rows=20", `health_query="select 1 from dual"`} {
+		if !strings.Contains(configString, pair) {
+			t.Errorf("Config String - expected: %v - received: %v", pair, configString)
+		}
+	}
+
+	tests := []struct {
+		charset   string
+		ignoreEnv bool
+		utf16     bool
+		env       map[string]string
+		expected  string
+	}{
+		{expected: "AL32UTF8"},
+		{charset: "WE8ISO8859P1", env: map[string]string{"NLS_LANG": "AMERICAN_AMERICA.UTF8"}, expected: "WE8ISO8859P1"},
+		{env: map[string]string{"NLS_LANG": "AMERICAN_AMERICA.UTF8"}, expected: "NLS_LANG=AMERICAN_AMERICA.UTF8"},
+		{env: map[string]string{"NLS_LANG": "AMERICAN_AMERICA.UTF8", "NLS_NCHAR": "AL16UTF16"}, expected: "NLS_LANG=AMERICAN_AMERICA.UTF8 NLS_NCHAR=AL16UTF16"},
+		{ignoreEnv: true, env: map[string]string{"NLS_LANG": "AMERICAN_AMERICA.UTF8"}, expected: "AL32UTF8"},
+		{charset: "WE8ISO8859P1", utf16: true, env: map[string]string{"NLS_LANG": "AMERICAN_AMERICA.UTF8"}, expected: "UTF16"},
+	}
+
+	for _, test := range tests {
+		dsn := &DSN{charset: test.charset, ignoreEnv: test.ignoreEnv, utf16: test.utf16}
+		charset := dsn.charsetName(func(name string) string { return test.env[name] })
+		if charset != test.expected {
+			t.Errorf("charsetName - expected: %v - received: %v", test.expected, charset)
+		}
+	}
+}
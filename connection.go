@@ -6,9 +6,12 @@ import "C"
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"io"
+	"strconv"
 	"time"
 	"unsafe"
 )
@@ -19,10 +22,41 @@ func (conn *Conn) Ping(ctx context.Context) error {
 		return ctx.Err()
 	}
 
-	done := make(chan struct{})
-	go conn.ociBreakDone(ctx, done)
+	conn.countStat(statValidations)
+	err := conn.validate(ctx)
+	if err != nil {
+		conn.logger.Print("Ping error: ", err)
+		conn.countStat(statValidationErrors)
+		return conn.badConnError("ping error: " + err.Error())
+	}
+	return nil
+}
+
+// validate makes a round trip to check the connection, the health query with the health timeout when set,
+// otherwise OCIPing
+func (conn *Conn) validate(ctx context.Context) error {
+	if conn.healthQuery == "" {
+		return conn.ociPing(ctx)
+	}
+
+	timeout := conn.healthTimeout
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := conn.healthQueryRow(ctx)
+	if err != nil {
+		return fmt.Errorf("health query: %v", err)
+	}
+	return nil
+}
+
+// ociPing makes a round trip to the server with OCIPing
+func (conn *Conn) ociPing(ctx context.Context) error {
+	stop := conn.watchBreak(ctx)
 	result := C.OCIPing(conn.svc, conn.errHandle, C.OCI_DEFAULT)
-	close(done)
+	stop()
 
 	if result == C.OCI_SUCCESS || result == C.OCI_SUCCESS_WITH_INFO {
 		return nil
@@ -33,20 +67,72 @@ func (conn *Conn) Ping(ctx context.Context) error {
 		// but a response of "ORA-01010: invalid OCI operation" confirms connectivity.
 		return nil
 	}
+	return err
+}
 
-	conn.logger.Print("Ping error: ", err)
-	return driver.ErrBadConn
+// healthQueryRow runs the health query and fetches the first row
+func (conn *Conn) healthQueryRow(ctx context.Context) error {
+	driverStmt, err := conn.PrepareContext(ctx, conn.healthQuery)
+	if err != nil {
+		return err
+	}
+	stmt := driverStmt.(*Stmt)
+	defer stmt.Close()
+
+	stmt.ctx = ctx
+	driverRows, err := stmt.query(nil)
+	if err != nil {
+		return err
+	}
+	rows := driverRows.(*Rows)
+	defer rows.Close()
+
+	err = rows.Next(make([]driver.Value, len(rows.defines)))
+	if err == io.EOF {
+		return sql.ErrNoRows
+	}
+	return err
 }
 
-// Close a connection
+// Close closes the connection, waiting at most the close_timeout of the connection
 func (conn *Conn) Close() error {
+	ctx, cancel := conn.closeContext()
+	defer cancel()
+	return conn.CloseContext(ctx)
+}
+
+// CloseContext closes the connection. When ctx is done before the session is ended, OCIBreak is called
+// and ctx.Err() is returned without waiting. The handles are then freed in the background when the database responds,
+// so a hung database can not block shutting down.
+func (conn *Conn) CloseContext(ctx context.Context) error {
 	if conn.closed {
 		return nil
 	}
 	conn.closed = true
+	conn.countStat(statCloses)
+
+	return conn.boundedCleanup(ctx, conn.close)
+}
+
+// close ends the session and frees the handles
+func (conn *Conn) close() error {
+	// statements released in the background use the handles
+	conn.cleanups.Wait()
+
+	// the statements of the group commit are committed, not left to how ending the session ends the transaction
+	var commitErr error
+	if !conn.sessionLost && !conn.inTransaction {
+		commitErr = conn.commitPending()
+	}
+
+	// TempLobs are freed while the session is open
+	conn.freeTempLobs()
 
 	var err error
-	if useOCISessionBegin {
+	if conn.sessionPool != nil {
+		// a session that was lost is dropped, not returned to the pool
+		err = conn.ociSessionRelease(conn.sessionLost)
+	} else if useOCISessionBegin {
 		if rv := C.OCISessionEnd(
 			conn.svc,
 			conn.errHandle,
@@ -62,10 +148,6 @@ func (conn *Conn) Close() error {
 		); rv != C.OCI_SUCCESS {
 			err = conn.getError(rv)
 		}
-		C.OCIHandleFree(unsafe.Pointer(conn.usrSession), C.OCI_HTYPE_SESSION)
-		C.OCIHandleFree(unsafe.Pointer(conn.srv), C.OCI_HTYPE_SERVER)
-		conn.usrSession = nil
-		conn.srv = nil
 	} else {
 		if rv := C.OCILogoff(
 			conn.svc,
@@ -75,18 +157,94 @@ func (conn *Conn) Close() error {
 		}
 	}
 
-	C.OCIHandleFree(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX)
-	C.OCIHandleFree(unsafe.Pointer(conn.errHandle), C.OCI_HTYPE_ERROR)
-	C.OCIHandleFree(unsafe.Pointer(conn.txHandle), C.OCI_HTYPE_TRANS)
-	C.OCIHandleFree(unsafe.Pointer(conn.env), C.OCI_HTYPE_ENV)
+	conn.handlesMutex.Lock()
+	if useOCISessionBegin {
+		ociHandleFree(unsafe.Pointer(conn.usrSession), C.OCI_HTYPE_SESSION)
+		ociHandleFree(unsafe.Pointer(conn.srv), C.OCI_HTYPE_SERVER)
+		conn.usrSession = nil
+		conn.srv = nil
+	}
+	if conn.sessionPool == nil {
+		// the service context of a pool session is owned by the pool, as is the environment
+		conn.clearTransHandle()
+		ociHandleFree(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX)
+	}
+	ociHandleFree(unsafe.Pointer(conn.errHandle), C.OCI_HTYPE_ERROR)
+	ociHandleFree(unsafe.Pointer(conn.txHandle), C.OCI_HTYPE_TRANS)
+	if conn.sessionPool == nil {
+		ociHandleFree(unsafe.Pointer(conn.env), C.OCI_HTYPE_ENV)
+	}
 	conn.svc = nil
 	conn.errHandle = nil
 	conn.txHandle = nil
 	conn.env = nil
+	conn.handlesMutex.Unlock()
 
+	conn.reportLeaks()
+
+	if commitErr != nil {
+		return commitErr
+	}
 	return err
 }
 
+// clearTransHandle removes the transaction handle from the service context, before the transaction handle is freed
+func (conn *Conn) clearTransHandle() {
+	if conn.svc == nil || conn.txHandle == nil {
+		return
+	}
+	C.OCIAttrSet(
+		unsafe.Pointer(conn.svc), // service context handle
+		C.OCI_HTYPE_SVCCTX,       // handle type
+		nil,                      // no transaction handle
+		0,                        // size of the attribute value
+		C.OCI_ATTR_TRANS,         // transaction handle attribute
+		conn.errHandle,           // error handle
+	)
+}
+
+// closeContext returns the context of Close, with the close_timeout of the connection
+func (conn *Conn) closeContext() (context.Context, context.CancelFunc) {
+	if conn.closeTimeout > 0 {
+		return context.WithTimeout(context.Background(), conn.closeTimeout)
+	}
+	return context.Background(), func() {}
+}
+
+// boundedCleanup runs cleanup and returns its error, or ctx.Err() when ctx is done first.
+// OCIBreak is called to interrupt the cleanup, that keeps running in the background.
+func (conn *Conn) boundedCleanup(ctx context.Context, cleanup func() error) error {
+	if ctx.Err() == nil && ctx.Done() == nil {
+		// context can never be done
+		return cleanup()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cleanup()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// select again to avoid race condition if both are done
+		select {
+		case err := <-done:
+			return err
+		default:
+		}
+	}
+
+	conn.handlesMutex.Lock()
+	if conn.svc != nil {
+		conn.ociBreak()
+	}
+	conn.handlesMutex.Unlock()
+
+	return ctx.Err()
+}
+
 // Prepare prepares a query
 func (conn *Conn) Prepare(query string) (driver.Stmt, error) {
 	return conn.PrepareContext(context.Background(), query)
@@ -97,8 +255,33 @@ func (conn *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt
 	if conn.enableQMPlaceholders {
 		query = placeholders(query)
 	}
+	return conn.prepare(ctx, query, conn.identityReturning)
+}
+
+// prepare prepares a query, with the RETURNING clause of identity_returning when identity is true
+func (conn *Conn) prepare(ctx context.Context, query string, identity bool) (driver.Stmt, error) {
+	// the statement text sent to the server, with the RETURNING clause of identity_returning
+	// and the request ID comment of WithRequestID. The statement cache key is the text without the comment.
+	prepareText := query
+	identityReturning := false
+	if identity {
+		prepareText, identityReturning = conn.identityReturningQuery(ctx, query)
+	}
+	cacheKey := prepareText
+	textOffset := 0
+	if requestID := requestIDFromContext(ctx); requestID != "" {
+		comment := requestIDComment(requestID)
+		prepareText = comment + prepareText
+		textOffset = len(comment)
+	}
 
-	queryP := cString(query)
+	// the statement options of the prepare context apply to the queries and execs run with a context without options
+	var options *StmtOptions
+	if stmtOptions, ok := stmtOptionsFromContext(ctx); ok {
+		options = &stmtOptions
+	}
+
+	queryP, queryLength := cText(prepareText, conn.utf16)
 	defer C.free(unsafe.Pointer(queryP))
 	var stmtTemp *C.OCIStmt
 	stmt := &stmtTemp
@@ -107,9 +290,8 @@ func (conn *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt
 		return nil, ctx.Err()
 	}
 
-	done := make(chan struct{})
-	go conn.ociBreakDone(ctx, done)
-	defer func() { close(done) }()
+	stop := conn.watchBreak(ctx)
+	defer stop()
 
 	if conn.stmtCacheSize == 0 {
 		if rv := C.OCIStmtPrepare2(
@@ -117,7 +299,7 @@ func (conn *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt
 			stmt,                    // pointer to the statement handle returned
 			conn.errHandle,          // error handle
 			queryP,                  // statement text
-			C.ub4(len(query)),       // statement text length
+			queryLength,             // statement text length
 			nil,                     // key to be used for searching the statement in the statement cache
 			C.ub4(0),                // length of the key
 			C.ub4(C.OCI_NTV_SYNTAX), // syntax - OCI_NTV_SYNTAX: syntax depends upon the version of the server
@@ -125,26 +307,102 @@ func (conn *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt
 		); rv != C.OCI_SUCCESS {
 			return nil, conn.getError(rv)
 		}
+		conn.leaks.alloc(unsafe.Pointer(*stmt), "statement")
 
-		return &Stmt{conn: conn, stmt: *stmt, ctx: ctx, releaseMode: C.OCI_DEFAULT}, nil
+		return conn.checkReadOnly(&Stmt{conn: conn, stmt: *stmt, ctx: ctx, releaseMode: C.OCI_DEFAULT, queryText: query, options: options,
+			identityReturning: identityReturning, textOffset: textOffset})
 	}
 
+	keyP, keyLength := cText(cacheKey, conn.utf16)
+	defer C.free(unsafe.Pointer(keyP))
 	if rv := C.OCIStmtPrepare2(
 		conn.svc,                // service context handle
 		stmt,                    // pointer to the statement handle returned
 		conn.errHandle,          // error handle
 		queryP,                  // statement text
-		C.ub4(len(query)),       // statement text length
-		queryP,                  // key to be used for searching the statement in the statement cache
-		C.ub4(len(query)),       // length of the key
+		queryLength,             // statement text length
+		keyP,                    // key to be used for searching the statement in the statement cache
+		keyLength,               // length of the key
 		C.ub4(C.OCI_NTV_SYNTAX), // syntax - OCI_NTV_SYNTAX: syntax depends upon the version of the server
 		C.ub4(C.OCI_DEFAULT),    // mode
 	); rv != C.OCI_SUCCESS && rv != C.OCI_SUCCESS_WITH_INFO {
 		// Note that C.OCI_SUCCESS_WITH_INFO is returned the first time a statement it put into the cache
 		return nil, conn.getError(rv)
 	}
+	conn.leaks.alloc(unsafe.Pointer(*stmt), "statement")
+
+	cachedStmt := &Stmt{conn: conn, stmt: *stmt, ctx: ctx, releaseMode: C.OCI_DEFAULT, cacheKey: cacheKey, queryText: query, options: options,
+		identityReturning: identityReturning}
+	// a cached statement has the request ID comment it was first prepared with, if any
+	if text, err := cachedStmt.statementText(); err == nil {
+		textOffset = requestIDCommentLength(text)
+	}
+	cachedStmt.textOffset = textOffset
+	return conn.checkReadOnly(cachedStmt)
+}
+
+// checkReadOnly returns the statement, or closes it and returns a *ReadOnlyError when the connection is read only
+// and the statement changes data or the schema
+func (conn *Conn) checkReadOnly(stmt *Stmt) (driver.Stmt, error) {
+	if !conn.readOnly {
+		return stmt, nil
+	}
 
-	return &Stmt{conn: conn, stmt: *stmt, ctx: ctx, releaseMode: C.OCI_DEFAULT, cacheKey: query}, nil
+	var stmtType C.ub2
+	_, err := stmt.ociAttrGet(unsafe.Pointer(&stmtType), C.OCI_ATTR_STMT_TYPE)
+	if err != nil {
+		stmt.Close()
+		return nil, err
+	}
+	statementType := readOnlyRefusedType(stmtType)
+	if statementType != "" {
+		stmt.Close()
+		return nil, &ReadOnlyError{StatementType: statementType}
+	}
+	return stmt, nil
+}
+
+// readOnlyRefusedType returns the name of the statement type when it is refused on a read only connection, otherwise empty.
+// Only queries, PL/SQL blocks, and calls are allowed.
+func readOnlyRefusedType(stmtType C.ub2) string {
+	switch stmtType {
+	case C.OCI_STMT_SELECT, C.OCI_STMT_BEGIN, C.OCI_STMT_DECLARE, C.OCI_STMT_CALL:
+		return ""
+	}
+	if statementType := statementTypeName(stmtType); statementType != "" {
+		return statementType
+	}
+	return "statement type " + strconv.Itoa(int(stmtType))
+}
+
+// statementTypeName returns the name of the OCI statement type, like SELECT or CREATE,
+// or empty for the statements without an OCI statement type, like TRUNCATE and GRANT
+func statementTypeName(stmtType C.ub2) string {
+	switch stmtType {
+	case C.OCI_STMT_SELECT:
+		return "SELECT"
+	case C.OCI_STMT_UPDATE:
+		return "UPDATE"
+	case C.OCI_STMT_DELETE:
+		return "DELETE"
+	case C.OCI_STMT_INSERT:
+		return "INSERT"
+	case C.OCI_STMT_CREATE:
+		return "CREATE"
+	case C.OCI_STMT_DROP:
+		return "DROP"
+	case C.OCI_STMT_ALTER:
+		return "ALTER"
+	case C.OCI_STMT_BEGIN:
+		return "BEGIN"
+	case C.OCI_STMT_DECLARE:
+		return "DECLARE"
+	case C.OCI_STMT_CALL:
+		return "CALL"
+	case C.OCI_STMT_MERGE:
+		return "MERGE"
+	}
+	return ""
 }
 
 // Begin starts a transaction
@@ -158,6 +416,12 @@ func (conn *Conn) BeginTx(ctx context.Context, txOptions driver.TxOptions) (driv
 		return nil, ctx.Err()
 	}
 
+	// the transaction must not include the statements of the group commit
+	err := conn.commitPending()
+	if err != nil {
+		return nil, err
+	}
+
 	if conn.transactionMode != C.OCI_TRANS_READWRITE {
 		if rv := C.OCITransStart(
 			conn.svc,
@@ -174,6 +438,57 @@ func (conn *Conn) BeginTx(ctx context.Context, txOptions driver.TxOptions) (driv
 	return &Tx{conn: conn}, nil
 }
 
+// queryRow runs a query on the connection and stores the first row into dest.
+// Returns sql.ErrNoRows if the query returns no rows.
+func (conn *Conn) queryRow(ctx context.Context, query string, dest []driver.Value) error {
+	driverStmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	stmt := driverStmt.(*Stmt)
+	defer stmt.Close()
+
+	stmt.ctx = ctx
+	driverRows, err := stmt.query(nil)
+	if err != nil {
+		return err
+	}
+	rows := driverRows.(*Rows)
+	defer rows.Close()
+
+	err = rows.Next(dest)
+	if err == io.EOF {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+// exec runs an exec query on the connection
+func (conn *Conn) exec(ctx context.Context, query string, namedValues []driver.NamedValue) (driver.Result, error) {
+	driverStmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	stmt := driverStmt.(*Stmt)
+	defer stmt.Close()
+
+	return stmt.ExecContext(ctx, namedValues)
+}
+
+// badConnError returns driver.ErrBadConn, or a *SessionLostError with the reason when the connection is pinned,
+// or a *GroupCommitLostError when statements of the group commit are lost with it.
+// The session is marked as lost, so it is dropped instead of returned to a session pool.
+func (conn *Conn) badConnError(reason string) error {
+	conn.sessionLost = true
+	if conn.pendingCommits > 0 {
+		return conn.groupCommitLost(errors.New(reason))
+	}
+	if conn.pinned {
+		return &SessionLostError{Reason: reason}
+	}
+	return driver.ErrBadConn
+}
+
 // getError gets error from return result (sword) or OCIError
 func (conn *Conn) getError(result C.sword) error {
 	switch result {
@@ -193,49 +508,85 @@ func (conn *Conn) getError(result C.sword) error {
 		return ErrOCIStillExecuting
 	case C.OCI_ERROR:
 		errorCode, err := conn.ociGetError()
-		switch errorCode {
-		/*
-			bad connection errors:
-			ORA-00028: your session has been killed
-			ORA-01012: Not logged on
-			ORA-01033: ORACLE initialization or shutdown in progress
-			ORA-01034: ORACLE not available
-			ORA-01089: immediate shutdown in progress - no operations are permitted
-			ORA-03113: end-of-file on communication channel
-			ORA-03114: Not Connected to Oracle
-			ORA-03135: connection lost contact
-			ORA-12528: TNS:listener: all appropriate instances are blocking new connections
-			ORA-12537: TNS:connection closed
-		*/
-		case 28, 1012, 1033, 1034, 1089, 3113, 3114, 3135, 12528, 12537:
-			return driver.ErrBadConn
+		if _, ok := unsentBadConnErrors[errorCode]; ok {
+			// the Oracle error is replaced by driver.ErrBadConn, log it so the cause is not lost
+			conn.logger.Print("bad connection: ", err)
+			return conn.badConnError(err.Error())
 		}
-		return err
+		if _, ok := badConnErrors[errorCode]; ok {
+			// the server may have run the call, so it must not be retried on a fresh connection.
+			// The session is marked as lost, so IsValid and ResetSession discard the connection.
+			conn.sessionLost = true
+			if conn.pendingCommits > 0 {
+				return conn.groupCommitLost(err)
+			}
+			if conn.pinned {
+				return &SessionLostError{Reason: err.Error()}
+			}
+			return err
+		}
+		return conn.errorTranslations.Translate(errorCode, err)
 	}
 	return fmt.Errorf("received result code %d", result)
 }
 
 // ociGetError calls OCIErrorGet then returs error code and text
 func (conn *Conn) ociGetError() (int, error) {
+	return ociErrorGet(conn.errHandle, conn.utf16)
+}
+
+// ociErrorGet calls OCIErrorGet on the error handle then returs error code and text.
+// With utf16 the text is UTF-16, the error handle is of an OCI_UTF16ID environment.
+func ociErrorGet(errHandle *C.OCIError, utf16 bool) (int, error) {
 	var errorCode C.sb4
 	errorText := make([]byte, 1024)
 
 	result := C.OCIErrorGet(
-		unsafe.Pointer(conn.errHandle), // error handle
-		1,                              // status record number, starts from 1
-		nil,                            // sqlstate, not supported in release 8.x or later
-		&errorCode,                     // error code
-		(*C.OraText)(&errorText[0]),    // error message text
-		1024,                           // size of the buffer provided in number of bytes
-		C.OCI_HTYPE_ERROR,              // type of the handle (OCI_HTYPE_ERR or OCI_HTYPE_ENV)
+		unsafe.Pointer(errHandle),   // error handle
+		1,                           // status record number, starts from 1
+		nil,                         // sqlstate, not supported in release 8.x or later
+		&errorCode,                  // error code
+		(*C.OraText)(&errorText[0]), // error message text
+		1024,                        // size of the buffer provided in number of bytes
+		C.OCI_HTYPE_ERROR,           // type of the handle (OCI_HTYPE_ERR or OCI_HTYPE_ENV)
 	)
 	if result != C.OCI_SUCCESS {
 		return 3114, errors.New("OCIErrorGet failed")
 	}
 
-	index := bytes.IndexByte(errorText, 0)
+	oracleError := &Error{Code: int(errorCode)}
+	if utf16 {
+		oracleError.Message = utf16Decode(errorText[:utf16Terminator(errorText)])
+	} else {
+		oracleError.Message = string(errorText[:bytes.IndexByte(errorText, 0)])
+	}
 
-	return int(errorCode), errors.New(string(errorText[:index]))
+	var offset C.ub2
+	result = C.OCIAttrGet(
+		unsafe.Pointer(errHandle),     // error handle
+		C.OCI_HTYPE_ERROR,             // handle type
+		unsafe.Pointer(&offset),       // attribute value
+		nil,                           // size of the attribute value, not needed
+		C.OCI_ATTR_PARSE_ERROR_OFFSET, // attribute type
+		errHandle,                     // error handle
+	)
+	if result == C.OCI_SUCCESS {
+		oracleError.Offset = int(offset)
+	}
+	if clientMajorVersion() >= 12 {
+		var recoverable C.boolean
+		result = C.OCIAttrGet(
+			unsafe.Pointer(errHandle),       // error handle
+			C.OCI_HTYPE_ERROR,               // handle type
+			unsafe.Pointer(&recoverable),    // attribute value
+			nil,                             // size of the attribute value, not needed
+			C.OCI_ATTR_ERROR_IS_RECOVERABLE, // attribute type
+			errHandle,                       // error handle
+		)
+		oracleError.Recoverable = result == C.OCI_SUCCESS && recoverable == C.TRUE
+	}
+
+	return oracleError.Code, oracleError
 }
 
 // ociAttrGet calls OCIAttrGet with OCIParam then returns attribute size and error.
@@ -299,6 +650,7 @@ func (conn *Conn) ociHandleAlloc(handleType C.ub4, size C.size_t) (*unsafe.Point
 	if err != nil {
 		return nil, nil, err
 	}
+	conn.leaks.alloc(*handle, ociTypeName(handleType))
 
 	if size > 0 {
 		return handle, buffer, nil
@@ -330,6 +682,7 @@ func (conn *Conn) ociDescriptorAlloc(descriptorType C.ub4, size C.size_t) (*unsa
 	if err != nil {
 		return nil, nil, err
 	}
+	conn.leaks.alloc(*descriptor, ociTypeName(descriptorType))
 
 	if size > 0 {
 		return descriptor, buffer, nil
@@ -339,7 +692,11 @@ func (conn *Conn) ociDescriptorAlloc(descriptorType C.ub4, size C.size_t) (*unsa
 }
 
 // ociLobCreateTemporary calls OCILobCreateTemporary then returns error
-func (conn *Conn) ociLobCreateTemporary(lobLocator *C.OCILobLocator, form C.ub1, lobType C.ub1) error {
+func (conn *Conn) ociLobCreateTemporary(lobLocator *C.OCILobLocator, form C.ub1, lobType C.ub1, options TempLobOptions) error {
+	cache := C.boolean(C.TRUE)
+	if !conn.tempLobCache || options.NoCache {
+		cache = C.FALSE
+	}
 
 	result := C.OCILobCreateTemporary(
 		conn.svc,               // service context handle
@@ -348,10 +705,51 @@ func (conn *Conn) ociLobCreateTemporary(lobLocator *C.OCILobLocator, form C.ub1,
 		C.OCI_DEFAULT,          // LOB character set ID. For Oracle8i or later, pass as OCI_DEFAULT.
 		form,                   // character set form
 		lobType,                // type of LOB to create: OCI_TEMP_BLOB or OCI_TEMP_CLOB
-		C.TRUE,                 // Pass TRUE if the temporary LOB should be read into the cache; pass FALSE if it should not. FALSE for NOCACHE functionality
-		C.OCI_DURATION_SESSION, //  duration of the temporary LOB: OCI_DURATION_SESSION or OCI_DURATION_CALL
+		cache,                  // Pass TRUE if the temporary LOB should be read into the cache; pass FALSE if it should not. FALSE for NOCACHE functionality
+		C.OCI_DURATION_SESSION, // duration of the temporary LOB, freed by the driver when done with it
 	)
 
+	err := conn.getError(result)
+	if err != nil {
+		return err
+	}
+
+	err = conn.ociLobSetOptions(lobLocator, options)
+	if err != nil {
+		C.OCILobFreeTemporary(conn.svc, conn.errHandle, lobLocator)
+		return err
+	}
+
+	conn.tempLobStats.Created++
+	conn.leaks.markTemporaryLob(unsafe.Pointer(lobLocator))
+	return nil
+}
+
+// ociLobSetOptions calls OCILobSetOptions to turn on the SecureFiles compression and deduplication of the options.
+// Does nothing when neither is set.
+func (conn *Conn) ociLobSetOptions(lobLocator *C.OCILobLocator, options TempLobOptions) error {
+	var optionTypes, values C.ub4
+	if options.Compress {
+		optionTypes |= C.OCI_LOB_OPT_COMPRESS
+		values |= C.OCI_LOB_COMPRESS_ON
+	}
+	if options.Deduplicate {
+		optionTypes |= C.OCI_LOB_OPT_DEDUPLICATE
+		values |= C.OCI_LOB_DEDUPLICATE_ON
+	}
+	if optionTypes == 0 {
+		return nil
+	}
+
+	result := C.OCILobSetOptions(
+		conn.svc,                // service context handle
+		conn.errHandle,          // error handle
+		lobLocator,              // LOB locator
+		optionTypes,             // the options to set, like OCI_LOB_OPT_COMPRESS
+		unsafe.Pointer(&values), // the values of the options, like OCI_LOB_COMPRESS_ON
+		C.sizeof_ub4,            // size of the values
+		C.OCI_DEFAULT,           // mode, must be OCI_DEFAULT
+	)
 	return conn.getError(result)
 }
 
@@ -390,7 +788,7 @@ func (conn *Conn) ociLobRead(lobLocator *C.OCILobLocator, form C.ub1) ([]byte, e
 			piece,                          // For polling, pass OCI_FIRST_PIECE the first time and OCI_NEXT_PIECE in subsequent calls.
 			nil,                            // context pointer for the callback function
 			nil,                            // If this is null, then OCI_NEED_DATA will be returned for each piece.
-			0,                              // character set ID of the buffer data. If this value is 0 then csid is set to the client's NLS_LANG or NLS_CHAR value, depending on the value of csfrm.
+			conn.lobCharset(),              // character set ID of the buffer data. If this value is 0 then csid is set to the client's NLS_LANG or NLS_CHAR value, depending on the value of csfrm.
 			form,                           // character set form of the buffer data
 		)
 
@@ -406,6 +804,40 @@ func (conn *Conn) ociLobRead(lobLocator *C.OCILobLocator, form C.ub1) ([]byte, e
 	return buffer, conn.getError(result)
 }
 
+// freeTemporaryLob calls OCILobFreeTemporary when the LOB is a temporary LOB
+func (conn *Conn) freeTemporaryLob(lobLocator *C.OCILobLocator) {
+	if lobLocator == nil {
+		return
+	}
+	var isTemporary C.boolean
+	result := C.OCILobIsTemporary(
+		conn.env,       // environment handle
+		conn.errHandle, // error handle
+		lobLocator,     // LOB locator
+		&isTemporary,   // TRUE when the LOB is a temporary LOB
+	)
+	if result != C.OCI_SUCCESS || isTemporary != C.TRUE {
+		return
+	}
+	C.OCILobFreeTemporary(conn.svc, conn.errHandle, lobLocator)
+}
+
+// countTempLobWrite adds the bytes written to the LOB to TempLobStats when the LOB is a temporary LOB,
+// writes to persistent LOBs are not counted
+func (conn *Conn) countTempLobWrite(lobLocator *C.OCILobLocator, written int) {
+	var isTemporary C.boolean
+	result := C.OCILobIsTemporary(
+		conn.env,       // environment handle
+		conn.errHandle, // error handle
+		lobLocator,     // LOB locator
+		&isTemporary,   // TRUE when the LOB is a temporary LOB
+	)
+	if result != C.OCI_SUCCESS || isTemporary != C.TRUE {
+		return
+	}
+	conn.tempLobStats.BytesWritten += int64(written)
+}
+
 // ociLobWrite calls OCILobWrite then returns error.
 func (conn *Conn) ociLobWrite(lobLocator *C.OCILobLocator, form C.ub1, data []byte) error {
 	start := 0
@@ -432,7 +864,7 @@ func (conn *Conn) ociLobWrite(lobLocator *C.OCILobLocator, form C.ub1, data []by
 			piece,                           // which piece of the buffer is being written. OCI_ONE_PIECE, indicating that the buffer is written in a single piece. Piecewise or callback mode: OCI_FIRST_PIECE, OCI_NEXT_PIECE, and OCI_LAST_PIECE.
 			nil,                             // callback function
 			nil,                             // callback that can be registered
-			0,                               // character set ID
+			conn.lobCharset(),               // character set ID
 			form,                            // character set form
 		)
 
@@ -457,6 +889,8 @@ func (conn *Conn) ociLobWrite(lobLocator *C.OCILobLocator, form C.ub1, data []by
 		}
 	}
 
+	conn.countTempLobWrite(lobLocator, len(data))
+
 	return nil
 }
 
@@ -499,7 +933,7 @@ func (conn *Conn) ociDateTimeToTime(dateTime *C.OCIDateTime, ociDateTimeHasTimeZ
 	}
 
 	if !ociDateTimeHasTimeZone {
-		aTime := time.Date(int(year), time.Month(month), int(day), int(hour), int(min), int(sec), int(fsec), conn.timeLocation)
+		aTime := time.Date(oracleYearToGo(int(year)), time.Month(month), int(day), int(hour), int(min), int(sec), int(fsec), conn.timeLocation)
 		return &aTime, nil
 	}
 
@@ -519,14 +953,29 @@ func (conn *Conn) ociDateTimeToTime(dateTime *C.OCIDateTime, ociDateTimeHasTimeZ
 	}
 
 	// return Go Time using OCI time zone offset
-	aTime := time.Date(int(year), time.Month(month), int(day), int(hour), int(min), int(sec), int(fsec),
+	aTime := time.Date(oracleYearToGo(int(year)), time.Month(month), int(day), int(hour), int(min), int(sec), int(fsec),
 		timezoneToLocation(int64(timeZoneHour), int64(timeZoneMin)))
+	aTime = normalizeTime(aTime, conn.timeLocation)
 	return &aTime, nil
 }
 
+// localTimeZoneTime returns the time of a TIMESTAMP WITH LOCAL TIME ZONE column in the ltz_loc location,
+// the times of other columns are returned unchanged
+func (conn *Conn) localTimeZoneTime(define *defineStruct, aTime time.Time) time.Time {
+	if define.columnType != C.SQLT_TIMESTAMP_LTZ || conn.ltzLocation == nil {
+		return aTime
+	}
+	return aTime.In(conn.ltzLocation)
+}
+
 // timeToOCIDateTime coverts Go Time to OCIDateTime
 func (conn *Conn) timeToOCIDateTime(aTime *time.Time) (*unsafe.Pointer, error) {
-	var err error
+	rangeTime, err := dateRangeTime(normalizeTime(*aTime, nil), conn.dateRangeClamp)
+	if err != nil {
+		return nil, err
+	}
+	aTime = &rangeTime
+
 	var dateTimePP *unsafe.Pointer
 	dateTimePP, _, err = conn.ociDescriptorAlloc(C.OCI_DTYPE_TIMESTAMP_TZ, 0)
 	if err != nil {
@@ -550,11 +999,17 @@ func (conn *Conn) timeToOCIDateTime(aTime *time.Time) (*unsafe.Pointer, error) {
 	// minutes
 	timeZone = appendSmallInt(timeZone, offset/60)
 
+	if conn.utf16 {
+		// the time zone string is text in the character set of the environment
+		timeZone = utf16Encode(string(timeZone))
+	}
+
+	year := C.sb2(goYearToOracle(aTime.Year()))
 	result := C.OCIDateTimeConstruct(
 		unsafe.Pointer(conn.env),   // environment handle
 		conn.errHandle,             // error handle
 		dateTimeP,                  // an OCIDateTime pointer
-		C.sb2(aTime.Year()),        // year
+		year,                       // year
 		C.ub1(aTime.Month()),       // month
 		C.ub1(aTime.Day()),         // day
 		C.ub1(aTime.Hour()),        // hour
@@ -562,7 +1017,7 @@ func (conn *Conn) timeToOCIDateTime(aTime *time.Time) (*unsafe.Pointer, error) {
 		C.ub1(aTime.Second()),      // second
 		C.ub4(aTime.Nanosecond()),  // fractional second
 		(*C.OraText)(&timeZone[0]), // time zone string formated: [+|-][HH:MM]
-		C.size_t(6),                //  time zone string length
+		C.size_t(len(timeZone)),    //  time zone string length
 	)
 	err = conn.getError(result)
 	if err != nil {
@@ -584,8 +1039,8 @@ func appendSmallInt(slice []byte, num int) []byte {
 	return append(slice, byte('0'+num/10), byte('0'+(num%10)))
 }
 
-// ociBreakDone calls OCIBreak if ctx.Done is finished before done chan is closed
-func (conn *Conn) ociBreakDone(ctx context.Context, done chan struct{}) {
+// ociBreakDone calls OCIBreak if ctx.Done is finished before done chan is closed, returns true when OCIBreak was called
+func (conn *Conn) ociBreakDone(ctx context.Context, done chan struct{}) bool {
 	select {
 	case <-done:
 	case <-ctx.Done():
@@ -593,13 +1048,17 @@ func (conn *Conn) ociBreakDone(ctx context.Context, done chan struct{}) {
 		select {
 		case <-done:
 		default:
-			conn.ociBreak()
+			start := conn.now()
+			err := conn.ociBreak()
+			conn.afterBreak(ctx, done, start, err)
+			return true
 		}
 	}
+	return false
 }
 
 // ociBreak calls OCIBreak
-func (conn *Conn) ociBreak() {
+func (conn *Conn) ociBreak() error {
 	result := C.OCIBreak(
 		unsafe.Pointer(conn.svc), // service or server context handle
 		conn.errHandle,           // error handle
@@ -608,4 +1067,5 @@ func (conn *Conn) ociBreak() {
 	if err != nil {
 		conn.logger.Print("OCIBreak error: ", err)
 	}
+	return err
 }
@@ -2,11 +2,19 @@
 
 package oci8
 
+// #include "oci8.go.h"
+import "C"
+
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
 	"io/ioutil"
 	"log"
+	"strconv"
+	"time"
+
+	"github.com/mattn/go-oci8/oracle"
 )
 
 // NewConnector returns a new database connector
@@ -16,6 +24,312 @@ func NewConnector(hosts ...string) driver.Connector {
 	}
 }
 
+// ConnectorOption sets an option of a connector created with NewConnectorWithOptions
+type ConnectorOption func(connector *Connector) error
+
+// NewConnectorWithOptions returns a new database connector with the options applied in order,
+// so the connection parameters are typed values instead of a DSN string:
+//
+//	connector, err := oci8.NewConnectorWithOptions(
+//		oci8.ConnectorCredentials("scott", "tiger"),
+//		oci8.ConnectorConnectString("dbhost:1521/ORCLPDB1"),
+//		oci8.ConnectorPrefetch(1000, 0),
+//	)
+//	db := sql.OpenDB(connector)
+//
+// Parameters without an option can be set with ConnectorDSN, which must come first as it replaces the DSN.
+// The connector fields, like MaxRows and ReadOnly, can also be set on the returned connector before opening the sql.DB.
+// When set, they override the DSN parameters, their zero values keep the DSN parameters.
+func NewConnectorWithOptions(options ...ConnectorOption) (*Connector, error) {
+	connector := &Connector{
+		Logger: log.New(ioutil.Discard, "", 0),
+		dsn:    newDSN(),
+	}
+	for _, option := range options {
+		err := option(connector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := connector.dsn.checkSessionPool()
+	if err != nil {
+		return nil, err
+	}
+	return connector, nil
+}
+
+// ConnectorDSN replaces the connection parameters of the connector with the parsed DSN string, see ParseDSN
+func ConnectorDSN(dsnString string) ConnectorOption {
+	return func(connector *Connector) error {
+		dsn, err := ParseDSN(dsnString)
+		if err != nil {
+			return err
+		}
+		connector.dsn = dsn
+		return nil
+	}
+}
+
+// ConnectorCredentials sets the username and password. Without a username the external credentials of the operating system are used.
+func ConnectorCredentials(username string, password string) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.Username = username
+		connector.dsn.Password = password
+		return nil
+	}
+}
+
+// ConnectorConnectString sets the connect string, like an easy connect string host:port/service_name or a tnsnames.ora alias
+func ConnectorConnectString(connect string) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.Connect = connect
+		return nil
+	}
+}
+
+// ConnectorTimeLocation sets the time location for reading timestamps without time zone, like the loc DSN parameter
+func ConnectorTimeLocation(location *time.Location) ConnectorOption {
+	return func(connector *Connector) error {
+		if location == nil {
+			return errors.New("invalid time location: nil")
+		}
+		connector.dsn.timeLocation = location
+		return nil
+	}
+}
+
+// ConnectorPrefetch sets the number of rows and the memory in bytes prefetched, like the prefetch_rows and prefetch_memory DSN parameters
+func ConnectorPrefetch(rows uint32, memory uint32) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.prefetchRows = C.ub4(rows)
+		connector.dsn.prefetchMemory = C.ub4(memory)
+		connector.dsn.prefetchSet = true
+		return nil
+	}
+}
+
+// ConnectorPrefetchTarget sets the target bytes of the rows prefetched in a round trip, 0 turns it off, like the prefetch_target DSN parameter
+func ConnectorPrefetchTarget(target int64) ConnectorOption {
+	return func(connector *Connector) error {
+		if target < 0 {
+			return errors.New("invalid prefetch target: " + strconv.FormatInt(target, 10))
+		}
+		connector.dsn.prefetchTarget = target
+		connector.dsn.prefetchTargetSet = true
+		return nil
+	}
+}
+
+// ConnectorFetchArraySize sets the number of rows fetched by each fetch of a query, like the fetch_array_size DSN parameter
+func ConnectorFetchArraySize(size uint32) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.fetchArraySize = C.ub4(size)
+		return nil
+	}
+}
+
+// ConnectorStmtCacheSize sets the size of the OCI statement cache, like the stmt_cache_size DSN parameter
+func ConnectorStmtCacheSize(size uint32) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.stmtCacheSize = C.ub4(size)
+		return nil
+	}
+}
+
+// ConnectorCharset sets the client character set name, like AL32UTF8, like the charset DSN parameter
+func ConnectorCharset(charset string) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.charset = charset
+		return nil
+	}
+}
+
+// ConnectorUTF16 creates the environment in UTF-16 mode, with the text converted between UTF-16 and UTF-8 by the driver,
+// like the utf16 DSN parameter
+func ConnectorUTF16() ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.utf16 = true
+		return nil
+	}
+}
+
+// ConnectorNumberStrings returns NUMBER and FLOAT columns as exact decimal strings, like the number_strings DSN parameter
+func ConnectorNumberStrings() ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.numberStrings = true
+		return nil
+	}
+}
+
+// ConnectorIntervalInt64 returns INTERVAL values as int64 nanoseconds and months, like the interval_int64 DSN parameter
+func ConnectorIntervalInt64() ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.intervalInt64 = true
+		return nil
+	}
+}
+
+// ConnectorIgnoreEnv ignores the NLS_LANG and NLS_NCHAR environment variables, like the ignore_env DSN parameter
+func ConnectorIgnoreEnv() ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.ignoreEnv = true
+		return nil
+	}
+}
+
+// ConnectorTNSAdmin sets the directory of the tnsnames.ora net service names are resolved with, like the tns_admin DSN parameter.
+// An empty directory uses the TNS_ADMIN of the client.
+func ConnectorTNSAdmin(directory string) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.tnsAdmin = directory
+		return nil
+	}
+}
+
+// ConnectorOracleHome sets the Oracle home whose network/admin/tnsnames.ora net service names are resolved with,
+// like the oracle_home DSN parameter. An empty directory uses the ORACLE_HOME of the client.
+func ConnectorOracleHome(directory string) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.oracleHome = directory
+		return nil
+	}
+}
+
+// ConnectorNLS sets the session NLS language and territory, like the nls_language and nls_territory DSN parameters.
+// An empty string keeps the default.
+func ConnectorNLS(language string, territory string) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.dsn.nlsLanguage = language
+		connector.dsn.nlsTerritory = territory
+		return nil
+	}
+}
+
+// ConnectorTempTablespace sets the temporary tablespace, or tablespace group, of the user on connect,
+// like the temp_tablespace DSN parameter. An empty name keeps the temporary tablespace of the user.
+func ConnectorTempTablespace(name string) ConnectorOption {
+	return func(connector *Connector) error {
+		if name != "" {
+			err := ValidateIdentifier(name)
+			if err != nil {
+				return err
+			}
+		}
+		connector.dsn.tempTablespace = name
+		return nil
+	}
+}
+
+// ConnectorTimeZone sets the session time zone and the time location TIMESTAMP WITH LOCAL TIME ZONE values are returned in,
+// like the time_zone and ltz_loc DSN parameters. An empty time zone keeps the session default, a nil location keeps the default location.
+func ConnectorTimeZone(timeZone string, location *time.Location) ConnectorOption {
+	return func(connector *Connector) error {
+		if timeZone != "" && !oracle.ValidTimeZone(timeZone) {
+			return errors.New("invalid time zone: " + timeZone)
+		}
+		connector.dsn.sessionTimeZone = timeZone
+		connector.dsn.ltzLocation = location
+		return nil
+	}
+}
+
+// ConnectorIPFormat sets how net.IP bind values are bound, as text or as RAW
+func ConnectorIPFormat(format IPFormat) ConnectorOption {
+	return func(connector *Connector) error {
+		if format < IPBytes || format > IPRaw {
+			return errors.New("invalid IP format: " + strconv.Itoa(int(format)))
+		}
+		connector.IPFormat = format
+		return nil
+	}
+}
+
+// ConnectorRawUUIDs binds the values of [16]byte based types, like UUID types, as RAW(16)
+func ConnectorRawUUIDs() ConnectorOption {
+	return func(connector *Connector) error {
+		connector.RawUUIDs = true
+		return nil
+	}
+}
+
+// ConnectorYesNoBools maps CHAR(1) and VARCHAR2(1) Y/N and 1/0 columns to bool, and binds bools as Y and N
+func ConnectorYesNoBools() ConnectorOption {
+	return func(connector *Connector) error {
+		connector.YesNoBools = true
+		return nil
+	}
+}
+
+// ConnectorSessionPool takes sessions from an OCI session pool, like the pool_min, pool_max, and pool_increment DSN parameters
+func ConnectorSessionPool(min uint32, max uint32, increment uint32) ConnectorOption {
+	return func(connector *Connector) error {
+		if max < 1 {
+			return errors.New("invalid session pool max: 0")
+		}
+		connector.dsn.poolMin = C.ub4(min)
+		connector.dsn.poolMax = C.ub4(max)
+		connector.dsn.poolIncrement = C.ub4(increment)
+		return nil
+	}
+}
+
+// ConnectorLogger sets the logger of connection ping errors
+func ConnectorLogger(logger *log.Logger) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.Logger = logger
+		return nil
+	}
+}
+
+// ConnectorHooks sets the hooks called around statement execution
+func ConnectorHooks(hooks Hooks) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.Hooks = hooks
+		return nil
+	}
+}
+
+// ConnectorConverter sets the converter of result values and bool binds
+func ConnectorConverter(converter Converter) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.Converter = converter
+		return nil
+	}
+}
+
+// ConnectorErrorTranslations sets the translations of Oracle errors
+func ConnectorErrorTranslations(errorTranslations *ErrorTranslations) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.ErrorTranslations = errorTranslations
+		return nil
+	}
+}
+
+// ConnectorClock sets the clock of the driver timers, like a fake clock in tests of timeout behavior
+func ConnectorClock(clock Clock) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.Clock = clock
+		return nil
+	}
+}
+
+// OpenConnector returns a connector for the DSN, which is parsed once instead of each time a connection is opened.
+// The connector uses the logger, hooks, converter, error translations, and clock of the driver.
+func (drv *DriverStruct) OpenConnector(dsnString string) (driver.Connector, error) {
+	dsn, err := ParseDSN(dsnString)
+	if err != nil {
+		return nil, err
+	}
+	return &Connector{
+		Logger:            drv.Logger,
+		Hooks:             drv.Hooks,
+		Converter:         drv.Converter,
+		ErrorTranslations: drv.ErrorTranslations,
+		Clock:             drv.Clock,
+		dsn:               dsn,
+	}, nil
+}
+
 // Driver returns the OCI8 driver
 func (connector *Connector) Driver() driver.Driver {
 	return Driver
@@ -27,12 +341,54 @@ func (connector *Connector) Connect(ctx context.Context) (driver.Conn, error) {
 		return nil, ctx.Err()
 	}
 
+	dsn := connector.connectDSN()
 	conn := &Conn{
-		logger: connector.Logger,
+		logger:            connector.Logger,
+		hooks:             connector.Hooks,
+		converter:         connector.Converter,
+		errorTranslations: connector.ErrorTranslations,
+		clock:             connector.Clock,
+		healthQuery:       connector.HealthQuery,
+		healthTimeout:     connector.HealthTimeout,
+		resetStatement:    connector.ResetStatement,
+		ipFormat:          connector.IPFormat,
+		rawUUIDs:          connector.RawUUIDs,
+		yesNoBools:        connector.YesNoBools,
+		counters:          &connector.counters,
+		leaks:             &connector.leaks,
 	}
-	if conn.logger == nil {
-		conn.logger = log.New(ioutil.Discard, "", 0)
+	err := conn.open(dsn)
+	conn.countConnect(err)
+	if err != nil {
+		return nil, err
 	}
 
 	return conn, nil
 }
+
+// connectDSN returns a copy of the DSN of the connector with the connector fields that are set
+func (connector *Connector) connectDSN() *DSN {
+	var dsn DSN
+	if connector.dsn != nil {
+		dsn = *connector.dsn
+	} else {
+		// a Connector{} literal
+		dsn = *newDSN()
+	}
+	if connector.MaxRows != 0 {
+		dsn.maxRows = connector.MaxRows
+	}
+	if connector.CloseTimeout != 0 {
+		dsn.closeTimeout = connector.CloseTimeout
+	}
+	if connector.ReadOnly {
+		dsn.readOnly = true
+	}
+	if connector.GroupCommitCount != 0 {
+		dsn.groupCommitCount = connector.GroupCommitCount
+	}
+	if connector.GroupCommitInterval != 0 {
+		dsn.groupCommitInterval = connector.GroupCommitInterval
+	}
+	return &dsn
+}
@@ -0,0 +1,134 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestConnector checks connecting with connectors from NewConnectorWithOptions and OpenConnector
+func TestConnector(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	connector, err := NewConnectorWithOptions(ConnectorDSN(testGetOpenString("")), ConnectorNLS("AMERICAN", "AMERICA"))
+	if err != nil {
+		t.Fatal("NewConnectorWithOptions error:", err)
+	}
+	connector.MaxRows = 1
+	driverConnector, err := Driver.OpenConnector(testGetOpenString("?max_rows=1"))
+	if err != nil {
+		t.Fatal("OpenConnector error:", err)
+	}
+
+	for _, driverConnector := range []driver.Connector{connector, driverConnector} {
+		db := sql.OpenDB(driverConnector)
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+
+		var result int64
+		err = db.QueryRowContext(ctx, "select 1 from dual").Scan(&result)
+		if err != nil {
+			t.Error("query error:", err)
+		} else if result != 1 {
+			t.Errorf("query result - expected: %v - received: %v", 1, result)
+		}
+
+		// the max rows of the connector or DSN applies
+		rows, err := db.QueryContext(ctx, "select 1 from dual union all select 2 from dual")
+		if err != nil {
+			t.Error("query error:", err)
+		} else {
+			for rows.Next() {
+			}
+			if rows.Err() != ErrTooManyRows {
+				t.Errorf("rows error - expected: %v - received: %v", ErrTooManyRows, rows.Err())
+			}
+			rows.Close()
+		}
+
+		cancel()
+		db.Close()
+	}
+}
+
+// TestNewConnector tests the connector without options uses the default DSN
+func TestNewConnector(t *testing.T) {
+	t.Parallel()
+
+	connector, ok := NewConnector().(*Connector)
+	if !ok {
+		t.Fatal("NewConnector - expected: *Connector - received: not *Connector")
+	}
+	if dsn := connector.connectDSN(); dsn.timeLocation != time.UTC || dsn.prefetchMemory != 4096 {
+		t.Errorf("NewConnector DSN - expected: %+v - received: %+v", newDSN(), dsn)
+	}
+}
+
+// TestNewConnectorWithOptions tests the connector options set the same DSN as the DSN parameters
+func TestNewConnectorWithOptions(t *testing.T) {
+	t.Parallel()
+
+	connector, err := NewConnectorWithOptions(
+		ConnectorCredentials("scott", "tiger"),
+		ConnectorConnectString("dbhost:1521/ORCLPDB1"),
+		ConnectorPrefetch(1000, 0),
+		ConnectorStmtCacheSize(20),
+		ConnectorCharset("AL32UTF8"),
+		ConnectorIgnoreEnv(),
+		ConnectorUTF16(),
+		ConnectorNLS("GERMAN", "GERMANY"),
+		ConnectorTempTablespace("TEMP_LOBS"),
+		ConnectorTNSAdmin("/etc/oracle"),
+		ConnectorOracleHome("/opt/oracle"),
+		ConnectorSessionPool(1, 4, 0),
+	)
+	if err != nil {
+		t.Fatal("NewConnectorWithOptions error:", err)
+	}
+	expected, err := ParseDSN("scott/tiger@dbhost:1521/ORCLPDB1?prefetch_rows=1000&prefetch_memory=0&stmt_cache_size=20" +
+		"&charset=AL32UTF8&ignore_env=true&utf16=true&nls_language=GERMAN&nls_territory=GERMANY&temp_tablespace=TEMP_LOBS&tns_admin=%2Fetc%2Foracle&oracle_home=%2Fopt%2Foracle&pool_min=1&pool_max=4")
+	if err != nil {
+		t.Fatal("ParseDSN error:", err)
+	}
+	if !reflect.DeepEqual(connector.dsn, expected) {
+		t.Errorf("NewConnectorWithOptions DSN - expected: %+v - received: %+v", expected, connector.dsn)
+	}
+
+	connector, err = NewConnectorWithOptions(ConnectorDSN("scott/tiger@dbhost?max_rows=10&read_only=false"), ConnectorNLS("", "AMERICA"))
+	if err != nil {
+		t.Fatal("NewConnectorWithOptions error:", err)
+	}
+	connector.MaxRows = 20
+	connector.ReadOnly = true
+	dsn := connector.connectDSN()
+	if dsn.nlsTerritory != "AMERICA" || dsn.maxRows != 20 || !dsn.readOnly {
+		t.Errorf("connect DSN - expected: AMERICA, 20, true - received: %v, %v, %v", dsn.nlsTerritory, dsn.maxRows, dsn.readOnly)
+	}
+	if connector.dsn.maxRows != 10 || connector.dsn.readOnly {
+		t.Errorf("connector DSN - expected: 10, false - received: %v, %v", connector.dsn.maxRows, connector.dsn.readOnly)
+	}
+
+	for _, options := range [][]ConnectorOption{
+		{ConnectorDSN("")},
+		{ConnectorTimeLocation(nil)},
+		{ConnectorTempTablespace("temp lobs")},
+		{ConnectorSessionPool(0, 0, 0)},
+		{ConnectorSessionPool(5, 2, 1)},
+	} {
+		_, err = NewConnectorWithOptions(options...)
+		if err == nil {
+			t.Errorf("NewConnectorWithOptions - expected: error - received: nil")
+		}
+	}
+
+	driverConnector, err := Driver.OpenConnector("scott/tiger@dbhost?prefetch_rows=x")
+	if err == nil {
+		t.Errorf("OpenConnector - expected: error - received: %v", driverConnector)
+	}
+}
@@ -0,0 +1,328 @@
+package oci8
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// contextKey is the type of the context keys used by the driver
+type contextKey int
+
+const (
+	contextKeyScanColumns contextKey = iota
+	contextKeyLongPieces
+	contextKeyMemoryBudget
+	contextKeyMaxRows
+	contextKeyPrefetch
+	contextKeyLobReaders
+	contextKeyNumberStrings
+	contextKeyRawBytes
+	contextKeyTempLobOptions
+	contextKeyRequestID
+	contextKeyStmtOptions
+)
+
+const (
+	// LobValues reads BLOB, CLOB, and NCLOB values whole into a []byte or string, the default
+	LobValues LobMode = iota
+	// LobReaders returns BLOB, CLOB, and NCLOB values as a *Lob, see WithLobReaders
+	LobReaders
+)
+
+const (
+	// NumberDefault returns NUMBER and FLOAT columns as set by the number_strings DSN parameter
+	NumberDefault NumberMode = iota
+	// NumberNative returns NUMBER and FLOAT columns as int64 and float64
+	NumberNative
+	// NumberStrings returns NUMBER and FLOAT columns as exact decimal strings
+	NumberStrings
+)
+
+type (
+	// LobMode is how queries return LOB values, see StmtOptions
+	LobMode int
+
+	// NumberMode is how queries return NUMBER and FLOAT values, see StmtOptions
+	NumberMode int
+
+	// StmtOptions are the options of the queries and execs run with a context, see WithStmtOptions.
+	// Zero values keep the defaults of the connection.
+	StmtOptions struct {
+		// PrefetchRows and PrefetchMemory are the number of rows and the memory in bytes prefetched by a query, see WithPrefetch.
+		// Set only when one of them is not 0, so prefetching can not be turned off with StmtOptions.
+		PrefetchRows   uint32
+		PrefetchMemory uint32
+		// FetchArraySize is the number of rows fetched into the column buffers by each fetch of a query,
+		// overriding the fetch_array_size DSN parameter
+		FetchArraySize uint32
+		// LobMode is how queries return LOB values
+		LobMode LobMode
+		// NumberMode is how queries return NUMBER and FLOAT values, overriding the number_strings DSN parameter
+		NumberMode NumberMode
+		// Timeout limits how long a query or exec runs, including fetching the rows of a query until the rows are closed.
+		// When it expires, the call is cancelled with OCIBreak like for a done context.
+		Timeout time.Duration
+	}
+)
+
+// LongPieceFunc is called with each piece of a LONG or LONG RAW column, column is the zero based column index.
+// The piece is only valid until the function returns.
+type LongPieceFunc func(column int, piece []byte) error
+
+// scanColumns are the columns of a query the caller will scan
+type scanColumns struct {
+	names   map[string]struct{}
+	indexes map[int]struct{}
+}
+
+// WithScanColumns returns a context that limits the columns fetched by a query to the named columns.
+// Other columns are defined without a data buffer, are not copied, and are returned as nil.
+// Column names are matched case insensitively. Can be combined with WithScanColumnIndexes.
+// Nested cursor, object, and BFILE columns are always fetched.
+func WithScanColumns(ctx context.Context, names ...string) context.Context {
+	columns := scanColumnsFromContext(ctx).clone()
+	for _, name := range names {
+		columns.names[strings.ToUpper(name)] = struct{}{}
+	}
+	return context.WithValue(ctx, contextKeyScanColumns, columns)
+}
+
+// WithScanColumnIndexes returns a context that limits the columns fetched by a query to the zero based column indexes.
+// Other columns are defined without a data buffer, are not copied, and are returned as nil.
+// Can be combined with WithScanColumns. Nested cursor, object, and BFILE columns are always fetched.
+func WithScanColumnIndexes(ctx context.Context, indexes ...int) context.Context {
+	columns := scanColumnsFromContext(ctx).clone()
+	for _, index := range indexes {
+		columns.indexes[index] = struct{}{}
+	}
+	return context.WithValue(ctx, contextKeyScanColumns, columns)
+}
+
+// scanColumnsFromContext returns the scan columns of the context, nil if not set
+func scanColumnsFromContext(ctx context.Context) *scanColumns {
+	columns, _ := ctx.Value(contextKeyScanColumns).(*scanColumns)
+	return columns
+}
+
+// clone returns a copy of scan columns, or new scan columns if nil
+func (columns *scanColumns) clone() *scanColumns {
+	newColumns := &scanColumns{
+		names:   make(map[string]struct{}),
+		indexes: make(map[int]struct{}),
+	}
+	if columns == nil {
+		return newColumns
+	}
+	for name := range columns.names {
+		newColumns.names[name] = struct{}{}
+	}
+	for index := range columns.indexes {
+		newColumns.indexes[index] = struct{}{}
+	}
+	return newColumns
+}
+
+// wants returns true if the column should be fetched. A nil scanColumns wants all columns.
+func (columns *scanColumns) wants(index int, name string) bool {
+	if columns == nil {
+		return true
+	}
+	if _, ok := columns.indexes[index]; ok {
+		return true
+	}
+	_, ok := columns.names[strings.ToUpper(name)]
+	return ok
+}
+
+// WithLongPieces returns a context that makes queries fetch LONG and LONG RAW columns in pieces,
+// calling fn with each piece while the row is fetched by rows.Next, so the whole value is never held in memory.
+// The scanned value of these columns is the total number of bytes read as an int64, or nil when null.
+// An error returned by fn cancels the query and is returned by rows.Next.
+func WithLongPieces(ctx context.Context, fn LongPieceFunc) context.Context {
+	return context.WithValue(ctx, contextKeyLongPieces, fn)
+}
+
+// longPiecesFromContext returns the long piece function of the context, nil if not set
+func longPiecesFromContext(ctx context.Context) LongPieceFunc {
+	fn, _ := ctx.Value(contextKeyLongPieces).(LongPieceFunc)
+	return fn
+}
+
+// WithMemoryBudget returns a context that limits the client memory used to fetch a query to budget bytes.
+// The prefetch memory is lowered to the budget, so fewer rows are prefetched for wide rows, and once the row size is known
+// after the execute, the prefetch rows and the fetch array size are lowered to the number of rows that fit in the budget.
+// A query with row buffers larger than the budget returns a *MemoryBudgetError instead of allocating them.
+// LOB values read after the fetch are not counted.
+func WithMemoryBudget(ctx context.Context, budget int64) context.Context {
+	return context.WithValue(ctx, contextKeyMemoryBudget, budget)
+}
+
+// memoryBudgetFromContext returns the memory budget of the context, 0 if not set
+func memoryBudgetFromContext(ctx context.Context) int64 {
+	budget, _ := ctx.Value(contextKeyMemoryBudget).(int64)
+	return budget
+}
+
+// WithMaxRows returns a context that limits the number of rows a query can return to maxRows.
+// Fetching more rows returns ErrTooManyRows, protecting from runaway queries like ones with user supplied filters.
+// Overrides the max_rows DSN parameter and Connector MaxRows. A 0 means the connection limit is used.
+func WithMaxRows(ctx context.Context, maxRows int64) context.Context {
+	return context.WithValue(ctx, contextKeyMaxRows, maxRows)
+}
+
+// maxRowsFromContext returns the max rows of the context, 0 if not set
+func maxRowsFromContext(ctx context.Context) int64 {
+	maxRows, _ := ctx.Value(contextKeyMaxRows).(int64)
+	return maxRows
+}
+
+// prefetch is the prefetch rows and memory of a query
+type prefetch struct {
+	rows   uint32
+	memory uint32
+}
+
+// WithPrefetch returns a context that sets the number of rows and the memory in bytes prefetched by a query,
+// overriding the prefetch_rows and prefetch_memory DSN parameters. A 0 means no limit, as with the DSN parameters,
+// so WithPrefetch(ctx, 10000, 0) fetches up to 10000 rows in each round trip for a large result set scan.
+// With both 0 prefetching is turned off.
+// A memory budget from WithMemoryBudget still lowers the prefetch memory.
+func WithPrefetch(ctx context.Context, rows uint32, memory uint32) context.Context {
+	return context.WithValue(ctx, contextKeyPrefetch, prefetch{rows: rows, memory: memory})
+}
+
+// prefetchFromContext returns the prefetch of the context, ok is false if not set
+func prefetchFromContext(ctx context.Context) (prefetch, bool) {
+	value, ok := ctx.Value(contextKeyPrefetch).(prefetch)
+	return value, ok
+}
+
+// WithLobReaders returns a context that makes queries return BLOB, CLOB, and NCLOB values as a *Lob,
+// which reads the value in chunks, instead of reading the whole value into a []byte or string,
+// so multi GB values can be copied without holding them in memory. Scan the column into a *oci8.Lob:
+//
+//	var lob *oci8.Lob
+//	err = rows.Scan(&id, &lob)
+//	if err == nil && lob != nil {
+//		_, err = io.Copy(file, lob)
+//	}
+//
+// A null value is nil. The Lob can only be read until the next call to rows.Next or rows.Close.
+// Lob values are not passed to the Converter.
+func WithLobReaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyLobReaders, true)
+}
+
+// lobReadersFromContext returns true when the context returns LOB values as *Lob
+func lobReadersFromContext(ctx context.Context) bool {
+	lobReaders, _ := ctx.Value(contextKeyLobReaders).(bool)
+	return lobReaders
+}
+
+// WithNumberStrings returns a context that makes queries return NUMBER and FLOAT columns as exact decimal strings when enabled,
+// or as int64 and float64 when not, overriding the number_strings DSN parameter
+func WithNumberStrings(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, contextKeyNumberStrings, enabled)
+}
+
+// numberStringsFromContext returns if the context returns NUMBER columns as strings, ok is false if not set
+func numberStringsFromContext(ctx context.Context) (bool, bool) {
+	enabled, ok := ctx.Value(contextKeyNumberStrings).(bool)
+	return enabled, ok
+}
+
+// WithRawBytes returns a context that makes queries return CHAR, VARCHAR2, and LONG values as a []byte
+// of the fetch buffer of the driver instead of a string, so scanning into a *sql.RawBytes does not copy the value:
+//
+//	var name sql.RawBytes
+//	for rows.Next() {
+//		err = rows.Scan(&id, &name)
+//		...
+//		writer.Write(name)
+//	}
+//
+// The fetch buffer is reused by the next row, a sql.RawBytes is only valid until the next call to rows.Next, rows.Scan, or rows.Close.
+// Scanning into a *string or *[]byte copies the value once, as without WithRawBytes,
+// but an *interface{} gets a []byte in place of a string.
+// With the utf16 DSN parameter the values are converted to UTF-8, which is a copy.
+func WithRawBytes(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyRawBytes, true)
+}
+
+// rawBytesFromContext returns true when the context returns character values as []byte of the fetch buffer
+func rawBytesFromContext(ctx context.Context) bool {
+	rawBytes, _ := ctx.Value(contextKeyRawBytes).(bool)
+	return rawBytes
+}
+
+// WithTempLobOptions returns a context that creates the temporary LOBs of the binds with the options,
+// like the CLOB of a string longer than 32767 bytes or of a LobStream:
+//
+//	ctx = oci8.WithTempLobOptions(ctx, oci8.TempLobOptions{Compress: true})
+//	_, err = db.ExecContext(ctx, "insert into documents (id, body) values (:1, :2)", id, body)
+func WithTempLobOptions(ctx context.Context, options TempLobOptions) context.Context {
+	return context.WithValue(ctx, contextKeyTempLobOptions, options)
+}
+
+// tempLobOptionsFromContext returns the options of the temporary LOBs of the binds, the zero value if not set
+func tempLobOptionsFromContext(ctx context.Context) TempLobOptions {
+	options, _ := ctx.Value(contextKeyTempLobOptions).(TempLobOptions)
+	return options
+}
+
+// WithRequestID returns a context that annotates the statements run with it with a client-side request ID,
+// for end-to-end tracing from the middleware layer with one call:
+//
+//	ctx = oci8.WithRequestID(r.Context(), r.Header.Get("X-Request-ID"))
+//
+// Statements prepared with the context start with the comment /* request_id=id */, which shows in V$SQL,
+// and CLIENT_INFO is set to the ID while statements run with the context execute, which shows in V$SESSION,
+// then restored to the CLIENT_INFO the session had before, like one set with DBMS_APPLICATION_INFO.SET_CLIENT_INFO.
+// Both are sent with the statement execute. The CLIENT_INFO to restore is read with one extra round trip
+// by the first statement with a request ID on the connection, then kept, so CLIENT_INFO set by the application
+// after that is replaced by the first one when a statement with a request ID is done.
+// The ID is truncated to the 64 bytes of CLIENT_INFO, and a */ in it is changed to * / so it can not end the comment.
+// Each request ID makes a different statement text, which the server parses and caches as a separate cursor.
+// The statement cache of stmt_cache_size is keyed on the text without the comment, so a cached statement
+// keeps the comment it was first prepared with.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKeyRequestID, sanitizeRequestID(id))
+}
+
+// requestIDFromContext returns the request ID of the context, empty if not set
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKeyRequestID).(string)
+	return id
+}
+
+// WithStmtOptions returns a context with the options of the queries and execs run with it,
+// to override the connection defaults for individual queries without changing the DSN:
+//
+//	ctx = oci8.WithStmtOptions(ctx, oci8.StmtOptions{FetchArraySize: 1000, NumberMode: oci8.NumberStrings, Timeout: time.Minute})
+//	rows, err := db.QueryContext(ctx, "select ID, AMOUNT from PAYMENTS")
+//
+// The options are the same as the WithPrefetch, WithLobReaders, and WithNumberStrings contexts, which they replace.
+// The options of the context passed to PrepareContext apply to the queries and execs of the prepared statement
+// that are run with a context without options.
+func WithStmtOptions(ctx context.Context, options StmtOptions) context.Context {
+	if options.PrefetchRows != 0 || options.PrefetchMemory != 0 {
+		ctx = WithPrefetch(ctx, options.PrefetchRows, options.PrefetchMemory)
+	}
+	if options.LobMode == LobReaders {
+		ctx = WithLobReaders(ctx)
+	}
+	switch options.NumberMode {
+	case NumberNative:
+		ctx = WithNumberStrings(ctx, false)
+	case NumberStrings:
+		ctx = WithNumberStrings(ctx, true)
+	}
+	return context.WithValue(ctx, contextKeyStmtOptions, options)
+}
+
+// stmtOptionsFromContext returns the statement options of the context, ok is false if not set
+func stmtOptionsFromContext(ctx context.Context) (StmtOptions, bool) {
+	options, ok := ctx.Value(contextKeyStmtOptions).(StmtOptions)
+	return options, ok
+}
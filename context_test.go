@@ -0,0 +1,59 @@
+package oci8
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestWithStmtOptions tests the statement options of a context and of the prepare context of a statement
+func TestWithStmtOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithStmtOptions(context.Background(), StmtOptions{FetchArraySize: 500, LobMode: LobReaders, NumberMode: NumberStrings})
+	if received, ok := prefetchFromContext(ctx); ok {
+		t.Errorf("prefetch - expected: not set - received: %+v", received)
+	}
+	stmt := &Stmt{conn: &Conn{fetchArraySize: 10}, ctx: ctx}
+	if size := stmt.fetchArraySize(nil, 0); size != 500 {
+		t.Errorf("fetch array size - expected: %v - received: %v", 500, size)
+	}
+	if !lobReadersFromContext(ctx) {
+		t.Errorf("lob readers - expected: %v - received: %v", true, false)
+	}
+	if enabled, ok := numberStringsFromContext(ctx); !ok || !enabled {
+		t.Errorf("number strings - expected: %v - received: %v %v", true, enabled, ok)
+	}
+
+	ctx = WithStmtOptions(context.Background(), StmtOptions{PrefetchRows: 10, FetchArraySize: 500, NumberMode: NumberNative})
+	received, _ := prefetchFromContext(ctx)
+	if received != (prefetch{rows: 10}) {
+		t.Errorf("prefetch - expected: %+v - received: %+v", prefetch{rows: 10}, received)
+	}
+	if enabled, ok := numberStringsFromContext(ctx); !ok || enabled {
+		t.Errorf("number strings - expected: %v - received: %v %v", false, enabled, ok)
+	}
+
+	// the options of the prepare context apply when the query context has none
+	stmt = &Stmt{options: &StmtOptions{Timeout: time.Hour, NumberMode: NumberStrings}}
+	ctx, cancel := stmt.optionsContext(context.Background())
+	if _, ok := ctx.Deadline(); !ok {
+		t.Errorf("deadline - expected: set - received: not set")
+	}
+	if enabled, _ := numberStringsFromContext(ctx); !enabled {
+		t.Errorf("number strings - expected: %v - received: %v", true, enabled)
+	}
+	cancel()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("cancel - expected: %v - received: %v", context.Canceled, ctx.Err())
+	}
+
+	ctx, cancel = stmt.optionsContext(WithStmtOptions(context.Background(), StmtOptions{}))
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("deadline - expected: not set - received: set")
+	}
+	if _, ok := numberStringsFromContext(ctx); ok {
+		t.Errorf("number strings - expected: not set - received: set")
+	}
+}
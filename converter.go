@@ -0,0 +1,77 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-oci8/oracle"
+)
+
+type (
+	// Converter converts result values and bool binds, so each sql.DB can have its own type mapping.
+	// Set it on the Connector or DriverStruct, it is copied to connections when they are opened.
+	// Embed DefaultConverter to only change some of the policies.
+	// Null values are not passed to the converter.
+	Converter = oracle.Converter
+
+	// ConverterColumn is the result column of a value passed to a Converter
+	ConverterColumn = oracle.ConverterColumn
+
+	// DefaultConverter returns all values unchanged
+	DefaultConverter struct{}
+)
+
+// ConvertNumber returns the value unchanged
+func (DefaultConverter) ConvertNumber(column ConverterColumn, value driver.Value) (driver.Value, error) {
+	return value, nil
+}
+
+// ConvertTime returns the value unchanged
+func (DefaultConverter) ConvertTime(column ConverterColumn, value time.Time) (driver.Value, error) {
+	return value, nil
+}
+
+// ConvertLob returns the value unchanged
+func (DefaultConverter) ConvertLob(column ConverterColumn, value driver.Value) (driver.Value, error) {
+	return value, nil
+}
+
+// ConvertBool returns the value unchanged
+func (DefaultConverter) ConvertBool(value bool) (driver.Value, error) {
+	return value, nil
+}
+
+// convertValues passes the not null values of a row to the converter of the connection
+func (rows *Rows) convertValues(dest []driver.Value) error {
+	converter := rows.stmt.conn.converter
+	for i, value := range dest {
+		if value == nil || rows.defines[i].piecewise {
+			continue
+		}
+		if _, ok := value.(*Lob); ok {
+			continue
+		}
+
+		column := ConverterColumn{Index: i, Name: rows.defines[i].name}
+		var err error
+		switch rows.defines[i].dataType {
+		case C.SQLT_INT, C.SQLT_BDOUBLE, C.SQLT_NUM:
+			column.DatabaseTypeName = rows.ColumnTypeDatabaseTypeName(i)
+			dest[i], err = converter.ConvertNumber(column, value)
+		case C.SQLT_DAT, C.SQLT_TIMESTAMP, C.SQLT_TIMESTAMP_TZ, C.SQLT_TIMESTAMP_LTZ:
+			column.DatabaseTypeName = rows.ColumnTypeDatabaseTypeName(i)
+			dest[i], err = converter.ConvertTime(column, value.(time.Time))
+		case C.SQLT_BLOB, C.SQLT_CLOB:
+			column.DatabaseTypeName = rows.ColumnTypeDatabaseTypeName(i)
+			dest[i], err = converter.ConvertLob(column, value)
+		}
+		if err != nil {
+			return fmt.Errorf("convert for column %v - error: %v", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testConverter converts numbers and times to strings, CLOBs to []byte, and binds bools as Y or N
+type testConverter struct {
+	DefaultConverter
+}
+
+func (testConverter) ConvertNumber(column ConverterColumn, value driver.Value) (driver.Value, error) {
+	return column.Name + "=" + fmt.Sprint(value), nil
+}
+
+func (testConverter) ConvertTime(column ConverterColumn, value time.Time) (driver.Value, error) {
+	return value.UTC().Format(time.RFC3339), nil
+}
+
+func (testConverter) ConvertLob(column ConverterColumn, value driver.Value) (driver.Value, error) {
+	if text, ok := value.(string); ok {
+		return []byte(text), nil
+	}
+	return value, nil
+}
+
+func (testConverter) ConvertBool(value bool) (driver.Value, error) {
+	if value {
+		return "Y", nil
+	}
+	return "N", nil
+}
+
+// testDriverConnector opens connections with a driver, to test driver settings without registering the driver
+type testDriverConnector struct {
+	driver *DriverStruct
+	dsn    string
+}
+
+func (connector testDriverConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return connector.driver.Open(connector.dsn)
+}
+
+func (connector testDriverConnector) Driver() driver.Driver {
+	return connector.driver
+}
+
+// TestConverter checks a Converter changes the values of one sql.DB only
+func TestConverter(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := sql.OpenDB(testDriverConnector{driver: &DriverStruct{Converter: testConverter{}}, dsn: testGetOpenString("")})
+	defer db.Close()
+
+	query := "select 12 as A, cast(1.5 as BINARY_DOUBLE) as B, timestamp '2020-01-02 03:04:05 +00:00' as C, " +
+		"to_clob('clob') as D, 'text' as E, cast(null as number(5)) as F, :1 as G from dual"
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	var a, b, c, e, g string
+	var d []byte
+	var f sql.NullString
+	err := db.QueryRowContext(ctx, query, true).Scan(&a, &b, &c, &d, &e, &f, &g)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	received := []interface{}{a, b, c, string(d), e, f.Valid, g}
+	expected := []interface{}{"A=12", "B=1.5", "2020-01-02T03:04:05Z", "clob", "text", false, "Y"}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("converted - expected: %v - received: %v", expected, received)
+	}
+
+	// the default sql.DB is not changed
+	var number int64
+	var aTime time.Time
+	err = TestDB.QueryRowContext(ctx, "select 12, timestamp '2020-01-02 03:04:05 +00:00' from dual").Scan(&number, &aTime)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if number != 12 || aTime.Year() != 2020 {
+		t.Errorf("not converted - received: %v, %v", number, aTime)
+	}
+}
@@ -0,0 +1,131 @@
+package oci8
+
+import (
+	"errors"
+	"time"
+)
+
+/*
+Oracle dates range from January 1, 4712 BC to December 31, 9999 AD.
+Oracle has no year 0, the year before 1 AD is -1 (1 BC).
+Go uses astronomical year numbering, where year 0 is 1 BC and year -4711 is 4712 BC.
+*/
+
+const (
+	// oracleMinYear is the first Oracle date year, 4712 BC
+	oracleMinYear = -4712
+	// oracleMaxYear is the last Oracle date year
+	oracleMaxYear = 9999
+)
+
+/*
+Oracle DATE format (SQLT_DAT)
+
+A DATE is 7 bytes: century + 100, year of century + 100, month, day, hour + 1, minute + 1, second + 1.
+BC years have negative century and year of century, so the bytes are below 100.
+*/
+
+var (
+	// ErrDateOutOfRange is returned when binding a time that is outside of the Oracle date range of 4712 BC to 9999 AD
+	ErrDateOutOfRange = errors.New("time is outside of the Oracle date range of 4712 BC to 9999 AD")
+	// ErrInvalidDate is returned when bytes are not a valid Oracle DATE
+	ErrInvalidDate = errors.New("invalid date")
+)
+
+// oracleYearToGo converts an Oracle year, that has no year 0, to a Go year
+func oracleYearToGo(year int) int {
+	if year < 0 {
+		return year + 1
+	}
+	return year
+}
+
+// goYearToOracle converts a Go year to an Oracle year, that has no year 0
+func goYearToOracle(year int) int {
+	if year < 1 {
+		return year - 1
+	}
+	return year
+}
+
+// encodeDate encodes the wall clock of a time in its location into Oracle DATE bytes, fractional seconds are truncated.
+// The time must be inside of the Oracle date range.
+func encodeDate(aTime time.Time) []byte {
+	year := goYearToOracle(aTime.Year())
+	return []byte{
+		byte(year/100 + 100),
+		byte(year%100 + 100),
+		byte(aTime.Month()),
+		byte(aTime.Day()),
+		byte(aTime.Hour() + 1),
+		byte(aTime.Minute() + 1),
+		byte(aTime.Second() + 1),
+	}
+}
+
+// decodeDate decodes Oracle DATE bytes into a time in location
+func decodeDate(buffer []byte, location *time.Location) time.Time {
+	return time.Date(
+		oracleYearToGo((int(buffer[0])-100)*100+(int(buffer[1])-100)),
+		time.Month(int(buffer[2])),
+		int(buffer[3]),
+		int(buffer[4])-1,
+		int(buffer[5])-1,
+		int(buffer[6])-1,
+		0,
+		location)
+}
+
+// dateRangeTime checks a time is inside of the Oracle date range, in the time's location.
+// Times outside of the range return ErrDateOutOfRange or, when clamp is true, the first or last time of the range.
+func dateRangeTime(aTime time.Time, clamp bool) (time.Time, error) {
+	location := aTime.Location()
+	minTime := time.Date(oracleYearToGo(oracleMinYear), time.January, 1, 0, 0, 0, 0, location)
+	if aTime.Before(minTime) {
+		if clamp {
+			return minTime, nil
+		}
+		return aTime, ErrDateOutOfRange
+	}
+
+	maxTime := time.Date(oracleMaxYear, time.December, 31, 23, 59, 59, 999999999, location)
+	if aTime.After(maxTime) {
+		if clamp {
+			return maxTime, nil
+		}
+		return aTime, ErrDateOutOfRange
+	}
+
+	return aTime, nil
+}
+
+/*
+Time normalization is done in normalizeTime, for both bound and returned times,
+so round-tripped times compare deterministically with == and reflect.DeepEqual, not just with Time.Equal.
+
+Bound times have the monotonic clock reading stripped and keep their location.
+The time zone offset is sent with the value, so TIMESTAMP WITH TIME ZONE columns store the offset of the time,
+and the offset is truncated for DATE and TIMESTAMP columns.
+
+Returned times never have a monotonic clock reading.
+DATE and TIMESTAMP values are returned in the loc DSN location.
+TIMESTAMP WITH TIME ZONE and TIMESTAMP WITH LOCAL TIME ZONE values are returned in the loc DSN location
+when it has the same offset at that time, otherwise in a fixed offset location, that is cached for whole hour offsets.
+TIMESTAMP WITH LOCAL TIME ZONE values are returned in the ltz_loc DSN location instead when it is set.
+*/
+
+// normalizeTime strips the monotonic clock reading of a time.
+// When location is not nil and has the same offset as the time at that instant, the time is moved to location.
+func normalizeTime(aTime time.Time, location *time.Location) time.Time {
+	aTime = aTime.Round(0)
+	if location == nil || aTime.Location() == location {
+		return aTime
+	}
+
+	_, offset := aTime.Zone()
+	locationTime := aTime.In(location)
+	if _, locationOffset := locationTime.Zone(); locationOffset == offset {
+		return locationTime
+	}
+	return aTime
+}
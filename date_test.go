@@ -0,0 +1,323 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-oci8/oracle"
+)
+
+// TestAlterSessionTimeZone tests the alter session statement for the session time zone
+func TestAlterSessionTimeZone(t *testing.T) {
+	t.Parallel()
+
+	var alterTests = []struct {
+		timeZone string
+		valid    bool
+		expected string
+	}{
+		{"UTC", true, "alter session set TIME_ZONE = 'UTC'"},
+		{"+02:00", true, "alter session set TIME_ZONE = '+02:00'"},
+		{"America/Argentina/Buenos_Aires", true, "alter session set TIME_ZONE = 'America/Argentina/Buenos_Aires'"},
+		{"", false, ""},
+		{"UTC' scope=spfile", false, ""},
+	}
+	for _, tt := range alterTests {
+		valid := oracle.ValidTimeZone(tt.timeZone)
+		if valid != tt.valid {
+			t.Errorf("oracle.ValidTimeZone(%q) - expected: %v - received: %v", tt.timeZone, tt.valid, valid)
+			continue
+		}
+		if !valid {
+			continue
+		}
+		query := alterSessionTimeZone(tt.timeZone)
+		if query != tt.expected {
+			t.Errorf("alterSessionTimeZone(%q) - expected: %v - received: %v", tt.timeZone, tt.expected, query)
+		}
+	}
+}
+
+// TestDateRange tests Oracle year conversion and the Oracle date range
+func TestDateRange(t *testing.T) {
+	t.Parallel()
+
+	var yearTests = []struct {
+		oracleYear int
+		goYear     int
+	}{
+		{-4712, -4711},
+		{-1, 0},
+		{1, 1},
+		{2020, 2020},
+	}
+	for _, tt := range yearTests {
+		if oracleYearToGo(tt.oracleYear) != tt.goYear {
+			t.Errorf("oracleYearToGo(%v) - expected: %v - received: %v", tt.oracleYear, tt.goYear, oracleYearToGo(tt.oracleYear))
+		}
+		if goYearToOracle(tt.goYear) != tt.oracleYear {
+			t.Errorf("goYearToOracle(%v) - expected: %v - received: %v", tt.goYear, tt.oracleYear, goYearToOracle(tt.goYear))
+		}
+	}
+
+	minTime := time.Date(-4711, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxTime := time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC)
+	var rangeTests = []struct {
+		aTime    time.Time
+		clamp    bool
+		expected time.Time
+		err      error
+	}{
+		{minTime, false, minTime, nil},
+		{maxTime, false, maxTime, nil},
+		{time.Time{}, false, time.Time{}, nil},
+		{minTime.Add(-time.Nanosecond), false, minTime.Add(-time.Nanosecond), ErrDateOutOfRange},
+		{maxTime.Add(time.Nanosecond), false, maxTime.Add(time.Nanosecond), ErrDateOutOfRange},
+		{minTime.AddDate(-1000, 0, 0), true, minTime, nil},
+		{maxTime.AddDate(1000, 0, 0), true, maxTime, nil},
+	}
+	for _, tt := range rangeTests {
+		aTime, err := dateRangeTime(tt.aTime, tt.clamp)
+		if err != tt.err {
+			t.Errorf("dateRangeTime(%v, %v) - expected error: %v - received: %v", tt.aTime, tt.clamp, tt.err, err)
+		}
+		if !aTime.Equal(tt.expected) {
+			t.Errorf("dateRangeTime(%v, %v) - expected: %v - received: %v", tt.aTime, tt.clamp, tt.expected, aTime)
+		}
+	}
+}
+
+// TestNormalizeTime tests time normalization
+func TestNormalizeTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	aTime := normalizeTime(now, nil)
+	if aTime != now.Round(0) {
+		t.Errorf("normalizeTime now - expected: %v - received: %v", now.Round(0), aTime)
+	}
+	if aTime.Location() != now.Location() {
+		t.Errorf("normalizeTime location - expected: %v - received: %v", now.Location(), aTime.Location())
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("America/New_York location not found:", err)
+	}
+
+	utc := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	minus5 := time.Date(2020, 1, 2, 3, 4, 5, 6, timezoneToLocation(-5, 0))
+	plus1 := time.Date(2020, 1, 2, 3, 4, 5, 6, timezoneToLocation(1, 0))
+	var normalizeTests = []struct {
+		aTime    time.Time
+		location *time.Location
+		expected time.Time
+	}{
+		{utc, nil, utc},
+		{utc, time.UTC, utc},
+		{time.Date(2020, 1, 2, 3, 4, 5, 6, timezoneToLocation(0, 0)), time.UTC, utc},
+		{minus5, newYork, minus5.In(newYork)},
+		{minus5, time.UTC, minus5},
+		{plus1, newYork, plus1},
+		// New York is -4 in the summer
+		{time.Date(2020, 7, 2, 3, 4, 5, 6, timezoneToLocation(-5, 0)), newYork, time.Date(2020, 7, 2, 3, 4, 5, 6, timezoneToLocation(-5, 0))},
+	}
+	for _, tt := range normalizeTests {
+		aTime = normalizeTime(tt.aTime, tt.location)
+		if aTime != tt.expected {
+			t.Errorf("normalizeTime(%v, %v) - expected: %v - received: %v", tt.aTime, tt.location, tt.expected, aTime)
+		}
+	}
+}
+
+// TestSelectDateRange checks the first and last Oracle dates and the date_range setting
+func TestSelectDateRange(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	minTime := time.Date(-4711, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxTime := time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+	oneBC := time.Date(0, 6, 15, 1, 2, 3, 0, time.UTC)
+
+	queryResults := testQueryResults{
+		query:        "select to_date('4712-01-01 BC', 'YYYY-MM-DD BC'), to_date('0001-06-15 01:02:03 BC', 'YYYY-MM-DD HH24:MI:SS BC'), to_date('9999-12-31 23:59:59', 'YYYY-MM-DD HH24:MI:SS') from dual",
+		queryResults: []testQueryResult{{results: [][]interface{}{{minTime, oneBC, maxTime}}}},
+	}
+	testRunQueryResults(t, queryResults)
+
+	queryResults = testQueryResults{
+		query: "select cast (:1 as TIMESTAMP(9)), to_char(cast (:2 as DATE), 'YYYY-MM-DD BC') from dual",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{minTime, oneBC},
+				results: [][]interface{}{{minTime, "0001-06-15 BC"}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err := TestDB.ExecContext(ctx, "select :1 from dual", time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC))
+	cancel()
+	if err == nil || !strings.Contains(err.Error(), ErrDateOutOfRange.Error()) {
+		t.Errorf("out of range - expected: %v - received: %v", ErrDateOutOfRange, err)
+	}
+
+	db := testGetDB("?date_range=CLAMP")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}()
+
+	var aTime time.Time
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = db.QueryRowContext(ctx, "select cast (:1 as TIMESTAMP(9)) from dual", time.Date(-9999, 1, 1, 0, 0, 0, 0, time.UTC)).Scan(&aTime)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if !aTime.Equal(minTime) {
+		t.Errorf("clamp - expected: %v - received: %v", minTime, aTime)
+	}
+}
+
+// TestSelectTimeRoundTrip checks round-tripped times compare equal with ==
+func TestSelectTimeRoundTrip(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	// time.Now has a monotonic clock reading, that is stripped when bound
+	now := time.Now()
+	_, offset := now.Zone()
+	expected := normalizeTime(now.In(timezoneToLocation(int64(offset/3600), int64(offset%3600/60))), time.UTC)
+
+	var aTime time.Time
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	err := TestDB.QueryRowContext(ctx, "select cast (:1 as TIMESTAMP(9) WITH TIME ZONE) from dual", now).Scan(&aTime)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if aTime != expected {
+		t.Errorf("round trip - expected: %v - received: %v", expected, aTime)
+	}
+	if !aTime.Equal(now) {
+		t.Errorf("round trip equal - expected: %v - received: %v", now, aTime)
+	}
+
+	utc := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select cast (:1 as TIMESTAMP(9) WITH TIME ZONE) from dual", utc).Scan(&aTime)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if aTime != utc {
+		t.Errorf("round trip UTC - expected: %v - received: %v", utc, aTime)
+	}
+}
+
+// TestTimeOutBinds checks time OUT and IN OUT binds
+func TestTimeOutBinds(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	in := time.Date(2020, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	var out time.Time
+	_, err := TestDB.ExecContext(ctx, "begin :1 := :2 + interval '1' day; end;", sql.Out{Dest: &out}, in)
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if !out.Equal(in.AddDate(0, 0, 1)) {
+		t.Errorf("out - expected: %v - received: %v", in.AddDate(0, 0, 1), out)
+	}
+
+	inOut := in
+	_, err = TestDB.ExecContext(ctx, "begin :1 := :1 + interval '2' hour; end;", sql.Out{Dest: &inOut, In: true})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if !inOut.Equal(in.Add(2 * time.Hour)) {
+		t.Errorf("in out - expected: %v - received: %v", in.Add(2*time.Hour), inOut)
+	}
+
+	nullTime := sql.NullTime{Time: in, Valid: true}
+	_, err = TestDB.ExecContext(ctx, "begin :1 := null; end;", sql.Out{Dest: &nullTime})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if nullTime.Valid || !nullTime.Time.IsZero() {
+		t.Errorf("null time - expected: invalid - received: %v", nullTime)
+	}
+
+	nullTime = sql.NullTime{}
+	_, err = TestDB.ExecContext(ctx, "begin :1 := nvl(:1, to_timestamp_tz('2021-03-04 05:06:07 +00:00', 'YYYY-MM-DD HH24:MI:SS TZH:TZM')); end;",
+		sql.Out{Dest: &nullTime, In: true})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	expected := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	if !nullTime.Valid || !nullTime.Time.Equal(expected) {
+		t.Errorf("null time in out - expected: %v - received: %v", expected, nullTime)
+	}
+}
+
+// TestSelectTimeZone checks TIMESTAMP WITH LOCAL TIME ZONE values with the time_zone and ltz_loc parameters
+func TestSelectTimeZone(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?time_zone=%2B05:00&ltz_loc=UTC")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	var sessionTimeZone string
+	var text string
+	var aTime time.Time
+	err := db.QueryRowContext(ctx, "select sessiontimezone, to_char(cast(:1 as TIMESTAMP WITH LOCAL TIME ZONE), 'HH24:MI'), cast(:2 as TIMESTAMP WITH LOCAL TIME ZONE) from dual",
+		time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)).Scan(&sessionTimeZone, &text, &aTime)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+
+	if sessionTimeZone != "+05:00" {
+		t.Errorf("session time zone - expected: %v - received: %v", "+05:00", sessionTimeZone)
+	}
+	if text != "08:04" {
+		t.Errorf("text - expected: %v - received: %v", "08:04", text)
+	}
+	expected := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if aTime != expected {
+		t.Errorf("time - expected: %v - received: %v", expected, aTime)
+	}
+}
@@ -0,0 +1,127 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"strings"
+	"unsafe"
+)
+
+const (
+	// ddlInTxAllow executes DDL in a transaction without a warning, the default
+	ddlInTxAllow = iota
+	// ddlInTxWarn logs a warning and calls the ImplicitCommit hook, then executes the DDL
+	ddlInTxWarn
+	// ddlInTxError returns an *ImplicitCommitError without executing the DDL
+	ddlInTxError
+)
+
+// ddlKeywords are the first keywords of the DDL statements without an OCI statement type
+// that Oracle commits the open transaction before
+var ddlKeywords = map[string]bool{
+	"ANALYZE":      true,
+	"ASSOCIATE":    true,
+	"AUDIT":        true,
+	"COMMENT":      true,
+	"DISASSOCIATE": true,
+	"FLASHBACK":    true,
+	"GRANT":        true,
+	"NOAUDIT":      true,
+	"PURGE":        true,
+	"RENAME":       true,
+	"REVOKE":       true,
+	"TRUNCATE":     true,
+}
+
+// ddlStatementType returns the statement type, like CREATE, when the statement is DDL that commits implicitly, otherwise empty.
+// statementType is the OCI statement type of the prepared statement, see statementTypeName, which identifies CREATE, DROP, and ALTER.
+// ALTER SESSION and ALTER SYSTEM have the ALTER statement type, but do not commit and are not DDL.
+// The other DDL statements, like TRUNCATE and GRANT, have no OCI statement type and are identified by the first keyword of the query.
+func ddlStatementType(statementType string, query string) string {
+	switch statementType {
+	case "CREATE", "DROP":
+		return statementType
+	case "ALTER":
+		keyword, rest := nextKeyword(query)
+		if keyword == "ALTER" {
+			switch next, _ := nextKeyword(rest); next {
+			case "SESSION", "SYSTEM":
+				return ""
+			}
+		}
+		return statementType
+	case "":
+		keyword, _ := nextKeyword(query)
+		if ddlKeywords[keyword] {
+			return keyword
+		}
+	}
+	return ""
+}
+
+// nextKeyword returns the next word of the query in upper case, after white space and comments, and the query after the word
+func nextKeyword(query string) (string, string) {
+	for {
+		query = strings.TrimLeft(query, " \t\r\n")
+		switch {
+		case strings.HasPrefix(query, "--"):
+			index := strings.IndexByte(query, '\n')
+			if index < 0 {
+				return "", ""
+			}
+			query = query[index+1:]
+		case strings.HasPrefix(query, "/*"):
+			index := strings.Index(query[2:], "*/")
+			if index < 0 {
+				return "", ""
+			}
+			query = query[index+4:]
+		default:
+			end := 0
+			for end < len(query) && (query[end] >= 'a' && query[end] <= 'z' || query[end] >= 'A' && query[end] <= 'Z') {
+				end++
+			}
+			return strings.ToUpper(query[:end]), query[end:]
+		}
+	}
+}
+
+// checkImplicitCommit checks for DDL executed in a transaction, which commits the transaction implicitly, with the ddl_in_tx DSN parameter.
+// The statement is recognized by its OCI statement type, like the read only check.
+// Returns an *ImplicitCommitError when the DDL must not be executed.
+func (stmt *Stmt) checkImplicitCommit() error {
+	if stmt.conn.ddlInTx == ddlInTxAllow || !stmt.conn.inTransaction {
+		return nil
+	}
+
+	var stmtType C.ub2
+	_, err := stmt.ociAttrGet(unsafe.Pointer(&stmtType), C.OCI_ATTR_STMT_TYPE)
+	if err != nil {
+		return err
+	}
+	return stmt.conn.checkImplicitCommit(stmt.ctx, statementTypeName(stmtType), stmt.queryText)
+}
+
+// checkImplicitCommit checks the statement of the OCI statement type and query is DDL executed in a transaction,
+// see Stmt.checkImplicitCommit. Returns an *ImplicitCommitError when the DDL must not be executed.
+func (conn *Conn) checkImplicitCommit(ctx context.Context, statementType string, query string) error {
+	if conn.ddlInTx == ddlInTxAllow || !conn.inTransaction {
+		return nil
+	}
+	statementType = ddlStatementType(statementType, query)
+	if statementType == "" {
+		return nil
+	}
+
+	implicitCommitError := &ImplicitCommitError{StatementType: statementType, Query: conn.hooks.QueryText.Apply(query)}
+	if conn.ddlInTx == ddlInTxError {
+		return implicitCommitError
+	}
+	conn.logger.Print("warning: ", implicitCommitError.Error())
+	if conn.hooks.ImplicitCommit != nil {
+		conn.hooks.ImplicitCommit(ctx, implicitCommitError)
+	}
+	return nil
+}
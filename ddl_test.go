@@ -0,0 +1,132 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+)
+
+// TestDDLStatementType tests recognizing DDL that commits implicitly from the statement type and text
+func TestDDLStatementType(t *testing.T) {
+	t.Parallel()
+
+	var ddlTests = []struct {
+		statementType string
+		query         string
+		expected      string
+	}{
+		{"CREATE", "create table t (a number)", "CREATE"},
+		{"DROP", "  \n\tDROP TABLE t", "DROP"},
+		{"", "-- comment\n/* block\ncomment */ truncate table t", "TRUNCATE"},
+		{"ALTER", "/*+ hint */alter table t add (b number)", "ALTER"},
+		{"", "grant select on t to public", "GRANT"},
+		{"", "comment on table t is 'a'", "COMMENT"},
+		{"ALTER", "alter session set nls_date_format = 'YYYY'", ""},
+		{"ALTER", "ALTER /* c */ SYSTEM flush shared_pool", ""},
+		{"SELECT", "select * from create_table", ""},
+		{"INSERT", "insert into t values (1)", ""},
+		{"BEGIN", "begin execute immediate 'create table t (a number)'; end;", ""},
+		{"", "lock table t in exclusive mode", ""},
+		{"", "-- only a comment", ""},
+		{"", "/* unterminated", ""},
+		{"", "", ""},
+	}
+	for _, tt := range ddlTests {
+		statementType := ddlStatementType(tt.statementType, tt.query)
+		if statementType != tt.expected {
+			t.Errorf("ddlStatementType(%q, %q) - expected: %q - received: %q", tt.statementType, tt.query, tt.expected, statementType)
+		}
+	}
+
+	var warnings []*ImplicitCommitError
+	conn := &Conn{logger: log.New(ioutil.Discard, "", 0), inTransaction: true, ddlInTx: ddlInTxWarn}
+	conn.hooks.ImplicitCommit = func(ctx context.Context, warning *ImplicitCommitError) {
+		warnings = append(warnings, warning)
+	}
+	err := conn.checkImplicitCommit(context.Background(), "", "truncate table t")
+	if err != nil || len(warnings) != 1 || warnings[0].StatementType != "TRUNCATE" {
+		t.Errorf("checkImplicitCommit WARN - expected: nil, 1 TRUNCATE warning - received: %v, %v", err, warnings)
+	}
+
+	conn.ddlInTx = ddlInTxError
+	err = conn.checkImplicitCommit(context.Background(), "CREATE", "create table t (a number)")
+	var implicitCommitError *ImplicitCommitError
+	if !errors.As(err, &implicitCommitError) || implicitCommitError.StatementType != "CREATE" {
+		t.Errorf("checkImplicitCommit ERROR - expected: CREATE ImplicitCommitError - received: %v", err)
+	}
+	err = conn.checkImplicitCommit(context.Background(), "INSERT", "insert into t values (1)")
+	if err != nil {
+		t.Errorf("checkImplicitCommit insert - expected: nil - received: %v", err)
+	}
+
+	conn.inTransaction = false
+	err = conn.checkImplicitCommit(context.Background(), "CREATE", "create table t (a number)")
+	if err != nil || len(warnings) != 1 {
+		t.Errorf("checkImplicitCommit outside a transaction - expected: nil, 1 warning - received: %v, %v", err, len(warnings))
+	}
+}
+
+// TestDestructiveDDLInTx checks DDL in a transaction is refused with ddl_in_tx=ERROR, and the transaction stays open
+func TestDestructiveDDLInTx(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "DDL_IN_TX_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	db := testGetDB("?ddl_in_tx=ERROR")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("begin error:", err)
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "insert into "+tableName+" ( A ) values ( 1 )")
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "truncate table "+tableName)
+	var implicitCommitError *ImplicitCommitError
+	if !errors.As(err, &implicitCommitError) || implicitCommitError.StatementType != "TRUNCATE" {
+		t.Fatalf("truncate - expected: TRUNCATE ImplicitCommitError - received: %v", err)
+	}
+
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal("rollback error:", err)
+	}
+	var count int64
+	err = db.QueryRowContext(ctx, "select count(1) from "+tableName).Scan(&count)
+	if err != nil {
+		t.Fatal("count error:", err)
+	}
+	if count != 0 {
+		t.Errorf("count - expected: 0 - received: %v", count)
+	}
+
+	// outside a transaction the DDL is executed
+	_, err = db.ExecContext(ctx, "truncate table "+tableName)
+	if err != nil {
+		t.Error("truncate error:", err)
+	}
+}
@@ -0,0 +1,357 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unsafe"
+)
+
+type (
+	// plsqlCall is a PL/SQL block that is a single procedure or function call
+	plsqlCall struct {
+		name  string      // name of the subprogram, like schema.package.procedure
+		binds []plsqlBind // binds in the order they appear in the block
+	}
+
+	// plsqlBind is a bind passed directly as an argument of a PL/SQL call
+	plsqlBind struct {
+		name      string // upper case bind name without the colon
+		position  int    // argument position, 0 is the function return value
+		parameter string // upper case parameter name when using named notation
+		argument  plsqlArgument
+	}
+
+	// plsqlArgument is the describe information of a subprogram argument
+	plsqlArgument struct {
+		name     string
+		position int
+		dataType C.ub2 // the Oracle type code of the argument, like SQLT_NUM or SQLT_CLOB
+		dataSize C.ub2
+		ioMode   C.ub4 // OCI_TYPEPARAM_IN, OCI_TYPEPARAM_OUT, or OCI_TYPEPARAM_INOUT
+	}
+)
+
+// ErrPlsqlBooleanUnsupported is returned when a bool is bound to a PL/SQL BOOLEAN argument with a client library older than 12c,
+// which can not bind BOOLEAN
+var ErrPlsqlBooleanUnsupported = errors.New("binding PL/SQL BOOLEAN needs a 12c or later client library")
+
+var (
+	plsqlCallRegexp     = regexp.MustCompile(`(?is)^\s*begin\s+(?::(\w+)\s*:=\s*)?([a-z][\w$#]*(?:\s*\.\s*[a-z][\w$#]*){0,2})\s*(?:\((.*)\))?\s*;\s*end\s*;?\s*$`)
+	plsqlBindRegexp     = regexp.MustCompile(`^:(\w+)$`)
+	plsqlNamedBindRegex = regexp.MustCompile(`(?i)^([a-z][\w$#]*)\s*=>\s*:(\w+)$`)
+)
+
+// parsePlsqlCall parses a PL/SQL block of the form: begin [:result :=] name[(arguments)]; end;
+// Returns false if the block is not a single call.
+func parsePlsqlCall(query string) (*plsqlCall, bool) {
+	matches := plsqlCallRegexp.FindStringSubmatch(query)
+	if matches == nil {
+		return nil, false
+	}
+
+	call := &plsqlCall{
+		name: strings.ToUpper(strings.Join(strings.Fields(matches[2]), "")),
+	}
+	if matches[1] != "" {
+		call.binds = append(call.binds, plsqlBind{name: strings.ToUpper(matches[1]), position: 0})
+	}
+
+	if strings.TrimSpace(matches[3]) == "" {
+		return call, true
+	}
+
+	arguments, ok := splitPlsqlArguments(matches[3])
+	if !ok {
+		return nil, false
+	}
+	for i, argument := range arguments {
+		argument = strings.TrimSpace(argument)
+		if bindMatches := plsqlBindRegexp.FindStringSubmatch(argument); bindMatches != nil {
+			call.binds = append(call.binds, plsqlBind{name: strings.ToUpper(bindMatches[1]), position: i + 1})
+		} else if bindMatches := plsqlNamedBindRegex.FindStringSubmatch(argument); bindMatches != nil {
+			call.binds = append(call.binds, plsqlBind{name: strings.ToUpper(bindMatches[2]), position: i + 1, parameter: strings.ToUpper(bindMatches[1])})
+		} else if strings.Contains(argument, ":") {
+			// a bind inside an expression, the argument type does not say anything about the bind type
+			return nil, false
+		}
+	}
+
+	return call, true
+}
+
+// splitPlsqlArguments splits the arguments of a call on commas that are not inside parentheses or quotes
+func splitPlsqlArguments(text string) ([]string, bool) {
+	var arguments []string
+	depth := 0
+	quoted := false
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\'':
+			quoted = !quoted
+		case quoted:
+		case text[i] == '(':
+			depth++
+		case text[i] == ')':
+			depth--
+			if depth < 0 {
+				return nil, false
+			}
+		case text[i] == ',' && depth == 0:
+			arguments = append(arguments, text[start:i])
+			start = i + 1
+		}
+	}
+	if quoted || depth != 0 {
+		return nil, false
+	}
+	return append(arguments, text[start:]), true
+}
+
+// plsqlBinds returns the binds of the statement with the describe information of the arguments they are passed to.
+// Returns nil if the statement is not a PL/SQL block with a single call or the call can not be described.
+// The result is cached in the statement.
+func (stmt *Stmt) plsqlBinds() []plsqlBind {
+	if stmt.plsqlDescribed {
+		return stmt.plsqlCallBinds
+	}
+	stmt.plsqlDescribed = true
+
+	var stmtType C.ub2
+	_, err := stmt.ociAttrGet(unsafe.Pointer(&stmtType), C.OCI_ATTR_STMT_TYPE)
+	if err != nil || stmtType != C.OCI_STMT_BEGIN {
+		return nil
+	}
+
+	call, ok := parsePlsqlCall(stmt.queryText)
+	if !ok || len(call.binds) < 1 {
+		return nil
+	}
+
+	arguments, err := stmt.conn.describePlsqlArguments(call.name)
+	if err != nil {
+		return nil
+	}
+
+	for i := range call.binds {
+		for _, argument := range arguments {
+			if (call.binds[i].parameter == "" && argument.position == call.binds[i].position) ||
+				(call.binds[i].parameter != "" && argument.name == call.binds[i].parameter) {
+				call.binds[i].argument = argument
+				break
+			}
+		}
+	}
+
+	stmt.plsqlCallBinds = call.binds
+	return stmt.plsqlCallBinds
+}
+
+// plsqlArgumentFor returns the describe information of the argument a bind is passed to, the zero value if unknown.
+// Named binds are matched by name, positional binds by the order the binds appear in the block.
+func plsqlArgumentFor(binds []plsqlBind, index int, name string) plsqlArgument {
+	if name != "" {
+		name = strings.ToUpper(name)
+		for _, bind := range binds {
+			if bind.name == name {
+				return bind.argument
+			}
+		}
+		return plsqlArgument{}
+	}
+	if index < len(binds) {
+		return binds[index].argument
+	}
+	return plsqlArgument{}
+}
+
+// describePlsqlArguments describes the arguments of a procedure or function, which can be in a package.
+// Overloaded package subprograms return an error because the arguments depend on the overload used.
+func (conn *Conn) describePlsqlArguments(name string) ([]plsqlArgument, error) {
+	describeP, _, err := conn.ociHandleAlloc(C.OCI_HTYPE_DESCRIBE, 0)
+	if err != nil {
+		return nil, err
+	}
+	describe := (*C.OCIDescribe)(*describeP)
+	defer ociHandleFree(unsafe.Pointer(describe), C.OCI_HTYPE_DESCRIBE)
+
+	param, paramType, err := conn.ociDescribeAny(describe, name)
+	if err == nil && (paramType == C.OCI_PTYPE_PROC || paramType == C.OCI_PTYPE_FUNC) {
+		return conn.plsqlArguments(param, paramType)
+	}
+
+	// could be package.subprogram or schema.package.subprogram
+	index := strings.LastIndexByte(name, '.')
+	if index < 0 {
+		if err == nil {
+			err = errors.New("not a procedure or function")
+		}
+		return nil, err
+	}
+
+	param, paramType, err = conn.ociDescribeAny(describe, name[:index])
+	if err != nil {
+		return nil, err
+	}
+	if paramType != C.OCI_PTYPE_PKG {
+		return nil, errors.New("not a package")
+	}
+
+	var subprograms *C.OCIParam
+	_, err = conn.ociAttrGet(param, unsafe.Pointer(&subprograms), C.OCI_ATTR_LIST_SUBPROGRAMS)
+	if err != nil {
+		return nil, err
+	}
+	var count C.ub2
+	_, err = conn.ociAttrGet(subprograms, unsafe.Pointer(&count), C.OCI_ATTR_NUM_PARAMS)
+	if err != nil {
+		return nil, err
+	}
+
+	subprogramName := name[index+1:]
+	var found *C.OCIParam
+	var foundType C.ub1
+	for i := C.ub4(0); i < C.ub4(count); i++ {
+		var subprogram *C.OCIParam
+		result := C.OCIParamGet(unsafe.Pointer(subprograms), C.OCI_DTYPE_PARAM, conn.errHandle, (*unsafe.Pointer)(unsafe.Pointer(&subprogram)), i)
+		err = conn.getError(result)
+		if err != nil {
+			return nil, err
+		}
+
+		var namePointer *C.OraText
+		var size C.ub4
+		size, err = conn.ociAttrGet(subprogram, unsafe.Pointer(&namePointer), C.OCI_ATTR_NAME)
+		if err != nil {
+			return nil, err
+		}
+		if conn.goText(namePointer, int(size)) != subprogramName {
+			continue
+		}
+		if found != nil {
+			return nil, errors.New("overloaded subprogram")
+		}
+		found = subprogram
+		_, err = conn.ociAttrGet(subprogram, unsafe.Pointer(&foundType), C.OCI_ATTR_PTYPE)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if found == nil {
+		return nil, errors.New("subprogram not found")
+	}
+
+	return conn.plsqlArguments(found, foundType)
+}
+
+// ociDescribeAny calls OCIDescribeAny for a named object then returns the object parameter and its type
+func (conn *Conn) ociDescribeAny(describe *C.OCIDescribe, name string) (*C.OCIParam, C.ub1, error) {
+	nameP, nameLength := cText(name, conn.utf16)
+	defer C.free(unsafe.Pointer(nameP))
+
+	result := C.OCIDescribeAny(
+		conn.svc,              // service context handle
+		conn.errHandle,        // error handle
+		unsafe.Pointer(nameP), // the name of the object
+		nameLength,            // length of the name
+		C.OCI_OTYPE_NAME,      // objptr is the name of the object
+		C.OCI_DEFAULT,         // info level, reserved
+		C.OCI_PTYPE_UNK,       // type of the object is unknown
+		describe,              // describe handle that is populated with the describe information
+	)
+	err := conn.getError(result)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var param *C.OCIParam
+	result = C.OCIAttrGet(
+		unsafe.Pointer(describe), // describe handle
+		C.OCI_HTYPE_DESCRIBE,     // handle type
+		unsafe.Pointer(&param),   // returns the parameter of the object
+		nil,                      // size not needed
+		C.OCI_ATTR_PARAM,         // attribute type
+		conn.errHandle,           // error handle
+	)
+	err = conn.getError(result)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var paramType C.ub1
+	_, err = conn.ociAttrGet(param, unsafe.Pointer(&paramType), C.OCI_ATTR_PTYPE)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return param, paramType, nil
+}
+
+// plsqlArguments returns the top level arguments of a procedure or function parameter.
+// For functions, position 0 is the return value.
+func (conn *Conn) plsqlArguments(param *C.OCIParam, paramType C.ub1) ([]plsqlArgument, error) {
+	var list *C.OCIParam
+	_, err := conn.ociAttrGet(param, unsafe.Pointer(&list), C.OCI_ATTR_LIST_ARGUMENTS)
+	if err != nil {
+		return nil, err
+	}
+	var count C.ub2
+	_, err = conn.ociAttrGet(list, unsafe.Pointer(&count), C.OCI_ATTR_NUM_PARAMS)
+	if err != nil {
+		return nil, err
+	}
+
+	// procedure arguments start at position 1, function arguments start with the return value at position 0
+	start := C.ub4(1)
+	if paramType == C.OCI_PTYPE_FUNC {
+		start = 0
+	}
+
+	arguments := make([]plsqlArgument, 0, count)
+	for position := start; position < start+C.ub4(count); position++ {
+		var argumentParam *C.OCIParam
+		result := C.OCIParamGet(unsafe.Pointer(list), C.OCI_DTYPE_PARAM, conn.errHandle, (*unsafe.Pointer)(unsafe.Pointer(&argumentParam)), position)
+		err = conn.getError(result)
+		if err != nil {
+			return nil, err
+		}
+
+		var level C.ub2
+		_, err = conn.ociAttrGet(argumentParam, unsafe.Pointer(&level), C.OCI_ATTR_LEVEL)
+		if err != nil {
+			return nil, err
+		}
+		if level != 0 {
+			continue
+		}
+
+		argument := plsqlArgument{position: int(position)}
+		var namePointer *C.OraText
+		var size C.ub4
+		size, err = conn.ociAttrGet(argumentParam, unsafe.Pointer(&namePointer), C.OCI_ATTR_NAME)
+		if err != nil {
+			return nil, err
+		}
+		argument.name = conn.goText(namePointer, int(size))
+		_, err = conn.ociAttrGet(argumentParam, unsafe.Pointer(&argument.dataType), C.OCI_ATTR_DATA_TYPE)
+		if err != nil {
+			return nil, err
+		}
+		_, err = conn.ociAttrGet(argumentParam, unsafe.Pointer(&argument.dataSize), C.OCI_ATTR_DATA_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		_, err = conn.ociAttrGet(argumentParam, unsafe.Pointer(&argument.ioMode), C.OCI_ATTR_IOMODE)
+		if err != nil {
+			return nil, err
+		}
+
+		arguments = append(arguments, argument)
+	}
+
+	return arguments, nil
+}
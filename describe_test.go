@@ -0,0 +1,176 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPlsqlCallOutBinds(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	procedureName := "P_OUT_BINDS_" + TestTimeString
+	testExecQuery(t, `create or replace procedure `+procedureName+` (p_text out clob, p_number in out number)
+is
+begin
+	p_text := rpad('a', 40000, 'a');
+	p_number := p_number / 4;
+end `+procedureName+`;`, nil)
+	defer testExecQuery(t, "drop procedure "+procedureName, nil)
+
+	functionName := "F_OUT_BINDS_" + TestTimeString
+	testExecQuery(t, `create or replace function `+functionName+` (p_number number) return number
+is
+begin
+	return p_number * 2.5;
+end `+functionName+`;`, nil)
+	defer testExecQuery(t, "drop function "+functionName, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	// alter the session so a numeric character conversion would use a comma
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "alter session set NLS_NUMERIC_CHARACTERS = ',.'")
+	if err != nil {
+		t.Fatal("alter session error:", err)
+	}
+	defer conn.ExecContext(ctx, "alter session set NLS_NUMERIC_CHARACTERS = '.,'")
+
+	var text string
+	number := "1.5"
+	_, err = conn.ExecContext(ctx, "begin "+procedureName+"(:1, :2); end;", sql.Out{Dest: &text}, sql.Out{Dest: &number, In: true})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if len(text) != 40000 {
+		t.Errorf("text - expected length: 40000 - received length: %v", len(text))
+	}
+	if number != "0.375" {
+		t.Errorf("number - expected: 0.375 - received: %v", number)
+	}
+
+	var result sql.NullString
+	_, err = conn.ExecContext(ctx, "begin :result := "+functionName+"(p_number => :value); end;", sql.Named("result", sql.Out{Dest: &result}), sql.Named("value", 3))
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if !result.Valid || result.String != "7.5" {
+		t.Errorf("result - expected: 7.5 - received: %v", result)
+	}
+}
+
+// TestPlsqlBooleanBinds tests binding bool to PL/SQL BOOLEAN IN, IN OUT, and function return arguments
+func TestPlsqlBooleanBinds(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	procedureName := "P_BOOLEAN_" + TestTimeString
+	testExecQuery(t, `create or replace procedure `+procedureName+` (p_in boolean, p_in_out in out boolean, p_text out varchar2)
+is
+begin
+	p_text := case when p_in then 'true' when not p_in then 'false' else 'null' end;
+	p_in_out := not p_in_out;
+end `+procedureName+`;`, nil)
+	defer testExecQuery(t, "drop procedure "+procedureName, nil)
+
+	functionName := "F_BOOLEAN_" + TestTimeString
+	testExecQuery(t, `create or replace function `+functionName+` (p_number number) return boolean
+is
+begin
+	return p_number > 0;
+end `+functionName+`;`, nil)
+	defer testExecQuery(t, "drop function "+functionName, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	for _, in := range []bool{true, false} {
+		inOut := in
+		var text string
+		_, err := TestDB.ExecContext(ctx, "begin "+procedureName+"(:1, :2, :3); end;", in, sql.Out{Dest: &inOut, In: true}, sql.Out{Dest: &text})
+		if err != nil {
+			t.Fatal("exec error:", err)
+		}
+		if text != strconv.FormatBool(in) {
+			t.Errorf("in - expected: %v - received: %v", in, text)
+		}
+		if inOut != !in {
+			t.Errorf("in out - expected: %v - received: %v", !in, inOut)
+		}
+	}
+
+	var result sql.NullBool
+	_, err := TestDB.ExecContext(ctx, "begin :result := "+functionName+"(:value); end;", sql.Named("result", sql.Out{Dest: &result}), sql.Named("value", 1))
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if !result.Valid || !result.Bool {
+		t.Errorf("result - expected: %v - received: %v", sql.NullBool{Bool: true, Valid: true}, result)
+	}
+
+	_, err = TestDB.ExecContext(ctx, "begin :result := "+functionName+"(:value); end;", sql.Named("result", sql.Out{Dest: &result}), sql.Named("value", -1))
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if !result.Valid || result.Bool {
+		t.Errorf("result - expected: %v - received: %v", sql.NullBool{Bool: false, Valid: true}, result)
+	}
+}
+
+// TestParsePlsqlCall tests parsing PL/SQL blocks with a single call
+func TestParsePlsqlCall(t *testing.T) {
+	t.Parallel()
+
+	var plsqlCallTests = []struct {
+		query    string
+		expected *plsqlCall
+	}{
+		{"begin proc; end;", &plsqlCall{name: "PROC"}},
+		{"BEGIN Pkg.Proc(:a, :B); END;", &plsqlCall{name: "PKG.PROC", binds: []plsqlBind{{name: "A", position: 1}, {name: "B", position: 2}}}},
+		{"begin\n\t:ret := schema . pkg.func(1, 'a,b', p_x => :x);\nend;", &plsqlCall{name: "SCHEMA.PKG.FUNC", binds: []plsqlBind{{name: "RET", position: 0}, {name: "X", position: 3, parameter: "P_X"}}}},
+		{"begin proc(nvl(:1, 0), :2); end;", nil},
+		{"begin proc(:1); proc(:2); end;", nil},
+		{"declare x number; begin proc(:1); end;", nil},
+		{"begin proc('a); end;", nil},
+		{"select 1 from dual", nil},
+	}
+
+	for _, tt := range plsqlCallTests {
+		call, ok := parsePlsqlCall(tt.query)
+		if ok != (tt.expected != nil) {
+			t.Errorf("parsePlsqlCall(%q) - expected ok: %v - received: %v", tt.query, tt.expected != nil, ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(call, tt.expected) {
+			t.Errorf("parsePlsqlCall(%q) - expected: %+v - received: %+v", tt.query, tt.expected, call)
+		}
+	}
+
+	binds := []plsqlBind{
+		{name: "A", argument: plsqlArgument{name: "P_A", position: 1}},
+		{name: "B", argument: plsqlArgument{name: "P_B", position: 2}},
+	}
+	if plsqlArgumentFor(binds, 1, "").name != "P_B" {
+		t.Error("plsqlArgumentFor positional expected P_B")
+	}
+	if plsqlArgumentFor(binds, 5, "b").name != "P_B" {
+		t.Error("plsqlArgumentFor named expected P_B")
+	}
+	if plsqlArgumentFor(binds, 2, "").name != "" || plsqlArgumentFor(binds, 0, "c").name != "" || plsqlArgumentFor(nil, 0, "").name != "" {
+		t.Error("plsqlArgumentFor expected zero value for unknown binds")
+	}
+}
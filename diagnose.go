@@ -0,0 +1,221 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// ErrDiagnoseSkipped is the error of the steps of Diagnose not run because an earlier step failed
+var ErrDiagnoseSkipped = errors.New("skipped, an earlier step failed")
+
+// DiagnoseStep is the result of one step of Diagnose
+type DiagnoseStep struct {
+	// Name is the name of the step, like connect
+	Name string
+	// Detail is what the step found, like the client version or the database name, empty when the step failed
+	Detail string
+	// Err is the error of the step, nil when it passed, ErrDiagnoseSkipped when an earlier step failed
+	Err error
+	// Duration is how long the step took
+	Duration time.Duration
+}
+
+// DiagnoseReport is the result of Diagnose, the steps in the order they are run
+type DiagnoseReport struct {
+	// Steps are library, environment, connect, round trip, LOB, and disconnect
+	Steps []DiagnoseStep
+	// Diagnostics are the details of the client environment
+	Diagnostics ConnectDiagnostics
+}
+
+// OK returns true when all the steps passed
+func (report *DiagnoseReport) OK() bool {
+	for _, step := range report.Steps {
+		if step.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns the error of the first step that failed, nil when all the steps passed
+func (report *DiagnoseReport) Err() error {
+	for _, step := range report.Steps {
+		if step.Err != nil {
+			return fmt.Errorf("%v: %v", step.Name, step.Err)
+		}
+	}
+	return nil
+}
+
+// String returns the report with one line per step and a line with the client diagnostics, to send to support
+func (report *DiagnoseReport) String() string {
+	var builder strings.Builder
+	for _, step := range report.Steps {
+		switch {
+		case step.Err == ErrDiagnoseSkipped:
+			builder.WriteString("SKIP " + step.Name)
+		case step.Err != nil:
+			builder.WriteString("FAIL " + step.Name + " (" + step.Duration.String() + "): " + step.Err.Error())
+		default:
+			builder.WriteString("OK   " + step.Name + " (" + step.Duration.String() + "): " + step.Detail)
+		}
+		builder.WriteByte('\n')
+	}
+	builder.WriteString("client: " + report.Diagnostics.String() + "\n")
+	return builder.String()
+}
+
+// Diagnose checks the driver setup step by step with the DSN: loading the client library, creating an environment,
+// connecting, a round trip to the database, creating a temporary LOB, and disconnecting,
+// and returns a report of each step. Steps after a failed step are skipped.
+// Support can ask for the String of the report in place of checking the client setup by hand:
+//
+//	fmt.Print(oci8.Diagnose(ctx, dsn))
+//
+// The connection uses the logger and hooks of the Driver. The password is not included in the report.
+func Diagnose(ctx context.Context, dsnString string) *DiagnoseReport {
+	report := &DiagnoseReport{
+		Diagnostics: clientDiagnostics(os.Getenv, "/proc/self/maps"),
+	}
+
+	var dsn *DSN
+	conn := &Conn{
+		logger: Driver.Logger,
+		hooks:  Driver.Hooks,
+		clock:  Driver.Clock,
+	}
+	connected := false
+
+	steps := []struct {
+		name string
+		run  func() (string, error)
+	}{
+		{name: "library", run: func() (string, error) {
+			detail := "client version " + report.Diagnostics.ClientVersion
+			if report.Diagnostics.LibraryPath != "" {
+				detail += ", " + report.Diagnostics.LibraryPath
+			}
+			return detail, nil
+		}},
+		{name: "environment", run: func() (string, error) {
+			var err error
+			dsn, err = ParseDSN(dsnString)
+			if err != nil {
+				return "", err
+			}
+			return diagnoseEnvironment(dsn)
+		}},
+		{name: "connect", run: func() (string, error) {
+			err := conn.open(dsn)
+			if err != nil {
+				return "", err
+			}
+			connected = true
+			return "connected to " + dsn.Connect + " as " + dsn.Username, nil
+		}},
+		{name: "round trip", run: func() (string, error) {
+			err := conn.Ping(ctx)
+			if err != nil {
+				return "", err
+			}
+			dest := make([]driver.Value, 2)
+			err = conn.queryRow(ctx, "select sys_context('USERENV', 'DB_NAME'), sys_context('USERENV', 'SESSION_USER') from dual", dest)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("database %v, session user %v", dest[0], dest[1]), nil
+		}},
+		{name: "LOB", run: func() (string, error) {
+			lob, err := conn.CreateTempLob(true)
+			if err != nil {
+				return "", err
+			}
+			_, err = lob.WriteString("Diagnose")
+			if err != nil {
+				lob.Close()
+				return "", err
+			}
+			size, err := lob.Size()
+			if err != nil {
+				lob.Close()
+				return "", err
+			}
+			err = lob.Close()
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("temporary CLOB of %v characters created and freed", size), nil
+		}},
+		{name: "disconnect", run: func() (string, error) {
+			connected = false
+			err := conn.CloseContext(ctx)
+			if err != nil {
+				return "", err
+			}
+			return "disconnected", nil
+		}},
+	}
+
+	var failed bool
+	for _, step := range steps {
+		if !failed && ctx.Err() != nil {
+			report.Steps = append(report.Steps, DiagnoseStep{Name: step.name, Err: ctx.Err()})
+			failed = true
+			continue
+		}
+		if failed {
+			report.Steps = append(report.Steps, DiagnoseStep{Name: step.name, Err: ErrDiagnoseSkipped})
+			continue
+		}
+
+		start := conn.now()
+		detail, err := step.run()
+		report.Steps = append(report.Steps, DiagnoseStep{Name: step.name, Detail: detail, Err: err, Duration: conn.since(start)})
+		failed = err != nil
+	}
+
+	if connected {
+		conn.CloseContext(ctx)
+	}
+
+	return report
+}
+
+// diagnoseEnvironment creates and frees an environment with the character set of the DSN, returns the character set name
+func diagnoseEnvironment(dsn *DSN) (string, error) {
+	charset, err := dsn.envCharset()
+	if err != nil {
+		return "", err
+	}
+
+	var env *C.OCIEnv
+	result := C.OCIEnvNlsCreate(
+		&env,           // pointer to a handle to the environment
+		C.OCI_THREADED, // environment mode
+		nil,            // user-defined context for the memory callback routines
+		nil,            // user-defined memory allocation function
+		nil,            // user-defined memory re-allocation function
+		nil,            // user-defined memory free function
+		0,              // amount of user memory to be allocated for the duration of the environment
+		nil,            // pointer to the user memory
+		charset,        // client-side character set, 0 uses the NLS_LANG setting
+		charset,        // client-side national character set, 0 uses the NLS_NCHAR setting
+	)
+	if result != C.OCI_SUCCESS {
+		// usually the client library can not find its files, like the time zone files or message files
+		return "", errors.New("OCIEnvNlsCreate error")
+	}
+	C.OCIHandleFree(unsafe.Pointer(env), C.OCI_HTYPE_ENV)
+
+	return "character set " + dsn.charsetName(os.Getenv), nil
+}
@@ -0,0 +1,67 @@
+package oci8
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDiagnose checks all the Diagnose steps pass with the test database
+func TestDiagnose(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	report := Diagnose(ctx, testGetOpenString(""))
+	if !report.OK() {
+		t.Fatalf("Diagnose - expected: all steps OK - received: %v", report)
+	}
+	if len(report.Steps) != 6 {
+		t.Errorf("steps - expected: %v - received: %v", 6, len(report.Steps))
+	}
+	if report.Err() != nil {
+		t.Errorf("Err - expected: %v - received: %v", nil, report.Err())
+	}
+}
+
+// TestDiagnoseInvalidDSN tests Diagnose reports the failed step and skips the steps after it
+func TestDiagnoseInvalidDSN(t *testing.T) {
+	t.Parallel()
+
+	report := Diagnose(context.Background(), "scott/tiger@dbhost?prefetch_rows=x")
+	names := make([]string, 0, len(report.Steps))
+	for _, step := range report.Steps {
+		names = append(names, step.Name)
+	}
+	expectedNames := []string{"library", "environment", "connect", "round trip", "LOB", "disconnect"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("steps - expected: %v - received: %v", expectedNames, names)
+	}
+
+	if report.Steps[0].Err != nil {
+		t.Errorf("library - expected: %v - received: %v", nil, report.Steps[0].Err)
+	}
+	if report.Steps[1].Err == nil || report.Steps[1].Err == ErrDiagnoseSkipped {
+		t.Errorf("environment - expected: invalid prefetch_rows - received: %v", report.Steps[1].Err)
+	}
+	for _, step := range report.Steps[2:] {
+		if step.Err != ErrDiagnoseSkipped {
+			t.Errorf("%v - expected: %v - received: %v", step.Name, ErrDiagnoseSkipped, step.Err)
+		}
+	}
+	if report.OK() {
+		t.Error("OK - expected: false - received: true")
+	}
+
+	text := report.String()
+	for _, line := range []string{"OK   library", "FAIL environment", "SKIP connect", "tiger"} {
+		if strings.Contains(text, line) != (line != "tiger") {
+			t.Errorf("String contains %v - expected: %v - received: %v", line, line != "tiger", text)
+		}
+	}
+}
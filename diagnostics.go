@@ -0,0 +1,184 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ConnectError is returned when connecting fails with an error that is usually caused by the client setup,
+// like ORA-12154 TNS:could not resolve the connect identifier specified, with diagnostics of the client environment
+type ConnectError struct {
+	// Err is the connect error
+	Err error
+	// Diagnostics are the details of the client environment
+	Diagnostics ConnectDiagnostics
+}
+
+// Error returns the connect error string with the diagnostics
+func (connectError *ConnectError) Error() string {
+	return connectError.Err.Error() + " (" + connectError.Diagnostics.String() + ")"
+}
+
+// Unwrap returns the connect error
+func (connectError *ConnectError) Unwrap() error {
+	return connectError.Err
+}
+
+// ConnectDiagnostics are the details of the client environment that decide how a connect string is resolved
+type ConnectDiagnostics struct {
+	// ClientVersion is the version of the Oracle client library
+	ClientVersion string
+	// LibraryPath is the path of the Oracle client library that was loaded, only found on Linux
+	LibraryPath string
+	// LibraryPathEnv is the environment variable that the loader searches for the library, like LD_LIBRARY_PATH
+	LibraryPathEnv string
+	// LibraryPathValue is the value of LibraryPathEnv
+	LibraryPathValue string
+	// OracleHome is the ORACLE_HOME environment variable
+	OracleHome string
+	// TNSAdmin is the TNS_ADMIN environment variable
+	TNSAdmin string
+	// TNSNames is the tnsnames.ora the client would read, empty when none was found
+	TNSNames string
+}
+
+// String returns the diagnostics as a one line string
+func (connectDiagnostics ConnectDiagnostics) String() string {
+	valueOrNotSet := func(value string) string {
+		if value == "" {
+			return "not set"
+		}
+		return value
+	}
+	libraryPath := connectDiagnostics.LibraryPath
+	if libraryPath == "" {
+		libraryPath = "unknown"
+	}
+	tnsNames := connectDiagnostics.TNSNames
+	if tnsNames == "" {
+		tnsNames = "not found"
+	}
+
+	return "client version " + connectDiagnostics.ClientVersion +
+		", client library " + libraryPath +
+		", " + connectDiagnostics.LibraryPathEnv + " " + valueOrNotSet(connectDiagnostics.LibraryPathValue) +
+		", ORACLE_HOME " + valueOrNotSet(connectDiagnostics.OracleHome) +
+		", TNS_ADMIN " + valueOrNotSet(connectDiagnostics.TNSAdmin) +
+		", tnsnames.ora " + tnsNames
+}
+
+/*
+connect errors that get diagnostics:
+ORA-01804: failure to initialize timezone information
+ORA-12154: TNS:could not resolve the connect identifier specified
+ORA-12162: TNS:net service name is incorrectly specified
+ORA-12538: TNS:no such protocol adapter
+ORA-12541: TNS:no listener
+ORA-12545: Connect failed because target host or object does not exist
+ORA-12547: TNS:lost contact
+ORA-12557: TNS:protocol adapter not loadable
+ORA-12560: TNS:protocol adapter error
+*/
+var connectDiagnosticCodes = []int{1804, 12154, 12162, 12538, 12541, 12545, 12547, 12557, 12560}
+
+// connectError returns a *ConnectError with the client diagnostics when err is a connect error caused by the client setup,
+// otherwise returns err
+func connectError(err error) error {
+	for _, code := range connectDiagnosticCodes {
+		if isOracleError(err, code) {
+			return &ConnectError{Err: err, Diagnostics: clientDiagnostics(os.Getenv, "/proc/self/maps")}
+		}
+	}
+	return err
+}
+
+// clientDiagnostics collects the client environment diagnostics.
+// getenv reads environment variables and mapsPath is the loader memory map file, used on Linux.
+func clientDiagnostics(getenv func(string) string, mapsPath string) ConnectDiagnostics {
+	connectDiagnostics := ConnectDiagnostics{
+		ClientVersion: clientVersion(),
+		OracleHome:    getenv("ORACLE_HOME"),
+		TNSAdmin:      getenv("TNS_ADMIN"),
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		connectDiagnostics.LibraryPathEnv = "PATH"
+	case "darwin":
+		connectDiagnostics.LibraryPathEnv = "DYLD_LIBRARY_PATH"
+	default:
+		connectDiagnostics.LibraryPathEnv = "LD_LIBRARY_PATH"
+	}
+	connectDiagnostics.LibraryPathValue = getenv(connectDiagnostics.LibraryPathEnv)
+
+	if runtime.GOOS == "linux" {
+		connectDiagnostics.LibraryPath = loadedLibraryPath(mapsPath, "libclntsh")
+	}
+
+	// the client looks for tnsnames.ora in TNS_ADMIN, then ORACLE_HOME/network/admin,
+	// then network/admin of the Instant Client library directory
+	var directories []string
+	if connectDiagnostics.TNSAdmin != "" {
+		directories = append(directories, connectDiagnostics.TNSAdmin)
+	}
+	if connectDiagnostics.OracleHome != "" {
+		directories = append(directories, filepath.Join(connectDiagnostics.OracleHome, "network", "admin"))
+	}
+	if connectDiagnostics.LibraryPath != "" {
+		directories = append(directories, filepath.Join(filepath.Dir(connectDiagnostics.LibraryPath), "network", "admin"))
+	}
+	for _, directory := range directories {
+		tnsNames := filepath.Join(directory, "tnsnames.ora")
+		if _, err := os.Stat(tnsNames); err == nil {
+			connectDiagnostics.TNSNames = tnsNames
+			break
+		}
+	}
+
+	return connectDiagnostics
+}
+
+// clientVersion returns the version of the Oracle client library, like 19.3.0.0.0
+func clientVersion() string {
+	var major, minor, update, patch, portUpdate C.sword
+	C.OCIClientVersion(&major, &minor, &update, &patch, &portUpdate)
+	return fmt.Sprintf("%d.%d.%d.%d.%d", major, minor, update, patch, portUpdate)
+}
+
+// clientMajorVersion returns the major version of the Oracle client library, like 19
+func clientMajorVersion() int {
+	var major, minor, update, patch, portUpdate C.sword
+	C.OCIClientVersion(&major, &minor, &update, &patch, &portUpdate)
+	return int(major)
+}
+
+// loadedLibraryPath returns the path of the first mapped file in the loader memory map file that contains name, empty if none
+func loadedLibraryPath(mapsPath string, name string) string {
+	file, err := os.Open(mapsPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// address perms offset dev inode pathname
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+		path := strings.Join(fields[5:], " ")
+		if strings.Contains(filepath.Base(path), name) {
+			return path
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,81 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestConnectDiagnostics tests connect errors get the client diagnostics
+func TestConnectDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	directory, err := ioutil.TempDir("", "oci8")
+	if err != nil {
+		t.Fatal("temp dir error:", err)
+	}
+	defer os.RemoveAll(directory)
+
+	libraryDirectory := filepath.Join(directory, "instantclient")
+	libraryPath := filepath.Join(libraryDirectory, "libclntsh.so.19.1")
+	tnsNames := filepath.Join(libraryDirectory, "network", "admin", "tnsnames.ora")
+	err = os.MkdirAll(filepath.Dir(tnsNames), 0700)
+	if err != nil {
+		t.Fatal("mkdir error:", err)
+	}
+	err = ioutil.WriteFile(tnsNames, nil, 0600)
+	if err != nil {
+		t.Fatal("write file error:", err)
+	}
+	mapsPath := filepath.Join(directory, "maps")
+	maps := "7f0000000000-7f0000001000 r-xp 00000000 08:01 1234 /usr/lib/libc.so.6\n" +
+		"7f0000001000-7f0000002000 r-xp 00000000 08:01 1235 " + libraryPath + "\n"
+	err = ioutil.WriteFile(mapsPath, []byte(maps), 0600)
+	if err != nil {
+		t.Fatal("write file error:", err)
+	}
+
+	environment := map[string]string{"TNS_ADMIN": filepath.Join(directory, "missing")}
+	getenv := func(key string) string { return environment[key] }
+
+	diagnostics := clientDiagnostics(getenv, mapsPath)
+	if diagnostics.ClientVersion == "" || diagnostics.TNSAdmin != environment["TNS_ADMIN"] || diagnostics.OracleHome != "" {
+		t.Errorf("diagnostics - received: %+v", diagnostics)
+	}
+	if runtime.GOOS == "linux" {
+		if diagnostics.LibraryPath != libraryPath {
+			t.Errorf("library path - expected: %v - received: %v", libraryPath, diagnostics.LibraryPath)
+		}
+		// TNS_ADMIN does not have a tnsnames.ora, the Instant Client directory does
+		if diagnostics.TNSNames != tnsNames {
+			t.Errorf("tnsnames.ora - expected: %v - received: %v", tnsNames, diagnostics.TNSNames)
+		}
+		if !strings.Contains(diagnostics.String(), "client library "+libraryPath+", LD_LIBRARY_PATH not set, ORACLE_HOME not set") {
+			t.Errorf("string - received: %v", diagnostics.String())
+		}
+	}
+
+	if loadedLibraryPath(filepath.Join(directory, "missing"), "libclntsh") != "" {
+		t.Error("missing maps file - expected empty library path")
+	}
+
+	tnsErr := errors.New("ORA-12154: TNS:could not resolve the connect identifier specified")
+	err = connectError(tnsErr)
+	var connectErr *ConnectError
+	if !errors.As(err, &connectErr) || !errors.Is(err, tnsErr) || !strings.HasPrefix(err.Error(), tnsErr.Error()+" (client version ") {
+		t.Errorf("connect error - received: %v", err)
+	}
+
+	otherErr := errors.New("ORA-01017: invalid username/password; logon denied")
+	err = connectError(otherErr)
+	if err != otherErr {
+		t.Errorf("other error - expected: %v - received: %v", otherErr, err)
+	}
+}
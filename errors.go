@@ -0,0 +1,396 @@
+package oci8
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-oci8/oracle"
+)
+
+// ErrTooManyRows is returned when fetching a row past the max rows limit of a query
+var ErrTooManyRows = oracle.ErrTooManyRows
+
+// Sentinel Oracle errors, matched by errors.Is for the *Error with their ORA codes:
+//
+//	if errors.Is(err, oci8.ErrUniqueViolation) {
+var (
+	// ErrUniqueViolation is ORA-00001: unique constraint violated
+	ErrUniqueViolation = errors.New("ORA-00001: unique constraint violated")
+	// ErrResourceBusy is ORA-00054: resource busy and acquire with NOWAIT specified or timeout expired,
+	// and ORA-30006: resource busy; acquire with WAIT timeout expired
+	ErrResourceBusy = errors.New("ORA-00054: resource busy")
+	// ErrDeadlock is ORA-00060: deadlock detected while waiting for resource
+	ErrDeadlock = errors.New("ORA-00060: deadlock detected while waiting for resource")
+	// ErrTableNotFound is ORA-00942: table or view does not exist,
+	// also returned by TableColumns when the table does not exist or is not visible to the user
+	ErrTableNotFound = errors.New("ORA-00942: table or view does not exist")
+	// ErrCancelled is ORA-01013: user requested cancel of current operation, returned when the call was interrupted with OCIBreak
+	ErrCancelled = errors.New("ORA-01013: user requested cancel of current operation")
+	// ErrNotNullViolation is ORA-01400: cannot insert NULL, and ORA-01407: cannot update to NULL
+	ErrNotNullViolation = errors.New("ORA-01400: cannot insert NULL")
+	// ErrNoDataFound is ORA-01403: no data found
+	ErrNoDataFound = errors.New("ORA-01403: no data found")
+	// ErrForeignKeyViolation is ORA-02291: integrity constraint violated - parent key not found,
+	// and ORA-02292: integrity constraint violated - child record found
+	ErrForeignKeyViolation = errors.New("ORA-02291: integrity constraint violated")
+	// ErrCheckViolation is ORA-02290: check constraint violated
+	ErrCheckViolation = errors.New("ORA-02290: check constraint violated")
+	// ErrCallTimeout is ORA-03156: OCI call timed out, see call_timeout
+	ErrCallTimeout = errors.New("ORA-03156: OCI call timed out")
+	// ErrValueTooLarge is ORA-12899: value too large for column
+	ErrValueTooLarge = errors.New("ORA-12899: value too large for column")
+)
+
+// errorSentinels are the sentinel errors of the ORA codes
+var errorSentinels = map[int]error{
+	1:     ErrUniqueViolation,
+	54:    ErrResourceBusy,
+	30006: ErrResourceBusy,
+	60:    ErrDeadlock,
+	942:   ErrTableNotFound,
+	1013:  ErrCancelled,
+	1400:  ErrNotNullViolation,
+	1407:  ErrNotNullViolation,
+	1403:  ErrNoDataFound,
+	2290:  ErrCheckViolation,
+	2291:  ErrForeignKeyViolation,
+	2292:  ErrForeignKeyViolation,
+	3156:  ErrCallTimeout,
+	12899: ErrValueTooLarge,
+}
+
+// Error is an Oracle error returned by an OCI call, like ORA-00001: unique constraint (SCOTT.PK_ORDERS) violated.
+// Use errors.As to get it, and errors.Is to match it with the sentinel errors, like ErrUniqueViolation,
+// or with an *Error of the same Code:
+//
+//	var oracleError *oci8.Error
+//	if errors.As(err, &oracleError) && oracleError.Code == 1 {
+type Error struct {
+	// Code is the ORA code, like 1 for ORA-00001
+	Code int
+	// Message is the Oracle error message, including the ORA code
+	Message string
+	// Offset is the zero based offset of the error in the statement text, set for the parse errors of statements
+	Offset int
+	// Snippet is the statement text around Offset, with ^ marking the offset, set for the parse errors of statements
+	Snippet string
+	// Recoverable is true when the error is recoverable, like for Application Continuity,
+	// a call that failed with it can be retried on a new connection. Needs an Oracle 12c or later client.
+	Recoverable bool
+}
+
+// Error returns the Oracle error message, followed by the offset and the snippet of a parse error
+func (oracleError *Error) Error() string {
+	if oracleError.Snippet == "" {
+		return oracleError.Message
+	}
+	return strings.TrimRight(oracleError.Message, " \r\n") + " at offset " + strconv.Itoa(oracleError.Offset) + ": " + oracleError.Snippet
+}
+
+// Is returns true for the sentinel error of the ORA code, and for an *Error with the same Code
+func (oracleError *Error) Is(target error) bool {
+	if targetError, ok := target.(*Error); ok {
+		return targetError.Code == oracleError.Code
+	}
+	sentinel, ok := errorSentinels[oracleError.Code]
+	return ok && sentinel == target
+}
+
+// Unwrap returns the sentinel error of the ORA code, nil when the code has none
+func (oracleError *Error) Unwrap() error {
+	return errorSentinels[oracleError.Code]
+}
+
+// ErrSessionLost is matched by errors.Is for the errors of a pinned session that was lost or replaced,
+// along with the session state that was in it
+var ErrSessionLost = errors.New("pinned session was lost, its session state is gone")
+
+// SessionLostError is returned instead of driver.ErrBadConn by a connection pinned with PinSession,
+// and by PinnedSession.Check when the session was replaced, for example by a transparent application failover.
+// errors.Is matches both ErrSessionLost and driver.ErrBadConn, so database/sql discards the connection.
+type SessionLostError struct {
+	// Reason is why the session is considered lost
+	Reason string
+}
+
+// Error returns the session lost error string
+func (sessionLostError *SessionLostError) Error() string {
+	return ErrSessionLost.Error() + ": " + sessionLostError.Reason
+}
+
+// Is returns true for ErrSessionLost and driver.ErrBadConn
+func (sessionLostError *SessionLostError) Is(target error) bool {
+	return target == ErrSessionLost || target == driver.ErrBadConn
+}
+
+// BindError is returned when a bind parameter can not be bound to a statement
+type BindError = oracle.BindError
+
+// MemoryBudgetError is returned when the buffers needed to fetch a row are larger than the query memory budget
+type MemoryBudgetError struct {
+	// Budget is the memory budget in bytes
+	Budget int64
+	// RowSize is the size in bytes of the buffers needed to fetch a row
+	RowSize int64
+}
+
+// Error returns the memory budget error string
+func (memoryBudgetError *MemoryBudgetError) Error() string {
+	return "query row size of " + strconv.FormatInt(memoryBudgetError.RowSize, 10) +
+		" bytes exceeds memory budget of " + strconv.FormatInt(memoryBudgetError.Budget, 10) + " bytes"
+}
+
+// GroupCommitLostError is returned in place of driver.ErrBadConn, or the bad connection Oracle error,
+// when the connection fails with statements of the group commit not committed yet, which are lost with the session.
+// It does not match driver.ErrBadConn, so database/sql does not retry the call on another connection
+// as if nothing was lost. The connection is still discarded, as its session is marked as lost.
+type GroupCommitLostError struct {
+	// Statements is the number of statements executed and not committed that are lost
+	Statements int
+	// Err is the error of the failed connection
+	Err error
+}
+
+// Error returns the group commit lost error string
+func (groupCommitLostError *GroupCommitLostError) Error() string {
+	return strconv.Itoa(groupCommitLostError.Statements) + " group commit statements lost: " + groupCommitLostError.Err.Error()
+}
+
+// ReadOnlyError is returned when preparing a statement that changes data or the schema on a read only connection
+type ReadOnlyError = oracle.ReadOnlyError
+
+// ImplicitCommitError is DDL executed in a transaction, which Oracle commits before the DDL.
+// It is returned by Exec with ddl_in_tx=ERROR, and passed to the ImplicitCommit hook with ddl_in_tx=WARN.
+type ImplicitCommitError struct {
+	// StatementType is the first keyword of the DDL, like CREATE or TRUNCATE
+	StatementType string
+	// Query is the statement text, shortened by Hooks.QueryText
+	Query string
+}
+
+// Error returns the implicit commit error string
+func (implicitCommitError *ImplicitCommitError) Error() string {
+	return implicitCommitError.StatementType + " statement in a transaction commits the transaction implicitly"
+}
+
+// ErrLockTimeout is matched by errors.Is for the *LockError of a lock request that timed out
+var ErrLockTimeout = errors.New("lock request timed out")
+
+// LockError is returned when DBMS_LOCK.REQUEST or DBMS_LOCK.RELEASE returns a status other than success
+type LockError struct {
+	// Name is the lock name
+	Name string
+	// Operation is request or release
+	Operation string
+	// Status is the DBMS_LOCK status: 1 timeout, 2 deadlock, 3 parameter error, 4 already own or do not own the lock, 5 illegal lock handle
+	Status int
+}
+
+// Error returns the lock error string
+func (lockError *LockError) Error() string {
+	return "lock " + lockError.Name + " " + lockError.Operation + " failed with status " + strconv.Itoa(lockError.Status) + ": " + lockStatusText(lockError.Status)
+}
+
+// Is returns true for ErrLockTimeout when the request timed out
+func (lockError *LockError) Is(target error) bool {
+	return target == ErrLockTimeout && lockError.Status == 1
+}
+
+// lockStatusText returns the description of a DBMS_LOCK status
+func lockStatusText(status int) string {
+	switch status {
+	case 1:
+		return "timeout"
+	case 2:
+		return "deadlock"
+	case 3:
+		return "parameter error"
+	case 4:
+		return "already own or do not own the lock"
+	case 5:
+		return "illegal lock handle"
+	}
+	return "unknown status"
+}
+
+// PasswordExpiryWarning is the ORA-28002 warning that the password of the user will expire soon,
+// passed to the PasswordExpiry hook when connecting. Connecting succeeds, the warning is not returned as an error.
+type PasswordExpiryWarning struct {
+	// Username is the user of the connection, empty for external credentials
+	Username string
+	// Days is the number of days until the password expires, -1 when the message has no number of days
+	Days int
+	// Message is the Oracle warning message
+	Message string
+}
+
+// Error returns the Oracle warning message
+func (passwordExpiryWarning *PasswordExpiryWarning) Error() string {
+	return passwordExpiryWarning.Message
+}
+
+// badConnErrors are the Oracle errors after which the connection can not be used, by ORA code.
+// The session is marked as lost, so the connection is discarded instead of returned to its pool.
+// Only the errors in unsentBadConnErrors are returned as driver.ErrBadConn, the others can happen
+// after the server ran the call, so the Oracle error is returned and database/sql does not run the call again.
+var badConnErrors = map[int]string{
+	28:    "your session has been killed",
+	1012:  "not logged on",
+	1033:  "ORACLE initialization or shutdown in progress",
+	1034:  "ORACLE not available",
+	1041:  "internal error. hostdef extension doesn't exist",
+	1089:  "immediate shutdown or close in progress",
+	1090:  "shutdown in progress",
+	1092:  "ORACLE instance terminated. Disconnection forced",
+	2396:  "exceeded maximum idle time",
+	2399:  "exceeded maximum connect time",
+	3113:  "end-of-file on communication channel",
+	3114:  "not connected to ORACLE",
+	3122:  "attempt to close ORACLE-side window on user side",
+	3135:  "connection lost contact",
+	12153: "TNS:not connected",
+	12157: "TNS:internal network communication error",
+	12528: "TNS:listener: all appropriate instances are blocking new connections",
+	12537: "TNS:connection closed",
+	12547: "TNS:lost contact",
+	12570: "TNS:packet reader failure",
+	12571: "TNS:packet writer failure",
+	12583: "TNS:no reader",
+	25401: "can not continue fetches",
+	25408: "can not safely replay call",
+	27146: "post/wait initialization failed",
+}
+
+// unsentBadConnErrors are the bad connection errors returned when the call did not reach the server, by ORA code.
+// They are returned as driver.ErrBadConn, so database/sql retries the call on a fresh connection.
+var unsentBadConnErrors = map[int]string{
+	1012:  "not logged on",
+	1033:  "ORACLE initialization or shutdown in progress",
+	1034:  "ORACLE not available",
+	3114:  "not connected to ORACLE",
+	12153: "TNS:not connected",
+}
+
+// IsBadConnError returns true when err is a bad connection error: driver.ErrBadConn, a *SessionLostError,
+// or an Oracle error after which the connection can not be used, like ORA-03113: end-of-file on communication channel.
+// The driver returns the bad connection errors that can happen after the server ran the call as the Oracle error,
+// so database/sql does not run the call again, use it to know the connection was lost.
+func IsBadConnError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	code, ok := oracleErrorCode(err)
+	if !ok {
+		return false
+	}
+	_, ok = badConnErrors[code]
+	return ok
+}
+
+// oracleErrorCode returns the ORA code of an Oracle error, or of its translation, ok is false for other errors
+func oracleErrorCode(err error) (int, bool) {
+	var translatedError *TranslatedError
+	if errors.As(err, &translatedError) {
+		err = translatedError.OracleErr
+	}
+	var oracleError *Error
+	if errors.As(err, &oracleError) {
+		return oracleError.Code, true
+	}
+	if err == nil {
+		return 0, false
+	}
+	text := err.Error()
+	if len(text) < 10 || !strings.HasPrefix(text, "ORA-") || text[9] != ':' {
+		return 0, false
+	}
+	code, parseErr := strconv.Atoi(text[4:9])
+	if parseErr != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// isOracleError returns true if err is the Oracle error with the ORA code, or its translation
+func isOracleError(err error, code int) bool {
+	errorCode, ok := oracleErrorCode(err)
+	return ok && errorCode == code
+}
+
+// ErrorTranslations is a registry of translations of Oracle errors by ORA code, like to map ORA-00001
+// to the duplicate key error of an application, so the error policy is in one place instead of at each call.
+// Set it on the Connector or DriverStruct, translations can be registered before or after connections are opened:
+//
+//	translations := &oci8.ErrorTranslations{}
+//	translations.Register(1, func(err error) error { return ErrDuplicateKey })
+//	oci8.Driver.ErrorTranslations = translations
+//
+// The translated error is returned as a *TranslatedError, which errors.Is and errors.As match with the translated error,
+// and its message is the message of the translated error followed by the Oracle error.
+// Bad connection errors are not translated.
+// The zero value has no translations and is safe for concurrent use.
+type ErrorTranslations struct {
+	mutex        sync.RWMutex
+	translations map[int]func(err error) error
+}
+
+// TranslatedError is returned in place of an Oracle error with a translation registered in the ErrorTranslations
+type TranslatedError struct {
+	// Code is the ORA code of the Oracle error, like 1 for ORA-00001
+	Code int
+	// Err is the error returned by the translation
+	Err error
+	// OracleErr is the Oracle error, like ORA-00001: unique constraint (SCOTT.PK_ORDERS) violated
+	OracleErr error
+}
+
+// Register sets the translation of the Oracle errors with the ORA code, replacing the translation set before.
+// translate is called with the Oracle error, when it returns nil the Oracle error is returned as is.
+// A nil translate removes the translation of the code.
+func (errorTranslations *ErrorTranslations) Register(code int, translate func(err error) error) {
+	errorTranslations.mutex.Lock()
+	defer errorTranslations.mutex.Unlock()
+
+	if translate == nil {
+		delete(errorTranslations.translations, code)
+		return
+	}
+	if errorTranslations.translations == nil {
+		errorTranslations.translations = make(map[int]func(err error) error)
+	}
+	errorTranslations.translations[code] = translate
+}
+
+// Translate returns the translation of the Oracle error with the ORA code as a *TranslatedError,
+// or err when the code has no translation. A nil ErrorTranslations has no translations.
+func (errorTranslations *ErrorTranslations) Translate(code int, err error) error {
+	if errorTranslations == nil || err == nil {
+		return err
+	}
+
+	errorTranslations.mutex.RLock()
+	translate := errorTranslations.translations[code]
+	errorTranslations.mutex.RUnlock()
+	if translate == nil {
+		return err
+	}
+
+	translated := translate(err)
+	if translated == nil {
+		return err
+	}
+	return &TranslatedError{Code: code, Err: translated, OracleErr: err}
+}
+
+// Error returns the message of the translated error followed by the Oracle error
+func (translatedError *TranslatedError) Error() string {
+	return translatedError.Err.Error() + ": " + translatedError.OracleErr.Error()
+}
+
+// Unwrap returns the translated error
+func (translatedError *TranslatedError) Unwrap() error {
+	return translatedError.Err
+}
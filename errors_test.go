@@ -0,0 +1,207 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// TestDestructiveErrorTranslations checks Oracle errors are translated with the error translations of the connector
+func TestDestructiveErrorTranslations(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	errDuplicate := errors.New("duplicate key")
+	translations := &ErrorTranslations{}
+	translations.Register(1, func(err error) error { return errDuplicate })
+
+	connector, err := NewConnectorWithOptions(ConnectorDSN(testGetOpenString("")), ConnectorErrorTranslations(translations))
+	if err != nil {
+		t.Fatal("NewConnectorWithOptions error:", err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	tableName := "ERROR_TRANSLATIONS_" + TestTimeString
+	err = testExec(t, "create table "+tableName+" ( A INTEGER primary key )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	_, err = db.ExecContext(ctx, "insert into "+tableName+" ( A ) values ( 1 )")
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+	_, err = db.ExecContext(ctx, "insert into "+tableName+" ( A ) values ( 1 )")
+	if !errors.Is(err, errDuplicate) || !isOracleError(err, 1) {
+		t.Errorf("insert duplicate - expected: %v - received: %v", errDuplicate, err)
+	}
+
+	_, err = db.ExecContext(ctx, "insert into "+tableName+"_NONE ( A ) values ( 1 )")
+	if errors.Is(err, errDuplicate) || !isOracleError(err, 942) {
+		t.Errorf("insert missing table - expected: ORA-00942 - received: %v", err)
+	}
+}
+
+// TestErrorTranslations tests translating Oracle errors by ORA code
+func TestErrorTranslations(t *testing.T) {
+	t.Parallel()
+
+	errDuplicate := errors.New("duplicate key")
+	oracleErr := errors.New("ORA-00001: unique constraint (SCOTT.PK) violated")
+
+	var nilTranslations *ErrorTranslations
+	if err := nilTranslations.Translate(1, oracleErr); err != oracleErr {
+		t.Errorf("nil Translate - expected: %v - received: %v", oracleErr, err)
+	}
+
+	translations := &ErrorTranslations{}
+	if err := translations.Translate(1, oracleErr); err != oracleErr {
+		t.Errorf("zero Translate - expected: %v - received: %v", oracleErr, err)
+	}
+
+	translations.Register(1, func(err error) error { return errDuplicate })
+	translations.Register(2, func(err error) error { return nil })
+
+	err := translations.Translate(1, oracleErr)
+	var translatedError *TranslatedError
+	if !errors.As(err, &translatedError) || translatedError.Code != 1 || translatedError.OracleErr != oracleErr {
+		t.Fatalf("Translate - expected: %v - received: %#v", "*TranslatedError", err)
+	}
+	if !errors.Is(err, errDuplicate) {
+		t.Errorf("errors.Is - expected: %v - received: %v", true, false)
+	}
+	if err.Error() != "duplicate key: ORA-00001: unique constraint (SCOTT.PK) violated" {
+		t.Errorf("Error - expected: %v - received: %v", "duplicate key: ORA-00001: unique constraint (SCOTT.PK) violated", err.Error())
+	}
+	if !isOracleError(err, 1) || !isOracleError(&BindError{Err: err}, 1) {
+		t.Errorf("isOracleError - expected: %v - received: %v", true, false)
+	}
+
+	if err = translations.Translate(2, oracleErr); err != oracleErr {
+		t.Errorf("Translate nil translation - expected: %v - received: %v", oracleErr, err)
+	}
+	if err = translations.Translate(3, oracleErr); err != oracleErr {
+		t.Errorf("Translate not registered - expected: %v - received: %v", oracleErr, err)
+	}
+
+	translations.Register(1, nil)
+	if err = translations.Translate(1, oracleErr); err != oracleErr {
+		t.Errorf("Translate removed - expected: %v - received: %v", oracleErr, err)
+	}
+}
+
+func TestIsBadConnError(t *testing.T) {
+	t.Parallel()
+
+	var badConnTests = []struct {
+		err     error
+		badConn bool
+	}{
+		{driver.ErrBadConn, true},
+		{&SessionLostError{Reason: "ORA-03113"}, true},
+		{errors.New("ORA-03113: end-of-file on communication channel"), true},
+		{errors.New("ORA-12570: TNS:packet reader failure"), true},
+		{errors.New("ORA-00001: unique constraint (SCOTT.PK) violated"), false},
+		{errors.New("ORA-3113"), false},
+		{ErrTooManyRows, false},
+		{nil, false},
+	}
+
+	for _, tt := range badConnTests {
+		if badConn := IsBadConnError(tt.err); badConn != tt.badConn {
+			t.Errorf("IsBadConnError(%v) - expected: %v - received: %v", tt.err, tt.badConn, badConn)
+		}
+	}
+
+	// the errors returned as driver.ErrBadConn are bad connection errors, ORA-03113 can happen after the call ran
+	for code := range unsentBadConnErrors {
+		if _, ok := badConnErrors[code]; !ok {
+			t.Errorf("unsentBadConnErrors %v - expected: in badConnErrors - received: not in badConnErrors", code)
+		}
+	}
+	if _, ok := unsentBadConnErrors[3113]; ok {
+		t.Error("unsentBadConnErrors 3113 - expected: not unsent - received: unsent")
+	}
+
+	code, ok := oracleErrorCode(errors.New("ORA-01012: not logged on"))
+	if !ok || code != 1012 {
+		t.Errorf("oracleErrorCode - expected: %v, %v - received: %v, %v", 1012, true, code, ok)
+	}
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	var err error = &Error{Code: 1, Message: "ORA-00001: unique constraint (SCOTT.PK) violated"}
+	if !errors.Is(err, ErrUniqueViolation) || !errors.Is(&BindError{Err: err}, ErrUniqueViolation) {
+		t.Errorf("errors.Is ErrUniqueViolation - expected: %v - received: %v", true, false)
+	}
+	if errors.Is(err, ErrNoDataFound) {
+		t.Errorf("errors.Is ErrNoDataFound - expected: %v - received: %v", false, true)
+	}
+	if !errors.Is(err, &Error{Code: 1}) || errors.Is(err, &Error{Code: 2}) {
+		t.Errorf("errors.Is *Error - expected: code 1 only")
+	}
+	if errors.Unwrap(err) != ErrUniqueViolation {
+		t.Errorf("Unwrap - expected: %v - received: %v", ErrUniqueViolation, errors.Unwrap(err))
+	}
+	if err.Error() != "ORA-00001: unique constraint (SCOTT.PK) violated" {
+		t.Errorf("Error - expected: %v - received: %v", "ORA-00001: unique constraint (SCOTT.PK) violated", err.Error())
+	}
+	if !isOracleError(err, 1) {
+		t.Errorf("isOracleError - expected: %v - received: %v", true, false)
+	}
+
+	err = &Error{Code: 30006, Message: "ORA-30006: resource busy; acquire with WAIT timeout expired"}
+	if !errors.Is(err, ErrResourceBusy) {
+		t.Errorf("errors.Is ErrResourceBusy - expected: %v - received: %v", true, false)
+	}
+	if errors.Unwrap(&Error{Code: 904}) != nil {
+		t.Errorf("Unwrap ORA-00904 - expected: nil")
+	}
+}
+
+// TestErrorSnippet tests the snippet of the statement text around the offset of a parse error
+func TestErrorSnippet(t *testing.T) {
+	t.Parallel()
+
+	long := "select id, name, description, created, updated, status from orders where id = :1"
+	tests := []struct {
+		text   string
+		offset int
+		want   string
+	}{
+		{text: "select id from", offset: 14, want: "select id from^"},
+		{text: "selct id from dual", offset: 0, want: "^selct id from dual"},
+		{text: "select id,\n\tname orders", offset: 17, want: "select id, name ^orders"},
+		{text: long, offset: 55, want: "..., description, created, updated, status ^from orders where id = :1"},
+		{text: long, offset: 7, want: "select ^id, name, description, created, updated,..."},
+		{text: "select 'ääääääääääääääääääää' x frm dual", offset: 52, want: "...ääääääääääääääääää' x ^frm dual"},
+	}
+	for _, test := range tests {
+		if snippet := errorSnippet(test.text, test.offset); snippet != test.want {
+			t.Errorf("errorSnippet %q %v - expected: %q - received: %q", test.text, test.offset, test.want, snippet)
+		}
+	}
+
+	err := &Error{Code: 923, Message: "ORA-00923: FROM keyword not found where expected\n", Offset: 16, Snippet: "select id, name ^orders"}
+	if err.Error() != "ORA-00923: FROM keyword not found where expected at offset 16: select id, name ^orders" {
+		t.Errorf("Error - expected: %v - received: %v", "ORA-00923: FROM keyword not found where expected at offset 16: select id, name ^orders", err.Error())
+	}
+	if !isParseError(err) || !isParseError(&Error{Code: 1756}) || isParseError(&Error{Code: 1}) {
+		t.Errorf("isParseError - expected: parse errors only")
+	}
+}
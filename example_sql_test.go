@@ -816,3 +816,134 @@ func Example_sqlRowid() {
 
 	// output: done
 }
+
+func Example_sqlPipelinedFunction() {
+	// Example shows how to stream the rows of chained pipelined table functions
+
+	// For testing, check if database tests are disabled
+	if oci8.TestDisableDatabase || oci8.TestDisableDestructive {
+		fmt.Println(30)
+		return
+	}
+
+	oci8.Driver.Logger = log.New(os.Stderr, "oci8 ", log.Ldate|log.Ltime|log.LUTC|log.Lshortfile)
+
+	var openString string
+	// [username/[password]@]host[:port][/service_name][?param1=value1&...&paramN=valueN]
+	if len(oci8.TestUsername) > 0 {
+		if len(oci8.TestPassword) > 0 {
+			openString = oci8.TestUsername + "/" + oci8.TestPassword + "@"
+		} else {
+			openString = oci8.TestUsername + "@"
+		}
+	}
+	openString += oci8.TestHostValid
+
+	// Rows of pipelined functions are sent to the client as they are piped.
+	// prefetch_rows sets how many rows are fetched per round trip,
+	// a higher number is faster while a lower number returns the first rows sooner.
+	// A normal simple Open to localhost would look like:
+	// db, err := sql.Open("oci8", "127.0.0.1?prefetch_rows=500")
+	// For testing, need to use additional variables
+	db, err := sql.Open("oci8", openString+"?prefetch_rows=500")
+	if err != nil {
+		fmt.Printf("Open error is not nil: %v", err)
+		return
+	}
+	if db == nil {
+		fmt.Println("db is nil")
+		return
+	}
+
+	// defer close database
+	defer func() {
+		err = db.Close()
+		if err != nil {
+			fmt.Println("Close error is not nil:", err)
+		}
+	}()
+
+	// create type and functions
+	typeName := "E_T_NUMBERS_" + oci8.TestTimeString
+	doubleName := "E_F_DOUBLE_" + oci8.TestTimeString
+	queries := []string{
+		"create or replace type " + typeName + " as table of number",
+		// The cursor passed to a chained function is closed by the function, also when the consumer stops
+		// reading early, which raises NO_DATA_NEEDED in the function. Otherwise the cursor leaks until the session ends.
+		`create or replace function ` + doubleName + `(p_cursor SYS_REFCURSOR) return ` + typeName + ` pipelined
+	is
+		l_number number;
+	begin
+		loop
+			fetch p_cursor into l_number;
+			exit when p_cursor%notfound;
+			pipe row(l_number * 2);
+		end loop;
+		close p_cursor;
+		return;
+	exception
+		when no_data_needed then
+			close p_cursor;
+	end ` + doubleName + `;`,
+	}
+	for _, query := range queries {
+		ctx, cancel := context.WithTimeout(context.Background(), 55*time.Second)
+		_, err = db.ExecContext(ctx, query)
+		cancel()
+		if err != nil {
+			fmt.Println("ExecContext error is not nil:", err)
+			return
+		}
+	}
+
+	// defer drop functions and type
+	defer func() {
+		for _, query := range []string{"drop function " + doubleName, "drop type " + typeName} {
+			ctx, cancel := context.WithTimeout(context.Background(), 55*time.Second)
+			_, err := db.ExecContext(ctx, query)
+			cancel()
+			if err != nil {
+				fmt.Println("ExecContext error is not nil:", err)
+			}
+		}
+	}()
+
+	var rows *sql.Rows
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Second)
+	defer cancel()
+	// The list is bound as a SYS.ODCINUMBERLIST collection, which table(:1) turns into rows
+	rows, err = db.QueryContext(ctx, "select column_value from table("+doubleName+"(cursor(select column_value from table(:1))))", oci8.Int64List{1, 2, 3, 4, 5})
+	if err != nil {
+		fmt.Println("QueryContext error is not nil:", err)
+		return
+	}
+
+	// defer close rows, closing the rows early stops the pipelined functions
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			fmt.Println("Close error is not nil:", err)
+		}
+	}()
+
+	var sum int64
+	for rows.Next() {
+		var number int64
+		err = rows.Scan(&number)
+		if err != nil {
+			fmt.Println("Scan error is not nil:", err)
+			return
+		}
+		sum += number
+	}
+
+	err = rows.Err()
+	if err != nil {
+		fmt.Println("Err error is not nil:", err)
+		return
+	}
+
+	fmt.Println(sum)
+
+	// output: 30
+}
@@ -0,0 +1,254 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+type (
+	// ExportOptions are the options of ExportTable
+	ExportOptions struct {
+		// Columns are the columns to select, all columns when empty. Each must be a valid unquoted identifier.
+		Columns []string
+		// Where is an optional condition added to the select of each partition, without the where keyword
+		Where string
+		// Args are the bind values of Where
+		Args []interface{}
+		// Parallel is the max number of partitions selected at the same time, each on its own connection, defaults to 4
+		Parallel int
+		// BatchSize is the max number of rows in an ExportBatch, defaults to 1000
+		BatchSize int
+	}
+
+	// ExportBatch is a batch of rows of a partition sent by ExportTable
+	ExportBatch struct {
+		// Partition is the partition name, empty for a table that is not partitioned
+		Partition string
+		// Columns are the column names
+		Columns []string
+		// Rows are the rows of the batch, using the same values as Rows.Next
+		Rows [][]interface{}
+		// Err is set when selecting the partition failed, it is the last batch of the partition
+		Err error
+	}
+)
+
+const (
+	// defaultExportParallel is the number of partitions selected at the same time when ExportOptions.Parallel is not set
+	defaultExportParallel = 4
+	// defaultExportBatchSize is the number of rows in an ExportBatch when ExportOptions.BatchSize is not set
+	defaultExportBatchSize = 1000
+
+	// exportPartitionsQuery selects the partitions of a table, owner is null for the current schema
+	exportPartitionsQuery = "select PARTITION_NAME from ALL_TAB_PARTITIONS where TABLE_OWNER = nvl(:1, sys_context('USERENV', 'CURRENT_SCHEMA')) and TABLE_NAME = :2 order by PARTITION_POSITION"
+)
+
+// ExportTable selects all the rows of a table, partition by partition, with up to Parallel partitions selected at the same time
+// on separate connections of db, and sends the rows in batches on the returned channel.
+// The rows are fetched with FetchBatch. A table that is not partitioned is selected as one partition.
+// The table name is a valid identifier, see ValidateIdentifier, optionally with the owner, like OWNER.TABLE_NAME.
+//
+// The channel is closed when all the partitions are done. A partition that fails sends a batch with Err set
+// and the other partitions continue. Batches of different partitions are interleaved.
+// Cancel ctx to stop early, the channel must be read until it is closed or ctx is done.
+// The batches of a partition are sent in the order they are fetched. The partitions are read in their own transactions,
+// so the export is not a consistent snapshot of the table when it is being changed.
+func ExportTable(ctx context.Context, db *sql.DB, tableName string, options ExportOptions) (<-chan ExportBatch, error) {
+	owner, table := "", tableName
+	if index := strings.IndexByte(tableName, '.'); index >= 0 {
+		owner, table = tableName[:index], tableName[index+1:]
+		err := ValidateIdentifier(owner)
+		if err != nil {
+			return nil, err
+		}
+		owner = identifierName(owner)
+	}
+	err := ValidateIdentifier(table)
+	if err != nil {
+		return nil, err
+	}
+	table = identifierName(table)
+
+	columns := "*"
+	if len(options.Columns) > 0 {
+		for _, column := range options.Columns {
+			err = ValidateIdentifier(column)
+			if err != nil {
+				return nil, err
+			}
+		}
+		columns = strings.Join(options.Columns, ", ")
+	}
+	if options.Parallel < 1 {
+		options.Parallel = defaultExportParallel
+	}
+	if options.BatchSize < 1 {
+		options.BatchSize = defaultExportBatchSize
+	}
+
+	var ownerArg interface{}
+	if owner != "" {
+		ownerArg = owner
+	}
+	rows, err := db.QueryContext(ctx, exportPartitionsQuery, ownerArg, table)
+	if err != nil {
+		return nil, err
+	}
+	var partitions []string
+	for rows.Next() {
+		var partition string
+		err = rows.Scan(&partition)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		partitions = append(partitions, partition)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(partitions) == 0 {
+		// not partitioned, select the table as one partition
+		partitions = []string{""}
+	}
+
+	batches := make(chan ExportBatch, options.Parallel)
+	partitionNames := make(chan string)
+	var waitGroup sync.WaitGroup
+	for i := 0; i < options.Parallel && i < len(partitions); i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for partition := range partitionNames {
+				query, err := exportQuery(columns, tableName, partition, options.Where)
+				if err == nil {
+					err = exportPartition(ctx, db, partition, query, options, batches)
+				}
+				if err != nil {
+					select {
+					case batches <- ExportBatch{Partition: partition, Err: err}:
+					case <-ctx.Done():
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+	Loop:
+		for _, partition := range partitions {
+			select {
+			case partitionNames <- partition:
+			case <-ctx.Done():
+				break Loop
+			}
+		}
+		close(partitionNames)
+		waitGroup.Wait()
+		close(batches)
+	}()
+
+	return batches, nil
+}
+
+// exportPartition runs the select of a partition on its own connection and sends its rows in batches
+func exportPartition(ctx context.Context, db *sql.DB, partition string, query string, options ExportOptions, batches chan<- ExportBatch) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		oci8Conn, ok := driverConn.(*Conn)
+		if !ok {
+			return fmt.Errorf("export needs an oci8 connection, received %T", driverConn)
+		}
+
+		namedValues := make([]driver.NamedValue, len(options.Args))
+		for i, arg := range options.Args {
+			namedValues[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+		}
+		driverStmt, err := oci8Conn.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		stmt := driverStmt.(*Stmt)
+		defer stmt.Close()
+		for i := range namedValues {
+			err = stmt.CheckNamedValue(&namedValues[i])
+			if err == driver.ErrSkip {
+				namedValues[i].Value, err = driver.DefaultParameterConverter.ConvertValue(namedValues[i].Value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		driverRows, err := stmt.QueryContext(ctx, namedValues)
+		if err != nil {
+			return err
+		}
+		rows := driverRows.(*Rows)
+		defer rows.Close()
+
+		columnNames := rows.Columns()
+		columns := make([][]interface{}, len(columnNames))
+		columnArgs := make([]interface{}, len(columnNames))
+		for i := range columns {
+			// the values are copied into the rows of each batch, so the column slices are reused
+			columns[i] = make([]interface{}, options.BatchSize)
+			columnArgs[i] = columns[i]
+		}
+		for {
+			count, err := rows.FetchBatch(columnArgs...)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			batch := ExportBatch{Partition: partition, Columns: columnNames, Rows: make([][]interface{}, count)}
+			for row := range batch.Rows {
+				batch.Rows[row] = make([]interface{}, len(columns))
+				for i := range columns {
+					batch.Rows[row][i] = columns[i][row]
+				}
+			}
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			if count < options.BatchSize {
+				return nil
+			}
+		}
+	})
+}
+
+// exportQuery returns the query selecting the columns of the partition of the table, or of the whole table when partition is empty.
+// Returns an *IdentifierError when the partition name can not be quoted.
+func exportQuery(columns string, tableName string, partition string, where string) (string, error) {
+	query := "select " + columns + " from " + tableName
+	if partition != "" {
+		quoted, err := QuoteIdentifier(partition)
+		if err != nil {
+			return "", err
+		}
+		query += " partition ( " + quoted + " )"
+	}
+	if where != "" {
+		query += " where " + where
+	}
+	return query, nil
+}
@@ -0,0 +1,131 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestDestructiveExportTable tests exporting a partitioned table and a table that is not partitioned
+func TestDestructiveExportTable(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "EXPORT_" + TestTimeString
+	err := testExec(t, "create table "+tableName+` ( A INTEGER, B VARCHAR2(20) ) partition by range ( A ) (
+	partition P1 values less than ( 100 ),
+	partition P2 values less than ( 200 ),
+	partition P3 values less than ( maxvalue ) )`, nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+	err = testExec(t, "insert into "+tableName+" ( A, B ) select level, 'row ' || level from dual connect by level <= 250", nil)
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	plainName := "EXPORT_PLAIN_" + TestTimeString
+	err = testExec(t, "create table "+plainName+" as select A, B from "+tableName+" where A <= 10", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, plainName)
+
+	var exportTests = []struct {
+		tableName  string
+		options    ExportOptions
+		partitions map[string]int
+	}{
+		{tableName, ExportOptions{BatchSize: 30, Parallel: 2}, map[string]int{"P1": 99, "P2": 100, "P3": 51}},
+		{tableName, ExportOptions{Columns: []string{"A"}, Where: "A > :1", Args: []interface{}{150}}, map[string]int{"P2": 49, "P3": 51}},
+		{plainName, ExportOptions{}, map[string]int{"": 10}},
+	}
+
+	for _, tt := range exportTests {
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		batches, err := ExportTable(ctx, TestDB, tt.tableName, tt.options)
+		if err != nil {
+			cancel()
+			t.Fatal("export error:", err)
+		}
+
+		partitions := make(map[string]int)
+		for batch := range batches {
+			if batch.Err != nil {
+				t.Errorf("export partition %v error: %v", batch.Partition, batch.Err)
+				continue
+			}
+			if tt.options.BatchSize > 0 && len(batch.Rows) > tt.options.BatchSize {
+				t.Errorf("export batch size - expected: %v - received: %v", tt.options.BatchSize, len(batch.Rows))
+			}
+			for _, row := range batch.Rows {
+				if len(row) != len(batch.Columns) {
+					t.Fatalf("export row - expected columns: %v - received: %v", batch.Columns, row)
+				}
+				if _, ok := row[0].(int64); !ok {
+					t.Errorf("export column A - expected: int64 - received: %T", row[0])
+				}
+			}
+			partitions[batch.Partition] += len(batch.Rows)
+		}
+		cancel()
+
+		if !reflect.DeepEqual(partitions, tt.partitions) {
+			t.Errorf("export %v rows - expected: %v - received: %v", tt.tableName, tt.partitions, partitions)
+		}
+	}
+}
+
+// TestExportTableValidate tests ExportTable validates names before using the database
+func TestExportTableValidate(t *testing.T) {
+	t.Parallel()
+
+	var validateTests = []struct {
+		tableName string
+		columns   []string
+	}{
+		{"", nil},
+		{"A B", nil},
+		{"OWNER.", nil},
+		{"OWNER.T;", nil},
+		{"T", []string{"A", "B C"}},
+	}
+	for _, tt := range validateTests {
+		_, err := ExportTable(context.Background(), nil, tt.tableName, ExportOptions{Columns: tt.columns})
+		var identifierError *IdentifierError
+		if !errors.As(err, &identifierError) {
+			t.Errorf("ExportTable %q %v - expected: %v - received: %v", tt.tableName, tt.columns, "*IdentifierError", err)
+		}
+	}
+
+	var queryTests = []struct {
+		partition string
+		where     string
+		query     string
+	}{
+		{"", "", "select * from T"},
+		{"", "A = 1", "select * from T where A = 1"},
+		{"P1", "", `select * from T partition ( "P1" )`},
+		{"p 1", "A = 1", `select * from T partition ( "p 1" ) where A = 1`},
+	}
+	for _, tt := range queryTests {
+		query, err := exportQuery("*", "T", tt.partition, tt.where)
+		if err != nil || query != tt.query {
+			t.Errorf("exportQuery %q %q - expected: %v - received: %v %v", tt.partition, tt.where, tt.query, query, err)
+		}
+	}
+
+	_, err := exportQuery("*", "T", `P"1`, "")
+	var identifierError *IdentifierError
+	if !errors.As(err, &identifierError) {
+		t.Errorf("exportQuery %q - expected: %v - received: %v", `P"1`, "*IdentifierError", err)
+	}
+}
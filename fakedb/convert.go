@@ -0,0 +1,331 @@
+package fakedb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-oci8/oracle"
+)
+
+var (
+	typeString    = reflect.TypeOf("a")
+	typeSliceByte = reflect.TypeOf([]byte{})
+	typeInt64     = reflect.TypeOf(int64(1))
+	typeFloat64   = reflect.TypeOf(float64(1))
+	typeTime      = reflect.TypeOf(time.Time{})
+	typeDuration  = reflect.TypeOf(time.Duration(1))
+	typeYearMonth = reflect.TypeOf(oracle.YearMonth{})
+)
+
+// bindValue returns the value Oracle receives for a bind value, see the package documentation
+func bindValue(value interface{}, converter oracle.Converter, intervalInt64 bool) (interface{}, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		reflectValue := reflect.ValueOf(valuer)
+		if reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil() {
+			return nil, nil
+		}
+		var err error
+		value, err = valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case sql.Out:
+		return nil, fmt.Errorf("OUT binds are not supported by fakedb")
+	case string:
+		if value == "" {
+			// Oracle stores an empty string as null
+			return nil, nil
+		}
+		return value, nil
+	case []byte:
+		if len(value) == 0 {
+			return nil, nil
+		}
+		return append([]byte(nil), value...), nil
+	case bool:
+		if converter != nil {
+			converted, err := converter.ConvertBool(value)
+			if err != nil {
+				return nil, err
+			}
+			convertedBool, ok := converted.(bool)
+			if !ok {
+				return bindValue(converted, nil, intervalInt64)
+			}
+			value = convertedBool
+		}
+		// oracle SQL does not have bool, bound as 0/1
+		if value {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case time.Duration:
+		if intervalInt64 {
+			return int64(value), nil
+		}
+		return value, nil
+	case oracle.YearMonth:
+		return value, nil
+	case time.Time:
+		// strip the monotonic clock reading
+		return value.Round(0), nil
+	case float32:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflectValue.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if reflectValue.Uint() > math.MaxInt64 {
+			return reflectValue.Uint(), nil
+		}
+		return int64(reflectValue.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return reflectValue.Float(), nil
+	case reflect.String:
+		return bindValue(reflectValue.String(), converter, intervalInt64)
+	case reflect.Bool:
+		return bindValue(reflectValue.Bool(), converter, intervalInt64)
+	}
+	return nil, fmt.Errorf("unsupported bind type %T", value)
+}
+
+// columnValue converts the value a handler returned to the type oci8 returns for the column, see the package documentation
+func columnValue(column *Column, value interface{}, numberStrings bool, intervalInt64 bool, timeLocation *time.Location) (driver.Value, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch column.Type {
+	case "NUMBER", "FLOAT":
+		number, err := toFloat64(value)
+		if err != nil {
+			return nil, columnError(column, value, err)
+		}
+		if column.Precision != 0 && column.Scale != -127 {
+			number = roundScale(number, column.Scale)
+		}
+		if !isInteger(column) {
+			if numberStrings {
+				return strconv.FormatFloat(number, 'f', -1, 64), nil
+			}
+			return number, nil
+		}
+		integer, ok := value.(int64)
+		if !ok || column.Scale != 0 {
+			integer = int64(number)
+		}
+		if numberStrings {
+			return strconv.FormatInt(integer, 10), nil
+		}
+		return integer, nil
+
+	case "BINARY_DOUBLE":
+		number, err := toFloat64(value)
+		if err != nil {
+			return nil, columnError(column, value, err)
+		}
+		return number, nil
+
+	case "BINARY_FLOAT":
+		number, err := toFloat64(value)
+		if err != nil {
+			return nil, columnError(column, value, err)
+		}
+		return float64(float32(number)), nil
+
+	case "VARCHAR2", "NVARCHAR2", "CLOB", "NCLOB", "LONG", "ROWID", "CHAR", "NCHAR":
+		var text string
+		switch value := value.(type) {
+		case string:
+			text = value
+		case []byte:
+			text = string(value)
+		default:
+			text = fmt.Sprint(value)
+		}
+		if text == "" {
+			// Oracle stores an empty string as null
+			return nil, nil
+		}
+		if (column.Type == "CHAR" || column.Type == "NCHAR") && int64(len([]rune(text))) < column.Length {
+			text += strings.Repeat(" ", int(column.Length)-len([]rune(text)))
+		}
+		return text, nil
+
+	case "RAW", "LONG RAW", "BLOB":
+		var data []byte
+		switch value := value.(type) {
+		case []byte:
+			data = append([]byte(nil), value...)
+		case string:
+			data = []byte(value)
+		default:
+			return nil, columnError(column, value, nil)
+		}
+		if len(data) == 0 {
+			return nil, nil
+		}
+		return data, nil
+
+	case "DATE", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		aTime, ok := value.(time.Time)
+		if !ok {
+			return nil, columnError(column, value, nil)
+		}
+		switch column.Type {
+		case "DATE":
+			// the wall clock of the time, without fractional seconds
+			return time.Date(aTime.Year(), aTime.Month(), aTime.Day(), aTime.Hour(), aTime.Minute(), aTime.Second(), 0, timeLocation), nil
+		case "TIMESTAMP":
+			return time.Date(aTime.Year(), aTime.Month(), aTime.Day(), aTime.Hour(), aTime.Minute(), aTime.Second(), aTime.Nanosecond(), timeLocation), nil
+		case "TIMESTAMP WITH LOCAL TIME ZONE":
+			return aTime.In(timeLocation), nil
+		}
+		return aTime.Round(0), nil
+
+	case "INTERVAL DAY TO SECOND":
+		var nanoseconds int64
+		switch value := value.(type) {
+		case time.Duration:
+			nanoseconds = int64(value)
+		case int:
+			nanoseconds = int64(value)
+		case int64:
+			nanoseconds = value
+		default:
+			return nil, columnError(column, value, nil)
+		}
+		if intervalInt64 {
+			return nanoseconds, nil
+		}
+		return time.Duration(nanoseconds), nil
+
+	case "INTERVAL YEAR TO MONTH":
+		var months int64
+		switch value := value.(type) {
+		case oracle.YearMonth:
+			months = value.TotalMonths()
+		case int:
+			months = int64(value)
+		case int64:
+			months = value
+		default:
+			return nil, columnError(column, value, nil)
+		}
+		if intervalInt64 {
+			return months, nil
+		}
+		return oracle.NewYearMonth(months), nil
+	}
+
+	return value, nil
+}
+
+// isInteger returns true for NUMBER columns with a precision and a scale of 0, which oci8 returns as int64
+func isInteger(column *Column) bool {
+	return !((column.Precision == 0 && column.Scale == 0) || column.Scale > 0 || column.Scale == -127)
+}
+
+// toFloat64 returns the number of an integer, floating point, or decimal string value
+func toFloat64(value interface{}) (float64, error) {
+	if text, ok := value.(string); ok {
+		return strconv.ParseFloat(text, 64)
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflectValue.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(reflectValue.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return reflectValue.Float(), nil
+	}
+	return 0, fmt.Errorf("%T is not a number", value)
+}
+
+// roundScale rounds a number to scale digits after the decimal point, before it for a negative scale, like Oracle stores it
+func roundScale(number float64, scale int64) float64 {
+	factor := math.Pow10(int(scale))
+	return math.Round(number*factor) / factor
+}
+
+// columnError returns the error of a value a handler returned that does not convert to the column type
+func columnError(column *Column, value interface{}, err error) error {
+	if err != nil {
+		return fmt.Errorf("fakedb column %v %v value %v error: %v", column.Name, column.Type, value, err)
+	}
+	return fmt.Errorf("fakedb column %v %v can not have a %T value", column.Name, column.Type, value)
+}
+
+// convertValue passes a not null value to the Converter of the connector like oci8
+func convertValue(converter oracle.Converter, column oracle.ConverterColumn, value driver.Value) (driver.Value, error) {
+	var err error
+	switch column.DatabaseTypeName {
+	case "NUMBER", "FLOAT", "BINARY_DOUBLE", "BINARY_FLOAT":
+		value, err = converter.ConvertNumber(column, value)
+	case "DATE", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		value, err = converter.ConvertTime(column, value.(time.Time))
+	case "CLOB", "NCLOB", "BLOB":
+		value, err = converter.ConvertLob(column, value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("convert for column %v - error: %v", column.Index, err)
+	}
+	return value, nil
+}
+
+// columnLength returns the Length of character and RAW columns, and math.MaxInt64 for LOB and LONG columns
+func columnLength(column *Column) (int64, bool) {
+	switch column.Type {
+	case "VARCHAR2", "NVARCHAR2", "CHAR", "NCHAR", "RAW":
+		return column.Length, true
+	case "CLOB", "NCLOB", "BLOB", "LONG", "LONG RAW":
+		return math.MaxInt64, true
+	}
+	return 0, false
+}
+
+// scanType returns the Go type of the values of a column, see the package documentation
+func scanType(column *Column, numberStrings bool, intervalInt64 bool) reflect.Type {
+	switch column.Type {
+	case "NUMBER", "FLOAT":
+		if numberStrings {
+			return typeString
+		}
+		if isInteger(column) {
+			return typeInt64
+		}
+		return typeFloat64
+	case "BINARY_DOUBLE", "BINARY_FLOAT":
+		return typeFloat64
+	case "RAW", "LONG RAW", "BLOB":
+		return typeSliceByte
+	case "DATE", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		return typeTime
+	case "INTERVAL DAY TO SECOND", "INTERVAL YEAR TO MONTH":
+		if intervalInt64 {
+			return typeInt64
+		}
+		if column.Type == "INTERVAL DAY TO SECOND" {
+			return typeDuration
+		}
+		return typeYearMonth
+	}
+	return typeString
+}
@@ -0,0 +1,47 @@
+/*
+Package fakedb is an in-memory fake of the oci8 driver, so application tests can check Oracle specific behavior
+without a database. It does not use cgo, so the tests do not need the Oracle client either.
+
+Statements are answered by handlers registered for the statement text. The fake applies the rules of the oci8 driver
+between the application and the handlers: the DSN is parsed by the oracle package the same way as oci8, bind values are converted
+the way Oracle receives them, and the values a handler returns are converted to the types oci8 returns for the column types:
+
+	fake := fakedb.New()
+	fake.Handle("select name, balance from accounts where id = :1", func(ctx context.Context, args []driver.NamedValue) (*fakedb.Result, error) {
+		return &fakedb.Result{
+			Columns: []fakedb.Column{
+				{Name: "NAME", Type: "VARCHAR2", Length: 100, Nullable: true},
+				{Name: "BALANCE", Type: "NUMBER", Precision: 12, Scale: 2},
+			},
+			Rows: [][]interface{}{{"", 10.005}},
+		}, nil
+	})
+	db, err := fake.Open("scott/tiger@dbhost:1521/ORCLPDB1")
+
+The query above returns a null NAME, as Oracle stores an empty string as null, and a BALANCE of 10.01 as a float64.
+
+Bind values passed to handlers are nil for null, empty strings, and empty byte slices, int64 for integers and bools,
+float64 for floats, []byte, string, time.Time without the monotonic clock reading, time.Duration, and oracle.YearMonth.
+With interval_int64 a time.Duration is passed as int64 nanoseconds.
+Bools are passed to the ConvertBool of the connector Converter first.
+
+Column values are converted by the Type of the column:
+
+	NUMBER, FLOAT                      int64 for a precision and a scale of 0 or less, otherwise float64 rounded to the scale,
+	                                   a decimal string with number_strings
+	BINARY_DOUBLE, BINARY_FLOAT        float64
+	VARCHAR2, NVARCHAR2, CLOB, NCLOB,  string, empty strings are null
+	LONG, ROWID
+	CHAR, NCHAR                        string padded with spaces to the Length
+	RAW, LONG RAW, BLOB                []byte, empty byte slices are null
+	DATE                               time.Time in the loc location, fractional seconds truncated
+	TIMESTAMP                          time.Time in the loc location
+	TIMESTAMP WITH TIME ZONE           time.Time
+	TIMESTAMP WITH LOCAL TIME ZONE     time.Time in the loc location
+	INTERVAL DAY TO SECOND             time.Duration, int64 nanoseconds with interval_int64
+	INTERVAL YEAR TO MONTH             oracle.YearMonth, int64 months with interval_int64
+
+Other types are returned as the handler returned them. The values are then passed to the connector Converter,
+and the max_rows and read_only parameters apply.
+*/
+package fakedb
@@ -0,0 +1,430 @@
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-oci8/oracle"
+)
+
+type (
+	// Fake is an in-memory database that answers the statements of its connections with the registered handlers
+	Fake struct {
+		mutex    sync.RWMutex
+		handlers map[string]Handler
+	}
+
+	// Handler answers a statement. args are the bind values as Oracle receives them, see the package documentation.
+	// The error is returned to the application as is, like an error with an ORA- message.
+	Handler func(ctx context.Context, args []driver.NamedValue) (*Result, error)
+
+	// Result is the result of a statement, the columns and rows of a query, or the rows affected of other statements
+	Result struct {
+		// Columns are the columns of a query
+		Columns []Column
+		// Rows are the rows of a query, with a value for each column, nil for null
+		Rows [][]interface{}
+		// RowsAffected is the number of rows changed by other statements
+		RowsAffected int64
+	}
+
+	// Column is a result column of a query
+	Column struct {
+		// Name is the column name, Oracle returns unquoted identifiers in upper case
+		Name string
+		// Type is the Oracle type name of the column, like VARCHAR2, NUMBER, or TIMESTAMP WITH TIME ZONE,
+		// the same as ColumnTypeDatabaseTypeName of oci8
+		Type string
+		// Precision is the precision of NUMBER and FLOAT columns, 0 for NUMBER without a precision
+		Precision int64
+		// Scale is the scale of NUMBER columns, -127 for FLOAT
+		Scale int64
+		// Length is the max length of character and RAW columns
+		Length int64
+		// Nullable is true when the column allows null
+		Nullable bool
+	}
+
+	// NoHandlerError is returned for a statement without a handler
+	NoHandlerError struct {
+		// Query is the statement text
+		Query string
+	}
+
+	// fakeDriver is the driver.Driver of a Fake, it opens connections for a DSN
+	fakeDriver struct {
+		fake *Fake
+	}
+
+	// connector is the driver.Connector of a Fake
+	connector struct {
+		fake      *Fake
+		dsn       *oracle.DSN
+		converter oracle.Converter
+	}
+
+	// conn is a fake connection
+	conn struct {
+		connector    *connector
+		timeLocation *time.Location
+		closed       bool
+	}
+
+	// stmt is a fake prepared statement
+	stmt struct {
+		conn  *conn
+		query string
+	}
+
+	// tx is a fake transaction, commit and rollback do nothing
+	tx struct{}
+
+	// result is the driver.Result of an exec
+	result struct {
+		rowsAffected int64
+	}
+
+	// rows are the rows of a query
+	rows struct {
+		conn    *conn
+		columns []Column
+		values  [][]interface{}
+		index   int
+	}
+)
+
+// ErrClosed is returned when a closed connection is used
+var ErrClosed = errors.New("connection is closed")
+
+// New returns a new Fake without handlers
+func New() *Fake {
+	return &Fake{
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Handle registers the handler of a statement, replacing the handler registered before for it.
+// Statements are matched by their text with runs of white space replaced by a single space, and the text trimmed.
+// A nil handler removes the handler.
+func (fake *Fake) Handle(query string, handler Handler) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	if handler == nil {
+		delete(fake.handlers, normalizeQuery(query))
+		return
+	}
+	fake.handlers[normalizeQuery(query)] = handler
+}
+
+// Connector returns a connector of the fake for an oci8 DSN, parsed and checked by oracle.ParseDSN the same way as oci8.ParseDSN.
+// converter is the Converter of the connector, nil keeps the values unchanged. No connection to a database is made.
+func (fake *Fake) Connector(dsn string, converter oracle.Converter) (driver.Connector, error) {
+	params, err := oracle.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &connector{
+		fake:      fake,
+		dsn:       params,
+		converter: converter,
+	}, nil
+}
+
+// Open returns a sql.DB of the fake for an oci8 DSN, see oci8.ParseDSN
+func (fake *Fake) Open(dsn string) (*sql.DB, error) {
+	connector, err := fake.Connector(dsn, nil)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// handler returns the handler of a statement
+func (fake *Fake) handler(query string) (Handler, error) {
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	handler := fake.handlers[normalizeQuery(query)]
+	if handler == nil {
+		return nil, &NoHandlerError{Query: query}
+	}
+	return handler, nil
+}
+
+// normalizeQuery replaces runs of white space with a single space and trims the text
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// Error returns the no handler error string
+func (noHandlerError *NoHandlerError) Error() string {
+	return "no fakedb handler for statement: " + noHandlerError.Query
+}
+
+// Open returns a new fake connection for an oci8 DSN
+func (fakeDriver fakeDriver) Open(dsn string) (driver.Conn, error) {
+	connector, err := fakeDriver.fake.Connector(dsn, nil)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+// Connect returns a new fake connection
+func (connector *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return &conn{connector: connector, timeLocation: connector.dsn.TimeLocation}, nil
+}
+
+// Driver returns the driver of the fake
+func (connector *connector) Driver() driver.Driver {
+	return fakeDriver{fake: connector.fake}
+}
+
+// Prepare returns a fake prepared statement
+func (conn *conn) Prepare(query string) (driver.Stmt, error) {
+	return conn.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext returns a fake prepared statement, a *oracle.ReadOnlyError on a read only connection
+// for statements other than queries, PL/SQL blocks, and calls
+func (conn *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if conn.closed {
+		return nil, ErrClosed
+	}
+	if conn.connector.dsn.ReadOnly {
+		fields := strings.Fields(query)
+		if len(fields) > 0 {
+			switch statementType := strings.ToUpper(fields[0]); statementType {
+			case "SELECT", "WITH", "BEGIN", "DECLARE", "CALL":
+			default:
+				return nil, &oracle.ReadOnlyError{StatementType: statementType}
+			}
+		}
+	}
+	return &stmt{conn: conn, query: query}, nil
+}
+
+// Close closes the fake connection
+func (conn *conn) Close() error {
+	conn.closed = true
+	return nil
+}
+
+// Begin starts a fake transaction
+func (conn *conn) Begin() (driver.Tx, error) {
+	return conn.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts a fake transaction
+func (conn *conn) BeginTx(ctx context.Context, txOptions driver.TxOptions) (driver.Tx, error) {
+	if conn.closed {
+		return nil, ErrClosed
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return tx{}, nil
+}
+
+// Ping returns driver.ErrBadConn when the connection is closed
+func (conn *conn) Ping(ctx context.Context) error {
+	if conn.closed {
+		return driver.ErrBadConn
+	}
+	return ctx.Err()
+}
+
+// CheckNamedValue converts the bind value to the value Oracle receives
+func (conn *conn) CheckNamedValue(namedValue *driver.NamedValue) error {
+	value, err := bindValue(namedValue.Value, conn.connector.converter, conn.connector.dsn.IntervalInt64)
+	if err != nil {
+		return &oracle.BindError{Index: namedValue.Ordinal - 1, Name: namedValue.Name, Err: err}
+	}
+	namedValue.Value = value
+	return nil
+}
+
+// run calls the handler of the statement
+func (conn *conn) run(ctx context.Context, query string, args []driver.NamedValue) (*Result, error) {
+	if conn.closed {
+		return nil, ErrClosed
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	handler, err := conn.connector.fake.handler(query)
+	if err != nil {
+		return nil, err
+	}
+	result, err := handler(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &Result{}
+	}
+	return result, nil
+}
+
+// Close closes the statement
+func (stmt *stmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1, the number of binds is not checked
+func (stmt *stmt) NumInput() int {
+	return -1
+}
+
+// CheckNamedValue converts the bind value to the value Oracle receives
+func (stmt *stmt) CheckNamedValue(namedValue *driver.NamedValue) error {
+	return stmt.conn.CheckNamedValue(namedValue)
+}
+
+// Exec runs the statement
+func (stmt *stmt) Exec(values []driver.Value) (driver.Result, error) {
+	return stmt.ExecContext(context.Background(), namedValues(values))
+}
+
+// ExecContext runs the statement with the handler and returns the rows affected
+func (stmt *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	handlerResult, err := stmt.conn.run(ctx, stmt.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return result{rowsAffected: handlerResult.RowsAffected}, nil
+}
+
+// Query runs the query
+func (stmt *stmt) Query(values []driver.Value) (driver.Rows, error) {
+	return stmt.QueryContext(context.Background(), namedValues(values))
+}
+
+// QueryContext runs the query with the handler and returns its rows
+func (stmt *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	handlerResult, err := stmt.conn.run(ctx, stmt.query, args)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range handlerResult.Rows {
+		if len(row) != len(handlerResult.Columns) {
+			return nil, errors.New("fakedb handler returned a row without a value for each column")
+		}
+	}
+	return &rows{conn: stmt.conn, columns: handlerResult.Columns, values: handlerResult.Rows}, nil
+}
+
+// namedValues returns the values as ordinal named values
+func namedValues(values []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(values))
+	for i, value := range values {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: value}
+	}
+	return named
+}
+
+// Commit does nothing
+func (tx) Commit() error {
+	return nil
+}
+
+// Rollback does nothing
+func (tx) Rollback() error {
+	return nil
+}
+
+// LastInsertId returns an error, the fake has no rowids
+func (result result) LastInsertId() (int64, error) {
+	return 0, errors.New("LastInsertId is not supported by fakedb")
+}
+
+// RowsAffected returns the rows affected of the handler result
+func (result result) RowsAffected() (int64, error) {
+	return result.rowsAffected, nil
+}
+
+// Columns returns the column names
+func (rows *rows) Columns() []string {
+	names := make([]string, len(rows.columns))
+	for i, column := range rows.columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// Close closes the rows
+func (rows *rows) Close() error {
+	rows.values = nil
+	return nil
+}
+
+// Next converts the next row to the types oci8 returns, returns oracle.ErrTooManyRows past max_rows
+func (rows *rows) Next(dest []driver.Value) error {
+	if rows.index >= len(rows.values) {
+		return io.EOF
+	}
+	row := rows.values[rows.index]
+	rows.index++
+	maxRows := rows.conn.connector.dsn.MaxRows
+	if maxRows > 0 && int64(rows.index) > maxRows {
+		return oracle.ErrTooManyRows
+	}
+
+	config := rows.conn.connector.dsn
+	converter := rows.conn.connector.converter
+	for i := range dest {
+		value, err := columnValue(&rows.columns[i], row[i], config.NumberStrings, config.IntervalInt64, rows.conn.timeLocation)
+		if err != nil {
+			return err
+		}
+		if value != nil && converter != nil {
+			value, err = convertValue(converter, oracle.ConverterColumn{Index: i, Name: rows.columns[i].Name, DatabaseTypeName: rows.columns[i].Type}, value)
+			if err != nil {
+				return err
+			}
+		}
+		dest[i] = value
+	}
+	return nil
+}
+
+// ColumnTypeDatabaseTypeName returns the Type of the column
+func (rows *rows) ColumnTypeDatabaseTypeName(i int) string {
+	return rows.columns[i].Type
+}
+
+// ColumnTypeLength returns the Length of character and RAW columns, and math.MaxInt64 for LOB and LONG columns
+func (rows *rows) ColumnTypeLength(i int) (int64, bool) {
+	return columnLength(&rows.columns[i])
+}
+
+// ColumnTypeNullable returns the Nullable of the column
+func (rows *rows) ColumnTypeNullable(i int) (bool, bool) {
+	return rows.columns[i].Nullable, true
+}
+
+// ColumnTypePrecisionScale returns the precision and scale of NUMBER and FLOAT columns declared with a precision
+func (rows *rows) ColumnTypePrecisionScale(i int) (int64, int64, bool) {
+	column := &rows.columns[i]
+	if (column.Type != "NUMBER" && column.Type != "FLOAT") || column.Precision == 0 {
+		return 0, 0, false
+	}
+	if column.Scale == -127 {
+		return column.Precision, 0, true
+	}
+	return column.Precision, column.Scale, true
+}
+
+// ColumnTypeScanType returns the Go type of the values of the column
+func (rows *rows) ColumnTypeScanType(i int) reflect.Type {
+	return scanType(&rows.columns[i], rows.conn.connector.dsn.NumberStrings, rows.conn.connector.dsn.IntervalInt64)
+}
@@ -0,0 +1,205 @@
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"go/build"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-oci8/oracle"
+)
+
+// testOpen opens a sql.DB of the fake with the DSN
+func testOpen(t *testing.T, fake *Fake, dsn string) *sql.DB {
+	db, err := fake.Open(dsn)
+	if err != nil {
+		t.Fatal("open error:", err)
+	}
+	return db
+}
+
+// TestNoCgo tests fakedb and the oracle package it uses build without cgo and do not import the cgo oci8 package,
+// so application tests do not need the Oracle client
+func TestNoCgo(t *testing.T) {
+	t.Parallel()
+
+	buildContext := build.Default
+	buildContext.CgoEnabled = true
+	for _, dir := range []string{".", "../oracle"} {
+		pkg, err := buildContext.ImportDir(dir, 0)
+		if err != nil {
+			t.Fatalf("import %v error: %v", dir, err)
+		}
+		if len(pkg.CgoFiles) > 0 {
+			t.Errorf("%v cgo files - expected: %v - received: %v", pkg.Name, nil, pkg.CgoFiles)
+		}
+		for _, imported := range pkg.Imports {
+			if imported == "C" || imported == "github.com/mattn/go-oci8" {
+				t.Errorf("%v imports - expected: no %v - received: %v", pkg.Name, imported, pkg.Imports)
+			}
+		}
+	}
+}
+
+// TestBindValues tests the bind values passed to handlers
+func TestBindValues(t *testing.T) {
+	t.Parallel()
+
+	fake := New()
+	var received []interface{}
+	fake.Handle("insert into t values (:1, :2, :3, :4, :5, :6)", func(ctx context.Context, args []driver.NamedValue) (*Result, error) {
+		for _, arg := range args {
+			received = append(received, arg.Value)
+		}
+		return &Result{RowsAffected: 1}, nil
+	})
+	db := testOpen(t, fake, "scott/tiger@dbhost")
+	defer db.Close()
+
+	aTime := time.Now()
+	result, err := db.Exec("insert into t \n values (:1, :2, :3, :4, :5, :6)", "", []byte{}, true, uint8(7), 2*time.Second, aTime)
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected != 1 {
+		t.Errorf("rows affected - expected: %v - received: %v %v", 1, rowsAffected, err)
+	}
+
+	expected := []interface{}{nil, nil, int64(1), int64(7), 2 * time.Second, aTime.Round(0)}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("binds - expected: %#v - received: %#v", expected, received)
+	}
+
+	_, err = db.Exec("insert into t values (:1, :2, :3, :4, :5, :6)", struct{}{}, 1, 2, 3, 4, 5)
+	if err == nil || !strings.Contains(err.Error(), "unsupported bind type struct {}") {
+		t.Errorf("bind error - received: %v", err)
+	}
+}
+
+// TestColumnValues tests the handler values are returned as the types of the columns
+func TestColumnValues(t *testing.T) {
+	t.Parallel()
+
+	columns := []Column{
+		{Name: "ID", Type: "NUMBER", Precision: 10},
+		{Name: "AMOUNT", Type: "NUMBER", Precision: 12, Scale: 2},
+		{Name: "RATIO", Type: "NUMBER"},
+		{Name: "NAME", Type: "VARCHAR2", Length: 20, Nullable: true},
+		{Name: "CODE", Type: "CHAR", Length: 4},
+		{Name: "DATA", Type: "RAW", Length: 16, Nullable: true},
+		{Name: "CREATED", Type: "DATE"},
+	}
+	fake := New()
+	fake.Handle("select * from t", func(ctx context.Context, args []driver.NamedValue) (*Result, error) {
+		return &Result{
+			Columns: columns,
+			Rows:    [][]interface{}{{7, 10.005, int64(3), "", "AB", []byte{}, time.Date(2020, 1, 2, 3, 4, 5, 600, time.UTC)}},
+		}, nil
+	})
+
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal("load location error:", err)
+	}
+
+	tests := []struct {
+		dsn    string
+		values []interface{}
+	}{
+		{
+			dsn:    "scott/tiger@dbhost?loc=America%2FNew_York",
+			values: []interface{}{int64(7), 10.01, float64(3), nil, "AB  ", nil, time.Date(2020, 1, 2, 3, 4, 5, 0, location)},
+		},
+		{
+			dsn:    "scott/tiger@dbhost?number_strings=true",
+			values: []interface{}{"7", "10.01", "3", nil, "AB  ", nil, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+	}
+	for _, test := range tests {
+		db := testOpen(t, fake, test.dsn)
+		rows, err := db.Query("select * from t")
+		if err != nil {
+			t.Fatal("query error:", err)
+		}
+		columnTypes, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal("column types error:", err)
+		}
+		if columnTypes[3].DatabaseTypeName() != "VARCHAR2" {
+			t.Errorf("database type name - expected: %v - received: %v", "VARCHAR2", columnTypes[3].DatabaseTypeName())
+		}
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if !rows.Next() {
+			t.Fatal("no rows:", rows.Err())
+		}
+		err = rows.Scan(dest...)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		rows.Close()
+		db.Close()
+
+		if !reflect.DeepEqual(values, test.values) {
+			t.Errorf("values %v - expected: %#v - received: %#v", test.dsn, test.values, values)
+		}
+		for i, value := range values {
+			if value != nil && reflect.TypeOf(value) != columnTypes[i].ScanType() {
+				t.Errorf("scan type %v %v - expected: %v - received: %v", test.dsn, columns[i].Name, reflect.TypeOf(value), columnTypes[i].ScanType())
+			}
+		}
+	}
+}
+
+// TestConnectorOptions tests the DSN is parsed like oci8 and the max_rows and read_only parameters
+func TestConnectorOptions(t *testing.T) {
+	t.Parallel()
+
+	fake := New()
+	_, err := fake.Open("scott/tiger@dbhost?prefetch_rows=x")
+	if err == nil {
+		t.Errorf("invalid DSN - expected: error - received: nil")
+	}
+
+	fake.Handle("select level from dual connect by level <= 3", func(ctx context.Context, args []driver.NamedValue) (*Result, error) {
+		return &Result{
+			Columns: []Column{{Name: "LEVEL", Type: "NUMBER"}},
+			Rows:    [][]interface{}{{1}, {2}, {3}},
+		}, nil
+	})
+	db := testOpen(t, fake, "scott/tiger@dbhost?max_rows=2&read_only=true")
+	defer db.Close()
+
+	rows, err := db.Query("select level from dual connect by level <= 3")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	if count != 2 || rows.Err() != oracle.ErrTooManyRows {
+		t.Errorf("max rows - expected: %v %v - received: %v %v", 2, oracle.ErrTooManyRows, count, rows.Err())
+	}
+	rows.Close()
+
+	_, err = db.Exec("delete from t")
+	readOnlyError, ok := err.(*oracle.ReadOnlyError)
+	if !ok || readOnlyError.StatementType != "DELETE" {
+		t.Errorf("read only - received: %v", err)
+	}
+
+	_, err = db.Query("select * from missing")
+	noHandlerError, ok := err.(*NoHandlerError)
+	if !ok || noHandlerError.Query != "select * from missing" {
+		t.Errorf("no handler - received: %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"io"
+	"unsafe"
+)
+
+/*
+With the fetch_array_size DSN parameter, or the FetchArraySize of WithStmtOptions, the columns of a query are defined
+with arrays of that many rows, and each fetch call fills the arrays with the next rows instead of fetching one row at a time:
+
+	db, err := sql.Open("oci8", "user/pass@host/service?fetch_array_size=1000")
+
+Next then returns the rows from the arrays until they are used up, and fetches the next rows.
+Rows.FetchBatch defines the arrays with the length of its column slices when the query is not fetched with arrays.
+Queries with LOB, VECTOR, object, or nested cursor columns, and with piecewise LONG columns, are fetched a row at a time.
+The define arrays take the size of a row times the fetch array size, which is lowered to fit a WithMemoryBudget.
+*/
+
+// fetchArraySize returns the number of rows fetched by each fetch of the query, the FetchArraySize of the statement options
+// or the fetch_array_size DSN parameter, lowered so the define arrays of the rows fit in the memory budget
+func (stmt *Stmt) fetchArraySize(defines []defineStruct, memoryBudget int64) int64 {
+	size := int64(stmt.conn.fetchArraySize)
+	if options, ok := stmtOptionsFromContext(stmt.ctx); ok && options.FetchArraySize > 0 {
+		size = int64(options.FetchArraySize)
+	}
+	if memoryBudget > 0 && size > 1 {
+		if budgetSize := memoryBudget / defineRowSize(defines); size > budgetSize {
+			size = budgetSize
+		}
+	}
+	return size
+}
+
+// defineArraysSupported returns true when all the columns can be defined with arrays,
+// columns of types with descriptors other than timestamps and intervals are fetched a row at a time
+func defineArraysSupported(defines []defineStruct) bool {
+	for i := range defines {
+		define := &defines[i]
+		if define.piecewise || define.objectConn != nil {
+			return false
+		}
+		if define.skip {
+			continue
+		}
+		switch define.dataType {
+		case C.SQLT_AFC, C.SQLT_BIN, C.SQLT_NUM, C.SQLT_INT, C.SQLT_BDOUBLE, C.SQLT_LNG,
+			C.SQLT_TIMESTAMP, C.SQLT_TIMESTAMP_TZ, C.SQLT_INTERVAL_DS, C.SQLT_INTERVAL_YM:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// defineArrayDescriptorType returns the descriptor type of the define data types with a descriptor for each row
+func defineArrayDescriptorType(dataType C.ub2) (C.ub4, bool) {
+	switch dataType {
+	case C.SQLT_TIMESTAMP:
+		return C.OCI_DTYPE_TIMESTAMP, true
+	case C.SQLT_TIMESTAMP_TZ:
+		return C.OCI_DTYPE_TIMESTAMP_TZ, true
+	case C.SQLT_INTERVAL_DS:
+		return C.OCI_DTYPE_INTERVAL_DS, true
+	case C.SQLT_INTERVAL_YM:
+		return C.OCI_DTYPE_INTERVAL_YM, true
+	}
+	return 0, false
+}
+
+// defineArrays defines the columns again with arrays of size rows, replacing the single row buffers.
+// The caller frees the defines on error.
+func (stmt *Stmt) defineArrays(defines []defineStruct, size int64) error {
+	for i := range defines {
+		define := &defines[i]
+
+		hasBuffer := define.pbuf != nil
+		if hasBuffer {
+			freeBuffer(define.pbuf, define.dataType)
+		}
+		C.free(unsafe.Pointer(define.length))
+		C.free(unsafe.Pointer(define.indicator))
+
+		define.arraySize = size
+		define.arrayLength = (*C.ub2)(C.calloc(C.size_t(size), C.sizeof_ub2))
+		define.arrayIndicator = (*C.sb2)(C.calloc(C.size_t(size), C.sizeof_sb2))
+		if hasBuffer {
+			define.arrayBuf = C.calloc(C.size_t(size), C.size_t(define.maxSize))
+		}
+		define.setArrayRow(0)
+
+		if descriptorType, ok := defineArrayDescriptorType(define.dataType); ok && hasBuffer {
+			for row := int64(0); row < size; row++ {
+				descriptorP, _, err := stmt.conn.ociDescriptorAlloc(descriptorType, 0)
+				if err != nil {
+					return err
+				}
+				*(*unsafe.Pointer)(define.arrayElement(row)) = *descriptorP
+			}
+		}
+
+		err := stmt.ociDefineByPos(C.ub4(i+1), define)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arrayElement returns the pointer to the buffer of the row in the define array
+func (define *defineStruct) arrayElement(row int64) unsafe.Pointer {
+	return unsafe.Pointer(uintptr(define.arrayBuf) + uintptr(row)*uintptr(define.maxSize))
+}
+
+// setArrayRow points the buffer, length, and indicator of the define to the row in the define arrays
+func (define *defineStruct) setArrayRow(row int64) {
+	if define.arrayBuf != nil {
+		define.pbuf = define.arrayElement(row)
+	}
+	define.length = (*C.ub2)(unsafe.Pointer(uintptr(unsafe.Pointer(define.arrayLength)) + uintptr(row)*C.sizeof_ub2))
+	define.indicator = (*C.sb2)(unsafe.Pointer(uintptr(unsafe.Pointer(define.arrayIndicator)) + uintptr(row)*C.sizeof_sb2))
+}
+
+// freeDefineArrays frees the define arrays and the descriptors in them
+func freeDefineArrays(define *defineStruct) {
+	if define.arrayBuf != nil {
+		if _, ok := defineArrayDescriptorType(define.dataType); ok {
+			for row := int64(0); row < define.arraySize; row++ {
+				element := define.arrayElement(row)
+				if *(*unsafe.Pointer)(element) != nil {
+					freeBuffer(element, define.dataType)
+				}
+			}
+		}
+		C.free(define.arrayBuf)
+		define.arrayBuf = nil
+	}
+	C.free(unsafe.Pointer(define.arrayLength))
+	define.arrayLength = nil
+	C.free(unsafe.Pointer(define.arrayIndicator))
+	define.arrayIndicator = nil
+	define.pbuf = nil
+	define.length = nil
+	define.indicator = nil
+}
+
+// fetchArrayRow moves to the next row of the define arrays, fetching the next rows into the arrays when all the rows were returned.
+// Returns io.EOF when there are no more rows.
+func (rows *Rows) fetchArrayRow() error {
+	if rows.arrayRow+1 < rows.arrayRows {
+		rows.arrayRow++
+		for i := range rows.defines {
+			rows.defines[i].setArrayRow(rows.arrayRow)
+		}
+		return nil
+	}
+	if rows.arrayDone {
+		return io.EOF
+	}
+
+	result := C.OCIStmtFetch2(
+		rows.stmt.stmt,
+		rows.stmt.conn.errHandle,
+		C.ub4(rows.fetchArraySize),
+		C.OCI_FETCH_NEXT,
+		0,
+		C.OCI_DEFAULT)
+	if result == C.OCI_NO_DATA {
+		// the last rows, fewer than the array size
+		rows.arrayDone = true
+	} else if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
+		return rows.stmt.conn.getError(result)
+	}
+
+	var fetched C.ub4
+	_, err := rows.stmt.ociAttrGet(unsafe.Pointer(&fetched), C.OCI_ATTR_ROWS_FETCHED)
+	if err != nil {
+		return err
+	}
+	if fetched == 0 {
+		rows.arrayDone = true
+		return io.EOF
+	}
+
+	rows.arrayRows = int64(fetched)
+	rows.arrayRow = 0
+	for i := range rows.defines {
+		rows.defines[i].setArrayRow(0)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package oci8
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestFetchArraySize checks the rows of queries fetched with define arrays
+func TestFetchArraySize(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	ctx = WithStmtOptions(ctx, StmtOptions{FetchArraySize: 100})
+
+	rows, err := TestDB.QueryContext(ctx, "select level, to_char(level), decode(mod(level, 2), 0, date '2000-01-01' + level), numtodsinterval(level, 'SECOND') from dual connect by level <= 250")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		var number int64
+		var text string
+		var date interface{}
+		var interval time.Duration
+		err = rows.Scan(&number, &text, &date, &interval)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		count++
+		if number != count || text != strconv.FormatInt(count, 10) || interval != time.Duration(count)*time.Second {
+			t.Fatalf("row %v - received: %v, %v, %v", count, number, text, interval)
+		}
+		if count%2 == 1 {
+			if date != nil {
+				t.Fatalf("row %v date - expected: nil - received: %v", count, date)
+			}
+			continue
+		}
+		expected := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(count))
+		if value, ok := date.(time.Time); !ok || !value.Equal(expected) {
+			t.Fatalf("row %v date - expected: %v - received: %v", count, expected, date)
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Fatal("rows error:", err)
+	}
+	if count != 250 {
+		t.Errorf("count - expected: %v - received: %v", 250, count)
+	}
+
+	// LOB columns are fetched a row at a time
+	var clob string
+	err = TestDB.QueryRowContext(ctx, "select to_clob('abc') from dual").Scan(&clob)
+	if err != nil {
+		t.Fatal("query row error:", err)
+	}
+	if clob != "abc" {
+		t.Errorf("clob - expected: %v - received: %v", "abc", clob)
+	}
+}
@@ -0,0 +1,273 @@
+package oci8
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// FetchBatch fetches rows directly into column slices, one preallocated slice per column,
+// filling up to the length of the shortest slice. It returns the number of rows fetched,
+// which is less than the slice length when there are no more rows, and io.EOF when no rows were fetched.
+//
+// Supported slice types are []int64, []float64, []string, [][]byte, []time.Time,
+// []sql.NullInt64, []sql.NullFloat64, []sql.NullString, and []interface{}.
+// Nulls are set to the zero value, use the sql.Null slices or []interface{} to tell nulls apart.
+// A []int64 can be used for NUMBER columns without a scale, which are fetched as floating point,
+// as long as the values are whole numbers.
+// INTEGER, floating point, character, and timestamp columns are read from the define buffers
+// without converting to interface{} values, unless the connection has a Converter.
+//
+// When the query is not fetched with define arrays, see fetch_array_size, the first call defines the columns
+// with arrays of the length of the slices, lowered to fit a WithMemoryBudget, so the rows are fetched
+// with one fetch call per batch instead of one per row.
+//
+// Rows are returned from QueryContext of the driver Stmt, use sql.Conn.Raw to get the driver connection:
+//
+//	rows, err := stmt.(driver.StmtQueryContext).QueryContext(ctx, nil)
+//	count, err := rows.(*oci8.Rows).FetchBatch(ids, names)
+func (rows *Rows) FetchBatch(columns ...interface{}) (int, error) {
+	err := rows.guard.enter("Rows", "FetchBatch", rows.stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.guard.exit(rows.stmt.conn.debugConcurrentUse)
+
+	if rows.closed {
+		return 0, io.EOF
+	}
+	if len(columns) != len(rows.defines) {
+		return 0, fmt.Errorf("FetchBatch needs %v column slices, received %v", len(rows.defines), len(columns))
+	}
+
+	size := -1
+	for i, column := range columns {
+		length := fetchBatchLength(column)
+		if length < 0 {
+			return 0, fmt.Errorf("FetchBatch unsupported type %T for column %v", column, i)
+		}
+		if size < 0 || length < size {
+			size = length
+		}
+	}
+
+	if rows.stmt.ctx.Err() != nil {
+		return 0, rows.stmt.ctx.Err()
+	}
+
+	err = rows.fetchBatchArrays(size)
+	if err != nil {
+		return 0, err
+	}
+
+	stop := rows.stmt.conn.watchBreak(rows.stmt.ctx)
+	defer stop()
+
+	var values []driver.Value
+	for row := 0; row < size; row++ {
+		err = rows.fetchNext()
+		if err != nil {
+			if err == io.EOF && row > 0 {
+				return row, nil
+			}
+			return row, err
+		}
+
+		converted := false
+		for i, column := range columns {
+			var ok bool
+			ok, err = rows.scanDirect(i, fetchBatchElement(column, row))
+			if err != nil {
+				return row, fmt.Errorf("FetchBatch column %v - error: %v", i, err)
+			}
+			if ok {
+				continue
+			}
+
+			if !converted {
+				if values == nil {
+					values = make([]driver.Value, len(rows.defines))
+				}
+				err = rows.values(values)
+				if err != nil {
+					return row, err
+				}
+				converted = true
+			}
+			err = fetchBatchSet(column, row, values[i])
+			if err != nil {
+				return row, fmt.Errorf("FetchBatch column %v - error: %v", i, err)
+			}
+		}
+	}
+
+	return size, nil
+}
+
+// fetchBatchArrays defines the columns with arrays of size rows when the rows are fetched a row at a time,
+// so fetchNext fetches the rows of the batch into the arrays with one fetch call
+func (rows *Rows) fetchBatchArrays(size int) error {
+	if rows.fetchArraySize > 1 || size < 2 || !defineArraysSupported(rows.defines) {
+		return nil
+	}
+
+	arraySize := int64(size)
+	if memoryBudget := memoryBudgetFromContext(rows.stmt.ctx); memoryBudget > 0 {
+		if budgetSize := memoryBudget / defineRowSize(rows.defines); arraySize > budgetSize {
+			arraySize = budgetSize
+		}
+	}
+	if arraySize < 2 {
+		return nil
+	}
+
+	// the columns are defined again between fetches, the next fetch fills the arrays
+	err := rows.stmt.defineArrays(rows.defines, arraySize)
+	if err != nil {
+		return err
+	}
+	rows.fetchArraySize = arraySize
+	return nil
+}
+
+// fetchBatchLength returns the length of a FetchBatch column slice, -1 when the type is not supported
+func fetchBatchLength(column interface{}) int {
+	switch column := column.(type) {
+	case []int64:
+		return len(column)
+	case []float64:
+		return len(column)
+	case []string:
+		return len(column)
+	case [][]byte:
+		return len(column)
+	case []time.Time:
+		return len(column)
+	case []sql.NullInt64:
+		return len(column)
+	case []sql.NullFloat64:
+		return len(column)
+	case []sql.NullString:
+		return len(column)
+	case []interface{}:
+		return len(column)
+	}
+	return -1
+}
+
+// fetchBatchElement returns a pointer to the row of the column slice, the destination of scanDirect.
+// A [][]byte element is set to nil first, so the bytes are copied into a new slice instead of the one of the last batch.
+func fetchBatchElement(column interface{}, row int) interface{} {
+	switch column := column.(type) {
+	case []int64:
+		return &column[row]
+	case []float64:
+		return &column[row]
+	case []string:
+		return &column[row]
+	case [][]byte:
+		column[row] = nil
+		return &column[row]
+	case []time.Time:
+		return &column[row]
+	case []sql.NullInt64:
+		return &column[row]
+	case []sql.NullFloat64:
+		return &column[row]
+	case []sql.NullString:
+		return &column[row]
+	case []interface{}:
+		return &column[row]
+	}
+	return nil
+}
+
+// fetchBatchSet sets the row of the column slice to a converted value.
+// Byte slices are copied because the define buffers are reused by the next fetch.
+func fetchBatchSet(column interface{}, row int, value driver.Value) error {
+	if buffer, ok := value.([]byte); ok {
+		value = append([]byte(nil), buffer...)
+	}
+
+	switch column := column.(type) {
+	case []interface{}:
+		column[row] = value
+		return nil
+	case []sql.NullInt64:
+		return column[row].Scan(value)
+	case []sql.NullFloat64:
+		return column[row].Scan(value)
+	case []sql.NullString:
+		return column[row].Scan(value)
+	}
+
+	switch value := value.(type) {
+	case nil:
+		switch column := column.(type) {
+		case []int64:
+			column[row] = 0
+		case []float64:
+			column[row] = 0
+		case []string:
+			column[row] = ""
+		case [][]byte:
+			column[row] = nil
+		case []time.Time:
+			column[row] = time.Time{}
+		}
+		return nil
+	case int64:
+		switch column := column.(type) {
+		case []int64:
+			column[row] = value
+			return nil
+		case []float64:
+			column[row] = float64(value)
+			return nil
+		}
+	case float64:
+		switch column := column.(type) {
+		case []float64:
+			column[row] = value
+			return nil
+		case []int64:
+			if floatIsInt64(value) {
+				column[row] = int64(value)
+				return nil
+			}
+		}
+	case string:
+		switch column := column.(type) {
+		case []string:
+			column[row] = value
+			return nil
+		case [][]byte:
+			column[row] = []byte(value)
+			return nil
+		}
+	case []byte:
+		switch column := column.(type) {
+		case [][]byte:
+			column[row] = value
+			return nil
+		case []string:
+			column[row] = string(value)
+			return nil
+		}
+	case time.Time:
+		if column, ok := column.([]time.Time); ok {
+			column[row] = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("can not set %v into %T", value, column)
+}
+
+// floatIsInt64 returns true when the float is a whole number in the int64 range
+func floatIsInt64(value float64) bool {
+	return value == math.Trunc(value) && value >= math.MinInt64 && value < math.MaxInt64
+}
@@ -0,0 +1,168 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestFetchBatch checks fetching rows into column slices
+func TestFetchBatch(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	query := "select level, level / 2, case when mod(level, 3) = 0 then null else 'row ' || level end, " +
+		"cast (timestamp '2020-01-01 00:00:00' + numtodsinterval(level, 'DAY') as TIMESTAMP(9)) from dual connect by level <= 5"
+
+	ids := make([]int64, 2)
+	halves := make([]float64, 2)
+	names := make([]sql.NullString, 2)
+	times := make([]time.Time, 2)
+	var allIDs []int64
+	var allNames []sql.NullString
+	err = conn.Raw(func(driverConn interface{}) error {
+		stmt, err := driverConn.(*Conn).PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		rows, err := stmt.(driver.StmtQueryContext).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for {
+			count, err := rows.(*Rows).FetchBatch(ids, halves, names, times)
+			if err == io.EOF {
+				// the columns are defined with arrays of the length of the slices
+				if rows.(*Rows).fetchArraySize != 2 {
+					t.Errorf("fetch array size - expected: %v - received: %v", 2, rows.(*Rows).fetchArraySize)
+				}
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			for i := 0; i < count; i++ {
+				if halves[i] != float64(ids[i])/2 {
+					t.Errorf("half - expected: %v - received: %v", float64(ids[i])/2, halves[i])
+				}
+				expectedTime := time.Date(2020, 1, 1+int(ids[i]), 0, 0, 0, 0, time.UTC)
+				if !times[i].Equal(expectedTime) {
+					t.Errorf("time - expected: %v - received: %v", expectedTime, times[i])
+				}
+			}
+			allIDs = append(allIDs, ids[:count]...)
+			allNames = append(allNames, names[:count]...)
+		}
+	})
+	if err != nil {
+		t.Fatal("fetch batch error:", err)
+	}
+
+	if !reflect.DeepEqual(allIDs, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("ids - received: %v", allIDs)
+	}
+	expectedNames := []sql.NullString{{String: "row 1", Valid: true}, {String: "row 2", Valid: true}, {}, {String: "row 4", Valid: true}, {String: "row 5", Valid: true}}
+	if !reflect.DeepEqual(allNames, expectedNames) {
+		t.Errorf("names - received: %v", allNames)
+	}
+}
+
+// TestFetchBatchSet tests setting converted values into FetchBatch column slices
+func TestFetchBatchSet(t *testing.T) {
+	t.Parallel()
+
+	if fetchBatchLength([]int64{1, 2}) != 2 || fetchBatchLength([]sql.NullString{}) != 0 || fetchBatchLength([]int{1}) != -1 {
+		t.Error("fetchBatchLength unexpected length")
+	}
+
+	aTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	buffer := []byte{1, 2}
+	var setTests = []struct {
+		column   interface{}
+		value    interface{}
+		expected interface{}
+	}{
+		{[]int64{9}, int64(1), []int64{1}},
+		{[]int64{9}, nil, []int64{0}},
+		{[]float64{9}, int64(2), []float64{2}},
+		{[]float64{9}, 2.5, []float64{2.5}},
+		{[]int64{9}, 3.0, []int64{3}},
+		{[]string{"x"}, "a", []string{"a"}},
+		{[]string{"x"}, []byte("b"), []string{"b"}},
+		{[][]byte{nil}, buffer, [][]byte{{1, 2}}},
+		{[]time.Time{{}}, aTime, []time.Time{aTime}},
+		{[]sql.NullInt64{{}}, int64(3), []sql.NullInt64{{Int64: 3, Valid: true}}},
+		{[]sql.NullInt64{{Int64: 3, Valid: true}}, nil, []sql.NullInt64{{}}},
+		{[]sql.NullFloat64{{}}, 1.5, []sql.NullFloat64{{Float64: 1.5, Valid: true}}},
+		{[]sql.NullString{{}}, "c", []sql.NullString{{String: "c", Valid: true}}},
+		{[]interface{}{1}, nil, []interface{}{nil}},
+	}
+	for _, tt := range setTests {
+		err := fetchBatchSet(tt.column, 0, tt.value)
+		if err != nil {
+			t.Errorf("fetchBatchSet(%T, %v) error: %v", tt.column, tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.column, tt.expected) {
+			t.Errorf("fetchBatchSet(%T, %v) - expected: %v - received: %v", tt.column, tt.value, tt.expected, tt.column)
+		}
+	}
+
+	// byte slices are copied
+	column := [][]byte{nil}
+	_ = fetchBatchSet(column, 0, buffer)
+	buffer[0] = 9
+	if column[0][0] != 1 {
+		t.Error("fetchBatchSet did not copy byte slice")
+	}
+
+	err := fetchBatchSet([]int64{0}, 0, "a")
+	if err == nil {
+		t.Error("fetchBatchSet expected error for string into []int64")
+	}
+	err = fetchBatchSet([]int64{0}, 0, 2.5)
+	if err == nil {
+		t.Error("fetchBatchSet expected error for 2.5 into []int64")
+	}
+
+	// the elements are the destinations of scanDirect
+	ids := []int64{0, 0}
+	*fetchBatchElement(ids, 1).(*int64) = 5
+	if ids[1] != 5 {
+		t.Errorf("fetchBatchElement []int64 - expected: %v - received: %v", 5, ids[1])
+	}
+	names := []sql.NullString{{}}
+	*fetchBatchElement(names, 0).(*sql.NullString) = sql.NullString{String: "a", Valid: true}
+	if !names[0].Valid || names[0].String != "a" {
+		t.Errorf("fetchBatchElement []sql.NullString - received: %v", names[0])
+	}
+	buffers := [][]byte{buffer}
+	if element := fetchBatchElement(buffers, 0).(*[]byte); *element != nil || buffers[0] != nil {
+		t.Error("fetchBatchElement [][]byte did not reset the element to nil")
+	}
+	if fetchBatchElement([]int{1}, 0) != nil {
+		t.Error("fetchBatchElement expected nil for []int")
+	}
+}
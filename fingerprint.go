@@ -0,0 +1,272 @@
+package oci8
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NormalizeQuery returns the fingerprint of a SQL statement, so statements that differ only in their literals,
+// bind names, comments, case, or the length of IN lists have the same fingerprint.
+// Use it to label timings and metrics, to keep the number of labels bounded when SQL is generated dynamically.
+//
+// String, national string, q-quoted string, and number literals, and bind variables, are replaced with ?.
+// Lists of only ? in parentheses, like IN lists, are collapsed to (?).
+// Comments are removed, whitespace is collapsed to one space, and the text outside of
+// double quoted identifiers is lower cased.
+//
+//	select * from T where ID in (1, 2, :3) and NAME = 'x' -- find
+//
+// has the fingerprint
+//
+//	select * from t where id in (?) and name = ?
+func NormalizeQuery(query string) string {
+	var builder strings.Builder
+	builder.Grow(len(query))
+	runes := []rune(query)
+	space := false
+	last := ' '
+
+	// write writes text, with one space before it when there was whitespace,
+	// except at the start, after an opening parenthesis, and before a comma or closing parenthesis
+	write := func(text string) {
+		if space && last != ' ' && last != '(' && text != "," && text != ")" {
+			builder.WriteByte(' ')
+		}
+		space = false
+		builder.WriteString(text)
+		last = []rune(text)[len([]rune(text))-1]
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+			i++
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			space = true
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+			space = true
+
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end < len(runes) {
+				end++
+			}
+			write(string(runes[i:end]))
+			i = end
+
+		case r == '\'':
+			i = skipStringLiteral(runes, i)
+			write("?")
+
+		case (r == 'n' || r == 'N') && i+1 < len(runes) && runes[i+1] == '\'' && !isIdentifierRune(runes, i-1):
+			i = skipStringLiteral(runes, i+1)
+			write("?")
+
+		case (r == 'q' || r == 'Q') && i+2 < len(runes) && runes[i+1] == '\'' && !isIdentifierRune(runes, i-1):
+			i = skipQuotedLiteral(runes, i+1)
+			write("?")
+
+		case (r == 'n' || r == 'N') && i+3 < len(runes) && (runes[i+1] == 'q' || runes[i+1] == 'Q') &&
+			runes[i+2] == '\'' && !isIdentifierRune(runes, i-1):
+			i = skipQuotedLiteral(runes, i+2)
+			write("?")
+
+		case r == ':' && i+1 < len(runes) && (runes[i+1] == '"' || isIdentifierStart(runes[i+1]) || unicode.IsDigit(runes[i+1])):
+			i++
+			if runes[i] == '"' {
+				i++
+				for i < len(runes) && runes[i] != '"' {
+					i++
+				}
+				i++
+			} else {
+				for i < len(runes) && isIdentifierPart(runes[i]) {
+					i++
+				}
+			}
+			write("?")
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			i = skipNumberLiteral(runes, i)
+			write("?")
+
+		case isIdentifierStart(r):
+			end := i + 1
+			for end < len(runes) && isIdentifierPart(runes[end]) {
+				end++
+			}
+			write(strings.ToLower(string(runes[i:end])))
+			i = end
+
+		default:
+			write(string(r))
+			if r == ',' {
+				space = true
+			}
+			i++
+		}
+	}
+
+	return collapseBindLists(builder.String())
+}
+
+// isIdentifierStart returns true when the rune can start an unquoted identifier
+func isIdentifierStart(r rune) bool {
+	return unicode.IsLetter(r)
+}
+
+// isIdentifierPart returns true when the rune can be part of an unquoted identifier
+func isIdentifierPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '#'
+}
+
+// isIdentifierRune returns true when runes[i] exists and is part of an unquoted identifier
+func isIdentifierRune(runes []rune, i int) bool {
+	return i >= 0 && i < len(runes) && isIdentifierPart(runes[i])
+}
+
+// skipStringLiteral returns the index after the string literal that starts with the quote at runes[i].
+// Doubled quotes are part of the literal.
+func skipStringLiteral(runes []rune, i int) int {
+	i++
+	for i < len(runes) {
+		if runes[i] == '\'' {
+			if i+1 < len(runes) && runes[i+1] == '\'' {
+				i += 2
+				continue
+			}
+			return i + 1
+		}
+		i++
+	}
+	return i
+}
+
+// skipQuotedLiteral returns the index after the q-quoted literal, like q'[text]', that starts with the quote at runes[i]
+func skipQuotedLiteral(runes []rune, i int) int {
+	i++
+	if i >= len(runes) {
+		return i
+	}
+	closing := runes[i]
+	switch closing {
+	case '[':
+		closing = ']'
+	case '{':
+		closing = '}'
+	case '<':
+		closing = '>'
+	case '(':
+		closing = ')'
+	}
+	i++
+	for i < len(runes) {
+		if runes[i] == closing && i+1 < len(runes) && runes[i+1] == '\'' {
+			return i + 2
+		}
+		i++
+	}
+	return i
+}
+
+// skipNumberLiteral returns the index after the number literal that starts at runes[i],
+// including the fraction, exponent, and the f or d suffix of binary floating point literals
+func skipNumberLiteral(runes []rune, i int) int {
+	for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+		i++
+	}
+	if i+1 < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+		next := i + 1
+		if runes[next] == '+' || runes[next] == '-' {
+			next++
+		}
+		if next < len(runes) && unicode.IsDigit(runes[next]) {
+			i = next
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+		}
+	}
+	if i < len(runes) && strings.ContainsRune("fFdD", runes[i]) && !isIdentifierRune(runes, i+1) {
+		i++
+	}
+	return i
+}
+
+// collapseBindLists replaces lists of ? in parentheses with (?)
+func collapseBindLists(query string) string {
+	var builder strings.Builder
+	builder.Grow(len(query))
+	for {
+		start := strings.Index(query, "(?, ?")
+		if start < 0 {
+			builder.WriteString(query)
+			return builder.String()
+		}
+		end := start + len("(?")
+		for strings.HasPrefix(query[end:], ", ?") {
+			end += len(", ?")
+		}
+		if !strings.HasPrefix(query[end:], ")") {
+			builder.WriteString(query[:end])
+			query = query[end:]
+			continue
+		}
+		builder.WriteString(query[:start])
+		builder.WriteString("(?)")
+		query = query[end+1:]
+	}
+}
+
+// Apply returns the statement text shortened by the policy. The literals are stripped first,
+// then the text is cut to MaxLength, then the hash suffix of the full text is appended when the text was changed.
+// The zero policy returns the text as is.
+//
+//	QueryTextPolicy{StripLiterals: true, HashSuffix: true}.Apply("select * from T where ID in (1, 2, 3)")
+//
+// returns
+//
+//	select * from t where id in (?) /* fnv:aa0ace9f241b1325 */
+func (policy QueryTextPolicy) Apply(query string) string {
+	text := query
+	if policy.StripLiterals {
+		text = NormalizeQuery(text)
+	}
+	if policy.MaxLength > 0 && len(text) > policy.MaxLength {
+		end := policy.MaxLength
+		for end > 0 && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		text = text[:end] + "..."
+	}
+	if policy.HashSuffix && text != query {
+		text += " /* fnv:" + queryHash(query) + " */"
+	}
+	return text
+}
+
+// queryHash returns the 64 bit FNV-1a hash of the statement text in hex
+func queryHash(query string) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(query))
+	text := strconv.FormatUint(hash.Sum64(), 16)
+	return strings.Repeat("0", 16-len(text)) + text
+}
@@ -0,0 +1,82 @@
+package oci8
+
+import "testing"
+
+// TestNormalizeQuery tests query fingerprints
+func TestNormalizeQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query       string
+		fingerprint string
+	}{
+		{query: "", fingerprint: ""},
+		{query: "select 1 from dual", fingerprint: "select ? from dual"},
+		{query: "SELECT  *\n\tFROM T\r\nWHERE ID = 42", fingerprint: "select * from t where id = ?"},
+		{query: "select * from T where ID in (1, 2, :3) and NAME = 'x' -- find", fingerprint: "select * from t where id in (?) and name = ?"},
+		{query: "select * from t where id in ( 1,2,3,4,5 )", fingerprint: "select * from t where id in (?)"},
+		{query: "select * from t where id in (:1, :2) or id in (:id1, :id2, :id3)", fingerprint: "select * from t where id in (?) or id in (?)"},
+		{query: "select * from t where (a, b) in ((1, 2), (3, 4))", fingerprint: "select * from t where (a, b) in ((?), (?))"},
+		{query: "select * from t where id in (1, 2 + 3)", fingerprint: "select * from t where id in (?, ? + ?)"},
+		{query: "select 'it''s', n'x', q'[it's]', nq'{y}', Q'!a!' from dual", fingerprint: "select ?, ?, ?, ?, ? from dual"},
+		{query: "select 1.5, .5, 1e10, 2.5E-3, 1.5f, 2d from dual", fingerprint: "select ?, ?, ?, ?, ?, ? from dual"},
+		{query: `select "MixedCase", col1, t$x#, n from "Tab 1"`, fingerprint: `select "MixedCase", col1, t$x#, n from "Tab 1"`},
+		{query: "select /* hint */ a /*+ INDEX(t) */ from t", fingerprint: "select a from t"},
+		{query: "insert into t (a, b) values (:a, :\"B\")", fingerprint: "insert into t (a, b) values (?)"},
+		{query: "begin x := :1; end;", fingerprint: "begin x := ?; end;"},
+		{query: "select count(*), max(a) from t", fingerprint: "select count(*), max(a) from t"},
+		{query: "select 'unterminated", fingerprint: "select ?"},
+	}
+
+	for _, test := range tests {
+		fingerprint := NormalizeQuery(test.query)
+		if fingerprint != test.fingerprint {
+			t.Errorf("query %q - expected: %q - received: %q", test.query, test.fingerprint, fingerprint)
+		}
+	}
+
+	// fingerprint is only set when enabled
+	stmt := &Stmt{conn: &Conn{}, queryText: "select 1 from dual"}
+	if fingerprint := stmt.queryFingerprint(); fingerprint != "" {
+		t.Errorf("fingerprint disabled - expected: empty - received: %q", fingerprint)
+	}
+	stmt.conn.hooks.NormalizeQuery = true
+	if fingerprint := stmt.queryFingerprint(); fingerprint != "select ? from dual" {
+		t.Errorf("fingerprint enabled - expected: %q - received: %q", "select ? from dual", fingerprint)
+	}
+}
+
+// TestQueryTextPolicy tests shortening the statement text for the hooks
+func TestQueryTextPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		policy   QueryTextPolicy
+		query    string
+		expected string
+	}{
+		{policy: QueryTextPolicy{}, query: "select 1 from dual", expected: "select 1 from dual"},
+		{policy: QueryTextPolicy{MaxLength: 100, HashSuffix: true}, query: "select 1 from dual", expected: "select 1 from dual"},
+		{policy: QueryTextPolicy{MaxLength: 10}, query: "select 'ééééé' from dual", expected: "select 'é..."},
+		{policy: QueryTextPolicy{MaxLength: 9, HashSuffix: true}, query: "select 'ééééé' from dual", expected: "select '... /* fnv:b3a52bc33638936b */"},
+		{policy: QueryTextPolicy{StripLiterals: true, HashSuffix: true}, query: "select * from T where ID in (1, 2, 3)", expected: "select * from t where id in (?) /* fnv:aa0ace9f241b1325 */"},
+		{policy: QueryTextPolicy{StripLiterals: true, MaxLength: 15}, query: "select * from T where ID in (1, 2, 3)", expected: "select * from t..."},
+	}
+
+	for _, test := range tests {
+		text := test.policy.Apply(test.query)
+		if text != test.expected {
+			t.Errorf("policy %+v query %q - expected: %q - received: %q", test.policy, test.query, test.expected, text)
+		}
+	}
+
+	// the text is shortened once per statement
+	stmt := &Stmt{conn: &Conn{}, queryText: "select 1 from dual"}
+	if text := stmt.hookQuery(); text != "select 1 from dual" {
+		t.Errorf("hook query - expected: %q - received: %q", "select 1 from dual", text)
+	}
+	stmt.conn.hooks.QueryText = QueryTextPolicy{StripLiterals: true}
+	if text := stmt.hookQuery(); text != "select ? from dual" {
+		t.Errorf("hook query - expected: %q - received: %q", "select ? from dual", text)
+	}
+}
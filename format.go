@@ -0,0 +1,97 @@
+package oci8
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatValue returns a value with its type, like int64(1), string("a"), or <nil>,
+// so values that print the same, like int64(1) and float64(1), can be told apart
+func FormatValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return "string(" + strconv.Quote(value) + ")"
+	case []byte:
+		if value == nil {
+			return "[]byte(nil)"
+		}
+		return "[]byte(0x" + hex.EncodeToString(value) + ")"
+	case time.Time:
+		return "time.Time(" + value.Format(time.RFC3339Nano) + " " + value.Location().String() + ")"
+	}
+	return fmt.Sprintf("%T(%v)", value, value)
+}
+
+// FormatRows returns result rows with one line per row, each value formatted with FormatValue
+func FormatRows(rows [][]interface{}) string {
+	var builder strings.Builder
+	for i, row := range rows {
+		builder.WriteString(formatRow(i, row))
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// DiffRows returns the differences between expected and received result rows, empty when they are equal.
+// Rows that are equal are prefixed with two spaces, differing expected rows with "- " and received rows with "+ ".
+// Times are equal when they are the same instant, other values when they have the same type and are deeply equal.
+func DiffRows(expected [][]interface{}, received [][]interface{}) string {
+	var builder strings.Builder
+	equal := true
+	for i := 0; i < len(expected) || i < len(received); i++ {
+		switch {
+		case i >= len(received):
+			equal = false
+			builder.WriteString("- " + formatRow(i, expected[i]) + "\n")
+		case i >= len(expected):
+			equal = false
+			builder.WriteString("+ " + formatRow(i, received[i]) + "\n")
+		case rowsEqual(expected[i], received[i]):
+			builder.WriteString("  " + formatRow(i, expected[i]) + "\n")
+		default:
+			equal = false
+			builder.WriteString("- " + formatRow(i, expected[i]) + "\n")
+			builder.WriteString("+ " + formatRow(i, received[i]) + "\n")
+		}
+	}
+	if equal {
+		return ""
+	}
+	return builder.String()
+}
+
+// formatRow returns a row as [index] followed by its formatted values
+func formatRow(index int, row []interface{}) string {
+	values := make([]string, len(row))
+	for i, value := range row {
+		values[i] = FormatValue(value)
+	}
+	return "[" + strconv.Itoa(index) + "] " + strings.Join(values, ", ")
+}
+
+// rowsEqual returns true when the rows have the same number of values and all the values are equal
+func rowsEqual(row1 []interface{}, row2 []interface{}) bool {
+	if len(row1) != len(row2) {
+		return false
+	}
+	for i := range row1 {
+		time1, ok1 := row1[i].(time.Time)
+		time2, ok2 := row2[i].(time.Time)
+		if ok1 || ok2 {
+			if !ok1 || !ok2 || !time1.Equal(time2) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(row1[i], row2[i]) {
+			return false
+		}
+	}
+	return true
+}
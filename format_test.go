@@ -0,0 +1,50 @@
+package oci8
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDiffRows tests formatting and diffing result rows
+func TestDiffRows(t *testing.T) {
+	t.Parallel()
+
+	aTime := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	var formatTests = []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "<nil>"},
+		{int64(1), "int64(1)"},
+		{float64(1), "float64(1)"},
+		{"a\n", `string("a\n")`},
+		{[]byte{1, 255}, "[]byte(0x01ff)"},
+		{[]byte(nil), "[]byte(nil)"},
+		{aTime, "time.Time(2020-01-02T03:04:05.000000006Z UTC)"},
+	}
+	for _, tt := range formatTests {
+		received := FormatValue(tt.value)
+		if received != tt.expected {
+			t.Errorf("FormatValue - expected: %v - received: %v", tt.expected, received)
+		}
+	}
+
+	expected := [][]interface{}{{int64(1), "a"}, {int64(2), aTime}, {int64(3), nil}}
+	received := [][]interface{}{{int64(1), "a"}, {int64(2), aTime.In(time.FixedZone("+1", 3600))}, {float64(3), nil}, {int64(4), nil}}
+	diff := DiffRows(expected, received)
+	expectedDiff := `  [0] int64(1), string("a")
+  [1] int64(2), time.Time(2020-01-02T03:04:05.000000006Z UTC)
+- [2] int64(3), <nil>
++ [2] float64(3), <nil>
++ [3] int64(4), <nil>
+`
+	if diff != expectedDiff {
+		t.Errorf("DiffRows - expected:\n%v - received:\n%v", expectedDiff, diff)
+	}
+	if DiffRows(expected, expected[:3]) != "" {
+		t.Errorf("DiffRows - expected: empty - received:\n%v", DiffRows(expected, expected[:3]))
+	}
+	if FormatRows(expected[:1]) != "[0] int64(1), string(\"a\")\n" {
+		t.Errorf("FormatRows - received: %v", FormatRows(expected[:1]))
+	}
+}
@@ -0,0 +1,301 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+type (
+	// SDOGeometry is an MDSYS.SDO_GEOMETRY value. SDO_GEOMETRY columns are returned as a *SDOGeometry, nil for null,
+	// decoded with the OCI object API, so the geometries do not have to be converted to WKT or GeoJSON on the server:
+	//
+	//	var geometry oci8.SDOGeometry
+	//	err = db.QueryRowContext(ctx, "select shape from parcels where id = :1", id).Scan(&geometry)
+	//
+	// Scan into a *SDOGeometry pointer, like a **SDOGeometry, for nullable columns.
+	// Null numbers of the point and the ordinates are NaN.
+	SDOGeometry struct {
+		// GType is SDO_GTYPE, like 2001 for a two dimensional point or 2003 for a two dimensional polygon, 0 when null
+		GType int64
+		// SRID is SDO_SRID, the coordinate system, 0 when null
+		SRID int64
+		// Point is SDO_POINT, nil when null
+		Point *SDOPoint
+		// ElemInfo is SDO_ELEM_INFO, the triplets of offset, element type, and interpretation of the ordinates, nil when null
+		ElemInfo []int64
+		// Ordinates is SDO_ORDINATES, the coordinates of the elements, nil when null
+		Ordinates []float64
+	}
+
+	// SDOPoint is an MDSYS.SDO_POINT_TYPE value, Z is NaN for two dimensional points
+	SDOPoint struct {
+		X float64
+		Y float64
+		Z float64
+	}
+)
+
+// Dimensions returns the number of dimensions of the geometry, the first digit of GType
+func (geometry *SDOGeometry) Dimensions() int {
+	return int(geometry.GType / 1000)
+}
+
+// Scan implements sql.Scanner, from the *SDOGeometry of an SDO_GEOMETRY column.
+// Returns an error for null, scan into a *SDOGeometry pointer, like a **SDOGeometry, for nullable columns.
+func (geometry *SDOGeometry) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case *SDOGeometry:
+		if src == nil {
+			break
+		}
+		*geometry = *src
+		return nil
+	case SDOGeometry:
+		*geometry = src
+		return nil
+	}
+	return fmt.Errorf("can not scan %T into SDOGeometry", src)
+}
+
+// isSDOGeometry returns true when the column is an MDSYS.SDO_GEOMETRY object
+func (define *defineStruct) isSDOGeometry() bool {
+	return define.columnType == C.SQLT_NTY && define.typeSchema == "MDSYS" && define.typeName == "SDO_GEOMETRY"
+}
+
+// sdoGeometryType returns the type descriptor of MDSYS.SDO_GEOMETRY, pinned for the session the first time it is used
+func (conn *Conn) sdoGeometryType() (*C.OCIType, error) {
+	if conn.sdoGeometryTDO != nil {
+		return conn.sdoGeometryTDO, nil
+	}
+
+	schema, schemaLength := cText("MDSYS", conn.utf16)
+	defer C.free(unsafe.Pointer(schema))
+	name, nameLength := cText("SDO_GEOMETRY", conn.utf16)
+	defer C.free(unsafe.Pointer(name))
+
+	var tdo *C.OCIType
+	result := C.OCITypeByName(
+		conn.env,               // environment handle
+		conn.errHandle,         // error handle
+		conn.svc,               // service context handle
+		schema,                 // schema name
+		schemaLength,           // length of the schema name
+		name,                   // type name
+		nameLength,             // length of the type name
+		nil,                    // version name, not used
+		0,                      // length of the version name
+		C.OCI_DURATION_SESSION, // pin duration
+		C.OCI_TYPEGET_HEADER,   // only the header of the type descriptor
+		&tdo,                   // the type descriptor
+	)
+	err := conn.getError(result)
+	if err != nil {
+		return nil, fmt.Errorf("SDO_GEOMETRY type error: %v", err)
+	}
+	conn.sdoGeometryTDO = tdo
+	return tdo, nil
+}
+
+// defineSDOGeometry defines an SDO_GEOMETRY column as an object. The define buffer holds the pointers to the object instance
+// and its null indicator struct that OCI allocates in the object cache, the instance is freed with freeObject.
+func (stmt *Stmt) defineSDOGeometry(position C.ub4, define *defineStruct) error {
+	tdo, err := stmt.conn.sdoGeometryType()
+	if err != nil {
+		return err
+	}
+
+	define.dataType = C.SQLT_NTY
+	define.maxSize = 0
+	define.pbuf = C.calloc(2, C.size_t(sizeOfNilPointer))
+	define.objectConn = stmt.conn
+
+	result := C.OCIDefineByPos(
+		stmt.stmt,                        // statement handle
+		&define.defineHandle,             // pointer to a pointer to a define handle
+		stmt.conn.errHandle,              // error handle
+		position,                         // position of this value in the select list
+		nil,                              // no buffer, the object is set with OCIDefineObject
+		0,                                // size of the buffer
+		C.SQLT_NTY,                       // named data type
+		unsafe.Pointer(define.indicator), // not used for objects, the null indicator struct has the indicators
+		nil,                              // no lengths
+		nil,                              // no column-level return codes
+		C.OCI_DEFAULT,                    // mode
+	)
+	err = stmt.conn.getError(result)
+	if err != nil {
+		return err
+	}
+
+	pointers := (*[2]unsafe.Pointer)(define.pbuf)
+	result = C.OCIDefineObject(
+		define.defineHandle, // define handle
+		stmt.conn.errHandle, // error handle
+		tdo,                 // type descriptor of the object
+		&pointers[0],        // pointer to the object instance pointer, allocated by OCI when nil
+		nil,                 // size of the object, not used
+		&pointers[1],        // pointer to the null indicator struct pointer, allocated by OCI when nil
+		nil,                 // size of the null indicator struct, not used
+	)
+	return stmt.conn.getError(result)
+}
+
+// freeObject frees the object instance of an object define buffer from the object cache.
+// Does nothing when the connection is closed, as the object cache is freed with the environment.
+func (conn *Conn) freeObject(buffer unsafe.Pointer) {
+	pointers := (*[2]unsafe.Pointer)(buffer)
+	if pointers[0] == nil {
+		return
+	}
+	if !conn.closed {
+		result := C.OCIObjectFree(conn.env, conn.errHandle, pointers[0], C.OCI_OBJECTFREE_FORCE)
+		if result != C.OCI_SUCCESS {
+			conn.logger.Print("OCIObjectFree error: ", conn.getError(result))
+		}
+	}
+	pointers[0] = nil
+	pointers[1] = nil
+}
+
+// sdoGeometryValue returns the fetched SDO_GEOMETRY of an object define as a *SDOGeometry, nil when null
+func (conn *Conn) sdoGeometryValue(define *defineStruct) (interface{}, error) {
+	pointers := (*[2]unsafe.Pointer)(define.pbuf)
+	object := (*C.oci8_sdo_geometry)(pointers[0])
+	indicator := (*C.oci8_sdo_geometry_ind)(pointers[1])
+	if object == nil || indicator == nil || indicator.atomic == C.OCI_IND_NULL {
+		return nil, nil
+	}
+
+	geometry := &SDOGeometry{}
+	var err error
+	if indicator.sdo_gtype != C.OCI_IND_NULL {
+		geometry.GType, err = conn.ociNumberToInt64(&object.sdo_gtype)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if indicator.sdo_srid != C.OCI_IND_NULL {
+		geometry.SRID, err = conn.ociNumberToInt64(&object.sdo_srid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if indicator.sdo_point.atomic != C.OCI_IND_NULL {
+		geometry.Point = &SDOPoint{}
+		coordinates := []struct {
+			number    *C.OCINumber
+			indicator C.OCIInd
+			value     *float64
+		}{
+			{number: &object.sdo_point.x, indicator: indicator.sdo_point.x, value: &geometry.Point.X},
+			{number: &object.sdo_point.y, indicator: indicator.sdo_point.y, value: &geometry.Point.Y},
+			{number: &object.sdo_point.z, indicator: indicator.sdo_point.z, value: &geometry.Point.Z},
+		}
+		for _, coordinate := range coordinates {
+			if coordinate.indicator == C.OCI_IND_NULL {
+				*coordinate.value = math.NaN()
+				continue
+			}
+			*coordinate.value, err = conn.ociNumberToFloat64(coordinate.number)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if indicator.sdo_elem_info != C.OCI_IND_NULL {
+		geometry.ElemInfo = []int64{}
+		err = conn.ociNumberArray(object.sdo_elem_info, func(number *C.OCINumber) error {
+			value := int64(0)
+			if number != nil {
+				value, err = conn.ociNumberToInt64(number)
+				if err != nil {
+					return err
+				}
+			}
+			geometry.ElemInfo = append(geometry.ElemInfo, value)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if indicator.sdo_ordinates != C.OCI_IND_NULL {
+		geometry.Ordinates = []float64{}
+		err = conn.ociNumberArray(object.sdo_ordinates, func(number *C.OCINumber) error {
+			value := math.NaN()
+			if number != nil {
+				value, err = conn.ociNumberToFloat64(number)
+				if err != nil {
+					return err
+				}
+			}
+			geometry.Ordinates = append(geometry.Ordinates, value)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return geometry, nil
+}
+
+// ociNumberArray calls each with the elements of a VARRAY of NUMBER in order, a nil number for null elements
+func (conn *Conn) ociNumberArray(array *C.OCIArray, each func(number *C.OCINumber) error) error {
+	collection := (*C.OCIColl)(unsafe.Pointer(array))
+	var size C.sb4
+	result := C.OCICollSize(conn.env, conn.errHandle, collection, &size)
+	err := conn.getError(result)
+	if err != nil {
+		return err
+	}
+
+	for index := C.sb4(0); index < size; index++ {
+		var exists C.boolean
+		var element unsafe.Pointer
+		var elementIndicator unsafe.Pointer
+		result = C.OCICollGetElem(conn.env, conn.errHandle, collection, index, &exists, &element, &elementIndicator)
+		err = conn.getError(result)
+		if err != nil {
+			return err
+		}
+		if exists == 0 || element == nil || (elementIndicator != nil && *(*C.OCIInd)(elementIndicator) == C.OCI_IND_NULL) {
+			err = each(nil)
+		} else {
+			err = each((*C.OCINumber)(element))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ociNumberToInt64 converts an OCINumber to an int64
+func (conn *Conn) ociNumberToInt64(number *C.OCINumber) (int64, error) {
+	var value C.sb8
+	result := C.OCINumberToInt(conn.errHandle, number, C.sizeof_sb8, C.OCI_NUMBER_SIGNED, unsafe.Pointer(&value))
+	err := conn.getError(result)
+	if err != nil {
+		return 0, err
+	}
+	return int64(value), nil
+}
+
+// ociNumberToFloat64 converts an OCINumber to a float64
+func (conn *Conn) ociNumberToFloat64(number *C.OCINumber) (float64, error) {
+	var value C.double
+	result := C.OCINumberToReal(conn.errHandle, number, C.sizeof_double, unsafe.Pointer(&value))
+	err := conn.getError(result)
+	if err != nil {
+		return 0, err
+	}
+	return float64(value), nil
+}
@@ -0,0 +1,174 @@
+package oci8
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestSelectSDOGeometry tests selecting SDO_GEOMETRY values
+func TestSelectSDOGeometry(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	rows, err := TestDB.QueryContext(ctx, `select sdo_geometry(2001, 8307, sdo_point_type(-122.4, 37.8, null), null, null),
+		sdo_geometry(2003, null, null, sdo_elem_info_array(1, 1003, 3), sdo_ordinate_array(1, 1, 5, 7)),
+		cast(null as sdo_geometry)
+		from dual`)
+	cancel()
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal("column types error:", err)
+	}
+	if columnTypes[0].DatabaseTypeName() != "SDO_GEOMETRY" {
+		t.Errorf("database type name - expected: %v - received: %v", "SDO_GEOMETRY", columnTypes[0].DatabaseTypeName())
+	}
+	if columnTypes[0].ScanType() != reflect.TypeOf(&SDOGeometry{}) {
+		t.Errorf("scan type - expected: %v - received: %v", reflect.TypeOf(&SDOGeometry{}), columnTypes[0].ScanType())
+	}
+
+	if !rows.Next() {
+		t.Fatal("no rows:", rows.Err())
+	}
+	var point SDOGeometry
+	var polygon *SDOGeometry
+	var null *SDOGeometry
+	err = rows.Scan(&point, &polygon, &null)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+
+	if point.GType != 2001 || point.SRID != 8307 || point.Point == nil || point.ElemInfo != nil || point.Ordinates != nil {
+		t.Fatalf("point - received: %+v", point)
+	}
+	if point.Point.X != -122.4 || point.Point.Y != 37.8 || !math.IsNaN(point.Point.Z) {
+		t.Errorf("point - expected: %v - received: %+v", "-122.4 37.8 NaN", *point.Point)
+	}
+
+	expected := &SDOGeometry{GType: 2003, ElemInfo: []int64{1, 1003, 3}, Ordinates: []float64{1, 1, 5, 7}}
+	if !reflect.DeepEqual(polygon, expected) {
+		t.Errorf("polygon - expected: %+v - received: %+v", expected, polygon)
+	}
+
+	if null != nil {
+		t.Errorf("null - expected: %v - received: %+v", nil, null)
+	}
+
+	err = rows.Close()
+	if err != nil {
+		t.Fatal("close error:", err)
+	}
+}
+
+// TestDestructiveSDOGeometry checks storing geometries built from bound values in a table and fetching them back
+func TestDestructiveSDOGeometry(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "SDO_GEOMETRY_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( ID INTEGER, SHAPE SDO_GEOMETRY )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	err = testExecRows(t, "insert into "+tableName+" ( ID, SHAPE ) values ( :1, sdo_geometry(2001, :2, sdo_point_type(:3, :4, null), null, null) )",
+		[][]interface{}{{1, 4326, -122.4, 37.8}})
+	if err != nil {
+		t.Fatal("insert point error:", err)
+	}
+	err = testExecRows(t, "insert into "+tableName+" ( ID, SHAPE ) values ( :1,"+
+		" sdo_geometry(2003, null, null, sdo_elem_info_array(1, 1003, 1), sdo_ordinate_array(:2, :3, :4, :5, :6, :7, :8, :9)) )",
+		[][]interface{}{{2, 0, 0, 4, 0, 4, 3, 0, 0}})
+	if err != nil {
+		t.Fatal("insert polygon error:", err)
+	}
+	err = testExecRows(t, "insert into "+tableName+" ( ID, SHAPE ) values ( :1, null )", [][]interface{}{{3}})
+	if err != nil {
+		t.Fatal("insert null error:", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	var point SDOGeometry
+	err = TestDB.QueryRowContext(ctx, "select SHAPE from "+tableName+" where ID = :1", 1).Scan(&point)
+	if err != nil {
+		t.Fatal("select point error:", err)
+	}
+	if point.GType != 2001 || point.SRID != 4326 || point.Point == nil || point.Point.X != -122.4 || point.Point.Y != 37.8 {
+		t.Errorf("point - expected: %v - received: %+v", "2001 4326 -122.4 37.8", point)
+	}
+
+	var polygon *SDOGeometry
+	err = TestDB.QueryRowContext(ctx, "select SHAPE from "+tableName+" where ID = :1", 2).Scan(&polygon)
+	if err != nil {
+		t.Fatal("select polygon error:", err)
+	}
+	expected := &SDOGeometry{GType: 2003, ElemInfo: []int64{1, 1003, 1}, Ordinates: []float64{0, 0, 4, 0, 4, 3, 0, 0}}
+	if !reflect.DeepEqual(polygon, expected) {
+		t.Errorf("polygon - expected: %+v - received: %+v", expected, polygon)
+	}
+
+	rows, err := TestDB.QueryContext(ctx, "select ID, SHAPE from "+tableName+" order by ID")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+	count := 0
+	for rows.Next() {
+		var id int64
+		var shape *SDOGeometry
+		err = rows.Scan(&id, &shape)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		if (id == 3) != (shape == nil) {
+			t.Errorf("row %v - received: %+v", id, shape)
+		}
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal("rows error:", err)
+	}
+	if count != 3 {
+		t.Errorf("count - expected: %v - received: %v", 3, count)
+	}
+}
+
+// TestSDOGeometryScan tests scanning an SDOGeometry
+func TestSDOGeometryScan(t *testing.T) {
+	t.Parallel()
+
+	src := &SDOGeometry{GType: 3001, SRID: 4326, Point: &SDOPoint{X: 1, Y: 2, Z: 3}}
+	var geometry SDOGeometry
+	err := geometry.Scan(src)
+	if err != nil {
+		t.Fatal("Scan error:", err)
+	}
+	if !reflect.DeepEqual(&geometry, src) {
+		t.Errorf("Scan - expected: %+v - received: %+v", src, geometry)
+	}
+	if geometry.Dimensions() != 3 {
+		t.Errorf("Dimensions - expected: %v - received: %v", 3, geometry.Dimensions())
+	}
+
+	for _, src := range []interface{}{nil, (*SDOGeometry)(nil), "POINT (1 2)"} {
+		err = geometry.Scan(src)
+		if err == nil {
+			t.Errorf("Scan %#v - expected: error - received: nil", src)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import "C"
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"io/ioutil"
 	"log"
@@ -26,6 +27,20 @@ const (
 	lobBufferSize      = 4000
 	useOCISessionBegin = true
 	sizeOfNilPointer   = unsafe.Sizeof(unsafe.Pointer(nil))
+	// maxBindCount is the max number of bind variables allowed in a statement
+	maxBindCount = 65535
+	// longPieceSize is the size of the pieces when fetching LONG columns piecewise
+	longPieceSize = 65536
+	// lobChunkSize is the size of the chunks read by a Lob
+	lobChunkSize = 262144
+	// defaultHealthTimeout is the timeout of the health query when the connector does not set one
+	defaultHealthTimeout = 2 * time.Second
+	// validPingInterval is the min time between the validations of IsValid and ResetSession
+	validPingInterval = time.Second
+	// defaultPrefetchTarget is the target bytes of the rows prefetched in a round trip, see the prefetch_target parameter
+	defaultPrefetchTarget = 1048576
+	// maxTunedPrefetchRows is the max prefetch rows set for the prefetch target
+	maxTunedPrefetchRows = 100000
 )
 
 type (
@@ -36,11 +51,44 @@ type (
 		Password             string
 		prefetchRows         C.ub4
 		prefetchMemory       C.ub4
+		prefetchSet          bool
+		prefetchTarget       int64
+		prefetchTargetSet    bool
 		timeLocation         *time.Location
 		transactionMode      C.ub4
 		enableQMPlaceholders bool
 		operationMode        C.ub4
 		stmtCacheSize        C.ub4
+		tempLobCache         bool
+		tempTablespace       string
+		lobPrefetchSize      C.ub4
+		fetchArraySize       C.ub4
+		callTimeoutOff       bool
+		floatDecimal         bool
+		floatPrecision       int
+		dateRangeClamp       bool
+		numberStrings        bool
+		intervalInt64        bool
+		identityReturning    bool
+		debugConcurrentUse   bool
+		charset              string
+		utf16                bool
+		ignoreEnv            bool
+		tnsAdmin             string
+		oracleHome           string
+		nlsLanguage          string
+		nlsTerritory         string
+		sessionTimeZone      string
+		ltzLocation          *time.Location
+		maxRows              int64
+		closeTimeout         time.Duration
+		poolMin              C.ub4
+		poolMax              C.ub4
+		poolIncrement        C.ub4
+		readOnly             bool
+		ddlInTx              int
+		groupCommitCount     int
+		groupCommitInterval  time.Duration
 	}
 
 	// DriverStruct is Oracle driver struct
@@ -48,12 +96,71 @@ type (
 		// Logger is used to log connection ping errors, defaults to discard
 		// To log set it to something like: log.New(os.Stderr, "oci8 ", log.Ldate|log.Ltime|log.LUTC|log.Lshortfile)
 		Logger *log.Logger
+		// Hooks are called around statement execution on connections opened by this driver
+		Hooks Hooks
+		// Converter converts result values and bool binds on connections opened by this driver, nil keeps the values unchanged
+		Converter Converter
+		// ErrorTranslations translates the Oracle errors of connections opened by this driver, nil returns them as is
+		ErrorTranslations *ErrorTranslations
+		// Clock is the source of time of the timers of connections opened by this driver, nil uses the system clock
+		Clock Clock
+
+		leaks leakTracker // allocations of the connections opened by this driver, for the LeakReport hook
 	}
 
-	// Connector is the sql driver connector
+	// Connector is the sql driver connector, created with NewConnector, NewConnectorWithOptions, or OpenConnector.
+	// The DSN is parsed once, each connection uses a copy of it.
+	// The fields that are also DSN parameters, like MaxRows and ReadOnly, override the DSN parameter when set.
+	// Their zero values keep the DSN parameter, so ReadOnly false does not turn off a read_only=true DSN parameter.
 	Connector struct {
 		// Logger is used to log connection ping errors
 		Logger *log.Logger
+		// Hooks are called around statement execution on connections opened by this connector
+		Hooks Hooks
+		// Converter converts result values and bool binds on connections opened by this connector, nil keeps the values unchanged
+		Converter Converter
+		// ErrorTranslations translates the Oracle errors of connections opened by this connector, nil returns them as is
+		ErrorTranslations *ErrorTranslations
+		// Clock is the source of time of the timers of connections opened by this connector, nil uses the system clock
+		Clock Clock
+		// HealthQuery is run in place of OCIPing to validate connections, like a PDB specific sanity query,
+		// by Ping, IsValid, and ResetSession. The connection is bad when the query errors or returns no rows.
+		HealthQuery string
+		// HealthTimeout is the timeout of HealthQuery, defaults to 2 seconds
+		HealthTimeout time.Duration
+		// ResetStatement is run by ResetSession before a connection that was used is used again, to clear the session state,
+		// like begin dbms_session.modify_package_state(dbms_session.reinitialize); end;
+		ResetStatement string
+		// MaxRows limits the number of rows a query can return, fetching more rows returns ErrTooManyRows.
+		// A 0 means unlimited rows. Can be overridden per query with WithMaxRows.
+		MaxRows int64
+		// CloseTimeout limits how long closing a connection or statement waits for the database, 0 means no limit.
+		// Connections not closed in time are freed in the background when the database responds,
+		// the connection of a statement not closed in time is discarded.
+		CloseTimeout time.Duration
+		// ReadOnly refuses to prepare statements other than queries, PL/SQL blocks, and calls, like INSERT, UPDATE, and DDL,
+		// with a *ReadOnlyError. PL/SQL blocks and calls are not checked.
+		ReadOnly bool
+		// GroupCommitCount commits statements executed outside a transaction every GroupCommitCount statements,
+		// instead of each statement. Statements that Exec returned without error are lost when the connection fails
+		// before the group is committed, see the group commit documentation. 0 commits each statement.
+		GroupCommitCount int
+		// GroupCommitInterval commits statements executed outside a transaction at the first statement executed
+		// GroupCommitInterval after the first uncommitted statement. It has the same durability caveats as GroupCommitCount.
+		GroupCommitInterval time.Duration
+		// IPFormat is how net.IP bind values are bound, as text or as RAW, defaults to the bytes of the net.IP like database/sql.
+		// Scan with ScanIP.
+		IPFormat IPFormat
+		// RawUUIDs binds the values of [16]byte based types as RAW(16), including types with a Value method like uuid.UUID
+		// that return the text form, which does not convert to RAW. Scan with ScanUUID or GUID.
+		RawUUIDs bool
+		// YesNoBools returns the Y, N, 1, and 0 values of one character text columns as bool, and binds bools as Y and N,
+		// for CHAR(1) flag columns
+		YesNoBools bool
+
+		dsn      *DSN               // parsed once, copied for each connection
+		counters connectionCounters // statistics of the connections, see Stats
+		leaks    leakTracker        // allocations of the connections, for the LeakReport hook
 	}
 
 	// Conn is Oracle connection
@@ -66,6 +173,7 @@ type (
 		txHandle             *C.OCITrans
 		prefetchRows         C.ub4
 		prefetchMemory       C.ub4
+		prefetchTarget       int64 // target bytes of the rows prefetched in a round trip, 0 when the prefetch rows are not tuned
 		transactionMode      C.ub4
 		operationMode        C.ub4
 		stmtCacheSize        C.ub4
@@ -73,7 +181,58 @@ type (
 		enableQMPlaceholders bool
 		closed               bool
 		timeLocation         *time.Location
+		ltzLocation          *time.Location      // location of TIMESTAMP WITH LOCAL TIME ZONE values, nil uses timeLocation
+		ipFormat             IPFormat            // how net.IP bind values are bound
+		rawUUIDs             bool                // bind [16]byte based types as RAW(16)
+		yesNoBools           bool                // map one character Y/N text columns and bool binds
+		counters             *connectionCounters // statistics of the connector, nil when not opened by a connector
+		leaks                *leakTracker        // allocations of the connector or driver, for the LeakReport hook
+		identityReturning    bool                // add RETURNING of the identity column to inserts, see identity_returning
+		identityUnsupported  bool                // the server has no ALL_TAB_IDENTITY_COLS, before 12c
+		identityColumns      map[string]string   // identity column by OWNER.TABLE, empty for tables without one
 		logger               *log.Logger
+		tempLobCache         bool
+		tempLobStats         TempLobStats
+		lobPrefetchSize      C.ub4
+		fetchArraySize       C.ub4
+		callTimeouts         bool  // OCI_ATTR_CALL_TIMEOUT is set from the context deadlines, see call_timeout
+		callTimeout          C.ub4 // the call timeout set on the service context in milliseconds, 0 for none
+		floatDecimal         bool
+		floatPrecision       int
+		dateRangeClamp       bool
+		numberStrings        bool
+		intervalInt64        bool // INTERVAL values are returned as int64 nanoseconds and months
+		hooks                Hooks
+		converter            Converter
+		errorTranslations    *ErrorTranslations
+		clock                Clock
+		healthQuery          string
+		healthTimeout        time.Duration
+		resetStatement       string
+		debugConcurrentUse   bool
+		maxRows              int64
+		closeTimeout         time.Duration
+		cleanups             sync.WaitGroup        // statements being released in the background
+		handlesMutex         sync.Mutex            // guards OCIBreak of a timed out close from the handles being freed
+		pinned               bool                  // set by PinSession, bad connection errors are returned as SessionLostError
+		sessionPool          *sessionPool          // set when the session is from an OCI session pool
+		sessionLost          bool                  // set on bad connection errors, a lost pool session is dropped on close
+		readOnly             bool                  // refuse statements that change data or the schema
+		ddlInTx              int                   // ddlInTxAllow, ddlInTxWarn, or ddlInTxError for DDL executed in a transaction
+		groupCommitCount     int                   // commit every group commit count statements executed outside a transaction
+		groupCommitInterval  time.Duration         // commit the group commit statements after the interval
+		pendingCommits       int                   // statements executed outside a transaction not committed yet
+		pendingSince         time.Time             // time of the first statement not committed yet
+		validated            time.Time             // time of the last successful validation of IsValid or ResetSession
+		priorClientInfo      string                // CLIENT_INFO restored after the statements with a request ID
+		priorClientInfoRead  bool                  // priorClientInfo was read from the session
+		maxNameLength        int                   // max length of identifiers and bind names, read on first use, see identifierLength
+		utf16                bool                  // the environment is OCI_UTF16ID, text is converted between UTF-16 and UTF-8
+		envCharsetID         C.ub2                 // character set of the environment when names are converted to UTF-8, see goName
+		sdoGeometryTDO       *C.OCIType            // type descriptor of MDSYS.SDO_GEOMETRY, pinned for the session on first use
+		collectionTDOs       map[string]*C.OCIType // type descriptors of the SYS collection types of list binds, pinned for the session on first use
+		tempLobs             map[*TempLob]struct{} // TempLobs created by CreateTempLob not freed yet
+		tempLobsMutex        sync.Mutex            // guards tempLobs, as statements are released in the background
 	}
 
 	// Tx is Oracle transaction
@@ -89,13 +248,50 @@ type (
 		ctx         context.Context
 		cacheKey    string // if statement caching is enabled, this is the key for this statement into the cache
 		releaseMode C.ub4
+		queryText   string
+		fingerprint string              // NormalizeQuery of queryText, set on first use when Hooks.NormalizeQuery is true
+		hookText    string              // queryText shortened by Hooks.QueryText, set on first use
+		hookArgs    []driver.NamedValue // the bind values of the execute for the hooks, set with Hooks.IncludeArgs
+		// plsqlCallBinds are the binds of a PL/SQL call with the describe information of their arguments
+		plsqlCallBinds []plsqlBind
+		plsqlDescribed bool
+		guard          useGuard
+		tempLobs       []*TempLob   // bound TempLobs, freed when the statement is closed
+		options        *StmtOptions // the WithStmtOptions of the prepare context, nil if not set
+		// identityReturning is set when the statement was prepared with the RETURNING clause of identity_returning,
+		// identity is the value returned by the last execute
+		identityReturning bool
+		identity          int64
+		// textOffset is the length of the request ID comment of WithRequestID prepended to queryText in the prepared text
+		textOffset int
 	}
 
-	// Rows is Oracle rows
+	// Rows is Oracle rows.
+	// A SYS_REFCURSOR OUT parameter is returned as Rows by binding sql.Out{Dest: &rows}, with rows a *Rows.
+	// Read the rows with Next until it returns io.EOF, then Close them to free the cursor.
 	Rows struct {
-		stmt    *Stmt
-		defines []defineStruct
-		closed  bool
+		stmt            *Stmt
+		defines         []defineStruct
+		closed          bool
+		longPieces      LongPieceFunc
+		guard           useGuard
+		maxRows         int64 // 0 means unlimited rows
+		rowCount        int64
+		cursor          bool           // rows of a REF CURSOR OUT bind, the statement handle is freed on close
+		parent          *Stmt          // the PL/SQL statement of implicit results, stmt is the current implicit result
+		implicitResults int            // the number of implicit results after the current one
+		lobReaders      bool           // LOB values are returned as *Lob
+		rawBytes        bool           // character values are returned as []byte of the define buffer
+		prefetchTarget  int64          // target bytes of the rows prefetched in a round trip, 0 when the prefetch rows are not tuned
+		scanValues      []driver.Value // the values of ScanInto, reused for each row
+
+		cancel context.CancelFunc // cancels the StmtOptions Timeout context of the query, called on close
+
+		// array fetch, see fetch_array_size
+		fetchArraySize int64 // rows fetched into the define arrays by each fetch, 0 when fetching a row at a time
+		arrayRows      int64 // rows in the define arrays from the last fetch
+		arrayRow       int64 // current row in the define arrays
+		arrayDone      bool  // the last fetch returned the last rows
 	}
 
 	// Result is Oracle result
@@ -105,6 +301,11 @@ type (
 		rowid           string
 		rowidErr        error
 		stmt            *Stmt
+		// identityReturning is set for the results of connections with identity_returning,
+		// LastInsertId returns identity or identityErr
+		identityReturning bool
+		identity          int64
+		identityErr       error
 	}
 
 	defineStruct struct {
@@ -115,7 +316,29 @@ type (
 		length       *C.ub2
 		indicator    *C.sb2
 		defineHandle *C.OCIDefine
-		subDefines   []defineStruct
+		nestedRows   *Rows // rows of the nested cursor of the current row of a CURSOR expression column
+		objectConn   *Conn // connection of the object cache of SQLT_NTY object defines, which frees the object instance
+		skip         bool  // column is not wanted, only the indicator is fetched
+		piecewise    bool  // column is fetched in pieces with OCI_DYNAMIC_FETCH
+		pieceTotal   int64 // total bytes of the pieces fetched for the current row
+		charsetID    C.ub2 // character set of the column, 0 when not character data
+		charsetForm  C.ub1 // SQLCS_IMPLICIT or SQLCS_NCHAR, 0 when not character data
+		collationID  C.ub4 // collation of the column, 0 before Oracle 12.2
+		// define arrays of array fetch, pbuf, length, and indicator point to the current row in them
+		arraySize      int64
+		arrayBuf       unsafe.Pointer
+		arrayLength    *C.ub2
+		arrayIndicator *C.sb2
+		// describe information of the column, for the ColumnType methods
+		columnType   C.ub2  // data type of the column as described, dataType is the type it is defined as
+		dataSize     C.ub4  // max size in bytes
+		charSize     C.ub2  // max length in characters of character columns
+		precision    C.sb2  // precision of NUMBER columns
+		scale        C.sb1  // scale of NUMBER columns, -127 for FLOAT
+		nullable     bool   // column allows null
+		typeName     string // name of the object type of SQLT_NTY columns, like SDO_GEOMETRY
+		typeSchema   string // schema of the object type of SQLT_NTY columns, like MDSYS
+		vectorFormat C.ub1  // value format of VECTOR columns, 0 when flexible or not known
 	}
 
 	bindStruct struct {
@@ -126,6 +349,14 @@ type (
 		indicator  *C.sb2
 		bindHandle *C.OCIBind
 		out        sql.Out
+		// maxArrayLength and currentLength are the max and current number of elements of a PL/SQL array bind
+		maxArrayLength C.ub4
+		currentLength  *C.ub4
+		// borrowed is set when the descriptor in pbuf is owned by a bound value, like the LOB locator of a TempLob,
+		// only the memory of pbuf is freed with the binds, not the descriptor
+		borrowed bool
+		// tdo is the type descriptor of the collection of a SQLT_NTY list bind, bound with OCIBindObject
+		tdo *C.OCIType
 	}
 )
 
@@ -145,24 +376,53 @@ var (
 
 	// ErrNoRowid is result has no rowid
 	ErrNoRowid = errors.New("result has no rowid")
+	// ErrNoIdentity is returned by LastInsertId with identity_returning when the execute did not return an identity value
+	ErrNoIdentity = errors.New("result has no identity value")
+
+	// ErrLobClosed is returned when reading a Lob after the rows moved to the next row or were closed
+	ErrLobClosed = errors.New("LOB is no longer valid, the rows moved to the next row or were closed")
+
+	// ErrTooManyBinds is returned when a statement has more bind parameters than Oracle allows
+	ErrTooManyBinds = errors.New("too many bind parameters, max is " + strconv.Itoa(maxBindCount))
+	// ErrInvalidBindName is returned when a bind parameter name would cause ORA-01745: invalid host/bind variable name
+	ErrInvalidBindName = errors.New("invalid bind parameter name")
 
 	phre           = regexp.MustCompile(`\?`)
 	defaultCharset = C.ub2(0)
 
 	typeNil       = reflect.TypeOf(nil)
 	typeString    = reflect.TypeOf("a")
+	typeBool      = reflect.TypeOf(false)
 	typeSliceByte = reflect.TypeOf([]byte{})
 	typeInt64     = reflect.TypeOf(int64(1))
 	typeFloat64   = reflect.TypeOf(float64(1))
 	typeTime      = reflect.TypeOf(time.Time{})
+	typeFloat32s  = reflect.TypeOf([]float32{})
+	typeFloat64s  = reflect.TypeOf([]float64{})
+	typeSQLRows   = reflect.TypeOf(&sql.Rows{})
+	typeLob       = reflect.TypeOf(&Lob{})
+	typeGeometry  = reflect.TypeOf(&SDOGeometry{})
+	typeDuration  = reflect.TypeOf(time.Duration(0))
+	typeYearMonth = reflect.TypeOf(YearMonth{})
 
 	// Driver is the sql driver
 	Driver = &DriverStruct{
 		Logger: log.New(ioutil.Discard, "", 0),
 	}
 
+	// InstrumentedDriver is the sql driver registered as oci8-instrumented.
+	// It behaves the same as Driver until its Hooks are set,
+	// so instrumentation can be switched on by only changing the driver name passed to sql.Open.
+	InstrumentedDriver = &DriverStruct{
+		Logger: log.New(ioutil.Discard, "", 0),
+	}
+
 	timeLocations []*time.Location
 
+	// charsetIDs caches the character set ids of character set names
+	charsetIDs      = make(map[string]C.ub2)
+	charsetIDsMutex sync.Mutex
+
 	byteBufferPool = sync.Pool{
 		New: func() interface{} {
 			return make([]byte, lobBufferSize)
@@ -172,6 +432,7 @@ var (
 
 func init() {
 	sql.Register("oci8", Driver)
+	sql.Register("oci8-instrumented", InstrumentedDriver)
 
 	// set defaultCharset to AL32UTF8
 	var envP *C.OCIEnv
@@ -184,7 +445,7 @@ func init() {
 	nlsLang := cString("AL32UTF8")
 	defaultCharset = C.OCINlsCharSetNameToId(unsafe.Pointer(*envPP), (*C.oratext)(nlsLang))
 	C.free(unsafe.Pointer(nlsLang))
-	C.OCIHandleFree(unsafe.Pointer(*envPP), C.OCI_HTYPE_ENV)
+	ociHandleFree(unsafe.Pointer(*envPP), C.OCI_HTYPE_ENV)
 
 	// build timeLocations: GMT -12 to 14
 	timeLocationNames := []string{"Etc/GMT+12", "Pacific/Pago_Pago", // -12 to -11
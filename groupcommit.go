@@ -0,0 +1,107 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+/*
+Group commit
+
+By default a statement executed outside a transaction is committed by the execute itself,
+so each single row insert of a log style ingestion waits for its own commit, which waits for the redo to be written.
+With group_commit_count or group_commit_interval set, statements executed outside a transaction are not committed
+by the execute. Instead the connection commits every group_commit_count statements, or at the first statement
+executed group_commit_interval after the first uncommitted statement, whichever comes first.
+
+Group commit trades durability for throughput. Until the group is committed:
+
+	- Exec returns success for statements that are not committed yet.
+	  They are lost, without an error to the statements that made them, when the connection or database fails.
+	- Other sessions do not see the uncommitted rows, and the rows stay locked.
+	- There is no background timer, the interval is only checked when a statement is executed.
+	  An idle connection keeps its uncommitted statements until its next statement, transaction, or close.
+
+Use Conn.CommitPending, with sql.Conn.Raw, to commit the group, for example before reporting the rows as stored.
+Beginning a transaction, executing a Batch outside a transaction, resetting the connection for its next use,
+and closing the connection commit the group first. Queries executed outside a transaction commit on success,
+as without group commit, which commits the group with them.
+An error committing the group is returned by the statement, Begin, or Close that committed it.
+When the connection fails with statements not committed, the call that finds it returns a *GroupCommitLostError
+in place of driver.ErrBadConn, so database/sql does not retry the call on another connection without reporting the loss.
+Only use group commit for data that can be lost or written again, like logs and metrics.
+*/
+
+// groupCommitEnabled returns true when statements outside a transaction are committed in groups
+func (conn *Conn) groupCommitEnabled() bool {
+	return conn.groupCommitCount > 0 || conn.groupCommitInterval > 0
+}
+
+// executeMode returns the OCIStmtExecute mode of a statement, which commits on success
+// when outside a transaction, unless the commit is left to the group commit
+func (conn *Conn) executeMode() C.ub4 {
+	if conn.inTransaction || conn.groupCommitEnabled() {
+		return C.OCI_DEFAULT
+	}
+	return C.OCI_COMMIT_ON_SUCCESS
+}
+
+// queryExecuteMode returns the OCIStmtExecute mode of a query, which commits on success when outside a transaction,
+// committing the statements of the group commit with it, see queryExecuted
+func (conn *Conn) queryExecuteMode() C.ub4 {
+	if conn.inTransaction {
+		return C.OCI_DEFAULT
+	}
+	return C.OCI_COMMIT_ON_SUCCESS
+}
+
+// queryExecuted clears the statements of the group commit committed by a query executed outside a transaction
+func (conn *Conn) queryExecuted() {
+	if !conn.inTransaction {
+		conn.pendingCommits = 0
+	}
+}
+
+// groupCommitLost returns a *GroupCommitLostError with the err of the failed connection
+// for the statements of the group commit lost with the session
+func (conn *Conn) groupCommitLost(err error) error {
+	lostErr := &GroupCommitLostError{Statements: conn.pendingCommits, Err: err}
+	conn.pendingCommits = 0
+	conn.logger.Print(lostErr)
+	return lostErr
+}
+
+// groupCommitExecuted counts a statement executed outside a transaction,
+// and commits the group when it has group_commit_count statements or is older than group_commit_interval
+func (conn *Conn) groupCommitExecuted() error {
+	if conn.inTransaction || !conn.groupCommitEnabled() {
+		return nil
+	}
+
+	now := conn.now()
+	if conn.pendingCommits == 0 {
+		conn.pendingSince = now
+	}
+	conn.pendingCommits++
+
+	if (conn.groupCommitCount > 0 && conn.pendingCommits >= conn.groupCommitCount) ||
+		(conn.groupCommitInterval > 0 && now.Sub(conn.pendingSince) >= conn.groupCommitInterval) {
+		return conn.commitPending()
+	}
+	return nil
+}
+
+// commitPending commits the statements of the group commit that are not committed yet
+func (conn *Conn) commitPending() error {
+	if conn.pendingCommits == 0 {
+		return nil
+	}
+	// the count is cleared after the commit, so a failed connection reports the lost statements
+	err := conn.getError(C.OCITransCommit(conn.svc, conn.errHandle, 0))
+	conn.pendingCommits = 0
+	return err
+}
+
+// CommitPending commits the statements executed outside a transaction that the group commit has not committed yet.
+// Does nothing when group commit is not enabled or all the statements are committed.
+func (conn *Conn) CommitPending() error {
+	return conn.commitPending()
+}
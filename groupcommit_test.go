@@ -0,0 +1,116 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+)
+
+// TestGroupCommitLost tests a bad connection with statements of the group commit not committed reports them as lost
+func TestGroupCommitLost(t *testing.T) {
+	t.Parallel()
+
+	conn := &Conn{logger: log.New(ioutil.Discard, "", 0), groupCommitCount: 10, pendingCommits: 3}
+	err := conn.badConnError("ORA-03113: end-of-file on communication channel")
+	var lostErr *GroupCommitLostError
+	if !errors.As(err, &lostErr) || lostErr.Statements != 3 {
+		t.Fatalf("badConnError - expected: %v - received: %v", "3 group commit statements lost", err)
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		t.Errorf("GroupCommitLostError errors.Is ErrBadConn - expected: %v - received: %v", false, true)
+	}
+	if conn.pendingCommits != 0 || !conn.sessionLost {
+		t.Errorf("pending commits, session lost - expected: %v, %v - received: %v, %v", 0, true, conn.pendingCommits, conn.sessionLost)
+	}
+
+	err = conn.badConnError("ORA-03113: end-of-file on communication channel")
+	if err != driver.ErrBadConn {
+		t.Errorf("badConnError - expected: %v - received: %v", driver.ErrBadConn, err)
+	}
+}
+
+// TestDestructiveGroupCommit checks statements outside a transaction are committed in groups
+func TestDestructiveGroupCommit(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "GROUP_COMMIT_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	db := testGetDB("?group_commit_count=3")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	// the count is selected by TestDB, another session, which only sees committed rows
+	committed := func() int64 {
+		var count int64
+		err := TestDB.QueryRowContext(ctx, "select count(1) from "+tableName).Scan(&count)
+		if err != nil {
+			t.Fatal("select count error:", err)
+		}
+		return count
+	}
+	insert := func(a int) {
+		_, err := conn.ExecContext(ctx, "insert into "+tableName+" ( A ) values ( :1 )", a)
+		if err != nil {
+			t.Fatal("insert error:", err)
+		}
+	}
+
+	insert(1)
+	insert(2)
+	if count := committed(); count != 0 {
+		t.Errorf("committed rows after 2 inserts - expected: %v - received: %v", 0, count)
+	}
+	insert(3)
+	if count := committed(); count != 3 {
+		t.Errorf("committed rows after 3 inserts - expected: %v - received: %v", 3, count)
+	}
+
+	insert(4)
+	err = conn.Raw(func(driverConn interface{}) error {
+		return driverConn.(*Conn).CommitPending()
+	})
+	if err != nil {
+		t.Fatal("commit pending error:", err)
+	}
+	if count := committed(); count != 4 {
+		t.Errorf("committed rows after commit pending - expected: %v - received: %v", 4, count)
+	}
+
+	insert(5)
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("begin error:", err)
+	}
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatal("rollback error:", err)
+	}
+	if count := committed(); count != 5 {
+		t.Errorf("committed rows after begin - expected: %v - received: %v", 5, count)
+	}
+}
@@ -0,0 +1,72 @@
+package oci8
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentUseError is returned when a Stmt or Rows is used by a goroutine while another goroutine is using it.
+// OCI handles are not safe for concurrent use, so the call is refused instead of corrupting the C state.
+type ConcurrentUseError struct {
+	// Object is the object that was used concurrently, Stmt or Rows
+	Object string
+	// Method is the method that was refused
+	Method string
+	// Stack is the stack of the refused call, only set when debug_concurrent_use is enabled
+	Stack string
+	// OtherStack is the stack of the call that was using the object, only set when debug_concurrent_use is enabled
+	OtherStack string
+}
+
+// Error returns the concurrent use error string
+func (concurrentUseError *ConcurrentUseError) Error() string {
+	message := "concurrent use of " + concurrentUseError.Object + " by " + concurrentUseError.Method +
+		" while it is in use by another goroutine"
+	if concurrentUseError.Stack != "" {
+		message += "\nrefused call:\n" + concurrentUseError.Stack
+	}
+	if concurrentUseError.OtherStack != "" {
+		message += "\ncall in use:\n" + concurrentUseError.OtherStack
+	}
+	return message
+}
+
+// useGuard detects concurrent use of a Stmt or Rows
+type useGuard struct {
+	inUse int32
+	mutex sync.Mutex
+	stack []byte // stack of the call using the object, only kept when debugging
+}
+
+// enter marks the object as in use, returns a *ConcurrentUseError if it is already in use.
+// When debugStacks is true, the stacks of both calls are added to the error.
+func (guard *useGuard) enter(object string, method string, debugStacks bool) error {
+	if !atomic.CompareAndSwapInt32(&guard.inUse, 0, 1) {
+		concurrentUseError := &ConcurrentUseError{Object: object, Method: method}
+		if debugStacks {
+			concurrentUseError.Stack = string(debug.Stack())
+			guard.mutex.Lock()
+			concurrentUseError.OtherStack = string(guard.stack)
+			guard.mutex.Unlock()
+		}
+		return concurrentUseError
+	}
+
+	if debugStacks {
+		guard.mutex.Lock()
+		guard.stack = debug.Stack()
+		guard.mutex.Unlock()
+	}
+	return nil
+}
+
+// exit marks the object as no longer in use
+func (guard *useGuard) exit(debugStacks bool) {
+	if debugStacks {
+		guard.mutex.Lock()
+		guard.stack = nil
+		guard.mutex.Unlock()
+	}
+	atomic.StoreInt32(&guard.inUse, 0)
+}
@@ -0,0 +1,49 @@
+package oci8
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestUseGuard tests concurrent use detection
+func TestUseGuard(t *testing.T) {
+	t.Parallel()
+
+	var guard useGuard
+	err := guard.enter("Rows", "Next", false)
+	if err != nil {
+		t.Fatal("enter error:", err)
+	}
+	err = guard.enter("Rows", "Close", false)
+	concurrentUseError, ok := err.(*ConcurrentUseError)
+	if !ok {
+		t.Fatalf("enter - expected: *ConcurrentUseError - received: %v", err)
+	}
+	if concurrentUseError.Object != "Rows" || concurrentUseError.Method != "Close" || concurrentUseError.Stack != "" || concurrentUseError.OtherStack != "" {
+		t.Errorf("concurrent use error - received: %+v", concurrentUseError)
+	}
+	guard.exit(false)
+
+	err = guard.enter("Stmt", "ExecContext", true)
+	if err != nil {
+		t.Fatal("enter error:", err)
+	}
+	err = guard.enter("Stmt", "Close", true)
+	concurrentUseError, ok = err.(*ConcurrentUseError)
+	if !ok {
+		t.Fatalf("enter - expected: *ConcurrentUseError - received: %v", err)
+	}
+	if !strings.Contains(concurrentUseError.Stack, "TestUseGuard") || !strings.Contains(concurrentUseError.OtherStack, "TestUseGuard") {
+		t.Errorf("concurrent use error stacks - received: %+v", concurrentUseError)
+	}
+	if !strings.HasPrefix(err.Error(), "concurrent use of Stmt by Close while it is in use by another goroutine") {
+		t.Errorf("error - received: %v", err)
+	}
+	guard.exit(true)
+
+	err = guard.enter("Stmt", "Close", true)
+	if err != nil {
+		t.Fatal("enter after exit error:", err)
+	}
+	guard.exit(true)
+}
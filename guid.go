@@ -0,0 +1,168 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidGUID is returned when a value can not be converted to a GUID
+var ErrInvalidGUID = errors.New("invalid GUID")
+
+// GUID is a 16 byte globally unique identifier stored in a RAW(16) column, like the values of SYS_GUID,
+// with the bytes in the order they are stored in the database.
+// A GUID can be bound and scanned, and the bytes are the RFC 4122 order of a UUID,
+// so converting to and from a UUID type, like [16]byte based types, keeps the bytes as they are:
+//
+//	id := oci8.GUID(uuid.New())
+//	_, err = db.ExecContext(ctx, "insert into orders (id) values (:1)", id)
+//
+// Use GUIDFromMixedEndian and MixedEndian for GUIDs written by .NET and Windows APIs,
+// which store the first three fields little endian.
+type GUID [16]byte
+
+// ParseGUID parses a GUID from 32 hexadecimal digits, like the RAWTOHEX of a SYS_GUID,
+// or the 36 character UUID text form, like 6ba7b810-9dad-11d1-80b4-00c04fd430c8. Case is ignored.
+func ParseGUID(text string) (GUID, error) {
+	var guid GUID
+	digits := text
+	if len(text) == 36 {
+		if text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+			return guid, ErrInvalidGUID
+		}
+		digits = text[:8] + text[9:13] + text[14:18] + text[19:23] + text[24:]
+	}
+	if len(digits) != 32 {
+		return guid, ErrInvalidGUID
+	}
+	_, err := hex.Decode(guid[:], []byte(digits))
+	if err != nil {
+		return guid, ErrInvalidGUID
+	}
+	return guid, nil
+}
+
+// GUIDFromMixedEndian returns the GUID of a .NET or Windows GUID byte array, like from Guid.ToByteArray,
+// which has the first three fields little endian
+func GUIDFromMixedEndian(mixed [16]byte) GUID {
+	return GUID(swapGUIDFields(mixed))
+}
+
+// MixedEndian returns the GUID as a .NET or Windows GUID byte array, with the first three fields little endian
+func (guid GUID) MixedEndian() [16]byte {
+	return swapGUIDFields(guid)
+}
+
+// swapGUIDFields reverses the bytes of the 4, 2, and 2 byte first fields, converting between RFC 4122 and mixed endian order
+func swapGUIDFields(guid [16]byte) [16]byte {
+	guid[0], guid[1], guid[2], guid[3] = guid[3], guid[2], guid[1], guid[0]
+	guid[4], guid[5] = guid[5], guid[4]
+	guid[6], guid[7] = guid[7], guid[6]
+	return guid
+}
+
+// String returns the GUID as 32 upper case hexadecimal digits, like RAWTOHEX
+func (guid GUID) String() string {
+	return strings.ToUpper(hex.EncodeToString(guid[:]))
+}
+
+// UUIDString returns the GUID in the lower case UUID text form, like 6ba7b810-9dad-11d1-80b4-00c04fd430c8
+func (guid GUID) UUIDString() string {
+	text := hex.EncodeToString(guid[:])
+	return text[:8] + "-" + text[8:12] + "-" + text[12:16] + "-" + text[16:20] + "-" + text[20:]
+}
+
+// Value implements driver.Valuer, binding the GUID as RAW
+func (guid GUID) Value() (driver.Value, error) {
+	return guid[:], nil
+}
+
+// Scan implements sql.Scanner, from RAW bytes or hexadecimal text in a form ParseGUID accepts.
+// Returns an error for null, scan into a *GUID pointer, like a **GUID, for nullable columns.
+func (guid *GUID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		if len(src) != len(guid) {
+			return fmt.Errorf("GUID needs %v bytes, received %v", len(guid), len(src))
+		}
+		copy(guid[:], src)
+		return nil
+	case string:
+		parsed, err := ParseGUID(src)
+		if err != nil {
+			return err
+		}
+		*guid = parsed
+		return nil
+	}
+	return fmt.Errorf("can not scan %T into GUID", src)
+}
+
+// FetchGUIDs returns count new SYS_GUID values in one round trip, like to assign the keys of a batch of rows before inserting them
+func FetchGUIDs(ctx context.Context, db *sql.DB, count int) ([]GUID, error) {
+	if count < 1 {
+		return nil, nil
+	}
+
+	rows, err := db.QueryContext(ctx, "select sys_guid() from dual connect by level <= :1", count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	guids := make([]GUID, 0, count)
+	for rows.Next() {
+		var guid GUID
+		err = rows.Scan(&guid)
+		if err != nil {
+			return nil, err
+		}
+		guids = append(guids, guid)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return guids, nil
+}
+
+// FetchSequenceValues returns count values of a sequence in one round trip, like to assign the keys of a batch of rows.
+// The sequence name is an unquoted identifier, optionally prefixed with the owner, like OWNER.ORDER_SEQ.
+// The values are in the order the sequence returned them, which is not ascending on RAC or for sharded sequences,
+// as each instance or shard caches its own range of values.
+func FetchSequenceValues(ctx context.Context, db *sql.DB, sequenceName string, count int) ([]int64, error) {
+	for _, name := range strings.Split(sequenceName, ".") {
+		err := ValidateIdentifier(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if count < 1 {
+		return nil, nil
+	}
+
+	rows, err := db.QueryContext(ctx, "select "+sequenceName+".nextval from dual connect by level <= :1", count)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]int64, 0, count)
+	for rows.Next() {
+		var value int64
+		err = rows.Scan(&value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
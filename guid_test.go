@@ -0,0 +1,141 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestDestructiveGUID checks fetching SYS_GUID values and sequence values, and binding and scanning GUIDs
+func TestDestructiveGUID(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	guids, err := FetchGUIDs(ctx, TestDB, 100)
+	if err != nil {
+		t.Fatal("FetchGUIDs error:", err)
+	}
+	unique := make(map[GUID]struct{}, len(guids))
+	for _, guid := range guids {
+		unique[guid] = struct{}{}
+	}
+	if len(guids) != 100 || len(unique) != 100 {
+		t.Errorf("FetchGUIDs - expected: %v unique - received: %v, %v unique", 100, len(guids), len(unique))
+	}
+
+	var text string
+	err = TestDB.QueryRowContext(ctx, "select rawtohex(:1) from dual", guids[0]).Scan(&text)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if text != guids[0].String() {
+		t.Errorf("rawtohex - expected: %v - received: %v", guids[0].String(), text)
+	}
+
+	var nullGUID *GUID
+	err = TestDB.QueryRowContext(ctx, "select cast (null as RAW(16)) from dual").Scan(&nullGUID)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if nullGUID != nil {
+		t.Errorf("null GUID - expected: %v - received: %v", nil, nullGUID)
+	}
+
+	sequenceName := "GUID_SEQ_" + TestTimeString
+	err = testExec(t, "create sequence "+sequenceName, nil)
+	if err != nil {
+		t.Fatal("create sequence error:", err)
+	}
+	defer func() {
+		err := testExec(t, "drop sequence "+sequenceName, nil)
+		if err != nil {
+			t.Error("drop sequence error:", err)
+		}
+	}()
+
+	values, err := FetchSequenceValues(ctx, TestDB, sequenceName, 10)
+	if err != nil {
+		t.Fatal("FetchSequenceValues error:", err)
+	}
+	if !reflect.DeepEqual(values, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("FetchSequenceValues - expected: 1 to 10 - received: %v", values)
+	}
+
+	_, err = FetchSequenceValues(ctx, TestDB, "bad name", 10)
+	var identifierError *IdentifierError
+	if !errors.As(err, &identifierError) {
+		t.Errorf("FetchSequenceValues bad name - expected: IdentifierError - received: %v", err)
+	}
+}
+
+// TestGUID tests GUID text, byte order, bind, and scan conversions
+func TestGUID(t *testing.T) {
+	t.Parallel()
+
+	guid, err := ParseGUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	if err != nil {
+		t.Fatal("ParseGUID error:", err)
+	}
+	expected := GUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	if guid != expected {
+		t.Errorf("ParseGUID - expected: %v - received: %v", expected, guid)
+	}
+	if guid.String() != "6BA7B8109DAD11D180B400C04FD430C8" {
+		t.Errorf("String - expected: %v - received: %v", "6BA7B8109DAD11D180B400C04FD430C8", guid.String())
+	}
+	if guid.UUIDString() != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" {
+		t.Errorf("UUIDString - expected: %v - received: %v", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", guid.UUIDString())
+	}
+	parsed, err := ParseGUID(guid.String())
+	if err != nil || parsed != guid {
+		t.Errorf("ParseGUID of String - expected: %v - received: %v %v", guid, parsed, err)
+	}
+
+	mixed := guid.MixedEndian()
+	expectedMixed := [16]byte{0x10, 0xb8, 0xa7, 0x6b, 0xad, 0x9d, 0xd1, 0x11, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	if mixed != expectedMixed {
+		t.Errorf("MixedEndian - expected: %v - received: %v", expectedMixed, mixed)
+	}
+	if GUIDFromMixedEndian(mixed) != guid {
+		t.Errorf("GUIDFromMixedEndian - expected: %v - received: %v", guid, GUIDFromMixedEndian(mixed))
+	}
+
+	for _, text := range []string{"", "6ba7b810", "6ba7b810x9dad-11d1-80b4-00c04fd430c8", "6BA7B8109DAD11D180B400C04FD430CX"} {
+		_, err = ParseGUID(text)
+		if err != ErrInvalidGUID {
+			t.Errorf("ParseGUID %q - expected: %v - received: %v", text, ErrInvalidGUID, err)
+		}
+	}
+
+	value, err := guid.Value()
+	if err != nil || !reflect.DeepEqual(value, expected[:]) {
+		t.Errorf("Value - expected: %v - received: %v %v", expected[:], value, err)
+	}
+
+	var scanned GUID
+	err = scanned.Scan(expected[:])
+	if err != nil || scanned != guid {
+		t.Errorf("Scan bytes - expected: %v - received: %v %v", guid, scanned, err)
+	}
+	scanned = GUID{}
+	err = scanned.Scan("6BA7B8109DAD11D180B400C04FD430C8")
+	if err != nil || scanned != guid {
+		t.Errorf("Scan string - expected: %v - received: %v %v", guid, scanned, err)
+	}
+	for _, src := range []interface{}{nil, []byte{1, 2}, int64(1)} {
+		err = scanned.Scan(src)
+		if err == nil {
+			t.Errorf("Scan %v - expected: error - received: %v", src, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"database/sql/driver"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Hooks are optional functions called around statement execution.
+	// A nil function is skipped, so the zero value does nothing.
+	// Hooks are copied to a connection when it is opened, so set them before opening the sql.DB.
+	Hooks struct {
+		// BeforeQuery is called before a query or exec is sent to the server
+		BeforeQuery func(ctx context.Context, query string)
+		// AfterQuery is called after a query has been executed
+		AfterQuery func(ctx context.Context, info QueryInfo)
+		// AfterExec is called after an exec has been executed
+		AfterExec func(ctx context.Context, info QueryInfo)
+		// AfterBreak is called after OCIBreak was sent because the context of a call was done.
+		// A break that is not acknowledged within BreakThreshold is reported as not acknowledged,
+		// which shows networks or servers where cancel silently does nothing.
+		AfterBreak func(ctx context.Context, info BreakInfo)
+		// BreakThreshold is how long to wait for the cancelled call to return, defaults to 5 seconds
+		BreakThreshold time.Duration
+		// LeakReport is called when the last open connection of the connector closes, with the OCI handles, descriptors,
+		// statements, and temporary LOBs of its connections that were not freed, to catch leaks during development.
+		// Each connector tracks its own connections, connections opened with DriverStruct.Open are tracked by the driver.
+		// Allocations are tracked from when a connection with this hook is opened, and leaks are also logged to the Logger.
+		// Build with the oci8debug build tag to include the allocation stacks.
+		LeakReport func(report LeakReport)
+		// NormalizeQuery sets QueryInfo.Fingerprint to the NormalizeQuery fingerprint of the query,
+		// to label timings without a label for every literal of dynamically generated SQL.
+		// The fingerprint is computed once per prepared statement.
+		NormalizeQuery bool
+		// PasswordExpiry is called when connecting returns the ORA-28002 warning that the password will expire soon,
+		// so an alert can be raised before the credentials expire. The warning is also logged to the Logger.
+		// With a session pool it is called for the connections that get a session with the warning.
+		PasswordExpiry func(warning *PasswordExpiryWarning)
+		// ResourceBusy is called when an exec fails with ORA-00054 resource busy and acquire with NOWAIT specified,
+		// or ORA-30006 resource busy; acquire with WAIT timeout expired, like DDL on a table with uncommitted changes.
+		// When it returns true the exec is executed again after waiting wait, otherwise the error is returned.
+		// When the context is done while waiting, the error is returned. Queries and batches are not executed again.
+		// See ResourceBusyBackoff.
+		ResourceBusy func(ctx context.Context, info ResourceBusyInfo) (retry bool, wait time.Duration)
+		// ImplicitCommit is called with ddl_in_tx=WARN before DDL is executed in a transaction,
+		// which Oracle commits before the DDL. The warning is also logged to the Logger.
+		ImplicitCommit func(ctx context.Context, warning *ImplicitCommitError)
+		// QueryText shortens the statement text passed to the hooks and set in ImplicitCommitError,
+		// like to keep generated statements with IN lists of thousands of literals from flooding the logs.
+		// The text is shortened once per prepared statement. The zero value passes the text as is.
+		QueryText QueryTextPolicy
+		// IncludeArgs sets QueryInfo.Args to the bind values of the query or exec, like to log slow statements with their values.
+		// It is off by default, so values like passwords only reach the hooks when asked for, see RedactArg.
+		IncludeArgs bool
+		// RedactArg returns the value set in QueryInfo.Args in place of a bind value with IncludeArgs,
+		// like "***" for the bind of a password. Called once per bind for each execute. A nil RedactArg passes the values as is.
+		RedactArg func(arg driver.NamedValue) interface{}
+	}
+
+	// QueryTextPolicy is how the statement text is shortened before it reaches the hooks, see QueryTextPolicy.Apply
+	QueryTextPolicy struct {
+		// MaxLength is the max length in bytes of the text, 0 for no limit. Longer text is cut at a character boundary and ... is appended.
+		MaxLength int
+		// StripLiterals replaces the text with its NormalizeQuery fingerprint, with the literals replaced with ? and the IN lists collapsed
+		StripLiterals bool
+		// HashSuffix appends the hash of the full text to a shortened text, like /* fnv:aa0ace9f241b1325 */,
+		// to tell apart the statements that are shortened to the same text
+		HashSuffix bool
+	}
+
+	// QueryInfo is the information passed to the after hooks
+	QueryInfo struct {
+		// Query is the statement text as prepared, shortened by Hooks.QueryText
+		Query string
+		// Fingerprint is the NormalizeQuery fingerprint of the query, only set when Hooks.NormalizeQuery is true
+		Fingerprint string
+		// Args are the bind values of the execute, redacted by Hooks.RedactArg, only set when Hooks.IncludeArgs is true.
+		// Values bound by position have an Ordinal and no Name. Not set for batches.
+		Args []driver.NamedValue
+		// Duration is the time spent executing, not including binding or reading rows
+		Duration time.Duration
+		// Err is the error returned by the execute, nil on success
+		Err error
+	}
+
+	// ResourceBusyInfo is the information passed to the ResourceBusy hook
+	ResourceBusyInfo struct {
+		// Query is the statement text as prepared, shortened by Hooks.QueryText
+		Query string
+		// Attempt is the number of times the statement was executed, 1 for the first execute
+		Attempt int
+		// Err is the ORA-00054 or ORA-30006 error returned by the execute
+		Err error
+	}
+
+	// BreakInfo is the information passed to the AfterBreak hook
+	BreakInfo struct {
+		// Acknowledged is true when the cancelled call returned within the break threshold
+		Acknowledged bool
+		// Duration is the time from sending the break to the call returning, or the threshold when not acknowledged
+		Duration time.Duration
+		// Err is the error returned by OCIBreak, nil on success
+		Err error
+	}
+)
+
+// defaultBreakThreshold is the break threshold when Hooks.BreakThreshold is not set
+const defaultBreakThreshold = 5 * time.Second
+
+// beforeQuery calls the BeforeQuery hook and returns the start time for the after hooks
+func (conn *Conn) beforeQuery(ctx context.Context, query string) time.Time {
+	if conn.hooks.BeforeQuery != nil {
+		conn.hooks.BeforeQuery(ctx, query)
+	}
+	return conn.now()
+}
+
+// afterQuery calls the AfterQuery hook
+func (conn *Conn) afterQuery(ctx context.Context, query string, fingerprint string, args []driver.NamedValue, start time.Time, err error) {
+	if conn.hooks.AfterQuery != nil {
+		conn.hooks.AfterQuery(ctx, QueryInfo{Query: query, Fingerprint: fingerprint, Args: args, Duration: conn.since(start), Err: err})
+	}
+}
+
+// afterExec calls the AfterExec hook
+func (conn *Conn) afterExec(ctx context.Context, query string, fingerprint string, args []driver.NamedValue, start time.Time, err error) {
+	if conn.hooks.AfterExec != nil {
+		conn.hooks.AfterExec(ctx, QueryInfo{Query: query, Fingerprint: fingerprint, Args: args, Duration: conn.since(start), Err: err})
+	}
+}
+
+// hookArgs returns the bind values for QueryInfo.Args with Hooks.IncludeArgs, redacted by Hooks.RedactArg,
+// nil without IncludeArgs or without values
+func (conn *Conn) hookArgs(values []driver.Value, namedValues []driver.NamedValue) []driver.NamedValue {
+	if !conn.hooks.IncludeArgs || (len(values) == 0 && len(namedValues) == 0) {
+		return nil
+	}
+
+	args := make([]driver.NamedValue, 0, len(values)+len(namedValues))
+	for i, value := range values {
+		args = append(args, driver.NamedValue{Ordinal: i + 1, Value: value})
+	}
+	args = append(args, namedValues...)
+	if conn.hooks.RedactArg != nil {
+		for i := range args {
+			args[i].Value = conn.hooks.RedactArg(args[i])
+		}
+	}
+	return args
+}
+
+// queryFingerprint returns the fingerprint of the statement query when Hooks.NormalizeQuery is true, otherwise empty
+func (stmt *Stmt) queryFingerprint() string {
+	if !stmt.conn.hooks.NormalizeQuery {
+		return ""
+	}
+	if stmt.fingerprint == "" {
+		stmt.fingerprint = NormalizeQuery(stmt.queryText)
+	}
+	return stmt.fingerprint
+}
+
+// hookQuery returns the statement query shortened by Hooks.QueryText, shortened once per statement
+func (stmt *Stmt) hookQuery() string {
+	if stmt.conn.hooks.QueryText == (QueryTextPolicy{}) {
+		return stmt.queryText
+	}
+	if stmt.hookText == "" {
+		stmt.hookText = stmt.conn.hooks.QueryText.Apply(stmt.queryText)
+	}
+	return stmt.hookText
+}
+
+// afterBreak waits for done to be closed up to the break threshold then calls the AfterBreak hook
+func (conn *Conn) afterBreak(ctx context.Context, done chan struct{}, start time.Time, err error) {
+	if conn.hooks.AfterBreak == nil {
+		return
+	}
+
+	threshold := conn.hooks.BreakThreshold
+	if threshold <= 0 {
+		threshold = defaultBreakThreshold
+	}
+	timer := conn.newTimer(threshold - conn.since(start))
+	defer timer.Stop()
+
+	info := BreakInfo{Err: err}
+	select {
+	case <-done:
+		info.Acknowledged = true
+	case <-timer.C():
+	}
+	info.Duration = conn.since(start)
+
+	conn.hooks.AfterBreak(ctx, info)
+}
+
+// connectWarning reports the ORA-28002 password expiry warning when connecting returned OCI_SUCCESS_WITH_INFO
+func (conn *Conn) connectWarning(result C.sword, username string) {
+	if result != C.OCI_SUCCESS_WITH_INFO {
+		return
+	}
+	code, err := conn.ociGetError()
+	if code != 28002 {
+		return
+	}
+
+	warning := &PasswordExpiryWarning{Username: username, Days: passwordExpiryDays(err.Error()), Message: err.Error()}
+	conn.logger.Print("connect warning: ", warning.Message)
+	if conn.hooks.PasswordExpiry != nil {
+		conn.hooks.PasswordExpiry(warning)
+	}
+}
+
+// passwordExpiryDays returns the number of days of an ORA-28002 message, like:
+// ORA-28002: the password will expire within 7 days
+// Returns -1 when the message has no number of days.
+func passwordExpiryDays(message string) int {
+	index := strings.Index(message, "within ")
+	if index < 0 {
+		return -1
+	}
+	fields := strings.Fields(message[index+len("within "):])
+	if len(fields) < 1 {
+		return -1
+	}
+	days, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return -1
+	}
+	return days
+}
+
+// ResourceBusyBackoff returns a ResourceBusy hook that executes the statement up to attempts times in total,
+// waiting initial before the first retry and doubling the wait for each retry after, up to max:
+//
+//	connector.Hooks.ResourceBusy = oci8.ResourceBusyBackoff(10, 100*time.Millisecond, 5*time.Second)
+func ResourceBusyBackoff(attempts int, initial time.Duration, max time.Duration) func(ctx context.Context, info ResourceBusyInfo) (bool, time.Duration) {
+	return func(ctx context.Context, info ResourceBusyInfo) (bool, time.Duration) {
+		if info.Attempt >= attempts {
+			return false, 0
+		}
+		wait := initial
+		for i := 1; i < info.Attempt && wait < max; i++ {
+			wait *= 2
+		}
+		if wait > max {
+			wait = max
+		}
+		return true, wait
+	}
+}
+
+// resourceBusyRetry returns true when err is a resource busy error and the ResourceBusy hook decided to execute again,
+// after waiting the time returned by the hook. Returns false when the context is done while waiting.
+func (conn *Conn) resourceBusyRetry(ctx context.Context, query string, attempt int, err error) bool {
+	if conn.hooks.ResourceBusy == nil || !(isOracleError(err, 54) || isOracleError(err, 30006)) {
+		return false
+	}
+
+	retry, wait := conn.hooks.ResourceBusy(ctx, ResourceBusyInfo{Query: query, Attempt: attempt, Err: err})
+	if !retry {
+		return false
+	}
+	if wait <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := conn.newTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C():
+		return true
+	}
+}
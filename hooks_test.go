@@ -0,0 +1,165 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestInstrumentedDriverHooks(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	var mutex sync.Mutex
+	var beforeQueries []string
+	var afterQueries []QueryInfo
+	var afterExecs []QueryInfo
+	InstrumentedDriver.Hooks = Hooks{
+		BeforeQuery: func(ctx context.Context, query string) {
+			mutex.Lock()
+			beforeQueries = append(beforeQueries, query)
+			mutex.Unlock()
+		},
+		AfterQuery: func(ctx context.Context, info QueryInfo) {
+			mutex.Lock()
+			afterQueries = append(afterQueries, info)
+			mutex.Unlock()
+		},
+		AfterExec: func(ctx context.Context, info QueryInfo) {
+			mutex.Lock()
+			afterExecs = append(afterExecs, info)
+			mutex.Unlock()
+		},
+		NormalizeQuery: true,
+	}
+	defer func() {
+		InstrumentedDriver.Hooks = Hooks{}
+	}()
+
+	db, err := sql.Open("oci8-instrumented", testGetOpenString(""))
+	if err != nil {
+		t.Fatal("open error:", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	var result int64
+	err = db.QueryRowContext(ctx, "select 1 from dual").Scan(&result)
+	if err != nil {
+		t.Fatal("query row error:", err)
+	}
+	_, err = db.ExecContext(ctx, "begin null; end;")
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	_, err = db.ExecContext(ctx, "begin raise_application_error(-20001, 'hook'); end;")
+	if err == nil {
+		t.Fatal("expected exec error")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !reflect.DeepEqual(beforeQueries, []string{"select 1 from dual", "begin null; end;", "begin raise_application_error(-20001, 'hook'); end;"}) {
+		t.Errorf("before queries - received: %v", beforeQueries)
+	}
+	if len(afterQueries) != 1 || afterQueries[0].Query != "select 1 from dual" || afterQueries[0].Fingerprint != "select ? from dual" ||
+		afterQueries[0].Err != nil {
+		t.Errorf("after queries - received: %+v", afterQueries)
+	}
+	if len(afterExecs) != 2 || afterExecs[0].Err != nil || afterExecs[1].Err == nil {
+		t.Errorf("after execs - received: %+v", afterExecs)
+	}
+}
+
+// TestHooks tests the drivers are registered and the hooks are called
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	drivers := sql.Drivers()
+	for _, name := range []string{"oci8", "oci8-instrumented"} {
+		found := false
+		for _, driver := range drivers {
+			if driver == name {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("driver %v is not registered: %v", name, drivers)
+		}
+	}
+
+	// zero value hooks do nothing
+	conn := &Conn{}
+	start := conn.beforeQuery(context.Background(), "select 1 from dual")
+	conn.afterQuery(context.Background(), "select 1 from dual", "", nil, start, nil)
+	conn.afterExec(context.Background(), "select 1 from dual", "", nil, start, nil)
+	if args := conn.hookArgs([]driver.Value{int64(1)}, nil); args != nil {
+		t.Errorf("hookArgs without IncludeArgs - expected: nil - received: %v", args)
+	}
+
+	var before string
+	var infos []QueryInfo
+	testErr := errors.New("test error")
+	conn.hooks = Hooks{
+		BeforeQuery: func(ctx context.Context, query string) { before = query },
+		AfterQuery:  func(ctx context.Context, info QueryInfo) { infos = append(infos, info) },
+		AfterExec:   func(ctx context.Context, info QueryInfo) { infos = append(infos, info) },
+	}
+	start = conn.beforeQuery(context.Background(), "a")
+	conn.afterQuery(context.Background(), "b", "", nil, start, nil)
+	conn.afterExec(context.Background(), "c", "", []driver.NamedValue{{Ordinal: 1, Value: "x"}}, start, testErr)
+	if before != "a" {
+		t.Errorf("before - expected: a - received: %v", before)
+	}
+	if len(infos) != 2 || infos[0].Query != "b" || infos[0].Err != nil || infos[1].Query != "c" || infos[1].Err != testErr ||
+		len(infos[1].Args) != 1 {
+		t.Errorf("infos - received: %+v", infos)
+	}
+
+	conn.hooks.IncludeArgs = true
+	if args := conn.hookArgs(nil, nil); args != nil {
+		t.Errorf("hookArgs without values - expected: nil - received: %v", args)
+	}
+	args := conn.hookArgs([]driver.Value{int64(1), "secret"}, nil)
+	if !reflect.DeepEqual(args, []driver.NamedValue{{Ordinal: 1, Value: int64(1)}, {Ordinal: 2, Value: "secret"}}) {
+		t.Errorf("hookArgs values - received: %v", args)
+	}
+	conn.hooks.RedactArg = func(arg driver.NamedValue) interface{} {
+		if arg.Name == "password" {
+			return "***"
+		}
+		return arg.Value
+	}
+	args = conn.hookArgs(nil, []driver.NamedValue{{Name: "username", Ordinal: 1, Value: "scott"}, {Name: "password", Ordinal: 2, Value: "tiger"}})
+	if !reflect.DeepEqual(args, []driver.NamedValue{{Name: "username", Ordinal: 1, Value: "scott"}, {Name: "password", Ordinal: 2, Value: "***"}}) {
+		t.Errorf("hookArgs redacted - received: %v", args)
+	}
+}
+
+// TestPasswordExpiryDays tests getting the number of days from ORA-28002 messages
+func TestPasswordExpiryDays(t *testing.T) {
+	t.Parallel()
+
+	var daysTests = []struct {
+		message string
+		days    int
+	}{
+		{"ORA-28002: the password will expire within 7 days", 7},
+		{"ORA-28002: the password will expire within 1 days\n", 1},
+		{"ORA-28002: the password will expire within  days", -1},
+		{"ORA-28002: the password will expire soon", -1},
+	}
+	for _, tt := range daysTests {
+		days := passwordExpiryDays(tt.message)
+		if days != tt.days {
+			t.Errorf("passwordExpiryDays %q - expected: %v - received: %v", tt.message, tt.days, days)
+		}
+	}
+}
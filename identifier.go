@@ -0,0 +1,218 @@
+package oci8
+
+import (
+	"context"
+	"database/sql/driver"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxIdentifierLength is the max length in bytes of identifiers and bind names before Oracle 12.2,
+	// or with the compatible parameter lower than 12.2
+	maxIdentifierLength = 30
+	// maxLongIdentifierLength is the max length in bytes of identifiers and bind names with Oracle 12.2 and later
+	maxLongIdentifierLength = 128
+)
+
+// IdentifierError is returned when a name can not be used as an identifier
+type IdentifierError struct {
+	// Name is the invalid name
+	Name string
+	// Reason is why the name can not be used
+	Reason string
+}
+
+// Error returns the identifier error string
+func (identifierError *IdentifierError) Error() string {
+	return "invalid identifier " + strconv.Quote(identifierError.Name) + ": " + identifierError.Reason
+}
+
+// reservedWords are the Oracle SQL reserved words, which can not be used as unquoted identifiers or bind names
+var reservedWords = map[string]struct{}{
+	"ACCESS": {}, "ADD": {}, "ALL": {}, "ALTER": {}, "AND": {}, "ANY": {}, "AS": {}, "ASC": {}, "AUDIT": {},
+	"BETWEEN": {}, "BY": {}, "CHAR": {}, "CHECK": {}, "CLUSTER": {}, "COLUMN": {}, "COMMENT": {}, "COMPRESS": {},
+	"CONNECT": {}, "CREATE": {}, "CURRENT": {}, "DATE": {}, "DECIMAL": {}, "DEFAULT": {}, "DELETE": {}, "DESC": {},
+	"DISTINCT": {}, "DROP": {}, "ELSE": {}, "EXCLUSIVE": {}, "EXISTS": {}, "FILE": {}, "FLOAT": {}, "FOR": {},
+	"FROM": {}, "GRANT": {}, "GROUP": {}, "HAVING": {}, "IDENTIFIED": {}, "IMMEDIATE": {}, "IN": {}, "INCREMENT": {},
+	"INDEX": {}, "INITIAL": {}, "INSERT": {}, "INTEGER": {}, "INTERSECT": {}, "INTO": {}, "IS": {}, "LEVEL": {},
+	"LIKE": {}, "LOCK": {}, "LONG": {}, "MAXEXTENTS": {}, "MINUS": {}, "MLSLABEL": {}, "MODE": {}, "MODIFY": {},
+	"NOAUDIT": {}, "NOCOMPRESS": {}, "NOT": {}, "NOWAIT": {}, "NULL": {}, "NUMBER": {}, "OF": {}, "OFFLINE": {},
+	"ON": {}, "ONLINE": {}, "OPTION": {}, "OR": {}, "ORDER": {}, "PCTFREE": {}, "PRIOR": {}, "PRIVILEGES": {},
+	"PUBLIC": {}, "RAW": {}, "RENAME": {}, "RESOURCE": {}, "REVOKE": {}, "ROW": {}, "ROWID": {}, "ROWNUM": {},
+	"ROWS": {}, "SELECT": {}, "SESSION": {}, "SET": {}, "SHARE": {}, "SIZE": {}, "SMALLINT": {}, "START": {},
+	"SUCCESSFUL": {}, "SYNONYM": {}, "SYSDATE": {}, "TABLE": {}, "THEN": {}, "TO": {}, "TRIGGER": {}, "UID": {},
+	"UNION": {}, "UNIQUE": {}, "UPDATE": {}, "USER": {}, "VALIDATE": {}, "VALUES": {}, "VARCHAR": {},
+	"VARCHAR2": {}, "VIEW": {}, "WHENEVER": {}, "WHERE": {}, "WITH": {},
+}
+
+// isReservedWord returns true if word is an Oracle SQL reserved word
+func isReservedWord(word string) bool {
+	_, ok := reservedWords[strings.ToUpper(word)]
+	return ok
+}
+
+// validateBindName checks that name can be used as a bind placeholder name of at most maxLength bytes.
+// A valid name is either a number or an unquoted identifier that is not a reserved word.
+// Using an invalid name results in ORA-01745: invalid host/bind variable name
+func validateBindName(name string, maxLength int) error {
+	if len(name) > maxLength {
+		return ErrInvalidBindName
+	}
+
+	numeric := true
+	for i := 0; i < len(name); i++ {
+		if name[i] < '0' || name[i] > '9' {
+			numeric = false
+			break
+		}
+	}
+	if numeric {
+		return nil
+	}
+
+	if !isIdentifier(name) {
+		return ErrInvalidBindName
+	}
+
+	return nil
+}
+
+// ValidateIdentifier checks that name can be used as an identifier, like a table or column name,
+// so it can be safely added to dynamic SQL. A valid name is either an unquoted identifier,
+// a letter followed by letters, digits, _, $, or # that is not a reserved word,
+// or a quoted identifier, like "My Table", see QuoteIdentifier.
+// Unquoted identifiers are ASCII and not case sensitive, Oracle stores them in upper case.
+// Quoted identifiers are case sensitive and can contain any character except double quotes and the null character.
+// The name, without the quotes, is at most 128 bytes. Databases before Oracle 12.2, or with the compatible parameter
+// lower than 12.2, only allow 30 bytes, use Conn.ValidateIdentifier to check the limit of the database.
+// Returns an *IdentifierError when the name is not valid.
+func ValidateIdentifier(name string) error {
+	return validateIdentifier(name, maxLongIdentifierLength)
+}
+
+// ValidateIdentifier checks that name can be used as an identifier, like the package ValidateIdentifier,
+// with the max length allowed by the database: 128 bytes from Oracle 12.2 with the compatible parameter 12.2 or higher,
+// otherwise 30 bytes. The max length is read from the database with a round trip on first use.
+// Use sql.Conn.Raw to get the underlying *Conn.
+func (conn *Conn) ValidateIdentifier(name string) error {
+	return validateIdentifier(name, conn.identifierLength())
+}
+
+// validateIdentifier checks that name is an unquoted or a quoted identifier of at most maxLength bytes
+func validateIdentifier(name string, maxLength int) error {
+	if len(name) > 1 && name[0] == '"' && name[len(name)-1] == '"' {
+		_, err := quoteIdentifier(name[1:len(name)-1], maxLength)
+		if err != nil {
+			return &IdentifierError{Name: name, Reason: err.(*IdentifierError).Reason}
+		}
+		return nil
+	}
+
+	switch {
+	case len(name) < 1:
+		return &IdentifierError{Name: name, Reason: "empty"}
+	case len(name) > maxLength:
+		return &IdentifierError{Name: name, Reason: "longer than " + strconv.Itoa(maxLength) + " bytes"}
+	case isReservedWord(name):
+		return &IdentifierError{Name: name, Reason: "reserved word"}
+	case !isIdentifier(name):
+		return &IdentifierError{Name: name, Reason: "must be a letter followed by letters, digits, _, $, or #, or be quoted"}
+	}
+	return nil
+}
+
+// QuoteIdentifier returns name as a quoted identifier, like "My Table", so it can be safely added to dynamic SQL.
+// Quoted identifiers are case sensitive and can contain any character except double quotes and the null character,
+// including spaces, reserved words, and non-ASCII characters, which must be valid UTF-8.
+// The name must not be empty and is at most 128 bytes. Databases before Oracle 12.2, or with the compatible parameter
+// lower than 12.2, only allow 30 bytes, use Conn.QuoteIdentifier to check the limit of the database.
+// Returns an *IdentifierError when the name can not be quoted.
+func QuoteIdentifier(name string) (string, error) {
+	return quoteIdentifier(name, maxLongIdentifierLength)
+}
+
+// QuoteIdentifier returns name as a quoted identifier, like the package QuoteIdentifier,
+// with the max length allowed by the database, see Conn.ValidateIdentifier.
+// Use sql.Conn.Raw to get the underlying *Conn.
+func (conn *Conn) QuoteIdentifier(name string) (string, error) {
+	return quoteIdentifier(name, conn.identifierLength())
+}
+
+// quoteIdentifier returns name as a quoted identifier of at most maxLength bytes
+func quoteIdentifier(name string, maxLength int) (string, error) {
+	switch {
+	case len(name) < 1:
+		return "", &IdentifierError{Name: name, Reason: "empty"}
+	case len(name) > maxLength:
+		return "", &IdentifierError{Name: name, Reason: "longer than " + strconv.Itoa(maxLength) + " bytes"}
+	case strings.ContainsAny(name, "\"\x00"):
+		return "", &IdentifierError{Name: name, Reason: "contains a double quote or null character"}
+	case !utf8.ValidString(name):
+		return "", &IdentifierError{Name: name, Reason: "not valid UTF-8"}
+	}
+	return `"` + name + `"`, nil
+}
+
+// identifierName returns the name Oracle stores for a valid identifier, as found in the data dictionary:
+// a quoted identifier without the quotes, an unquoted identifier in upper case
+func identifierName(identifier string) string {
+	if len(identifier) > 1 && identifier[0] == '"' && identifier[len(identifier)-1] == '"' {
+		return identifier[1 : len(identifier)-1]
+	}
+	return strings.ToUpper(identifier)
+}
+
+// identifierLength returns the max length in bytes of identifiers and bind names allowed by the database.
+// ORA_MAX_NAME_LEN_SUPPORTED returns it from Oracle 12.2, depending on the compatible parameter,
+// and does not exist before 12.2, which allows 30 bytes. The length is read once with a round trip.
+func (conn *Conn) identifierLength() int {
+	if conn.maxNameLength > 0 {
+		return conn.maxNameLength
+	}
+
+	dest := make([]driver.Value, 1)
+	err := conn.queryRow(context.Background(), "select ora_max_name_len_supported from dual", dest)
+	if err != nil {
+		// ORA-00904: invalid identifier
+		if isOracleError(err, 904) {
+			conn.maxNameLength = maxIdentifierLength
+			return conn.maxNameLength
+		}
+		conn.logger.Print("get max identifier length error: ", err)
+		return maxLongIdentifierLength
+	}
+
+	switch length := dest[0].(type) {
+	case int64:
+		conn.maxNameLength = int(length)
+	case float64:
+		conn.maxNameLength = int(length)
+	case string:
+		conn.maxNameLength, _ = strconv.Atoi(length)
+	}
+	if conn.maxNameLength < maxIdentifierLength {
+		conn.maxNameLength = maxIdentifierLength
+	}
+	return conn.maxNameLength
+}
+
+// isIdentifier returns true if name is a nonreserved unquoted identifier: a letter followed by letters, digits, _, $, or #
+func isIdentifier(name string) bool {
+	if len(name) < 1 {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '_' || c == '$' || c == '#'):
+		default:
+			return false
+		}
+	}
+
+	return !isReservedWord(name)
+}
@@ -0,0 +1,225 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValidateIdentifier tests identifier validation
+func TestValidateIdentifier(t *testing.T) {
+	t.Parallel()
+
+	var identifierTests = []struct {
+		name  string
+		valid bool
+	}{
+		{"A", true},
+		{"temp_table_1", true},
+		{"A$#", true},
+		{"", false},
+		{"1A", false},
+		{"_A", false},
+		{"A B", false},
+		{"A;drop", false},
+		{"TABLE", false},
+		{"ABCDEFGHIJABCDEFGHIJABCDEFGHIJ", true},
+		{"ABCDEFGHIJABCDEFGHIJABCDEFGHIJK", true},
+		{strings.Repeat("A", 128), true},
+		{strings.Repeat("A", 129), false},
+		{`"My Table"`, true},
+		{`"TABLE"`, true},
+		{`"Größe"`, true},
+		{`"` + strings.Repeat("A", 128) + `"`, true},
+		{`"` + strings.Repeat("A", 129) + `"`, false},
+		{`""`, false},
+		{`"a" or "b"`, false},
+		{`"a`, false},
+		{"Größe", false},
+	}
+
+	for _, tt := range identifierTests {
+		err := ValidateIdentifier(tt.name)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateIdentifier(%q) - expected valid: %v - received: %v", tt.name, tt.valid, err)
+		}
+		if err != nil {
+			if _, ok := err.(*IdentifierError); !ok {
+				t.Errorf("ValidateIdentifier(%q) - expected: *IdentifierError - received: %T", tt.name, err)
+			}
+		}
+	}
+
+	var quoteTests = []struct {
+		name     string
+		expected string
+		valid    bool
+	}{
+		{"A", `"A"`, true},
+		{"My Table", `"My Table"`, true},
+		{"TABLE", `"TABLE"`, true},
+		{"a;drop table b", `"a;drop table b"`, true},
+		{strings.Repeat("A", 128), `"` + strings.Repeat("A", 128) + `"`, true},
+		{"", "", false},
+		{strings.Repeat("A", 129), "", false},
+		{`a" or "b`, "", false},
+		{"a\x00", "", false},
+		{"Größe", `"Größe"`, true},
+		{"a\xff", "", false},
+	}
+
+	for _, tt := range quoteTests {
+		quoted, err := QuoteIdentifier(tt.name)
+		if (err == nil) != tt.valid {
+			t.Errorf("QuoteIdentifier(%q) - expected valid: %v - received: %v", tt.name, tt.valid, err)
+		}
+		if quoted != tt.expected {
+			t.Errorf("QuoteIdentifier(%q) - expected: %v - received: %v", tt.name, tt.expected, quoted)
+		}
+	}
+
+	var lengthTests = []struct {
+		name      string
+		maxLength int
+		valid     bool
+	}{
+		{strings.Repeat("A", 30), maxIdentifierLength, true},
+		{strings.Repeat("A", 31), maxIdentifierLength, false},
+		{`"` + strings.Repeat("A", 30) + `"`, maxIdentifierLength, true},
+		{`"` + strings.Repeat("A", 31) + `"`, maxIdentifierLength, false},
+		{strings.Repeat("A", 31), maxLongIdentifierLength, true},
+	}
+	for _, tt := range lengthTests {
+		err := validateIdentifier(tt.name, tt.maxLength)
+		if (err == nil) != tt.valid {
+			t.Errorf("validateIdentifier(%q, %v) - expected valid: %v - received: %v", tt.name, tt.maxLength, tt.valid, err)
+		}
+	}
+
+	var nameTests = []struct {
+		identifier string
+		expected   string
+	}{
+		{"my_table", "MY_TABLE"},
+		{`"My Table"`, "My Table"},
+		{`"Größe"`, "Größe"},
+	}
+	for _, tt := range nameTests {
+		name := identifierName(tt.identifier)
+		if name != tt.expected {
+			t.Errorf("identifierName(%q) - expected: %v - received: %v", tt.identifier, tt.expected, name)
+		}
+	}
+
+	err := ValidateIdentifier("TABLE")
+	if err == nil || err.Error() != `invalid identifier "TABLE": reserved word` {
+		t.Errorf("ValidateIdentifier error - received: %v", err)
+	}
+
+	if !isOracleError(errors.New("ORA-00955: name is already used by an existing object"), 955) {
+		t.Error("isOracleError expected true for ORA-00955")
+	}
+	if isOracleError(errors.New("ORA-00942: table or view does not exist"), 955) || isOracleError(nil, 955) {
+		t.Error("isOracleError expected false")
+	}
+}
+
+// TestIdentifierLength tests the max identifier length is read from the database
+func TestIdentifierLength(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		oci8Conn := driverConn.(*Conn)
+		length := oci8Conn.identifierLength()
+		if length != maxIdentifierLength && length != maxLongIdentifierLength {
+			t.Errorf("identifierLength - expected: %v or %v - received: %v", maxIdentifierLength, maxLongIdentifierLength, length)
+		}
+		if oci8Conn.maxNameLength != length {
+			t.Errorf("maxNameLength - expected: %v - received: %v", length, oci8Conn.maxNameLength)
+		}
+
+		err := oci8Conn.ValidateIdentifier(strings.Repeat("A", length))
+		if err != nil {
+			t.Errorf("ValidateIdentifier max length - received: %v", err)
+		}
+		_, err = oci8Conn.QuoteIdentifier(strings.Repeat("A", length+1))
+		if err == nil {
+			t.Errorf("QuoteIdentifier over max length - expected: error - received: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("raw error:", err)
+	}
+}
+
+// TestValidateBinds tests bind count and bind name validation
+func TestValidateBinds(t *testing.T) {
+	t.Parallel()
+
+	var bindNameTests = []struct {
+		name      string
+		maxLength int
+		valid     bool
+	}{
+		{"1", maxIdentifierLength, true},
+		{"65535", maxIdentifierLength, true},
+		{"a", maxIdentifierLength, true},
+		{"num_1", maxIdentifierLength, true},
+		{"a$b#c", maxIdentifierLength, true},
+		{"date", maxIdentifierLength, false},
+		{"Select", maxIdentifierLength, false},
+		{"1a", maxIdentifierLength, false},
+		{"_a", maxIdentifierLength, false},
+		{"a-b", maxIdentifierLength, false},
+		{"a_very_long_bind_name_over_thirty_bytes", maxIdentifierLength, false},
+		{"a_very_long_bind_name_over_thirty_bytes", maxLongIdentifierLength, true},
+		{strings.Repeat("a", 129), maxLongIdentifierLength, false},
+	}
+
+	for _, tt := range bindNameTests {
+		namedValues := []driver.NamedValue{{Name: "ok", Ordinal: 1}, {Name: tt.name, Ordinal: 2}}
+		err := validateBinds(namedValues, len(namedValues), tt.maxLength)
+		if tt.valid {
+			if err != nil {
+				t.Errorf("validateBinds(%s) got error: %v", tt.name, err)
+			}
+			continue
+		}
+		var bindError *BindError
+		if !errors.As(err, &bindError) {
+			t.Errorf("validateBinds(%s) expected BindError, got: %v", tt.name, err)
+			continue
+		}
+		if bindError.Index != 1 || bindError.Name != tt.name || !errors.Is(err, ErrInvalidBindName) {
+			t.Errorf("validateBinds(%s) unexpected error: %+v", tt.name, bindError)
+		}
+	}
+
+	err := validateBinds(nil, maxBindCount, maxIdentifierLength)
+	if err != nil {
+		t.Errorf("validateBinds max count got error: %v", err)
+	}
+	err = validateBinds(nil, maxBindCount+1, maxIdentifierLength)
+	var bindError *BindError
+	if !errors.As(err, &bindError) || bindError.Index != maxBindCount || !errors.Is(err, ErrTooManyBinds) {
+		t.Errorf("validateBinds over max count unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+)
+
+/*
+With the identity_returning DSN parameter, LastInsertId returns the value of the identity column of the row inserted,
+like the ID of a table created with ID NUMBER GENERATED ALWAYS AS IDENTITY:
+
+	result, err := db.ExecContext(ctx, "insert into ORDERS (NAME) values (:1)", name)
+	id, err := result.LastInsertId()
+
+A single table INSERT ... VALUES statement into a table with an identity column is prepared
+with RETURNING identity INTO an added bind, so the value is returned by the execute without an extra round trip.
+The identity column of a table is looked up in ALL_TAB_IDENTITY_COLS once per connection.
+For other statements, like INSERT ... SELECT, an insert through a synonym or a database link, a statement with a RETURNING clause,
+or with servers before 12c which have no identity columns, the statement is executed unchanged
+and LastInsertId returns ErrNoIdentity.
+
+LastInsertId then does not return the pointer to the ROWID of GetLastInsertId, so with identity_returning
+use GetLastRowID only with the *Result of the driver, not with a sql.Result.
+*/
+
+// identityBindName is the name of the bind added for the RETURNING clause of identity_returning
+const identityBindName = "oci8_identity"
+
+// identityReturningQuery returns the query with a RETURNING clause for the identity column of the insert target,
+// ok is false when the query is not an insert into a table with an identity column
+func (conn *Conn) identityReturningQuery(ctx context.Context, query string) (string, bool) {
+	if conn.identityUnsupported {
+		return query, false
+	}
+	owner, table, ok := insertTable(query)
+	if !ok {
+		return query, false
+	}
+
+	key := owner + "." + table
+	column, ok := conn.identityColumns[key]
+	if !ok {
+		var err error
+		column, err = conn.identityColumn(ctx, owner, table)
+		if err != nil {
+			if isOracleError(err, 942) {
+				// ORA-00942: table or view does not exist, a server before 12c
+				conn.identityUnsupported = true
+			} else {
+				conn.logger.Print("identity column lookup error: ", err)
+			}
+			return query, false
+		}
+		if conn.identityColumns == nil {
+			conn.identityColumns = make(map[string]string)
+		}
+		conn.identityColumns[key] = column
+	}
+	if column == "" {
+		return query, false
+	}
+
+	return strings.TrimRight(query, " \t\r\n") + ` returning "` + column + `" into :` + identityBindName, true
+}
+
+// identityColumn returns the identity column of the table, empty when it has none.
+// An empty owner is the current schema.
+func (conn *Conn) identityColumn(ctx context.Context, owner string, table string) (string, error) {
+	ownerText := "sys_context('USERENV', 'CURRENT_SCHEMA')"
+	if owner != "" {
+		ownerText = quoteString(owner)
+	}
+	dest := make([]driver.Value, 1)
+	err := conn.queryRow(ctx, "select column_name from all_tab_identity_cols where owner = "+ownerText+" and table_name = "+quoteString(table), dest)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	column, _ := dest[0].(string)
+	return column, nil
+}
+
+// identityNamedValues returns the named values with the OUT bind of the identity added,
+// bound by name when the values are bound by name, otherwise by position
+func (stmt *Stmt) identityNamedValues(namedValues []driver.NamedValue) []driver.NamedValue {
+	stmt.identity = 0
+	identity := driver.NamedValue{Ordinal: len(namedValues) + 1, Value: sql.Out{Dest: &stmt.identity}}
+	if len(namedValues) > 0 && namedValues[0].Name != "" {
+		identity.Name = identityBindName
+	}
+	return append(namedValues[:len(namedValues):len(namedValues)], identity)
+}
+
+// insertTable returns the owner and the table of a single table INSERT ... VALUES statement without a RETURNING clause,
+// upper case unless quoted. The owner is empty when the table is not qualified. ok is false for other statements.
+func insertTable(query string) (owner string, table string, ok bool) {
+	keyword, rest := nextKeyword(query)
+	if keyword != "INSERT" {
+		return "", "", false
+	}
+	keyword, rest = nextKeyword(rest)
+	if keyword != "INTO" {
+		// INSERT ALL and INSERT FIRST
+		return "", "", false
+	}
+
+	table, rest, ok = nextIdentifier(rest)
+	if !ok {
+		return "", "", false
+	}
+	if strings.HasPrefix(rest, ".") {
+		owner = table
+		table, rest, ok = nextIdentifier(rest[1:])
+		if !ok {
+			return "", "", false
+		}
+	}
+	if strings.HasPrefix(rest, "@") {
+		// database link
+		return "", "", false
+	}
+
+	hasValues := false
+	for _, word := range strings.FieldsFunc(NormalizeQuery(rest), func(r rune) bool { return !isIdentifierPart(r) }) {
+		switch word {
+		case "values":
+			hasValues = true
+		case "returning", "return":
+			return "", "", false
+		}
+	}
+	if !hasValues {
+		return "", "", false
+	}
+	return owner, table, true
+}
+
+// nextIdentifier returns the identifier at the start of the query after any whitespace, and the rest of the query.
+// An unquoted identifier is returned upper case, a quoted identifier without the quotes.
+func nextIdentifier(query string) (string, string, bool) {
+	query = strings.TrimLeft(query, " \t\r\n")
+	if strings.HasPrefix(query, `"`) {
+		end := strings.IndexByte(query[1:], '"')
+		if end < 1 {
+			return "", "", false
+		}
+		return query[1 : end+1], query[end+2:], true
+	}
+	end := 0
+	for end < len(query) && query[end] < 0x80 && isIdentifierPart(rune(query[end])) {
+		end++
+	}
+	if end == 0 || !isIdentifierStart(rune(query[0])) {
+		return "", "", false
+	}
+	return strings.ToUpper(query[:end]), query[end:], true
+}
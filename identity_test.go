@@ -0,0 +1,30 @@
+package oci8
+
+import "testing"
+
+func TestInsertTable(t *testing.T) {
+	t.Parallel()
+
+	var insertTableTests = []struct {
+		query string
+		owner string
+		table string
+		ok    bool
+	}{
+		{"insert into T (A) values (:1)", "", "T", true},
+		{"INSERT INTO scott.\"Orders\" values (1)", "SCOTT", "Orders", true},
+		{" insert /*+ append */ into orders(name) values (:name)", "", "ORDERS", true},
+		{"insert into T (A) select A from U", "", "", false},
+		{"insert into T (A) values (:1) returning A into :2", "", "", false},
+		{"insert all into T values (1) into U values (2) select * from dual", "", "", false},
+		{"insert into T@link values (1)", "", "", false},
+		{"update T set A = 1", "", "", false},
+	}
+
+	for _, tt := range insertTableTests {
+		owner, table, ok := insertTable(tt.query)
+		if owner != tt.owner || table != tt.table || ok != tt.ok {
+			t.Errorf("insertTable(%q) - expected: %q, %q, %v - received: %q, %q, %v", tt.query, tt.owner, tt.table, tt.ok, owner, table, ok)
+		}
+	}
+}
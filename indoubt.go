@@ -0,0 +1,184 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// pendingTransactionsQuery selects the in-doubt distributed transactions, oldest first
+const pendingTransactionsQuery = `select local_tran_id, global_tran_id, state, mixed, advice, tran_comment,
+	fail_time, force_time, retry_time, os_user, os_terminal, host, db_user, commit#
+from dba_2pc_pending
+order by fail_time, local_tran_id`
+
+// PendingTransaction is an in-doubt distributed transaction from the DBA_2PC_PENDING view,
+// a transaction that failed between the prepare and the commit, like when the transaction manager or the network failed.
+// The rows it locked stay locked until it is committed or rolled back with CommitForce or RollbackForce.
+type PendingTransaction struct {
+	// LocalTranID is the local transaction id, like 1.21.17, used to force the outcome
+	LocalTranID string
+	// GlobalTranID is the global transaction id, the same in all the databases of the transaction
+	GlobalTranID string
+	// State is the state of the transaction: collecting, prepared, committed, forced commit, or forced rollback
+	State string
+	// Mixed is true when the outcome was forced differently than in another database of the transaction,
+	// see PurgeMixedTransaction
+	Mixed bool
+	// Advice is the commit advice of the transaction: C for commit, R for rollback, or empty for none
+	Advice string
+	// Comment is the COMMIT COMMENT of the transaction
+	Comment string
+	// FailTime is when the transaction became in doubt
+	FailTime time.Time
+	// ForceTime is when the outcome was forced, zero when it was not
+	ForceTime time.Time
+	// RetryTime is when the database last tried to resolve the transaction itself
+	RetryTime time.Time
+	// OSUser is the operating system user of the session of the transaction
+	OSUser string
+	// OSTerminal is the operating system terminal of the session of the transaction
+	OSTerminal string
+	// Host is the host of the session of the transaction
+	Host string
+	// DBUser is the database user of the session of the transaction
+	DBUser string
+	// CommitNumber is the SCN of a committed transaction, to force the same SCN in the other databases with CommitForce
+	CommitNumber string
+}
+
+// PendingTransactions returns the in-doubt distributed transactions from the DBA_2PC_PENDING view, oldest first,
+// like for an operator to decide which to force with CommitForce and RollbackForce.
+// The user needs select on DBA_2PC_PENDING.
+func PendingTransactions(ctx context.Context, db *sql.DB) ([]PendingTransaction, error) {
+	rows, err := db.QueryContext(ctx, pendingTransactionsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []PendingTransaction
+	for rows.Next() {
+		var transaction PendingTransaction
+		var mixed string
+		var globalTranID, advice, comment, osUser, osTerminal, host, dbUser, commitNumber sql.NullString
+		var failTime, forceTime, retryTime sql.NullTime
+		err = rows.Scan(&transaction.LocalTranID, &globalTranID, &transaction.State, &mixed, &advice, &comment,
+			&failTime, &forceTime, &retryTime, &osUser, &osTerminal, &host, &dbUser, &commitNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		transaction.GlobalTranID = globalTranID.String
+		transaction.Mixed = mixed == "yes"
+		transaction.Advice = advice.String
+		transaction.Comment = comment.String
+		transaction.FailTime = failTime.Time
+		transaction.ForceTime = forceTime.Time
+		transaction.RetryTime = retryTime.Time
+		transaction.OSUser = osUser.String
+		transaction.OSTerminal = osTerminal.String
+		transaction.Host = host.String
+		transaction.DBUser = dbUser.String
+		transaction.CommitNumber = commitNumber.String
+
+		transactions = append(transactions, transaction)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// CommitForceStatement returns the COMMIT FORCE statement that commits the in-doubt transaction with the local transaction id.
+// The commit number is the SCN to commit with, like the CommitNumber of the transaction in a database where it was committed,
+// so the transaction has the same SCN in all the databases. An empty commit number uses the current SCN.
+// The statement can not be bound, so the local transaction id must be numbers separated by dots and the commit number a number.
+func CommitForceStatement(localTranID string, commitNumber string) (string, error) {
+	if !validLocalTranID(localTranID) {
+		return "", fmt.Errorf("invalid local transaction id: %q", localTranID)
+	}
+	if commitNumber == "" {
+		return "commit force '" + localTranID + "'", nil
+	}
+	if !isDigits(commitNumber) {
+		return "", fmt.Errorf("invalid commit number: %q", commitNumber)
+	}
+	return "commit force '" + localTranID + "', " + commitNumber, nil
+}
+
+// RollbackForceStatement returns the ROLLBACK FORCE statement that rolls back the in-doubt transaction with the local transaction id
+func RollbackForceStatement(localTranID string) (string, error) {
+	if !validLocalTranID(localTranID) {
+		return "", fmt.Errorf("invalid local transaction id: %q", localTranID)
+	}
+	return "rollback force '" + localTranID + "'", nil
+}
+
+// CommitForce commits the in-doubt transaction with the local transaction id, see CommitForceStatement.
+// The user needs the FORCE TRANSACTION or FORCE ANY TRANSACTION privilege.
+// The statement must not run in a transaction, so use a *sql.DB or a *sql.Conn.
+func CommitForce(ctx context.Context, execer Execer, localTranID string, commitNumber string) error {
+	query, err := CommitForceStatement(localTranID, commitNumber)
+	if err != nil {
+		return err
+	}
+	_, err = execer.ExecContext(ctx, query)
+	return err
+}
+
+// RollbackForce rolls back the in-doubt transaction with the local transaction id.
+// The user needs the FORCE TRANSACTION or FORCE ANY TRANSACTION privilege.
+// The statement must not run in a transaction, so use a *sql.DB or a *sql.Conn.
+func RollbackForce(ctx context.Context, execer Execer, localTranID string) error {
+	query, err := RollbackForceStatement(localTranID)
+	if err != nil {
+		return err
+	}
+	_, err = execer.ExecContext(ctx, query)
+	return err
+}
+
+// PurgeMixedTransaction removes the DBA_2PC_PENDING entry of a forced transaction with the local transaction id,
+// with DBMS_TRANSACTION.PURGE_MIXED, after the mixed outcome has been reconciled by hand.
+// The user needs execute on DBMS_TRANSACTION.
+func PurgeMixedTransaction(ctx context.Context, execer Execer, localTranID string) error {
+	if !validLocalTranID(localTranID) {
+		return fmt.Errorf("invalid local transaction id: %q", localTranID)
+	}
+	_, err := execer.ExecContext(ctx, "begin dbms_transaction.purge_mixed(:1); end;", localTranID)
+	return err
+}
+
+// validLocalTranID returns true for a local transaction id, numbers separated by dots like 1.21.17
+func validLocalTranID(localTranID string) bool {
+	if localTranID == "" {
+		return false
+	}
+	start := 0
+	for i := 0; i <= len(localTranID); i++ {
+		if i == len(localTranID) || localTranID[i] == '.' {
+			if !isDigits(localTranID[start:i]) {
+				return false
+			}
+			start = i + 1
+		}
+	}
+	return true
+}
+
+// isDigits returns true for a non empty string of ASCII digits
+func isDigits(text string) bool {
+	if text == "" {
+		return false
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
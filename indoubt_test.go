@@ -0,0 +1,64 @@
+package oci8
+
+import (
+	"context"
+	"testing"
+)
+
+// TestPendingTransactions checks listing the in-doubt distributed transactions
+func TestPendingTransactions(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	transactions, err := PendingTransactions(ctx, TestDB)
+	if err != nil {
+		if isOracleError(err, 942) {
+			t.Skip("no access to dba_2pc_pending:", err)
+		}
+		t.Fatal("PendingTransactions error:", err)
+	}
+	for _, transaction := range transactions {
+		if !validLocalTranID(transaction.LocalTranID) {
+			t.Errorf("local transaction id - expected: numbers separated by dots - received: %q", transaction.LocalTranID)
+		}
+	}
+
+	err = RollbackForce(ctx, TestDB, "999.999.999999")
+	if err == nil {
+		t.Error("RollbackForce unknown transaction - expected: error - received: nil")
+	}
+}
+
+// TestForceStatements tests the COMMIT FORCE and ROLLBACK FORCE statements of in-doubt transactions
+func TestForceStatements(t *testing.T) {
+	t.Parallel()
+
+	var forceTests = []struct {
+		localTranID  string
+		commitNumber string
+		commit       string
+		rollback     string
+	}{
+		{"1.21.17", "", "commit force '1.21.17'", "rollback force '1.21.17'"},
+		{"10.5.3021", "2345678", "commit force '10.5.3021', 2345678", "rollback force '10.5.3021'"},
+		{"", "", "", ""},
+		{"1.21.", "", "", ""},
+		{"1.21.17' --", "", "", ""},
+		{"1.21.17", "12a", "", "rollback force '1.21.17'"},
+	}
+	for _, tt := range forceTests {
+		commit, err := CommitForceStatement(tt.localTranID, tt.commitNumber)
+		if commit != tt.commit || (err == nil) != (tt.commit != "") {
+			t.Errorf("CommitForceStatement(%q, %q) - expected: %q - received: %q, %v", tt.localTranID, tt.commitNumber, tt.commit, commit, err)
+		}
+		rollback, err := RollbackForceStatement(tt.localTranID)
+		if rollback != tt.rollback || (err == nil) != (tt.rollback != "") {
+			t.Errorf("RollbackForceStatement(%q) - expected: %q - received: %q, %v", tt.localTranID, tt.rollback, rollback, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"database/sql/driver"
+	"time"
+	"unsafe"
+
+	"github.com/mattn/go-oci8/oracle"
+)
+
+/*
+INTERVAL DAY TO SECOND values are returned as time.Duration, and INTERVAL YEAR TO MONTH values as YearMonth,
+as a number of months does not have a fixed duration.
+With the interval_int64 DSN parameter they are returned as int64 nanoseconds and int64 months instead, like before.
+A time.Duration can be scanned into an int64, a YearMonth can be scanned from the int64 months.
+
+time.Duration and YearMonth bind values are bound as INTERVAL DAY TO SECOND and INTERVAL YEAR TO MONTH,
+so they can be inserted into INTERVAL columns without NUMTODSINTERVAL and NUMTOYMINTERVAL.
+With interval_int64 a time.Duration is bound as int64 nanoseconds instead, like before.
+*/
+
+// YearMonth is an INTERVAL YEAR TO MONTH value. Both fields have the sign of the interval,
+// like YearMonth{Years: -1, Months: -6} for -1-6. Bind a YearMonth to bind an INTERVAL YEAR TO MONTH value.
+type YearMonth = oracle.YearMonth
+
+// NewYearMonth returns the YearMonth of a number of months, like 18 for YearMonth{Years: 1, Months: 6}
+func NewYearMonth(months int64) YearMonth {
+	return oracle.NewYearMonth(months)
+}
+
+// intervalDSValue returns the value of an INTERVAL DAY TO SECOND, a time.Duration, or int64 nanoseconds with interval_int64
+func (conn *Conn) intervalDSValue(interval *C.OCIInterval) (driver.Value, error) {
+	var days C.sb4
+	var hours C.sb4
+	var minutes C.sb4
+	var seconds C.sb4
+	var fracSeconds C.sb4
+	result := C.OCIIntervalGetDaySecond(
+		unsafe.Pointer(conn.env), // environment handle
+		conn.errHandle,           // error handle
+		&days,                    // days
+		&hours,                   // hours
+		&minutes,                 // minutes
+		&seconds,                 // seconds
+		&fracSeconds,             // fractional seconds
+		interval,                 // interval
+	)
+	if result != C.OCI_SUCCESS {
+		return nil, conn.getError(result)
+	}
+
+	nanoseconds := (int64(days) * 24 * int64(time.Hour)) + (int64(hours) * int64(time.Hour)) +
+		(int64(minutes) * int64(time.Minute)) + (int64(seconds) * int64(time.Second)) + int64(fracSeconds)
+	if conn.intervalInt64 {
+		return nanoseconds, nil
+	}
+	return time.Duration(nanoseconds), nil
+}
+
+// intervalYMValue returns the value of an INTERVAL YEAR TO MONTH, a YearMonth, or int64 months with interval_int64
+func (conn *Conn) intervalYMValue(interval *C.OCIInterval) (driver.Value, error) {
+	var years C.sb4
+	var months C.sb4
+	result := C.OCIIntervalGetYearMonth(
+		unsafe.Pointer(conn.env), // environment handle
+		conn.errHandle,           // error handle
+		&years,                   // year
+		&months,                  // month
+		interval,                 // interval
+	)
+	if result != C.OCI_SUCCESS {
+		return nil, conn.getError(result)
+	}
+
+	if conn.intervalInt64 {
+		return (int64(years) * 12) + int64(months), nil
+	}
+	return YearMonth{Years: int(years), Months: int(months)}, nil
+}
+
+// yearMonthToOCIInterval returns an INTERVAL YEAR TO MONTH descriptor of the YearMonth
+func (conn *Conn) yearMonthToOCIInterval(yearMonth YearMonth) (*unsafe.Pointer, error) {
+	intervalPP, _, err := conn.ociDescriptorAlloc(C.OCI_DTYPE_INTERVAL_YM, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	months := yearMonth.TotalMonths()
+	result := C.OCIIntervalSetYearMonth(
+		unsafe.Pointer(conn.env),      // environment handle
+		conn.errHandle,                // error handle
+		C.sb4(months/12),              // year
+		C.sb4(months%12),              // month
+		(*C.OCIInterval)(*intervalPP), // interval
+	)
+	err = conn.getError(result)
+	if err != nil {
+		ociDescriptorFree(*intervalPP, C.OCI_DTYPE_INTERVAL_YM)
+		return nil, err
+	}
+
+	return intervalPP, nil
+}
+
+// durationToOCIInterval returns an INTERVAL DAY TO SECOND descriptor of the duration
+func (conn *Conn) durationToOCIInterval(duration time.Duration) (*unsafe.Pointer, error) {
+	intervalPP, _, err := conn.ociDescriptorAlloc(C.OCI_DTYPE_INTERVAL_DS, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// all the fields have the sign of the duration
+	days := duration / (24 * time.Hour)
+	duration -= days * 24 * time.Hour
+	hours := duration / time.Hour
+	duration -= hours * time.Hour
+	minutes := duration / time.Minute
+	duration -= minutes * time.Minute
+	seconds := duration / time.Second
+	duration -= seconds * time.Second
+	result := C.OCIIntervalSetDaySecond(
+		unsafe.Pointer(conn.env),      // environment handle
+		conn.errHandle,                // error handle
+		C.sb4(days),                   // days
+		C.sb4(hours),                  // hours
+		C.sb4(minutes),                // minutes
+		C.sb4(seconds),                // seconds
+		C.sb4(duration),               // fractional seconds, in nanoseconds
+		(*C.OCIInterval)(*intervalPP), // interval
+	)
+	err = conn.getError(result)
+	if err != nil {
+		ociDescriptorFree(*intervalPP, C.OCI_DTYPE_INTERVAL_DS)
+		return nil, err
+	}
+
+	return intervalPP, nil
+}
@@ -0,0 +1,113 @@
+package oci8
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestYearMonth tests the YearMonth of INTERVAL YEAR TO MONTH values
+func TestYearMonth(t *testing.T) {
+	t.Parallel()
+
+	var yearMonthTests = []struct {
+		months    int64
+		yearMonth YearMonth
+		text      string
+	}{
+		{0, YearMonth{}, "+00-00"},
+		{18, YearMonth{Years: 1, Months: 6}, "+01-06"},
+		{-18, YearMonth{Years: -1, Months: -6}, "-01-06"},
+		{-2, YearMonth{Months: -2}, "-00-02"},
+		{1200, YearMonth{Years: 100}, "+100-00"},
+	}
+	for _, tt := range yearMonthTests {
+		yearMonth := NewYearMonth(tt.months)
+		if yearMonth != tt.yearMonth {
+			t.Errorf("NewYearMonth(%v) - expected: %+v - received: %+v", tt.months, tt.yearMonth, yearMonth)
+		}
+		if yearMonth.TotalMonths() != tt.months {
+			t.Errorf("TotalMonths %+v - expected: %v - received: %v", yearMonth, tt.months, yearMonth.TotalMonths())
+		}
+		if yearMonth.String() != tt.text {
+			t.Errorf("String %+v - expected: %v - received: %v", yearMonth, tt.text, yearMonth.String())
+		}
+
+		var scanned YearMonth
+		err := scanned.Scan(tt.months)
+		if err != nil || scanned != tt.yearMonth {
+			t.Errorf("Scan(%v) - expected: %+v - received: %+v, %v", tt.months, tt.yearMonth, scanned, err)
+		}
+	}
+
+	var scanned YearMonth
+	err := scanned.Scan("+01-06")
+	if err == nil {
+		t.Error("Scan string - expected: error - received: nil")
+	}
+}
+
+// TestSelectIntervalBind checks binding YearMonth and time.Duration values and the interval_int64 setting
+func TestSelectIntervalBind(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	queryResults := testQueryResults{
+		query: "select cast(:1 as INTERVAL YEAR TO MONTH), NUMTODSINTERVAL(:2, 'SECOND') from dual",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{YearMonth{Years: 1, Months: 6}, 90},
+				results: [][]interface{}{{YearMonth{Years: 1, Months: 6}, 90 * time.Second}},
+			},
+			{
+				args:    []interface{}{YearMonth{Years: -2, Months: -3}, -1.5},
+				results: [][]interface{}{{YearMonth{Years: -2, Months: -3}, -1500 * time.Millisecond}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	queryResults = testQueryResults{
+		query: "select cast(:1 as INTERVAL DAY(9) TO SECOND(9)) from dual",
+		queryResults: []testQueryResult{
+			{
+				args:    []interface{}{90 * time.Second},
+				results: [][]interface{}{{90 * time.Second}},
+			},
+			{
+				args:    []interface{}{-(49*time.Hour + 1500*time.Millisecond + 7)},
+				results: [][]interface{}{{-(49*time.Hour + 1500*time.Millisecond + 7)}},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	db := testGetDB("?interval_int64=true")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	var months int64
+	var nanoseconds int64
+	var yearMonth YearMonth
+	err := db.QueryRowContext(ctx, "select cast(:1 as INTERVAL YEAR TO MONTH), NUMTODSINTERVAL(90, 'SECOND'), cast(:2 as INTERVAL YEAR TO MONTH) from dual",
+		YearMonth{Years: 1, Months: 6}, YearMonth{Years: 2}).Scan(&months, &nanoseconds, &yearMonth)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if months != 18 {
+		t.Errorf("months - expected: %v - received: %v", 18, months)
+	}
+	if nanoseconds != int64(90*time.Second) {
+		t.Errorf("nanoseconds - expected: %v - received: %v", int64(90*time.Second), nanoseconds)
+	}
+	if yearMonth != (YearMonth{Years: 2}) {
+		t.Errorf("year month - expected: %+v - received: %+v", YearMonth{Years: 2}, yearMonth)
+	}
+}
@@ -0,0 +1,98 @@
+package oci8
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"reflect"
+)
+
+// IPFormat is how net.IP bind values are bound, see Connector.IPFormat
+type IPFormat int
+
+const (
+	// IPBytes binds a net.IP as the bytes it has, like database/sql,
+	// which are 16 bytes for an IPv4 address from net.ParseIP
+	IPBytes IPFormat = iota
+	// IPText binds a net.IP as VARCHAR2 text, like 192.0.2.1 or 2001:db8::1
+	IPText
+	// IPRaw binds a net.IP as RAW, 4 bytes for an IPv4 address and 16 bytes for an IPv6 address
+	IPRaw
+)
+
+// typeUUID is the [16]byte type the types bound as RAW(16) with Connector.RawUUIDs convert to
+var typeUUID = reflect.TypeOf([16]byte{})
+
+// ScanIP returns a sql.Scanner that scans into ip, from VARCHAR2 text or the 4 or 16 bytes of a RAW.
+// Null scans as a nil net.IP.
+//
+//	var ip net.IP
+//	err = db.QueryRowContext(ctx, "select ADDRESS from HOSTS where ID = :1", id).Scan(oci8.ScanIP(&ip))
+func ScanIP(ip *net.IP) sql.Scanner {
+	return (*ipScanner)(ip)
+}
+
+// ScanUUID returns a sql.Scanner that scans into uuid, from the 16 bytes of a RAW(16) or text in a form ParseGUID accepts.
+// Like GUID, the bytes are kept in the order they are stored in the database. Convert other [16]byte types with a pointer conversion:
+//
+//	var id uuid.UUID
+//	err = db.QueryRowContext(ctx, "select ID from ORDERS where NAME = :1", name).Scan(oci8.ScanUUID((*[16]byte)(&id)))
+func ScanUUID(uuid *[16]byte) sql.Scanner {
+	return (*GUID)(uuid)
+}
+
+// ipScanner scans a net.IP, see ScanIP
+type ipScanner net.IP
+
+// Scan implements sql.Scanner
+func (ip *ipScanner) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*ip = nil
+		return nil
+	case string:
+		parsed := net.ParseIP(src)
+		if parsed == nil {
+			return fmt.Errorf("invalid IP address: %q", src)
+		}
+		*ip = ipScanner(parsed)
+		return nil
+	case []byte:
+		if len(src) != net.IPv4len && len(src) != net.IPv6len {
+			return fmt.Errorf("IP address needs %v or %v bytes, received %v", net.IPv4len, net.IPv6len, len(src))
+		}
+		*ip = append(ipScanner(nil), src...)
+		return nil
+	}
+	return fmt.Errorf("can not scan %T into net.IP", src)
+}
+
+// convertIPUUID returns the value to bind of a net.IP with Connector.IPFormat, or of a [16]byte based type with Connector.RawUUIDs.
+// Returns false for other values.
+func (conn *Conn) convertIPUUID(value interface{}) (interface{}, bool, error) {
+	if ip, ok := value.(net.IP); ok && conn.ipFormat != IPBytes {
+		if len(ip) == 0 {
+			return nil, true, nil
+		}
+		if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+			return nil, true, fmt.Errorf("invalid net.IP length %v", len(ip))
+		}
+		if conn.ipFormat == IPText {
+			return ip.String(), true, nil
+		}
+		if ipv4 := ip.To4(); ipv4 != nil {
+			return []byte(ipv4), true, nil
+		}
+		return []byte(ip), true, nil
+	}
+
+	if conn.rawUUIDs && value != nil {
+		reflectValue := reflect.ValueOf(value)
+		if reflectValue.Type().ConvertibleTo(typeUUID) && reflectValue.Kind() == reflect.Array {
+			uuid := reflectValue.Convert(typeUUID).Interface().([16]byte)
+			return uuid[:], true, nil
+		}
+	}
+
+	return nil, false, nil
+}
@@ -0,0 +1,74 @@
+package oci8
+
+import (
+	"database/sql/driver"
+	"net"
+	"reflect"
+	"testing"
+)
+
+// TestIPUUID tests binding net.IP values and [16]byte based types with the connector options, and ScanIP and ScanUUID
+func TestIPUUID(t *testing.T) {
+	t.Parallel()
+
+	uuid := testUUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	tests := []struct {
+		conn     *Conn
+		value    interface{}
+		expected interface{}
+	}{
+		{conn: &Conn{}, value: uuid, expected: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{conn: &Conn{rawUUIDs: true}, value: uuid, expected: uuid[:]},
+		{conn: &Conn{rawUUIDs: true}, value: [16]byte(uuid), expected: uuid[:]},
+		{conn: &Conn{rawUUIDs: true}, value: GUID(uuid), expected: uuid[:]},
+		{conn: &Conn{}, value: net.ParseIP("192.0.2.1"), expected: net.ParseIP("192.0.2.1")},
+		{conn: &Conn{ipFormat: IPText}, value: net.ParseIP("192.0.2.1"), expected: "192.0.2.1"},
+		{conn: &Conn{ipFormat: IPText}, value: net.ParseIP("2001:db8::1"), expected: "2001:db8::1"},
+		{conn: &Conn{ipFormat: IPRaw}, value: net.ParseIP("192.0.2.1"), expected: []byte{192, 0, 2, 1}},
+		{conn: &Conn{ipFormat: IPRaw}, value: net.ParseIP("2001:db8::1"), expected: []byte(net.ParseIP("2001:db8::1"))},
+		{conn: &Conn{ipFormat: IPRaw}, value: net.IP(nil), expected: nil},
+	}
+	for _, test := range tests {
+		stmt := &Stmt{conn: test.conn}
+		namedValue := &driver.NamedValue{Ordinal: 1, Value: test.value}
+		err := stmt.CheckNamedValue(namedValue)
+		if err != nil && err != driver.ErrSkip {
+			t.Errorf("CheckNamedValue %#v - error: %v", test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(namedValue.Value, test.expected) {
+			t.Errorf("CheckNamedValue %#v - expected: %#v - received: %#v", test.value, test.expected, namedValue.Value)
+		}
+	}
+
+	stmt := &Stmt{conn: &Conn{ipFormat: IPRaw}}
+	err := stmt.CheckNamedValue(&driver.NamedValue{Ordinal: 1, Value: net.IP{1, 2, 3}})
+	if _, ok := err.(*BindError); !ok {
+		t.Errorf("CheckNamedValue invalid IP - expected: %v - received: %v", "*BindError", err)
+	}
+
+	var ip net.IP
+	for _, src := range []interface{}{"192.0.2.1", []byte{192, 0, 2, 1}, []byte(net.ParseIP("192.0.2.1"))} {
+		err = ScanIP(&ip).Scan(src)
+		if err != nil || !ip.Equal(net.ParseIP("192.0.2.1")) {
+			t.Errorf("ScanIP %#v - expected: %v - received: %v %v", src, "192.0.2.1", ip, err)
+		}
+	}
+	err = ScanIP(&ip).Scan(nil)
+	if err != nil || ip != nil {
+		t.Errorf("ScanIP nil - expected: %v - received: %v %v", nil, ip, err)
+	}
+	for _, src := range []interface{}{"host", []byte{1, 2, 3}, int64(1)} {
+		if ScanIP(&ip).Scan(src) == nil {
+			t.Errorf("ScanIP %#v - expected error", src)
+		}
+	}
+
+	var scanned testUUID
+	for _, src := range []interface{}{uuid[:], "6BA7B8109DAD11D180B400C04FD430C8", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"} {
+		err = ScanUUID((*[16]byte)(&scanned)).Scan(src)
+		if err != nil || scanned != uuid {
+			t.Errorf("ScanUUID %#v - expected: %v - received: %v %v", src, uuid, scanned, err)
+		}
+	}
+}
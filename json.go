@@ -0,0 +1,56 @@
+package oci8
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+// jsonBindValue returns the JSON text of a bind value that is a map with string keys,
+// or a struct, or pointer to a struct, that has fields with json tags.
+// ok is false for other values, and for values that implement driver.Valuer, which convert themselves.
+// The JSON text is bound as a string, which Oracle converts for JSON columns and for CLOB or VARCHAR2 columns with IS JSON.
+func jsonBindValue(value interface{}) (text string, ok bool, err error) {
+	if value == nil {
+		return "", false, nil
+	}
+	if _, isValuer := value.(driver.Valuer); isValuer {
+		return "", false, nil
+	}
+
+	valueType := reflect.TypeOf(value)
+	if valueType.Kind() == reflect.Ptr {
+		if reflect.ValueOf(value).IsNil() {
+			return "", false, nil
+		}
+		valueType = valueType.Elem()
+	}
+	switch valueType.Kind() {
+	case reflect.Map:
+		if valueType.Key().Kind() != reflect.String {
+			return "", false, nil
+		}
+	case reflect.Struct:
+		if !hasJSONTags(valueType) {
+			return "", false, nil
+		}
+	default:
+		return "", false, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", true, err
+	}
+	return string(data), true, nil
+}
+
+// hasJSONTags returns true when the struct type has a field with a json tag
+func hasJSONTags(structType reflect.Type) bool {
+	for i := 0; i < structType.NumField(); i++ {
+		if _, ok := structType.Field(i).Tag.Lookup("json"); ok {
+			return true
+		}
+	}
+	return false
+}
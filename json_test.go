@@ -0,0 +1,110 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDestructiveJSONBinds tests inserting maps and structs with json tags into an IS JSON column
+func TestDestructiveJSONBinds(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "JSON_BINDS_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( ID NUMBER(10), DOC CLOB check ( DOC is json ) )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	type document struct {
+		Name  string   `json:"name"`
+		Tags  []string `json:"tags"`
+		Count int      `json:"count"`
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	query := "insert into " + tableName + " ( ID, DOC ) values ( :1, :2 )"
+	_, err = TestDB.ExecContext(ctx, query, 1, map[string]interface{}{"name": "map", "count": 1})
+	if err != nil {
+		t.Fatal("insert map error:", err)
+	}
+	_, err = TestDB.ExecContext(ctx, query, 2, document{Name: "struct", Tags: []string{"a"}, Count: 2})
+	if err != nil {
+		t.Fatal("insert struct error:", err)
+	}
+	_, err = TestDB.ExecContext(ctx, query, 3, &document{Name: strings.Repeat("a", 40000)})
+	if err != nil {
+		t.Fatal("insert long struct error:", err)
+	}
+
+	queryResults := testQueryResults{
+		query: "select ID, json_value(DOC, '$.name' returning varchar2(10) truncate), json_value(DOC, '$.count' returning number(10)) from " + tableName + " order by ID",
+		queryResults: []testQueryResult{{results: [][]interface{}{
+			{int64(1), "map", int64(1)},
+			{int64(2), "struct", int64(2)},
+			{int64(3), "aaaaaaaaaa", int64(0)},
+		}}},
+	}
+	testRunQueryResults(t, queryResults)
+}
+
+// TestJSONBindValue tests maps and structs with json tags are marshaled to JSON text binds
+func TestJSONBindValue(t *testing.T) {
+	t.Parallel()
+
+	type tagged struct {
+		Name  string `json:"name"`
+		Count int    `json:"count,omitempty"`
+	}
+	type untagged struct {
+		Name string
+	}
+	var nilTagged *tagged
+
+	stmt := &Stmt{}
+	var jsonTests = []struct {
+		value    interface{}
+		expected interface{}
+		err      error
+	}{
+		{map[string]interface{}{"a": 1, "b": "x"}, `{"a":1,"b":"x"}`, nil},
+		{tagged{Name: "a", Count: 2}, `{"name":"a","count":2}`, nil},
+		{&tagged{Name: "a"}, `{"name":"a"}`, nil},
+		{nilTagged, nilTagged, driver.ErrSkip},
+		{untagged{Name: "a"}, untagged{Name: "a"}, driver.ErrSkip},
+		{map[int]string{1: "a"}, map[int]string{1: "a"}, driver.ErrSkip},
+		{sql.NullString{String: "a", Valid: true}, "a", nil},
+		{"a", "a", driver.ErrSkip},
+	}
+	for _, tt := range jsonTests {
+		namedValue := &driver.NamedValue{Ordinal: 1, Value: tt.value}
+		err := stmt.CheckNamedValue(namedValue)
+		if err != tt.err {
+			t.Errorf("CheckNamedValue %#v - expected: %v - received: %v", tt.value, tt.err, err)
+			continue
+		}
+		if !reflect.DeepEqual(namedValue.Value, tt.expected) {
+			t.Errorf("CheckNamedValue %#v - expected: %v - received: %v", tt.value, tt.expected, namedValue.Value)
+		}
+	}
+
+	err := stmt.CheckNamedValue(&driver.NamedValue{Ordinal: 1, Value: map[string]interface{}{"a": make(chan int)}})
+	var bindError *BindError
+	if !errors.As(err, &bindError) {
+		t.Errorf("CheckNamedValue chan - expected: %v - received: %v", "*BindError", err)
+	}
+}
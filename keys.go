@@ -0,0 +1,77 @@
+package oci8
+
+import (
+	"strconv"
+	"time"
+)
+
+/*
+Order-preserving keys
+
+Oracle NUMBER and DATE bytes compare in the same order as their values when compared byte by byte, like with bytes.Compare,
+so they can be used as keys of ordered key value stores and caches.
+Equal values always have equal keys, NUMBER keys are normalized, like 1.50 and 1.5 have the same key.
+
+NUMBER keys are 1 to 22 bytes and are not self delimiting. When joining them into a composite key, add a 0 byte after each NUMBER key,
+0 is never used after the first byte of a NUMBER, so the composite keys keep the order.
+DATE keys are always 7 bytes.
+*/
+
+// NumberKey returns the order-preserving key of a number, that is its Oracle NUMBER bytes.
+// The number can be an int, int64, uint64, float64, or a decimal number string, like -123.456 or 1.5e10.
+// Numbers are rounded to the 38 to 40 digit NUMBER precision. Returns ErrInvalidNumber for other values, NaN, and infinity,
+// and ErrNumberOverflow for numbers too large for a NUMBER.
+func NumberKey(number interface{}) ([]byte, error) {
+	switch number := number.(type) {
+	case int:
+		return encodeNumber(strconv.Itoa(number))
+	case int64:
+		return encodeNumber(strconv.FormatInt(number, 10))
+	case uint64:
+		return encodeNumber(strconv.FormatUint(number, 10))
+	case float64:
+		buffer, ok := floatToNumber(number, -1)
+		if !ok {
+			return nil, ErrInvalidNumber
+		}
+		return buffer, nil
+	case string:
+		return encodeNumber(number)
+	}
+	return nil, ErrInvalidNumber
+}
+
+// NumberFromKey returns the decimal number string, like -123.456, of a NUMBER key.
+// Also decodes the bytes of NUMBER columns selected as RAW, like with DUMP or UTL_RAW.CAST_FROM_NUMBER.
+func NumberFromKey(key []byte) (string, error) {
+	if len(key) > numberMaxDigits+2 {
+		return "", ErrInvalidNumber
+	}
+	return decodeNumber(key)
+}
+
+// DateKey returns the order-preserving key of a time, that is its Oracle DATE bytes.
+// Like binding a time to a DATE column, the wall clock of the time in its location is used
+// and fractional seconds are truncated, so use the same location for all keys, like UTC.
+// Returns ErrDateOutOfRange for times outside of the Oracle date range.
+func DateKey(aTime time.Time) ([]byte, error) {
+	_, err := dateRangeTime(aTime, false)
+	if err != nil {
+		return nil, err
+	}
+	return encodeDate(aTime), nil
+}
+
+// DateFromKey returns the time of a DATE key in location
+func DateFromKey(key []byte, location *time.Location) (time.Time, error) {
+	if len(key) != 7 {
+		return time.Time{}, ErrInvalidDate
+	}
+
+	aTime := decodeDate(key, location)
+	if encoded := encodeDate(aTime); string(encoded) != string(key) {
+		// out of range fields, like month 13, are normalized by time.Date
+		return time.Time{}, ErrInvalidDate
+	}
+	return aTime, nil
+}
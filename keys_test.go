@@ -0,0 +1,154 @@
+package oci8
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestSelectNumberKey checks NumberKey returns the Oracle NUMBER bytes
+func TestSelectNumberKey(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	for _, number := range []string{"0", "1", "-1", "123.456", "-123.456", "1.5e10", "-1e-130"} {
+		key, err := NumberKey(number)
+		if err != nil {
+			t.Fatalf("NumberKey(%v) error: %v", number, err)
+		}
+
+		// number literals do not depend on the session NLS numeric characters
+		var buffer []byte
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		err = TestDB.QueryRowContext(ctx, "select utl_raw.cast_from_number("+number+") from dual").Scan(&buffer)
+		cancel()
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		if !bytes.Equal(key, buffer) {
+			t.Errorf("NumberKey(%v) - expected: %v - received: %v", number, buffer, key)
+		}
+	}
+}
+
+// TestNumberKey tests NUMBER keys keep the order of the numbers
+func TestNumberKey(t *testing.T) {
+	t.Parallel()
+
+	numbers := []interface{}{
+		"-1e125", "-1e20", int64(-10000000000), -1000, -100.5, -100, "-99.99", -1.5, -1, -0.5, "-1e-130",
+		0, "1e-130", 0.5, "0.50000000000000000000000000000000000001", 1, "1.0000000000000000000000000000000000001", 1.5,
+		99, uint64(100), 100.5, 1000, int64(1e18), uint64(18446744073709551615), "1e125",
+	}
+
+	var previous []byte
+	for i, number := range numbers {
+		key, err := NumberKey(number)
+		if err != nil {
+			t.Fatalf("NumberKey(%v) error: %v", number, err)
+		}
+		if i > 0 && bytes.Compare(previous, key) >= 0 {
+			t.Errorf("NumberKey(%v) - expected greater than key of %v - received: %v %v", number, numbers[i-1], previous, key)
+		}
+		// composite keys with a 0 byte after the NUMBER key keep the order
+		if i > 0 && bytes.Compare(append(previous, 0, 0xff), append(key, 0, 0)) >= 0 {
+			t.Errorf("NumberKey(%v) composite - expected greater than key of %v", number, numbers[i-1])
+		}
+		previous = key
+	}
+
+	var decodeTests = []struct {
+		number  interface{}
+		decoded string
+	}{
+		{"1.50", "1.5"},
+		{-100.5, "-100.5"},
+		{int64(-10000000000), "-10000000000"},
+		{uint64(18446744073709551615), "18446744073709551615"},
+		{0, "0"},
+	}
+	for _, tt := range decodeTests {
+		key, err := NumberKey(tt.number)
+		if err != nil {
+			t.Fatalf("NumberKey(%v) error: %v", tt.number, err)
+		}
+		decoded, err := NumberFromKey(key)
+		if err != nil {
+			t.Fatalf("NumberFromKey(%v) error: %v", key, err)
+		}
+		if decoded != tt.decoded {
+			t.Errorf("NumberFromKey(%v) - expected: %v - received: %v", key, tt.decoded, decoded)
+		}
+	}
+
+	for _, number := range []interface{}{math.NaN(), math.Inf(1), "abc", true, "1e200"} {
+		_, err := NumberKey(number)
+		if err == nil {
+			t.Errorf("NumberKey(%v) - expected error - received: nil", number)
+		}
+	}
+}
+
+// TestDateKey tests DATE keys keep the order of the times
+func TestDateKey(t *testing.T) {
+	t.Parallel()
+
+	times := []time.Time{
+		time.Date(-4711, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(-4711, 1, 1, 0, 0, 1, 0, time.UTC),
+		time.Date(-4700, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(-99, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(0, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(99, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(100, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 2, 29, 12, 30, 45, 0, time.UTC),
+		time.Date(2020, 2, 29, 12, 31, 0, 0, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC),
+	}
+
+	var previous []byte
+	for i, aTime := range times {
+		key, err := DateKey(aTime)
+		if err != nil {
+			t.Fatalf("DateKey(%v) error: %v", aTime, err)
+		}
+		if i > 0 && bytes.Compare(previous, key) >= 0 {
+			t.Errorf("DateKey(%v) - expected greater than key of %v - received: %v %v", aTime, times[i-1], previous, key)
+		}
+		previous = key
+
+		decoded, err := DateFromKey(key, time.UTC)
+		if err != nil {
+			t.Fatalf("DateFromKey(%v) error: %v", key, err)
+		}
+		if decoded != aTime {
+			t.Errorf("DateFromKey(%v) - expected: %v - received: %v", key, aTime, decoded)
+		}
+	}
+
+	key, err := DateKey(time.Date(2020, 1, 2, 3, 4, 5, 999999999, time.UTC))
+	if err != nil {
+		t.Fatal("DateKey error:", err)
+	}
+	if !reflect.DeepEqual(key, []byte{120, 120, 1, 2, 4, 5, 6}) {
+		t.Errorf("DateKey - expected: %v - received: %v", []byte{120, 120, 1, 2, 4, 5, 6}, key)
+	}
+
+	_, err = DateKey(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != ErrDateOutOfRange {
+		t.Errorf("DateKey out of range - expected: %v - received: %v", ErrDateOutOfRange, err)
+	}
+	for _, key := range [][]byte{nil, {120, 120, 1, 2, 4, 5}, {120, 120, 13, 1, 1, 1, 1}, {120, 120, 2, 30, 1, 1, 1}} {
+		_, err = DateFromKey(key, time.UTC)
+		if err != ErrInvalidDate {
+			t.Errorf("DateFromKey(%v) - expected: %v - received: %v", key, ErrInvalidDate, err)
+		}
+	}
+}
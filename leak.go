@@ -0,0 +1,233 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// LeakReport lists the OCI handles, descriptors, statements, and temporary LOBs of the connections of a connector
+// that were not freed when its last open connection closed
+type LeakReport struct {
+	// Counts are the number not freed by type, like OCI_DTYPE_LOB, statement, or temporary LOB
+	Counts map[string]int
+	// Sites are the allocation stacks of the ones not freed, only recorded when built with the oci8debug build tag
+	Sites []string
+}
+
+// String returns the leak report as text, the counts sorted by type followed by the allocation sites
+func (report LeakReport) String() string {
+	if len(report.Counts) < 1 {
+		return "no leaks"
+	}
+
+	kinds := make([]string, 0, len(report.Counts))
+	for kind := range report.Counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	counts := make([]string, len(kinds))
+	for i, kind := range kinds {
+		counts[i] = kind + ": " + strconv.Itoa(report.Counts[kind])
+	}
+	text := "not freed: " + strings.Join(counts, ", ")
+	for _, site := range report.Sites {
+		text += "\nallocated at:\n" + site
+	}
+	return text
+}
+
+// leakAllocation is a tracked allocation
+type leakAllocation struct {
+	kind  string
+	stack []byte
+}
+
+// leakTracker tracks the allocations of the connections of a connector,
+// from when a connection with a LeakReport hook is opened
+type leakTracker struct {
+	enabled     int32
+	mutex       sync.Mutex
+	allocations map[unsafe.Pointer]leakAllocation
+	openConns   int
+}
+
+var (
+	// leakTrackers are the enabled leak trackers, so freeing an allocation, which does not know its connection,
+	// stops tracking it in the tracker of its connector
+	leakTrackers      []*leakTracker
+	leakTrackersMutex sync.Mutex
+)
+
+// enable starts tracking allocations
+func (tracker *leakTracker) enable() {
+	if !atomic.CompareAndSwapInt32(&tracker.enabled, 0, 1) {
+		return
+	}
+
+	tracker.mutex.Lock()
+	if tracker.allocations == nil {
+		tracker.allocations = make(map[unsafe.Pointer]leakAllocation)
+	}
+	tracker.mutex.Unlock()
+
+	leakTrackersMutex.Lock()
+	leakTrackers = append(leakTrackers, tracker)
+	leakTrackersMutex.Unlock()
+}
+
+// leakFree stops tracking an allocation in the enabled leak trackers, allocations not tracked are ignored
+func leakFree(pointer unsafe.Pointer) {
+	if pointer == nil {
+		return
+	}
+
+	leakTrackersMutex.Lock()
+	trackers := leakTrackers
+	leakTrackersMutex.Unlock()
+
+	for _, tracker := range trackers {
+		tracker.free(pointer)
+	}
+}
+
+// alloc tracks an allocation
+func (tracker *leakTracker) alloc(pointer unsafe.Pointer, kind string) {
+	if tracker == nil || atomic.LoadInt32(&tracker.enabled) == 0 || pointer == nil {
+		return
+	}
+
+	allocation := leakAllocation{kind: kind}
+	if leakStacks {
+		allocation.stack = debug.Stack()
+	}
+
+	tracker.mutex.Lock()
+	tracker.allocations[pointer] = allocation
+	tracker.mutex.Unlock()
+}
+
+// markTemporaryLob changes a tracked LOB locator to a temporary LOB
+func (tracker *leakTracker) markTemporaryLob(pointer unsafe.Pointer) {
+	if tracker == nil || atomic.LoadInt32(&tracker.enabled) == 0 {
+		return
+	}
+
+	tracker.mutex.Lock()
+	if allocation, ok := tracker.allocations[pointer]; ok {
+		allocation.kind = "temporary LOB"
+		tracker.allocations[pointer] = allocation
+	}
+	tracker.mutex.Unlock()
+}
+
+// free stops tracking an allocation, allocations not tracked are ignored
+func (tracker *leakTracker) free(pointer unsafe.Pointer) {
+	if atomic.LoadInt32(&tracker.enabled) == 0 {
+		return
+	}
+
+	tracker.mutex.Lock()
+	delete(tracker.allocations, pointer)
+	tracker.mutex.Unlock()
+}
+
+// connOpened counts an open connection
+func (tracker *leakTracker) connOpened() {
+	if tracker == nil {
+		return
+	}
+
+	tracker.mutex.Lock()
+	tracker.openConns++
+	tracker.mutex.Unlock()
+}
+
+// connClosed counts a closed connection, returns the leak report and true when it was the last open connection
+func (tracker *leakTracker) connClosed() (LeakReport, bool) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.openConns--
+	if tracker.openConns > 0 {
+		return LeakReport{}, false
+	}
+	tracker.openConns = 0
+
+	report := LeakReport{Counts: make(map[string]int)}
+	for _, allocation := range tracker.allocations {
+		report.Counts[allocation.kind]++
+		if allocation.stack != nil {
+			report.Sites = append(report.Sites, string(allocation.stack))
+		}
+	}
+	return report, true
+}
+
+// reportLeaks is called after the connection is closed, when it was the last open connection of its connector
+// the leaks are logged and the LeakReport hook is called
+func (conn *Conn) reportLeaks() {
+	if conn.leaks == nil {
+		return
+	}
+	report, last := conn.leaks.connClosed()
+	if !last || atomic.LoadInt32(&conn.leaks.enabled) == 0 {
+		return
+	}
+
+	if len(report.Counts) > 0 {
+		conn.logger.Print("leak report: ", report)
+	}
+	if conn.hooks.LeakReport != nil {
+		conn.hooks.LeakReport(report)
+	}
+}
+
+// ociTypeName returns the name of an OCI handle or descriptor type
+func ociTypeName(ociType C.ub4) string {
+	switch ociType {
+	case C.OCI_HTYPE_ENV:
+		return "OCI_HTYPE_ENV"
+	case C.OCI_HTYPE_ERROR:
+		return "OCI_HTYPE_ERROR"
+	case C.OCI_HTYPE_SVCCTX:
+		return "OCI_HTYPE_SVCCTX"
+	case C.OCI_HTYPE_STMT:
+		return "OCI_HTYPE_STMT"
+	case C.OCI_HTYPE_DESCRIBE:
+		return "OCI_HTYPE_DESCRIBE"
+	case C.OCI_HTYPE_SERVER:
+		return "OCI_HTYPE_SERVER"
+	case C.OCI_HTYPE_SESSION:
+		return "OCI_HTYPE_SESSION"
+	case C.OCI_HTYPE_TRANS:
+		return "OCI_HTYPE_TRANS"
+	case C.OCI_DTYPE_LOB:
+		return "OCI_DTYPE_LOB"
+	case C.OCI_DTYPE_PARAM:
+		return "OCI_DTYPE_PARAM"
+	case C.OCI_DTYPE_ROWID:
+		return "OCI_DTYPE_ROWID"
+	case C.OCI_DTYPE_INTERVAL_YM:
+		return "OCI_DTYPE_INTERVAL_YM"
+	case C.OCI_DTYPE_INTERVAL_DS:
+		return "OCI_DTYPE_INTERVAL_DS"
+	case C.OCI_DTYPE_TIMESTAMP:
+		return "OCI_DTYPE_TIMESTAMP"
+	case C.OCI_DTYPE_TIMESTAMP_TZ:
+		return "OCI_DTYPE_TIMESTAMP_TZ"
+	case C.OCI_DTYPE_TIMESTAMP_LTZ:
+		return "OCI_DTYPE_TIMESTAMP_LTZ"
+	case C.OCI_DTYPE_VECTOR:
+		return "OCI_DTYPE_VECTOR"
+	}
+	return "OCI type " + strconv.Itoa(int(ociType))
+}
@@ -0,0 +1,7 @@
+//go:build oci8debug
+// +build oci8debug
+
+package oci8
+
+// leakStacks records the allocation stacks in the leak report, enabled with the oci8debug build tag
+const leakStacks = true
@@ -0,0 +1,67 @@
+package oci8
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// TestLeakTracker tests tracking allocations for the leak report
+func TestLeakTracker(t *testing.T) {
+	t.Parallel()
+
+	var tracker leakTracker
+	pointers := []unsafe.Pointer{unsafe.Pointer(new(int)), unsafe.Pointer(new(int)), unsafe.Pointer(new(int))}
+
+	// allocations before enable are not tracked
+	tracker.alloc(pointers[0], "OCI_DTYPE_LOB")
+	tracker.enable()
+	tracker.free(pointers[0])
+	tracker.alloc(pointers[0], "OCI_DTYPE_LOB")
+	tracker.alloc(pointers[1], "OCI_DTYPE_LOB")
+	tracker.alloc(pointers[2], "statement")
+	tracker.markTemporaryLob(pointers[1])
+	leakFree(pointers[0])
+	leakFree(unsafe.Pointer(new(int)))
+
+	// the tracker of another connector does not see the allocations
+	var other leakTracker
+	other.enable()
+	other.alloc(unsafe.Pointer(new(int)), "statement")
+	other.connOpened()
+	otherReport, last := other.connClosed()
+	if !last || !reflect.DeepEqual(otherReport.Counts, map[string]int{"statement": 1}) {
+		t.Errorf("other tracker - expected: %v - received: %v", map[string]int{"statement": 1}, otherReport.Counts)
+	}
+
+	tracker.connOpened()
+	tracker.connOpened()
+	_, last = tracker.connClosed()
+	if last {
+		t.Error("first close - expected last: false - received: true")
+	}
+	report, last := tracker.connClosed()
+	if !last {
+		t.Error("second close - expected last: true - received: false")
+	}
+
+	expected := map[string]int{"temporary LOB": 1, "statement": 1}
+	if !reflect.DeepEqual(report.Counts, expected) {
+		t.Errorf("counts - expected: %v - received: %v", expected, report.Counts)
+	}
+	if (len(report.Sites) == 2) != leakStacks {
+		t.Errorf("sites - expected stacks: %v - received: %v", leakStacks, len(report.Sites))
+	}
+	if !strings.HasPrefix(report.String(), "not freed: statement: 1, temporary LOB: 1") {
+		t.Errorf("string - received: %v", report.String())
+	}
+
+	leakFree(pointers[1])
+	leakFree(pointers[2])
+	tracker.connOpened()
+	report, _ = tracker.connClosed()
+	if report.String() != "no leaks" {
+		t.Errorf("string - expected: no leaks - received: %v", report.String())
+	}
+}
@@ -0,0 +1,337 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// Lob is a BLOB, CLOB, or NCLOB value of a query made with WithLobReaders, read from the database in chunks.
+// Read returns the bytes of a BLOB, or the text of a CLOB or NCLOB in the client character set.
+// A Lob is only valid until the next call to rows.Next or rows.Close, after that reads return ErrLobClosed.
+// A Lob must not be read at the same time as the rows or the connection are used.
+type Lob struct {
+	rows     *Rows
+	rowCount int64 // the row of the rows the LOB was fetched with
+	locator  *C.OCILobLocator
+	form     C.ub1
+	binary   bool
+	offset   C.oraub8 // offset of the next chunk, starting at 1, in bytes for BLOB and characters for CLOB
+	chunk    []byte   // buffer of the chunks read by Read
+	buffer   []byte   // part of the chunk not returned by Read yet
+	eof      bool
+}
+
+// TempLobStats are driver side statistics of the temporary LOBs created by a connection
+type TempLobStats struct {
+	// Created is the number of temporary LOBs created
+	Created int64
+	// BytesWritten is the number of bytes written into temporary LOBs, writes to persistent LOBs are not counted
+	BytesWritten int64
+}
+
+// TempLobUsage is the temporary LOB usage of a session as reported by the server
+type TempLobUsage struct {
+	// CacheLobs is the number of cached temporary LOBs
+	CacheLobs int64
+	// NoCacheLobs is the number of nocache temporary LOBs
+	NoCacheLobs int64
+	// AbstractLobs is the number of abstract temporary LOBs
+	AbstractLobs int64
+	// Blocks is the number of temporary tablespace blocks used by LOB data
+	Blocks int64
+}
+
+// TempLobStats returns the driver side temporary LOB statistics of the connection.
+// Use sql.Conn.Raw to get the underlying *Conn.
+func (conn *Conn) TempLobStats() TempLobStats {
+	return conn.tempLobStats
+}
+
+// TempLobUsage returns the temporary LOB usage of the connection session.
+// Use sql.Conn.Raw to get the underlying *Conn.
+// Requires select privileges on V$TEMPORARY_LOBS, V$TEMPSEG_USAGE, and V$SESSION.
+func (conn *Conn) TempLobUsage(ctx context.Context) (*TempLobUsage, error) {
+	dest := make([]driver.Value, 4)
+	err := conn.queryRow(ctx, `select l.cache_lobs, l.nocache_lobs, l.abstract_lobs,
+	(select nvl(sum(u.blocks), 0) from v$tempseg_usage u, v$session s
+		where u.session_addr = s.saddr and s.sid = l.sid and u.segtype = 'LOB_DATA')
+from v$temporary_lobs l where l.sid = sys_context('USERENV', 'SID')`, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TempLobUsage{
+		CacheLobs:    valueToInt64(dest[0]),
+		NoCacheLobs:  valueToInt64(dest[1]),
+		AbstractLobs: valueToInt64(dest[2]),
+		Blocks:       valueToInt64(dest[3]),
+	}, nil
+}
+
+// LobStream is a bind value that writes the data read from Reader into a temporary LOB in chunks, which is then bound,
+// so a large BLOB or CLOB value can be inserted or updated without holding the whole value in memory:
+//
+//	file, err := os.Open("backup.tar")
+//	...
+//	_, err = db.ExecContext(ctx, "insert into files (name, data) values (:1, :2)", "backup.tar", oci8.LobStream{Reader: file})
+//
+// Reader is read until io.EOF when the statement is executed. A LobStream can only be bound once,
+// as Reader is read to the end, and can not be an OUT bind.
+type LobStream struct {
+	// Reader is the data of the LOB
+	Reader io.Reader
+	// Text binds a CLOB, Reader returns text in the client character set. Otherwise a BLOB is bound.
+	Text bool
+}
+
+// ociLobWriteFrom writes the data read from reader into the LOB in chunks, with OCILobWrite2 in piecewise polling mode,
+// as the total length is not known before reader returns io.EOF
+func (conn *Conn) ociLobWriteFrom(lobLocator *C.OCILobLocator, form C.ub1, reader io.Reader) error {
+	if reader == nil {
+		return errors.New("LobStream has no Reader")
+	}
+
+	// one chunk is read ahead, to know which piece is the last
+	chunk := make([]byte, lobChunkSize)
+	next := make([]byte, lobChunkSize)
+	n, readErr := io.ReadFull(reader, chunk)
+	if readErr == io.EOF {
+		// empty LOB
+		return nil
+	}
+	if readErr != nil && readErr != io.ErrUnexpectedEOF {
+		return readErr
+	}
+	last := readErr == io.ErrUnexpectedEOF
+	readErr = nil
+
+	piece := C.ub1(C.OCI_FIRST_PIECE)
+	for {
+		var nextN int
+		if !last {
+			nextN, readErr = io.ReadFull(reader, next)
+			// a read error ends the piecewise write with this piece, then the error is returned
+			last = readErr != nil && readErr != io.ErrUnexpectedEOF
+		}
+		if last {
+			piece = lastLobPiece(piece)
+		}
+
+		// the amount is 0 for polling mode, with OCI_ONE_PIECE it is the length
+		var writeBytes C.oraub8
+		if piece == C.OCI_ONE_PIECE {
+			writeBytes = C.oraub8(n)
+		}
+		result := C.OCILobWrite2(
+			conn.svc,                  // service context handle
+			conn.errHandle,            // error handle
+			lobLocator,                // LOB locator
+			&writeBytes,               // IN - 0 for polling mode, or the length in one piece. OUT - the number of bytes written
+			nil,                       // maximum number of characters to write
+			1,                         // the offset in the first call, ignored in subsequent polling calls
+			unsafe.Pointer(&chunk[0]), // buffer of the piece
+			C.oraub8(n),               // length of the piece
+			piece,                     // OCI_ONE_PIECE, or OCI_FIRST_PIECE, OCI_NEXT_PIECE, and OCI_LAST_PIECE in polling mode
+			nil,                       // context pointer for the callback function
+			nil,                       // no callback function
+			conn.lobCharset(),         // character set ID of the buffer data, see lobCharset
+			form,                      // character set form of the buffer data
+		)
+		if result != C.OCI_SUCCESS && result != C.OCI_NEED_DATA {
+			return conn.getError(result)
+		}
+		conn.countTempLobWrite(lobLocator, n)
+
+		if last {
+			if readErr != nil && readErr != io.EOF {
+				return readErr
+			}
+			return nil
+		}
+
+		piece = C.OCI_NEXT_PIECE
+		chunk, next = next, chunk
+		n = nextN
+		if readErr == io.ErrUnexpectedEOF {
+			// after a short read the chunk read ahead is the last piece
+			last = true
+			readErr = nil
+		}
+	}
+}
+
+// lastLobPiece returns the piece that ends a piecewise LOB write
+func lastLobPiece(piece C.ub1) C.ub1 {
+	if piece == C.OCI_FIRST_PIECE {
+		return C.OCI_ONE_PIECE
+	}
+	return C.OCI_LAST_PIECE
+}
+
+// newLob returns a Lob for the LOB locator of the define of the current row
+func (rows *Rows) newLob(locator *C.OCILobLocator, define *defineStruct) *Lob {
+	form := define.charsetForm
+	if form == 0 {
+		form = C.SQLCS_IMPLICIT
+	}
+	return &Lob{
+		rows:     rows,
+		rowCount: rows.rowCount,
+		locator:  locator,
+		form:     form,
+		binary:   define.dataType == C.SQLT_BLOB,
+		offset:   1,
+	}
+}
+
+// IsBinary returns true for a BLOB, false for a CLOB or NCLOB
+func (lob *Lob) IsBinary() bool {
+	return lob.binary
+}
+
+// Size returns the length of the LOB, in bytes for a BLOB and in characters for a CLOB or NCLOB
+func (lob *Lob) Size() (int64, error) {
+	err := lob.check()
+	if err != nil {
+		return 0, err
+	}
+
+	conn := lob.rows.stmt.conn
+	var length C.oraub8
+	result := C.OCILobGetLength2(conn.svc, conn.errHandle, lob.locator, &length)
+	if result != C.OCI_SUCCESS {
+		return 0, conn.getError(result)
+	}
+	return int64(length), nil
+}
+
+// Read implements io.Reader, reading the next chunk from the database when the chunk read before is used up
+func (lob *Lob) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if len(lob.buffer) == 0 {
+		if lob.eof {
+			return 0, io.EOF
+		}
+		err := lob.check()
+		if err != nil {
+			return 0, err
+		}
+		if lob.chunk == nil {
+			lob.chunk = make([]byte, lobChunkSize)
+		}
+		n, amount, err := lob.read(lob.chunk, lob.offset)
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			lob.eof = true
+			return 0, io.EOF
+		}
+		lob.offset += amount
+		lob.buffer = lob.chunk[:n]
+	}
+
+	n := copy(p, lob.buffer)
+	lob.buffer = lob.buffer[n:]
+	return n, nil
+}
+
+// ReadAt implements io.ReaderAt for a BLOB, reading len(p) bytes starting at byte offset off,
+// without changing the offset of Read. Returns an error for a CLOB or NCLOB, as their offsets are in characters.
+func (lob *Lob) ReadAt(p []byte, off int64) (int, error) {
+	if !lob.binary {
+		return 0, errors.New("ReadAt needs a BLOB, CLOB offsets are in characters")
+	}
+	if off < 0 {
+		return 0, errors.New("negative LOB offset")
+	}
+
+	total := 0
+	for total < len(p) {
+		err := lob.check()
+		if err != nil {
+			return total, err
+		}
+		n, _, err := lob.read(p[total:], C.oraub8(off)+C.oraub8(total)+1)
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, io.EOF
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// check returns ErrLobClosed when the rows are closed or moved to another row, or the error of the context of the query
+func (lob *Lob) check() error {
+	if lob.rows.closed || lob.rows.rowCount != lob.rowCount {
+		return ErrLobClosed
+	}
+	return lob.rows.stmt.ctx.Err()
+}
+
+// read reads up to len(buffer) bytes of the LOB starting at offset in one round trip.
+// Returns the number of bytes read, and the amount to move the offset by, bytes for BLOB and characters for CLOB.
+// Returns 0 bytes read at the end of the LOB.
+func (lob *Lob) read(buffer []byte, offset C.oraub8) (int, C.oraub8, error) {
+	conn := lob.rows.stmt.conn
+	byteAmount := C.oraub8(len(buffer))
+	var charAmount C.oraub8
+	var charAmountP *C.oraub8
+	if !lob.binary {
+		// a character amount of 0 reads up to byteAmount bytes, and returns the number of characters read
+		charAmountP = &charAmount
+	}
+
+	result := C.OCILobRead2(
+		conn.svc,                   // service context handle
+		conn.errHandle,             // error handle
+		lob.locator,                // LOB locator
+		&byteAmount,                // IN - the max number of bytes to read. OUT - the number of bytes read
+		charAmountP,                // IN - 0 to read by bytes. OUT - the number of characters read
+		offset,                     // the offset to read from, starting at 1, in characters for CLOB and NCLOB
+		unsafe.Pointer(&buffer[0]), // buffer the data is read into
+		C.oraub8(len(buffer)),      // length of the buffer
+		C.OCI_ONE_PIECE,            // read in one piece
+		nil,                        // context pointer for the callback function
+		nil,                        // no callback function
+		conn.lobCharset(),          // character set ID of the buffer data, see lobCharset
+		lob.form,                   // character set form of the buffer data
+	)
+	if result == C.OCI_NO_DATA {
+		// the offset is past the end of the LOB
+		return 0, 0, nil
+	}
+	if result != C.OCI_SUCCESS {
+		return 0, 0, conn.getError(result)
+	}
+
+	if lob.binary {
+		return int(byteAmount), byteAmount, nil
+	}
+	return int(byteAmount), charAmount, nil
+}
+
+// setTempTablespace sets the temporary tablespace of the user to the tablespace or tablespace group name,
+// when it is not already set, so the temporary LOBs of the session go to it. The name is a valid identifier.
+func (conn *Conn) setTempTablespace(name string) error {
+	_, err := conn.exec(context.Background(), `declare
+	l_name varchar2(128) := :1;
+begin
+	for u in (select username from user_users where temporary_tablespace <> l_name) loop
+		execute immediate 'alter user "' || u.username || '" temporary tablespace "' || l_name || '"';
+	end loop;
+end;`, []driver.NamedValue{{Ordinal: 1, Value: identifierName(name)}})
+	return err
+}
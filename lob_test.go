@@ -0,0 +1,202 @@
+package oci8
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestDestructiveLobReaders checks reading BLOB and CLOB values in chunks with WithLobReaders
+func TestDestructiveLobReaders(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "LOB_READERS_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER, B BLOB, C CLOB )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	// larger than a chunk, so the values are read in more than one round trip
+	blob := make([]byte, 600000)
+	for i := range blob {
+		blob[i] = byte(i)
+	}
+	clob := strings.Repeat("abc\u00e4\u20ac", 100000)
+	err = testExec(t, "insert into "+tableName+" ( A, B, C ) values ( :1, :2, :3 )", []interface{}{1, blob, clob})
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+	err = testExec(t, "insert into "+tableName+" ( A, B, C ) values ( :1, :2, :3 )", []interface{}{2, nil, nil})
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	rows, err := TestDB.QueryContext(WithLobReaders(ctx), "select B, C from "+tableName+" order by A")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		t.Fatal("no rows:", rows.Err())
+	}
+	var blobLob *Lob
+	var clobLob *Lob
+	err = rows.Scan(&blobLob, &clobLob)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+
+	size, err := blobLob.Size()
+	if err != nil || size != int64(len(blob)) {
+		t.Errorf("blob size - expected: %v - received: %v, %v", len(blob), size, err)
+	}
+	part := make([]byte, 10)
+	_, err = blobLob.ReadAt(part, 300000)
+	if err != nil || !bytes.Equal(part, blob[300000:300010]) {
+		t.Errorf("blob ReadAt - expected: %v - received: %v, %v", blob[300000:300010], part, err)
+	}
+	data, err := ioutil.ReadAll(blobLob)
+	if err != nil || !bytes.Equal(data, blob) {
+		t.Errorf("blob read - expected: %v bytes - received: %v bytes, %v", len(blob), len(data), err)
+	}
+
+	size, err = clobLob.Size()
+	if err != nil || size != 500000 {
+		t.Errorf("clob size - expected: %v - received: %v, %v", 500000, size, err)
+	}
+	data, err = ioutil.ReadAll(clobLob)
+	if err != nil || string(data) != clob {
+		t.Errorf("clob read - expected: %v bytes - received: %v bytes, %v", len(clob), len(data), err)
+	}
+	_, err = clobLob.ReadAt(part, 0)
+	if err == nil {
+		t.Error("clob ReadAt - expected: error - received: nil")
+	}
+
+	if !rows.Next() {
+		t.Fatal("no second row:", rows.Err())
+	}
+	_, err = blobLob.Read(part)
+	if err != ErrLobClosed {
+		t.Errorf("blob read after next - expected: %v - received: %v", ErrLobClosed, err)
+	}
+	err = rows.Scan(&blobLob, &clobLob)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if blobLob != nil || clobLob != nil {
+		t.Errorf("null lobs - expected: nil, nil - received: %v, %v", blobLob, clobLob)
+	}
+}
+
+// TestLobPrefetch checks LOB values smaller and larger than lob_prefetch_size are read in full
+func TestLobPrefetch(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?lob_prefetch_size=100")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, "select to_clob(rpad('a', level * 60, 'b')), to_blob(utl_raw.cast_to_raw(rpad('c', level * 60, 'd'))) from dual connect by level <= 3")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	level := 0
+	for rows.Next() {
+		level++
+		var clob string
+		var blob []byte
+		err = rows.Scan(&clob, &blob)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		expected := "a" + strings.Repeat("b", level*60-1)
+		if clob != expected {
+			t.Errorf("clob - expected: %v - received: %v", expected, clob)
+		}
+		expected = "c" + strings.Repeat("d", level*60-1)
+		if string(blob) != expected {
+			t.Errorf("blob - expected: %v - received: %v", expected, string(blob))
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Fatal("rows error:", err)
+	}
+	if level != 3 {
+		t.Errorf("rows - expected: %v - received: %v", 3, level)
+	}
+}
+
+// TestDestructiveLobStream checks inserting BLOB and CLOB values read from a LobStream
+func TestDestructiveLobStream(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "LOB_STREAM_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER, B BLOB, C CLOB )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	// more than two chunks and not a multiple of the chunk size
+	blob := make([]byte, 700000)
+	for i := range blob {
+		blob[i] = byte(i % 251)
+	}
+	clob := strings.Repeat("abc\u00e4\u20ac", 100000)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	query := "insert into " + tableName + " ( A, B, C ) values ( :1, :2, :3 )"
+	_, err = TestDB.ExecContext(ctx, query, 1, LobStream{Reader: bytes.NewReader(blob)}, LobStream{Reader: strings.NewReader(clob), Text: true})
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+	_, err = TestDB.ExecContext(ctx, query, 2, LobStream{Reader: bytes.NewReader(nil)}, LobStream{Reader: strings.NewReader("a"), Text: true})
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+	_, err = TestDB.ExecContext(ctx, query, 3, LobStream{Reader: iotest.TimeoutReader(bytes.NewReader(blob))}, nil)
+	if err == nil || !strings.Contains(err.Error(), iotest.ErrTimeout.Error()) {
+		t.Errorf("insert error - expected: %v - received: %v", iotest.ErrTimeout, err)
+	}
+
+	queryResults := testQueryResults{
+		query: "select A, B, C from " + tableName + " order by A",
+		queryResults: []testQueryResult{
+			{
+				results: [][]interface{}{
+					{int64(1), blob, clob},
+					{int64(2), []byte{}, "a"},
+				},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+}
@@ -0,0 +1,150 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Lock is an exclusive DBMS_LOCK lock, used as a mutex between processes that share the database.
+// The lock is held by the session of a connection taken from the sql.DB for the lifetime of the lock,
+// so when the session is lost, for example when the process dies or the network fails, the database releases the lock.
+// A Lock must not be used from more than one goroutine at the same time.
+type Lock struct {
+	// Name is the lock name
+	Name string
+
+	session *PinnedSession
+	handle  string
+}
+
+const (
+	// maxLockWait is DBMS_LOCK.MAXWAIT, a request waits for the lock without a timeout
+	maxLockWait = 32767
+
+	// acquireLockQuery allocates the lock handle of the name and requests the lock in exclusive mode,
+	// the lock is not released on commit. ALLOCATE_UNIQUE commits, which is nothing on the connection of the lock.
+	acquireLockQuery = `declare
+	lock_handle varchar2(128);
+begin
+	dbms_lock.allocate_unique(:1, lock_handle);
+	:2 := dbms_lock.request(lock_handle, dbms_lock.x_mode, :3, false);
+	:4 := lock_handle;
+end;`
+
+	// releaseLockQuery releases the lock of the handle
+	releaseLockQuery = "begin :1 := dbms_lock.release(:2); end;"
+)
+
+// AcquireLock requests the exclusive lock with the name, waiting up to timeout for another session to release it.
+// Returns a *LockError that errors.Is matches with ErrLockTimeout when the timeout is reached.
+// The timeout is rounded up to seconds, a negative timeout waits without a timeout. Cancel ctx to stop waiting.
+//
+// The lock name is up to 128 bytes, names starting with ORA$ are reserved by Oracle.
+// The user needs execute on DBMS_LOCK.
+//
+//	lock, err := oci8.AcquireLock(ctx, db, "nightly-report", time.Minute)
+//	if err != nil {
+//		return err
+//	}
+//	defer lock.Release(context.Background())
+//
+// Use Check to confirm the lock is still held before work that depends on it.
+func AcquireLock(ctx context.Context, db *sql.DB, name string, timeout time.Duration) (*Lock, error) {
+	if name == "" || len(name) > 128 || strings.HasPrefix(strings.ToUpper(name), "ORA$") {
+		return nil, fmt.Errorf("invalid lock name: %q", name)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	session, err := PinSession(ctx, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	lock := &Lock{Name: name, session: session}
+	var status int64
+	_, err = conn.ExecContext(ctx, acquireLockQuery, name, sql.Out{Dest: &status}, lockWaitSeconds(timeout), sql.Out{Dest: &lock.handle})
+	if err != nil {
+		// the lock could have been granted before the error, discarding the session releases it
+		lock.close(true)
+		return nil, err
+	}
+	if status != 0 {
+		lock.close(false)
+		return nil, &LockError{Name: name, Operation: "request", Status: int(status)}
+	}
+
+	return lock, nil
+}
+
+// lockWaitSeconds returns the DBMS_LOCK.REQUEST timeout in seconds of a timeout, rounded up
+func lockWaitSeconds(timeout time.Duration) int64 {
+	if timeout < 0 {
+		return maxLockWait
+	}
+	seconds := math.Ceil(timeout.Seconds())
+	if seconds >= maxLockWait {
+		return maxLockWait
+	}
+	return int64(seconds)
+}
+
+// Check returns nil when the lock is still held, or a *SessionLostError when the session, and with it the lock, was lost
+func (lock *Lock) Check(ctx context.Context) error {
+	if lock.session == nil {
+		return errors.New("lock " + lock.Name + " is released")
+	}
+	return lock.session.Check(ctx)
+}
+
+// Release releases the lock and returns its connection to the sql.DB.
+// When releasing fails the connection is closed, which releases the lock with its session, and the error is returned.
+// Returns nil when the session was lost, as the database released the lock with it.
+// Calling Release again does nothing.
+func (lock *Lock) Release(ctx context.Context) error {
+	if lock.session == nil {
+		return nil
+	}
+
+	var status int64
+	_, err := lock.session.Conn().ExecContext(ctx, releaseLockQuery, sql.Out{Dest: &status}, lock.handle)
+	if err != nil {
+		// discarding the session releases the lock
+		lock.close(true)
+		if errors.Is(err, ErrSessionLost) {
+			return nil
+		}
+		return err
+	}
+	if status != 0 {
+		lock.close(true)
+		return &LockError{Name: lock.Name, Operation: "release", Status: int(status)}
+	}
+
+	lock.close(false)
+	return nil
+}
+
+// close unpins and closes the connection of the lock.
+// With discard the connection is closed instead of returned to the sql.DB, which ends its session and the locks of the session.
+func (lock *Lock) close(discard bool) {
+	conn := lock.session.Conn()
+	// errors are for a connection that database/sql already discarded
+	_ = lock.session.Unpin()
+	if discard {
+		_ = conn.Raw(func(driverConn interface{}) error {
+			return driver.ErrBadConn
+		})
+	}
+	_ = conn.Close()
+	lock.session = nil
+}
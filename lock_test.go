@@ -0,0 +1,89 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestLock checks a DBMS_LOCK lock excludes other sessions until released
+func TestLock(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	name := "OCI8_TEST_LOCK_" + TestTimeString
+	lock, err := AcquireLock(ctx, TestDB, name, time.Second)
+	if err != nil {
+		t.Fatal("acquire lock error:", err)
+	}
+	err = lock.Check(ctx)
+	if err != nil {
+		t.Error("check lock error:", err)
+	}
+
+	_, err = AcquireLock(ctx, TestDB, name, 0)
+	if !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("acquire held lock - expected: %v - received: %v", ErrLockTimeout, err)
+	}
+
+	err = lock.Release(ctx)
+	if err != nil {
+		t.Fatal("release lock error:", err)
+	}
+	err = lock.Release(ctx)
+	if err != nil {
+		t.Error("release lock again error:", err)
+	}
+
+	lock, err = AcquireLock(ctx, TestDB, name, 0)
+	if err != nil {
+		t.Fatal("acquire released lock error:", err)
+	}
+	err = lock.Release(ctx)
+	if err != nil {
+		t.Error("release lock error:", err)
+	}
+}
+
+// TestLockWaitSeconds tests the DBMS_LOCK timeouts and lock errors
+func TestLockWaitSeconds(t *testing.T) {
+	t.Parallel()
+
+	var waitTests = []struct {
+		timeout time.Duration
+		seconds int64
+	}{
+		{0, 0},
+		{time.Millisecond, 1},
+		{2 * time.Second, 2},
+		{-1, 32767},
+		{24 * time.Hour, 32767},
+	}
+	for _, tt := range waitTests {
+		seconds := lockWaitSeconds(tt.timeout)
+		if seconds != tt.seconds {
+			t.Errorf("lockWaitSeconds %v - expected: %v - received: %v", tt.timeout, tt.seconds, seconds)
+		}
+	}
+
+	if !errors.Is(&LockError{Name: "a", Operation: "request", Status: 1}, ErrLockTimeout) {
+		t.Errorf("LockError status 1 - expected: ErrLockTimeout - received: not matched")
+	}
+	if errors.Is(&LockError{Name: "a", Operation: "request", Status: 2}, ErrLockTimeout) {
+		t.Errorf("LockError status 2 - expected: not matched - received: ErrLockTimeout")
+	}
+	_, err := AcquireLock(context.Background(), nil, "ORA$LOCK", 0)
+	if err == nil {
+		t.Errorf("AcquireLock ORA$LOCK - expected: error - received: nil")
+	}
+}
@@ -0,0 +1,98 @@
+package oci8
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSelectLongPieces(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "SELECT_LONG_PIECES_" + TestTimeString
+	testExecQuery(t, "create table "+tableName+" ( A INTEGER, B LONG )", nil)
+	defer testDropTable(t, tableName)
+
+	value := strings.Repeat("0123456789", 3000)
+	testExecQuery(t, "insert into "+tableName+" ( A, B ) values (1, :1)", []interface{}{value})
+	testExecQuery(t, "insert into "+tableName+" ( A, B ) values (2, null)", nil)
+
+	var buffer bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	ctx = WithLongPieces(ctx, func(column int, piece []byte) error {
+		if column != 1 {
+			t.Errorf("column - expected: 1 - received: %v", column)
+		}
+		buffer.Write(piece)
+		return nil
+	})
+	rows, err := TestDB.QueryContext(ctx, "select A, B from "+tableName+" order by A")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	var a int64
+	var b interface{}
+	if !rows.Next() {
+		t.Fatal("expected a row, rows error:", rows.Err())
+	}
+	err = rows.Scan(&a, &b)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if b != int64(len(value)) {
+		t.Errorf("total - expected: %v - received: %v", len(value), b)
+	}
+	if buffer.String() != value {
+		t.Errorf("pieces - expected length: %v - received length: %v", len(value), buffer.Len())
+	}
+
+	if !rows.Next() {
+		t.Fatal("expected a row, rows error:", rows.Err())
+	}
+	err = rows.Scan(&a, &b)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if b != nil {
+		t.Errorf("null - expected: nil - received: %v", b)
+	}
+
+	if rows.Next() {
+		t.Fatal("expected no more rows")
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Fatal("rows error:", err)
+	}
+}
+
+// TestLongPiecesContext tests setting the long pieces function on a context
+func TestLongPiecesContext(t *testing.T) {
+	t.Parallel()
+
+	if longPiecesFromContext(context.Background()) != nil {
+		t.Fatal("expected nil long pieces function")
+	}
+
+	var called bool
+	ctx := WithLongPieces(context.Background(), func(column int, piece []byte) error {
+		called = true
+		return nil
+	})
+	fn := longPiecesFromContext(ctx)
+	if fn == nil {
+		t.Fatal("expected long pieces function")
+	}
+	err := fn(0, nil)
+	if err != nil || !called {
+		t.Errorf("long pieces function - called: %v - error: %v", called, err)
+	}
+}
@@ -0,0 +1,76 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// TestSelectMaxRows checks the max rows limit of queries
+func TestSelectMaxRows(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	query := "select level from dual connect by level <= 5"
+	countRows := func(ctx context.Context, db *sql.DB) (int, error) {
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return 0, err
+		}
+		defer rows.Close()
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		return count, rows.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	count, err := countRows(WithMaxRows(ctx, 3), TestDB)
+	if err != ErrTooManyRows {
+		t.Errorf("max rows 3 error - expected: %v - received: %v", ErrTooManyRows, err)
+	}
+	if count != 3 {
+		t.Errorf("max rows 3 count - expected: 3 - received: %v", count)
+	}
+
+	count, err = countRows(WithMaxRows(ctx, 5), TestDB)
+	if err != nil {
+		t.Errorf("max rows 5 error - expected: nil - received: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("max rows 5 count - expected: 5 - received: %v", count)
+	}
+
+	db := testGetDB("?max_rows=2")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}()
+
+	count, err = countRows(ctx, db)
+	if err != ErrTooManyRows {
+		t.Errorf("max_rows 2 error - expected: %v - received: %v", ErrTooManyRows, err)
+	}
+	if count != 2 {
+		t.Errorf("max_rows 2 count - expected: 2 - received: %v", count)
+	}
+
+	count, err = countRows(WithMaxRows(ctx, 10), db)
+	if err != nil {
+		t.Errorf("max rows 10 error - expected: nil - received: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("max rows 10 count - expected: 5 - received: %v", count)
+	}
+}
@@ -0,0 +1,95 @@
+package oci8
+
+import (
+	"context"
+	"math"
+	"testing"
+	"unsafe"
+)
+
+// TestSelectMemoryBudget checks a query with a memory budget
+func TestSelectMemoryBudget(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	query := "select cast ('a' as VARCHAR2(4000)) from dual connect by level <= 100"
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	_, err := TestDB.QueryContext(WithMemoryBudget(ctx, 1000), query)
+	memoryBudgetError, ok := err.(*MemoryBudgetError)
+	if !ok {
+		t.Fatalf("query - expected: *MemoryBudgetError - received: %v", err)
+	}
+	if memoryBudgetError.Budget != 1000 || memoryBudgetError.RowSize <= 4000 {
+		t.Errorf("memory budget error - received: %+v", memoryBudgetError)
+	}
+
+	rows, err := TestDB.QueryContext(WithMemoryBudget(ctx, 50000), query)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	if rows.Err() != nil {
+		t.Fatal("rows error:", rows.Err())
+	}
+	if count != 100 {
+		t.Errorf("count - expected: 100 - received: %v", count)
+	}
+}
+
+// TestMemoryBudget tests the memory budget context and row size
+func TestMemoryBudget(t *testing.T) {
+	t.Parallel()
+
+	if memoryBudgetFromContext(context.Background()) != 0 {
+		t.Error("memory budget expected 0 when not set")
+	}
+	if memoryBudgetFromContext(WithMemoryBudget(context.Background(), 1024)) != 1024 {
+		t.Error("memory budget expected 1024")
+	}
+
+	var buffer [1]byte
+	defines := []defineStruct{
+		{maxSize: 8, pbuf: unsafe.Pointer(&buffer)},
+		{maxSize: 4000, pbuf: unsafe.Pointer(&buffer)},
+		{skip: true},
+		{piecewise: true, maxSize: 1<<31 - 1},
+	}
+	// indicator and length are 4 bytes per column
+	expected := int64(8 + 4000 + longPieceSize + 4*4)
+	if rowSize := defineRowSize(defines); rowSize != expected {
+		t.Errorf("defineRowSize - expected: %v - received: %v", expected, rowSize)
+	}
+
+	prefetchTests := []struct {
+		prefetchRows uint32
+		budget       int64
+		rowSize      int64
+		expected     uint32
+	}{
+		{prefetchRows: 10, budget: 100000, rowSize: 100, expected: 10},
+		{prefetchRows: 10000, budget: 100000, rowSize: 100, expected: 1000},
+		{prefetchRows: 0, budget: 100000, rowSize: 100, expected: 1000},
+		{prefetchRows: 10, budget: 100, rowSize: 100, expected: 1},
+		{prefetchRows: 0, budget: 1 << 62, rowSize: 1, expected: math.MaxUint32},
+	}
+	for _, test := range prefetchTests {
+		if rows := budgetPrefetchRows(test.prefetchRows, test.budget, test.rowSize); rows != test.expected {
+			t.Errorf("budgetPrefetchRows %+v - expected: %v - received: %v", test, test.expected, rows)
+		}
+	}
+
+	err := &MemoryBudgetError{Budget: 100, RowSize: 4016}
+	if err.Error() != "query row size of 4016 bytes exceeds memory budget of 100 bytes" {
+		t.Errorf("error - received: %v", err.Error())
+	}
+}
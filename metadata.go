@@ -0,0 +1,203 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+const (
+	// ddlTransformsQuery sets the DBMS_METADATA session transforms of GetDDL: terminated statements,
+	// without the storage and tablespace clauses, so the DDL of schemas in different databases can be compared
+	ddlTransformsQuery = `begin
+	dbms_metadata.set_transform_param(dbms_metadata.session_transform, 'SQLTERMINATOR', true);
+	dbms_metadata.set_transform_param(dbms_metadata.session_transform, 'PRETTY', true);
+	dbms_metadata.set_transform_param(dbms_metadata.session_transform, 'SEGMENT_ATTRIBUTES', false);
+	dbms_metadata.set_transform_param(dbms_metadata.session_transform, 'STORAGE', false);
+end;`
+
+	// ddlResetQuery sets the DBMS_METADATA session transforms back to the defaults
+	ddlResetQuery = "begin dbms_metadata.set_transform_param(dbms_metadata.session_transform, 'DEFAULT'); end;"
+
+	// ddlQuery selects the DDL of an object, a null schema is the current schema
+	ddlQuery = "select dbms_metadata.get_ddl(:1, :2, :3) from dual"
+)
+
+// GetDDL returns the DDL of an object using DBMS_METADATA.GET_DDL, like for schema sync tools.
+// The object type is a DBMS_METADATA object type, like TABLE, VIEW, INDEX, or PACKAGE BODY,
+// which can also be written with an underscore, like PACKAGE_BODY.
+// The name is the object name as stored in the data dictionary, usually upper case,
+// optionally prefixed with the owner, like OWNER.NAME. Without an owner the current schema is used.
+// ORA-31603 is returned when the object does not exist or is not visible to the user.
+//
+// Each statement ends with a terminator and the storage and segment attributes, like TABLESPACE, are left out.
+// The transforms are set on the session of conn, and set back to the defaults after.
+// The DDL CLOB is read in chunks by the driver, so large packages are returned in full.
+func GetDDL(ctx context.Context, conn *sql.Conn, objectType string, name string) (string, error) {
+	objectType, schema, name, err := ddlObject(objectType, name)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = conn.ExecContext(ctx, ddlTransformsQuery)
+	if err != nil {
+		return "", err
+	}
+
+	var ddl string
+	err = conn.QueryRowContext(ctx, ddlQuery, objectType, name, schema).Scan(&ddl)
+
+	_, resetErr := conn.ExecContext(ctx, ddlResetQuery)
+	if err != nil {
+		return "", err
+	}
+	if resetErr != nil {
+		return "", resetErr
+	}
+
+	return strings.TrimSpace(ddl), nil
+}
+
+// ddlObject returns the DBMS_METADATA object type, the schema, nil for the current schema, and the name of an object
+func ddlObject(objectType string, name string) (string, interface{}, string, error) {
+	objectType = strings.Join(strings.Fields(strings.ToUpper(objectType)), "_")
+	if objectType == "" {
+		return "", nil, "", errors.New("empty object type")
+	}
+
+	var schema interface{}
+	if index := strings.Index(name, "."); index >= 0 {
+		schema = name[:index]
+		name = name[index+1:]
+		if schema == "" {
+			return "", nil, "", errors.New("empty schema name")
+		}
+	}
+	if name == "" {
+		return "", nil, "", errors.New("empty object name")
+	}
+
+	return objectType, schema, name, nil
+}
+
+// tableColumnsQuery selects the columns of a table with their defaults and identity options, a null schema is the current schema.
+// user_generated excludes the hidden columns Oracle adds, like for function-based indexes, but keeps invisible columns.
+const tableColumnsQuery = `select c.column_name, c.data_type, c.nullable, c.data_default, c.default_on_null,
+	c.virtual_column, c.hidden_column, c.identity_column, i.generation_type, i.sequence_name, i.identity_options
+from all_tab_cols c
+left join all_tab_identity_cols i on i.owner = c.owner and i.table_name = c.table_name and i.column_name = c.column_name
+where c.owner = nvl(:1, sys_context('USERENV', 'CURRENT_SCHEMA')) and c.table_name = :2 and c.user_generated = 'YES'
+order by c.column_id nulls last, c.internal_column_id`
+
+// TableColumn is the default, identity, and virtual column metadata of a column, from the data dictionary
+type TableColumn struct {
+	// Name is the column name as stored in the data dictionary
+	Name string
+	// DataType is the data type name, like NUMBER, VARCHAR2, or TIMESTAMP(6)
+	DataType string
+	// Nullable is false for NOT NULL columns
+	Nullable bool
+	// Default is the default expression, like sysdate or 'N', empty when the column has no default.
+	// For a virtual column it is the column expression, see Virtual.
+	Default string
+	// DefaultOnNull is true for DEFAULT ON NULL columns, which get the default when null is inserted
+	DefaultOnNull bool
+	// Sequence is the sequence that generates the values, like OWNER.ORDER_SEQ,
+	// of an identity column or of a column with a sequence NEXTVAL default, empty otherwise
+	Sequence string
+	// Identity is true for an identity column
+	Identity bool
+	// IdentityAlways is true for a GENERATED ALWAYS identity column, which can not be inserted or updated,
+	// false for GENERATED BY DEFAULT
+	IdentityAlways bool
+	// IdentityOptions are the sequence options of an identity column, like START WITH: 1, INCREMENT BY: 1, ...
+	IdentityOptions string
+	// Virtual is true for a virtual column, its expression is Default
+	Virtual bool
+	// Hidden is true for an INVISIBLE column, which is not returned by select *
+	Hidden bool
+}
+
+// TableColumns returns the columns of a table with their defaults, identity options, and virtual column expressions
+// from the ALL_TAB_COLS and ALL_TAB_IDENTITY_COLS views, in column order with invisible columns last,
+// like for code generators that need to know which columns to leave out of an insert.
+// The name is the table name as stored in the data dictionary, usually upper case,
+// optionally prefixed with the owner, like OWNER.NAME. Without an owner the current schema is used.
+// ErrTableNotFound is returned when the table does not exist or is not visible to the user.
+// Needs Oracle 12c or later. Defaults longer than 4000 bytes are truncated, as the dictionary stores them as LONG.
+func TableColumns(ctx context.Context, db *sql.DB, name string) ([]TableColumn, error) {
+	_, schema, name, err := ddlObject("TABLE", name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, tableColumnsQuery, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []TableColumn
+	for rows.Next() {
+		var column TableColumn
+		var nullable, defaultOnNull, virtual, hidden, identity string
+		var dataDefault, generationType, sequenceName, identityOptions sql.NullString
+		err = rows.Scan(&column.Name, &column.DataType, &nullable, &dataDefault, &defaultOnNull,
+			&virtual, &hidden, &identity, &generationType, &sequenceName, &identityOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		column.Nullable = nullable == "Y"
+		column.Default = strings.TrimSpace(dataDefault.String)
+		column.DefaultOnNull = defaultOnNull == "YES"
+		column.Virtual = virtual == "YES"
+		column.Hidden = hidden == "YES"
+		column.Identity = identity == "YES"
+		column.IdentityAlways = generationType.String == "ALWAYS"
+		column.IdentityOptions = identityOptions.String
+		if !column.Virtual {
+			// the default of an identity column is its sequence NEXTVAL, like "OWNER"."ISEQ$$_123".nextval
+			column.Sequence = defaultSequence(column.Default)
+		}
+		if column.Identity && column.Sequence == "" {
+			column.Sequence = sequenceName.String
+		}
+
+		columns = append(columns, column)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) < 1 {
+		return nil, ErrTableNotFound
+	}
+
+	return columns, nil
+}
+
+// defaultSequence returns the sequence name of a default that is only a sequence NEXTVAL, like "OWNER"."ORDER_SEQ".nextval,
+// without the quotes, like OWNER.ORDER_SEQ, empty for other defaults
+func defaultSequence(dataDefault string) string {
+	const nextval = ".NEXTVAL"
+	if len(dataDefault) <= len(nextval) || !strings.EqualFold(dataDefault[len(dataDefault)-len(nextval):], nextval) {
+		return ""
+	}
+
+	parts := strings.Split(dataDefault[:len(dataDefault)-len(nextval)], ".")
+	if len(parts) > 2 {
+		return ""
+	}
+	for i, part := range parts {
+		if len(part) > 1 && part[0] == '"' && part[len(part)-1] == '"' {
+			parts[i] = part[1 : len(part)-1]
+		} else if isIdentifier(part) {
+			parts[i] = strings.ToUpper(part)
+		} else {
+			return ""
+		}
+	}
+	return strings.Join(parts, ".")
+}
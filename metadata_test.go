@@ -0,0 +1,156 @@
+package oci8
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestDestructiveGetDDL checks getting the DDL of a table
+func TestDestructiveGetDDL(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "GET_DDL_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER, B VARCHAR2(20) )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	ddl, err := GetDDL(ctx, conn, "table", tableName)
+	if err != nil {
+		t.Fatal("GetDDL error:", err)
+	}
+	if !strings.Contains(ddl, "CREATE TABLE") || !strings.Contains(ddl, tableName) || !strings.HasSuffix(ddl, ";") {
+		t.Errorf("GetDDL - expected: CREATE TABLE %v ... ; - received: %v", tableName, ddl)
+	}
+	if strings.Contains(ddl, "STORAGE(") || strings.Contains(ddl, "TABLESPACE") {
+		t.Errorf("GetDDL - expected: no segment attributes - received: %v", ddl)
+	}
+
+	_, err = GetDDL(ctx, conn, "table", tableName+"_NONE")
+	if !isOracleError(err, 31603) {
+		t.Errorf("GetDDL error - expected: ORA-31603 - received: %v", err)
+	}
+}
+
+// TestDestructiveTableColumns checks the default, identity, and virtual column metadata of a table
+func TestDestructiveTableColumns(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "TABLE_COLUMNS_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( ID NUMBER generated always as identity, A VARCHAR2(1) default 'N' not null,"+
+		" B NUMBER default on null 0, C NUMBER generated always as (B * 2) virtual, D DATE invisible )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	columns, err := TableColumns(ctx, TestDB, tableName)
+	if err != nil {
+		t.Fatal("TableColumns error:", err)
+	}
+	if len(columns) != 5 {
+		t.Fatalf("columns - expected: %v - received: %+v", 5, columns)
+	}
+
+	if columns[0].Name != "ID" || !columns[0].Identity || !columns[0].IdentityAlways || columns[0].Sequence == "" ||
+		!strings.Contains(columns[0].IdentityOptions, "START WITH") || columns[0].Nullable {
+		t.Errorf("identity column - received: %+v", columns[0])
+	}
+	if columns[1].Name != "A" || columns[1].Default != "'N'" || columns[1].Nullable || columns[1].Identity || columns[1].Sequence != "" {
+		t.Errorf("default column - received: %+v", columns[1])
+	}
+	if columns[2].Name != "B" || columns[2].Default != "0" || !columns[2].DefaultOnNull {
+		t.Errorf("default on null column - received: %+v", columns[2])
+	}
+	if columns[3].Name != "C" || !columns[3].Virtual || !strings.Contains(columns[3].Default, "\"B\"*2") {
+		t.Errorf("virtual column - received: %+v", columns[3])
+	}
+	if columns[4].Name != "D" || !columns[4].Hidden || columns[4].DataType != "DATE" || !columns[4].Nullable {
+		t.Errorf("invisible column - received: %+v", columns[4])
+	}
+
+	_, err = TableColumns(ctx, TestDB, tableName+"_NONE")
+	if err != ErrTableNotFound {
+		t.Errorf("TableColumns - expected: %v - received: %v", ErrTableNotFound, err)
+	}
+}
+
+// TestDDLObject tests the object types and names passed to DBMS_METADATA
+func TestDDLObject(t *testing.T) {
+	t.Parallel()
+
+	var objectTests = []struct {
+		objectType string
+		name       string
+		typeOut    string
+		schema     interface{}
+		nameOut    string
+		err        bool
+	}{
+		{"table", "EMP", "TABLE", nil, "EMP", false},
+		{"package  body", "SCOTT.PKG", "PACKAGE_BODY", "SCOTT", "PKG", false},
+		{"MATERIALIZED_VIEW", "SCOTT.MV", "MATERIALIZED_VIEW", "SCOTT", "MV", false},
+		{"", "EMP", "", nil, "", true},
+		{"TABLE", "", "", nil, "", true},
+		{"TABLE", ".EMP", "", nil, "", true},
+		{"TABLE", "SCOTT.", "", nil, "", true},
+	}
+	for _, tt := range objectTests {
+		objectType, schema, name, err := ddlObject(tt.objectType, tt.name)
+		if (err != nil) != tt.err {
+			t.Errorf("ddlObject %q %q error - expected: %v - received: %v", tt.objectType, tt.name, tt.err, err)
+			continue
+		}
+		if objectType != tt.typeOut || schema != tt.schema || name != tt.nameOut {
+			t.Errorf("ddlObject %q %q - expected: %v, %v, %v - received: %v, %v, %v",
+				tt.objectType, tt.name, tt.typeOut, tt.schema, tt.nameOut, objectType, schema, name)
+		}
+	}
+}
+
+// TestDefaultSequence tests getting the sequence name of a sequence NEXTVAL column default
+func TestDefaultSequence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dataDefault string
+		expected    string
+	}{
+		{dataDefault: `"SCOTT"."ISEQ$$_73155".nextval`, expected: "SCOTT.ISEQ$$_73155"},
+		{dataDefault: "order_seq.NEXTVAL", expected: "ORDER_SEQ"},
+		{dataDefault: `scott."Order Seq".nextval`, expected: "SCOTT.Order Seq"},
+		{dataDefault: "sysdate", expected: ""},
+		{dataDefault: "'A.NEXTVAL'", expected: ""},
+		{dataDefault: ".nextval", expected: ""},
+		{dataDefault: "a.b.c.nextval", expected: ""},
+		{dataDefault: "", expected: ""},
+	}
+
+	for _, test := range tests {
+		sequence := defaultSequence(test.dataDefault)
+		if sequence != test.expected {
+			t.Errorf("defaultSequence %q - expected: %v - received: %v", test.dataDefault, test.expected, sequence)
+		}
+	}
+}
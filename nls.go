@@ -0,0 +1,172 @@
+package oci8
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The format models of the NLS-safe conversion helpers. They only use digits and separators,
+// so the result does not depend on the session NLS_DATE_FORMAT, NLS_TIMESTAMP_FORMAT, NLS_DATE_LANGUAGE,
+// or NLS_NUMERIC_CHARACTERS, which can differ between environments.
+const (
+	// DateFormatModel is the format model of DATE values, like 2006-01-02 15:04:05
+	DateFormatModel = "YYYY-MM-DD HH24:MI:SS"
+	// TimestampFormatModel is the format model of TIMESTAMP values, like 2006-01-02 15:04:05.000000000
+	TimestampFormatModel = "YYYY-MM-DD HH24:MI:SS.FF9"
+	// TimestampTZFormatModel is the format model of TIMESTAMP WITH TIME ZONE values, like 2006-01-02 15:04:05.000000000 -07:00
+	TimestampTZFormatModel = "YYYY-MM-DD HH24:MI:SS.FF9 TZH:TZM"
+	// NumberFormatModel is the format model of NUMBER values, like -123.45, up to 38 digits before and after the decimal point
+	NumberFormatModel = "FM99999999999999999999999999999999999999D99999999999999999999999999999999999999"
+)
+
+const (
+	// dateLayout is the Go layout of DateFormatModel
+	dateLayout = "2006-01-02 15:04:05"
+	// timestampLayout is the Go layout of TimestampFormatModel
+	timestampLayout = "2006-01-02 15:04:05.000000000"
+	// timestampTZLayout is the Go layout of TimestampTZFormatModel
+	timestampTZLayout = "2006-01-02 15:04:05.000000000 -07:00"
+
+	// numericCharacters is the NLS parameter of the number conversions, a period as decimal character
+	numericCharacters = "'NLS_NUMERIC_CHARACTERS=''.,'''"
+	// maxNumberDigits is the max number of digits before and after the decimal point of NumberFormatModel
+	maxNumberDigits = 38
+)
+
+// ToCharDate returns a SQL expression that converts the DATE expression expr to text in the DateFormatModel,
+// for building dynamic SQL. Parse the text with ParseDate.
+// expr is added as is, it must not come from user input, see ValidateIdentifier and QuoteIdentifier.
+func ToCharDate(expr string) string {
+	return "to_char(" + expr + ", '" + DateFormatModel + "')"
+}
+
+// ToCharTimestamp returns a SQL expression that converts the TIMESTAMP expression expr to text in the TimestampFormatModel.
+// Parse the text with ParseTimestamp.
+func ToCharTimestamp(expr string) string {
+	return "to_char(" + expr + ", '" + TimestampFormatModel + "')"
+}
+
+// ToCharTimestampTZ returns a SQL expression that converts the TIMESTAMP WITH TIME ZONE expression expr to text
+// in the TimestampTZFormatModel. Parse the text with ParseTimestampTZ.
+func ToCharTimestampTZ(expr string) string {
+	return "to_char(" + expr + ", '" + TimestampTZFormatModel + "')"
+}
+
+// ToCharNumber returns a SQL expression that converts the NUMBER expression expr to text with a period as decimal character,
+// in the shortest form, like 1.5 or 1.0E+40 for numbers that do not fit in 64 characters.
+// Parse the text with strconv.ParseFloat or strconv.ParseInt.
+func ToCharNumber(expr string) string {
+	return "to_char(" + expr + ", 'TM9', " + numericCharacters + ")"
+}
+
+// ToDate returns a SQL expression that converts the text expression expr in the DateFormatModel to DATE,
+// like ToDate(":1") with a bind value from FormatDate.
+// expr is added as is, it must not come from user input, see ValidateIdentifier and QuoteIdentifier.
+func ToDate(expr string) string {
+	return "to_date(" + expr + ", '" + DateFormatModel + "')"
+}
+
+// ToTimestamp returns a SQL expression that converts the text expression expr in the TimestampFormatModel to TIMESTAMP,
+// like ToTimestamp(":1") with a bind value from FormatTimestamp
+func ToTimestamp(expr string) string {
+	return "to_timestamp(" + expr + ", '" + TimestampFormatModel + "')"
+}
+
+// ToTimestampTZ returns a SQL expression that converts the text expression expr in the TimestampTZFormatModel
+// to TIMESTAMP WITH TIME ZONE, like ToTimestampTZ(":1") with a bind value from FormatTimestampTZ
+func ToTimestampTZ(expr string) string {
+	return "to_timestamp_tz(" + expr + ", '" + TimestampTZFormatModel + "')"
+}
+
+// ToNumber returns a SQL expression that converts the text expression expr with a period as decimal character to NUMBER,
+// like ToNumber(":1") with a bind value from FormatNumber. The text must be in the NumberFormatModel,
+// without exponent or group separators.
+func ToNumber(expr string) string {
+	return "to_number(" + expr + ", '" + NumberFormatModel + "', " + numericCharacters + ")"
+}
+
+// FormatDate returns the time as text in the DateFormatModel, to bind for ToDate.
+// The time is formatted in its location, use In to convert it first. Fractional seconds are truncated.
+func FormatDate(aTime time.Time) string {
+	return aTime.Format(dateLayout)
+}
+
+// FormatTimestamp returns the time as text in the TimestampFormatModel, to bind for ToTimestamp.
+// The time is formatted in its location, use In to convert it first.
+func FormatTimestamp(aTime time.Time) string {
+	return aTime.Format(timestampLayout)
+}
+
+// FormatTimestampTZ returns the time with its time zone offset as text in the TimestampTZFormatModel, to bind for ToTimestampTZ
+func FormatTimestampTZ(aTime time.Time) string {
+	return aTime.Format(timestampTZLayout)
+}
+
+// FormatNumber returns an integer or floating point value as text with a period as decimal character,
+// in the NumberFormatModel, to bind for ToNumber.
+// Returns an error for other types, NaN, infinity, and numbers with more than 38 digits before or after the decimal point.
+func FormatNumber(value interface{}) (string, error) {
+	var text string
+	switch value := value.(type) {
+	case int:
+		text = strconv.FormatInt(int64(value), 10)
+	case int8:
+		text = strconv.FormatInt(int64(value), 10)
+	case int16:
+		text = strconv.FormatInt(int64(value), 10)
+	case int32:
+		text = strconv.FormatInt(int64(value), 10)
+	case int64:
+		text = strconv.FormatInt(value, 10)
+	case uint:
+		text = strconv.FormatUint(uint64(value), 10)
+	case uint8:
+		text = strconv.FormatUint(uint64(value), 10)
+	case uint16:
+		text = strconv.FormatUint(uint64(value), 10)
+	case uint32:
+		text = strconv.FormatUint(uint64(value), 10)
+	case uint64:
+		text = strconv.FormatUint(value, 10)
+	case float32:
+		if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+			return "", fmt.Errorf("can not format %v as NUMBER", value)
+		}
+		text = strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return "", fmt.Errorf("can not format %v as NUMBER", value)
+		}
+		text = strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return "", fmt.Errorf("can not format %T as NUMBER", value)
+	}
+
+	digits := strings.TrimPrefix(text, "-")
+	fraction := ""
+	if index := strings.IndexByte(digits, '.'); index >= 0 {
+		digits, fraction = digits[:index], digits[index+1:]
+	}
+	if len(digits) > maxNumberDigits || len(fraction) > maxNumberDigits {
+		return "", fmt.Errorf("%v has more than %v digits before or after the decimal point", text, maxNumberDigits)
+	}
+	return text, nil
+}
+
+// ParseDate parses text in the DateFormatModel, like from ToCharDate, as a time in location
+func ParseDate(text string, location *time.Location) (time.Time, error) {
+	return time.ParseInLocation(dateLayout, text, location)
+}
+
+// ParseTimestamp parses text in the TimestampFormatModel, like from ToCharTimestamp, as a time in location
+func ParseTimestamp(text string, location *time.Location) (time.Time, error) {
+	return time.ParseInLocation(timestampLayout, text, location)
+}
+
+// ParseTimestampTZ parses text in the TimestampTZFormatModel, like from ToCharTimestampTZ, as a time with a fixed zone offset
+func ParseTimestampTZ(text string) (time.Time, error) {
+	return time.Parse(timestampTZLayout, text)
+}
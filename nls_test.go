@@ -0,0 +1,219 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestIgnoreEnv checks connecting with the environment ignored and the NLS settings set by the DSN
+func TestIgnoreEnv(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	var envTests = []struct {
+		params    string
+		territory string
+	}{
+		{"?ignore_env=true", "AMERICA"},
+		{"?ignore_env=true&nls_territory=GERMANY&charset=AL32UTF8", "GERMANY"},
+	}
+
+	for _, tt := range envTests {
+		db := testGetDB(tt.params)
+		if db == nil {
+			t.Fatal("db is nil for params:", tt.params)
+		}
+
+		var territory string
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		err := db.QueryRowContext(ctx, "select value from nls_session_parameters where parameter = 'NLS_TERRITORY'").Scan(&territory)
+		cancel()
+		if err != nil {
+			db.Close()
+			t.Fatal("query error:", err)
+		}
+		if territory != tt.territory {
+			t.Errorf("territory - params: %v - expected: %v - received: %v", tt.params, tt.territory, territory)
+		}
+
+		var value string
+		ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+		err = db.QueryRowContext(ctx, "select :1 from dual", "ünïcödé").Scan(&value)
+		cancel()
+		if err != nil {
+			db.Close()
+			t.Fatal("query error:", err)
+		}
+		if value != "ünïcödé" {
+			t.Errorf("value - params: %v - expected: ünïcödé - received: %v", tt.params, value)
+		}
+
+		err = db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}
+
+	db, err := sql.Open("oci8", testGetOpenString("?charset=NOT_A_CHARSET"))
+	if err != nil {
+		t.Fatal("open error:", err)
+	}
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	err = db.PingContext(ctx)
+	cancel()
+	if err == nil || !strings.Contains(err.Error(), "invalid charset") {
+		t.Errorf("invalid charset - expected: invalid charset - received: %v", err)
+	}
+}
+
+// TestNLSHelpersSession checks the NLS-safe conversions do not depend on the session NLS settings
+func TestNLSHelpersSession(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?nls_language=GERMAN&nls_territory=GERMANY")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	aTime := time.Date(2006, 1, 2, 15, 4, 5, 123456789, time.FixedZone("", -7*60*60))
+	number, err := FormatNumber(-1234.5)
+	if err != nil {
+		t.Fatal("format number error:", err)
+	}
+
+	query := "select " + ToCharDate(ToDate(":1")) + ", " + ToCharTimestamp(ToTimestamp(":2")) + ", " +
+		ToCharTimestampTZ(ToTimestampTZ(":3")) + ", " + ToCharNumber(ToNumber(":4")) + " from dual"
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	var date, timestamp, timestampTZ, text string
+	err = db.QueryRowContext(ctx, query, FormatDate(aTime), FormatTimestamp(aTime), FormatTimestampTZ(aTime), number).
+		Scan(&date, &timestamp, &timestampTZ, &text)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+
+	if date != "2006-01-02 15:04:05" {
+		t.Errorf("date - expected: %v - received: %v", "2006-01-02 15:04:05", date)
+	}
+	if timestamp != "2006-01-02 15:04:05.123456789" {
+		t.Errorf("timestamp - expected: %v - received: %v", "2006-01-02 15:04:05.123456789", timestamp)
+	}
+	parsed, err := ParseTimestampTZ(timestampTZ)
+	if err != nil || !parsed.Equal(aTime) {
+		t.Errorf("timestamp tz - expected: %v - received: %v %v", aTime, timestampTZ, err)
+	}
+	if text != "-1234.5" {
+		t.Errorf("number - expected: %v - received: %v", "-1234.5", text)
+	}
+}
+
+// TestAlterSessionNLS tests the alter session statement for the NLS language and territory
+func TestAlterSessionNLS(t *testing.T) {
+	t.Parallel()
+
+	var alterTests = []struct {
+		nlsLanguage  string
+		nlsTerritory string
+		expected     string
+	}{
+		{"AMERICAN", "AMERICA", "alter session set NLS_LANGUAGE = 'AMERICAN' NLS_TERRITORY = 'AMERICA'"},
+		{"BRAZILIAN PORTUGUESE", "", "alter session set NLS_LANGUAGE = 'BRAZILIAN PORTUGUESE'"},
+		{"", "GERMANY", "alter session set NLS_TERRITORY = 'GERMANY'"},
+		{"A'B", "", "alter session set NLS_LANGUAGE = 'A''B'"},
+	}
+	for _, tt := range alterTests {
+		query := alterSessionNLS(tt.nlsLanguage, tt.nlsTerritory)
+		if query != tt.expected {
+			t.Errorf("alterSessionNLS(%q, %q) - expected: %v - received: %v", tt.nlsLanguage, tt.nlsTerritory, tt.expected, query)
+		}
+	}
+}
+
+// TestNLSHelpers tests the NLS-safe conversion expressions and the bind values formatted for them
+func TestNLSHelpers(t *testing.T) {
+	t.Parallel()
+
+	expressions := []struct {
+		expression string
+		expected   string
+	}{
+		{expression: ToCharDate("A"), expected: "to_char(A, 'YYYY-MM-DD HH24:MI:SS')"},
+		{expression: ToCharTimestamp("A"), expected: "to_char(A, 'YYYY-MM-DD HH24:MI:SS.FF9')"},
+		{expression: ToCharTimestampTZ("A"), expected: "to_char(A, 'YYYY-MM-DD HH24:MI:SS.FF9 TZH:TZM')"},
+		{expression: ToCharNumber("A"), expected: "to_char(A, 'TM9', 'NLS_NUMERIC_CHARACTERS=''.,''')"},
+		{expression: ToDate(":1"), expected: "to_date(:1, 'YYYY-MM-DD HH24:MI:SS')"},
+		{expression: ToTimestamp(":1"), expected: "to_timestamp(:1, 'YYYY-MM-DD HH24:MI:SS.FF9')"},
+		{expression: ToTimestampTZ(":1"), expected: "to_timestamp_tz(:1, 'YYYY-MM-DD HH24:MI:SS.FF9 TZH:TZM')"},
+		{expression: ToNumber(":1"), expected: "to_number(:1, '" + NumberFormatModel + "', 'NLS_NUMERIC_CHARACTERS=''.,''')"},
+	}
+	for _, expression := range expressions {
+		if expression.expression != expression.expected {
+			t.Errorf("expression - expected: %v - received: %v", expression.expected, expression.expression)
+		}
+	}
+
+	location := time.FixedZone("", 5*60*60+30*60)
+	aTime := time.Date(2006, 1, 2, 15, 4, 5, 123456789, location)
+	texts := []struct {
+		text     string
+		expected string
+	}{
+		{text: FormatDate(aTime), expected: "2006-01-02 15:04:05"},
+		{text: FormatTimestamp(aTime), expected: "2006-01-02 15:04:05.123456789"},
+		{text: FormatTimestampTZ(aTime), expected: "2006-01-02 15:04:05.123456789 +05:30"},
+	}
+	for _, text := range texts {
+		if text.text != text.expected {
+			t.Errorf("format - expected: %v - received: %v", text.expected, text.text)
+		}
+	}
+
+	parsed, err := ParseTimestampTZ(FormatTimestampTZ(aTime))
+	if err != nil || !parsed.Equal(aTime) {
+		t.Errorf("parse timestamp tz - expected: %v - received: %v %v", aTime, parsed, err)
+	}
+	parsed, err = ParseDate("2006-01-02 15:04:05", location)
+	if err != nil || !parsed.Equal(aTime.Truncate(time.Second)) {
+		t.Errorf("parse date - expected: %v - received: %v %v", aTime.Truncate(time.Second), parsed, err)
+	}
+
+	numbers := []struct {
+		value    interface{}
+		expected string
+		err      bool
+	}{
+		{value: 12, expected: "12"},
+		{value: int64(-9223372036854775808), expected: "-9223372036854775808"},
+		{value: uint64(18446744073709551615), expected: "18446744073709551615"},
+		{value: -1.5, expected: "-1.5"},
+		{value: float32(0.25), expected: "0.25"},
+		{value: 1e-7, expected: "0.0000001"},
+		{value: 1e40, err: true},
+		{value: math.NaN(), err: true},
+		{value: math.Inf(1), err: true},
+		{value: "1", err: true},
+	}
+	for _, number := range numbers {
+		text, err := FormatNumber(number.value)
+		if (err != nil) != number.err {
+			t.Errorf("format number %v error - expected: %v - received: %v", number.value, number.err, err)
+			continue
+		}
+		if text != number.expected {
+			t.Errorf("format number - expected: %v - received: %v", number.expected, text)
+		}
+	}
+}
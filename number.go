@@ -0,0 +1,264 @@
+package oci8
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+Oracle NUMBER format (SQLT_NUM)
+
+A NUMBER is an exponent byte followed by up to 20 base 100 mantissa digit bytes.
+Zero is the single byte 0x80.
+Positive numbers: exponent byte is 193 + exponent, digit bytes are digit + 1.
+Negative numbers: exponent byte is 62 - exponent, digit bytes are 101 - digit,
+followed by a 102 terminator byte when there are less than 20 digits.
+The value is the sum of digit * 100^(exponent - index) for each digit index.
+*/
+
+const (
+	numberMaxDigits      = 20
+	numberMaxExponent    = 62
+	numberMinExponent    = -65
+	numberNegativeEndTag = 102
+)
+
+var (
+	// ErrInvalidNumber is returned when a value can not be converted to or from an Oracle NUMBER
+	ErrInvalidNumber = errors.New("invalid number")
+	// ErrNumberOverflow is returned when a value is too large for an Oracle NUMBER
+	ErrNumberOverflow = errors.New("number overflow")
+)
+
+// encodeNumber encodes a decimal number string, like -123.456 or 1.5e10, into Oracle NUMBER bytes.
+// Digits past the NUMBER precision are rounded half up, values too small for a NUMBER become zero.
+func encodeNumber(number string) ([]byte, error) {
+	s := number
+	negative := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	exponent10 := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		exponent10, err = strconv.Atoi(s[i+1:])
+		if err != nil {
+			return nil, ErrInvalidNumber
+		}
+		s = s[:i]
+	}
+
+	integerPart, fractionPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integerPart, fractionPart = s[:i], s[i+1:]
+	}
+	if integerPart == "" && fractionPart == "" {
+		return nil, ErrInvalidNumber
+	}
+
+	digits := integerPart + fractionPart
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return nil, ErrInvalidNumber
+		}
+	}
+
+	// value is 0.digits * 10^pointPosition
+	pointPosition := len(integerPart) + exponent10
+	for len(digits) > 0 && digits[0] == '0' {
+		digits = digits[1:]
+		pointPosition--
+	}
+	digits = strings.TrimRight(digits, "0")
+	if digits == "" {
+		return []byte{0x80}, nil
+	}
+
+	// align digits to base 100
+	if pointPosition%2 != 0 {
+		digits = "0" + digits
+		pointPosition++
+	}
+	if len(digits)%2 != 0 {
+		digits += "0"
+	}
+
+	mantissa := make([]int, len(digits)/2)
+	for i := 0; i < len(mantissa); i++ {
+		mantissa[i] = int(digits[2*i]-'0')*10 + int(digits[2*i+1]-'0')
+	}
+	exponent := pointPosition/2 - 1
+
+	if len(mantissa) > numberMaxDigits {
+		roundUp := mantissa[numberMaxDigits] >= 50
+		mantissa = mantissa[:numberMaxDigits]
+		if roundUp {
+			i := numberMaxDigits - 1
+			for ; i >= 0; i-- {
+				mantissa[i]++
+				if mantissa[i] < 100 {
+					break
+				}
+				mantissa[i] = 0
+			}
+			if i < 0 {
+				mantissa = []int{1}
+				exponent++
+			}
+		}
+	}
+	for len(mantissa) > 0 && mantissa[len(mantissa)-1] == 0 {
+		mantissa = mantissa[:len(mantissa)-1]
+	}
+
+	if exponent > numberMaxExponent {
+		return nil, ErrNumberOverflow
+	}
+	if exponent < numberMinExponent {
+		return []byte{0x80}, nil
+	}
+
+	buffer := make([]byte, 0, numberMaxDigits+2)
+	if !negative {
+		buffer = append(buffer, byte(193+exponent))
+		for _, digit := range mantissa {
+			buffer = append(buffer, byte(digit+1))
+		}
+		return buffer, nil
+	}
+
+	buffer = append(buffer, byte(62-exponent))
+	for _, digit := range mantissa {
+		buffer = append(buffer, byte(101-digit))
+	}
+	if len(mantissa) < numberMaxDigits {
+		buffer = append(buffer, numberNegativeEndTag)
+	}
+	return buffer, nil
+}
+
+// decodeNumber decodes Oracle NUMBER bytes into a decimal number string, like -123.456
+func decodeNumber(buffer []byte) (string, error) {
+	if len(buffer) == 0 {
+		return "", ErrInvalidNumber
+	}
+	if len(buffer) == 1 {
+		if buffer[0] == 0x80 {
+			return "0", nil
+		}
+		// single byte 0 is negative infinity
+		return "", ErrInvalidNumber
+	}
+
+	negative := buffer[0]&0x80 == 0
+	var exponent int
+	digitBytes := buffer[1:]
+	if negative {
+		exponent = 62 - int(buffer[0])
+		if digitBytes[len(digitBytes)-1] == numberNegativeEndTag {
+			digitBytes = digitBytes[:len(digitBytes)-1]
+		}
+	} else {
+		exponent = int(buffer[0]) - 193
+	}
+
+	digits := make([]byte, 0, 2*len(digitBytes))
+	for _, digitByte := range digitBytes {
+		digit := int(digitByte) - 1
+		if negative {
+			digit = 101 - int(digitByte)
+		}
+		if digit < 0 || digit > 99 {
+			// includes positive infinity 255, 101
+			return "", ErrInvalidNumber
+		}
+		digits = append(digits, byte('0'+digit/10), byte('0'+digit%10))
+	}
+	if len(digits) < 1 {
+		return "", ErrInvalidNumber
+	}
+
+	pointPosition := (exponent + 1) * 2
+	var integerPart, fractionPart string
+	switch {
+	case pointPosition <= 0:
+		integerPart = "0"
+		fractionPart = strings.Repeat("0", -pointPosition) + string(digits)
+	case pointPosition >= len(digits):
+		integerPart = string(digits) + strings.Repeat("0", pointPosition-len(digits))
+	default:
+		integerPart = string(digits[:pointPosition])
+		fractionPart = string(digits[pointPosition:])
+	}
+
+	integerPart = strings.TrimLeft(integerPart, "0")
+	if integerPart == "" {
+		integerPart = "0"
+	}
+	fractionPart = strings.TrimRight(fractionPart, "0")
+
+	number := integerPart
+	if fractionPart != "" {
+		number += "." + fractionPart
+	}
+	if negative {
+		number = "-" + number
+	}
+	return number, nil
+}
+
+// floatToNumber converts a float32 or float64 to Oracle NUMBER bytes from its decimal representation,
+// rounded to precision decimal places or the shortest decimal that represents the float when precision is -1.
+// Returns false when the value can not be stored in a NUMBER, like NaN and infinity.
+func floatToNumber(value interface{}, precision int) ([]byte, bool) {
+	var number string
+	switch value := value.(type) {
+	case float32:
+		if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+			return nil, false
+		}
+		number = strconv.FormatFloat(float64(value), 'f', precision, 32)
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, false
+		}
+		number = strconv.FormatFloat(value, 'f', precision, 64)
+	default:
+		return nil, false
+	}
+
+	buffer, err := encodeNumber(number)
+	if err != nil {
+		return nil, false
+	}
+	return buffer, true
+}
+
+// unsignedToNumber converts a uint, uint64, or uintptr over the int64 range to Oracle NUMBER bytes.
+// Returns false for other values.
+func unsignedToNumber(value interface{}) ([]byte, bool) {
+	var unsigned uint64
+	switch value := value.(type) {
+	case uint:
+		unsigned = uint64(value)
+	case uint64:
+		unsigned = value
+	case uintptr:
+		unsigned = uint64(value)
+	default:
+		return nil, false
+	}
+	if unsigned <= math.MaxInt64 {
+		return nil, false
+	}
+
+	buffer, err := encodeNumber(strconv.FormatUint(unsigned, 10))
+	if err != nil {
+		return nil, false
+	}
+	return buffer, true
+}
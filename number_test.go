@@ -0,0 +1,291 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelectFloatPrecision(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	var floatPrecisionTests = []struct {
+		params   string
+		value    float64
+		expected string
+	}{
+		{"?float_precision=SHORTEST", 0.1, ".1"},
+		{"?float_precision=SHORTEST", 0.1 + 0.2, ".30000000000000004"},
+		{"?float_precision=2", 0.1 + 0.2, ".3"},
+		{"?float_precision=0", 1234.5, "1234"},
+	}
+
+	for _, tt := range floatPrecisionTests {
+		db := testGetDB(tt.params)
+		if db == nil {
+			t.Fatal("db is null")
+		}
+
+		var result string
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		err := db.QueryRowContext(ctx, "select to_char(:1) from dual", tt.value).Scan(&result)
+		cancel()
+		if err != nil {
+			t.Error("query row error:", err)
+		} else if result != tt.expected {
+			t.Errorf("%v - expected: %v - received: %v", tt.params, tt.expected, result)
+		}
+
+		err = db.Close()
+		if err != nil {
+			t.Error("db close error:", err)
+		}
+	}
+}
+
+// TestNumber tests Oracle NUMBER encoding and decoding
+func TestNumber(t *testing.T) {
+	t.Parallel()
+
+	var numberTests = []struct {
+		number  string
+		buffer  []byte
+		decoded string
+	}{
+		{"0", []byte{0x80}, "0"},
+		{"-0.000", []byte{0x80}, "0"},
+		{"1", []byte{0xc1, 0x02}, "1"},
+		{"100", []byte{0xc2, 0x02}, "100"},
+		{"0.1", []byte{0xc0, 0x0b}, "0.1"},
+		{".01", []byte{0xc0, 0x02}, "0.01"},
+		{"123.456", []byte{0xc2, 0x02, 0x18, 0x2e, 0x3d}, "123.456"},
+		{"-1", []byte{0x3e, 0x64, 0x66}, "-1"},
+		{"-123.456", []byte{0x3d, 0x64, 0x4e, 0x38, 0x29, 0x66}, "-123.456"},
+		{"1.5e10", []byte{0xc6, 0x02, 0x33}, "15000000000"},
+		{"+00012.3400", []byte{0xc1, 0x0d, 0x23}, "12.34"},
+		{"1e-131", []byte{0x80}, "0"},
+		{"1234567890123456789012345678901234567890123", []byte{0xd6, 0x02, 0x18, 0x2e, 0x44, 0x5a, 0x02, 0x18, 0x2e, 0x44, 0x5a, 0x02, 0x18, 0x2e, 0x44, 0x5a, 0x02, 0x18, 0x2e, 0x44, 0x5a}, "1234567890123456789012345678901234567890000"},
+		{strings.Repeat("9", 41), []byte{0xd5, 0x0b}, "1" + strings.Repeat("0", 41)},
+		{strings.Repeat("9", 42), []byte{0xd6, 0x02}, "1" + strings.Repeat("0", 42)},
+	}
+
+	for _, tt := range numberTests {
+		buffer, err := encodeNumber(tt.number)
+		if err != nil {
+			t.Errorf("encodeNumber(%v) error: %v", tt.number, err)
+			continue
+		}
+		if !reflect.DeepEqual(buffer, tt.buffer) {
+			t.Errorf("encodeNumber(%v) - expected: %v - received: %v", tt.number, tt.buffer, buffer)
+		}
+		decoded, err := decodeNumber(buffer)
+		if err != nil {
+			t.Errorf("decodeNumber(%v) error: %v", buffer, err)
+			continue
+		}
+		if decoded != tt.decoded {
+			t.Errorf("decodeNumber(%v) - expected: %v - received: %v", buffer, tt.decoded, decoded)
+		}
+	}
+
+	for _, number := range []string{"", ".", "1a", "1e", "--1", "1.2.3"} {
+		_, err := encodeNumber(number)
+		if err != ErrInvalidNumber {
+			t.Errorf("encodeNumber(%v) - expected: %v - received: %v", number, ErrInvalidNumber, err)
+		}
+	}
+	_, err := encodeNumber("1e126")
+	if err != ErrNumberOverflow {
+		t.Errorf("encodeNumber(1e126) - expected: %v - received: %v", ErrNumberOverflow, err)
+	}
+
+	var floatTests = []struct {
+		value     interface{}
+		precision int
+		decoded   string
+	}{
+		{float64(0.1), -1, "0.1"},
+		{float32(0.1), -1, "0.1"},
+		{float64(0.1) + float64(0.2), -1, "0.30000000000000004"},
+		{float64(0.1) + float64(0.2), 2, "0.3"},
+		{float64(-2.675), 2, "-2.67"},
+		{float64(1234.5), 0, "1234"},
+	}
+
+	for _, tt := range floatTests {
+		buffer, ok := floatToNumber(tt.value, tt.precision)
+		if !ok {
+			t.Errorf("floatToNumber(%v, %v) failed", tt.value, tt.precision)
+			continue
+		}
+		decoded, err := decodeNumber(buffer)
+		if err != nil {
+			t.Errorf("decodeNumber(%v) error: %v", buffer, err)
+			continue
+		}
+		if decoded != tt.decoded {
+			t.Errorf("floatToNumber(%v, %v) - expected: %v - received: %v", tt.value, tt.precision, tt.decoded, decoded)
+		}
+	}
+
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, ok := floatToNumber(value, -1)
+		if ok {
+			t.Errorf("floatToNumber(%v) - expected failure", value)
+		}
+	}
+}
+
+// TestCheckNamedValue tests driver types, unsigned integers, durations, and Valuer values are converted by the driver
+func TestCheckNamedValue(t *testing.T) {
+	t.Parallel()
+
+	type status uint64
+	var nilValuer *testValuer
+	var nilLob *Lob
+	tempLob := &TempLob{}
+	vector := []float32{1, 2}
+
+	stmt := &Stmt{}
+	tests := []struct {
+		value    interface{}
+		expected interface{}
+		err      error
+	}{
+		{value: vector, expected: vector},
+		{value: tempLob, expected: tempLob},
+		{value: big.NewInt(1), expected: big.NewInt(1)},
+		{value: nilLob, expected: nil},
+		{value: 3 * time.Second, expected: 3 * time.Second},
+		{value: YearMonth{Years: 1}, expected: YearMonth{Years: 1}},
+		{value: uint64(math.MaxUint64), expected: uint64(math.MaxUint64)},
+		{value: status(math.MaxUint64), expected: uint64(math.MaxUint64)},
+		{value: testValuer{value: int64(1)}, expected: int64(1)},
+		{value: testValuer{value: vector}, expected: vector},
+		{value: testValuer{value: uint64(math.MaxUint64)}, expected: uint64(math.MaxUint64)},
+		{value: testValuer{value: nil}, expected: nil},
+		{value: nilValuer, expected: nil},
+		{value: GUID{1}, expected: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{value: int32(1), expected: int32(1), err: driver.ErrSkip},
+	}
+	for _, test := range tests {
+		namedValue := &driver.NamedValue{Ordinal: 1, Value: test.value}
+		err := stmt.CheckNamedValue(namedValue)
+		if err != test.err {
+			t.Errorf("CheckNamedValue %#v - expected: %v - received: %v", test.value, test.err, err)
+			continue
+		}
+		if !reflect.DeepEqual(namedValue.Value, test.expected) {
+			t.Errorf("CheckNamedValue %#v - expected: %#v - received: %#v", test.value, test.expected, namedValue.Value)
+		}
+	}
+
+	lobStream, err := checkBindValue(&Lob{binary: true}, true)
+	if stream, ok := lobStream.(LobStream); err != nil || !ok || stream.Text {
+		t.Errorf("checkBindValue *Lob - expected: BLOB LobStream - received: %#v %v", lobStream, err)
+	}
+
+	err = stmt.CheckNamedValue(&driver.NamedValue{Ordinal: 1, Value: testValuer{value: struct{}{}}})
+	var bindError *BindError
+	if !errors.As(err, &bindError) {
+		t.Errorf("CheckNamedValue Valuer of struct - expected: %v - received: %v", "*BindError", err)
+	}
+
+	number, ok := unsignedToNumber(uint64(math.MaxUint64))
+	text, err := decodeNumber(number)
+	if !ok || err != nil || text != "18446744073709551615" {
+		t.Errorf("unsignedToNumber - expected: %v - received: %v %v %v", "18446744073709551615", text, ok, err)
+	}
+	_, ok = unsignedToNumber(uint64(math.MaxInt64))
+	if ok {
+		t.Errorf("unsignedToNumber MaxInt64 - expected: %v - received: %v", false, ok)
+	}
+}
+
+// TestSelectDualNumberStrings checks NUMBER columns are returned as exact decimal strings with number_strings
+func TestSelectDualNumberStrings(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?number_strings=true")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	queryRow := func(ctx context.Context, db *sql.DB, query string, columns int) []interface{} {
+		values := make([]interface{}, columns)
+		dest := make([]interface{}, columns)
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		err := db.QueryRowContext(ctx, query).Scan(dest...)
+		if err != nil {
+			t.Fatal("query error:", err)
+		}
+		return values
+	}
+
+	values := queryRow(ctx, db, "select 12345678901234567890123456789012345678, -1234567890.123456789, 0, cast (0.5 as FLOAT), cast (1 as BINARY_DOUBLE), cast (null as NUMBER) from dual", 6)
+	expected := []interface{}{"12345678901234567890123456789012345678", "-1234567890.123456789", "0", "0.5", float64(1), nil}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("number strings - expected: %v - received: %v", expected, values)
+	}
+
+	values = queryRow(WithNumberStrings(ctx, false), db, "select 1, 1.5 from dual", 2)
+	expected = []interface{}{int64(1), float64(1.5)}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("WithNumberStrings false - expected: %v - received: %v", expected, values)
+	}
+
+	values = queryRow(WithNumberStrings(ctx, true), TestDB, "select 1, 1.5 from dual", 2)
+	expected = []interface{}{"1", "1.5"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("WithNumberStrings true - expected: %v - received: %v", expected, values)
+	}
+}
+
+// TestBindUnsignedDuration checks binding unsigned integers over the int64 range, durations, and Valuer values
+func TestBindUnsignedDuration(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	type status uint64
+	var unsigned, named, duration, valuer interface{}
+	err := TestDB.QueryRowContext(WithNumberStrings(ctx, true), "select :1, :2, :3, :4 from dual",
+		uint64(math.MaxUint64), status(math.MaxInt64+1), 1500*time.Millisecond, testValuer{value: uint(7)}).Scan(&unsigned, &named, &duration, &valuer)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	expected := []interface{}{"18446744073709551615", "9223372036854775808", "1500000000", "7"}
+	received := []interface{}{unsigned, named, duration, valuer}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("binds - expected: %v - received: %v", expected, received)
+	}
+}
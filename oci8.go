@@ -4,16 +4,21 @@ package oci8
 import "C"
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
 	"unsafe"
+
+	"github.com/mattn/go-oci8/oracle"
 )
 
 // ParseDSN parses a DSN used to connect to Oracle
@@ -28,108 +33,267 @@ import (
 //
 // loc - the time location for reading timestamp (without time zone). Defaults to UTC
 // Note that writing a timestamp (without time zone) just truncates the time zone.
+// Timestamps with time zone are read in loc when loc has the same offset at that time.
+// Bound times have the monotonic clock reading stripped, so round-tripped times can be compared with ==.
 //
 // isolation - the isolation level that can be set to: READONLY, SERIALIZABLE, or DEFAULT
 //
 // prefetch_rows - the number of top level rows to be prefetched. Defaults to 0. A 0 means unlimited rows.
 //
 // prefetch_memory - the max memory for top level rows to be prefetched. Defaults to 4096. A 0 means unlimited memory.
+// Both can be overridden per query with WithPrefetch.
+//
+// prefetch_target - the target bytes of the rows prefetched in a round trip. Defaults to 1048576, 1 MB. A 0 turns it off.
+// After the first row of a query is fetched, the prefetch rows are set to the number of rows of its width that fit in the target,
+// so the next round trips fetch more rows of narrow rows and less rows of wide rows, without tuning prefetch_rows for each query.
+// Only used when prefetch_rows and prefetch_memory are not set and the query has no WithPrefetch, and lowered to the WithMemoryBudget.
 //
 // questionph - when true, enables question mark placeholders. Defaults to false. (uses strconv.ParseBool to check for true)
-func ParseDSN(dsnString string) (dsn *DSN, err error) {
-
-	if dsnString == "" {
-		return nil, errors.New("empty dsn")
+//
+// stmt_cache_size - the number of statements kept in the OCI statement cache of each connection, also accepted as stmtCacheSize.
+// Defaults to 0, no statement cache. Statements are prepared with their text as the cache key, so preparing the same
+// query again on the connection reuses the statement handle without parsing it again on the server.
+// Statements that fail to execute are removed from the cache.
+//
+// temp_lob_cache - when false, temporary LOBs created by the driver are not read into the buffer cache. Defaults to true. (uses strconv.ParseBool)
+//
+// temp_tablespace - the temporary tablespace, or tablespace group, the temporary LOBs and sorts of the sessions go to.
+// Oracle has no session setting for it, so on connect the temporary tablespace of the user is changed to it,
+// for all the sessions of the user, when it is not already set. Needs the ALTER USER privilege to change it.
+// Defaults to empty, the temporary tablespace of the user is kept.
+//
+// Temporary LOBs created by the driver have the session duration, so LOB values returned by the server stay readable,
+// and are freed when the statement they are bound to is done.
+//
+// lob_prefetch_size - the number of bytes of BLOB, and characters of CLOB and NCLOB, columns fetched with the row,
+// also accepted as lobPrefetchSize. Defaults to 0, no LOB prefetch. LOB values up to the size are read without
+// a round trip per LOB, larger ones are read from the database. The length of each LOB is prefetched too.
+//
+// call_timeout - when false, calls are only interrupted with OCIBreak when their context is done. Defaults to true,
+// with an Oracle 18c or later client the deadline of the context also sets the OCI call timeout,
+// so calls time out even when OCIBreak hangs on a dead network. (uses strconv.ParseBool)
+//
+// fetch_array_size - the number of rows fetched into the column buffers by each fetch of a query, also accepted as fetchArraySize.
+// Defaults to 0, rows are fetched one at a time. Queries with LOB, VECTOR, object, nested cursor, or piecewise LONG columns
+// are always fetched one row at a time. See WithStmtOptions to set it for a query.
+//
+// float_precision - how float32 and float64 values are bound: BINARY, SHORTEST, or a number of decimal places. Defaults to BINARY.
+// BINARY binds as BINARY_DOUBLE, which can store binary artifacts like 0.1000000000000000055511151231257827 in NUMBER columns.
+// SHORTEST binds as NUMBER using the shortest decimal that represents the float, so 0.1 is stored as 0.1.
+// A number of decimal places binds as NUMBER rounded to that many decimal places.
+//
+// date_range - what to do when binding a time outside of the Oracle date range of 4712 BC to 9999 AD: ERROR or CLAMP. Defaults to ERROR.
+// ERROR returns ErrDateOutOfRange. CLAMP binds the first or last time of the range instead.
+// Years before 1 AD are converted between Go, where year 0 is 1 BC, and Oracle, where year -1 is 1 BC.
+//
+// number_strings - when true, NUMBER and FLOAT columns are returned as exact decimal strings, like 12345678901234567890.12,
+// instead of int64 and float64, which can not hold all NUMBER(38) values. Defaults to false. (uses strconv.ParseBool)
+// Scan them into a string, or a decimal type that implements sql.Scanner from a string. Can be overridden per query with WithNumberStrings.
+//
+// interval_int64 - when true, INTERVAL DAY TO SECOND values are returned as int64 nanoseconds and INTERVAL YEAR TO MONTH values
+// as int64 months, like before they were returned as time.Duration and YearMonth. Defaults to false. (uses strconv.ParseBool)
+//
+// identity_returning - when true, a single table INSERT ... VALUES into a table with an identity column is prepared with
+// RETURNING the identity column, and LastInsertId returns the identity value of the row inserted instead of the ROWID
+// of GetLastInsertId. Defaults to false. (uses strconv.ParseBool)
+//
+// charset - the client character set name, like AL32UTF8, used for both the character set and the national character set.
+// Defaults to the NLS_LANG and NLS_NCHAR environment variables when either is set, otherwise AL32UTF8.
+//
+// utf16 - when true, the environment is created with the OCI_UTF16ID character set, UTF-16, and the driver converts the text
+// between UTF-16 and the UTF-8 of Go strings. Defaults to false. (uses strconv.ParseBool)
+// The charset parameter and the NLS_LANG and NLS_NCHAR environment variables are not used for the character set,
+// for databases with a character set the client library does not convert correctly to or from the client character set,
+// like some EBCDIC and legacy character sets. CLOB data is read and written as AL32UTF8,
+// the pieces of LONG columns read piecewise are UTF-16.
+//
+// ignore_env - when true, the NLS_LANG and NLS_NCHAR environment variables are ignored. Defaults to false. (uses strconv.ParseBool)
+// The character set defaults to AL32UTF8 and the session NLS language and territory default to AMERICAN and AMERICA.
+// Note that TNS_ADMIN and ORACLE_HOME are read by the Oracle client library itself, set tns_admin or oracle_home,
+// or use a full connect descriptor or an easy connect string, to not depend on them for tnsnames.ora.
+//
+// tns_admin - the directory of the tnsnames.ora a connect string that is a net service name, like ORCL, is resolved with,
+// in place of the TNS_ADMIN environment variable. The driver reads the file on each connect and passes the connect descriptor
+// of the name to the client, so connectors can use different files. IFILE entries are not followed.
+// Other files of the directory, like sqlnet.ora and wallets, are still found by the client with TNS_ADMIN.
+//
+// oracle_home - the Oracle home whose network/admin/tnsnames.ora net service names are resolved with, like tns_admin,
+// in place of the ORACLE_HOME environment variable. tns_admin takes precedence. The client library still finds
+// its message and NLS data files with ORACLE_HOME, which is for the whole process.
+//
+// nls_language - the session NLS_LANGUAGE, like AMERICAN. Set with ALTER SESSION after connecting.
+//
+// nls_territory - the session NLS_TERRITORY, like AMERICA. Set with ALTER SESSION after connecting.
+//
+// time_zone - the session TIME_ZONE, like UTC, +02:00, or Europe/Paris. Set with ALTER SESSION after connecting.
+// TIMESTAMP WITH LOCAL TIME ZONE values are converted to and from the session time zone, which defaults to the ORA_SDTZ
+// environment variable or the time zone of the client, so clients in different zones see the values shifted
+// when they compare them to text or use them in SQL expressions without a time zone. CURRENT_TIMESTAMP is in the session time zone too.
+//
+// ltz_loc - the time location TIMESTAMP WITH LOCAL TIME ZONE values are returned in, like UTC.
+// Defaults to the same location as TIMESTAMP WITH TIME ZONE values, see loc. The instant of the time is not changed.
+//
+// max_rows - the max number of rows a query can return, fetching more rows returns ErrTooManyRows.
+// Defaults to 0. A 0 means unlimited rows. Can be overridden per query with WithMaxRows.
+//
+// close_timeout - the max time closing a connection or statement waits for the database, like 10s. Defaults to 0, no limit.
+// When the timeout is reached, OCIBreak is called and Close returns context.DeadlineExceeded.
+// The connection is then freed in the background when the database responds, so a hung database can not block shutting down.
+//
+// debug_concurrent_use - when true, a *ConcurrentUseError from using a Stmt or Rows from more than one goroutine at the same time
+// includes the stacks of both calls. Defaults to false. (uses strconv.ParseBool)
+//
+// read_only - when true, preparing statements other than queries, PL/SQL blocks, and calls,
+// like INSERT, UPDATE, DELETE, MERGE, and DDL, returns a *ReadOnlyError,
+// so a reporting service can not change data even when connected as the schema owner. Defaults to false. (uses strconv.ParseBool)
+// PL/SQL blocks and calls are not checked, use a read only user or isolation=READONLY as well.
+//
+// ddl_in_tx - what to do when executing DDL, like CREATE, ALTER, DROP, TRUNCATE, or GRANT, in a transaction,
+// as Oracle commits the transaction before the DDL: ALLOW, WARN, or ERROR. Defaults to ALLOW.
+// WARN logs a warning and calls the ImplicitCommit hook, then executes the DDL.
+// ERROR returns an *ImplicitCommitError without executing the DDL, the transaction stays open.
+// The DDL is recognized from the OCI statement type, like read_only, and from the first keyword for the DDL without one,
+// like TRUNCATE, so DDL run by PL/SQL, like EXECUTE IMMEDIATE, is not checked.
+//
+// group_commit_count - when more than 0, statements executed outside a transaction are not committed by the execute,
+// the connection commits them every group_commit_count statements instead, for high rate single row inserts.
+// Defaults to 0, each statement is committed. NOT DURABLE: Exec returns success before the statement is committed,
+// so statements are lost when the connection or database fails before the group is committed,
+// which is only reported by the *GroupCommitLostError of a later call on the connection.
+// See the group commit documentation.
+//
+// group_commit_interval - when more than 0, like 100ms, statements executed outside a transaction are committed
+// at the first statement executed group_commit_interval after the first uncommitted statement. Defaults to 0, no interval.
+// Has the same durability caveats as group_commit_count. There is no timer, an idle connection commits when it is next used or closed.
+//
+// pool_max - when more than 0, sessions are taken from an OCI session pool, instead of each connection logging on,
+// so opening a connection after the first does not wait for a new session. Defaults to 0, no session pool.
+// The pool is shared by the connections with the same connect string, username, password, charset, stmt_cache_size, and pool parameters.
+// Closing a connection returns the session to the pool, the session state like ALTER SESSION settings is kept.
+// Use CloseSessionPools to close the pools. The as parameter is not supported with a session pool.
+//
+// pool_min - the number of sessions the session pool keeps open. Defaults to 0.
+//
+// pool_increment - the number of sessions the session pool opens when it needs more sessions. Defaults to 1.
+func ParseDSN(dsnString string) (*DSN, error) {
+	params, err := oracle.ParseDSN(dsnString)
+	if err != nil {
+		return nil, err
+	}
+	if params.TempTablespace != "" {
+		err = ValidateIdentifier(params.TempTablespace)
+		if err != nil {
+			return nil, fmt.Errorf("invalid temp_tablespace: %v", err)
+		}
 	}
+	return dsnFromParams(params), nil
+}
 
-	const prefix = "oracle://"
+// newDSN returns a DSN with the default parameters
+func newDSN() *DSN {
+	return dsnFromParams(oracle.NewDSN())
+}
 
-	if strings.HasPrefix(dsnString, prefix) {
-		dsnString = dsnString[len(prefix):]
+// dsnFromParams returns the DSN of the parameters parsed by the oracle package, with the OCI modes of the parameters
+func dsnFromParams(params *oracle.DSN) *DSN {
+	dsn := &DSN{
+		Connect:              params.Connect,
+		Username:             params.Username,
+		Password:             params.Password,
+		prefetchRows:         C.ub4(params.PrefetchRows),
+		prefetchMemory:       C.ub4(params.PrefetchMemory),
+		prefetchSet:          params.PrefetchSet,
+		prefetchTarget:       params.PrefetchTarget,
+		prefetchTargetSet:    params.PrefetchTargetSet,
+		timeLocation:         params.TimeLocation,
+		enableQMPlaceholders: params.QuestionPlaceholders,
+		operationMode:        C.OCI_DEFAULT,
+		stmtCacheSize:        C.ub4(params.StmtCacheSize),
+		tempLobCache:         params.TempLobCache,
+		tempTablespace:       params.TempTablespace,
+		lobPrefetchSize:      C.ub4(params.LobPrefetchSize),
+		fetchArraySize:       C.ub4(params.FetchArraySize),
+		callTimeoutOff:       !params.CallTimeout,
+		floatDecimal:         params.FloatDecimal,
+		floatPrecision:       params.FloatPrecision,
+		dateRangeClamp:       params.DateRangeClamp,
+		numberStrings:        params.NumberStrings,
+		intervalInt64:        params.IntervalInt64,
+		identityReturning:    params.IdentityReturning,
+		debugConcurrentUse:   params.DebugConcurrentUse,
+		charset:              params.Charset,
+		utf16:                params.UTF16,
+		ignoreEnv:            params.IgnoreEnv,
+		tnsAdmin:             params.TNSAdmin,
+		oracleHome:           params.OracleHome,
+		nlsLanguage:          params.NLSLanguage,
+		nlsTerritory:         params.NLSTerritory,
+		sessionTimeZone:      params.TimeZone,
+		ltzLocation:          params.LTZLocation,
+		maxRows:              params.MaxRows,
+		closeTimeout:         params.CloseTimeout,
+		poolMin:              C.ub4(params.PoolMin),
+		poolMax:              C.ub4(params.PoolMax),
+		poolIncrement:        C.ub4(params.PoolIncrement),
+		readOnly:             params.ReadOnly,
+		groupCommitCount:     params.GroupCommitCount,
+		groupCommitInterval:  params.GroupCommitInterval,
 	}
 
-	dsn = &DSN{
-		prefetchRows:   0,
-		prefetchMemory: 4096,
-		stmtCacheSize:  0,
-		operationMode:  C.OCI_DEFAULT,
-		timeLocation:   time.UTC,
+	switch params.Isolation {
+	case "READONLY":
+		dsn.transactionMode = C.OCI_TRANS_READONLY
+	case "SERIALIZABLE":
+		dsn.transactionMode = C.OCI_TRANS_SERIALIZABLE
+	case "DEFAULT":
+		dsn.transactionMode = C.OCI_TRANS_READWRITE
 	}
 
-	authority, dsnString := splitRight(dsnString, "@")
-	if authority != "" {
-		dsn.Username, dsn.Password, err = parseAuthority(authority)
-		if err != nil {
-			return nil, err
-		}
+	switch params.As {
+	case "SYSDBA":
+		dsn.operationMode = C.OCI_SYSDBA
+	case "SYSASM":
+		dsn.operationMode = C.OCI_SYSASM
+	case "SYSOPER":
+		dsn.operationMode = C.OCI_SYSOPER
 	}
 
-	host, params := splitRight(dsnString, "?")
-
-	if host, err = unescape(host, encodeHost); err != nil {
-		return nil, err
+	switch params.DDLInTx {
+	case "WARN":
+		dsn.ddlInTx = ddlInTxWarn
+	case "ERROR":
+		dsn.ddlInTx = ddlInTxError
 	}
 
-	dsn.Connect = host
+	return dsn
+}
 
-	qp, err := ParseQuery(params)
-	for k, v := range qp {
-		switch k {
-		case "loc":
-			if len(v) > 0 {
-				if dsn.timeLocation, err = time.LoadLocation(v[0]); err != nil {
-					return nil, fmt.Errorf("Invalid loc: %v: %v", v[0], err)
-				}
-			}
-		case "isolation":
-			switch v[0] {
-			case "READONLY":
-				dsn.transactionMode = C.OCI_TRANS_READONLY
-			case "SERIALIZABLE":
-				dsn.transactionMode = C.OCI_TRANS_SERIALIZABLE
-			case "DEFAULT":
-				dsn.transactionMode = C.OCI_TRANS_READWRITE
-			default:
-				return nil, fmt.Errorf("Invalid isolation: %v", v[0])
-			}
-		case "questionph":
-			dsn.enableQMPlaceholders, err = strconv.ParseBool(v[0])
-			if err != nil {
-				return nil, fmt.Errorf("Invalid questionph: %v", v[0])
-			}
-		case "prefetch_rows":
-			z, err := strconv.ParseUint(v[0], 10, 32)
-			if err != nil {
-				return nil, fmt.Errorf("invalid prefetch_rows: %v", v[0])
-			}
-			dsn.prefetchRows = C.ub4(z)
-		case "prefetch_memory":
-			z, err := strconv.ParseUint(v[0], 10, 32)
-			if err != nil {
-				return nil, fmt.Errorf("invalid prefetch_memory: %v", v[0])
-			}
-			dsn.prefetchMemory = C.ub4(z)
-		case "as":
-			switch v[0] {
-			case "SYSDBA", "sysdba":
-				dsn.operationMode = C.OCI_SYSDBA
-			case "SYSASM", "sysasm":
-				dsn.operationMode = C.OCI_SYSASM
-			case "SYSOPER", "sysoper":
-				dsn.operationMode = C.OCI_SYSOPER
-			default:
-				return nil, fmt.Errorf("Invalid as: %v", v[0])
-			}
-		case "stmt_cache_size":
-			z, err := strconv.ParseUint(v[0], 10, 32)
-			if err != nil {
-				return nil, fmt.Errorf("invalid stmt_cache_size: %v", v[0])
-			}
-			dsn.stmtCacheSize = C.ub4(z)
-		}
+// prefetchTargetBytes returns the target bytes of the rows prefetched in a round trip, 0 when the prefetch rows are not tuned,
+// see the prefetch_target parameter
+func (dsn *DSN) prefetchTargetBytes() int64 {
+	switch {
+	case dsn.prefetchTargetSet:
+		return dsn.prefetchTarget
+	case dsn.prefetchSet:
+		return 0
 	}
+	return defaultPrefetchTarget
+}
 
-	return dsn, nil
+// checkSessionPool checks the session pool parameters and sets the default pool_increment
+func (dsn *DSN) checkSessionPool() error {
+	if dsn.poolMax > 0 {
+		if dsn.poolMin > dsn.poolMax {
+			return fmt.Errorf("invalid pool_min: %v is more than pool_max %v", dsn.poolMin, dsn.poolMax)
+		}
+		if dsn.operationMode != C.OCI_DEFAULT {
+			return errors.New("invalid as: session pools do not support as")
+		}
+		if dsn.poolIncrement == 0 {
+			dsn.poolIncrement = 1
+		}
+	}
+	return nil
 }
 
 // Commit transaction commit
@@ -160,54 +324,128 @@ func (tx *Tx) Rollback() error {
 
 // Open opens a new database connection
 func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
-	var err error
-	var dsn *DSN
-	if dsn, err = ParseDSN(dsnString); err != nil {
+	dsn, err := ParseDSN(dsnString)
+	if err != nil {
 		return nil, err
 	}
 
-	conn := Conn{
-		operationMode: dsn.operationMode,
-		stmtCacheSize: dsn.stmtCacheSize,
-		logger:        drv.Logger,
+	conn := &Conn{
+		logger:            drv.Logger,
+		hooks:             drv.Hooks,
+		converter:         drv.Converter,
+		errorTranslations: drv.ErrorTranslations,
+		clock:             drv.Clock,
+		leaks:             &drv.leaks,
 	}
+	err = conn.open(dsn)
+	conn.countConnect(err)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// resolveConnect returns the DSN with the connect string resolved with the tnsnames.ora of tns_admin or oracle_home,
+// when set and the connect string is a net service name, otherwise the DSN
+func (dsn *DSN) resolveConnect() (*DSN, error) {
+	directory := dsn.tnsAdmin
+	if directory == "" && dsn.oracleHome != "" {
+		directory = filepath.Join(dsn.oracleHome, "network", "admin")
+	}
+	if directory == "" || !oracle.IsTNSAlias(dsn.Connect) {
+		return dsn, nil
+	}
+
+	connect, err := oracle.ResolveTNSAlias(directory, dsn.Connect)
+	if err != nil {
+		return nil, fmt.Errorf("resolve net service name error: %v", err)
+	}
+	resolved := *dsn
+	resolved.Connect = connect
+	return &resolved, nil
+}
+
+// envCharset returns the character set ID to create the environment with, 0 to take it from NLS_LANG and NLS_NCHAR,
+// see the charset parameter
+func (dsn *DSN) envCharset() (C.ub2, error) {
+	switch {
+	case dsn.utf16:
+		return C.OCI_UTF16ID, nil
+	case dsn.charset != "":
+		return charsetID(dsn.charset)
+	case dsn.ignoreEnv || (os.Getenv("NLS_LANG") == "" && os.Getenv("NLS_NCHAR") == ""):
+		return defaultCharset, nil
+	}
+	return 0, nil
+}
+
+// open connects the connection with the DSN.
+// The connection has the options that are not in the DSN set, like the logger and hooks.
+func (conn *Conn) open(dsn *DSN) error {
+	dsn, err := dsn.resolveConnect()
+	if err != nil {
+		return err
+	}
+	conn.operationMode = dsn.operationMode
+	conn.stmtCacheSize = dsn.stmtCacheSize
 	if conn.logger == nil {
 		conn.logger = log.New(ioutil.Discard, "", 0)
 	}
+	if conn.hooks.LeakReport != nil {
+		conn.leaks.enable()
+	}
 
 	// environment handle
 	var envP *C.OCIEnv
 	envPP := &envP
 	var result C.sword
-	charset := C.ub2(0)
-
-	if os.Getenv("NLS_LANG") == "" && os.Getenv("NLS_NCHAR") == "" {
-		charset = defaultCharset
-	}
-
-	result = C.OCIEnvNlsCreate(
-		envPP,          // pointer to a handle to the environment
-		C.OCI_THREADED, // environment mode: https://docs.oracle.com/cd/B28359_01/appdev.111/b28395/oci16rel001.htm#LNOCI87683
-		nil,            // Specifies the user-defined context for the memory callback routines.
-		nil,            // Specifies the user-defined memory allocation function. If mode is OCI_THREADED, this memory allocation routine must be thread-safe.
-		nil,            // Specifies the user-defined memory re-allocation function. If the mode is OCI_THREADED, this memory allocation routine must be thread safe.
-		nil,            // Specifies the user-defined memory free function. If mode is OCI_THREADED, this memory free routine must be thread-safe.
-		0,              // Specifies the amount of user memory to be allocated for the duration of the environment.
-		nil,            // Returns a pointer to the user memory of size xtramemsz allocated by the call for the user.
-		charset,        // The client-side character set for the current environment handle. If it is 0, the NLS_LANG setting is used.
-		charset,        // The client-side national character set for the current environment handle. If it is 0, NLS_NCHAR setting is used.
-	)
-	if result != C.OCI_SUCCESS {
-		return nil, errors.New("OCIEnvNlsCreate error")
+	charset, err := dsn.envCharset()
+	if err != nil {
+		return err
+	}
+
+	// set before connecting, for the text of the connect errors
+	conn.utf16 = dsn.utf16
+
+	var pool *sessionPool
+	if dsn.poolMax > 0 {
+		// the connections of a session pool share the environment of the pool
+		pool, err = getSessionPool(dsn, charset)
+		if err != nil {
+			return err
+		}
+		conn.env = pool.env
+	} else {
+		result = C.OCIEnvNlsCreate(
+			envPP,                       // pointer to a handle to the environment
+			C.OCI_THREADED|C.OCI_OBJECT, // environment mode, OCI_OBJECT for the object API of SDO_GEOMETRY columns: https://docs.oracle.com/cd/B28359_01/appdev.111/b28395/oci16rel001.htm#LNOCI87683
+			nil,                         // Specifies the user-defined context for the memory callback routines.
+			nil,                         // Specifies the user-defined memory allocation function. If mode is OCI_THREADED, this memory allocation routine must be thread-safe.
+			nil,                         // Specifies the user-defined memory re-allocation function. If the mode is OCI_THREADED, this memory allocation routine must be thread safe.
+			nil,                         // Specifies the user-defined memory free function. If mode is OCI_THREADED, this memory free routine must be thread-safe.
+			0,                           // Specifies the amount of user memory to be allocated for the duration of the environment.
+			nil,                         // Returns a pointer to the user memory of size xtramemsz allocated by the call for the user.
+			charset,                     // The client-side character set for the current environment handle. If it is 0, the NLS_LANG setting is used.
+			charset,                     // The client-side national character set for the current environment handle. If it is 0, NLS_NCHAR setting is used.
+		)
+		if result != C.OCI_SUCCESS {
+			// usually the client library can not find its files, like the time zone files or message files
+			return &ConnectError{Err: errors.New("OCIEnvNlsCreate error"), Diagnostics: clientDiagnostics(os.Getenv, "/proc/self/maps")}
+		}
+		conn.env = *envPP
+		conn.leaks.alloc(unsafe.Pointer(conn.env), ociTypeName(C.OCI_HTYPE_ENV))
 	}
-	conn.env = *envPP
 
 	// defer on error handle free
 	var doneSessionBegin bool
 	var doneServerAttach bool
 	var doneLogon bool
+	var doneSessionGet bool
 	defer func(errP *error) {
 		if *errP != nil {
+			if doneSessionGet {
+				conn.ociSessionRelease(true)
+			}
 			if doneSessionBegin {
 				C.OCISessionEnd(
 					conn.svc,
@@ -230,26 +468,29 @@ func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
 				)
 			}
 			if conn.txHandle != nil {
-				C.OCIHandleFree(unsafe.Pointer(conn.txHandle), C.OCI_HTYPE_TRANS)
+				conn.clearTransHandle()
+				ociHandleFree(unsafe.Pointer(conn.txHandle), C.OCI_HTYPE_TRANS)
 				conn.txHandle = nil
 			}
 			if conn.usrSession != nil {
-				C.OCIHandleFree(unsafe.Pointer(conn.usrSession), C.OCI_HTYPE_SESSION)
+				ociHandleFree(unsafe.Pointer(conn.usrSession), C.OCI_HTYPE_SESSION)
 				conn.usrSession = nil
 			}
 			if conn.svc != nil {
-				C.OCIHandleFree(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX)
+				ociHandleFree(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX)
 				conn.svc = nil
 			}
 			if conn.srv != nil {
-				C.OCIHandleFree(unsafe.Pointer(conn.srv), C.OCI_HTYPE_SERVER)
+				ociHandleFree(unsafe.Pointer(conn.srv), C.OCI_HTYPE_SERVER)
 				conn.srv = nil
 			}
 			if conn.errHandle != nil {
-				C.OCIHandleFree(unsafe.Pointer(conn.errHandle), C.OCI_HTYPE_ERROR)
+				ociHandleFree(unsafe.Pointer(conn.errHandle), C.OCI_HTYPE_ERROR)
 				conn.errHandle = nil
 			}
-			C.OCIHandleFree(unsafe.Pointer(conn.env), C.OCI_HTYPE_ENV)
+			if pool == nil {
+				ociHandleFree(unsafe.Pointer(conn.env), C.OCI_HTYPE_ENV)
+			}
 		}
 	}(&err)
 
@@ -266,22 +507,32 @@ func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
 	if result != C.OCI_SUCCESS {
 		// TODO: error handle not yet allocated, how to get string error from oracle?
 		err = errors.New("allocate error handle error")
-		return nil, err
+		return err
 	}
 	conn.errHandle = (*C.OCIError)(*handle)
+	conn.leaks.alloc(*handle, ociTypeName(C.OCI_HTYPE_ERROR))
 
-	connectString := cString(dsn.Connect)
+	connectString, connectStringLength := cText(dsn.Connect, dsn.utf16)
 	defer C.free(unsafe.Pointer(connectString))
-	username := cString(dsn.Username)
+	username, usernameLength := cText(dsn.Username, dsn.utf16)
 	defer C.free(unsafe.Pointer(username))
-	password := cString(dsn.Password)
+	password, passwordLength := cText(dsn.Password, dsn.utf16)
 	defer C.free(unsafe.Pointer(password))
 
-	if useOCISessionBegin {
+	if pool != nil {
+
+		result, err = conn.ociSessionGet(pool)
+		if err != nil {
+			return err
+		}
+		doneSessionGet = true
+		conn.connectWarning(result, dsn.Username)
+
+	} else if useOCISessionBegin {
 		// server handle
 		handle, _, err = conn.ociHandleAlloc(C.OCI_HTYPE_SERVER, 0)
 		if err != nil {
-			return nil, fmt.Errorf("allocate server handle error: %v", err)
+			return fmt.Errorf("allocate server handle error: %v", err)
 		}
 		conn.srv = (*C.OCIServer)(*handle)
 
@@ -295,51 +546,51 @@ func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
 			)
 		} else {
 			result = C.OCIServerAttach(
-				conn.srv,                // uninitialized server handle, which gets initialized by this call. Passing in an initialized server handle causes an error.
-				conn.errHandle,          // error handle
-				connectString,           // connect string or a service point
-				C.sb4(len(dsn.Connect)), // length of the database server
-				C.OCI_DEFAULT,           // mode of operation: OCI_DEFAULT or OCI_CPOOL
+				conn.srv,                   // uninitialized server handle, which gets initialized by this call. Passing in an initialized server handle causes an error.
+				conn.errHandle,             // error handle
+				connectString,              // connect string or a service point
+				C.sb4(connectStringLength), // length of the database server
+				C.OCI_DEFAULT,              // mode of operation: OCI_DEFAULT or OCI_CPOOL
 			)
 		}
 		if result != C.OCI_SUCCESS {
 			err = conn.getError(result)
-			return nil, conn.getError(result)
+			return connectError(err)
 		}
 		doneServerAttach = true
 
 		// service handle
 		handle, _, err = conn.ociHandleAlloc(C.OCI_HTYPE_SVCCTX, 0)
 		if err != nil {
-			return nil, fmt.Errorf("allocate service handle error: %v", err)
+			return fmt.Errorf("allocate service handle error: %v", err)
 		}
 		conn.svc = (*C.OCISvcCtx)(*handle)
 
 		// sets the server context attribute of the service context
 		err = conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(conn.srv), 0, C.OCI_ATTR_SERVER)
 		if err != nil {
-			return nil, fmt.Errorf("server context attribute set error: %v", err)
+			return fmt.Errorf("server context attribute set error: %v", err)
 		}
 
 		// user session handle
 		handle, _, err = conn.ociHandleAlloc(C.OCI_HTYPE_SESSION, 0)
 		if err != nil {
-			return nil, fmt.Errorf("allocate user session handle error: %v", err)
+			return fmt.Errorf("allocate user session handle error: %v", err)
 		}
 		conn.usrSession = (*C.OCISession)(*handle)
 
 		credentialType := C.ub4(C.OCI_CRED_EXT)
 		if len(dsn.Username) > 0 {
 			// specifies a username to use for authentication
-			err = conn.ociAttrSet(unsafe.Pointer(conn.usrSession), C.OCI_HTYPE_SESSION, unsafe.Pointer(username), C.ub4(len(dsn.Username)), C.OCI_ATTR_USERNAME)
+			err = conn.ociAttrSet(unsafe.Pointer(conn.usrSession), C.OCI_HTYPE_SESSION, unsafe.Pointer(username), usernameLength, C.OCI_ATTR_USERNAME)
 			if err != nil {
-				return nil, fmt.Errorf("username attribute set error: %v", err)
+				return fmt.Errorf("username attribute set error: %v", err)
 			}
 
 			// specifies a password to use for authentication
-			err = conn.ociAttrSet(unsafe.Pointer(conn.usrSession), C.OCI_HTYPE_SESSION, unsafe.Pointer(password), C.ub4(len(dsn.Password)), C.OCI_ATTR_PASSWORD)
+			err = conn.ociAttrSet(unsafe.Pointer(conn.usrSession), C.OCI_HTYPE_SESSION, unsafe.Pointer(password), passwordLength, C.OCI_ATTR_PASSWORD)
 			if err != nil {
-				return nil, fmt.Errorf("password attribute set error: %v", err)
+				return fmt.Errorf("password attribute set error: %v", err)
 			}
 
 			credentialType = C.OCI_CRED_RDBMS
@@ -354,21 +605,22 @@ func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
 		)
 		if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
 			err = conn.getError(result)
-			return nil, err
+			return err
 		}
 		doneSessionBegin = true
+		conn.connectWarning(result, dsn.Username)
 
 		// sets the authentication context attribute of the service context
 		err = conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(conn.usrSession), 0, C.OCI_ATTR_SESSION)
 		if err != nil {
-			return nil, fmt.Errorf("authentication context attribute set error: %v", err)
+			return fmt.Errorf("authentication context attribute set error: %v", err)
 		}
 
 		if dsn.stmtCacheSize > 0 {
 			stmtCacheSize := dsn.stmtCacheSize
 			err = conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&stmtCacheSize), 0, C.OCI_ATTR_STMTCACHESIZE)
 			if err != nil {
-				return nil, fmt.Errorf("stmt cache size attribute set error: %v", err)
+				return fmt.Errorf("stmt cache size attribute set error: %v", err)
 			}
 		}
 
@@ -377,53 +629,182 @@ func (drv *DriverStruct) Open(dsnString string) (driver.Conn, error) {
 		var svcCtxP *C.OCISvcCtx
 		svcCtxPP := &svcCtxP
 		result = C.OCILogon(
-			conn.env,                 // environment handle
-			conn.errHandle,           // error handle
-			svcCtxPP,                 // service context pointer
-			username,                 // user name. Must be in the encoding specified by the charset parameter of a previous call to OCIEnvNlsCreate().
-			C.ub4(len(dsn.Username)), // length of user name, in number of bytes, regardless of the encoding
-			password,                 // user's password. Must be in the encoding specified by the charset parameter of a previous call to OCIEnvNlsCreate().
-			C.ub4(len(dsn.Password)), // length of password, in number of bytes, regardless of the encoding.
-			connectString,            // name of the database to connect to. Must be in the encoding specified by the charset parameter of a previous call to OCIEnvNlsCreate().
-			C.ub4(len(dsn.Connect)),  // length of dbname, in number of bytes, regardless of the encoding.
+			conn.env,            // environment handle
+			conn.errHandle,      // error handle
+			svcCtxPP,            // service context pointer
+			username,            // user name. Must be in the encoding specified by the charset parameter of a previous call to OCIEnvNlsCreate().
+			usernameLength,      // length of user name, in number of bytes, regardless of the encoding
+			password,            // user's password. Must be in the encoding specified by the charset parameter of a previous call to OCIEnvNlsCreate().
+			passwordLength,      // length of password, in number of bytes, regardless of the encoding.
+			connectString,       // name of the database to connect to. Must be in the encoding specified by the charset parameter of a previous call to OCIEnvNlsCreate().
+			connectStringLength, // length of dbname, in number of bytes, regardless of the encoding.
 		)
 		if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
 			err = conn.getError(result)
-			return nil, err
+			return connectError(err)
 		}
 		conn.svc = *svcCtxPP
 		doneLogon = true
+		conn.connectWarning(result, dsn.Username)
 	}
 
 	// Create transaction context.
 	handle, _, err = conn.ociHandleAlloc(C.OCI_HTYPE_TRANS, 0)
 	if err != nil {
-		return nil, fmt.Errorf("allocate transaction handle error: %v", err)
+		return fmt.Errorf("allocate transaction handle error: %v", err)
 	}
 	conn.txHandle = (*C.OCITrans)(*handle)
 
 	// Set transaction context attribute of the service context.
 	err = conn.ociAttrSet(unsafe.Pointer(conn.svc), C.OCI_HTYPE_SVCCTX, *handle, 0, C.OCI_ATTR_TRANS)
 	if err != nil {
-		return nil, fmt.Errorf("service context attribute set error: %v", err)
+		return fmt.Errorf("service context attribute set error: %v", err)
 	}
 
 	conn.transactionMode = dsn.transactionMode
 	conn.prefetchRows = dsn.prefetchRows
 	conn.prefetchMemory = dsn.prefetchMemory
+	conn.prefetchTarget = dsn.prefetchTargetBytes()
 	conn.timeLocation = dsn.timeLocation
+	conn.ltzLocation = dsn.ltzLocation
 	conn.enableQMPlaceholders = dsn.enableQMPlaceholders
+	conn.tempLobCache = dsn.tempLobCache
+	conn.lobPrefetchSize = dsn.lobPrefetchSize
+	conn.fetchArraySize = dsn.fetchArraySize
+	conn.callTimeouts = !dsn.callTimeoutOff && clientMajorVersion() >= 18
+	conn.floatDecimal = dsn.floatDecimal
+	conn.floatPrecision = dsn.floatPrecision
+	conn.dateRangeClamp = dsn.dateRangeClamp
+	conn.numberStrings = dsn.numberStrings
+	conn.intervalInt64 = dsn.intervalInt64
+	conn.identityReturning = dsn.identityReturning
+	conn.debugConcurrentUse = dsn.debugConcurrentUse
+	conn.maxRows = dsn.maxRows
+	conn.closeTimeout = dsn.closeTimeout
+	conn.groupCommitCount = dsn.groupCommitCount
+	conn.groupCommitInterval = dsn.groupCommitInterval
+	conn.envCharsetID = conn.nameCharsetID()
+
+	nlsLanguage, nlsTerritory := dsn.nlsLanguage, dsn.nlsTerritory
+	if dsn.ignoreEnv {
+		if nlsLanguage == "" {
+			nlsLanguage = "AMERICAN"
+		}
+		if nlsTerritory == "" {
+			nlsTerritory = "AMERICA"
+		}
+	}
+	if nlsLanguage != "" || nlsTerritory != "" {
+		_, err = conn.exec(context.Background(), alterSessionNLS(nlsLanguage, nlsTerritory), nil)
+		if err != nil {
+			err = fmt.Errorf("alter session NLS error: %v", err)
+			return err
+		}
+	}
 
-	return &conn, nil
+	if dsn.sessionTimeZone != "" {
+		_, err = conn.exec(context.Background(), alterSessionTimeZone(dsn.sessionTimeZone), nil)
+		if err != nil {
+			err = fmt.Errorf("alter session time zone error: %v", err)
+			return err
+		}
+	}
+
+	if dsn.tempTablespace != "" {
+		err = conn.setTempTablespace(dsn.tempTablespace)
+		if err != nil {
+			return fmt.Errorf("set temporary tablespace error: %v", err)
+		}
+	}
+
+	// set after the NLS, time zone, and temporary tablespace statements, which are refused on a read only connection
+	conn.readOnly = dsn.readOnly
+	conn.ddlInTx = dsn.ddlInTx
+
+	conn.leaks.connOpened()
+	return nil
+}
+
+// charsetID returns the Oracle character set id of a character set name, like AL32UTF8
+func charsetID(name string) (C.ub2, error) {
+	charsetIDsMutex.Lock()
+	defer charsetIDsMutex.Unlock()
+
+	if id, ok := charsetIDs[name]; ok {
+		return id, nil
+	}
+
+	var envP *C.OCIEnv
+	envPP := &envP
+	result := C.OCIEnvCreate(envPP, C.OCI_DEFAULT, nil, nil, nil, nil, 0, nil)
+	if result != C.OCI_SUCCESS {
+		return 0, errors.New("OCIEnvCreate error")
+	}
+	defer ociHandleFree(unsafe.Pointer(*envPP), C.OCI_HTYPE_ENV)
+
+	charsetName := cString(name)
+	defer C.free(unsafe.Pointer(charsetName))
+	id := C.OCINlsCharSetNameToId(unsafe.Pointer(*envPP), (*C.oratext)(charsetName))
+	if id == 0 {
+		return 0, fmt.Errorf("invalid charset: %v", name)
+	}
+
+	charsetIDs[name] = id
+	return id, nil
+}
+
+// alterSessionNLS returns the alter session statement that sets the NLS language and territory, empty values are not set
+func alterSessionNLS(nlsLanguage string, nlsTerritory string) string {
+	query := "alter session set"
+	if nlsLanguage != "" {
+		query += " NLS_LANGUAGE = " + quoteString(nlsLanguage)
+	}
+	if nlsTerritory != "" {
+		query += " NLS_TERRITORY = " + quoteString(nlsTerritory)
+	}
+	return query
+}
+
+// alterSessionTimeZone returns the alter session statement that sets the session time zone
+func alterSessionTimeZone(timeZone string) string {
+	return "alter session set TIME_ZONE = " + quoteString(timeZone)
+}
+
+// quoteString returns the string as a SQL string literal
+func quoteString(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
 }
 
-// GetLastInsertId returns rowid from LastInsertId
+// GetLastInsertId returns rowid from LastInsertId.
+// The id is only valid while the result it came from is reachable, prefer GetLastRowID.
 func GetLastInsertId(id int64) string {
 	return *(*string)(unsafe.Pointer(uintptr(id)))
 }
 
-// LastInsertId returns last inserted ID
+// GetLastRowID returns the ROWID of the last row inserted, updated, or deleted by the execute of the result,
+// as Oracle has no auto increment ids. Returns ErrNoRowid when no rows were affected.
+// The result can be a sql.Result or a driver.Result from the Conn of this driver.
+// For a Batch the ROWID is the one of the last row of the last chunk.
+func GetLastRowID(result driver.Result) (string, error) {
+	if oci8Result, ok := result.(*Result); ok {
+		return oci8Result.rowid, oci8Result.rowidErr
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+	rowid := GetLastInsertId(id)
+	// the id points into the result, keep the result alive until the rowid has been copied
+	runtime.KeepAlive(result)
+	return rowid, nil
+}
+
+// LastInsertId returns last inserted ID, a pointer to the ROWID for GetLastInsertId,
+// or the identity value of the row inserted with identity_returning
 func (result *Result) LastInsertId() (int64, error) {
+	if result.identityReturning {
+		return result.identity, result.identityErr
+	}
 	return int64(uintptr(unsafe.Pointer(&result.rowid))), result.rowidErr
 }
 
@@ -464,3 +845,15 @@ func timezoneToLocation(hour int64, minute int64) *time.Location {
 	// use location from timeLocations cache
 	return timeLocations[12+hour]
 }
+
+// valueToInt64 converts a NUMBER column value returned by Rows.Next to int64.
+// A null value returns 0.
+func valueToInt64(value driver.Value) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
@@ -1,2 +1,75 @@
 #include <oci.h>
 #include <stdlib.h>
+
+#ifdef OCI_DTYPE_VECTOR
+#define OCI8_VECTOR_SUPPORTED 1
+#else
+// client headers before 23ai do not have VECTOR, define what is needed to compile.
+// the Go code checks OCI8_VECTOR_SUPPORTED before calling the vector functions.
+#define OCI8_VECTOR_SUPPORTED 0
+#define OCI_DTYPE_VECTOR 87
+#define SQLT_VEC 127
+#define OCI_ATTR_VECTOR_DIMENSION 647
+#define OCI_ATTR_VECTOR_DATA_FORMAT 648
+#define OCI_ATTR_VECTOR_FORMAT_FLOAT32 2
+#define OCI_ATTR_VECTOR_FORMAT_FLOAT64 3
+typedef struct OCIVector OCIVector;
+static inline sword OCIVectorFromArray(OCIVector *vectord, OCIError *errhp, ub1 vformat, ub4 vdim, void *vecarray, ub4 mode) { return OCI_ERROR; }
+static inline sword OCIVectorToArray(OCIVector *vectord, OCIError *errhp, ub1 vformat, ub4 *vdim, void *vecarray, ub4 mode) { return OCI_ERROR; }
+#endif
+
+#ifndef OCI_ATTR_COLLATION_ID
+// client headers before 12.2 do not have collations
+#define OCI_ATTR_COLLATION_ID 473
+#endif
+
+#ifndef OCI_ATTR_TRANSACTION_IN_PROGRESS
+// client headers before 12c do not have the transaction in progress attribute, the Go code checks the client version before getting it
+#define OCI_ATTR_TRANSACTION_IN_PROGRESS 484
+#endif
+
+#ifndef OCI_ATTR_ERROR_IS_RECOVERABLE
+// client headers before 12c do not have recoverable errors, the Go code checks the client version before getting it
+#define OCI_ATTR_ERROR_IS_RECOVERABLE 472
+#endif
+
+#ifndef SQLT_JSON
+// client headers before 21c do not have the JSON type, only the column type name uses it
+#define SQLT_JSON 119
+#endif
+
+#ifndef OCI_ATTR_CALL_TIMEOUT
+// client headers before 18c do not have call timeouts, the Go code checks the client version before setting it
+#define OCI_ATTR_CALL_TIMEOUT 531
+#endif
+
+// MDSYS.SDO_GEOMETRY object and null indicator structs, in the layout OTT generates for the types
+typedef struct {
+	OCINumber x;
+	OCINumber y;
+	OCINumber z;
+} oci8_sdo_point;
+
+typedef struct {
+	OCIInd atomic;
+	OCIInd x;
+	OCIInd y;
+	OCIInd z;
+} oci8_sdo_point_ind;
+
+typedef struct {
+	OCINumber sdo_gtype;
+	OCINumber sdo_srid;
+	oci8_sdo_point sdo_point;
+	OCIArray *sdo_elem_info;
+	OCIArray *sdo_ordinates;
+} oci8_sdo_geometry;
+
+typedef struct {
+	OCIInd atomic;
+	OCIInd sdo_gtype;
+	OCIInd sdo_srid;
+	oci8_sdo_point_ind sdo_point;
+	OCIInd sdo_elem_info;
+	OCIInd sdo_ordinates;
+} oci8_sdo_geometry_ind;
@@ -82,3 +82,4 @@ func TestStatementCaching(t *testing.T) {
 		t.Fatal("stmt close error:", err)
 	}
 }
+
@@ -3789,18 +3789,37 @@ func TestDestructiveNumberColumnTypes(t *testing.T) {
 
 	columnNum := 0
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_BDOUBLE" {
+	if columnTypes[columnNum].DatabaseTypeName() != "NUMBER" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok := columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != 0 {
 		t.Error("Length does not match -", length)
 	}
-	if ok != true {
+	if ok != false {
 		t.Error("Length ok does not match -", ok)
 	}
 
+	precision, scale, ok := columnTypes[columnNum].DecimalSize()
+	if precision != 10 {
+		t.Error("DecimalSize precision does not match -", precision)
+	}
+	if scale != 2 {
+		t.Error("DecimalSize scale does not match -", scale)
+	}
+	if ok != true {
+		t.Error("DecimalSize ok does not match -", ok)
+	}
+
+	nullable, ok := columnTypes[columnNum].Nullable()
+	if nullable != true {
+		t.Error("Nullable does not match -", nullable)
+	}
+	if ok != true {
+		t.Error("Nullable ok does not match -", ok)
+	}
+
 	if columnTypes[columnNum].Name() != "A" {
 		t.Error("Name does not match -", columnTypes[columnNum].Name())
 	}
@@ -3813,18 +3832,37 @@ func TestDestructiveNumberColumnTypes(t *testing.T) {
 
 	columnNum = 1
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_BDOUBLE" {
+	if columnTypes[columnNum].DatabaseTypeName() != "FLOAT" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok = columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != 0 {
 		t.Error("Length does not match -", length)
 	}
-	if ok != true {
+	if ok != false {
 		t.Error("Length ok does not match -", ok)
 	}
 
+	precision, scale, ok = columnTypes[columnNum].DecimalSize()
+	if precision != 20 {
+		t.Error("DecimalSize precision does not match -", precision)
+	}
+	if scale != 0 {
+		t.Error("DecimalSize scale does not match -", scale)
+	}
+	if ok != true {
+		t.Error("DecimalSize ok does not match -", ok)
+	}
+
+	nullable, ok = columnTypes[columnNum].Nullable()
+	if nullable != true {
+		t.Error("Nullable does not match -", nullable)
+	}
+	if ok != true {
+		t.Error("Nullable ok does not match -", ok)
+	}
+
 	if columnTypes[columnNum].Name() != "B" {
 		t.Error("Name does not match -", columnTypes[columnNum].Name())
 	}
@@ -3837,18 +3875,37 @@ func TestDestructiveNumberColumnTypes(t *testing.T) {
 
 	columnNum = 2
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_INT" {
+	if columnTypes[columnNum].DatabaseTypeName() != "NUMBER" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok = columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != 0 {
 		t.Error("Length does not match -", length)
 	}
-	if ok != true {
+	if ok != false {
 		t.Error("Length ok does not match -", ok)
 	}
 
+	precision, scale, ok = columnTypes[columnNum].DecimalSize()
+	if precision != 38 {
+		t.Error("DecimalSize precision does not match -", precision)
+	}
+	if scale != 0 {
+		t.Error("DecimalSize scale does not match -", scale)
+	}
+	if ok != true {
+		t.Error("DecimalSize ok does not match -", ok)
+	}
+
+	nullable, ok = columnTypes[columnNum].Nullable()
+	if nullable != true {
+		t.Error("Nullable does not match -", nullable)
+	}
+	if ok != true {
+		t.Error("Nullable ok does not match -", ok)
+	}
+
 	if columnTypes[columnNum].Name() != "C" {
 		t.Error("Name does not match -", columnTypes[columnNum].Name())
 	}
@@ -3,6 +3,7 @@ package oci8
 import (
 	"context"
 	"database/sql"
+	"math"
 	"strings"
 	"testing"
 )
@@ -3315,7 +3316,7 @@ func TestDestructiveStringColumnTypes(t *testing.T) {
 
 	columnNum := 0
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_AFC" {
+	if columnTypes[columnNum].DatabaseTypeName() != "VARCHAR2" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
@@ -3339,7 +3340,7 @@ func TestDestructiveStringColumnTypes(t *testing.T) {
 
 	columnNum = 1
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_BIN" {
+	if columnTypes[columnNum].DatabaseTypeName() != "RAW" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
@@ -3363,12 +3364,12 @@ func TestDestructiveStringColumnTypes(t *testing.T) {
 
 	columnNum = 2
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_CLOB" {
+	if columnTypes[columnNum].DatabaseTypeName() != "CLOB" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok = columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != math.MaxInt64 {
 		t.Error("Length does not match -", length)
 	}
 	if ok != true {
@@ -3387,12 +3388,12 @@ func TestDestructiveStringColumnTypes(t *testing.T) {
 
 	columnNum = 3
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_BLOB" {
+	if columnTypes[columnNum].DatabaseTypeName() != "BLOB" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok = columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != math.MaxInt64 {
 		t.Error("Length does not match -", length)
 	}
 	if ok != true {
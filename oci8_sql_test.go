@@ -18,18 +18,7 @@ import (
 func testGetDB(params string) *sql.DB {
 	Driver.Logger = log.New(os.Stderr, "oci8 ", log.Ldate|log.Ltime|log.LUTC|log.Lshortfile)
 
-	var openString string
-	// [username/[password]@]host[:port][/service_name][?param1=value1&...&paramN=valueN]
-	if len(TestUsername) > 0 {
-		if len(TestPassword) > 0 {
-			openString = TestUsername + "/" + TestPassword + "@"
-		} else {
-			openString = TestUsername + "@"
-		}
-	}
-	openString += TestHostValid + params
-
-	db, err := sql.Open("oci8", openString)
+	db, err := sql.Open("oci8", testGetOpenString(params))
 	if err != nil {
 		fmt.Println("Open error:", err)
 		return nil
@@ -51,6 +40,22 @@ func testGetDB(params string) *sql.DB {
 	return db
 }
 
+// testGetOpenString returns the string to open the test database with
+func testGetOpenString(params string) string {
+	var openString string
+	// [username/[password]@]host[:port][/service_name][?param1=value1&...&paramN=valueN]
+	if len(TestUsername) > 0 {
+		if len(TestPassword) > 0 {
+			openString = TestUsername + "/" + TestPassword + "@"
+		} else {
+			openString = TestUsername + "@"
+		}
+	}
+	openString += TestHostValid + params
+
+	return openString
+}
+
 func testDropTable(t *testing.T, tableName string) {
 	err := testExec(t, "drop table "+tableName, nil)
 	if err != nil {
@@ -282,8 +287,8 @@ func testRunQueryResult(t *testing.T, queryResult testQueryResult, query string,
 		return
 	}
 	if len(result) != len(queryResult.results) {
-		t.Errorf("result rows len %v not equal to results len %v - query: %v",
-			len(result), len(queryResult.results), query)
+		t.Errorf("result rows len %v not equal to results len %v - query: %v\n%v",
+			len(result), len(queryResult.results), query, DiffRows(queryResult.results, result))
 		return
 	}
 
@@ -323,8 +328,8 @@ func testRunQueryResult(t *testing.T, queryResult testQueryResult, query string,
 				}
 			}
 			if bad {
-				t.Errorf("result - row %v, %v - received: %T, %v - expected: %T, %v - query: %v",
-					i, j, result[i][j], result[i][j], queryResult.results[i][j], queryResult.results[i][j], query)
+				t.Errorf("result - row %v, %v - received: %v - expected: %v - query: %v",
+					i, j, FormatValue(result[i][j]), FormatValue(queryResult.results[i][j]), query)
 			}
 		}
 
@@ -939,7 +944,7 @@ func TestInsertRowid(t *testing.T) {
 		t.Fatal("prepare error:", err)
 	}
 
-	rowids := make([]string, 3)
+	rowids := make([]string, 4)
 	var result sql.Result
 	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
 	result, err = stmt.ExecContext(ctx, 1, sql.Named("rowid2", sql.Out{Dest: &rowids[0]}))
@@ -958,6 +963,12 @@ func TestInsertRowid(t *testing.T) {
 
 	rowids[1] = GetLastInsertId(id)
 
+	rowids[3], err = GetLastRowID(result)
+	if err != nil {
+		stmt.Close()
+		t.Fatal("last rowid error:", err)
+	}
+
 	err = stmt.Close()
 	if err != nil {
 		t.Fatal("stmt close error", err)
@@ -92,43 +92,43 @@ func TestSelectDualTime(t *testing.T) {
 	queryResultTimeYearToMonth := []testQueryResult{
 		{
 			args:    []interface{}{int64(-2)},
-			results: [][]interface{}{{int64(-24)}},
+			results: [][]interface{}{{YearMonth{Years: -2}}},
 		},
 		{
 			args:    []interface{}{int64(-1)},
-			results: [][]interface{}{{int64(-12)}},
+			results: [][]interface{}{{YearMonth{Years: -1}}},
 		},
 		{
 			args:    []interface{}{int64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{YearMonth{}}},
 		},
 		{
 			args:    []interface{}{int64(1)},
-			results: [][]interface{}{{int64(12)}},
+			results: [][]interface{}{{YearMonth{Years: 1}}},
 		},
 		{
 			args:    []interface{}{int64(2)},
-			results: [][]interface{}{{int64(24)}},
+			results: [][]interface{}{{YearMonth{Years: 2}}},
 		},
 		{
 			args:    []interface{}{float64(-2.5)},
-			results: [][]interface{}{{int64(-30)}},
+			results: [][]interface{}{{YearMonth{Years: -2, Months: -6}}},
 		},
 		{
 			args:    []interface{}{float64(-1.25)},
-			results: [][]interface{}{{int64(-15)}},
+			results: [][]interface{}{{YearMonth{Years: -1, Months: -3}}},
 		},
 		{
 			args:    []interface{}{float64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{YearMonth{}}},
 		},
 		{
 			args:    []interface{}{float64(1.25)},
-			results: [][]interface{}{{int64(15)}},
+			results: [][]interface{}{{YearMonth{Years: 1, Months: 3}}},
 		},
 		{
 			args:    []interface{}{float64(2.5)},
-			results: [][]interface{}{{int64(30)}},
+			results: [][]interface{}{{YearMonth{Years: 2, Months: 6}}},
 		},
 	}
 
@@ -140,43 +140,43 @@ func TestSelectDualTime(t *testing.T) {
 	queryResultTimeMonthToMonth := []testQueryResult{
 		{
 			args:    []interface{}{int64(-2)},
-			results: [][]interface{}{{int64(-2)}},
+			results: [][]interface{}{{YearMonth{Months: -2}}},
 		},
 		{
 			args:    []interface{}{int64(-1)},
-			results: [][]interface{}{{int64(-1)}},
+			results: [][]interface{}{{YearMonth{Months: -1}}},
 		},
 		{
 			args:    []interface{}{int64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{YearMonth{}}},
 		},
 		{
 			args:    []interface{}{int64(1)},
-			results: [][]interface{}{{int64(1)}},
+			results: [][]interface{}{{YearMonth{Months: 1}}},
 		},
 		{
 			args:    []interface{}{int64(2)},
-			results: [][]interface{}{{int64(2)}},
+			results: [][]interface{}{{YearMonth{Months: 2}}},
 		},
 		{
 			args:    []interface{}{float64(-2.75)},
-			results: [][]interface{}{{int64(-3)}},
+			results: [][]interface{}{{YearMonth{Months: -3}}},
 		},
 		{
 			args:    []interface{}{float64(-1.25)},
-			results: [][]interface{}{{int64(-1)}},
+			results: [][]interface{}{{YearMonth{Months: -1}}},
 		},
 		{
 			args:    []interface{}{float64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{YearMonth{}}},
 		},
 		{
 			args:    []interface{}{float64(1.25)},
-			results: [][]interface{}{{int64(1)}},
+			results: [][]interface{}{{YearMonth{Months: 1}}},
 		},
 		{
 			args:    []interface{}{float64(2.75)},
-			results: [][]interface{}{{int64(3)}},
+			results: [][]interface{}{{YearMonth{Months: 3}}},
 		},
 	}
 
@@ -188,43 +188,43 @@ func TestSelectDualTime(t *testing.T) {
 	queryResultTimeDayToSecond := []testQueryResult{
 		{
 			args:    []interface{}{int64(-2)},
-			results: [][]interface{}{{int64(-172800000000000)}},
+			results: [][]interface{}{{time.Duration(-172800000000000)}},
 		},
 		{
 			args:    []interface{}{int64(-1)},
-			results: [][]interface{}{{int64(-86400000000000)}},
+			results: [][]interface{}{{time.Duration(-86400000000000)}},
 		},
 		{
 			args:    []interface{}{int64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{time.Duration(0)}},
 		},
 		{
 			args:    []interface{}{int64(1)},
-			results: [][]interface{}{{int64(86400000000000)}},
+			results: [][]interface{}{{time.Duration(86400000000000)}},
 		},
 		{
 			args:    []interface{}{int64(2)},
-			results: [][]interface{}{{int64(172800000000000)}},
+			results: [][]interface{}{{time.Duration(172800000000000)}},
 		},
 		{
 			args:    []interface{}{float64(-2.5)},
-			results: [][]interface{}{{int64(-216000000000000)}},
+			results: [][]interface{}{{time.Duration(-216000000000000)}},
 		},
 		{
 			args:    []interface{}{float64(-1.25)},
-			results: [][]interface{}{{int64(-108000000000000)}},
+			results: [][]interface{}{{time.Duration(-108000000000000)}},
 		},
 		{
 			args:    []interface{}{float64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{time.Duration(0)}},
 		},
 		{
 			args:    []interface{}{float64(1.25)},
-			results: [][]interface{}{{int64(108000000000000)}},
+			results: [][]interface{}{{time.Duration(108000000000000)}},
 		},
 		{
 			args:    []interface{}{float64(2.5)},
-			results: [][]interface{}{{int64(216000000000000)}},
+			results: [][]interface{}{{time.Duration(216000000000000)}},
 		},
 	}
 
@@ -236,43 +236,43 @@ func TestSelectDualTime(t *testing.T) {
 	queryResultTimeHourToSecond := []testQueryResult{
 		{
 			args:    []interface{}{int64(-2)},
-			results: [][]interface{}{{int64(-7200000000000)}},
+			results: [][]interface{}{{time.Duration(-7200000000000)}},
 		},
 		{
 			args:    []interface{}{int64(-1)},
-			results: [][]interface{}{{int64(-3600000000000)}},
+			results: [][]interface{}{{time.Duration(-3600000000000)}},
 		},
 		{
 			args:    []interface{}{int64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{time.Duration(0)}},
 		},
 		{
 			args:    []interface{}{int64(1)},
-			results: [][]interface{}{{int64(3600000000000)}},
+			results: [][]interface{}{{time.Duration(3600000000000)}},
 		},
 		{
 			args:    []interface{}{int64(2)},
-			results: [][]interface{}{{int64(7200000000000)}},
+			results: [][]interface{}{{time.Duration(7200000000000)}},
 		},
 		{
 			args:    []interface{}{float64(-2.5)},
-			results: [][]interface{}{{int64(-9000000000000)}},
+			results: [][]interface{}{{time.Duration(-9000000000000)}},
 		},
 		{
 			args:    []interface{}{float64(-1.25)},
-			results: [][]interface{}{{int64(-4500000000000)}},
+			results: [][]interface{}{{time.Duration(-4500000000000)}},
 		},
 		{
 			args:    []interface{}{float64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{time.Duration(0)}},
 		},
 		{
 			args:    []interface{}{float64(1.25)},
-			results: [][]interface{}{{int64(4500000000000)}},
+			results: [][]interface{}{{time.Duration(4500000000000)}},
 		},
 		{
 			args:    []interface{}{float64(2.5)},
-			results: [][]interface{}{{int64(9000000000000)}},
+			results: [][]interface{}{{time.Duration(9000000000000)}},
 		},
 	}
 
@@ -284,43 +284,43 @@ func TestSelectDualTime(t *testing.T) {
 	queryResultTimeMinuteToSecond := []testQueryResult{
 		{
 			args:    []interface{}{int64(-2)},
-			results: [][]interface{}{{int64(-120000000000)}},
+			results: [][]interface{}{{time.Duration(-120000000000)}},
 		},
 		{
 			args:    []interface{}{int64(-1)},
-			results: [][]interface{}{{int64(-60000000000)}},
+			results: [][]interface{}{{time.Duration(-60000000000)}},
 		},
 		{
 			args:    []interface{}{int64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{time.Duration(0)}},
 		},
 		{
 			args:    []interface{}{int64(1)},
-			results: [][]interface{}{{int64(60000000000)}},
+			results: [][]interface{}{{time.Duration(60000000000)}},
 		},
 		{
 			args:    []interface{}{int64(2)},
-			results: [][]interface{}{{int64(120000000000)}},
+			results: [][]interface{}{{time.Duration(120000000000)}},
 		},
 		{
 			args:    []interface{}{float64(-2.5)},
-			results: [][]interface{}{{int64(-150000000000)}},
+			results: [][]interface{}{{time.Duration(-150000000000)}},
 		},
 		{
 			args:    []interface{}{float64(-1.25)},
-			results: [][]interface{}{{int64(-75000000000)}},
+			results: [][]interface{}{{time.Duration(-75000000000)}},
 		},
 		{
 			args:    []interface{}{float64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{time.Duration(0)}},
 		},
 		{
 			args:    []interface{}{float64(1.25)},
-			results: [][]interface{}{{int64(75000000000)}},
+			results: [][]interface{}{{time.Duration(75000000000)}},
 		},
 		{
 			args:    []interface{}{float64(2.5)},
-			results: [][]interface{}{{int64(150000000000)}},
+			results: [][]interface{}{{time.Duration(150000000000)}},
 		},
 	}
 
@@ -332,43 +332,43 @@ func TestSelectDualTime(t *testing.T) {
 	queryResultTimeSecondToSecond := []testQueryResult{
 		{
 			args:    []interface{}{int64(-2)},
-			results: [][]interface{}{{int64(-2000000000)}},
+			results: [][]interface{}{{time.Duration(-2000000000)}},
 		},
 		{
 			args:    []interface{}{int64(-1)},
-			results: [][]interface{}{{int64(-1000000000)}},
+			results: [][]interface{}{{time.Duration(-1000000000)}},
 		},
 		{
 			args:    []interface{}{int64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{time.Duration(0)}},
 		},
 		{
 			args:    []interface{}{int64(1)},
-			results: [][]interface{}{{int64(1000000000)}},
+			results: [][]interface{}{{time.Duration(1000000000)}},
 		},
 		{
 			args:    []interface{}{int64(2)},
-			results: [][]interface{}{{int64(2000000000)}},
+			results: [][]interface{}{{time.Duration(2000000000)}},
 		},
 		{
 			args:    []interface{}{float64(-2.5)},
-			results: [][]interface{}{{int64(-2500000000)}},
+			results: [][]interface{}{{time.Duration(-2500000000)}},
 		},
 		{
 			args:    []interface{}{float64(-1.25)},
-			results: [][]interface{}{{int64(-1250000000)}},
+			results: [][]interface{}{{time.Duration(-1250000000)}},
 		},
 		{
 			args:    []interface{}{float64(0)},
-			results: [][]interface{}{{int64(0)}},
+			results: [][]interface{}{{time.Duration(0)}},
 		},
 		{
 			args:    []interface{}{float64(1.25)},
-			results: [][]interface{}{{int64(1250000000)}},
+			results: [][]interface{}{{time.Duration(1250000000)}},
 		},
 		{
 			args:    []interface{}{float64(2.5)},
-			results: [][]interface{}{{int64(2500000000)}},
+			results: [][]interface{}{{time.Duration(2500000000)}},
 		},
 	}
 
@@ -544,13 +544,13 @@ func TestDestructiveTime(t *testing.T) {
 		queryResults: []testQueryResult{
 			{
 				results: [][]interface{}{
-					{int64(1), int64(-24), int64(-2)},
-					{int64(2), int64(-12), int64(-1)},
-					{int64(3), int64(12), int64(1)},
-					{int64(4), int64(24), int64(2)},
-					{int64(5), int64(15), int64(2)},
-					{int64(6), int64(18), int64(3)},
-					{int64(7), int64(33), int64(3)},
+					{int64(1), YearMonth{Years: -2}, YearMonth{Months: -2}},
+					{int64(2), YearMonth{Years: -1}, YearMonth{Months: -1}},
+					{int64(3), YearMonth{Years: 1}, YearMonth{Months: 1}},
+					{int64(4), YearMonth{Years: 2}, YearMonth{Months: 2}},
+					{int64(5), YearMonth{Years: 1, Months: 3}, YearMonth{Months: 2}},
+					{int64(6), YearMonth{Years: 1, Months: 6}, YearMonth{Months: 3}},
+					{int64(7), YearMonth{Years: 2, Months: 9}, YearMonth{Months: 3}},
 				},
 			},
 		},
@@ -572,10 +572,10 @@ func TestDestructiveTime(t *testing.T) {
 		queryResults: []testQueryResult{
 			{
 				results: [][]interface{}{
-					{int64(1), int64(-24), int64(-2)},
-					{int64(2), int64(-12), int64(-1)},
-					{int64(3), int64(12), int64(1)},
-					{int64(4), int64(24), int64(2)},
+					{int64(1), YearMonth{Years: -2}, YearMonth{Months: -2}},
+					{int64(2), YearMonth{Years: -1}, YearMonth{Months: -1}},
+					{int64(3), YearMonth{Years: 1}, YearMonth{Months: 1}},
+					{int64(4), YearMonth{Years: 2}, YearMonth{Months: 2}},
 				},
 			},
 		},
@@ -611,13 +611,13 @@ func TestDestructiveTime(t *testing.T) {
 		queryResults: []testQueryResult{
 			{
 				results: [][]interface{}{
-					{int64(1), int64(-172800000000000), int64(-7200000000000)},
-					{int64(2), int64(-86400000000000), int64(-3600000000000)},
-					{int64(3), int64(86400000000000), int64(3600000000000)},
-					{int64(4), int64(172800000000000), int64(7200000000000)},
-					{int64(5), int64(108000000000000), int64(4500000000000)},
-					{int64(6), int64(129600000000000), int64(5400000000000)},
-					{int64(7), int64(237600000000000), int64(9900000000000)},
+					{int64(1), time.Duration(-172800000000000), time.Duration(-7200000000000)},
+					{int64(2), time.Duration(-86400000000000), time.Duration(-3600000000000)},
+					{int64(3), time.Duration(86400000000000), time.Duration(3600000000000)},
+					{int64(4), time.Duration(172800000000000), time.Duration(7200000000000)},
+					{int64(5), time.Duration(108000000000000), time.Duration(4500000000000)},
+					{int64(6), time.Duration(129600000000000), time.Duration(5400000000000)},
+					{int64(7), time.Duration(237600000000000), time.Duration(9900000000000)},
 				},
 			},
 		},
@@ -639,10 +639,10 @@ func TestDestructiveTime(t *testing.T) {
 		queryResults: []testQueryResult{
 			{
 				results: [][]interface{}{
-					{int64(1), int64(-172800000000000), int64(-7200000000000)},
-					{int64(2), int64(-86400000000000), int64(-3600000000000)},
-					{int64(3), int64(86400000000000), int64(3600000000000)},
-					{int64(4), int64(172800000000000), int64(7200000000000)},
+					{int64(1), time.Duration(-172800000000000), time.Duration(-7200000000000)},
+					{int64(2), time.Duration(-86400000000000), time.Duration(-3600000000000)},
+					{int64(3), time.Duration(86400000000000), time.Duration(3600000000000)},
+					{int64(4), time.Duration(172800000000000), time.Duration(7200000000000)},
 				},
 			},
 		},
@@ -673,13 +673,13 @@ func TestDestructiveTime(t *testing.T) {
 		queryResults: []testQueryResult{
 			{
 				results: [][]interface{}{
-					{int64(1), int64(-120000000000), int64(-2000000000)},
-					{int64(2), int64(-60000000000), int64(-1000000000)},
-					{int64(3), int64(60000000000), int64(1000000000)},
-					{int64(4), int64(120000000000), int64(2000000000)},
-					{int64(5), int64(75000000000), int64(1250000000)},
-					{int64(6), int64(90000000000), int64(1500000000)},
-					{int64(7), int64(165000000000), int64(2750000000)},
+					{int64(1), time.Duration(-120000000000), time.Duration(-2000000000)},
+					{int64(2), time.Duration(-60000000000), time.Duration(-1000000000)},
+					{int64(3), time.Duration(60000000000), time.Duration(1000000000)},
+					{int64(4), time.Duration(120000000000), time.Duration(2000000000)},
+					{int64(5), time.Duration(75000000000), time.Duration(1250000000)},
+					{int64(6), time.Duration(90000000000), time.Duration(1500000000)},
+					{int64(7), time.Duration(165000000000), time.Duration(2750000000)},
 				},
 			},
 		},
@@ -701,10 +701,10 @@ func TestDestructiveTime(t *testing.T) {
 		queryResults: []testQueryResult{
 			{
 				results: [][]interface{}{
-					{int64(1), int64(-120000000000), int64(-2000000000)},
-					{int64(2), int64(-60000000000), int64(-1000000000)},
-					{int64(3), int64(60000000000), int64(1000000000)},
-					{int64(4), int64(120000000000), int64(2000000000)},
+					{int64(1), time.Duration(-120000000000), time.Duration(-2000000000)},
+					{int64(2), time.Duration(-60000000000), time.Duration(-1000000000)},
+					{int64(3), time.Duration(60000000000), time.Duration(1000000000)},
+					{int64(4), time.Duration(120000000000), time.Duration(2000000000)},
 				},
 			},
 		},
@@ -792,15 +792,15 @@ func TestDestructiveTimeColumnTypes(t *testing.T) {
 
 	columnNum := 0
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_TIMESTAMP" {
+	if columnTypes[columnNum].DatabaseTypeName() != "TIMESTAMP" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok := columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != 0 {
 		t.Error("Length does not match -", length)
 	}
-	if ok != true {
+	if ok != false {
 		t.Error("Length ok does not match -", ok)
 	}
 
@@ -816,15 +816,15 @@ func TestDestructiveTimeColumnTypes(t *testing.T) {
 
 	columnNum = 1
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_TIMESTAMP_TZ" {
+	if columnTypes[columnNum].DatabaseTypeName() != "TIMESTAMP WITH TIME ZONE" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok = columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != 0 {
 		t.Error("Length does not match -", length)
 	}
-	if ok != true {
+	if ok != false {
 		t.Error("Length ok does not match -", ok)
 	}
 
@@ -840,15 +840,15 @@ func TestDestructiveTimeColumnTypes(t *testing.T) {
 
 	columnNum = 2
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_TIMESTAMP_TZ" {
+	if columnTypes[columnNum].DatabaseTypeName() != "TIMESTAMP WITH LOCAL TIME ZONE" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok = columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != 0 {
 		t.Error("Length does not match -", length)
 	}
-	if ok != true {
+	if ok != false {
 		t.Error("Length ok does not match -", ok)
 	}
 
@@ -864,15 +864,15 @@ func TestDestructiveTimeColumnTypes(t *testing.T) {
 
 	columnNum = 3
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_INTERVAL_YM" {
+	if columnTypes[columnNum].DatabaseTypeName() != "INTERVAL YEAR TO MONTH" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok = columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != 0 {
 		t.Error("Length does not match -", length)
 	}
-	if ok != true {
+	if ok != false {
 		t.Error("Length ok does not match -", ok)
 	}
 
@@ -880,7 +880,7 @@ func TestDestructiveTimeColumnTypes(t *testing.T) {
 		t.Error("Name does not match -", columnTypes[columnNum].Name())
 	}
 
-	if columnTypes[columnNum].ScanType() != typeInt64 {
+	if columnTypes[columnNum].ScanType() != typeYearMonth {
 		t.Error("ScanType does not match -", columnTypes[columnNum].ScanType())
 	}
 
@@ -888,15 +888,15 @@ func TestDestructiveTimeColumnTypes(t *testing.T) {
 
 	columnNum = 4
 
-	if columnTypes[columnNum].DatabaseTypeName() != "SQLT_INTERVAL_DS" {
+	if columnTypes[columnNum].DatabaseTypeName() != "INTERVAL DAY TO SECOND" {
 		t.Error("DatabaseTypeName does not match -", columnTypes[columnNum].DatabaseTypeName())
 	}
 
 	length, ok = columnTypes[columnNum].Length()
-	if length != 8 {
+	if length != 0 {
 		t.Error("Length does not match -", length)
 	}
-	if ok != true {
+	if ok != false {
 		t.Error("Length ok does not match -", ok)
 	}
 
@@ -904,7 +904,7 @@ func TestDestructiveTimeColumnTypes(t *testing.T) {
 		t.Error("Name does not match -", columnTypes[columnNum].Name())
 	}
 
-	if columnTypes[columnNum].ScanType() != typeInt64 {
+	if columnTypes[columnNum].ScanType() != typeDuration {
 		t.Error("ScanType does not match -", columnTypes[columnNum].ScanType())
 	}
 
@@ -0,0 +1,35 @@
+package oracle
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+type (
+	// Converter converts result values and bool binds, so each sql.DB can have its own type mapping.
+	// Set it on the oci8 Connector or DriverStruct, it is copied to connections when they are opened.
+	// Embed oci8.DefaultConverter to only change some of the policies.
+	// Null values are not passed to the converter.
+	Converter interface {
+		// ConvertNumber converts NUMBER, INTEGER, BINARY_FLOAT, and BINARY_DOUBLE values, which are int64 or float64,
+		// or a decimal string for NUMBER columns with number strings
+		ConvertNumber(column ConverterColumn, value driver.Value) (driver.Value, error)
+		// ConvertTime converts DATE and TIMESTAMP values
+		ConvertTime(column ConverterColumn, value time.Time) (driver.Value, error)
+		// ConvertLob converts CLOB values, which are string, and BLOB values, which are []byte
+		ConvertLob(column ConverterColumn, value driver.Value) (driver.Value, error)
+		// ConvertBool converts bool bind values, Oracle SQL has no bool type.
+		// The bool returned is bound as a 1 or 0 number.
+		ConvertBool(value bool) (driver.Value, error)
+	}
+
+	// ConverterColumn is the result column of a value passed to a Converter
+	ConverterColumn struct {
+		// Index is the zero based index of the column
+		Index int
+		// Name is the column name
+		Name string
+		// DatabaseTypeName is the Oracle type name of the column, like VARCHAR2 or NUMBER, same as ColumnTypeDatabaseTypeName
+		DatabaseTypeName string
+	}
+)
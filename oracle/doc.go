@@ -0,0 +1,9 @@
+/*
+Package oracle has the parts of the oci8 driver that do not need cgo or the Oracle client:
+the DSN parsing, the tnsnames.ora net service name resolution, the value types like YearMonth, the Converter interface,
+and the errors returned before calling OCI.
+
+The oci8 package uses them and has aliases of the types, so oci8.YearMonth and oracle.YearMonth are the same type.
+Packages that can not use cgo, like fakedb, import this package instead of oci8.
+*/
+package oracle
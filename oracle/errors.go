@@ -0,0 +1,43 @@
+package oracle
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrTooManyRows is returned when fetching a row past the max rows limit of a query
+var ErrTooManyRows = errors.New("query returned more rows than the max rows limit")
+
+// BindError is returned when a bind parameter can not be bound to a statement
+type BindError struct {
+	// Index is the zero based index of the offending bind parameter
+	Index int
+	// Name is the name of the offending bind parameter, empty for positional parameters
+	Name string
+	// Err is the underlying error
+	Err error
+}
+
+// Error returns the bind error string
+func (bindError *BindError) Error() string {
+	if bindError.Name != "" {
+		return "bind parameter " + strconv.Itoa(bindError.Index) + " (" + bindError.Name + "): " + bindError.Err.Error()
+	}
+	return "bind parameter " + strconv.Itoa(bindError.Index) + ": " + bindError.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (bindError *BindError) Unwrap() error {
+	return bindError.Err
+}
+
+// ReadOnlyError is returned when preparing a statement that changes data or the schema on a read only connection
+type ReadOnlyError struct {
+	// StatementType is the type of the refused statement, like INSERT or CREATE
+	StatementType string
+}
+
+// Error returns the read only error string
+func (readOnlyError *ReadOnlyError) Error() string {
+	return readOnlyError.StatementType + " statements are not allowed on a read only connection"
+}
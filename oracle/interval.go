@@ -0,0 +1,57 @@
+package oracle
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// YearMonth is an INTERVAL YEAR TO MONTH value. Both fields have the sign of the interval,
+// like YearMonth{Years: -1, Months: -6} for -1-6. Bind a YearMonth to bind an INTERVAL YEAR TO MONTH value.
+type YearMonth struct {
+	Years  int
+	Months int
+}
+
+// NewYearMonth returns the YearMonth of a number of months, like 18 for YearMonth{Years: 1, Months: 6}
+func NewYearMonth(months int64) YearMonth {
+	return YearMonth{Years: int(months / 12), Months: int(months % 12)}
+}
+
+// TotalMonths returns the number of months of the interval
+func (yearMonth YearMonth) TotalMonths() int64 {
+	return int64(yearMonth.Years)*12 + int64(yearMonth.Months)
+}
+
+// String returns the interval as an Oracle interval literal, like +01-06 or -01-06
+func (yearMonth YearMonth) String() string {
+	months := yearMonth.TotalMonths()
+	sign := "+"
+	if months < 0 {
+		sign = "-"
+		months = -months
+	}
+	text := sign
+	if months/12 < 10 {
+		text += "0"
+	}
+	text += strconv.FormatInt(months/12, 10) + "-"
+	if months%12 < 10 {
+		text += "0"
+	}
+	return text + strconv.FormatInt(months%12, 10)
+}
+
+// Scan implements sql.Scanner, from a YearMonth or the int64 months of the interval_int64 parameter
+func (yearMonth *YearMonth) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case YearMonth:
+		*yearMonth = src
+	case int64:
+		*yearMonth = NewYearMonth(src)
+	case nil:
+		*yearMonth = YearMonth{}
+	default:
+		return fmt.Errorf("unsupported type %T for YearMonth", src)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package oracle
+
+import (
+	"testing"
+)
+
+// TestParseDSN tests parsing the DSN parameters into the fields of DSN
+func TestParseDSN(t *testing.T) {
+	t.Parallel()
+
+	dsn, err := ParseDSN("oracle://scott/tiger@dbhost:1521/ORCLPDB1?loc=America%2FNew_York&isolation=SERIALIZABLE&as=sysdba" +
+		"&prefetch_rows=10&temp_tablespace=temp_lobs&call_timeout=false&float_precision=shortest&ddl_in_tx=warn&max_rows=5&read_only=true&tns_admin=%2Fetc%2Foracle")
+	if err != nil {
+		t.Fatal("ParseDSN error:", err)
+	}
+	expected := NewDSN()
+	expected.Connect = "dbhost:1521/ORCLPDB1"
+	expected.Username = "scott"
+	expected.Password = "tiger"
+	// time.LoadLocation returns a new location each time
+	if dsn.TimeLocation.String() != "America/New_York" {
+		t.Errorf("loc - expected: %v - received: %v", "America/New_York", dsn.TimeLocation)
+	}
+	expected.TimeLocation = dsn.TimeLocation
+	expected.Isolation = "SERIALIZABLE"
+	expected.As = "SYSDBA"
+	expected.PrefetchRows = 10
+	expected.PrefetchSet = true
+	expected.TempTablespace = "temp_lobs"
+	expected.CallTimeout = false
+	expected.FloatDecimal = true
+	expected.FloatPrecision = -1
+	expected.DDLInTx = "WARN"
+	expected.MaxRows = 5
+	expected.ReadOnly = true
+	expected.TNSAdmin = "/etc/oracle"
+	if *dsn != *expected {
+		t.Errorf("ParseDSN - expected: %+v - received: %+v", expected, dsn)
+	}
+
+	dsn, err = ParseDSN("scott/tiger@dbhost?pool_max=4")
+	if err != nil {
+		t.Fatal("ParseDSN error:", err)
+	}
+	if dsn.PoolMax != 4 || dsn.PoolIncrement != 1 {
+		t.Errorf("pool - expected: %v %v - received: %v %v", 4, 1, dsn.PoolMax, dsn.PoolIncrement)
+	}
+
+	for _, dsnString := range []string{
+		"",
+		"scott/tiger@dbhost?isolation=x",
+		"scott/tiger@dbhost?prefetch_rows=-1",
+		"scott/tiger@dbhost?time_zone=UTC'",
+		"scott/tiger@dbhost?pool_max=1&pool_min=2",
+		"scott/tiger@dbhost?pool_max=1&as=SYSDBA",
+	} {
+		_, err = ParseDSN(dsnString)
+		if err == nil {
+			t.Errorf("ParseDSN %q - expected: error - received: nil", dsnString)
+		}
+	}
+}
+
+// TestValidTimeZone tests the time_zone values that can be set with ALTER SESSION
+func TestValidTimeZone(t *testing.T) {
+	t.Parallel()
+
+	var validTests = []struct {
+		timeZone string
+		valid    bool
+	}{
+		{"UTC", true},
+		{"+02:00", true},
+		{"America/Argentina/Buenos_Aires", true},
+		{"", false},
+		{"UTC' scope=spfile", false},
+	}
+	for _, tt := range validTests {
+		valid := ValidTimeZone(tt.timeZone)
+		if valid != tt.valid {
+			t.Errorf("ValidTimeZone(%q) - expected: %v - received: %v", tt.timeZone, tt.valid, valid)
+		}
+	}
+}
+
+// TestYearMonth tests the months and text of YearMonth
+func TestYearMonth(t *testing.T) {
+	t.Parallel()
+
+	yearMonth := NewYearMonth(-18)
+	if yearMonth != (YearMonth{Years: -1, Months: -6}) || yearMonth.TotalMonths() != -18 || yearMonth.String() != "-01-06" {
+		t.Errorf("NewYearMonth(-18) - received: %+v %v", yearMonth, yearMonth)
+	}
+
+	err := yearMonth.Scan(int64(30))
+	if err != nil || yearMonth.String() != "+02-06" {
+		t.Errorf("Scan(30) - received: %v %v", yearMonth, err)
+	}
+	err = yearMonth.Scan("x")
+	if err == nil {
+		t.Error("Scan(string) - expected: error - received: nil")
+	}
+}
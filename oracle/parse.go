@@ -0,0 +1,390 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DSN are the parameters of an oci8 DSN, see oci8.ParseDSN for the format and the parameters.
+// Fields of parameters that are not set have the defaults of NewDSN.
+type DSN struct {
+	// Connect is the connect string
+	Connect string
+	// Username is the user name, empty for external credentials
+	Username string
+	// Password is the password
+	Password string
+
+	// TimeLocation is the loc parameter, defaults to UTC
+	TimeLocation *time.Location
+	// Isolation is the isolation parameter: READONLY, SERIALIZABLE, DEFAULT, or empty when not set
+	Isolation string
+	// As is the as parameter in upper case: SYSDBA, SYSASM, SYSOPER, or empty for a normal session
+	As string
+	// QuestionPlaceholders is the questionph parameter
+	QuestionPlaceholders bool
+	// PrefetchRows is the prefetch_rows parameter, defaults to 0
+	PrefetchRows uint32
+	// PrefetchMemory is the prefetch_memory parameter, defaults to 4096
+	PrefetchMemory uint32
+	// PrefetchSet is true when prefetch_rows or prefetch_memory is set
+	PrefetchSet bool
+	// PrefetchTarget is the prefetch_target parameter
+	PrefetchTarget int64
+	// PrefetchTargetSet is true when prefetch_target is set
+	PrefetchTargetSet bool
+	// StmtCacheSize is the stmt_cache_size parameter
+	StmtCacheSize uint32
+	// TempLobCache is the temp_lob_cache parameter, defaults to true
+	TempLobCache bool
+	// TempTablespace is the temp_tablespace parameter, the temporary tablespace of the user set on connect
+	TempTablespace string
+	// LobPrefetchSize is the lob_prefetch_size parameter
+	LobPrefetchSize uint32
+	// FetchArraySize is the fetch_array_size parameter
+	FetchArraySize uint32
+	// CallTimeout is the call_timeout parameter, defaults to true
+	CallTimeout bool
+	// FloatDecimal is true when float_precision is SHORTEST or a number of decimal places
+	FloatDecimal bool
+	// FloatPrecision is the number of decimal places of float_precision, -1 for SHORTEST
+	FloatPrecision int
+	// DateRangeClamp is true when date_range is CLAMP
+	DateRangeClamp bool
+	// NumberStrings is the number_strings parameter
+	NumberStrings bool
+	// IntervalInt64 is the interval_int64 parameter
+	IntervalInt64 bool
+	// IdentityReturning is the identity_returning parameter
+	IdentityReturning bool
+	// Charset is the charset parameter
+	Charset string
+	// UTF16 is the utf16 parameter
+	UTF16 bool
+	// IgnoreEnv is the ignore_env parameter
+	IgnoreEnv bool
+	// TNSAdmin is the tns_admin parameter, the directory of the tnsnames.ora net service names are resolved with
+	TNSAdmin string
+	// OracleHome is the oracle_home parameter, net service names are resolved with its network/admin/tnsnames.ora
+	OracleHome string
+	// NLSLanguage is the nls_language parameter
+	NLSLanguage string
+	// NLSTerritory is the nls_territory parameter
+	NLSTerritory string
+	// TimeZone is the time_zone parameter
+	TimeZone string
+	// LTZLocation is the ltz_loc parameter, nil when not set
+	LTZLocation *time.Location
+	// MaxRows is the max_rows parameter
+	MaxRows int64
+	// CloseTimeout is the close_timeout parameter
+	CloseTimeout time.Duration
+	// DebugConcurrentUse is the debug_concurrent_use parameter
+	DebugConcurrentUse bool
+	// ReadOnly is the read_only parameter
+	ReadOnly bool
+	// DDLInTx is the ddl_in_tx parameter in upper case: ALLOW, WARN, or ERROR, defaults to ALLOW
+	DDLInTx string
+	// GroupCommitCount is the group_commit_count parameter
+	GroupCommitCount int
+	// GroupCommitInterval is the group_commit_interval parameter
+	GroupCommitInterval time.Duration
+	// PoolMin is the pool_min parameter
+	PoolMin uint32
+	// PoolMax is the pool_max parameter
+	PoolMax uint32
+	// PoolIncrement is the pool_increment parameter, defaults to 1 with a pool_max
+	PoolIncrement uint32
+}
+
+// NewDSN returns a DSN with the default parameters
+func NewDSN() *DSN {
+	return &DSN{
+		TimeLocation:   time.UTC,
+		PrefetchMemory: 4096,
+		TempLobCache:   true,
+		CallTimeout:    true,
+		DDLInTx:        "ALLOW",
+	}
+}
+
+// ParseDSN parses a DSN, see oci8.ParseDSN for the format and the parameters
+func ParseDSN(dsnString string) (dsn *DSN, err error) {
+
+	if dsnString == "" {
+		return nil, errors.New("empty dsn")
+	}
+
+	const prefix = "oracle://"
+
+	if strings.HasPrefix(dsnString, prefix) {
+		dsnString = dsnString[len(prefix):]
+	}
+
+	dsn = NewDSN()
+
+	authority, dsnString := splitRight(dsnString, "@")
+	if authority != "" {
+		dsn.Username, dsn.Password, err = parseAuthority(authority)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	host, params := splitRight(dsnString, "?")
+
+	if host, err = unescape(host, encodeHost); err != nil {
+		return nil, err
+	}
+
+	dsn.Connect = host
+
+	qp, err := ParseQuery(params)
+	for k, v := range qp {
+		switch k {
+		case "loc":
+			if len(v) > 0 {
+				if dsn.TimeLocation, err = time.LoadLocation(v[0]); err != nil {
+					return nil, fmt.Errorf("Invalid loc: %v: %v", v[0], err)
+				}
+			}
+		case "isolation":
+			switch v[0] {
+			case "READONLY", "SERIALIZABLE", "DEFAULT":
+				dsn.Isolation = v[0]
+			default:
+				return nil, fmt.Errorf("Invalid isolation: %v", v[0])
+			}
+		case "questionph":
+			dsn.QuestionPlaceholders, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid questionph: %v", v[0])
+			}
+		case "prefetch_rows":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid prefetch_rows: %v", v[0])
+			}
+			dsn.PrefetchRows = uint32(z)
+			dsn.PrefetchSet = true
+		case "prefetch_memory":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid prefetch_memory: %v", v[0])
+			}
+			dsn.PrefetchMemory = uint32(z)
+			dsn.PrefetchSet = true
+		case "prefetch_target":
+			dsn.PrefetchTarget, err = strconv.ParseInt(v[0], 10, 64)
+			if err != nil || dsn.PrefetchTarget < 0 {
+				return nil, fmt.Errorf("invalid prefetch_target: %v", v[0])
+			}
+			dsn.PrefetchTargetSet = true
+		case "as":
+			switch v[0] {
+			case "SYSDBA", "sysdba", "SYSASM", "sysasm", "SYSOPER", "sysoper":
+				dsn.As = strings.ToUpper(v[0])
+			default:
+				return nil, fmt.Errorf("Invalid as: %v", v[0])
+			}
+		case "lob_prefetch_size", "lobPrefetchSize":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid lob_prefetch_size: %v", v[0])
+			}
+			dsn.LobPrefetchSize = uint32(z)
+		case "call_timeout":
+			dsn.CallTimeout, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid call_timeout: %v", v[0])
+			}
+		case "fetch_array_size", "fetchArraySize":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fetch_array_size: %v", v[0])
+			}
+			dsn.FetchArraySize = uint32(z)
+		case "stmt_cache_size", "stmtCacheSize":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stmt_cache_size: %v", v[0])
+			}
+			dsn.StmtCacheSize = uint32(z)
+		case "temp_lob_cache":
+			dsn.TempLobCache, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid temp_lob_cache: %v", v[0])
+			}
+		case "temp_tablespace":
+			dsn.TempTablespace = v[0]
+		case "float_precision":
+			switch v[0] {
+			case "BINARY", "binary":
+				dsn.FloatDecimal = false
+				dsn.FloatPrecision = 0
+			case "SHORTEST", "shortest":
+				dsn.FloatDecimal = true
+				dsn.FloatPrecision = -1
+			default:
+				z, err := strconv.ParseUint(v[0], 10, 8)
+				if err != nil {
+					return nil, fmt.Errorf("invalid float_precision: %v", v[0])
+				}
+				dsn.FloatDecimal = true
+				dsn.FloatPrecision = int(z)
+			}
+		case "date_range":
+			switch v[0] {
+			case "ERROR", "error":
+				dsn.DateRangeClamp = false
+			case "CLAMP", "clamp":
+				dsn.DateRangeClamp = true
+			default:
+				return nil, fmt.Errorf("invalid date_range: %v", v[0])
+			}
+		case "number_strings":
+			dsn.NumberStrings, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid number_strings: %v", v[0])
+			}
+		case "interval_int64":
+			dsn.IntervalInt64, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid interval_int64: %v", v[0])
+			}
+		case "identity_returning":
+			dsn.IdentityReturning, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid identity_returning: %v", v[0])
+			}
+		case "charset":
+			dsn.Charset = v[0]
+		case "utf16":
+			dsn.UTF16, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid utf16: %v", v[0])
+			}
+		case "ignore_env":
+			dsn.IgnoreEnv, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid ignore_env: %v", v[0])
+			}
+		case "tns_admin":
+			dsn.TNSAdmin = v[0]
+		case "oracle_home":
+			dsn.OracleHome = v[0]
+		case "nls_language":
+			dsn.NLSLanguage = v[0]
+		case "nls_territory":
+			dsn.NLSTerritory = v[0]
+		case "time_zone":
+			if !ValidTimeZone(v[0]) {
+				return nil, fmt.Errorf("invalid time_zone: %v", v[0])
+			}
+			dsn.TimeZone = v[0]
+		case "ltz_loc":
+			if dsn.LTZLocation, err = time.LoadLocation(v[0]); err != nil {
+				return nil, fmt.Errorf("invalid ltz_loc: %v: %v", v[0], err)
+			}
+		case "max_rows":
+			dsn.MaxRows, err = strconv.ParseInt(v[0], 10, 64)
+			if err != nil || dsn.MaxRows < 0 {
+				return nil, fmt.Errorf("invalid max_rows: %v", v[0])
+			}
+		case "close_timeout":
+			dsn.CloseTimeout, err = time.ParseDuration(v[0])
+			if err != nil || dsn.CloseTimeout < 0 {
+				return nil, fmt.Errorf("invalid close_timeout: %v", v[0])
+			}
+		case "debug_concurrent_use":
+			dsn.DebugConcurrentUse, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid debug_concurrent_use: %v", v[0])
+			}
+		case "read_only":
+			dsn.ReadOnly, err = strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid read_only: %v", v[0])
+			}
+		case "ddl_in_tx":
+			switch v[0] {
+			case "ALLOW", "allow", "WARN", "warn", "ERROR", "error":
+				dsn.DDLInTx = strings.ToUpper(v[0])
+			default:
+				return nil, fmt.Errorf("invalid ddl_in_tx: %v", v[0])
+			}
+		case "group_commit_count":
+			dsn.GroupCommitCount, err = strconv.Atoi(v[0])
+			if err != nil || dsn.GroupCommitCount < 0 {
+				return nil, fmt.Errorf("invalid group_commit_count: %v", v[0])
+			}
+		case "group_commit_interval":
+			dsn.GroupCommitInterval, err = time.ParseDuration(v[0])
+			if err != nil || dsn.GroupCommitInterval < 0 {
+				return nil, fmt.Errorf("invalid group_commit_interval: %v", v[0])
+			}
+		case "pool_min":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pool_min: %v", v[0])
+			}
+			dsn.PoolMin = uint32(z)
+		case "pool_max":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pool_max: %v", v[0])
+			}
+			dsn.PoolMax = uint32(z)
+		case "pool_increment":
+			z, err := strconv.ParseUint(v[0], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pool_increment: %v", v[0])
+			}
+			dsn.PoolIncrement = uint32(z)
+		}
+	}
+
+	err = dsn.CheckSessionPool()
+	if err != nil {
+		return nil, err
+	}
+
+	return dsn, nil
+}
+
+// CheckSessionPool checks the session pool parameters and sets the default pool_increment
+func (dsn *DSN) CheckSessionPool() error {
+	if dsn.PoolMax > 0 {
+		if dsn.PoolMin > dsn.PoolMax {
+			return fmt.Errorf("invalid pool_min: %v is more than pool_max %v", dsn.PoolMin, dsn.PoolMax)
+		}
+		if dsn.As != "" {
+			return errors.New("invalid as: session pools do not support as")
+		}
+		if dsn.PoolIncrement == 0 {
+			dsn.PoolIncrement = 1
+		}
+	}
+	return nil
+}
+
+// ValidTimeZone returns true when the time zone is an offset like +02:00 or a region name like Europe/Paris,
+// the time_zone values that can be set with ALTER SESSION
+func ValidTimeZone(timeZone string) bool {
+	if timeZone == "" {
+		return false
+	}
+	for i := 0; i < len(timeZone); i++ {
+		c := timeZone[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		case c == '+', c == '-', c == ':', c == '/', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package oracle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// IsTNSAlias returns true when the connect string is a net service name, like ORCL or orcl.example.com,
+// not an easy connect string or a connect descriptor
+func IsTNSAlias(connect string) bool {
+	return connect != "" && !strings.ContainsAny(connect, "/:()@= \t\r\n")
+}
+
+// ResolveTNSAlias returns the connect descriptor of the net service name alias in the tnsnames.ora of the directory,
+// or connect unchanged when it is not an alias, see IsTNSAlias. Aliases are not case sensitive.
+// IFILE entries are not followed.
+func ResolveTNSAlias(directory string, connect string) (string, error) {
+	if !IsTNSAlias(connect) {
+		return connect, nil
+	}
+
+	path := filepath.Join(directory, "tnsnames.ora")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	descriptors, err := parseTNSNames(string(data))
+	if err != nil {
+		return "", fmt.Errorf("%v: %v", path, err)
+	}
+	descriptor, ok := descriptors[strings.ToUpper(connect)]
+	if !ok {
+		return "", fmt.Errorf("%v: alias %v not found", path, connect)
+	}
+	return descriptor, nil
+}
+
+// parseTNSNames returns the connect descriptors of the tnsnames.ora text by upper case alias.
+// An entry is a comma separated list of aliases, =, and a parenthesized descriptor, # starts a comment.
+func parseTNSNames(text string) (map[string]string, error) {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if index := strings.IndexByte(line, '#'); index >= 0 {
+			lines[i] = line[:index]
+		}
+	}
+	text = strings.Join(lines, "\n")
+
+	descriptors := make(map[string]string)
+	for {
+		equal := strings.IndexByte(text, '=')
+		if equal < 0 {
+			if strings.TrimSpace(text) != "" {
+				return nil, fmt.Errorf("entry without =: %v", strings.TrimSpace(text))
+			}
+			return descriptors, nil
+		}
+		aliases := strings.Split(text[:equal], ",")
+		text = strings.TrimLeft(text[equal+1:], " \t\r\n")
+		if !strings.HasPrefix(text, "(") {
+			return nil, fmt.Errorf("entry %v is not a parenthesized descriptor", strings.TrimSpace(aliases[0]))
+		}
+
+		depth := 0
+		end := -1
+		for i := 0; i < len(text) && end < 0; i++ {
+			switch text[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					end = i + 1
+				}
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("entry %v has unbalanced parentheses", strings.TrimSpace(aliases[0]))
+		}
+
+		// the descriptor on one line, the client accepts the whitespace between its parts
+		descriptor := strings.Join(strings.Fields(text[:end]), " ")
+		for _, alias := range aliases {
+			alias = strings.ToUpper(strings.TrimSpace(alias))
+			if alias != "" {
+				descriptors[alias] = descriptor
+			}
+		}
+		text = text[end:]
+	}
+}
@@ -0,0 +1,68 @@
+package oracle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestResolveTNSAlias tests resolving net service names with the tnsnames.ora of a directory
+func TestResolveTNSAlias(t *testing.T) {
+	t.Parallel()
+
+	directory, err := ioutil.TempDir("", "tnsnames")
+	if err != nil {
+		t.Fatal("temp dir error:", err)
+	}
+	defer os.RemoveAll(directory)
+
+	tnsNames := `# test entries
+ORCL =
+  (DESCRIPTION =
+    (ADDRESS = (PROTOCOL = TCP)(HOST = dbhost)(PORT = 1521)) # the listener
+    (CONNECT_DATA = (SERVICE_NAME = orclpdb1))
+  )
+
+reports.example.com, REPORTS=(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=reporthost)(PORT=1522))(CONNECT_DATA=(SID=REP)))
+`
+	err = ioutil.WriteFile(filepath.Join(directory, "tnsnames.ora"), []byte(tnsNames), 0600)
+	if err != nil {
+		t.Fatal("write error:", err)
+	}
+
+	var resolveTests = []struct {
+		connect  string
+		expected string
+		err      bool
+	}{
+		{connect: "orcl", expected: "(DESCRIPTION = (ADDRESS = (PROTOCOL = TCP)(HOST = dbhost)(PORT = 1521)) (CONNECT_DATA = (SERVICE_NAME = orclpdb1)) )"},
+		{connect: "Reports", expected: "(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=reporthost)(PORT=1522))(CONNECT_DATA=(SID=REP)))"},
+		{connect: "REPORTS.EXAMPLE.COM", expected: "(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=reporthost)(PORT=1522))(CONNECT_DATA=(SID=REP)))"},
+		{connect: "dbhost:1521/orclpdb1", expected: "dbhost:1521/orclpdb1"},
+		{connect: "", expected: ""},
+		{connect: "missing", err: true},
+	}
+	for _, tt := range resolveTests {
+		descriptor, err := ResolveTNSAlias(directory, tt.connect)
+		if (err != nil) != tt.err {
+			t.Errorf("ResolveTNSAlias %v - expected error: %v - received: %v", tt.connect, tt.err, err)
+			continue
+		}
+		if descriptor != tt.expected {
+			t.Errorf("ResolveTNSAlias %v - expected: %v - received: %v", tt.connect, tt.expected, descriptor)
+		}
+	}
+
+	_, err = ResolveTNSAlias(filepath.Join(directory, "missing"), "orcl")
+	if err == nil {
+		t.Errorf("ResolveTNSAlias missing directory - expected: error - received: %v", err)
+	}
+
+	for _, text := range []string{"ORCL = (DESCRIPTION = (ADDRESS = (HOST = dbhost))", "ORCL = dbhost", "ORCL"} {
+		_, err = parseTNSNames(text)
+		if err == nil {
+			t.Errorf("parseTNSNames %q - expected: error - received: %v", text, err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package oci8
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+When a statement fails to parse, the *Error returned has the Offset of the error in the statement text,
+from OCI_ATTR_PARSE_ERROR_OFFSET, and a Snippet of the text around it, so syntax errors can be found in large generated SQL:
+
+	ORA-00923: FROM keyword not found where expected at offset 16: select id, name ^orders where id = :1
+
+The offset is in bytes of the query as passed to Prepare, without the request ID comment of WithRequestID.
+With a UTF-16 environment the offset is reported as is, without a snippet.
+*/
+
+// parseErrorContext is the max number of bytes of the statement text shown before and after the offset of a parse error
+const parseErrorContext = 40
+
+// parseErrorCodes are the ORA codes of parse errors, which can be reported at offset 0
+var parseErrorCodes = map[int]struct{}{
+	1740: {}, // missing double quote in identifier
+	1756: {}, // quoted string not properly terminated
+	6550: {}, // PL/SQL compilation error
+}
+
+// isParseError returns true when the Oracle error is a parse error of the statement
+func isParseError(oracleError *Error) bool {
+	if oracleError.Offset > 0 {
+		return true
+	}
+	if oracleError.Code >= 900 && oracleError.Code <= 999 {
+		// ORA-00900 to ORA-00999, like ORA-00900: invalid SQL statement and ORA-00942: table or view does not exist
+		return true
+	}
+	_, ok := parseErrorCodes[oracleError.Code]
+	return ok
+}
+
+// parseError sets the offset of the parse error of the statement to the offset in the query text,
+// and the snippet of the query text around it. Returns err.
+func (stmt *Stmt) parseError(err error) error {
+	var oracleError *Error
+	if !errors.As(err, &oracleError) || !isParseError(oracleError) || stmt.conn.utf16 {
+		return err
+	}
+	offset := oracleError.Offset - stmt.textOffset
+	if offset < 0 || offset > len(stmt.queryText) {
+		// in the request ID comment, or in the RETURNING clause of identity_returning
+		return err
+	}
+	oracleError.Offset = offset
+	oracleError.Snippet = errorSnippet(stmt.queryText, offset)
+	return err
+}
+
+// errorSnippet returns the text around the offset, on one line with ^ marking the offset,
+// and ... where the text was cut
+func errorSnippet(text string, offset int) string {
+	start := offset - parseErrorContext
+	if start < 0 {
+		start = 0
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	end := offset + parseErrorContext
+	if end > len(text) {
+		end = len(text)
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	var builder strings.Builder
+	if start > 0 {
+		builder.WriteString("...")
+	}
+	builder.WriteString(text[start:offset])
+	builder.WriteString("^")
+	builder.WriteString(text[offset:end])
+	if end < len(text) {
+		builder.WriteString("...")
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
@@ -0,0 +1,91 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// PinnedSession is a sql.Conn pinned to its Oracle session, for work that depends on session state,
+// like global temporary table rows, package variables, and temporary LOBs.
+//
+// database/sql never moves a sql.Conn to another connection, but the session under it can still be lost,
+// in which case its state is gone. Once pinned, bad connection errors are returned as *SessionLostError,
+// which errors.Is matches with ErrSessionLost, and Check detects a session replaced by a transparent application failover.
+type PinnedSession struct {
+	conn *sql.Conn
+	// SID is the session id
+	SID string
+	// SessionID is the auditing session id, unique in the database across sessions that reuse the same SID
+	SessionID string
+	// Instance is the instance number of the session
+	Instance string
+}
+
+// pinnedSessionQuery selects the session identity, using only sys_context so no privileges are needed
+const pinnedSessionQuery = "select sys_context('USERENV', 'SID'), sys_context('USERENV', 'SESSIONID'), sys_context('USERENV', 'INSTANCE') from dual"
+
+// PinSession pins the sql.Conn to its current session and records the session identity.
+// The sql.Conn must be an oci8 connection. Call Unpin before closing the sql.Conn,
+// otherwise the connection stays pinned in the pool, though its errors still match driver.ErrBadConn with errors.Is.
+//
+//	conn, err := db.Conn(ctx)
+//	pinned, err := oci8.PinSession(ctx, conn)
+//	defer conn.Close()
+//	// fill a global temporary table, then query it
+//	err = pinned.Check(ctx)
+func PinSession(ctx context.Context, conn *sql.Conn) (*PinnedSession, error) {
+	err := setPinned(conn, true)
+	if err != nil {
+		return nil, err
+	}
+
+	pinnedSession := &PinnedSession{conn: conn}
+	err = conn.QueryRowContext(ctx, pinnedSessionQuery).Scan(&pinnedSession.SID, &pinnedSession.SessionID, &pinnedSession.Instance)
+	if err != nil {
+		setPinned(conn, false)
+		return nil, err
+	}
+
+	return pinnedSession, nil
+}
+
+// Conn returns the pinned sql.Conn
+func (pinnedSession *PinnedSession) Conn() *sql.Conn {
+	return pinnedSession.conn
+}
+
+// Check returns nil when the connection is still on the pinned session.
+// Returns a *SessionLostError when the session was replaced, or the error of the identity query,
+// which is a *SessionLostError when the session was lost.
+func (pinnedSession *PinnedSession) Check(ctx context.Context) error {
+	var sid, sessionID, instance string
+	err := pinnedSession.conn.QueryRowContext(ctx, pinnedSessionQuery).Scan(&sid, &sessionID, &instance)
+	if err != nil {
+		return err
+	}
+
+	if sid != pinnedSession.SID || sessionID != pinnedSession.SessionID || instance != pinnedSession.Instance {
+		return &SessionLostError{Reason: fmt.Sprintf("session changed from sid %v session id %v instance %v to sid %v session id %v instance %v",
+			pinnedSession.SID, pinnedSession.SessionID, pinnedSession.Instance, sid, sessionID, instance)}
+	}
+
+	return nil
+}
+
+// Unpin returns the bad connection errors of the connection as before PinSession, instead of as *SessionLostError
+func (pinnedSession *PinnedSession) Unpin() error {
+	return setPinned(pinnedSession.conn, false)
+}
+
+// setPinned sets pinned of the oci8 connection of a sql.Conn
+func setPinned(conn *sql.Conn, pinned bool) error {
+	return conn.Raw(func(driverConn interface{}) error {
+		oci8Conn, ok := driverConn.(*Conn)
+		if !ok {
+			return fmt.Errorf("pin session needs an oci8 connection, received %T", driverConn)
+		}
+		oci8Conn.pinned = pinned
+		return nil
+	})
+}
@@ -0,0 +1,118 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// TestPinSession checks a pinned session keeps its identity and session state
+func TestPinSession(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	pinned, err := PinSession(ctx, conn)
+	if err != nil {
+		t.Fatal("pin session error:", err)
+	}
+	if pinned.SID == "" || pinned.SessionID == "" || pinned.Instance == "" || pinned.Conn() != conn {
+		t.Fatalf("pinned session - received: %+v", pinned)
+	}
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		if !driverConn.(*Conn).pinned {
+			t.Error("pinned - expected: true - received: false")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("raw error:", err)
+	}
+
+	// session state is kept
+	_, err = conn.ExecContext(ctx, "begin dbms_session.set_identifier('pin_session_test'); end;")
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	var identifier string
+	err = conn.QueryRowContext(ctx, "select sys_context('USERENV', 'CLIENT_IDENTIFIER') from dual").Scan(&identifier)
+	if err != nil {
+		t.Fatal("query row error:", err)
+	}
+	if identifier != "pin_session_test" {
+		t.Errorf("client identifier - expected: %v - received: %v", "pin_session_test", identifier)
+	}
+
+	err = pinned.Check(ctx)
+	if err != nil {
+		t.Fatal("check error:", err)
+	}
+
+	// a different session is reported as lost
+	other := *pinned
+	other.SessionID += "0"
+	err = other.Check(ctx)
+	if !errors.Is(err, ErrSessionLost) {
+		t.Errorf("check other - expected: %v - received: %v", ErrSessionLost, err)
+	}
+
+	_, err = conn.ExecContext(ctx, "begin dbms_session.set_identifier(null); end;")
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+
+	err = pinned.Unpin()
+	if err != nil {
+		t.Fatal("unpin error:", err)
+	}
+	err = conn.Raw(func(driverConn interface{}) error {
+		if driverConn.(*Conn).pinned {
+			t.Error("pinned - expected: false - received: true")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("raw error:", err)
+	}
+}
+
+// TestSessionLostError tests session lost errors and bad connection errors of pinned connections
+func TestSessionLostError(t *testing.T) {
+	t.Parallel()
+
+	conn := &Conn{}
+	err := conn.badConnError("lost")
+	if err != driver.ErrBadConn {
+		t.Errorf("not pinned - expected: %v - received: %v", driver.ErrBadConn, err)
+	}
+
+	conn.pinned = true
+	err = conn.badConnError("ORA-03113: end-of-file on communication channel")
+	var sessionLostError *SessionLostError
+	if !errors.As(err, &sessionLostError) || sessionLostError.Reason != "ORA-03113: end-of-file on communication channel" {
+		t.Errorf("pinned - received: %#v", err)
+	}
+	if !errors.Is(err, ErrSessionLost) || !errors.Is(err, driver.ErrBadConn) {
+		t.Errorf("pinned - expected errors.Is ErrSessionLost and driver.ErrBadConn - received: %v", err)
+	}
+	expected := "pinned session was lost, its session state is gone: ORA-03113: end-of-file on communication channel"
+	if err.Error() != expected {
+		t.Errorf("error - expected: %v - received: %v", expected, err.Error())
+	}
+}
@@ -0,0 +1,240 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testBlockBatchExecer records the exec and sets the OUT binds of the batch block
+type testBlockBatchExecer struct {
+	query string
+	args  []interface{}
+	err   error
+}
+
+func (execer *testBlockBatchExecer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	execer.query = query
+	execer.args = args
+	if execer.err != nil {
+		return nil, execer.err
+	}
+	for _, arg := range args {
+		named := arg.(sql.NamedArg)
+		out, ok := named.Value.(sql.Out)
+		if !ok {
+			continue
+		}
+		switch {
+		case named.Name == "p1_rows":
+			*out.Dest.(*sql.NullInt64) = sql.NullInt64{Int64: 3, Valid: true}
+		case named.Name == "p2_code":
+			*out.Dest.(*sql.NullInt64) = sql.NullInt64{Int64: -1, Valid: true}
+		case named.Name == "p2_message":
+			*out.Dest.(*sql.NullString) = sql.NullString{String: "ORA-00001: unique constraint violated", Valid: true}
+		}
+	}
+	return nil, nil
+}
+
+// TestRenamePlaceholders tests renaming bind placeholders
+func TestRenamePlaceholders(t *testing.T) {
+	t.Parallel()
+
+	var renameTests = []struct {
+		query    string
+		expected string
+		names    []string
+	}{
+		{"select 1 from dual", "select 1 from dual", nil},
+		{"update t set a = :1 where b = :2", "update t set a = :x0 where b = :x1", []string{"1", "2"}},
+		{"insert into t values (:a, :B, :a)", "insert into t values (:x0, :x1, :x0)", []string{"A", "B"}},
+		{"begin x := ':a'; /* :b */ y := \"C:D\"; -- :c\n z := :d; end", "begin x := ':a'; /* :b */ y := \"C:D\"; -- :c\n z := :x0; end", []string{"D"}},
+		{"update t set a = 'it''s :a' where b = :b", "update t set a = 'it''s :a' where b = :x0", []string{"B"}},
+		{"select :a from t where x = ':", "select :x0 from t where x = ':", []string{"A"}},
+		{"update t set a = q'[it's :a]' where b = :b", "update t set a = q'[it's :a]' where b = :x0", []string{"B"}},
+		{"update t set a = NQ'{:a}', c = 'x' where b = :b", "update t set a = NQ'{:a}', c = 'x' where b = :x0", []string{"B"}},
+		{"update t set a = ? where b = ? /* ? */", "update t set a = :x0 where b = :x1 /* ? */", []string{"?1", "?2"}},
+		{"begin :a := 'ä'; :b := :a; end", "begin :x0 := 'ä'; :x1 := :x0; end", []string{"A", "B"}},
+	}
+
+	for _, tt := range renameTests {
+		query, names := renamePlaceholders(tt.query, func(name string, position int) string {
+			return "x" + string(rune('0'+position))
+		})
+		if query != tt.expected {
+			t.Errorf("renamePlaceholders(%q) - expected: %q - received: %q", tt.query, tt.expected, query)
+		}
+		if !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("renamePlaceholders(%q) names - expected: %v - received: %v", tt.query, tt.names, names)
+		}
+	}
+}
+
+// TestBlockBatch tests queuing and flushing a batch
+func TestBlockBatch(t *testing.T) {
+	t.Parallel()
+
+	execer := &testBlockBatchExecer{}
+	batch := NewBlockBatch(execer)
+
+	err := batch.Add("update t set a = :1 where b = :2", 1, "x")
+	if err != nil {
+		t.Fatal("add error:", err)
+	}
+	err = batch.Add("insert into t (a, b) values (:a, :b);", sql.Named("b", 2), sql.Named("A", "y"))
+	if err != nil {
+		t.Fatal("add error:", err)
+	}
+	if batch.Len() != 2 {
+		t.Fatalf("len - expected: 2 - received: %v", batch.Len())
+	}
+
+	if batch.Add("") == nil {
+		t.Error("expected error for empty statement")
+	}
+	if batch.Add("update t set a = :1", 1, 2) == nil {
+		t.Error("expected error for too many args")
+	}
+	if batch.Add("update t set a = :1 where b = :2", 1) == nil {
+		t.Error("expected error for missing arg")
+	}
+	if batch.Add("update t set a = :a", sql.Named("c", 1)) == nil {
+		t.Error("expected error for unknown name")
+	}
+	if batch.Len() != 2 {
+		t.Fatalf("len after errors - expected: 2 - received: %v", batch.Len())
+	}
+
+	results, err := batch.Flush(context.Background())
+	if err != nil {
+		t.Fatal("flush error:", err)
+	}
+	if batch.Len() != 0 {
+		t.Errorf("len after flush - expected: 0 - received: %v", batch.Len())
+	}
+
+	if !strings.Contains(execer.query, "update t set a = :p1_1 where b = :p1_2;\n:p1_rows := sql%rowcount;") ||
+		!strings.Contains(execer.query, "insert into t (a, b) values (:p2_1, :p2_2);\n:p2_rows := sql%rowcount;") {
+		t.Errorf("unexpected query: %v", execer.query)
+	}
+	if len(execer.args) != 10 {
+		t.Fatalf("args - expected: 10 - received: %v", len(execer.args))
+	}
+	expectedArgs := []sql.NamedArg{sql.Named("p1_1", 1), sql.Named("p1_2", "x")}
+	if !reflect.DeepEqual(execer.args[:2], []interface{}{expectedArgs[0], expectedArgs[1]}) {
+		t.Errorf("args - expected: %v - received: %v", expectedArgs, execer.args[:2])
+	}
+	expectedArgs = []sql.NamedArg{sql.Named("p2_1", "y"), sql.Named("p2_2", 2)}
+	if !reflect.DeepEqual(execer.args[5:7], []interface{}{expectedArgs[0], expectedArgs[1]}) {
+		t.Errorf("args - expected: %v - received: %v", expectedArgs, execer.args[5:7])
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("results - expected: 2 - received: %v", len(results))
+	}
+	if results[0].RowsAffected != 3 || results[0].Err != nil {
+		t.Errorf("result 0 - received: %+v", results[0])
+	}
+	var oracleError *Error
+	if !errors.As(results[1].Err, &oracleError) || oracleError.Code != 1 || oracleError.Message != "ORA-00001: unique constraint violated" ||
+		!errors.Is(results[1].Err, ErrUniqueViolation) {
+		t.Errorf("result 1 - received: %+v", results[1])
+	}
+
+	results, err = batch.Flush(context.Background())
+	if err != nil || results != nil {
+		t.Errorf("empty flush - expected nil - received: %v, %v", results, err)
+	}
+
+	execer.err = errors.New("exec failed")
+	err = batch.Add("delete from t")
+	if err != nil {
+		t.Fatal("add error:", err)
+	}
+	_, err = batch.Flush(context.Background())
+	if err != execer.err {
+		t.Errorf("flush error - expected: %v - received: %v", execer.err, err)
+	}
+}
+
+// TestBlockBatchError tests the errors of the SQLCODE and SQLERRM of batch statements
+func TestBlockBatchError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sqlCode int64
+		message string
+		code    int
+		text    string
+	}{
+		{sqlCode: -1, message: "ORA-00001: unique constraint (SCOTT.PK) violated", code: 1, text: "ORA-00001: unique constraint (SCOTT.PK) violated"},
+		{sqlCode: 100, message: "ORA-01403: no data found", code: 1403, text: "ORA-01403: no data found"},
+		{sqlCode: 1, message: "User-Defined Exception", code: 0, text: "User-Defined Exception"},
+		{sqlCode: -20001, message: "", code: 20001, text: "ORA-20001"},
+	}
+	for _, test := range tests {
+		err := blockBatchError(test.sqlCode, test.message)
+		if err.Code != test.code || err.Error() != test.text {
+			t.Errorf("blockBatchError %v - expected: %v %v - received: %v %v", test.sqlCode, test.code, test.text, err.Code, err.Error())
+		}
+	}
+	if !errors.Is(blockBatchError(100, "ORA-01403: no data found"), ErrNoDataFound) {
+		t.Errorf("errors.Is ErrNoDataFound - expected: %v - received: %v", true, false)
+	}
+}
+
+// TestBlockBatchFlush checks the results of the statements of a batch
+func TestBlockBatchFlush(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "BLOCK_BATCH_FLUSH_" + TestTimeString
+	testExecQuery(t, "create table "+tableName+" ( A INTEGER primary key, B VARCHAR2(10) )", nil)
+	defer testDropTable(t, tableName)
+
+	batch := NewBlockBatch(TestDB)
+	queries := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"insert into " + tableName + " ( A, B ) values (:1, :2)", []interface{}{1, "a"}},
+		{"insert into " + tableName + " ( A, B ) values (:1, :2)", []interface{}{2, "b"}},
+		{"insert into " + tableName + " ( A, B ) values (:1, :2)", []interface{}{1, "c"}},
+		{"update " + tableName + " set B = :b where A >= :a", []interface{}{sql.Named("a", 1), sql.Named("b", "d")}},
+	}
+	for _, query := range queries {
+		err := batch.Add(query.query, query.args...)
+		if err != nil {
+			t.Fatal("add error:", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	results, err := batch.Flush(ctx)
+	cancel()
+	if err != nil {
+		t.Fatal("flush error:", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("results - expected: 4 - received: %v", len(results))
+	}
+	if results[0].Err != nil || results[0].RowsAffected != 1 || results[1].Err != nil || results[1].RowsAffected != 1 {
+		t.Errorf("insert results - received: %+v, %+v", results[0], results[1])
+	}
+	if !errors.Is(results[2].Err, ErrUniqueViolation) || !strings.HasPrefix(results[2].Err.Error(), "ORA-00001:") {
+		t.Errorf("duplicate insert result - received: %+v", results[2])
+	}
+	if results[3].Err != nil || results[3].RowsAffected != 2 {
+		t.Errorf("update result - received: %+v", results[3])
+	}
+}
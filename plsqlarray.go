@@ -0,0 +1,200 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+const (
+	// defaultPlsqlArrayLength is the max number of elements of a PL/SQL array OUT bind when the slice has no capacity
+	defaultPlsqlArrayLength = 1000
+	// plsqlArrayStringSize is the buffer size in bytes of each string element of a PL/SQL array OUT bind
+	plsqlArrayStringSize = 4000
+	// plsqlArrayMaxStringSize is the max size in bytes of a PL/SQL VARCHAR2
+	plsqlArrayMaxStringSize = 32767
+)
+
+/*
+PL/SQL array binds
+
+A sql.Out with a Dest of *[]int64, *[]float64, or *[]string is bound as a PL/SQL index-by table,
+like a TABLE OF NUMBER INDEX BY PLS_INTEGER declared in a package, so a procedure can BULK COLLECT
+thousands of scalars into an OUT parameter and return them in one round trip:
+
+	var ids []int64
+	_, err := db.ExecContext(ctx, "begin my_package.get_ids(:1); end;", sql.Out{Dest: &ids})
+
+The max number of elements is the capacity of the slice, or 1000 when the slice has no capacity.
+Use make([]int64, 0, 50000) to receive more elements. Getting more elements than the max is an Oracle error.
+With sql.Out In set, the elements of the slice are bound as the input of an IN OUT parameter.
+Null elements are returned as the zero value. String elements are returned up to 4000 bytes,
+or up to the longest input element.
+*/
+
+// plsqlStringArray is a PL/SQL array OUT bind of strings with elements of size bytes,
+// for strings longer than plsqlArrayStringSize like the lines of DBMS_OUTPUT
+type plsqlStringArray struct {
+	dest *[]string
+	size int
+}
+
+// plsqlStrings returns the slice and the element size in bytes of a *[]string or *plsqlStringArray dest
+func plsqlStrings(dest interface{}) (*[]string, int) {
+	if array, ok := dest.(*plsqlStringArray); ok {
+		return array.dest, array.size
+	}
+	return dest.(*[]string), plsqlArrayStringSize
+}
+
+// plsqlArrayLengths returns the number of input elements and the max number of elements of a PL/SQL array bind,
+// ok is false when dest is not a supported slice pointer
+func plsqlArrayLengths(dest interface{}, in bool) (length int, maxLength int, ok bool) {
+	switch dest := dest.(type) {
+	case *[]int64:
+		length, maxLength = len(*dest), cap(*dest)
+	case *[]float64:
+		length, maxLength = len(*dest), cap(*dest)
+	case *[]string:
+		length, maxLength = len(*dest), cap(*dest)
+	case *plsqlStringArray:
+		length, maxLength = len(*dest.dest), cap(*dest.dest)
+	default:
+		return 0, 0, false
+	}
+	if !in {
+		length = 0
+	}
+	if maxLength < 1 {
+		maxLength = defaultPlsqlArrayLength
+	}
+	return length, maxLength, true
+}
+
+// plsqlArrayBind sets the buffers of a PL/SQL array bind, replacing the single value length and indicator of the bind.
+// With utf16 the string elements are bound as UTF-16 text.
+func plsqlArrayBind(bind *bindStruct, dest interface{}, utf16 bool) error {
+	length, maxLength, ok := plsqlArrayLengths(dest, bind.out.In)
+	if !ok {
+		return fmt.Errorf("unsupported PL/SQL array type %T", dest)
+	}
+
+	C.free(unsafe.Pointer(bind.length))
+	C.free(unsafe.Pointer(bind.indicator))
+	bind.length = (*C.ub2)(C.malloc(C.size_t(maxLength) * C.sizeof_ub2))
+	bind.indicator = (*C.sb2)(C.malloc(C.size_t(maxLength) * C.sizeof_sb2))
+	bind.currentLength = (*C.ub4)(C.malloc(C.sizeof_ub4))
+	*bind.currentLength = C.ub4(length)
+	bind.maxArrayLength = C.ub4(maxLength)
+	lengths := (*[1 << 28]C.ub2)(unsafe.Pointer(bind.length))[:maxLength:maxLength]
+	indicators := (*[1 << 28]C.sb2)(unsafe.Pointer(bind.indicator))[:maxLength:maxLength]
+	for i := range indicators {
+		indicators[i] = 0
+	}
+
+	switch dest := dest.(type) {
+	case *[]int64, *[]float64:
+		bind.maxSize = 8
+		bind.pbuf = C.malloc(C.size_t(maxLength) * 8)
+		buffer := (*[1 << 30]byte)(bind.pbuf)[: maxLength*8 : maxLength*8]
+		if values, ok := dest.(*[]int64); ok {
+			bind.dataType = C.SQLT_INT
+			for i := 0; i < length; i++ {
+				binary.LittleEndian.PutUint64(buffer[i*8:], uint64((*values)[i]))
+			}
+		} else {
+			bind.dataType = C.SQLT_BDOUBLE
+			values := dest.(*[]float64)
+			for i := 0; i < length; i++ {
+				binary.LittleEndian.PutUint64(buffer[i*8:], math.Float64bits((*values)[i]))
+			}
+		}
+		for i := range lengths {
+			lengths[i] = 8
+		}
+
+	case *[]string, *plsqlStringArray:
+		values, size := plsqlStrings(dest)
+		elements := *values
+		maxSize := plsqlArrayMaxStringSize
+		if utf16 {
+			// the elements as text in the character set of the environment
+			elements = make([]string, length)
+			for i := range elements {
+				elements[i] = string(utf16Encode((*values)[i]))
+			}
+			size *= 2
+			maxSize *= 2
+		}
+		for i := 0; i < length; i++ {
+			if len(elements[i]) > size {
+				size = len(elements[i])
+			}
+		}
+		if size > maxSize {
+			return fmt.Errorf("PL/SQL array string element of %v bytes is longer than %v bytes", size, maxSize)
+		}
+		bind.dataType = C.SQLT_CHR
+		bind.maxSize = C.sb4(size)
+		bind.pbuf = C.malloc(C.size_t(maxLength) * C.size_t(size))
+		buffer := (*[1 << 30]byte)(bind.pbuf)[: maxLength*size : maxLength*size]
+		for i := range lengths {
+			lengths[i] = 0
+		}
+		for i := 0; i < length; i++ {
+			copy(buffer[i*size:], elements[i])
+			lengths[i] = C.ub2(len(elements[i]))
+		}
+	}
+
+	return nil
+}
+
+// outputPlsqlArray sets the dest slice to the elements returned in a PL/SQL array bind.
+// With utf16 the string elements are UTF-16 text.
+func outputPlsqlArray(bind bindStruct, utf16 bool) {
+	count := int(*bind.currentLength)
+	if count > int(bind.maxArrayLength) {
+		count = int(bind.maxArrayLength)
+	}
+	lengths := (*[1 << 28]C.ub2)(unsafe.Pointer(bind.length))[:count:count]
+	indicators := (*[1 << 28]C.sb2)(unsafe.Pointer(bind.indicator))[:count:count]
+	size := int(bind.maxSize)
+	buffer := (*[1 << 30]byte)(bind.pbuf)[: count*size : count*size]
+
+	switch dest := bind.out.Dest.(type) {
+	case *[]int64:
+		values := make([]int64, count)
+		for i := range values {
+			if indicators[i] != -1 {
+				values[i] = int64(binary.LittleEndian.Uint64(buffer[i*8:]))
+			}
+		}
+		*dest = values
+	case *[]float64:
+		values := make([]float64, count)
+		for i := range values {
+			if indicators[i] != -1 {
+				values[i] = math.Float64frombits(binary.LittleEndian.Uint64(buffer[i*8:]))
+			}
+		}
+		*dest = values
+	case *[]string, *plsqlStringArray:
+		values := make([]string, count)
+		for i := range values {
+			if indicators[i] != -1 {
+				if utf16 {
+					values[i] = utf16Decode(buffer[i*size : i*size+int(lengths[i])])
+				} else {
+					values[i] = string(buffer[i*size : i*size+int(lengths[i])])
+				}
+			}
+		}
+		strings, _ := plsqlStrings(dest)
+		*strings = values
+	}
+}
@@ -0,0 +1,110 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+// TestPlsqlArrayOutBinds tests PL/SQL index-by table OUT binds filled with BULK COLLECT are returned as slices
+func TestPlsqlArrayOutBinds(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	packageName := "PKG_ARRAY_BINDS_" + TestTimeString
+	testExecQuery(t, `create or replace package `+packageName+` is
+	type t_numbers is table of number index by pls_integer;
+	type t_strings is table of varchar2(100) index by pls_integer;
+	procedure get_rows (p_count number, p_ids out t_numbers, p_names out t_strings);
+	procedure double_rows (p_values in out t_numbers);
+end `+packageName+`;`, nil)
+	defer testExecQuery(t, "drop package "+packageName, nil)
+	testExecQuery(t, `create or replace package body `+packageName+` is
+	procedure get_rows (p_count number, p_ids out t_numbers, p_names out t_strings) is
+	begin
+		select level, decode(level, 2, null, 'row ' || level) bulk collect into p_ids, p_names from dual connect by level <= p_count;
+	end get_rows;
+	procedure double_rows (p_values in out t_numbers) is
+	begin
+		for i in 1 .. p_values.count loop
+			p_values(i) := p_values(i) * 2;
+		end loop;
+	end double_rows;
+end `+packageName+`;`, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	var ids []int64
+	var names []string
+	_, err := TestDB.ExecContext(ctx, "begin "+packageName+".get_rows(:1, :2, :3); end;", 3, sql.Out{Dest: &ids}, sql.Out{Dest: &names})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+		t.Errorf("ids - expected: %v - received: %v", []int64{1, 2, 3}, ids)
+	}
+	if !reflect.DeepEqual(names, []string{"row 1", "", "row 3"}) {
+		t.Errorf("names - expected: %v - received: %v", []string{"row 1", "", "row 3"}, names)
+	}
+
+	// more rows than the default max elements
+	ids = make([]int64, 0, 5000)
+	_, err = TestDB.ExecContext(ctx, "begin "+packageName+".get_rows(:1, :2, :3); end;", 5000, sql.Out{Dest: &ids}, sql.Out{Dest: &names})
+	if err == nil {
+		t.Fatal("exec - expected: error for 5000 names - received: nil")
+	}
+	names = make([]string, 0, 5000)
+	_, err = TestDB.ExecContext(ctx, "begin "+packageName+".get_rows(:1, :2, :3); end;", 5000, sql.Out{Dest: &ids}, sql.Out{Dest: &names})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if len(ids) != 5000 || ids[4999] != 5000 || len(names) != 5000 || names[4999] != "row 5000" {
+		t.Errorf("ids and names - expected length: 5000 - received: %v %v", len(ids), len(names))
+	}
+
+	values := []float64{1.5, 2, 3.25}
+	_, err = TestDB.ExecContext(ctx, "begin "+packageName+".double_rows(:1); end;", sql.Out{Dest: &values, In: true})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if !reflect.DeepEqual(values, []float64{3, 4, 6.5}) {
+		t.Errorf("values - expected: %v - received: %v", []float64{3, 4, 6.5}, values)
+	}
+}
+
+// TestPlsqlArrayLengths tests the input and max element counts of PL/SQL array binds
+func TestPlsqlArrayLengths(t *testing.T) {
+	t.Parallel()
+
+	var ids []int64
+	values := make([]float64, 2, 10)
+	names := []string{"a"}
+	lines := make([]string, 0, 100)
+	var text string
+	var lengthTests = []struct {
+		dest      interface{}
+		in        bool
+		length    int
+		maxLength int
+		ok        bool
+	}{
+		{&ids, false, 0, defaultPlsqlArrayLength, true},
+		{&values, false, 0, 10, true},
+		{&values, true, 2, 10, true},
+		{&names, true, 1, 1, true},
+		{&plsqlStringArray{dest: &lines, size: plsqlArrayMaxStringSize}, false, 0, 100, true},
+		{&text, false, 0, 0, false},
+		{ids, false, 0, 0, false},
+	}
+	for _, tt := range lengthTests {
+		length, maxLength, ok := plsqlArrayLengths(tt.dest, tt.in)
+		if length != tt.length || maxLength != tt.maxLength || ok != tt.ok {
+			t.Errorf("plsqlArrayLengths %T - expected: %v %v %v - received: %v %v %v", tt.dest, tt.length, tt.maxLength, tt.ok, length, maxLength, ok)
+		}
+	}
+}
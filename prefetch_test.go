@@ -0,0 +1,163 @@
+package oci8
+
+import (
+	"context"
+	"testing"
+)
+
+// TestPrefetchTargetRows checks fetching narrow and wide rows with the prefetch rows tuned by prefetch_target
+func TestPrefetchTargetRows(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?prefetch_target=65536")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	for _, width := range []int{1, 4000} {
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		rows, err := db.QueryContext(ctx, "select level, rpad('a', :1, 'a') from dual connect by level <= 1000", width)
+		if err != nil {
+			cancel()
+			t.Fatal("query error:", err)
+		}
+		var count int64
+		for rows.Next() {
+			var level int64
+			var text string
+			err = rows.Scan(&level, &text)
+			if err != nil {
+				rows.Close()
+				cancel()
+				t.Fatal("scan error:", err)
+			}
+			count++
+			if level != count || len(text) != width {
+				t.Errorf("row %v - expected: %v, %v - received: %v, %v", count, count, width, level, len(text))
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		cancel()
+		if err != nil {
+			t.Fatal("rows error:", err)
+		}
+		if count != 1000 {
+			t.Errorf("count - expected: %v - received: %v", 1000, count)
+		}
+	}
+}
+
+// TestSelectWithPrefetch checks queries with the prefetch of the context return all the rows
+func TestSelectWithPrefetch(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?stmt_cache_size=10")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	query := "select level from dual connect by level <= 1000"
+	for _, tt := range []struct {
+		rows   uint32
+		memory uint32
+	}{
+		{500, 0},
+		{10, 1024},
+		{0, 0},
+		{1, 0},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		rows, err := db.QueryContext(WithPrefetch(ctx, tt.rows, tt.memory), query)
+		if err != nil {
+			cancel()
+			t.Fatal("query error:", err)
+		}
+		count := int64(0)
+		for rows.Next() {
+			var level int64
+			err = rows.Scan(&level)
+			if err != nil {
+				t.Error("scan error:", err)
+				break
+			}
+			count++
+			if level != count {
+				t.Errorf("prefetch %v %v level - expected: %v - received: %v", tt.rows, tt.memory, count, level)
+				break
+			}
+		}
+		err = rows.Err()
+		if err != nil {
+			t.Error("rows error:", err)
+		}
+		rows.Close()
+		cancel()
+
+		if count != 1000 {
+			t.Errorf("prefetch %v %v rows - expected: %v - received: %v", tt.rows, tt.memory, 1000, count)
+		}
+	}
+}
+
+// TestPrefetchFromContext tests the prefetch of a context
+func TestPrefetchFromContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, ok := prefetchFromContext(ctx)
+	if ok {
+		t.Errorf("prefetch of background context - expected: not set - received: set")
+	}
+
+	ctx = WithPrefetch(ctx, 10000, 1<<20)
+	received, ok := prefetchFromContext(ctx)
+	expected := prefetch{rows: 10000, memory: 1 << 20}
+	if !ok || received != expected {
+		t.Errorf("prefetch - expected: %+v - received: %+v", expected, received)
+	}
+}
+
+// TestPrefetchTarget tests the prefetch target of the prefetch_target, prefetch_rows, and prefetch_memory parameters
+func TestPrefetchTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dsn    string
+		target int64
+	}{
+		{dsn: "scott/tiger@dbhost", target: defaultPrefetchTarget},
+		{dsn: "scott/tiger@dbhost?prefetch_target=65536", target: 65536},
+		{dsn: "scott/tiger@dbhost?prefetch_target=0", target: 0},
+		{dsn: "scott/tiger@dbhost?prefetch_rows=100", target: 0},
+		{dsn: "scott/tiger@dbhost?prefetch_memory=0", target: 0},
+		{dsn: "scott/tiger@dbhost?prefetch_rows=100&prefetch_target=65536", target: 65536},
+	}
+	for _, test := range tests {
+		dsn, err := ParseDSN(test.dsn)
+		if err != nil {
+			t.Fatal("ParseDSN error:", err)
+		}
+		target := dsn.prefetchTargetBytes()
+		if target != test.target {
+			t.Errorf("prefetch target %v - expected: %v - received: %v", test.dsn, test.target, target)
+		}
+	}
+
+	for _, dsn := range []string{"scott/tiger@dbhost?prefetch_target=x", "scott/tiger@dbhost?prefetch_target=-1"} {
+		_, err := ParseDSN(dsn)
+		if err == nil {
+			t.Errorf("ParseDSN %v - expected: error - received: nil", dsn)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// PrepareBatchError is returned by PrepareBatch when a statement can not be prepared or does not parse
+type PrepareBatchError struct {
+	// Index is the zero based index of the statement
+	Index int
+	// Query is the statement text
+	Query string
+	// Offset is the zero based offset in the statement of the parse error, -1 when not known
+	Offset int
+	// Err is the underlying error
+	Err error
+}
+
+// Error returns the prepare batch error string
+func (prepareBatchError *PrepareBatchError) Error() string {
+	text := "prepare statement " + strconv.Itoa(prepareBatchError.Index)
+	if prepareBatchError.Offset >= 0 {
+		text += " at offset " + strconv.Itoa(prepareBatchError.Offset)
+	}
+	return text + ": " + prepareBatchError.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (prepareBatchError *PrepareBatchError) Unwrap() error {
+	return prepareBatchError.Err
+}
+
+// PrepareBatch prepares many statements on the connection, like the statements of an application at startup,
+// and checks that they parse on the server.
+// Preparing a statement is done by the client library, the server only parses it when it is first executed,
+// so PrepareBatch adds one round trip that parses the queries, DML, and PL/SQL blocks with DBMS_SQL in one PL/SQL block.
+// It does not save the round trip of the first execute of each statement, nor describe the statements,
+// but it finds the parse errors at startup and leaves the cursors in the shared pool, so the first execute is a soft parse.
+// The text parsed is the text prepared, with the request ID comment of WithRequestID and the RETURNING clause of identity_returning.
+// DDL and other statements are prepared but not parsed, as DBMS_SQL executes DDL when parsing it.
+// Use sql.Conn.Raw to get the underlying *Conn, the statements can only be used on it, and must be closed.
+//
+// When a statement can not be prepared or does not parse, the prepared statements are closed
+// and a *PrepareBatchError of the first failing statement is returned.
+func (conn *Conn) PrepareBatch(ctx context.Context, queries []string) ([]driver.Stmt, error) {
+	stmts := make([]driver.Stmt, 0, len(queries))
+	closeStmts := func() {
+		for _, stmt := range stmts {
+			stmt.Close()
+		}
+	}
+
+	var parseIndexes []int
+	var parseTexts []string
+	for i, query := range queries {
+		stmt, err := conn.PrepareContext(ctx, query)
+		if err != nil {
+			closeStmts()
+			return nil, &PrepareBatchError{Index: i, Query: query, Offset: -1, Err: err}
+		}
+		stmts = append(stmts, stmt)
+
+		var stmtType C.ub2
+		_, err = stmt.(*Stmt).ociAttrGet(unsafe.Pointer(&stmtType), C.OCI_ATTR_STMT_TYPE)
+		if err != nil {
+			closeStmts()
+			return nil, &PrepareBatchError{Index: i, Query: query, Offset: -1, Err: err}
+		}
+		if prepareBatchParses(stmtType) {
+			var text string
+			text, err = stmt.(*Stmt).statementText()
+			if err != nil {
+				closeStmts()
+				return nil, &PrepareBatchError{Index: i, Query: query, Offset: -1, Err: err}
+			}
+			parseIndexes = append(parseIndexes, i)
+			parseTexts = append(parseTexts, text)
+		}
+	}
+	if len(parseIndexes) == 0 {
+		return stmts, nil
+	}
+
+	index := int64(-1)
+	offset := int64(-1)
+	var message string
+	namedValues := []driver.NamedValue{
+		{Name: "error_index", Ordinal: 1, Value: sql.Out{Dest: &index, In: true}},
+		{Name: "error_offset", Ordinal: 2, Value: sql.Out{Dest: &offset, In: true}},
+		{Name: "error_message", Ordinal: 3, Value: sql.Out{Dest: &message}},
+	}
+	for i, index := range parseIndexes {
+		namedValues = append(namedValues, driver.NamedValue{Name: "q" + strconv.Itoa(index), Ordinal: len(namedValues) + 1, Value: parseTexts[i]})
+	}
+
+	_, err := conn.exec(ctx, prepareBatchBlock(parseIndexes), namedValues)
+	if err != nil {
+		closeStmts()
+		return nil, err
+	}
+	if index >= 0 {
+		closeStmts()
+		return nil, &PrepareBatchError{Index: int(index), Query: queries[index], Offset: int(offset), Err: errors.New(message)}
+	}
+
+	return stmts, nil
+}
+
+// statementText returns the text of the prepared statement handle, which is the text the execute runs
+func (stmt *Stmt) statementText() (string, error) {
+	var text *C.OraText
+	size, err := stmt.ociAttrGet(unsafe.Pointer(&text), C.OCI_ATTR_STATEMENT)
+	if err != nil {
+		return "", err
+	}
+	return stmt.conn.goText(text, int(size)), nil
+}
+
+// prepareBatchParses returns true for the statement types PrepareBatch parses with DBMS_SQL,
+// which does not execute them when parsing
+func prepareBatchParses(stmtType C.ub2) bool {
+	switch stmtType {
+	case C.OCI_STMT_SELECT, C.OCI_STMT_INSERT, C.OCI_STMT_UPDATE, C.OCI_STMT_DELETE, C.OCI_STMT_MERGE,
+		C.OCI_STMT_BEGIN, C.OCI_STMT_DECLARE:
+		return true
+	}
+	return false
+}
+
+// prepareBatchBlock returns the PL/SQL block that parses the statements of the indexes, bound as :q<index>.
+// It stops at the first statement that does not parse and sets its index, the error offset, and the error message.
+// The cursor is closed in all cases.
+func prepareBatchBlock(indexes []int) string {
+	var builder strings.Builder
+	builder.WriteString(`declare
+	l_cursor integer := dbms_sql.open_cursor;
+	l_index pls_integer;
+	l_offset pls_integer;
+	l_message varchar2(4000);
+	procedure parse_query(p_index pls_integer, p_query clob) is
+	begin
+		l_index := p_index;
+		dbms_sql.parse(l_cursor, p_query, dbms_sql.native);
+	end;
+begin
+`)
+	for _, index := range indexes {
+		builder.WriteString("\tparse_query(" + strconv.Itoa(index) + ", :q" + strconv.Itoa(index) + ");\n")
+	}
+	builder.WriteString(`	dbms_sql.close_cursor(l_cursor);
+exception
+	when others then
+		l_offset := dbms_sql.last_error_position;
+		l_message := sqlerrm;
+		if dbms_sql.is_open(l_cursor) then
+			dbms_sql.close_cursor(l_cursor);
+		end if;
+		if l_index is null then
+			raise;
+		end if;
+		:error_index := l_index;
+		:error_offset := l_offset;
+		:error_message := l_message;
+end;`)
+	return builder.String()
+}
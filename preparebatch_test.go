@@ -0,0 +1,103 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestPrepareBatch checks preparing many statements with PrepareBatch and the error of a statement that does not parse
+func TestPrepareBatch(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		stmts, err := driverConn.(*Conn).PrepareBatch(ctx, []string{
+			"select :1 from dual",
+			"begin null; end;",
+			"select sysdate from dual",
+		})
+		if err != nil {
+			return err
+		}
+		if len(stmts) != 3 {
+			t.Errorf("stmts - expected: %v - received: %v", 3, len(stmts))
+		}
+
+		rows, err := stmts[0].(driver.StmtQueryContext).QueryContext(ctx, []driver.NamedValue{{Ordinal: 1, Value: int64(7)}})
+		if err != nil {
+			return err
+		}
+		dest := make([]driver.Value, 1)
+		err = rows.Next(dest)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if dest[0] != int64(7) {
+			t.Errorf("value - expected: %v - received: %v", int64(7), dest[0])
+		}
+
+		for _, stmt := range stmts {
+			err = stmt.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = driverConn.(*Conn).PrepareBatch(ctx, []string{
+			"select 1 from dual",
+			"select 1 from PREPARE_BATCH_DOES_NOT_EXIST",
+		})
+		var prepareBatchError *PrepareBatchError
+		if !errors.As(err, &prepareBatchError) {
+			t.Fatalf("PrepareBatch error - expected: %T - received: %v", prepareBatchError, err)
+		}
+		if prepareBatchError.Index != 1 || prepareBatchError.Offset != 14 || !isOracleError(prepareBatchError.Err, 942) {
+			t.Errorf("PrepareBatch error - expected: index 1 offset 14 ORA-00942 - received: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("prepare batch error:", err)
+	}
+}
+
+// TestPrepareBatchBlock tests the PL/SQL block that parses the statements of PrepareBatch
+func TestPrepareBatchBlock(t *testing.T) {
+	t.Parallel()
+
+	block := prepareBatchBlock([]int{0, 2})
+	for _, expected := range []string{"parse_query(0, :q0);", "parse_query(2, :q2);", ":error_index := l_index;", "dbms_sql.is_open(l_cursor)"} {
+		if !strings.Contains(block, expected) {
+			t.Errorf("prepareBatchBlock - expected: %v - received: %v", expected, block)
+		}
+	}
+	if strings.Contains(block, ":q1") {
+		t.Errorf("prepareBatchBlock - expected: no :q1 - received: %v", block)
+	}
+	if strings.Index(block, "dbms_sql.close_cursor(l_cursor);\n\t\tend if;") > strings.Index(block, "raise;") {
+		t.Errorf("prepareBatchBlock - expected: cursor closed before raise - received: %v", block)
+	}
+
+	err := &PrepareBatchError{Index: 2, Offset: 7, Err: errors.New("ORA-00942: table or view does not exist")}
+	if err.Error() != "prepare statement 2 at offset 7: ORA-00942: table or view does not exist" {
+		t.Errorf("PrepareBatchError - expected: %v - received: %v", "prepare statement 2 at offset 7: ORA-00942: table or view does not exist", err.Error())
+	}
+}
@@ -0,0 +1,122 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// TestScanIntoRawBytes checks ScanInto sets a sql.RawBytes to the define buffer
+func TestScanIntoRawBytes(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	var name, raw, null sql.RawBytes
+	var names []string
+	err = conn.Raw(func(driverConn interface{}) error {
+		stmt, err := driverConn.(*Conn).PrepareContext(ctx, "select 'row ' || level, hextoraw('0' || level), cast (null as VARCHAR2(10)) from dual connect by level <= 3")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		rows, err := stmt.(driver.StmtQueryContext).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for {
+			err = rows.(*Rows).ScanInto(&name, &raw, &null)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if len(raw) != 1 || raw[0] != byte(len(names)+1) {
+				t.Errorf("raw - expected: %v - received: %v", []byte{byte(len(names) + 1)}, raw)
+			}
+			if null != nil {
+				t.Errorf("null - expected: nil - received: %v", null)
+			}
+			names = append(names, string(name))
+		}
+	})
+	if err != nil {
+		t.Fatal("scan into error:", err)
+	}
+
+	if !reflect.DeepEqual(names, []string{"row 1", "row 2", "row 3"}) {
+		t.Errorf("names - expected: %v - received: %v", []string{"row 1", "row 2", "row 3"}, names)
+	}
+}
+
+// TestRawBytes checks scanning character values into sql.RawBytes with WithRawBytes
+func TestRawBytes(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	rows, err := TestDB.QueryContext(WithRawBytes(ctx), "select 'row ' || level, to_char(level), cast (null as VARCHAR2(10)), to_char(level) from dual connect by level <= 3")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal("column types error:", err)
+	}
+	if columnTypes[0].ScanType() != reflect.TypeOf([]byte{}) {
+		t.Errorf("scan type - expected: %v - received: %v", reflect.TypeOf([]byte{}), columnTypes[0].ScanType())
+	}
+
+	var raw, null sql.RawBytes
+	var text string
+	var value interface{}
+	var names []string
+	for rows.Next() {
+		err = rows.Scan(&raw, &text, &null, &value)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		names = append(names, string(raw))
+		if null != nil {
+			t.Errorf("null - expected: nil - received: %v", null)
+		}
+		if !reflect.DeepEqual(value, []byte(text)) {
+			t.Errorf("interface - expected: %v - received: %v", []byte(text), value)
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Fatal("rows error:", err)
+	}
+
+	if !reflect.DeepEqual(names, []string{"row 1", "row 2", "row 3"}) || text != "3" {
+		t.Errorf("names - expected: %v 3 - received: %v %v", []string{"row 1", "row 2", "row 3"}, names, text)
+	}
+}
@@ -0,0 +1,69 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestReadOnly checks a read only connection runs queries and refuses statements that change data or the schema
+func TestReadOnly(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?read_only=true")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	var result int64
+	err := db.QueryRowContext(ctx, "select 1 from dual").Scan(&result)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if result != 1 {
+		t.Errorf("query result - expected: %v - received: %v", 1, result)
+	}
+
+	var readOnlyTests = []struct {
+		query         string
+		statementType string
+	}{
+		{"create table READ_ONLY_" + TestTimeString + " ( A INTEGER )", "CREATE"},
+		{"insert into READ_ONLY_" + TestTimeString + " ( A ) values ( 1 )", "INSERT"},
+		{"update dual set DUMMY = 'Y'", "UPDATE"},
+		{"delete from dual", "DELETE"},
+	}
+	for _, tt := range readOnlyTests {
+		_, err = db.ExecContext(ctx, tt.query)
+		var readOnlyError *ReadOnlyError
+		if !errors.As(err, &readOnlyError) {
+			t.Errorf("exec %v - expected: *ReadOnlyError - received: %v", tt.query, err)
+			continue
+		}
+		if readOnlyError.StatementType != tt.statementType {
+			t.Errorf("exec %v statement type - expected: %v - received: %v", tt.query, tt.statementType, readOnlyError.StatementType)
+		}
+	}
+}
+
+// TestReadOnlyError tests the read only error string
+func TestReadOnlyError(t *testing.T) {
+	t.Parallel()
+
+	err := &ReadOnlyError{StatementType: "INSERT"}
+	expected := "INSERT statements are not allowed on a read only connection"
+	if err.Error() != expected {
+		t.Errorf("ReadOnlyError - expected: %v - received: %v", expected, err.Error())
+	}
+}
@@ -0,0 +1,78 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// TestRefCursorOutBind checks a SYS_REFCURSOR OUT parameter is returned as Rows
+func TestRefCursorOutBind(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	var rows *Rows
+	query := "begin open :1 for select level as A, 'row ' || level as B from dual connect by level <= :2; end;"
+	_, err := TestDB.ExecContext(ctx, query, sql.Out{Dest: &rows}, 3)
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if rows == nil {
+		t.Fatal("rows is nil")
+	}
+
+	columns := rows.Columns()
+	if !reflect.DeepEqual(columns, []string{"A", "B"}) {
+		t.Errorf("columns - expected: %v - received: %v", []string{"A", "B"}, columns)
+	}
+
+	var received [][]interface{}
+	values := make([]driver.Value, len(columns))
+	for {
+		err = rows.Next(values)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rows.Close()
+			t.Fatal("next error:", err)
+		}
+		received = append(received, []interface{}{values[0], values[1]})
+	}
+	err = rows.Close()
+	if err != nil {
+		t.Fatal("close error:", err)
+	}
+
+	expected := [][]interface{}{{float64(1), "row 1"}, {float64(2), "row 2"}, {float64(3), "row 3"}}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("rows - expected: %v - received: %v", expected, received)
+	}
+
+	// a cursor reused for a second call
+	_, err = TestDB.ExecContext(ctx, query, sql.Out{Dest: &rows}, 1)
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	err = rows.Next(values)
+	if err != nil {
+		t.Fatal("next error:", err)
+	}
+	err = rows.Next(values)
+	if err != io.EOF {
+		t.Errorf("next - expected: %v - received: %v", io.EOF, err)
+	}
+	err = rows.Close()
+	if err != nil {
+		t.Fatal("close error:", err)
+	}
+}
@@ -0,0 +1,126 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// maxClientInfoLength is the max length in bytes of CLIENT_INFO
+const maxClientInfoLength = 64
+
+// sanitizeRequestID returns the request ID truncated to maxClientInfoLength bytes, without control characters,
+// and without */, so it can not end the request ID comment
+func sanitizeRequestID(id string) string {
+	id = strings.Map(func(r rune) rune {
+		if r < ' ' || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, id)
+	id = strings.Replace(id, "*/", "* /", -1)
+	for len(id) > maxClientInfoLength {
+		_, size := utf8.DecodeLastRuneInString(id)
+		id = id[:len(id)-size]
+	}
+	return id
+}
+
+// requestIDComment returns the comment that starts the statements prepared with a request ID
+func requestIDComment(id string) string {
+	return "/* request_id=" + id + " */ "
+}
+
+// requestIDCommentLength returns the length of the request ID comment text starts with, 0 when it does not start with one
+func requestIDCommentLength(text string) int {
+	if !strings.HasPrefix(text, "/* request_id=") {
+		return 0
+	}
+	// a sanitized request ID has no */
+	end := strings.Index(text, " */ ")
+	if end < 0 {
+		return 0
+	}
+	return end + len(" */ ")
+}
+
+// setRequestClientInfo sets CLIENT_INFO to the request ID, if any, for the next execute.
+// Returns a function that restores the CLIENT_INFO the session had before the first request ID.
+func (conn *Conn) setRequestClientInfo(requestID string) func() {
+	if requestID == "" {
+		return func() {}
+	}
+
+	// the application can have set CLIENT_INFO with DBMS_APPLICATION_INFO, which the session handle does not know,
+	// so it is read with a round trip once
+	if !conn.priorClientInfoRead {
+		prior, err := conn.clientInfo()
+		if err != nil {
+			conn.logger.Print("get CLIENT_INFO error: ", err)
+			return func() {}
+		}
+		conn.priorClientInfo = prior
+		conn.priorClientInfoRead = true
+	}
+	prior := conn.priorClientInfo
+	err := conn.setClientInfo(requestID)
+	if err != nil {
+		conn.logger.Print("set CLIENT_INFO error: ", err)
+		return func() {}
+	}
+	return func() {
+		err := conn.setClientInfo(prior)
+		if err != nil {
+			conn.logger.Print("restore CLIENT_INFO error: ", err)
+		}
+	}
+}
+
+// clientInfo returns the CLIENT_INFO of the session, with a round trip
+func (conn *Conn) clientInfo() (string, error) {
+	dest := make([]driver.Value, 1)
+	// a context without a request ID, so the query does not set CLIENT_INFO itself
+	err := conn.queryRow(context.Background(), "select sys_context('USERENV', 'CLIENT_INFO') from dual", dest)
+	if err != nil {
+		return "", err
+	}
+	clientInfo, _ := dest[0].(string)
+	return clientInfo, nil
+}
+
+// sessionHandle returns the session handle of the connection
+func (conn *Conn) sessionHandle() (unsafe.Pointer, error) {
+	// the session handle is only allocated by the driver with OCISessionBegin, otherwise get it from the service context
+	session := unsafe.Pointer(conn.usrSession)
+	if session == nil {
+		result := C.OCIAttrGet(
+			unsafe.Pointer(conn.svc), // service context handle
+			C.OCI_HTYPE_SVCCTX,       // handle type
+			unsafe.Pointer(&session), // session handle of the service context
+			nil,                      // size of the attribute value, not needed
+			C.OCI_ATTR_SESSION,       // attribute type
+			conn.errHandle,           // error handle
+		)
+		if result != C.OCI_SUCCESS {
+			return nil, conn.getError(result)
+		}
+	}
+	return session, nil
+}
+
+// setClientInfo sets the CLIENT_INFO attribute of the session, which is sent to the server with the next round trip
+func (conn *Conn) setClientInfo(clientInfo string) error {
+	session, err := conn.sessionHandle()
+	if err != nil {
+		return err
+	}
+
+	text, length := cText(clientInfo, conn.utf16)
+	defer C.free(unsafe.Pointer(text))
+	return conn.ociAttrSet(session, C.OCI_HTYPE_SESSION, unsafe.Pointer(text), length, C.OCI_ATTR_CLIENT_INFO)
+}
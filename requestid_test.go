@@ -0,0 +1,90 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+// TestRequestID checks CLIENT_INFO is set to the request ID of WithRequestID while a statement runs, then restored
+func TestRequestID(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	// the CLIENT_INFO set by the application before the first request ID is restored
+	_, err = conn.ExecContext(ctx, "begin dbms_application_info.set_client_info('application'); end;")
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	defer conn.ExecContext(context.Background(), "begin dbms_application_info.set_client_info(null); end;")
+
+	var clientInfo sql.NullString
+	err = conn.QueryRowContext(WithRequestID(ctx, "request-1"), "select sys_context('USERENV', 'CLIENT_INFO') from dual").Scan(&clientInfo)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if clientInfo.String != "request-1" {
+		t.Errorf("client info - expected: %v - received: %v", "request-1", clientInfo.String)
+	}
+
+	var result int64
+	err = conn.QueryRowContext(WithRequestID(ctx, "request-2"), "select 1 from dual").Scan(&result)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+
+	err = conn.QueryRowContext(ctx, "select sys_context('USERENV', 'CLIENT_INFO') from dual").Scan(&clientInfo)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if clientInfo.String != "application" {
+		t.Errorf("restored client info - expected: %v - received: %v", "application", clientInfo.String)
+	}
+}
+
+// TestSanitizeRequestID tests the request IDs of WithRequestID
+func TestSanitizeRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{id: "", expected: ""},
+		{id: "abc-123", expected: "abc-123"},
+		{id: "a*/b", expected: "a* /b"},
+		{id: "a\nb\tc", expected: "abc"},
+		{id: strings.Repeat("a", 70), expected: strings.Repeat("a", 64)},
+		{id: strings.Repeat("a", 63) + "é", expected: strings.Repeat("a", 63)},
+	}
+
+	for _, test := range tests {
+		id := requestIDFromContext(WithRequestID(context.Background(), test.id))
+		if id != test.expected {
+			t.Errorf("WithRequestID %q - expected: %q - received: %q", test.id, test.expected, id)
+		}
+	}
+
+	if id := requestIDFromContext(context.Background()); id != "" {
+		t.Errorf("requestIDFromContext - expected: %q - received: %q", "", id)
+	}
+
+	for _, text := range []string{"select 1 from dual", requestIDComment("a* /b") + "select 1 from dual"} {
+		length := requestIDCommentLength(text)
+		if text[length:] != "select 1 from dual" {
+			t.Errorf("requestIDCommentLength %q - expected: %q - received: %q", text, "select 1 from dual", text[length:])
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"context"
+	"database/sql/driver"
+	"unsafe"
+)
+
+/*
+Connections implement driver.Validator and driver.SessionResetter, so database/sql does not put a broken connection
+back in its pool, and the session state of one use of a pooled connection does not leak into the next.
+
+IsValid is called each time a connection is returned to the pool. A connection is not valid after a bad connection error,
+otherwise IsValid checks the server with the HealthQuery of the Connector, or OCIPing when not set, at most once a second,
+so a busy connection does not make a round trip each time it is returned.
+
+ResetSession is called before a connection that was used is used again. It commits the statements of the group commit
+that are not committed yet, then rolls back a transaction left in progress, like the uncommitted DML of statements run
+with the driver connection outside a transaction, then runs the ResetStatement of the Connector, if set,
+to clear the state of the session:
+
+	connector.ResetStatement = "begin dbms_session.modify_package_state(dbms_session.reinitialize); end;"
+
+With a HealthQuery, ResetSession then runs it when the connection was not validated in the last second.
+When the commit, the rollback, the reset statement, or the health query fails, the connection is bad, so database/sql uses another one.
+Connections pinned with PinSession keep their session state.
+*/
+
+// IsValid implements driver.Validator, returns false when the connection is closed, had a bad connection error,
+// or fails the health query or ping
+func (conn *Conn) IsValid() bool {
+	if conn.closed || conn.sessionLost {
+		return false
+	}
+
+	timeout := conn.healthTimeout
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := conn.validateEvery(ctx)
+	if err != nil {
+		conn.logger.Print("IsValid error: ", err)
+		conn.sessionLost = true
+		return false
+	}
+	return true
+}
+
+// validateEvery validates the connection when it was not validated in the last validPingInterval
+func (conn *Conn) validateEvery(ctx context.Context) error {
+	now := conn.now()
+	if now.Sub(conn.validated) < validPingInterval {
+		return nil
+	}
+
+	conn.countStat(statValidations)
+	err := conn.validate(ctx)
+	if err != nil {
+		conn.countStat(statValidationErrors)
+		return err
+	}
+	conn.validated = now
+	return nil
+}
+
+// ResetSession implements driver.SessionResetter, rolls back a transaction in progress and runs the reset statement
+// before the connection is used again. Returns driver.ErrBadConn when the connection can not be reset.
+func (conn *Conn) ResetSession(ctx context.Context) error {
+	if conn.closed || conn.sessionLost {
+		return driver.ErrBadConn
+	}
+	if conn.pinned {
+		return nil
+	}
+
+	conn.countStat(statResets)
+	err := conn.resetSession(ctx)
+	if err != nil {
+		conn.logger.Print("reset session error: ", err)
+		conn.countStat(statResetErrors)
+		// the session state is unknown, drop the session instead of returning it to a session pool
+		conn.sessionLost = true
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+// resetSession commits the statements of the group commit, rolls back a transaction in progress,
+// and runs the reset statement
+func (conn *Conn) resetSession(ctx context.Context) error {
+	err := conn.commitPending()
+	if err != nil {
+		return err
+	}
+
+	inProgress := conn.inTransaction
+	if clientMajorVersion() >= 12 {
+		inProgress, err = conn.transactionInProgress()
+		if err != nil {
+			return err
+		}
+	}
+	if inProgress {
+		err = conn.getError(C.OCITransRollback(conn.svc, conn.errHandle, 0))
+		if err != nil {
+			return err
+		}
+	}
+	conn.inTransaction = false
+
+	if conn.resetStatement != "" {
+		_, err = conn.exec(ctx, conn.resetStatement, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conn.healthQuery != "" {
+		return conn.validateEvery(ctx)
+	}
+	return nil
+}
+
+// transactionInProgress returns true when the session has a transaction in progress, needs an Oracle 12c or later client.
+// Does not make a round trip.
+func (conn *Conn) transactionInProgress() (bool, error) {
+	session, err := conn.sessionHandle()
+	if err != nil {
+		return false, err
+	}
+
+	var inProgress C.boolean
+	result := C.OCIAttrGet(
+		session,                            // session handle
+		C.OCI_HTYPE_SESSION,                // handle type
+		unsafe.Pointer(&inProgress),        // attribute value
+		nil,                                // size of the attribute value, not needed
+		C.OCI_ATTR_TRANSACTION_IN_PROGRESS, // attribute type
+		conn.errHandle,                     // error handle
+	)
+	if result != C.OCI_SUCCESS {
+		return false, conn.getError(result)
+	}
+	return inProgress == C.TRUE, nil
+}
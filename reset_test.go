@@ -0,0 +1,179 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+// TestDestructiveResetSessionPending checks ResetSession commits the statements of the group commit not committed yet
+func TestDestructiveResetSessionPending(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "RESET_PENDING_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	db := testGetDB("?group_commit_count=100")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "insert into "+tableName+" ( A ) values ( 1 )")
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+	err = conn.Raw(func(driverConn interface{}) error {
+		if !driverConn.(*Conn).IsValid() {
+			t.Errorf("IsValid - expected: %v - received: %v", true, false)
+		}
+		return driverConn.(*Conn).ResetSession(ctx)
+	})
+	if err != nil {
+		t.Fatal("reset session error:", err)
+	}
+
+	// the count is selected by TestDB, another session, which only sees committed rows
+	var count int64
+	err = TestDB.QueryRowContext(ctx, "select count(1) from "+tableName).Scan(&count)
+	if err != nil {
+		t.Fatal("select count error:", err)
+	}
+	if count != 1 {
+		t.Errorf("committed rows after reset - expected: %v - received: %v", 1, count)
+	}
+}
+
+// TestHealthQuery checks Ping and IsValid run the health query in place of OCIPing
+func TestHealthQuery(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer func() {
+		err := db.Close()
+		if err != nil {
+			t.Fatal("db close error:", err)
+		}
+	}()
+
+	var healthTests = []struct {
+		healthQuery string
+		expected    error
+	}{
+		{"select 1 from dual", nil},
+		{"select 1 from dual where 1 = 0", driver.ErrBadConn},
+		{"select 1 from health_query_no_table", driver.ErrBadConn},
+	}
+
+	for _, tt := range healthTests {
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		conn, err := db.Conn(ctx)
+		cancel()
+		if err != nil {
+			t.Fatal("conn error:", err)
+		}
+
+		err = conn.Raw(func(driverConn interface{}) error {
+			driverConn.(*Conn).healthQuery = tt.healthQuery
+			driverConn.(*Conn).healthTimeout = time.Second
+			if valid := driverConn.(*Conn).IsValid(); valid != (tt.expected == nil) {
+				t.Errorf("IsValid %q - expected: %v - received: %v", tt.healthQuery, tt.expected == nil, valid)
+			}
+			return nil
+		})
+		if err != nil {
+			conn.Close()
+			t.Fatal("raw error:", err)
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+		err = conn.PingContext(ctx)
+		cancel()
+		if err != tt.expected {
+			t.Errorf("ping %q - expected: %v - received: %v", tt.healthQuery, tt.expected, err)
+		}
+		conn.Close()
+	}
+}
+
+// TestResetStatement checks the reset statement of the connector runs before a connection is used again
+func TestResetStatement(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	connector, err := NewConnectorWithOptions(ConnectorDSN(testGetOpenString("")))
+	if err != nil {
+		t.Fatal("NewConnectorWithOptions error:", err)
+	}
+	connector.ResetStatement = "begin dbms_session.set_identifier('oci8_reset'); end;"
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	_, err = db.ExecContext(ctx, "begin dbms_session.set_identifier('oci8_used'); end;")
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+
+	var identifier string
+	err = db.QueryRowContext(ctx, "select sys_context('USERENV', 'CLIENT_IDENTIFIER') from dual").Scan(&identifier)
+	if err != nil {
+		t.Fatal("query row error:", err)
+	}
+	if identifier != "oci8_reset" {
+		t.Errorf("client identifier - expected: %v - received: %v", "oci8_reset", identifier)
+	}
+
+	stats := connector.Stats()
+	if stats.Resets < 1 || stats.ResetErrors != 0 {
+		t.Errorf("resets - expected: at least 1 and 0 errors - received: %v and %v errors", stats.Resets, stats.ResetErrors)
+	}
+}
+
+// TestBadConnIsValid tests closed connections and connections with a bad connection error are not valid and not reset
+func TestBadConnIsValid(t *testing.T) {
+	t.Parallel()
+
+	for _, conn := range []*Conn{{closed: true}, {sessionLost: true}, {sessionLost: true, pinned: true}} {
+		if conn.IsValid() {
+			t.Errorf("IsValid closed %v session lost %v - expected: %v - received: %v", conn.closed, conn.sessionLost, false, true)
+		}
+		if err := conn.ResetSession(context.Background()); err != driver.ErrBadConn {
+			t.Errorf("ResetSession closed %v session lost %v - expected: %v - received: %v", conn.closed, conn.sessionLost, driver.ErrBadConn, err)
+		}
+	}
+}
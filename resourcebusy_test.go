@@ -0,0 +1,97 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDestructiveResourceBusy checks an exec that fails with ORA-00054 is executed again when the ResourceBusy hook retries
+func TestDestructiveResourceBusy(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "RESOURCE_BUSY_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A INTEGER )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	// the uncommitted insert of the transaction makes lock table nowait fail
+	tx, err := TestDB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("begin error:", err)
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, "insert into "+tableName+" ( A ) values ( 1 )")
+	if err != nil {
+		t.Fatal("insert error:", err)
+	}
+
+	var attempts []int
+	connector, err := NewConnectorWithOptions(ConnectorDSN(testGetOpenString("")), ConnectorHooks(Hooks{
+		ResourceBusy: func(ctx context.Context, info ResourceBusyInfo) (bool, time.Duration) {
+			attempts = append(attempts, info.Attempt)
+			if info.Attempt == 2 {
+				// release the lock so the third attempt succeeds
+				err := tx.Rollback()
+				if err != nil {
+					t.Error("rollback error:", err)
+				}
+			}
+			return info.Attempt < 3, 10 * time.Millisecond
+		},
+	}))
+	if err != nil {
+		t.Fatal("NewConnectorWithOptions error:", err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "lock table "+tableName+" in exclusive mode nowait")
+	if err != nil {
+		t.Fatal("lock table error:", err)
+	}
+	if !reflect.DeepEqual(attempts, []int{1, 2}) {
+		t.Errorf("attempts - expected: %v - received: %v", []int{1, 2}, attempts)
+	}
+}
+
+// TestResourceBusyBackoff tests the waits of the resource busy backoff hook
+func TestResourceBusyBackoff(t *testing.T) {
+	t.Parallel()
+
+	hook := ResourceBusyBackoff(5, 100*time.Millisecond, 300*time.Millisecond)
+	var backoffTests = []struct {
+		attempt int
+		retry   bool
+		wait    time.Duration
+	}{
+		{1, true, 100 * time.Millisecond},
+		{2, true, 200 * time.Millisecond},
+		{3, true, 300 * time.Millisecond},
+		{4, true, 300 * time.Millisecond},
+		{5, false, 0},
+	}
+	for _, tt := range backoffTests {
+		retry, wait := hook(context.Background(), ResourceBusyInfo{Attempt: tt.attempt})
+		if retry != tt.retry || wait != tt.wait {
+			t.Errorf("ResourceBusyBackoff attempt %v - expected: %v, %v - received: %v, %v", tt.attempt, tt.retry, tt.wait, retry, wait)
+		}
+	}
+}
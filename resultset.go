@@ -0,0 +1,186 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"errors"
+	"io"
+	"unsafe"
+)
+
+/*
+Implicit results
+
+A PL/SQL block or call that returns cursors with DBMS_SQL.RETURN_RESULT (Oracle 12c or later) is run with Query,
+the rows are the first result set, and NextResultSet moves to the next one:
+
+	rows, err := db.QueryContext(ctx, `declare
+		c1 sys_refcursor;
+		c2 sys_refcursor;
+	begin
+		open c1 for select id, name from customers;
+		dbms_sql.return_result(c1);
+		open c2 for select id, total from orders;
+		dbms_sql.return_result(c2);
+	end;`)
+	...
+	for rows.Next() {
+		// customers
+	}
+	if rows.NextResultSet() {
+		for rows.Next() {
+			// orders
+		}
+	}
+
+The statement handles of the implicit results belong to the statement, they are freed when the statement is closed.
+*/
+
+// implicitResultCount returns the number of implicit results of an executed PL/SQL statement
+func (stmt *Stmt) implicitResultCount() (int, error) {
+	var count C.ub4
+	_, err := stmt.ociAttrGet(unsafe.Pointer(&count), C.OCI_ATTR_IMPLICIT_RESULT_COUNT)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// nextImplicitResult calls OCIStmtGetNextResult then returns a statement of the next implicit result.
+// Returns io.EOF when there are no more implicit results.
+func (stmt *Stmt) nextImplicitResult() (*Stmt, error) {
+	var result unsafe.Pointer
+	var resultType C.ub4
+	returnCode := C.OCIStmtGetNextResult(
+		stmt.stmt,           // statement handle of the executed PL/SQL statement
+		stmt.conn.errHandle, // error handle
+		&result,             // statement handle of the implicit result
+		&resultType,         // type of the implicit result, OCI_RESULT_TYPE_SELECT
+		C.OCI_DEFAULT,       // mode
+	)
+	if returnCode == C.OCI_NO_DATA {
+		return nil, io.EOF
+	}
+	if returnCode != C.OCI_SUCCESS {
+		return nil, stmt.conn.getError(returnCode)
+	}
+	if resultType != C.OCI_RESULT_TYPE_SELECT {
+		return nil, errors.New("implicit result is not a query result")
+	}
+
+	return &Stmt{conn: stmt.conn, stmt: (*C.OCIStmt)(result), ctx: stmt.ctx, releaseMode: C.ub4(C.OCI_DEFAULT)}, nil
+}
+
+// openImplicitResult sets the rows to the next implicit result of the parent statement
+func (rows *Rows) openImplicitResult() error {
+	resultStmt, err := rows.parent.nextImplicitResult()
+	if err != nil {
+		return err
+	}
+	defines, err := resultStmt.makeDefines(nil, false)
+	if err != nil {
+		return err
+	}
+
+	rows.stmt = resultStmt
+	rows.defines = defines
+	rows.rowCount = 0
+	rows.implicitResults--
+	return nil
+}
+
+// HasNextResultSet implements driver.RowsNextResultSet,
+// returns true when the PL/SQL statement returned more implicit results after the current one
+func (rows *Rows) HasNextResultSet() bool {
+	return !rows.closed && rows.implicitResults > 0
+}
+
+// NextResultSet implements driver.RowsNextResultSet, moves to the next implicit result of a PL/SQL statement.
+// Returns io.EOF when there are no more implicit results.
+func (rows *Rows) NextResultSet() error {
+	err := rows.guard.enter("Rows", "NextResultSet", rows.stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return err
+	}
+	defer rows.guard.exit(rows.stmt.conn.debugConcurrentUse)
+
+	if rows.closed || rows.implicitResults < 1 {
+		return io.EOF
+	}
+
+	freeDefines(rows.defines)
+	rows.defines = nil
+	return rows.openImplicitResult()
+}
+
+// implicitResultRows returns the rows of the first implicit result of an executed PL/SQL statement,
+// ok is false when the statement returned no implicit results
+func (stmt *Stmt) implicitResultRows() (*Rows, bool, error) {
+	count, err := stmt.implicitResultCount()
+	if err != nil || count < 1 {
+		return nil, false, err
+	}
+
+	maxRows := maxRowsFromContext(stmt.ctx)
+	if maxRows < 1 {
+		maxRows = stmt.conn.maxRows
+	}
+	rows := &Rows{
+		stmt:            stmt,
+		parent:          stmt,
+		implicitResults: count,
+		maxRows:         maxRows,
+		lobReaders:      lobReadersFromContext(stmt.ctx),
+		rawBytes:        rawBytesFromContext(stmt.ctx),
+	}
+	err = rows.openImplicitResult()
+	if err != nil {
+		return nil, true, err
+	}
+	return rows, true, nil
+}
+
+/*
+Nested cursors
+
+A CURSOR expression column is returned as the rows of the nested cursor of each row, scan it into a *sql.Rows:
+
+	rows, err := db.QueryContext(ctx, "select d.name, cursor(select e.name from employees e where e.department_id = d.id) from departments d")
+	...
+	for rows.Next() {
+		var department string
+		var employees *sql.Rows
+		err = rows.Scan(&department, &employees)
+		...
+		for employees.Next() {
+			...
+		}
+	}
+
+The nested rows are only valid until the next call to rows.Next or rows.Close of the parent rows, which close them.
+*/
+
+// nestedCursorRows returns the rows of the nested cursor of a CURSOR expression column for the current row.
+// The nested rows of the previous row are closed, as the fetch reuses the statement handle of the column.
+func (rows *Rows) nestedCursorRows(define *defineStruct) (*Rows, error) {
+	if define.nestedRows != nil {
+		define.nestedRows.Close()
+		define.nestedRows = nil
+	}
+
+	nestedStmt := &Stmt{conn: rows.stmt.conn, stmt: *(**C.OCIStmt)(define.pbuf), ctx: rows.stmt.ctx, releaseMode: C.ub4(C.OCI_DEFAULT)}
+	defines, err := nestedStmt.makeDefines(nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	define.nestedRows = &Rows{
+		stmt:       nestedStmt,
+		defines:    defines,
+		maxRows:    rows.stmt.conn.maxRows,
+		lobReaders: rows.lobReaders,
+		rawBytes:   rows.rawBytes,
+	}
+	return define.nestedRows, nil
+}
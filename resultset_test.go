@@ -0,0 +1,153 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+// TestImplicitResults checks reading the implicit results of DBMS_SQL.RETURN_RESULT with NextResultSet
+func TestImplicitResults(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	query := `declare
+	c1 sys_refcursor;
+	c2 sys_refcursor;
+begin
+	open c1 for select level from dual connect by level <= 3;
+	dbms_sql.return_result(c1);
+	open c2 for select 'a' as A, 'b' as B from dual;
+	dbms_sql.return_result(c2);
+end;`
+
+	rows, err := TestDB.QueryContext(ctx, query)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	var levels []int64
+	for rows.Next() {
+		var level int64
+		err = rows.Scan(&level)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		levels = append(levels, level)
+	}
+	if !reflect.DeepEqual(levels, []int64{1, 2, 3}) {
+		t.Errorf("first result set - expected: %v - received: %v", []int64{1, 2, 3}, levels)
+	}
+
+	if !rows.NextResultSet() {
+		t.Fatal("NextResultSet - expected: true - received: false", rows.Err())
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal("columns error:", err)
+	}
+	if !reflect.DeepEqual(columns, []string{"A", "B"}) {
+		t.Errorf("columns - expected: %v - received: %v", []string{"A", "B"}, columns)
+	}
+	var a, b string
+	count := 0
+	for rows.Next() {
+		err = rows.Scan(&a, &b)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		count++
+	}
+	if count != 1 || a != "a" || b != "b" {
+		t.Errorf("second result set - expected: 1 a b - received: %v %v %v", count, a, b)
+	}
+
+	if rows.NextResultSet() {
+		t.Error("NextResultSet - expected: false - received: true")
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Error("rows error:", err)
+	}
+
+	// a PL/SQL block without implicit results has no rows
+	rows, err = TestDB.QueryContext(ctx, "begin null; end;")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if rows.Next() || rows.NextResultSet() {
+		t.Error("no implicit results - expected: no rows - received: rows")
+	}
+	rows.Close()
+}
+
+// TestNestedCursorRows checks reading the nested cursor of a CURSOR expression for each row
+func TestNestedCursorRows(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	rows, err := TestDB.QueryContext(ctx, "select p.n, cursor(select p.n * 10 + level from dual connect by level <= p.n) from (select level as n from dual connect by level <= 3) p order by p.n")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	var previous *sql.Rows
+	var parents []int64
+	var children [][]int64
+	for rows.Next() {
+		var parent int64
+		var nested *sql.Rows
+		err = rows.Scan(&parent, &nested)
+		if err != nil {
+			t.Fatal("scan error:", err)
+		}
+		if previous != nil && previous.Next() {
+			t.Error("previous nested rows - expected: closed - received: next row")
+		}
+
+		var values []int64
+		for nested.Next() {
+			var value int64
+			err = nested.Scan(&value)
+			if err != nil {
+				t.Fatal("nested scan error:", err)
+			}
+			values = append(values, value)
+		}
+		err = nested.Err()
+		if err != nil {
+			t.Fatal("nested rows error:", err)
+		}
+
+		parents = append(parents, parent)
+		children = append(children, values)
+		previous = nested
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Fatal("rows error:", err)
+	}
+
+	if !reflect.DeepEqual(parents, []int64{1, 2, 3}) {
+		t.Errorf("parents - expected: %v - received: %v", []int64{1, 2, 3}, parents)
+	}
+	expected := [][]int64{{11}, {21, 22}, {31, 32, 33}}
+	if !reflect.DeepEqual(children, expected) {
+		t.Errorf("children - expected: %v - received: %v", expected, children)
+	}
+}
@@ -9,21 +9,35 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
-	"time"
 	"unsafe"
 )
 
 // Close closes rows
 func (rows *Rows) Close() error {
+	err := rows.guard.enter("Rows", "Close", rows.stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return err
+	}
+	defer rows.guard.exit(rows.stmt.conn.debugConcurrentUse)
+
 	if rows.closed {
 		return nil
 	}
 
 	rows.closed = true
+	if rows.cancel != nil {
+		rows.cancel()
+	}
 
 	freeDefines(rows.defines)
 
+	if rows.cursor {
+		ociHandleFree(unsafe.Pointer(rows.stmt.stmt), C.OCI_HTYPE_STMT)
+		rows.stmt.stmt = nil
+	}
+
 	return nil
 }
 
@@ -38,17 +52,43 @@ func (rows *Rows) Columns() []string {
 
 // Next gets next row
 func (rows *Rows) Next(dest []driver.Value) error {
+	err := rows.guard.enter("Rows", "Next", rows.stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return err
+	}
+	defer rows.guard.exit(rows.stmt.conn.debugConcurrentUse)
+
 	if rows.closed {
-		return nil
+		// like the rows of a nested cursor closed when the parent rows moved to the next row
+		return io.EOF
 	}
 
 	if rows.stmt.ctx.Err() != nil {
 		return rows.stmt.ctx.Err()
 	}
 
-	done := make(chan struct{})
-	defer close(done)
-	go rows.stmt.conn.ociBreakDone(rows.stmt.ctx, done)
+	stop := rows.stmt.conn.watchBreak(rows.stmt.ctx)
+	defer stop()
+	err = rows.fetchNext()
+	if err != nil {
+		return err
+	}
+
+	return rows.values(dest)
+}
+
+// fetchNext fetches the next row into the define buffers. Returns io.EOF when there are no more rows,
+// and ErrTooManyRows when the row is past the max rows limit.
+// The caller is responsible for calling OCIBreak when the context is done.
+func (rows *Rows) fetchNext() error {
+	if rows.fetchArraySize > 1 {
+		err := rows.fetchArrayRow()
+		if err != nil {
+			return err
+		}
+		return rows.countRow()
+	}
+
 	result := C.OCIStmtFetch2(
 		rows.stmt.stmt,
 		rows.stmt.conn.errHandle,
@@ -56,13 +96,91 @@ func (rows *Rows) Next(dest []driver.Value) error {
 		C.OCI_FETCH_NEXT,
 		0,
 		C.OCI_DEFAULT)
+	if result == C.OCI_NEED_DATA {
+		var err error
+		result, err = rows.fetchLongPieces()
+		if err != nil {
+			return err
+		}
+	}
 	if result == C.OCI_NO_DATA {
 		return io.EOF
 	} else if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
 		return rows.stmt.conn.getError(result)
 	}
 
+	return rows.countRow()
+}
+
+// countRow counts the fetched row, returns ErrTooManyRows when the row is past the max rows limit
+func (rows *Rows) countRow() error {
+	rows.rowCount++
+	if rows.maxRows > 0 && rows.rowCount > rows.maxRows {
+		return ErrTooManyRows
+	}
+	if rows.rowCount == 1 && rows.prefetchTarget > 0 {
+		rows.tunePrefetch()
+	}
+	return nil
+}
+
+// tunePrefetch sets the prefetch rows of the next round trips to the number of rows of the width of the fetched row
+// that fit in the prefetch target, and removes the prefetch memory limit
+func (rows *Rows) tunePrefetch() {
+	prefetchRows := rows.prefetchTarget / rows.rowWidth()
+	if prefetchRows < 1 {
+		prefetchRows = 1
+	} else if prefetchRows > maxTunedPrefetchRows {
+		prefetchRows = maxTunedPrefetchRows
+	}
+
+	conn := rows.stmt.conn
+	prefetchRowsUb4 := C.ub4(prefetchRows)
+	err := conn.ociAttrSet(unsafe.Pointer(rows.stmt.stmt), C.OCI_HTYPE_STMT, unsafe.Pointer(&prefetchRowsUb4), 0, C.OCI_ATTR_PREFETCH_ROWS)
+	if err != nil {
+		conn.logger.Print("tune prefetch rows error: ", err)
+		return
+	}
+	prefetchMemory := C.ub4(0)
+	err = conn.ociAttrSet(unsafe.Pointer(rows.stmt.stmt), C.OCI_HTYPE_STMT, unsafe.Pointer(&prefetchMemory), 0, C.OCI_ATTR_PREFETCH_MEMORY)
+	if err != nil {
+		conn.logger.Print("tune prefetch memory error: ", err)
+	}
+}
+
+// rowWidth returns the bytes of the fetched row in the define buffers, the lengths of the values of variable length columns,
+// and at least 1
+func (rows *Rows) rowWidth() int64 {
+	width := int64(1)
+	for i := range rows.defines {
+		define := &rows.defines[i]
+		switch {
+		case define.skip || *define.indicator == -1:
+			// only the indicator
+		case define.length != nil && *define.length > 0:
+			width += int64(*define.length)
+		default:
+			width += int64(define.maxSize)
+		}
+	}
+	return width
+}
+
+// values converts the fetched row in the define buffers into dest
+func (rows *Rows) values(dest []driver.Value) error {
 	for i := range dest {
+		if rows.defines[i].skip {
+			dest[i] = nil
+			continue
+		}
+		if rows.defines[i].piecewise {
+			if *rows.defines[i].indicator == -1 { // Null
+				dest[i] = nil
+			} else {
+				dest[i] = rows.defines[i].pieceTotal
+			}
+			continue
+		}
 		if *rows.defines[i].indicator == -1 { // Null
 			dest[i] = nil
 			continue
@@ -75,21 +193,16 @@ func (rows *Rows) Next(dest []driver.Value) error {
 		// SQLT_DAT
 		case C.SQLT_DAT: // for test, date are return as timestamp
 			buf := (*[1 << 30]byte)(rows.defines[i].pbuf)[0:*rows.defines[i].length]
-			// TODO: Handle BCE dates (http://docs.oracle.com/cd/B12037_01/appdev.101/b10779/oci03typ.htm#438305)
 			// TODO: Handle timezones (http://docs.oracle.com/cd/B12037_01/appdev.101/b10779/oci03typ.htm#443601)
-			dest[i] = time.Date(
-				(int(buf[0])-100)*100+(int(buf[1])-100),
-				time.Month(int(buf[2])),
-				int(buf[3]),
-				int(buf[4])-1,
-				int(buf[5])-1,
-				int(buf[6])-1,
-				0,
-				rows.stmt.conn.timeLocation)
+			dest[i] = decodeDate(buf, rows.stmt.conn.timeLocation)
 
 		// SQLT_BLOB and SQLT_CLOB
 		case C.SQLT_BLOB, C.SQLT_CLOB:
 			lobLocator := (**C.OCILobLocator)(rows.defines[i].pbuf)
+			if rows.lobReaders {
+				dest[i] = rows.newLob(*lobLocator, &rows.defines[i])
+				continue
+			}
 			buffer, err := rows.stmt.conn.ociLobRead(*lobLocator, C.SQLCS_IMPLICIT)
 			if err != nil {
 				return err
@@ -104,7 +217,19 @@ func (rows *Rows) Next(dest []driver.Value) error {
 
 		// SQLT_CHR, SQLT_STR, SQLT_AFC, SQLT_AVC, and SQLT_LNG
 		case C.SQLT_CHR, C.SQLT_STR, C.SQLT_AFC, C.SQLT_AVC, C.SQLT_LNG:
-			dest[i] = C.GoStringN((*C.char)(rows.defines[i].pbuf), C.int(*rows.defines[i].length))
+			switch {
+			case !rows.rawBytes:
+				dest[i] = rows.stmt.conn.goText((*C.OraText)(rows.defines[i].pbuf), int(*rows.defines[i].length))
+				if rows.yesNoColumn(i) {
+					dest[i] = yesNoValue(dest[i].(string))
+				}
+			case rows.stmt.conn.utf16:
+				dest[i] = []byte(rows.stmt.conn.goText((*C.OraText)(rows.defines[i].pbuf), int(*rows.defines[i].length)))
+			default:
+				// the define buffer, valid until the next fetch, see WithRawBytes
+				length := int(*rows.defines[i].length)
+				dest[i] = (*[1 << 30]byte)(rows.defines[i].pbuf)[:length:length]
+			}
 
 		// SQLT_BIN
 		case C.SQLT_BIN: // RAW
@@ -112,9 +237,13 @@ func (rows *Rows) Next(dest []driver.Value) error {
 			dest[i] = buf
 
 		// SQLT_NUM
-		case C.SQLT_NUM: // NUMBER
-			buf := (*[21]byte)(rows.defines[i].pbuf)[0:*rows.defines[i].length]
-			dest[i] = buf
+		case C.SQLT_NUM: // NUMBER, with number strings
+			buf := (*[22]byte)(rows.defines[i].pbuf)[0:*rows.defines[i].length]
+			number, err := decodeNumber(buf)
+			if err != nil {
+				return fmt.Errorf("decode number for column %v - error: %v", i, err)
+			}
+			dest[i] = number
 
 		// SQLT_VNU
 		case C.SQLT_VNU: // VARNUM
@@ -155,66 +284,48 @@ func (rows *Rows) Next(dest []driver.Value) error {
 			if err != nil {
 				return fmt.Errorf("ociDateTimeToTime for column %v - error: %v", i, err)
 			}
-			dest[i] = *aTime
+			dest[i] = rows.stmt.conn.localTimeZoneTime(&rows.defines[i], *aTime)
 
 		// SQLT_INTERVAL_DS
 		case C.SQLT_INTERVAL_DS:
-			var days C.sb4
-			var hours C.sb4
-			var minutes C.sb4
-			var seconds C.sb4
-			var fracSeconds C.sb4
-			interval := *(**C.OCIInterval)(rows.defines[i].pbuf)
-			result = C.OCIIntervalGetDaySecond(
-				unsafe.Pointer(rows.stmt.conn.env), // environment handle
-				rows.stmt.conn.errHandle,           // error handle
-				&days,                              // days
-				&hours,                             // hours
-				&minutes,                           // minutes
-				&seconds,                           // seconds
-				&fracSeconds,                       // fractional seconds
-				interval,                           // interval
-			)
-			if result != C.OCI_SUCCESS {
-				return rows.stmt.conn.getError(result)
+			var err error
+			dest[i], err = rows.stmt.conn.intervalDSValue(*(**C.OCIInterval)(rows.defines[i].pbuf))
+			if err != nil {
+				return err
 			}
 
-			dest[i] = (int64(days) * 24 * int64(time.Hour)) + (int64(hours) * int64(time.Hour)) +
-				(int64(minutes) * int64(time.Minute)) + (int64(seconds) * int64(time.Second)) + int64(fracSeconds)
-
 		// SQLT_INTERVAL_YM
 		case C.SQLT_INTERVAL_YM:
-			var years C.sb4
-			var months C.sb4
-			interval := *(**C.OCIInterval)(rows.defines[i].pbuf)
-			result = C.OCIIntervalGetYearMonth(
-				unsafe.Pointer(rows.stmt.conn.env), // environment handle
-				rows.stmt.conn.errHandle,           // error handle
-				&years,                             // year
-				&months,                            // month
-				interval,                           // interval
-			)
-			if result != C.OCI_SUCCESS {
-				return rows.stmt.conn.getError(result)
+			var err error
+			dest[i], err = rows.stmt.conn.intervalYMValue(*(**C.OCIInterval)(rows.defines[i].pbuf))
+			if err != nil {
+				return err
 			}
-			dest[i] = (int64(years) * 12) + int64(months)
 
-		// SQLT_RSET - ref cursor
-		case C.SQLT_RSET:
-			stmtP := (**C.OCIStmt)(rows.defines[i].pbuf)
-			subStmt := &Stmt{conn: rows.stmt.conn, stmt: *stmtP, ctx: rows.stmt.ctx, releaseMode: C.ub4(C.OCI_DEFAULT)}
-			if rows.defines[i].subDefines == nil {
-				var err error
-				rows.defines[i].subDefines, err = subStmt.makeDefines()
-				if err != nil {
-					return err
-				}
+		// SQLT_VEC - VECTOR
+		case C.SQLT_VEC:
+			vector := *(**C.OCIVector)(rows.defines[i].pbuf)
+			var err error
+			dest[i], err = rows.stmt.conn.ociVectorToSlice(vector)
+			if err != nil {
+				return err
+			}
+
+		// SQLT_NTY - SDO_GEOMETRY object
+		case C.SQLT_NTY:
+			var err error
+			dest[i], err = rows.stmt.conn.sdoGeometryValue(&rows.defines[i])
+			if err != nil {
+				return err
 			}
-			subRows := &Rows{
-				stmt:    subStmt,
-				defines: rows.defines[i].subDefines,
+
+		// SQLT_RSET - nested cursor of a CURSOR expression
+		case C.SQLT_RSET:
+			nestedRows, err := rows.nestedCursorRows(&rows.defines[i])
+			if err != nil {
+				return err
 			}
-			dest[i] = subRows
+			dest[i] = nestedRows
 
 		// default
 		default:
@@ -223,120 +334,312 @@ func (rows *Rows) Next(dest []driver.Value) error {
 		}
 	}
 
+	if rows.stmt.conn.converter != nil {
+		return rows.convertValues(dest)
+	}
 	return nil
 }
 
+// fetchLongPieces completes a fetch that returned OCI_NEED_DATA by fetching the piecewise columns a piece at a time,
+// passing each piece to the long pieces function. Returns the result of the last fetch.
+func (rows *Rows) fetchLongPieces() (C.sword, error) {
+	for i := 0; i < len(rows.defines); i++ {
+		rows.defines[i].pieceTotal = 0
+	}
+
+	// OCI keeps the addresses of the buffer, length, and indicator across the fetch calls, so they are C memory.
+	// The indicator is the one of the define.
+	pieceBuffer := C.malloc(longPieceSize)
+	defer C.free(pieceBuffer)
+	pieceLength := (*C.ub4)(C.malloc(C.sizeof_ub4))
+	defer C.free(unsafe.Pointer(pieceLength))
+
+	result := C.sword(C.OCI_NEED_DATA)
+	for result == C.OCI_NEED_DATA {
+		var handle unsafe.Pointer
+		var handleType C.ub4
+		var inOut C.ub1
+		var iteration C.ub4
+		var index C.ub4
+		var piece C.ub1
+		result = C.OCIStmtGetPieceInfo(
+			rows.stmt.stmt,           // statement handle
+			rows.stmt.conn.errHandle, // error handle
+			&handle,                  // returns a pointer to the define handle that needs data
+			&handleType,              // returns the handle type, OCI_HTYPE_DEFINE
+			&inOut,                   // returns OCI_PARAM_OUT for a define
+			&iteration,               // returns the row number
+			&index,                   // returns the array index
+			&piece,                   // returns the piece, OCI_FIRST_PIECE, OCI_NEXT_PIECE, or OCI_LAST_PIECE
+		)
+		if result != C.OCI_SUCCESS {
+			return result, nil
+		}
+
+		column := -1
+		for i := 0; i < len(rows.defines); i++ {
+			if unsafe.Pointer(rows.defines[i].defineHandle) == handle {
+				column = i
+				break
+			}
+		}
+		if column < 0 {
+			rows.cancelFetch()
+			return C.OCI_ERROR, fmt.Errorf("piecewise fetch for unknown define handle")
+		}
+
+		*pieceLength = longPieceSize
+		indicator := unsafe.Pointer(rows.defines[column].indicator)
+		result = C.OCIStmtSetPieceInfo(
+			handle,                   // define handle
+			handleType,               // handle type
+			rows.stmt.conn.errHandle, // error handle
+			pieceBuffer,              // buffer the next piece is fetched into
+			pieceLength,              // IN: size of the buffer, OUT: length of the piece fetched
+			piece,                    // the piece from OCIStmtGetPieceInfo
+			indicator,                // indicator of the column
+			nil,                      // column-level return code
+		)
+		if result != C.OCI_SUCCESS {
+			return result, nil
+		}
+
+		result = C.OCIStmtFetch2(
+			rows.stmt.stmt,
+			rows.stmt.conn.errHandle,
+			1,
+			C.OCI_FETCH_NEXT,
+			0,
+			C.OCI_DEFAULT)
+		if result != C.OCI_NEED_DATA && result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
+			return result, nil
+		}
+
+		if *pieceLength > 0 {
+			length := *pieceLength
+			rows.defines[column].pieceTotal += int64(length)
+			err := rows.longPieces(column, (*[1 << 30]byte)(pieceBuffer)[0:length:length])
+			if err != nil {
+				rows.cancelFetch()
+				return C.OCI_ERROR, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// cancelFetch cancels the cursor, used when a piecewise fetch is stopped part way through a row
+func (rows *Rows) cancelFetch() {
+	// fetching zero rows cancels the cursor
+	C.OCIStmtFetch2(
+		rows.stmt.stmt,
+		rows.stmt.conn.errHandle,
+		0,
+		C.OCI_FETCH_NEXT,
+		0,
+		C.OCI_DEFAULT)
+}
+
 // ColumnTypeDatabaseTypeName implement RowsColumnTypeDatabaseTypeName.
+// Returns the Oracle type name of the column without the length, precision, or scale, like VARCHAR2, NUMBER,
+// or TIMESTAMP WITH TIME ZONE, and the object type name for object columns, like SDO_GEOMETRY.
 func (rows *Rows) ColumnTypeDatabaseTypeName(i int) string {
 	if len(rows.defines) < i+1 {
 		return ""
 	}
 
-	switch rows.defines[i].dataType {
-	case C.SQLT_CHR:
-		return "SQLT_CHR"
+	define := &rows.defines[i]
+	nchar := define.charsetForm == C.SQLCS_NCHAR
+	switch define.columnType {
+	case C.SQLT_CHR, C.SQLT_VCS:
+		if nchar {
+			return "NVARCHAR2"
+		}
+		return "VARCHAR2"
+	case C.SQLT_AFC, C.SQLT_AVC:
+		if nchar {
+			return "NCHAR"
+		}
+		return "CHAR"
 	case C.SQLT_NUM:
-		return "SQLT_NUM"
+		if define.precision != 0 && define.scale == -127 {
+			return "FLOAT"
+		}
+		return "NUMBER"
 	case C.SQLT_INT:
-		return "SQLT_INT"
-	case C.SQLT_FLT:
-		return "SQLT_FLT"
-	case C.SQLT_STR:
-		return "SQLT_STR"
-	case C.SQLT_VNU:
-		return "SQLT_VNU"
+		return "NUMBER"
+	case C.SQLT_BFLOAT, C.SQLT_IBFLOAT:
+		return "BINARY_FLOAT"
+	case C.SQLT_BDOUBLE, C.SQLT_IBDOUBLE:
+		return "BINARY_DOUBLE"
 	case C.SQLT_LNG:
-		return "SQLT_LNG"
-	case C.SQLT_VCS:
-		return "SQLT_VCS"
-	case C.SQLT_DAT:
-		return "SQLT_DAT"
-	case C.SQLT_VBI:
-		return "SQLT_VBI"
-	case C.SQLT_BFLOAT:
-		return "SQLT_BFLOAT"
-	case C.SQLT_BDOUBLE:
-		return "SQLT_BDOUBLE"
+		return "LONG"
 	case C.SQLT_BIN:
-		return "SQLT_BIN"
+		return "RAW"
 	case C.SQLT_LBI:
-		return "SQLT_LBI"
-	case C.SQLT_UIN:
-		return "SQLT_UIN"
-	case C.SQLT_LVC:
-		return "SQLT_LVC"
-	case C.SQLT_LVB:
-		return "SQLT_LVB"
-	case C.SQLT_AFC:
-		return "SQLT_AFC"
-	case C.SQLT_AVC:
-		return "SQLT_AVC"
-	case C.SQLT_RDD:
-		return "SQLT_RDD"
-	case C.SQLT_NTY:
-		return "SQLT_NTY"
-	case C.SQLT_REF:
-		return "SQLT_REF"
-	case C.SQLT_CLOB:
-		return "SQLT_CLOB"
-	case C.SQLT_BLOB:
-		return "SQLT_BLOB"
-	case C.SQLT_FILE:
-		return "SQLT_FILE"
-	case C.SQLT_VST:
-		return "SQLT_VST"
-	case C.SQLT_ODT:
-		return "SQLT_ODT"
-	case C.SQLT_DATE:
-		return "SQLT_DATE"
+		return "LONG RAW"
+	case C.SQLT_DAT:
+		return "DATE"
 	case C.SQLT_TIMESTAMP:
-		return "SQLT_TIMESTAMP"
+		return "TIMESTAMP"
 	case C.SQLT_TIMESTAMP_TZ:
-		return "SQLT_TIMESTAMP_TZ"
+		return "TIMESTAMP WITH TIME ZONE"
+	case C.SQLT_TIMESTAMP_LTZ:
+		return "TIMESTAMP WITH LOCAL TIME ZONE"
 	case C.SQLT_INTERVAL_YM:
-		return "SQLT_INTERVAL_YM"
+		return "INTERVAL YEAR TO MONTH"
 	case C.SQLT_INTERVAL_DS:
-		return "SQLT_INTERVAL_DS"
-	case C.SQLT_TIMESTAMP_LTZ:
-		return "SQLT_TIMESTAMP_LTZ"
+		return "INTERVAL DAY TO SECOND"
+	case C.SQLT_CLOB:
+		if nchar {
+			return "NCLOB"
+		}
+		return "CLOB"
+	case C.SQLT_BLOB:
+		return "BLOB"
+	case C.SQLT_BFILE:
+		return "BFILE"
+	case C.SQLT_RDD:
+		return "ROWID"
+	case C.SQLT_RSET:
+		return "REF CURSOR"
+	case C.SQLT_JSON:
+		return "JSON"
+	case C.SQLT_VEC:
+		return "VECTOR"
+	case C.SQLT_NTY:
+		return define.typeName
 	}
 	return ""
 }
 
-// ColumnTypeLength is returning OCI_ATTR_DATA_SIZE, which is max data size in bytes.
-// Note this is not returing length of the column type, like the 20 in FLOAT(20), which is what is normally expected.
-// TODO: Should / can it be changed to return length of the column type?
+// ColumnTypeLength implement RowsColumnTypeLength.
+// Returns the max length in characters of CHAR, VARCHAR2, NCHAR, and NVARCHAR2 columns, in bytes of RAW columns,
+// and math.MaxInt64 for LOB and LONG columns. ok is false for columns that are not variable length.
 func (rows *Rows) ColumnTypeLength(i int) (int64, bool) {
 	if len(rows.defines) < i+1 {
 		return 0, false
 	}
 
-	if rows.defines[i].dataType == C.SQLT_AFC {
-		return int64(rows.defines[i].maxSize / 2), true
+	define := &rows.defines[i]
+	switch define.columnType {
+	case C.SQLT_AFC, C.SQLT_CHR, C.SQLT_VCS, C.SQLT_AVC:
+		if define.charSize > 0 {
+			return int64(define.charSize), true
+		}
+		return int64(define.dataSize), true
+	case C.SQLT_BIN:
+		return int64(define.dataSize), true
+	case C.SQLT_CLOB, C.SQLT_BLOB, C.SQLT_LNG, C.SQLT_LBI:
+		return math.MaxInt64, true
+	}
+	return 0, false
+}
+
+// ColumnTypeNullable implement RowsColumnTypeNullable.
+// Expressions and columns of views can be reported as nullable even when they never return null.
+func (rows *Rows) ColumnTypeNullable(i int) (nullable bool, ok bool) {
+	if len(rows.defines) < i+1 {
+		return false, false
+	}
+	return rows.defines[i].nullable, true
+}
+
+// ColumnTypePrecisionScale implement RowsColumnTypePrecisionScale.
+// Returns the precision and scale of NUMBER columns declared with a precision, like 10 and 2 for NUMBER(10,2).
+// FLOAT columns return their binary precision and a scale of 0. ok is false for NUMBER without a precision
+// and for other types.
+func (rows *Rows) ColumnTypePrecisionScale(i int) (precision int64, scale int64, ok bool) {
+	if len(rows.defines) < i+1 {
+		return 0, 0, false
 	}
-	return int64(rows.defines[i].maxSize), true
+
+	define := &rows.defines[i]
+	if define.columnType != C.SQLT_NUM || define.precision == 0 {
+		return 0, 0, false
+	}
+	if define.scale == -127 {
+		return int64(define.precision), 0, true
+	}
+	return int64(define.precision), int64(define.scale), true
 }
 
 // ColumnTypeScanType implement RowsColumnTypeScanType.
+// Returns the Go type the values of the column are returned as, before any Converter of the connection:
+// string, int64, float64, time.Time, []byte, []float32 or []float64 for VECTOR, and *sql.Rows for cursors.
+// LOB columns are *Lob for queries made with WithLobReaders.
+// NUMBER columns with a precision and a scale of 0, like INTEGER and NUMBER(10), are int64, other NUMBER columns are float64.
+// With number_strings, NUMBER and FLOAT columns are string.
+// INTERVAL columns are time.Duration for DAY TO SECOND and YearMonth for YEAR TO MONTH,
+// or int64 nanoseconds and months with interval_int64.
+// With Connector.YesNoBools, one character text columns are bool.
 func (rows *Rows) ColumnTypeScanType(i int) reflect.Type {
 	if len(rows.defines) < i+1 {
 		return typeNil
 	}
 
-	switch rows.defines[i].dataType {
-	case C.SQLT_AFC, C.SQLT_CHR, C.SQLT_VCS, C.SQLT_AVC, C.SQLT_CLOB, C.SQLT_RDD:
+	define := &rows.defines[i]
+	if define.piecewise {
+		// the pieces are passed to the long pieces function, the value is the total number of bytes
+		return typeInt64
+	}
+
+	if rows.lobReaders && (define.columnType == C.SQLT_CLOB || define.columnType == C.SQLT_BLOB) {
+		return typeLob
+	}
+
+	switch define.columnType {
+	case C.SQLT_AFC, C.SQLT_CHR, C.SQLT_VCS, C.SQLT_AVC, C.SQLT_LNG:
+		if rows.rawBytes {
+			return typeSliceByte
+		}
+		if rows.yesNoColumn(i) {
+			return typeBool
+		}
+		return typeString
+	case C.SQLT_CLOB, C.SQLT_RDD:
 		return typeString
 	case C.SQLT_BIN, C.SQLT_BLOB:
 		return typeSliceByte
+	case C.SQLT_NUM:
+		if define.dataType == C.SQLT_NUM {
+			// number strings
+			return typeString
+		}
+		if (define.precision == 0 && define.scale == 0) || define.scale > 0 || define.scale == -127 {
+			return typeFloat64
+		}
+		return typeInt64
 	case C.SQLT_INT:
 		return typeInt64
-	case C.SQLT_BDOUBLE, C.SQLT_IBDOUBLE, C.SQLT_BFLOAT, C.SQLT_IBFLOAT, C.SQLT_NUM:
+	case C.SQLT_BDOUBLE, C.SQLT_IBDOUBLE, C.SQLT_BFLOAT, C.SQLT_IBFLOAT:
 		return typeFloat64
 	case C.SQLT_TIMESTAMP, C.SQLT_DAT, C.SQLT_TIMESTAMP_TZ, C.SQLT_TIMESTAMP_LTZ:
 		return typeTime
-	case C.SQLT_INTERVAL_DS, C.SQLT_INTERVAL_YM:
-		return typeInt64
+	case C.SQLT_INTERVAL_DS:
+		if rows.stmt.conn.intervalInt64 {
+			return typeInt64
+		}
+		return typeDuration
+	case C.SQLT_INTERVAL_YM:
+		if rows.stmt.conn.intervalInt64 {
+			return typeInt64
+		}
+		return typeYearMonth
+	case C.SQLT_VEC:
+		if define.vectorFormat == C.OCI_ATTR_VECTOR_FORMAT_FLOAT64 {
+			return typeFloat64s
+		}
+		return typeFloat32s
+	case C.SQLT_RSET:
+		return typeSQLRows
+	case C.SQLT_NTY:
+		if define.dataType == C.SQLT_NTY {
+			return typeGeometry
+		}
 	}
 
-	return typeNil
+	// other types, like LONG RAW and JSON, are defined as character data
+	return typeString
 }
@@ -0,0 +1,112 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type (
+	// SavepointBatchOptions are the options of ExecSavepointBatch
+	SavepointBatchOptions struct {
+		// ChunkSize is the number of rows executed after each savepoint, as one array execute, defaults to 100
+		ChunkSize int
+		// Savepoint is the savepoint name, it must be a valid unquoted identifier. Defaults to OCI8_BATCH.
+		Savepoint string
+		// RetryRows keeps the rows of a failed chunk that succeeded, so only the failing rows are skipped,
+		// instead of rolling back the whole chunk
+		RetryRows bool
+	}
+
+	// SavepointBatchResult is the summary of ExecSavepointBatch
+	SavepointBatchResult struct {
+		// RowsAffected is the total rows affected by the rows that succeeded
+		RowsAffected int64
+		// Succeeded is the number of rows that succeeded
+		Succeeded int
+		// Failures are the failed chunks, or rows when RetryRows is set, in row order
+		Failures []BatchFailure
+	}
+
+	// BatchFailure is a failed chunk or row of ExecSavepointBatch, its rows were rolled back
+	BatchFailure struct {
+		// Start is the index of the first failed row
+		Start int
+		// End is the index after the last failed row, so the failed rows are rows[Start:End]
+		End int
+		// Err is the error of the failed chunk or row, a *BatchError for a chunk with failed rows
+		Err error
+	}
+)
+
+const defaultSavepointBatchChunkSize = 100
+
+// ExecSavepointBatch executes query once for each row of args, in chunks that each start with a savepoint.
+// Each chunk is executed as a Batch, with one array execute with OCI_BATCH_ERRORS, so the rows and their column types
+// follow the rules of Batch. When rows of a chunk fail, the chunk is rolled back to its savepoint, the failure is recorded,
+// and the next chunk is executed, so a few bad rows do not fail the whole load.
+// With RetryRows only the failing rows of a chunk are recorded, the rows of the chunk that succeeded are kept.
+// The savepoints only work in a transaction, so execer should be a *sql.Tx of an oci8 connection,
+// that is committed or rolled back by the caller.
+// The returned error is for failures that stop the batch, like the context being done or a savepoint error,
+// the transaction should then be rolled back.
+func ExecSavepointBatch(ctx context.Context, execer Execer, query string, rows [][]interface{}, options SavepointBatchOptions) (SavepointBatchResult, error) {
+	var result SavepointBatchResult
+
+	chunkSize := options.ChunkSize
+	if chunkSize < 1 {
+		chunkSize = defaultSavepointBatchChunkSize
+	}
+	savepoint := options.Savepoint
+	if savepoint == "" {
+		savepoint = "OCI8_BATCH"
+	}
+	err := ValidateIdentifier(savepoint)
+	if err != nil {
+		return result, err
+	}
+
+	for start := 0; start < len(rows); start += chunkSize {
+		end := start + chunkSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		_, err = execer.ExecContext(ctx, "savepoint "+savepoint)
+		if err != nil {
+			return result, err
+		}
+
+		// the rows that succeeded are kept by the batch, the chunk is rolled back to the savepoint here
+		var chunkResult sql.Result
+		chunkResult, err = execer.ExecContext(ctx, query, Batch{Rows: rows[start:end], ContinueOnError: true, ChunkSize: end - start})
+		if err == nil {
+			rowsAffected, _ := chunkResult.RowsAffected()
+			result.RowsAffected += rowsAffected
+			result.Succeeded += end - start
+			continue
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		var batchError *BatchError
+		if options.RetryRows && errors.As(err, &batchError) {
+			// a failed row is rolled back by the database, the rows that succeeded are kept
+			for _, rowError := range batchError.Rows {
+				result.Failures = append(result.Failures, BatchFailure{Start: start + rowError.Row, End: start + rowError.Row + 1, Err: rowError.Err})
+			}
+			result.RowsAffected += batchError.RowsAffected
+			result.Succeeded += end - start - len(batchError.Rows)
+			continue
+		}
+
+		_, rollbackErr := execer.ExecContext(ctx, "rollback to savepoint "+savepoint)
+		if rollbackErr != nil {
+			return result, rollbackErr
+		}
+		result.Failures = append(result.Failures, BatchFailure{Start: start, End: end, Err: err})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package oci8
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// TestDestructiveSavepointBatch checks a savepoint batch keeps the good rows and rolls back the failed ones
+func TestDestructiveSavepointBatch(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "SAVEPOINT_BATCH_" + TestTimeString
+	err := testExec(t, "create table "+tableName+" ( A NUMBER(2) )", nil)
+	if err != nil {
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	var rows [][]interface{}
+	for i := 1; i <= 10; i++ {
+		value := int64(i)
+		if i == 4 || i == 9 {
+			// too large for NUMBER(2), ORA-01438
+			value = 1000
+		}
+		rows = append(rows, []interface{}{value})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	tx, err := TestDB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("begin error:", err)
+	}
+	result, err := ExecSavepointBatch(ctx, tx, "insert into "+tableName+" ( A ) values ( :1 )", rows, SavepointBatchOptions{ChunkSize: 3})
+	if err != nil {
+		tx.Rollback()
+		t.Fatal("batch error:", err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal("commit error:", err)
+	}
+
+	// chunks 4 to 6 and 7 to 9 failed
+	if result.Succeeded != 4 || result.RowsAffected != 4 || len(result.Failures) != 2 {
+		t.Errorf("result - received: %+v", result)
+	}
+	for _, failure := range result.Failures {
+		if !isOracleError(failure.Err, 1438) {
+			t.Errorf("failure error - expected: ORA-01438 - received: %v", failure.Err)
+		}
+	}
+
+	// with RetryRows only rows 4 and 9 fail
+	tx, err = TestDB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("begin error:", err)
+	}
+	result, err = ExecSavepointBatch(ctx, tx, "insert into "+tableName+" ( A ) values ( :1 )", rows, SavepointBatchOptions{ChunkSize: 3, RetryRows: true})
+	if err != nil {
+		tx.Rollback()
+		t.Fatal("batch error:", err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal("commit error:", err)
+	}
+	if result.Succeeded != 8 || result.RowsAffected != 8 || len(result.Failures) != 2 ||
+		result.Failures[0].Start != 3 || result.Failures[1].Start != 8 {
+		t.Errorf("retry rows result - received: %+v", result)
+	}
+
+	queryResults := testQueryResults{
+		query: "select A from " + tableName + " order by A",
+		queryResults: []testQueryResult{{results: [][]interface{}{{int64(1)}, {int64(1)}, {int64(2)}, {int64(2)}, {int64(3)}, {int64(3)},
+			{int64(5)}, {int64(6)}, {int64(7)}, {int64(8)}, {int64(10)}, {int64(10)}}}},
+	}
+	testRunQueryResults(t, queryResults)
+}
+
+// TestExecSavepointBatch tests executing a batch in savepoint chunks
+func TestExecSavepointBatch(t *testing.T) {
+	t.Parallel()
+
+	rows := [][]interface{}{{1}, {2}, {-3}, {4}, {5}, {6}, {-7}}
+
+	execer := &testSavepointExecer{}
+	result, err := ExecSavepointBatch(context.Background(), execer, "insert", rows, SavepointBatchOptions{ChunkSize: 2})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if result.RowsAffected != 4 || result.Succeeded != 4 {
+		t.Errorf("result - expected: 4 rows - received: %+v", result)
+	}
+	if len(result.Failures) != 2 || result.Failures[0].Start != 2 || result.Failures[0].End != 4 ||
+		result.Failures[1].Start != 6 || result.Failures[1].End != 7 {
+		t.Errorf("failures - received: %+v", result.Failures)
+	}
+	if !isOracleError(result.Failures[0].Err, 1438) {
+		t.Errorf("failure error - expected: ORA-01438 - received: %v", result.Failures[0].Err)
+	}
+	expected := []string{
+		"savepoint OCI8_BATCH", "insert",
+		"savepoint OCI8_BATCH", "insert", "rollback to savepoint OCI8_BATCH",
+		"savepoint OCI8_BATCH", "insert",
+		"savepoint OCI8_BATCH", "insert", "rollback to savepoint OCI8_BATCH",
+	}
+	if !reflect.DeepEqual(execer.queries, expected) {
+		t.Errorf("queries - expected: %v - received: %v", expected, execer.queries)
+	}
+
+	execer = &testSavepointExecer{}
+	result, err = ExecSavepointBatch(context.Background(), execer, "insert", rows, SavepointBatchOptions{ChunkSize: 3, Savepoint: "LOAD", RetryRows: true})
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if result.RowsAffected != 5 || result.Succeeded != 5 {
+		t.Errorf("retry result - expected: 5 rows - received: %+v", result)
+	}
+	var failed []int
+	for _, failure := range result.Failures {
+		if failure.End != failure.Start+1 || failure.Err == nil {
+			t.Errorf("retry failure - received: %+v", failure)
+		}
+		failed = append(failed, failure.Start)
+	}
+	if !reflect.DeepEqual(failed, []int{2, 6}) {
+		t.Errorf("retry failures - expected: %v - received: %v", []int{2, 6}, failed)
+	}
+	expected = []string{"savepoint LOAD", "insert", "savepoint LOAD", "insert", "savepoint LOAD", "insert"}
+	if !reflect.DeepEqual(execer.queries, expected) {
+		t.Errorf("retry queries - expected: %v - received: %v", expected, execer.queries)
+	}
+
+	_, err = ExecSavepointBatch(context.Background(), execer, "insert", rows, SavepointBatchOptions{Savepoint: "a b"})
+	if err == nil {
+		t.Error("invalid savepoint - expected error - received: nil")
+	}
+}
@@ -0,0 +1,87 @@
+package oci8
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// TestSelectScanColumns tests only fetching wanted columns
+func TestSelectScanColumns(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	query := "select 1 as id, 'a' as name, to_clob('b') as data, hextoraw('01') as raw from dual"
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	stmt, err := TestDB.PrepareContext(ctx, query)
+	cancel()
+	if err != nil {
+		t.Fatal("prepare error:", err)
+	}
+	defer stmt.Close()
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	ctx = WithScanColumns(ctx, "name")
+	ctx = WithScanColumnIndexes(ctx, 3)
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		t.Fatal("expected a row, rows error:", rows.Err())
+	}
+	var id, name, data, raw interface{}
+	err = rows.Scan(&id, &name, &data, &raw)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if id != nil || data != nil {
+		t.Errorf("expected nil for unwanted columns - received: %v, %v", id, data)
+	}
+	if name != "a" {
+		t.Errorf("name - expected: a - received: %v", name)
+	}
+	if !reflect.DeepEqual(raw, []byte{1}) {
+		t.Errorf("raw - expected: [1] - received: %v", raw)
+	}
+}
+
+// TestScanColumns tests scan columns from context
+func TestScanColumns(t *testing.T) {
+	t.Parallel()
+
+	if scanColumnsFromContext(context.Background()) != nil {
+		t.Fatal("expected nil scan columns")
+	}
+	if !scanColumnsFromContext(context.Background()).wants(5, "ANY") {
+		t.Error("nil scan columns should want all columns")
+	}
+
+	ctx := WithScanColumns(context.Background(), "id", "Name")
+	ctx = WithScanColumnIndexes(ctx, 3)
+	columns := scanColumnsFromContext(ctx)
+
+	var wantsTests = []struct {
+		index int
+		name  string
+		wants bool
+	}{
+		{0, "ID", true},
+		{1, "NAME", true},
+		{2, "name", true},
+		{3, "OTHER", true},
+		{4, "OTHER", false},
+	}
+
+	for _, tt := range wantsTests {
+		if columns.wants(tt.index, tt.name) != tt.wants {
+			t.Errorf("wants(%v, %v) expected %v", tt.index, tt.name, tt.wants)
+		}
+	}
+}
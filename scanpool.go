@@ -0,0 +1,394 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ScanPool recycles scan destinations between rows and queries, for services that run many small queries,
+// so a row is scanned without allocating a destination slice and a pointer per column each time.
+// The zero value is ready to use and a ScanPool is safe for concurrent use.
+//
+//	dest := pool.Get(len(columns))
+//	defer dest.Release()
+//	for rows.Next() {
+//		err = rows.Scan(dest.Dest...)
+//		// use dest.Values
+//	}
+type ScanPool struct {
+	pool sync.Pool
+}
+
+// ScanDest are scan destinations taken from a ScanPool
+type ScanDest struct {
+	// Dest are the destinations to pass to sql.Rows.Scan or Rows.ScanInto, a pointer to each of the Values
+	Dest []interface{}
+	// Values are the scanned values of the columns
+	Values []interface{}
+
+	pool *ScanPool
+}
+
+// Get returns scan destinations for the number of columns.
+// The values are nil, or empty byte slices kept from an earlier use so Rows.ScanInto can reuse their capacity.
+func (pool *ScanPool) Get(columns int) *ScanDest {
+	scanDest, _ := pool.pool.Get().(*ScanDest)
+	if scanDest == nil {
+		scanDest = &ScanDest{pool: pool}
+	}
+
+	if cap(scanDest.Values) < columns {
+		scanDest.Values = make([]interface{}, columns)
+		scanDest.Dest = make([]interface{}, columns)
+		for i := range scanDest.Values {
+			scanDest.Dest[i] = &scanDest.Values[i]
+		}
+	}
+	scanDest.Values = scanDest.Values[:columns]
+	scanDest.Dest = scanDest.Dest[:columns]
+	return scanDest
+}
+
+// Release returns the scan destinations to the pool. The values must not be used after.
+func (scanDest *ScanDest) Release() {
+	values := scanDest.Values[:cap(scanDest.Values)]
+	for i, value := range values {
+		if buffer, ok := value.([]byte); ok {
+			values[i] = buffer[:0]
+		} else {
+			values[i] = nil
+		}
+	}
+	scanDest.pool.pool.Put(scanDest)
+}
+
+// ScanInto fetches the next row into dest, one destination per column, like Next and Scan of sql.Rows in one call.
+// Returns io.EOF when there are no more rows.
+// It allocates less than sql.Rows.Scan: INTEGER, floating point, character, RAW, and timestamp columns are read
+// from the define buffers without converting to interface{} values first, unless the connection has a Converter,
+// and byte slices, including a []byte in an *interface{}, are copied into the capacity of the destination.
+//
+// Supported destinations are *int64, *float64, *bool, *string, *[]byte, *time.Time, *interface{},
+// and sql.Scanner, like the sql.Null types. Nulls are set to the zero value, or nil for *interface{} and *[]byte,
+// use the sql.Null types or *interface{} to tell nulls apart.
+// A *sql.RawBytes of a character or RAW column is set to the define buffer without copying,
+// it is only valid until the next call to ScanInto or Close.
+// The Dest of a ScanDest from a ScanPool can be used for the destinations.
+//
+// Rows are returned from QueryContext of the driver Stmt, use sql.Conn.Raw to get the driver connection:
+//
+//	rows, err := stmt.(driver.StmtQueryContext).QueryContext(ctx, nil)
+//	for {
+//		err = rows.(*oci8.Rows).ScanInto(&id, &name)
+//		if err == io.EOF {
+//			break
+//		}
+//	}
+func (rows *Rows) ScanInto(dest ...interface{}) error {
+	err := rows.guard.enter("Rows", "ScanInto", rows.stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return err
+	}
+	defer rows.guard.exit(rows.stmt.conn.debugConcurrentUse)
+
+	if rows.closed {
+		return io.EOF
+	}
+	if len(dest) != len(rows.defines) {
+		return fmt.Errorf("ScanInto needs %v destinations, received %v", len(rows.defines), len(dest))
+	}
+
+	if rows.stmt.ctx.Err() != nil {
+		return rows.stmt.ctx.Err()
+	}
+
+	stop := rows.stmt.conn.watchBreak(rows.stmt.ctx)
+	defer stop()
+	err = rows.fetchNext()
+	if err != nil {
+		return err
+	}
+
+	converted := false
+	for i := range dest {
+		var ok bool
+		ok, err = rows.scanDirect(i, dest[i])
+		if err != nil {
+			return fmt.Errorf("ScanInto column %v - error: %v", i, err)
+		}
+		if ok {
+			continue
+		}
+
+		if !converted {
+			if rows.scanValues == nil {
+				rows.scanValues = make([]driver.Value, len(rows.defines))
+			}
+			err = rows.values(rows.scanValues)
+			if err != nil {
+				return err
+			}
+			converted = true
+		}
+		err = scanSet(dest[i], rows.scanValues[i])
+		if err != nil {
+			return fmt.Errorf("ScanInto column %v - error: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// scanDirect sets the destination from the define buffer of column i, used by ScanInto and FetchBatch.
+// Returns false when the column needs to be converted to a driver.Value first.
+func (rows *Rows) scanDirect(i int, dest interface{}) (bool, error) {
+	define := &rows.defines[i]
+	if define.skip || define.piecewise || rows.stmt.conn.converter != nil {
+		return false, nil
+	}
+	null := *define.indicator == -1
+	if !null && *define.indicator != 0 {
+		// let values return the indicator error
+		return false, nil
+	}
+
+	switch define.dataType {
+	case C.SQLT_INT:
+		var value int64
+		if !null {
+			if *define.length != 8 {
+				return false, nil
+			}
+			value = int64(binary.LittleEndian.Uint64((*[8]byte)(define.pbuf)[:]))
+		}
+		switch dest := dest.(type) {
+		case *int64:
+			*dest = value
+		case *float64:
+			*dest = float64(value)
+		case *sql.NullInt64:
+			*dest = sql.NullInt64{Int64: value, Valid: !null}
+		case *sql.NullFloat64:
+			*dest = sql.NullFloat64{Float64: float64(value), Valid: !null}
+		default:
+			return false, nil
+		}
+		return true, nil
+
+	case C.SQLT_BDOUBLE:
+		var value float64
+		if !null {
+			if *define.length != 8 {
+				return false, nil
+			}
+			value = math.Float64frombits(binary.LittleEndian.Uint64((*[8]byte)(define.pbuf)[:]))
+		}
+		switch dest := dest.(type) {
+		case *float64:
+			*dest = value
+		case *sql.NullFloat64:
+			*dest = sql.NullFloat64{Float64: value, Valid: !null}
+		case *int64:
+			// NUMBER columns without a scale, like count(*), are defined as floating point
+			if !floatIsInt64(value) {
+				return false, fmt.Errorf("can not set %v into %T", value, dest)
+			}
+			*dest = int64(value)
+		default:
+			return false, nil
+		}
+		return true, nil
+
+	case C.SQLT_CHR, C.SQLT_STR, C.SQLT_AFC, C.SQLT_AVC, C.SQLT_LNG:
+		switch dest := dest.(type) {
+		case *string:
+			*dest = ""
+			if !null {
+				*dest = rows.stmt.conn.goText((*C.OraText)(define.pbuf), int(*define.length))
+			}
+		case *sql.NullString:
+			*dest = sql.NullString{Valid: !null}
+			if !null {
+				dest.String = rows.stmt.conn.goText((*C.OraText)(define.pbuf), int(*define.length))
+			}
+		case *[]byte:
+			switch {
+			case null:
+				*dest = nil
+			case rows.stmt.conn.utf16:
+				*dest = append((*dest)[:0], rows.stmt.conn.goText((*C.OraText)(define.pbuf), int(*define.length))...)
+			default:
+				*dest = append((*dest)[:0], (*[1 << 30]byte)(define.pbuf)[:*define.length]...)
+			}
+		case *sql.RawBytes:
+			switch {
+			case null:
+				*dest = nil
+			case rows.stmt.conn.utf16:
+				*dest = append((*dest)[:0], rows.stmt.conn.goText((*C.OraText)(define.pbuf), int(*define.length))...)
+			default:
+				*dest = (*[1 << 30]byte)(define.pbuf)[:*define.length:*define.length]
+			}
+		default:
+			return false, nil
+		}
+		return true, nil
+
+	case C.SQLT_BIN:
+		switch dest := dest.(type) {
+		case *[]byte:
+			if null {
+				*dest = nil
+			} else {
+				*dest = append((*dest)[:0], (*[1 << 30]byte)(define.pbuf)[:*define.length]...)
+			}
+		case *sql.RawBytes:
+			*dest = nil
+			if !null {
+				*dest = (*[1 << 30]byte)(define.pbuf)[:*define.length:*define.length]
+			}
+		default:
+			return false, nil
+		}
+		return true, nil
+
+	case C.SQLT_TIMESTAMP, C.SQLT_TIMESTAMP_TZ, C.SQLT_TIMESTAMP_LTZ:
+		dest, ok := dest.(*time.Time)
+		if !ok {
+			return false, nil
+		}
+		if null {
+			*dest = time.Time{}
+			return true, nil
+		}
+		aTime, err := rows.stmt.conn.ociDateTimeToTime(*(**C.OCIDateTime)(define.pbuf), define.dataType != C.SQLT_TIMESTAMP)
+		if err != nil {
+			return false, fmt.Errorf("ociDateTimeToTime error: %v", err)
+		}
+		*dest = rows.stmt.conn.localTimeZoneTime(define, *aTime)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// scanSet sets the destination to a converted value.
+// Byte slices are copied because the define buffers are reused by the next fetch.
+func scanSet(dest interface{}, value driver.Value) error {
+	switch dest := dest.(type) {
+	case *interface{}:
+		if buffer, ok := value.([]byte); ok {
+			reuse, _ := (*dest).([]byte)
+			*dest = append(reuse[:0], buffer...)
+			return nil
+		}
+		*dest = value
+		return nil
+	case *[]byte:
+		switch value := value.(type) {
+		case nil:
+			*dest = nil
+			return nil
+		case []byte:
+			*dest = append((*dest)[:0], value...)
+			return nil
+		case string:
+			*dest = append((*dest)[:0], value...)
+			return nil
+		}
+	case *string:
+		switch value := value.(type) {
+		case nil:
+			*dest = ""
+			return nil
+		case string:
+			*dest = value
+			return nil
+		case []byte:
+			*dest = string(value)
+			return nil
+		case int64:
+			*dest = strconv.FormatInt(value, 10)
+			return nil
+		case float64:
+			*dest = strconv.FormatFloat(value, 'g', -1, 64)
+			return nil
+		case time.Time:
+			*dest = value.Format(time.RFC3339Nano)
+			return nil
+		}
+	case *int64:
+		switch value := value.(type) {
+		case nil:
+			*dest = 0
+			return nil
+		case int64:
+			*dest = value
+			return nil
+		case float64:
+			if floatIsInt64(value) {
+				*dest = int64(value)
+				return nil
+			}
+		}
+	case *float64:
+		switch value := value.(type) {
+		case nil:
+			*dest = 0
+			return nil
+		case float64:
+			*dest = value
+			return nil
+		case int64:
+			*dest = float64(value)
+			return nil
+		}
+	case *bool:
+		switch value := value.(type) {
+		case nil:
+			*dest = false
+			return nil
+		case bool:
+			*dest = value
+			return nil
+		case int64:
+			if value == 0 || value == 1 {
+				*dest = value == 1
+				return nil
+			}
+		case float64:
+			if value == 0 || value == 1 {
+				*dest = value == 1
+				return nil
+			}
+		}
+	case *time.Time:
+		switch value := value.(type) {
+		case nil:
+			*dest = time.Time{}
+			return nil
+		case time.Time:
+			*dest = value
+			return nil
+		}
+	case sql.Scanner:
+		if buffer, ok := value.([]byte); ok {
+			value = append([]byte(nil), buffer...)
+		}
+		return dest.Scan(value)
+	default:
+		return fmt.Errorf("unsupported destination type %T", dest)
+	}
+
+	return fmt.Errorf("can not set %v into %T", value, dest)
+}
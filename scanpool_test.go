@@ -0,0 +1,192 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestScanInto checks scanning rows into destinations from a scan pool
+func TestScanInto(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	query := "select level, level / 2, case when mod(level, 3) = 0 then null else 'row ' || level end, hextoraw('0' || level), " +
+		"cast (timestamp '2020-01-01 00:00:00' + numtodsinterval(level, 'DAY') as TIMESTAMP(9)), level from dual connect by level <= 5"
+
+	var pool ScanPool
+	var id int64
+	var half float64
+	var name sql.NullString
+	var raw []byte
+	var aTime time.Time
+	var ids []int64
+	var names []sql.NullString
+	err = conn.Raw(func(driverConn interface{}) error {
+		stmt, err := driverConn.(*Conn).PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		rows, err := stmt.(driver.StmtQueryContext).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		scanDest := pool.Get(1)
+		defer scanDest.Release()
+		for {
+			err = rows.(*Rows).ScanInto(&id, &half, &name, &raw, &aTime, scanDest.Dest[0])
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if half != float64(id)/2 {
+				t.Errorf("half - expected: %v - received: %v", float64(id)/2, half)
+			}
+			if !reflect.DeepEqual(raw, []byte{byte(id)}) {
+				t.Errorf("raw - expected: %v - received: %v", []byte{byte(id)}, raw)
+			}
+			expectedTime := time.Date(2020, 1, 1+int(id), 0, 0, 0, 0, time.UTC)
+			if !aTime.Equal(expectedTime) {
+				t.Errorf("time - expected: %v - received: %v", expectedTime, aTime)
+			}
+			if scanDest.Values[0] != float64(id) {
+				t.Errorf("value - expected: %v - received: %v", float64(id), scanDest.Values[0])
+			}
+			ids = append(ids, id)
+			names = append(names, name)
+		}
+	})
+	if err != nil {
+		t.Fatal("scan into error:", err)
+	}
+
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("ids - received: %v", ids)
+	}
+	expectedNames := []sql.NullString{{String: "row 1", Valid: true}, {String: "row 2", Valid: true}, {}, {String: "row 4", Valid: true}, {String: "row 5", Valid: true}}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("names - received: %v", names)
+	}
+}
+
+// TestScanPool tests scan destinations are recycled and scanSet sets converted values into destinations
+func TestScanPool(t *testing.T) {
+	t.Parallel()
+
+	var pool ScanPool
+	scanDest := pool.Get(3)
+	if len(scanDest.Dest) != 3 || len(scanDest.Values) != 3 {
+		t.Fatalf("Get length - expected: 3, 3 - received: %v, %v", len(scanDest.Dest), len(scanDest.Values))
+	}
+	for i := range scanDest.Dest {
+		if scanDest.Dest[i] != &scanDest.Values[i] {
+			t.Errorf("Dest %v - expected: pointer to value %v - received: %v", i, i, scanDest.Dest[i])
+		}
+	}
+	*scanDest.Dest[0].(*interface{}) = int64(1)
+	scanDest.Values[1] = []byte{1, 2}
+	scanDest.Release()
+
+	// a smaller destination can reuse the released one, the values are cleared and byte slices kept empty
+	scanDest = pool.Get(2)
+	if len(scanDest.Dest) != 2 || len(scanDest.Values) != 2 {
+		t.Fatalf("Get length - expected: 2, 2 - received: %v, %v", len(scanDest.Dest), len(scanDest.Values))
+	}
+	for i, value := range scanDest.Values {
+		if buffer, ok := value.([]byte); value != nil && (!ok || len(buffer) != 0) {
+			t.Errorf("value %v - expected: nil or empty []byte - received: %v", i, value)
+		}
+		if scanDest.Dest[i] != &scanDest.Values[i] {
+			t.Errorf("Dest %v - expected: pointer to value %v - received: %v", i, i, scanDest.Dest[i])
+		}
+	}
+	scanDest.Release()
+
+	aTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	var setTests = []struct {
+		dest     interface{}
+		value    interface{}
+		expected interface{}
+	}{
+		{new(int64), int64(1), int64(1)},
+		{new(int64), 3.0, int64(3)},
+		{new(int64), nil, int64(0)},
+		{new(float64), int64(2), float64(2)},
+		{new(float64), 2.5, 2.5},
+		{new(bool), int64(1), true},
+		{new(bool), false, false},
+		{new(string), "a", "a"},
+		{new(string), []byte("b"), "b"},
+		{new(string), int64(7), "7"},
+		{new(string), 0.5, "0.5"},
+		{new([]byte), "c", []byte("c")},
+		{new([]byte), nil, []byte(nil)},
+		{new(time.Time), aTime, aTime},
+		{new(interface{}), int64(4), int64(4)},
+		{new(interface{}), []byte{1}, []byte{1}},
+		{new(interface{}), nil, nil},
+		{&sql.NullString{}, "d", sql.NullString{String: "d", Valid: true}},
+		{&sql.NullInt64{Int64: 3, Valid: true}, nil, sql.NullInt64{}},
+	}
+	for _, tt := range setTests {
+		err := scanSet(tt.dest, tt.value)
+		if err != nil {
+			t.Errorf("scanSet(%T, %v) error: %v", tt.dest, tt.value, err)
+			continue
+		}
+		received := reflect.ValueOf(tt.dest).Elem().Interface()
+		if !reflect.DeepEqual(received, tt.expected) {
+			t.Errorf("scanSet(%T, %v) - expected: %v - received: %v", tt.dest, tt.value, tt.expected, received)
+		}
+	}
+
+	// byte slices are copied into the capacity of the destination
+	buffer := make([]byte, 0, 8)
+	var value interface{} = buffer
+	err := scanSet(&value, []byte{5, 6})
+	if err != nil {
+		t.Fatal("scanSet error:", err)
+	}
+	if received := value.([]byte); !reflect.DeepEqual(received, []byte{5, 6}) || &received[:1][0] != &buffer[:1][0] {
+		t.Errorf("scanSet reuse - expected: [5 6] in the same array - received: %v", received)
+	}
+
+	for _, tt := range []struct {
+		dest  interface{}
+		value interface{}
+	}{
+		{new(int64), "a"},
+		{new(int64), 2.5},
+		{new(bool), int64(2)},
+		{new(int), int64(1)},
+	} {
+		err = scanSet(tt.dest, tt.value)
+		if err == nil {
+			t.Errorf("scanSet(%T, %v) - expected: error - received: nil", tt.dest, tt.value)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package oci8
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// TestSelectColumnTypeScanType checks the column scan types are the types of the values returned
+func TestSelectColumnTypeScanType(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	query := "select cast('a' as VARCHAR2(10)), cast('b' as NCHAR(2)), to_clob('c'), hextoraw('0102'), to_blob(hextoraw('03'))," +
+		" cast(1 as NUMBER(10)), cast(1.5 as NUMBER(10,2)), 2.5, cast(3 as INTEGER), cast(4.5 as FLOAT), to_binary_double(5.5), to_binary_float(6.5)," +
+		" sysdate, systimestamp, cast(systimestamp as TIMESTAMP), interval '1-2' year to month, interval '1 2:3:4' day to second, rowid" +
+		" from dual"
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	rows, err := TestDB.QueryContext(ctx, query)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal("column types error:", err)
+	}
+	if !rows.Next() {
+		t.Fatal("no rows:", rows.Err())
+	}
+	values := make([]interface{}, len(columnTypes))
+	dest := make([]interface{}, len(columnTypes))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	err = rows.Scan(dest...)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+
+	for i, columnType := range columnTypes {
+		if reflect.TypeOf(values[i]) != columnType.ScanType() {
+			t.Errorf("column %v %v ScanType - expected: %v - received: %v", i, columnType.DatabaseTypeName(), reflect.TypeOf(values[i]), columnType.ScanType())
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+)
+
+const (
+	// serverOutputEnableQuery enables DBMS_OUTPUT with a buffer size in bytes, a null size is unlimited
+	serverOutputEnableQuery = "begin dbms_output.enable(:1); end;"
+
+	// serverOutputDisableQuery disables DBMS_OUTPUT and discards the buffered lines
+	serverOutputDisableQuery = "begin dbms_output.disable; end;"
+
+	// serverOutputQuery gets up to :2 buffered lines into the PL/SQL array :1, :2 is set to the number of lines returned
+	serverOutputQuery = "begin dbms_output.get_lines(:1, :2); end;"
+
+	// serverOutputLines is the max number of lines returned by each call of DBMS_OUTPUT.GET_LINES,
+	// each line has a buffer of plsqlArrayMaxStringSize bytes
+	serverOutputLines = 100
+)
+
+/*
+Server output
+
+DBMS_OUTPUT lines written by PL/SQL are buffered in the session. Enable the buffer on a sql.Conn,
+so the statements and GetServerOutput run on the same session, then get the lines after each Exec:
+
+	conn, err := db.Conn(ctx)
+	err = oci8.EnableServerOutput(ctx, conn, 0)
+	_, err = conn.ExecContext(ctx, "begin my_package.run; end;")
+	lines, err := oci8.GetServerOutput(ctx, conn)
+
+The lines are got with DBMS_OUTPUT.GET_LINES, up to 100 lines per round trip.
+Lines can be up to 32767 bytes, the max length of DBMS_OUTPUT lines.
+*/
+
+// EnableServerOutput enables DBMS_OUTPUT on the session of conn with a buffer of bufferSize bytes.
+// A bufferSize of 0 or less is an unlimited buffer.
+func EnableServerOutput(ctx context.Context, conn *sql.Conn, bufferSize int) error {
+	var size interface{}
+	if bufferSize > 0 {
+		size = int64(bufferSize)
+	}
+	_, err := conn.ExecContext(ctx, serverOutputEnableQuery, size)
+	return err
+}
+
+// DisableServerOutput disables DBMS_OUTPUT on the session of conn, the lines not got yet are discarded
+func DisableServerOutput(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, serverOutputDisableQuery)
+	return err
+}
+
+// GetServerOutput returns the DBMS_OUTPUT lines buffered in the session of conn, and removes them from the buffer.
+// Returns no lines when DBMS_OUTPUT is not enabled.
+func GetServerOutput(ctx context.Context, conn *sql.Conn) ([]string, error) {
+	var output []string
+	for {
+		lines := make([]string, 0, serverOutputLines)
+		numLines := int64(serverOutputLines)
+		array := &plsqlStringArray{dest: &lines, size: plsqlArrayMaxStringSize}
+		_, err := conn.ExecContext(ctx, serverOutputQuery, sql.Out{Dest: array}, sql.Out{Dest: &numLines, In: true})
+		if err != nil {
+			return output, err
+		}
+		output = append(output, lines...)
+		if numLines < serverOutputLines {
+			return output, nil
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package oci8
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestServerOutput checks getting the DBMS_OUTPUT lines of the session
+func TestServerOutput(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	err = EnableServerOutput(ctx, conn, 0)
+	if err != nil {
+		t.Fatal("EnableServerOutput error:", err)
+	}
+	_, err = conn.ExecContext(ctx, "begin for i in 1 .. 1500 loop dbms_output.put_line('line ' || i); end loop; end;")
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+
+	lines, err := GetServerOutput(ctx, conn)
+	if err != nil {
+		t.Fatal("GetServerOutput error:", err)
+	}
+	if len(lines) != 1500 || lines[0] != "line 1" || lines[1499] != "line 1500" {
+		t.Errorf("GetServerOutput - expected: 1500 lines from line 1 to line 1500 - received: %v lines", len(lines))
+	}
+
+	lines, err = GetServerOutput(ctx, conn)
+	if err != nil {
+		t.Fatal("GetServerOutput error:", err)
+	}
+	if len(lines) != 0 {
+		t.Errorf("GetServerOutput again - expected: no lines - received: %v", lines)
+	}
+
+	// lines longer than the 4000 byte elements of PL/SQL array binds
+	_, err = conn.ExecContext(ctx, "begin dbms_output.put_line(rpad('x', 32767, 'x')); dbms_output.put_line(rpad('y', 5000, 'y')); end;")
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	lines, err = GetServerOutput(ctx, conn)
+	if err != nil {
+		t.Fatal("GetServerOutput error:", err)
+	}
+	if len(lines) != 2 || lines[0] != strings.Repeat("x", 32767) || lines[1] != strings.Repeat("y", 5000) {
+		t.Errorf("GetServerOutput long lines - expected: lines of 32767 and 5000 bytes - received: %v lines", len(lines))
+	}
+
+	err = DisableServerOutput(ctx, conn)
+	if err != nil {
+		t.Fatal("DisableServerOutput error:", err)
+	}
+	_, err = conn.ExecContext(ctx, "begin dbms_output.put_line('disabled'); end;")
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	lines, err = GetServerOutput(ctx, conn)
+	if err != nil {
+		t.Fatal("GetServerOutput error:", err)
+	}
+	if len(lines) != 0 {
+		t.Errorf("GetServerOutput disabled - expected: no lines - received: %v", lines)
+	}
+}
@@ -0,0 +1,212 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"unsafe"
+)
+
+// sessionPool is an OCI session pool shared by the connections opened with the same DSN pool settings
+type sessionPool struct {
+	env            *C.OCIEnv
+	errHandle      *C.OCIError
+	pool           *C.OCISPool
+	poolName       *C.OraText
+	poolNameLength C.ub4
+	utf16          bool
+}
+
+var (
+	// sessionPools are the session pools by sessionPoolKey
+	sessionPools      = make(map[string]*sessionPool)
+	sessionPoolsMutex sync.Mutex
+)
+
+// sessionPoolKey returns the key of the session pool for the DSN and character set
+func sessionPoolKey(dsn *DSN, charset C.ub2) string {
+	return dsn.Connect + "\x00" + dsn.Username + "\x00" + dsn.Password + "\x00" +
+		strconv.FormatUint(uint64(charset), 10) + "\x00" +
+		strconv.FormatUint(uint64(dsn.poolMin), 10) + "\x00" +
+		strconv.FormatUint(uint64(dsn.poolMax), 10) + "\x00" +
+		strconv.FormatUint(uint64(dsn.poolIncrement), 10) + "\x00" +
+		strconv.FormatUint(uint64(dsn.stmtCacheSize), 10)
+}
+
+// getSessionPool returns the session pool for the DSN, creating it the first time.
+// The pool has its own environment, which the connections of the pool share, so the pool handles are not tracked for leak reports.
+func getSessionPool(dsn *DSN, charset C.ub2) (*sessionPool, error) {
+	sessionPoolsMutex.Lock()
+	defer sessionPoolsMutex.Unlock()
+
+	key := sessionPoolKey(dsn, charset)
+	if pool, ok := sessionPools[key]; ok {
+		return pool, nil
+	}
+
+	pool := &sessionPool{utf16: dsn.utf16}
+	var envP *C.OCIEnv
+	result := C.OCIEnvNlsCreate(
+		&envP,                       // pointer to a handle to the environment
+		C.OCI_THREADED|C.OCI_OBJECT, // environment mode, the pool is used by many goroutines, OCI_OBJECT for SDO_GEOMETRY columns
+		nil,                         // Specifies the user-defined context for the memory callback routines.
+		nil,                         // Specifies the user-defined memory allocation function.
+		nil,                         // Specifies the user-defined memory re-allocation function.
+		nil,                         // Specifies the user-defined memory free function.
+		0,                           // Specifies the amount of user memory to be allocated for the duration of the environment.
+		nil,                         // Returns a pointer to the user memory of size xtramemsz allocated by the call for the user.
+		charset,                     // The client-side character set for the current environment handle. If it is 0, the NLS_LANG setting is used.
+		charset,                     // The client-side national character set for the current environment handle. If it is 0, NLS_NCHAR setting is used.
+	)
+	if result != C.OCI_SUCCESS {
+		return nil, errors.New("session pool OCIEnvNlsCreate error")
+	}
+	pool.env = envP
+
+	var handle unsafe.Pointer
+	result = C.OCIHandleAlloc(unsafe.Pointer(pool.env), &handle, C.OCI_HTYPE_ERROR, 0, nil)
+	if result != C.OCI_SUCCESS {
+		C.OCIHandleFree(unsafe.Pointer(pool.env), C.OCI_HTYPE_ENV)
+		return nil, errors.New("session pool allocate error handle error")
+	}
+	pool.errHandle = (*C.OCIError)(handle)
+
+	result = C.OCIHandleAlloc(unsafe.Pointer(pool.env), &handle, C.OCI_HTYPE_SPOOL, 0, nil)
+	if result != C.OCI_SUCCESS {
+		pool.free()
+		return nil, errors.New("session pool allocate pool handle error")
+	}
+	pool.pool = (*C.OCISPool)(handle)
+
+	// the credentials are text in the character set of the environment
+	connectString, connectStringLength := cText(dsn.Connect, dsn.utf16)
+	defer C.free(unsafe.Pointer(connectString))
+	username, usernameLength := cText(dsn.Username, dsn.utf16)
+	defer C.free(unsafe.Pointer(username))
+	password, passwordLength := cText(dsn.Password, dsn.utf16)
+	defer C.free(unsafe.Pointer(password))
+
+	result = C.OCISessionPoolCreate(
+		pool.env,                    // environment handle
+		pool.errHandle,              // error handle
+		pool.pool,                   // session pool handle
+		&pool.poolName,              // returns the pool name, used to get sessions from the pool
+		&pool.poolNameLength,        // returns the length of the pool name
+		(*C.OraText)(connectString), // connect string
+		connectStringLength,         // length of the connect string
+		dsn.poolMin,                 // min number of sessions
+		dsn.poolMax,                 // max number of sessions
+		dsn.poolIncrement,           // number of sessions opened when more sessions are needed
+		(*C.OraText)(username),      // user name of all the sessions of the homogeneous pool
+		usernameLength,              // length of the user name
+		(*C.OraText)(password),      // password of all the sessions of the homogeneous pool
+		passwordLength,              // length of the password
+		C.OCI_SPC_HOMOGENEOUS,       // mode: all the sessions use the same credentials
+	)
+	if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
+		_, err := ociErrorGet(pool.errHandle, pool.utf16)
+		pool.free()
+		return nil, connectError(err)
+	}
+
+	if dsn.stmtCacheSize > 0 {
+		// the statement cache size of each session of the pool
+		stmtCacheSize := dsn.stmtCacheSize
+		result = C.OCIAttrSet(unsafe.Pointer(pool.pool), C.OCI_HTYPE_SPOOL, unsafe.Pointer(&stmtCacheSize), 0, C.OCI_ATTR_SPOOL_STMTCACHESIZE, pool.errHandle)
+		if result != C.OCI_SUCCESS {
+			_, err := ociErrorGet(pool.errHandle, pool.utf16)
+			C.OCISessionPoolDestroy(pool.pool, pool.errHandle, C.OCI_SPD_FORCE)
+			pool.free()
+			return nil, fmt.Errorf("stmt cache size attribute set error: %v", err)
+		}
+	}
+
+	sessionPools[key] = pool
+	return pool, nil
+}
+
+// free frees the handles of the pool
+func (pool *sessionPool) free() {
+	if pool.pool != nil {
+		C.OCIHandleFree(unsafe.Pointer(pool.pool), C.OCI_HTYPE_SPOOL)
+		pool.pool = nil
+	}
+	if pool.errHandle != nil {
+		C.OCIHandleFree(unsafe.Pointer(pool.errHandle), C.OCI_HTYPE_ERROR)
+		pool.errHandle = nil
+	}
+	if pool.env != nil {
+		C.OCIHandleFree(unsafe.Pointer(pool.env), C.OCI_HTYPE_ENV)
+		pool.env = nil
+	}
+}
+
+// ociSessionGet gets a session from the pool into the service context of the connection.
+// Returns the OCI result for the connect warnings.
+func (conn *Conn) ociSessionGet(pool *sessionPool) (C.sword, error) {
+	var svcCtxP *C.OCISvcCtx
+	result := C.OCISessionGet(
+		pool.env,            // environment handle of the pool
+		conn.errHandle,      // error handle
+		&svcCtxP,            // returns the service context of the session
+		nil,                 // authentication information, not needed for a homogeneous pool
+		pool.poolName,       // name of the pool
+		pool.poolNameLength, // length of the pool name
+		nil,                 // session tag
+		0,                   // length of the session tag
+		nil,                 // returns the tag of the session
+		nil,                 // returns the length of the tag of the session
+		nil,                 // returns if a session with the tag was found
+		C.OCI_SESSGET_SPOOL, // mode: get the session from a session pool
+	)
+	if result != C.OCI_SUCCESS && result != C.OCI_SUCCESS_WITH_INFO {
+		return result, connectError(conn.getError(result))
+	}
+	conn.svc = svcCtxP
+	conn.sessionPool = pool
+	return result, nil
+}
+
+// ociSessionRelease releases the session of the connection back to the pool.
+// With drop the session is closed instead, for a session that is not usable anymore.
+func (conn *Conn) ociSessionRelease(drop bool) error {
+	mode := C.ub4(C.OCI_DEFAULT)
+	if drop {
+		mode = C.OCI_SESSRLS_DROPSESS
+	}
+	// the service context is owned by the pool and used by the next connection getting the session,
+	// so it must not keep the transaction handle of this connection, which is freed
+	conn.clearTransHandle()
+	result := C.OCISessionRelease(conn.svc, conn.errHandle, nil, 0, mode)
+	// the service context can be got by another connection as soon as it is released,
+	// so it is cleared under handlesMutex for a timed out close to not call OCIBreak on it
+	conn.handlesMutex.Lock()
+	conn.svc = nil
+	conn.handlesMutex.Unlock()
+	if result != C.OCI_SUCCESS {
+		return conn.getError(result)
+	}
+	return nil
+}
+
+// CloseSessionPools closes the session pools created for DSNs with pool_max.
+// All connections of the pools must be closed first, the pools are created again when connecting.
+func CloseSessionPools() error {
+	sessionPoolsMutex.Lock()
+	defer sessionPoolsMutex.Unlock()
+
+	var err error
+	for key, pool := range sessionPools {
+		result := C.OCISessionPoolDestroy(pool.pool, pool.errHandle, C.OCI_SPD_FORCE)
+		if result != C.OCI_SUCCESS && err == nil {
+			_, err = ociErrorGet(pool.errHandle, pool.utf16)
+		}
+		pool.free()
+		delete(sessionPools, key)
+	}
+	return err
+}
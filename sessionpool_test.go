@@ -0,0 +1,108 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// TestSessionPool tests connections with pool_max get their sessions from a session pool and return them on close
+func TestSessionPool(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?pool_min=1&pool_max=2")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	db.SetMaxIdleConns(0)
+
+	sessionID := func() string {
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		defer cancel()
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal("conn error:", err)
+		}
+		defer conn.Close()
+		var sid string
+		err = conn.QueryRowContext(ctx, "select sys_context('USERENV', 'SID') from dual").Scan(&sid)
+		if err != nil {
+			t.Fatal("query error:", err)
+		}
+		return sid
+	}
+
+	// with no idle connections each sql.Conn opens a connection, which gets the pooled session
+	sid1 := sessionID()
+	sid2 := sessionID()
+	if sid1 != sid2 {
+		t.Errorf("session - expected: %v - received: %v", sid1, sid2)
+	}
+
+	err := db.Close()
+	if err != nil {
+		t.Fatal("close error:", err)
+	}
+	err = CloseSessionPools()
+	if err != nil {
+		t.Fatal("close session pools error:", err)
+	}
+}
+
+// TestStatementCacheReuse checks preparing the same query again reuses the cached statement handle
+func TestStatementCacheReuse(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	for _, params := range []string{"?stmtCacheSize=10", "?stmt_cache_size=10&pool_max=1"} {
+		db := testGetDB(params)
+		if db == nil {
+			t.Fatal("db is nil")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			cancel()
+			db.Close()
+			t.Fatal("conn error:", err)
+		}
+
+		// the statement handle addresses
+		var handles []string
+		err = conn.Raw(func(driverConn interface{}) error {
+			for i := 0; i < 2; i++ {
+				driverStmt, err := driverConn.(*Conn).PrepareContext(ctx, "select 1 from dual")
+				if err != nil {
+					return err
+				}
+				handles = append(handles, fmt.Sprintf("%p", driverStmt.(*Stmt).stmt))
+				err = driverStmt.Close()
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		conn.Close()
+		cancel()
+		db.Close()
+		if err != nil {
+			t.Fatal("prepare error:", err)
+		}
+
+		if handles[0] != handles[1] {
+			t.Errorf("statement handle %v - expected: reused - received: %v and %v", params, handles[0], handles[1])
+		}
+	}
+}
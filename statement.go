@@ -10,18 +10,51 @@ import (
 	"database/sql/driver"
 	"encoding/binary"
 	"fmt"
+	"math"
+	"math/big"
+	"reflect"
 	"strings"
 	"time"
 	"unsafe"
 )
 
-// Close closes the statement
+// Close closes the statement, waiting at most the close_timeout of the connection
 func (stmt *Stmt) Close() error {
+	ctx, cancel := stmt.conn.closeContext()
+	defer cancel()
+	return stmt.CloseContext(ctx)
+}
+
+// CloseContext closes the statement. When ctx is done before the statement is released, OCIBreak is called
+// and ctx.Err() is returned without waiting. The statement is then released in the background when the database responds,
+// closing the connection waits for it. The connection is marked as lost, so database/sql discards it
+// instead of running other calls on it while the statement is released.
+func (stmt *Stmt) CloseContext(ctx context.Context) error {
+	err := stmt.guard.enter("Stmt", "Close", stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return err
+	}
+	defer stmt.guard.exit(stmt.conn.debugConcurrentUse)
+
 	if stmt.closed {
 		return nil
 	}
 	stmt.closed = true
 
+	stmt.conn.cleanups.Add(1)
+	err = stmt.conn.boundedCleanup(ctx, func() error {
+		defer stmt.conn.cleanups.Done()
+		return stmt.release()
+	})
+	if err != nil && err == ctx.Err() {
+		// the release still runs in the background and the OCIBreak could break the next call
+		stmt.conn.sessionLost = true
+	}
+	return err
+}
+
+// release releases the statement handle, to the statement cache if enabled
+func (stmt *Stmt) release() error {
 	var result C.sword
 	if stmt.cacheKey == "" {
 		result = C.OCIStmtRelease(
@@ -32,21 +65,27 @@ func (stmt *Stmt) Close() error {
 			stmt.releaseMode,    // mode
 		)
 	} else {
-		cacheKeyP := cString(stmt.cacheKey)
+		cacheKeyP, cacheKeyLength := cText(stmt.cacheKey, stmt.conn.utf16)
 		defer C.free(unsafe.Pointer(cacheKeyP))
 
 		result = C.OCIStmtRelease(
-			stmt.stmt,                 // statement handle
-			stmt.conn.errHandle,       // error handle
-			cacheKeyP,                 // key to be associated with the statement in the cache
-			C.ub4(len(stmt.cacheKey)), // length of the key
-			stmt.releaseMode,          // mode
+			stmt.stmt,           // statement handle
+			stmt.conn.errHandle, // error handle
+			cacheKeyP,           // key to be associated with the statement in the cache
+			cacheKeyLength,      // length of the key
+			stmt.releaseMode,    // mode
 		)
 	}
 
+	leakFree(unsafe.Pointer(stmt.stmt))
 	stmt.stmt = nil
 
-	return stmt.conn.getError(result)
+	err := stmt.conn.getError(result)
+	lobErr := stmt.freeTempLobs()
+	if err == nil {
+		err = lobErr
+	}
+	return err
 }
 
 // NumInput returns the number of input
@@ -54,17 +93,110 @@ func (stmt *Stmt) NumInput() int {
 	return -1
 }
 
-// CheckNamedValue checks a named value
+// CheckNamedValue checks and converts a named value, in place of the database/sql default converter for the types the driver binds:
+// sql.Out, Batch, LobStream, *TempLob, *Lob, VECTOR slices, and math/big numbers are passed as is,
+// time.Duration and YearMonth are bound as INTERVAL DAY TO SECOND and INTERVAL YEAR TO MONTH,
+// unsigned integers are passed as uint64, with values over the int64 range bound as NUMBER,
+// and the value of a driver.Valuer is checked the same way, so a Valuer can return any of these types.
+// Maps with string keys, and structs with json tags, are marshaled to JSON text.
+// With Connector.IPFormat and Connector.RawUUIDs, net.IP values and [16]byte based types are bound as text or RAW.
+// Other types are left to the default converter.
 func (stmt *Stmt) CheckNamedValue(namedValue *driver.NamedValue) error {
-	switch namedValue.Value.(type) {
+	if stmt.conn != nil {
+		value, ok, err := stmt.conn.convertIPUUID(namedValue.Value)
+		if err != nil {
+			return &BindError{Index: namedValue.Ordinal - 1, Name: namedValue.Name, Err: err}
+		}
+		if ok {
+			namedValue.Value = value
+			return nil
+		}
+	}
+
+	value, err := checkBindValue(namedValue.Value, true)
+	if err == driver.ErrSkip {
+		return err
+	}
+	if err != nil {
+		return &BindError{Index: namedValue.Ordinal - 1, Name: namedValue.Name, Err: err}
+	}
+	namedValue.Value = value
+	return nil
+}
+
+// checkBindValue returns the value to bind, driver.ErrSkip to use the default converter.
+// When valuer is true, the value of a driver.Valuer is checked, otherwise it is left to the default converter.
+func checkBindValue(value interface{}, valuer bool) (interface{}, error) {
+	switch value := value.(type) {
 	case sql.Out:
-		return nil
+		return value, nil
+	case []float32, []float64: // VECTOR
+		return value, nil
+	case NumberList, Int64List, StringList:
+		return value, nil
+	case Batch, LobStream, *TempLob:
+		return value, nil
+	case *big.Int, *big.Rat, *big.Float:
+		return value, nil
+	case *Lob:
+		if value == nil {
+			return nil, nil
+		}
+		return LobStream{Reader: value, Text: !value.binary}, nil
+	case time.Duration, YearMonth:
+		return value, nil
+	case uint, uint64, uintptr:
+		return value, nil
+	case driver.Valuer:
+		if !valuer {
+			return nil, driver.ErrSkip
+		}
+		result, err := valuerValue(value)
+		if err != nil {
+			return nil, err
+		}
+		checked, err := checkBindValue(result, false)
+		if err == driver.ErrSkip {
+			if !driver.IsValue(result) {
+				return nil, fmt.Errorf("%T Value returned %T, which is not a supported bind type", value, result)
+			}
+			return result, nil
+		}
+		return checked, err
+	}
+
+	text, ok, err := jsonBindValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return text, nil
+	}
+
+	// named unsigned types, the default converter returns an error for values over the int64 range
+	if value != nil {
+		switch reflectValue := reflect.ValueOf(value); reflectValue.Kind() {
+		case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+			return reflectValue.Uint(), nil
+		}
+	}
+	return nil, driver.ErrSkip
+}
+
+// valuerValue returns the value of a driver.Valuer, nil for a nil pointer with a Value method on the element type,
+// same as database/sql
+func valuerValue(valuer driver.Valuer) (driver.Value, error) {
+	reflectValue := reflect.ValueOf(valuer)
+	if reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil() &&
+		reflectValue.Type().Elem().Implements(reflect.TypeOf((*driver.Valuer)(nil)).Elem()) {
+		return nil, nil
 	}
-	return driver.ErrSkip
+	return valuer.Value()
 }
 
 // bindValues binds the values to the stmt
 func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedValue) ([]bindStruct, error) {
+	stmt.hookArgs = stmt.conn.hookArgs(values, namedValues)
 	if len(values) == 0 && len(namedValues) == 0 {
 		return nil, nil
 	}
@@ -78,9 +210,40 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 		count = len(values)
 	}
 
+	bindNameLength := maxIdentifierLength
+	for i := 0; i < len(namedValues); i++ {
+		if namedValues[i].Name != "" {
+			bindNameLength = stmt.conn.identifierLength()
+			break
+		}
+	}
+	err = validateBinds(namedValues, count, bindNameLength)
+	if err != nil {
+		return nil, err
+	}
+
+	tempLobOptions := tempLobOptionsFromContext(stmt.ctx)
+
+	// for a PL/SQL call with OUT or bool binds, describe the arguments to pick the OUT buffer types and bind PL/SQL BOOLEAN
+	var plsqlBinds []plsqlBind
+	for i := 0; i < count; i++ {
+		var value interface{}
+		if useValues {
+			value = values[i]
+		} else {
+			value = namedValues[i].Value
+		}
+		_, isOut := value.(sql.Out)
+		_, isBool := value.(bool)
+		if isOut || isBool {
+			plsqlBinds = stmt.plsqlBinds()
+			break
+		}
+	}
+
 	for i := 0; i < count; i++ {
 		if stmt.ctx.Err() != nil {
-			freeBinds(binds)
+			stmt.conn.freeBinds(binds)
 			return nil, stmt.ctx.Err()
 		}
 
@@ -99,13 +262,27 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 
 		var isOut bool
 		var isNill bool
+		var argument plsqlArgument
+		if useValues {
+			argument = plsqlArgumentFor(plsqlBinds, i, "")
+		} else {
+			argument = plsqlArgumentFor(plsqlBinds, i, namedValues[i].Name)
+		}
 		sbind.out, isOut = valueInterface.(sql.Out)
 		if isOut {
-			valueInterface, err = driver.DefaultParameterConverter.ConvertValue(sbind.out.Dest)
-			if err != nil {
-				binds = append(binds, sbind)
-				freeBinds(binds)
-				return nil, err
+			if _, isCursor := sbind.out.Dest.(**Rows); isCursor {
+				valueInterface = sbind.out.Dest
+			} else if _, _, isArray := plsqlArrayLengths(sbind.out.Dest, false); isArray {
+				valueInterface = sbind.out.Dest
+			} else if isBigNumber(sbind.out.Dest) {
+				valueInterface = sbind.out.Dest
+			} else {
+				valueInterface, err = driver.DefaultParameterConverter.ConvertValue(sbind.out.Dest)
+				if err != nil {
+					binds = append(binds, sbind)
+					stmt.conn.freeBinds(binds)
+					return nil, err
+				}
 			}
 			switch valueInterface.(type) {
 			case nil:
@@ -120,10 +297,29 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 					valueInterface = int64(0)
 				case *sql.NullString:
 					valueInterface = ""
+				case *sql.NullTime:
+					valueInterface = time.Time{}
 				}
 			}
 		}
 
+		if value, ok := valueInterface.(bool); ok && !isOut && stmt.conn.converter != nil && argument.dataType != C.SQLT_BOL {
+			valueInterface, err = stmt.conn.converter.ConvertBool(value)
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, fmt.Errorf("convert bool for column %v - error: %v", i, err)
+			}
+		}
+		if value, ok := valueInterface.(bool); ok && !isOut && stmt.conn.yesNoBools && argument.dataType != C.SQLT_BOL {
+			valueInterface = yesNoText(value)
+		}
+
+		if value, ok := valueInterface.(time.Duration); ok && stmt.conn.intervalInt64 {
+			// int64 nanoseconds, like INTERVAL DAY TO SECOND values are returned with interval_int64
+			valueInterface = int64(value)
+		}
+
 		switch value := valueInterface.(type) {
 
 		case nil:
@@ -135,11 +331,11 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 		case []byte:
 			if isOut {
 
-				if len(value) > 32767 {
+				if len(value) > 32767 || argument.dataType == C.SQLT_BLOB {
 					var lobP *unsafe.Pointer
 					lobP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_LOB, 0)
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
 					sbind.dataType = C.SQLT_BLOB
@@ -147,15 +343,17 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 					sbind.maxSize = C.sb4(sizeOfNilPointer)
 					*sbind.length = C.ub2(sizeOfNilPointer)
 					lobLocator := (**C.OCILobLocator)(sbind.pbuf)
-					err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, C.OCI_TEMP_BLOB)
+					err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, C.OCI_TEMP_BLOB, tempLobOptions)
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
-					err = stmt.conn.ociLobWrite(*lobLocator, C.SQLCS_IMPLICIT, value)
-					if err != nil {
-						freeBinds(binds)
-						return nil, err
+					if len(value) > 0 {
+						err = stmt.conn.ociLobWrite(*lobLocator, C.SQLCS_IMPLICIT, value)
+						if err != nil {
+							stmt.conn.freeBinds(binds)
+							return nil, err
+						}
 					}
 				} else {
 					sbind.dataType = C.SQLT_BIN
@@ -174,7 +372,7 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 					var lobP *unsafe.Pointer
 					lobP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_LOB, 0)
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
 					sbind.dataType = C.SQLT_BLOB
@@ -182,20 +380,21 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 					sbind.maxSize = C.sb4(sizeOfNilPointer)
 					*sbind.length = C.ub2(sizeOfNilPointer)
 					lobLocator := (**C.OCILobLocator)(sbind.pbuf)
-					err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, C.OCI_TEMP_BLOB)
+					err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, C.OCI_TEMP_BLOB, tempLobOptions)
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
 					err = stmt.conn.ociLobWrite(*lobLocator, C.SQLCS_IMPLICIT, value)
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
 				} else {
+					maxSize := bindLengthBand(len(value))
 					sbind.dataType = C.SQLT_BIN
-					sbind.pbuf = unsafe.Pointer(cByte(value))
-					sbind.maxSize = C.sb4(len(value))
+					sbind.pbuf = unsafe.Pointer(cByteN(value, maxSize))
+					sbind.maxSize = C.sb4(maxSize)
 					*sbind.length = C.ub2(len(value))
 				}
 
@@ -208,20 +407,74 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 
 			dateTimePP, err := stmt.conn.timeToOCIDateTime(&value)
 			if err != nil {
-				freeBinds(binds)
+				stmt.conn.freeBinds(binds)
 				return nil, fmt.Errorf("timeToOCIDateTime for column %v - error: %v", i, err)
 			}
 
 			sbind.pbuf = unsafe.Pointer(dateTimePP)
+			if isOut && sbind.out.In && isNill {
+				*sbind.indicator = -1 // set to null
+			}
+
+		case time.Duration:
+			sbind.dataType = C.SQLT_INTERVAL_DS
+			sbind.maxSize = C.sb4(sizeOfNilPointer)
+			*sbind.length = C.ub2(sizeOfNilPointer)
+
+			intervalPP, err := stmt.conn.durationToOCIInterval(value)
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, fmt.Errorf("durationToOCIInterval for column %v - error: %v", i, err)
+			}
+
+			sbind.pbuf = unsafe.Pointer(intervalPP)
+
+		case YearMonth:
+			sbind.dataType = C.SQLT_INTERVAL_YM
+			sbind.maxSize = C.sb4(sizeOfNilPointer)
+			*sbind.length = C.ub2(sizeOfNilPointer)
+
+			intervalPP, err := stmt.conn.yearMonthToOCIInterval(value)
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, fmt.Errorf("yearMonthToOCIInterval for column %v - error: %v", i, err)
+			}
+
+			sbind.pbuf = unsafe.Pointer(intervalPP)
 
 		case string:
+			// the value as text in the character set of the environment, the length in bytes decides if it is bound as CLOB
+			text := value
+			if stmt.conn.utf16 {
+				text = string(utf16Encode(value))
+			}
+
 			if isOut {
 
-				if len(value) > 32767 {
+				if argument.dataType == C.SQLT_NUM {
+					// NUMBER argument, bind as NUMBER so the value does not depend on the session NLS numeric characters
+					var number []byte
+					if sbind.out.In && !isNill && value != "" {
+						number, err = encodeNumber(value)
+						if err != nil {
+							binds = append(binds, sbind)
+							stmt.conn.freeBinds(binds)
+							return nil, fmt.Errorf("number for column %v - error: %v", i, err)
+						}
+					} else {
+						*sbind.indicator = -1 // set to null
+					}
+					sbind.dataType = C.SQLT_NUM
+					sbind.pbuf = unsafe.Pointer(cByteN(number, 22))
+					sbind.maxSize = 22
+					*sbind.length = C.ub2(len(number))
+				} else if len(text) > 32767 || argument.dataType == C.SQLT_CLOB {
 					var lobP *unsafe.Pointer
 					lobP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_LOB, 0)
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
 					sbind.dataType = C.SQLT_CLOB
@@ -229,22 +482,24 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 					sbind.maxSize = C.sb4(sizeOfNilPointer)
 					*sbind.length = C.ub2(sizeOfNilPointer)
 					lobLocator := (**C.OCILobLocator)(sbind.pbuf)
-					err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, C.OCI_TEMP_CLOB)
+					err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, C.OCI_TEMP_CLOB, tempLobOptions)
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
-					err = stmt.conn.ociLobWrite(*lobLocator, C.SQLCS_IMPLICIT, []byte(value))
-					if err != nil {
-						freeBinds(binds)
-						return nil, err
+					if len(value) > 0 {
+						err = stmt.conn.ociLobWrite(*lobLocator, C.SQLCS_IMPLICIT, []byte(value))
+						if err != nil {
+							stmt.conn.freeBinds(binds)
+							return nil, err
+						}
 					}
 				} else {
 					sbind.dataType = C.SQLT_CHR
-					sbind.pbuf = unsafe.Pointer(cStringN(value, 32768))
+					sbind.pbuf = unsafe.Pointer(cStringN(text, 32768))
 					sbind.maxSize = 32767
 					if sbind.out.In && !isNill {
-						*sbind.length = C.ub2(len(value))
+						*sbind.length = C.ub2(len(text))
 					} else {
 						*sbind.indicator = -1 // set to null
 					}
@@ -252,11 +507,11 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 
 			} else {
 
-				if len(value) > 32767 {
+				if len(text) > 32767 {
 					var lobP *unsafe.Pointer
 					lobP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_LOB, 0)
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
 					sbind.dataType = C.SQLT_CLOB
@@ -264,30 +519,40 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 					sbind.maxSize = C.sb4(sizeOfNilPointer)
 					*sbind.length = C.ub2(sizeOfNilPointer)
 					lobLocator := (**C.OCILobLocator)(sbind.pbuf)
-					err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, C.OCI_TEMP_CLOB)
+					err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, C.OCI_TEMP_CLOB, tempLobOptions)
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
 					err = stmt.conn.ociLobWrite(*lobLocator, C.SQLCS_IMPLICIT, []byte(value))
 					if err != nil {
-						freeBinds(binds)
+						stmt.conn.freeBinds(binds)
 						return nil, err
 					}
 				} else {
+					maxSize := bindLengthBand(len(text))
 					sbind.dataType = C.SQLT_AFC
-					sbind.pbuf = unsafe.Pointer(C.CString(value))
-					sbind.maxSize = C.sb4(len(value))
-					*sbind.length = C.ub2(len(value))
+					sbind.pbuf = unsafe.Pointer(cStringN(text, maxSize+1))
+					sbind.maxSize = C.sb4(maxSize)
+					*sbind.length = C.ub2(len(text))
 				}
 
 			}
 
 		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr:
+			if number, ok := unsignedToNumber(value); ok && !isOut {
+				// over the int64 range of SQLT_INT
+				sbind.dataType = C.SQLT_NUM
+				sbind.pbuf = unsafe.Pointer(cByte(number))
+				sbind.maxSize = C.sb4(len(number))
+				*sbind.length = C.ub2(len(number))
+				break
+			}
+
 			buffer := bytes.Buffer{}
 			err = binary.Write(&buffer, binary.LittleEndian, value)
 			if err != nil {
-				freeBinds(binds)
+				stmt.conn.freeBinds(binds)
 				return nil, fmt.Errorf("binary read for column %v - error: %v", i, err)
 			}
 			sbind.dataType = C.SQLT_INT
@@ -299,10 +564,20 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 			}
 
 		case float32, float64:
+			if !isOut && stmt.conn.floatDecimal {
+				if number, ok := floatToNumber(value, stmt.conn.floatPrecision); ok {
+					sbind.dataType = C.SQLT_NUM
+					sbind.pbuf = unsafe.Pointer(cByte(number))
+					sbind.maxSize = C.sb4(len(number))
+					*sbind.length = C.ub2(len(number))
+					break
+				}
+			}
+
 			buffer := bytes.Buffer{}
 			err = binary.Write(&buffer, binary.LittleEndian, value)
 			if err != nil {
-				freeBinds(binds)
+				stmt.conn.freeBinds(binds)
 				return nil, fmt.Errorf("binary read for column %v - error: %v", i, err)
 			}
 			sbind.dataType = C.SQLT_BDOUBLE
@@ -313,7 +588,28 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 				*sbind.indicator = -1 // set to null
 			}
 
-		case bool: // oracle does not have bool, handle as 0/1 int
+		case bool:
+			if argument.dataType == C.SQLT_BOL {
+				// PL/SQL BOOLEAN argument
+				if clientMajorVersion() < 12 {
+					binds = append(binds, sbind)
+					stmt.conn.freeBinds(binds)
+					return nil, &BindError{Index: i, Err: ErrPlsqlBooleanUnsupported}
+				}
+				sbind.dataType = C.SQLT_BOL
+				sbind.pbuf = C.malloc(C.sizeof_int)
+				*(*C.int)(sbind.pbuf) = 0
+				if value {
+					*(*C.int)(sbind.pbuf) = 1
+				}
+				sbind.maxSize = C.sizeof_int
+				*sbind.length = C.sizeof_int
+				if isOut && sbind.out.In && isNill {
+					*sbind.indicator = -1 // set to null
+				}
+				break
+			}
+			// oracle SQL does not have bool, handle as 0/1 int
 			sbind.dataType = C.SQLT_INT
 			if value {
 				sbind.pbuf = unsafe.Pointer(cByte([]byte{1}))
@@ -326,6 +622,123 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 				*sbind.indicator = -1 // set to null
 			}
 
+		case **Rows: // REF CURSOR OUT bind
+			var stmtP *unsafe.Pointer
+			stmtP, _, err = stmt.conn.ociHandleAlloc(C.OCI_HTYPE_STMT, 0)
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, fmt.Errorf("allocate cursor statement handle for column %v - error: %v", i, err)
+			}
+			sbind.dataType = C.SQLT_RSET
+			sbind.pbuf = unsafe.Pointer(stmtP)
+			sbind.maxSize = 0
+
+		case *[]int64, *[]float64, *[]string, *plsqlStringArray: // PL/SQL array OUT bind
+			err = plsqlArrayBind(&sbind, value, stmt.conn.utf16)
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, fmt.Errorf("PL/SQL array for column %v - error: %v", i, err)
+			}
+
+		case Batch:
+			binds = append(binds, sbind)
+			stmt.conn.freeBinds(binds)
+			return nil, &BindError{Index: i, Err: ErrBatchQuery}
+
+		case LobStream:
+			lobType := C.ub1(C.OCI_TEMP_BLOB)
+			sbind.dataType = C.SQLT_BLOB
+			if value.Text {
+				lobType = C.OCI_TEMP_CLOB
+				sbind.dataType = C.SQLT_CLOB
+			}
+			var lobP *unsafe.Pointer
+			lobP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_LOB, 0)
+			if err != nil {
+				stmt.conn.freeBinds(binds)
+				return nil, err
+			}
+			sbind.pbuf = unsafe.Pointer(lobP)
+			sbind.maxSize = C.sb4(sizeOfNilPointer)
+			*sbind.length = C.ub2(sizeOfNilPointer)
+			lobLocator := (**C.OCILobLocator)(sbind.pbuf)
+			err = stmt.conn.ociLobCreateTemporary(*lobLocator, C.SQLCS_IMPLICIT, lobType, tempLobOptions)
+			if err == nil {
+				err = stmt.conn.ociLobWriteFrom(*lobLocator, C.SQLCS_IMPLICIT, value.Reader)
+			}
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, &BindError{Index: i, Err: err}
+			}
+
+		case *TempLob:
+			err = stmt.bindTempLob(&sbind, value)
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, &BindError{Index: i, Err: err}
+			}
+
+		case *big.Int, *big.Rat, *big.Float:
+			// NUMBER bytes, so the value does not go through float64 or depend on the session NLS numeric characters
+			var number []byte
+			number, err = bigToNumber(value)
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, &BindError{Index: i, Err: err}
+			}
+			if isOut && number == nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, &BindError{Index: i, Err: fmt.Errorf("sql.Out destination is a nil %T", value)}
+			}
+			sbind.dataType = C.SQLT_NUM
+			sbind.pbuf = unsafe.Pointer(cByteN(number, 22))
+			sbind.maxSize = 22
+			*sbind.length = C.ub2(len(number))
+			if number == nil || (isOut && !sbind.out.In) {
+				*sbind.indicator = -1 // set to null
+			}
+
+		case []float32, []float64: // VECTOR, an empty slice is bound as null
+			if vectorDimension(value) < 1 {
+				sbind.dataType = C.SQLT_AFC
+				sbind.pbuf = nil
+				sbind.maxSize = 0
+				*sbind.indicator = -1 // set to null
+				break
+			}
+			var vectorP *unsafe.Pointer
+			vectorP, err = stmt.conn.ociVectorFromSlice(value)
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, fmt.Errorf("vector for column %v - error: %v", i, err)
+			}
+			sbind.dataType = C.SQLT_VEC
+			sbind.pbuf = unsafe.Pointer(vectorP)
+			sbind.maxSize = C.sb4(sizeOfNilPointer)
+			*sbind.length = C.ub2(sizeOfNilPointer)
+
+		case NumberList, Int64List, StringList: // SYS.ODCINUMBERLIST or SYS.ODCIVARCHAR2LIST, for table(:1)
+			if isOut {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, &BindError{Index: i, Err: fmt.Errorf("%T can not be an sql.Out destination", value)}
+			}
+			sbind.pbuf, sbind.tdo, err = stmt.conn.ociCollectionFromList(value)
+			if err != nil {
+				binds = append(binds, sbind)
+				stmt.conn.freeBinds(binds)
+				return nil, fmt.Errorf("collection for column %v - error: %v", i, err)
+			}
+			sbind.dataType = C.SQLT_NTY
+			sbind.maxSize = 0
+
 		default:
 			if isOut {
 				// TODO: should this error instead of setting to null?
@@ -336,9 +749,13 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 				*sbind.indicator = -1 // set to null
 			} else {
 				d := fmt.Sprintf("%v", value)
+				if stmt.conn.utf16 {
+					d = string(utf16Encode(d))
+				}
+				maxSize := bindLengthBand(len(d))
 				sbind.dataType = C.SQLT_AFC
-				sbind.pbuf = unsafe.Pointer(C.CString(d))
-				sbind.maxSize = C.sb4(len(d))
+				sbind.pbuf = unsafe.Pointer(cStringN(d, maxSize+1))
+				sbind.maxSize = C.sb4(maxSize)
 				*sbind.length = C.ub2(len(d))
 			}
 		}
@@ -350,10 +767,13 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 			err = stmt.ociBindByPos(C.ub4(i+1), &sbind)
 			// TODO: should we use namedValues[i]Ordinal?
 		} else {
-			err = stmt.ociBindByName([]byte(":"+namedValues[i].Name), &sbind)
+			err = stmt.ociBindByName(stmt.conn.encodeText(":"+namedValues[i].Name), &sbind)
+		}
+		if err == nil && sbind.dataType == C.SQLT_NTY {
+			err = stmt.ociBindObject(&sbind)
 		}
 		if err != nil {
-			freeBinds(binds)
+			stmt.conn.freeBinds(binds)
 			return nil, err
 		}
 
@@ -362,8 +782,55 @@ func (stmt *Stmt) bindValues(values []driver.Value, namedValues []driver.NamedVa
 	return binds, nil
 }
 
+// bindLengthBands are the max sizes of the character and RAW binds, the same bands the server uses for bind lengths.
+// The server creates a new child cursor when the max size of a bind moves to a higher band,
+// so binding the max size of the band, not the length of the value, keeps values of growing length
+// from creating a child cursor for each length, even on servers that do not band the lengths themselves.
+var bindLengthBands = []int{32, 128, 2000, 4000}
+
+// bindLengthBand returns the max size of a character or RAW bind of length bytes, the smallest band it fits in,
+// or the length for values longer than the highest band
+func bindLengthBand(length int) int {
+	for _, band := range bindLengthBands {
+		if length <= band {
+			return band
+		}
+	}
+	return length
+}
+
+// validateBinds checks the bind count and bind names, of at most bindNameLength bytes, before anything is bound,
+// so an error can be returned with the offending bind instead of an ORA error from the server
+func validateBinds(namedValues []driver.NamedValue, count int, bindNameLength int) error {
+	if count > maxBindCount {
+		bindError := &BindError{Index: maxBindCount, Err: ErrTooManyBinds}
+		if len(namedValues) > maxBindCount {
+			bindError.Name = namedValues[maxBindCount].Name
+		}
+		return bindError
+	}
+
+	for i := 0; i < len(namedValues); i++ {
+		if len(namedValues[i].Name) < 1 {
+			continue
+		}
+		err := validateBindName(namedValues[i].Name, bindNameLength)
+		if err != nil {
+			return &BindError{Index: i, Name: namedValues[i].Name, Err: err}
+		}
+	}
+
+	return nil
+}
+
 // Query runs a query
 func (stmt *Stmt) Query(values []driver.Value) (driver.Rows, error) {
+	err := stmt.guard.enter("Stmt", "Query", stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.guard.exit(stmt.conn.debugConcurrentUse)
+
 	stmt.ctx = context.Background()
 	binds, err := stmt.bindValues(values, nil)
 	if err != nil {
@@ -375,18 +842,47 @@ func (stmt *Stmt) Query(values []driver.Value) (driver.Rows, error) {
 
 // QueryContext runs a query with context
 func (stmt *Stmt) QueryContext(ctx context.Context, namedValues []driver.NamedValue) (driver.Rows, error) {
+	err := stmt.guard.enter("Stmt", "QueryContext", stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.guard.exit(stmt.conn.debugConcurrentUse)
+
+	ctx, cancel := stmt.optionsContext(ctx)
 	stmt.ctx = ctx
 	binds, err := stmt.bindValues(nil, namedValues)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	return stmt.query(binds)
+	rows, err := stmt.query(binds)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	rows.(*Rows).cancel = cancel
+	return rows, nil
+}
+
+// optionsContext returns the context of a query or exec, with the statement options of the prepare context
+// when ctx has none, and with the Timeout of the statement options. The cancel function must be called when done.
+func (stmt *Stmt) optionsContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	options, ok := stmtOptionsFromContext(ctx)
+	if !ok && stmt.options != nil {
+		options = *stmt.options
+		ctx = WithStmtOptions(ctx, options)
+	}
+	if options.Timeout > 0 {
+		return context.WithTimeout(ctx, options.Timeout)
+	}
+	return ctx, func() {}
 }
 
 // query runs a query with context
 func (stmt *Stmt) query(binds []bindStruct) (driver.Rows, error) {
-	defer freeBinds(binds)
+	defer stmt.conn.freeBinds(binds)
+	defer stmt.conn.setRequestClientInfo(requestIDFromContext(stmt.ctx))()
 
 	var stmtType C.ub2
 	_, err := stmt.ociAttrGet(unsafe.Pointer(&stmtType), C.OCI_ATTR_STMT_TYPE)
@@ -399,62 +895,186 @@ func (stmt *Stmt) query(binds []bindStruct) (driver.Rows, error) {
 		iter = 0
 	}
 
-	if stmt.conn.prefetchRows != 1 {
-		prefetchRows := stmt.conn.prefetchRows
-		// OCI_ATTR_PREFETCH_ROWS sets the number of top level rows to be prefetched. The default value is 1 row. Value of 0 seems to mean only prefetch memory size limits the number of rows to prefetch.
-		err = stmt.conn.ociAttrSet(unsafe.Pointer(stmt.stmt), C.OCI_HTYPE_STMT, unsafe.Pointer(&prefetchRows), 0, C.OCI_ATTR_PREFETCH_ROWS)
-		if err != nil {
-			return nil, err
-		}
+	prefetchRows := stmt.conn.prefetchRows
+	prefetchMemory := stmt.conn.prefetchMemory
+	contextPrefetch, hasContextPrefetch := prefetchFromContext(stmt.ctx)
+	if hasContextPrefetch {
+		prefetchRows = C.ub4(contextPrefetch.rows)
+		prefetchMemory = C.ub4(contextPrefetch.memory)
 	}
 
-	if stmt.conn.prefetchMemory > 0 {
-		prefetchMemory := stmt.conn.prefetchMemory
-		// OCI_ATTR_PREFETCH_MEMORY sets the memory level for top level rows to be prefetched. Rows up to the specified top level row count are fetched if it occupies no more than the specified memory usage limit.
-		// The default value is 0, which means that memory size is not included in computing the number of rows to prefetch.
-		err = stmt.conn.ociAttrSet(unsafe.Pointer(stmt.stmt), C.OCI_HTYPE_STMT, unsafe.Pointer(&prefetchMemory), 0, C.OCI_ATTR_PREFETCH_MEMORY)
-		if err != nil {
-			return nil, err
-		}
+	// the prefetch is set for every query, as a statement handle, prepared or from the statement cache,
+	// keeps the prefetch a previous query set with WithPrefetch
+	// OCI_ATTR_PREFETCH_ROWS sets the number of top level rows to be prefetched. The default value is 1 row. Value of 0 seems to mean only prefetch memory size limits the number of rows to prefetch.
+	err = stmt.conn.ociAttrSet(unsafe.Pointer(stmt.stmt), C.OCI_HTYPE_STMT, unsafe.Pointer(&prefetchRows), 0, C.OCI_ATTR_PREFETCH_ROWS)
+	if err != nil {
+		return nil, err
 	}
 
-	mode := C.ub4(C.OCI_DEFAULT)
-	if !stmt.conn.inTransaction {
-		mode = mode | C.OCI_COMMIT_ON_SUCCESS
+	memoryBudget := memoryBudgetFromContext(stmt.ctx)
+	if memoryBudget > 0 && (prefetchMemory == 0 || int64(prefetchMemory) > memoryBudget) {
+		// lower the prefetch memory to the budget, OCI prefetches less rows to stay within it
+		prefetchMemory = math.MaxUint32
+		if memoryBudget < math.MaxUint32 {
+			prefetchMemory = C.ub4(memoryBudget)
+		}
+	}
+	// OCI_ATTR_PREFETCH_MEMORY sets the memory level for top level rows to be prefetched. Rows up to the specified top level row count are fetched if it occupies no more than the specified memory usage limit.
+	// The default value is 0, which means that memory size is not included in computing the number of rows to prefetch.
+	err = stmt.conn.ociAttrSet(unsafe.Pointer(stmt.stmt), C.OCI_HTYPE_STMT, unsafe.Pointer(&prefetchMemory), 0, C.OCI_ATTR_PREFETCH_MEMORY)
+	if err != nil {
+		return nil, err
 	}
 
+	mode := stmt.conn.queryExecuteMode()
+
 	if stmt.ctx.Err() != nil {
 		return nil, stmt.ctx.Err()
 	}
 
-	done := make(chan struct{})
-	go stmt.conn.ociBreakDone(stmt.ctx, done)
+	stop := stmt.conn.watchBreak(stmt.ctx)
+	start := stmt.conn.beforeQuery(stmt.ctx, stmt.hookQuery())
 	err = stmt.ociStmtExecute(iter, mode)
-	close(done)
+	stop()
+	stmt.conn.afterQuery(stmt.ctx, stmt.hookQuery(), stmt.queryFingerprint(), stmt.hookArgs, start, err)
 	if err != nil {
 		return nil, err
 	}
+	stmt.conn.queryExecuted()
+
+	if stmtType == C.OCI_STMT_BEGIN || stmtType == C.OCI_STMT_DECLARE || stmtType == C.OCI_STMT_CALL {
+		rows, ok, err := stmt.implicitResultRows()
+		if ok || err != nil {
+			return rows, err
+		}
+	}
 
 	var defines []defineStruct
-	defines, err = stmt.makeDefines()
+	longPieces := longPiecesFromContext(stmt.ctx)
+	defines, err = stmt.makeDefines(scanColumnsFromContext(stmt.ctx), longPieces != nil)
 	if err != nil {
 		return nil, err
 	}
 
+	if memoryBudget > 0 {
+		rowSize := defineRowSize(defines)
+		if rowSize > memoryBudget {
+			freeDefines(defines)
+			return nil, &MemoryBudgetError{Budget: memoryBudget, RowSize: rowSize}
+		}
+		// the row size is known after the execute, lower the prefetch rows of the next round trips to the rows that fit in the budget
+		if budgetRows := budgetPrefetchRows(uint32(prefetchRows), memoryBudget, rowSize); budgetRows != uint32(prefetchRows) {
+			prefetchRows = C.ub4(budgetRows)
+			err = stmt.conn.ociAttrSet(unsafe.Pointer(stmt.stmt), C.OCI_HTYPE_STMT, unsafe.Pointer(&prefetchRows), 0, C.OCI_ATTR_PREFETCH_ROWS)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+		}
+	}
+
+	fetchArraySize := stmt.fetchArraySize(defines, memoryBudget)
+	if fetchArraySize > 1 && defineArraysSupported(defines) {
+		err = stmt.defineArrays(defines, fetchArraySize)
+		if err != nil {
+			freeDefines(defines)
+			return nil, err
+		}
+	} else {
+		fetchArraySize = 0
+	}
+
 	if stmt.ctx.Err() != nil {
 		freeDefines(defines)
 		return nil, stmt.ctx.Err()
 	}
 
+	maxRows := maxRowsFromContext(stmt.ctx)
+	if maxRows < 1 {
+		maxRows = stmt.conn.maxRows
+	}
+
+	// tune the prefetch rows after the first row when the query does not set the prefetch
+	var prefetchTarget int64
+	if !hasContextPrefetch {
+		prefetchTarget = stmt.conn.prefetchTarget
+		if memoryBudget > 0 && prefetchTarget > memoryBudget {
+			prefetchTarget = memoryBudget
+		}
+	}
+
 	rows := &Rows{
-		stmt:    stmt,
-		defines: defines,
+		stmt:           stmt,
+		defines:        defines,
+		longPieces:     longPieces,
+		maxRows:        maxRows,
+		lobReaders:     lobReadersFromContext(stmt.ctx),
+		rawBytes:       rawBytesFromContext(stmt.ctx),
+		prefetchTarget: prefetchTarget,
+		fetchArraySize: fetchArraySize,
 	}
 
 	return rows, nil
 }
 
-func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
+// describeColumn sets the describe information of a select list column used by the ColumnType methods
+func (conn *Conn) describeColumn(param *C.OCIParam, define *defineStruct) error {
+	var isNull C.ub1
+	_, err := conn.ociAttrGet(param, unsafe.Pointer(&isNull), C.OCI_ATTR_IS_NULL)
+	if err != nil {
+		return err
+	}
+	define.nullable = isNull != 0
+
+	switch define.columnType {
+	case C.SQLT_AFC, C.SQLT_CHR, C.SQLT_VCS, C.SQLT_AVC:
+		_, err = conn.ociAttrGet(param, unsafe.Pointer(&define.charSize), C.OCI_ATTR_CHAR_SIZE)
+		if err != nil {
+			return err
+		}
+	case C.SQLT_NUM:
+		_, err = conn.ociAttrGet(param, unsafe.Pointer(&define.precision), C.OCI_ATTR_PRECISION)
+		if err != nil {
+			return err
+		}
+		_, err = conn.ociAttrGet(param, unsafe.Pointer(&define.scale), C.OCI_ATTR_SCALE)
+		if err != nil {
+			return err
+		}
+	case C.SQLT_NTY:
+		var typeName *C.OraText
+		var size C.ub4
+		size, err = conn.ociAttrGet(param, unsafe.Pointer(&typeName), C.OCI_ATTR_TYPE_NAME)
+		if err != nil {
+			return err
+		}
+		define.typeName = conn.goText(typeName, int(size))
+		var typeSchema *C.OraText
+		size, err = conn.ociAttrGet(param, unsafe.Pointer(&typeSchema), C.OCI_ATTR_SCHEMA_NAME)
+		if err != nil {
+			return err
+		}
+		define.typeSchema = conn.goText(typeSchema, int(size))
+	case C.SQLT_VEC:
+		// needs a 23ai client, and VECTOR(*, *) columns have no format, ignore the error
+		_, _ = conn.ociAttrGet(param, unsafe.Pointer(&define.vectorFormat), C.OCI_ATTR_VECTOR_DATA_FORMAT)
+	}
+
+	return nil
+}
+
+// numberStrings returns true when NUMBER columns are returned as decimal strings, see WithNumberStrings
+func (stmt *Stmt) numberStrings() bool {
+	if enabled, ok := numberStringsFromContext(stmt.ctx); ok {
+		return enabled
+	}
+	return stmt.conn.numberStrings
+}
+
+// makeDefines defines the select-list columns.
+// Columns not wanted by scanColumns are defined without a data buffer, only the indicator is fetched.
+// When piecewise is true, LONG and LONG RAW columns are defined for piecewise fetching.
+func (stmt *Stmt) makeDefines(scanColumns *scanColumns, piecewise bool) ([]defineStruct, error) {
 	var paramCountUb4 C.ub4 // number of columns in the select-list
 	_, err := stmt.ociAttrGet(unsafe.Pointer(&paramCountUb4), C.OCI_ATTR_PARAM_COUNT)
 	if err != nil {
@@ -476,7 +1096,7 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 			freeDefines(defines)
 			return nil, err
 		}
-		defer C.OCIDescriptorFree(unsafe.Pointer(param), C.OCI_DTYPE_PARAM)
+		defer ociDescriptorFree(unsafe.Pointer(param), C.OCI_DTYPE_PARAM)
 
 		var dataType C.ub2 // external datatype of the column: https://docs.oracle.com/cd/E11882_01/appdev.112/e10646/oci03typ.htm#CEGIEEJI
 		_, err = stmt.conn.ociAttrGet(param, unsafe.Pointer(&dataType), C.OCI_ATTR_DATA_TYPE)
@@ -492,7 +1112,7 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 			freeDefines(defines)
 			return nil, err
 		}
-		defines[i].name = cGoStringN(columnName, int(size))
+		defines[i].name = stmt.conn.goName(columnName, int(size))
 
 		var maxSize C.ub4 // Maximum size in bytes of the external data for the column. This can affect conversion buffer sizes.
 		_, err = stmt.conn.ociAttrGet(param, unsafe.Pointer(&maxSize), C.OCI_ATTR_DATA_SIZE)
@@ -501,11 +1121,74 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 			return nil, err
 		}
 
+		_, err = stmt.conn.ociAttrGet(param, unsafe.Pointer(&defines[i].charsetID), C.OCI_ATTR_CHARSET_ID)
+		if err != nil {
+			freeDefines(defines)
+			return nil, err
+		}
+		_, err = stmt.conn.ociAttrGet(param, unsafe.Pointer(&defines[i].charsetForm), C.OCI_ATTR_CHARSET_FORM)
+		if err != nil {
+			freeDefines(defines)
+			return nil, err
+		}
+		// collations need Oracle 12.2 or later, ignore the error of older versions
+		_, _ = stmt.conn.ociAttrGet(param, unsafe.Pointer(&defines[i].collationID), C.OCI_ATTR_COLLATION_ID)
+
+		defines[i].columnType = dataType
+		defines[i].dataSize = maxSize
+		err = stmt.conn.describeColumn(param, &defines[i])
+		if err != nil {
+			freeDefines(defines)
+			return nil, err
+		}
+
 		defines[i].length = (*C.ub2)(C.malloc(C.sizeof_ub2))
 		*defines[i].length = 0
 		defines[i].indicator = (*C.sb2)(C.malloc(C.sizeof_sb2))
 		*defines[i].indicator = 0
 
+		if defines[i].isSDOGeometry() {
+			// object define, the value is set by OCI in the object cache
+			err = stmt.defineSDOGeometry(C.ub4(i+1), &defines[i])
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			continue
+		}
+
+		// objects, BFILEs, and cursors can not be converted to text, they keep their define
+		if dataType != C.SQLT_NTY && dataType != C.SQLT_BFILE && dataType != C.SQLT_RSET && !scanColumns.wants(i, defines[i].name) {
+			// indicator only define, binary types need a binary define type to avoid a conversion error
+			defines[i].skip = true
+			switch dataType {
+			case C.SQLT_BIN, C.SQLT_BLOB, C.SQLT_LBI:
+				defines[i].dataType = C.SQLT_BIN
+			default:
+				defines[i].dataType = C.SQLT_CHR
+			}
+			defines[i].maxSize = 0
+			err = stmt.ociDefineByPos(C.ub4(i+1), &defines[i])
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			continue
+		}
+
+		if piecewise && (dataType == C.SQLT_LNG || dataType == C.SQLT_LBI) {
+			// dynamic fetch define, the piece buffers are set with OCIStmtSetPieceInfo while fetching
+			defines[i].piecewise = true
+			defines[i].dataType = dataType
+			defines[i].maxSize = C.SB4MAXVAL
+			err = stmt.ociDefineByPos(C.ub4(i+1), &defines[i])
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			continue
+		}
+
 		// switch on dataType
 		switch dataType {
 
@@ -522,19 +1205,17 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
 
 		case C.SQLT_NUM:
-			var precision C.sb2 // the precision
-			_, err = stmt.conn.ociAttrGet(param, unsafe.Pointer(&precision), C.OCI_ATTR_PRECISION)
-			if err != nil {
-				freeDefines(defines)
-				return nil, err
+			if stmt.numberStrings() {
+				// NUMBER bytes, decoded to an exact decimal string
+				defines[i].dataType = C.SQLT_NUM
+				defines[i].maxSize = 22
+				defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
+				break
 			}
 
-			var scale C.sb1 // the scale (number of digits to the right of the decimal point)
-			_, err = stmt.conn.ociAttrGet(param, unsafe.Pointer(&scale), C.OCI_ATTR_SCALE)
-			if err != nil {
-				freeDefines(defines)
-				return nil, err
-			}
+			precision := defines[i].precision
+			// the scale (number of digits to the right of the decimal point)
+			scale := defines[i].scale
 
 			// The precision of numeric type attributes. If the precision is nonzero and scale is -127, then it is a FLOAT;
 			// otherwise, it is a NUMBER(precision, scale).
@@ -566,6 +1247,10 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 		case C.SQLT_LNG:
 			defines[i].dataType = C.SQLT_LNG
 			defines[i].maxSize = 4000
+			if stmt.conn.utf16 {
+				// 4000 characters of UTF-16
+				defines[i].maxSize = 8000
+			}
 			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
 
 		case C.SQLT_CLOB, C.SQLT_BLOB:
@@ -623,6 +1308,17 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 			}
 			defines[i].pbuf = unsafe.Pointer(intervalP)
 
+		case C.SQLT_VEC: // VECTOR
+			defines[i].dataType = dataType
+			defines[i].maxSize = C.sb4(sizeOfNilPointer)
+			var vectorP *unsafe.Pointer
+			vectorP, _, err = stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_VECTOR, 0)
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
+			defines[i].pbuf = unsafe.Pointer(vectorP)
+
 		case C.SQLT_RDD: // rowid
 			defines[i].dataType = C.SQLT_AFC
 			defines[i].maxSize = 40
@@ -642,38 +1338,85 @@ func (stmt *Stmt) makeDefines() ([]defineStruct, error) {
 		default:
 			defines[i].dataType = C.SQLT_AFC
 			defines[i].maxSize = C.sb4(maxSize)
+			if stmt.conn.utf16 {
+				// UTF-16 text is up to 2 bytes a byte of single byte character sets
+				defines[i].maxSize *= 2
+			}
 			defines[i].pbuf = C.malloc(C.size_t(defines[i].maxSize))
 		}
 
-		result := C.OCIDefineByPos(
-			stmt.stmt,                            // statement handle
-			&defines[i].defineHandle,             // pointer to a pointer to a define handle. If NULL, this call implicitly allocates the define handle.
-			stmt.conn.errHandle,                  // error handle
-			C.ub4(i+1),                           // position of this value in the select list. Positions are 1-based and are numbered from left to right.
-			defines[i].pbuf,                      // pointer to a buffer
-			defines[i].maxSize,                   // size of each valuep buffer in bytes
-			defines[i].dataType,                  // datatype
-			unsafe.Pointer(defines[i].indicator), // pointer to an indicator variable or array
-			defines[i].length,                    // pointer to array of length of data fetched
-			nil,                                  // pointer to array of column-level return codes
-			C.OCI_DEFAULT,                        // mode - OCI_DEFAULT - This is the default mode.
-		)
-		if result != C.OCI_SUCCESS {
+		err = stmt.ociDefineByPos(C.ub4(i+1), &defines[i])
+		if err != nil {
 			freeDefines(defines)
-			return nil, stmt.conn.getError(result)
+			return nil, err
+		}
+
+		if stmt.conn.lobPrefetchSize > 0 && (dataType == C.SQLT_CLOB || dataType == C.SQLT_BLOB) {
+			err = stmt.setLobPrefetch(&defines[i])
+			if err != nil {
+				freeDefines(defines)
+				return nil, err
+			}
 		}
 	}
 
 	return defines, nil
 }
 
+// setLobPrefetch sets the LOB prefetch size of a LOB define, so the start of the LOB data and the LOB length
+// are fetched with the row, see the lob_prefetch_size DSN parameter
+func (stmt *Stmt) setLobPrefetch(define *defineStruct) error {
+	prefetchLength := C.boolean(C.TRUE)
+	err := stmt.conn.ociAttrSet(unsafe.Pointer(define.defineHandle), C.OCI_HTYPE_DEFINE, unsafe.Pointer(&prefetchLength), 0, C.OCI_ATTR_LOBPREFETCH_LENGTH)
+	if err != nil {
+		return fmt.Errorf("LOB prefetch length attribute set error: %v", err)
+	}
+	prefetchSize := stmt.conn.lobPrefetchSize
+	err = stmt.conn.ociAttrSet(unsafe.Pointer(define.defineHandle), C.OCI_HTYPE_DEFINE, unsafe.Pointer(&prefetchSize), 0, C.OCI_ATTR_LOBPREFETCH_SIZE)
+	if err != nil {
+		return fmt.Errorf("LOB prefetch size attribute set error: %v", err)
+	}
+	return nil
+}
+
+// defineRowSize returns the size in bytes of the client buffers used to fetch a row
+func defineRowSize(defines []defineStruct) int64 {
+	var size int64
+	for i := 0; i < len(defines); i++ {
+		size += int64(C.sizeof_sb2 + C.sizeof_ub2) // indicator and length
+		switch {
+		case defines[i].piecewise:
+			size += longPieceSize
+		case defines[i].pbuf != nil:
+			size += int64(defines[i].maxSize)
+		}
+	}
+	return size
+}
+
+// budgetPrefetchRows returns the prefetch rows lowered to the number of rows of rowSize bytes that fit in the memory budget,
+// at least 1. Prefetch rows of 0, only limited by the prefetch memory, are lowered too.
+func budgetPrefetchRows(prefetchRows uint32, memoryBudget int64, rowSize int64) uint32 {
+	budgetRows := memoryBudget / rowSize
+	if budgetRows < 1 {
+		budgetRows = 1
+	}
+	if prefetchRows > 0 && int64(prefetchRows) <= budgetRows {
+		return prefetchRows
+	}
+	if budgetRows > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(budgetRows)
+}
+
 // getRowid returns the rowid
 func (stmt *Stmt) getRowid() (string, error) {
 	rowidP, _, err := stmt.conn.ociDescriptorAlloc(C.OCI_DTYPE_ROWID, 0)
 	if err != nil {
 		return "", err
 	}
-	defer C.OCIDescriptorFree(*rowidP, C.OCI_DTYPE_ROWID)
+	defer ociDescriptorFree(*rowidP, C.OCI_DTYPE_ROWID)
 
 	// OCI_ATTR_ROWID returns the ROWID descriptor allocated with OCIDescriptorAlloc()
 	_, err = stmt.ociAttrGet(*rowidP, C.OCI_ATTR_ROWID)
@@ -681,16 +1424,20 @@ func (stmt *Stmt) getRowid() (string, error) {
 		return "", err
 	}
 
-	rowid := cStringN("", 18)
-	defer C.free(unsafe.Pointer(rowid))
+	// a ROWID is 18 characters, 36 bytes in UTF-16
 	rowidLength := C.ub2(18)
+	if stmt.conn.utf16 {
+		rowidLength = 36
+	}
+	rowid := cStringN("", int(rowidLength))
+	defer C.free(unsafe.Pointer(rowid))
 	result := C.OCIRowidToChar((*C.OCIRowid)(*rowidP), rowid, &rowidLength, stmt.conn.errHandle)
 	err = stmt.conn.getError(result)
 	if err != nil {
 		return "", err
 	}
 
-	return cGoStringN(rowid, int(rowidLength)), nil
+	return stmt.conn.goText(rowid, int(rowidLength)), nil
 }
 
 // rowsAffected returns the number of rows affected
@@ -705,8 +1452,28 @@ func (stmt *Stmt) rowsAffected() (int64, error) {
 
 // Exec runs an exec query
 func (stmt *Stmt) Exec(values []driver.Value) (driver.Result, error) {
+	err := stmt.guard.enter("Stmt", "Exec", stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.guard.exit(stmt.conn.debugConcurrentUse)
+
 	stmt.ctx = context.Background()
-	binds, err := stmt.bindValues(values, nil)
+	if len(values) == 1 {
+		if batch, ok := values[0].(Batch); ok {
+			return stmt.execBatch(batch)
+		}
+	}
+	var namedValues []driver.NamedValue
+	if stmt.identityReturning {
+		// bound by position, with the OUT bind of the identity
+		for i, value := range values {
+			namedValues = append(namedValues, driver.NamedValue{Ordinal: i + 1, Value: value})
+		}
+		namedValues = stmt.identityNamedValues(namedValues)
+		values = nil
+	}
+	binds, err := stmt.bindValues(values, namedValues)
 	if err != nil {
 		return nil, err
 	}
@@ -716,7 +1483,21 @@ func (stmt *Stmt) Exec(values []driver.Value) (driver.Result, error) {
 
 // ExecContext run a exec query with context
 func (stmt *Stmt) ExecContext(ctx context.Context, namedValues []driver.NamedValue) (driver.Result, error) {
+	err := stmt.guard.enter("Stmt", "ExecContext", stmt.conn.debugConcurrentUse)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.guard.exit(stmt.conn.debugConcurrentUse)
+
+	ctx, cancel := stmt.optionsContext(ctx)
+	defer cancel()
 	stmt.ctx = ctx
+	if batch, ok := batchFromNamedValues(namedValues); ok {
+		return stmt.execBatch(batch)
+	}
+	if stmt.identityReturning {
+		namedValues = stmt.identityNamedValues(namedValues)
+	}
 	binds, err := stmt.bindValues(nil, namedValues)
 	if err != nil {
 		return nil, err
@@ -726,22 +1507,38 @@ func (stmt *Stmt) ExecContext(ctx context.Context, namedValues []driver.NamedVal
 }
 
 func (stmt *Stmt) exec(binds []bindStruct) (driver.Result, error) {
-	defer freeBinds(binds)
+	defer stmt.conn.freeBinds(binds)
+	defer stmt.conn.setRequestClientInfo(requestIDFromContext(stmt.ctx))()
 
-	mode := C.ub4(C.OCI_DEFAULT)
-	if stmt.conn.inTransaction == false {
-		mode = mode | C.OCI_COMMIT_ON_SUCCESS
-	}
+	mode := stmt.conn.executeMode()
 
 	if stmt.ctx.Err() != nil {
 		return nil, stmt.ctx.Err()
 	}
 
-	done := make(chan struct{})
-	go stmt.conn.ociBreakDone(stmt.ctx, done)
-	err := stmt.ociStmtExecute(1, mode)
-	close(done)
-	if err != nil && err != ErrOCISuccessWithInfo {
+	err := stmt.checkImplicitCommit()
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		stop := stmt.conn.watchBreak(stmt.ctx)
+		start := stmt.conn.beforeQuery(stmt.ctx, stmt.hookQuery())
+		err := stmt.ociStmtExecute(1, mode)
+		stop()
+		if err != nil && err != ErrOCISuccessWithInfo {
+			stmt.conn.afterExec(stmt.ctx, stmt.hookQuery(), stmt.queryFingerprint(), stmt.hookArgs, start, err)
+			if stmt.conn.resourceBusyRetry(stmt.ctx, stmt.hookQuery(), attempt, err) {
+				continue
+			}
+			return nil, err
+		}
+		stmt.conn.afterExec(stmt.ctx, stmt.hookQuery(), stmt.queryFingerprint(), stmt.hookArgs, start, nil)
+		break
+	}
+
+	err = stmt.conn.groupCommitExecuted()
+	if err != nil {
 		return nil, err
 	}
 
@@ -759,9 +1556,72 @@ func (stmt *Stmt) exec(binds []bindStruct) (driver.Result, error) {
 		return nil, err
 	}
 
+	if stmt.conn.identityReturning {
+		result.identityReturning = true
+		result.identityErr = ErrNoIdentity
+		if stmt.identityReturning && result.rowsAffected > 0 {
+			result.identity, result.identityErr = stmt.identity, nil
+		}
+	}
+
 	return &result, nil
 }
 
+// outputBoundBool returns the bool value of an output bind, a PL/SQL BOOLEAN or a 0/1 int
+func outputBoundBool(bind bindStruct) bool {
+	if bind.dataType == C.SQLT_BOL {
+		return *(*C.int)(bind.pbuf) != 0
+	}
+	buf := (*[1 << 30]byte)(bind.pbuf)[0:1]
+	return buf[0] != 0
+}
+
+// outputBoundString returns the string value of an output bind that is not null
+func (stmt *Stmt) outputBoundString(bind bindStruct) (string, error) {
+	switch bind.dataType {
+	case C.SQLT_CLOB:
+		lobLocator := (**C.OCILobLocator)(bind.pbuf)
+		buffer, err := stmt.conn.ociLobRead(*lobLocator, C.SQLCS_IMPLICIT)
+		if err != nil {
+			return "", err
+		}
+		return string(buffer), nil
+	case C.SQLT_NUM:
+		return decodeNumber(C.GoBytes(bind.pbuf, C.int(*bind.length)))
+	}
+	return stmt.conn.goText((*C.OraText)(bind.pbuf), int(*bind.length)), nil
+}
+
+// outputBoundTime returns the time value of an output bind that is not null
+func (stmt *Stmt) outputBoundTime(bind bindStruct) (time.Time, error) {
+	aTime, err := stmt.conn.ociDateTimeToTime(*(**C.OCIDateTime)(bind.pbuf), true)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return *aTime, nil
+}
+
+// outputBoundCursor returns the rows of a REF CURSOR output bind, that free the cursor statement handle when closed
+func (stmt *Stmt) outputBoundCursor(bind bindStruct) (*Rows, error) {
+	cursorStmt := &Stmt{conn: stmt.conn, stmt: *(**C.OCIStmt)(bind.pbuf), ctx: stmt.ctx, releaseMode: C.ub4(C.OCI_DEFAULT)}
+	rows := &Rows{stmt: cursorStmt, cursor: true}
+	if *bind.indicator == -1 {
+		// a cursor that was not opened has no rows
+		rows.closed = true
+		ociHandleFree(unsafe.Pointer(cursorStmt.stmt), C.OCI_HTYPE_STMT)
+		return rows, nil
+	}
+
+	var err error
+	rows.defines, err = cursorStmt.makeDefines(nil, false)
+	if err != nil {
+		ociHandleFree(unsafe.Pointer(cursorStmt.stmt), C.OCI_HTYPE_STMT)
+		return nil, err
+	}
+	rows.maxRows = stmt.conn.maxRows
+	return rows, nil
+}
+
 // outputBoundParameters sets bound parameters
 func (stmt *Stmt) outputBoundParameters(binds []bindStruct) error {
 	var err error
@@ -770,6 +1630,30 @@ func (stmt *Stmt) outputBoundParameters(binds []bindStruct) error {
 		if bind.pbuf != nil {
 			switch dest := bind.out.Dest.(type) {
 
+			case **Rows:
+				*dest, err = stmt.outputBoundCursor(bind)
+				if err != nil {
+					return fmt.Errorf("output for column %v - error: %v", i, err)
+				}
+				// the rows own the statement handle now
+				binds[i].pbuf = nil
+
+			case *[]int64, *[]float64, *[]string, *plsqlStringArray:
+				outputPlsqlArray(bind, stmt.conn.utf16)
+
+			case *big.Int, *big.Rat, *big.Float:
+				number := "0" // best attempt at Go nil number
+				if *bind.indicator != -1 {
+					number, err = decodeNumber((*[22]byte)(bind.pbuf)[0:*bind.length])
+					if err != nil {
+						return fmt.Errorf("decode number for column %v - error: %v", i, err)
+					}
+				}
+				err = setBigNumber(dest, number)
+				if err != nil {
+					return fmt.Errorf("output for column %v - error: %v", i, err)
+				}
+
 			case *string:
 				switch {
 				case *bind.indicator > 0: // indicator variable is the actual length before truncation
@@ -777,23 +1661,16 @@ func (stmt *Stmt) outputBoundParameters(binds []bindStruct) error {
 					if spaces < 0 {
 						return fmt.Errorf("spaces less than 0 for column %v", i)
 					}
-					*dest = C.GoStringN((*C.char)(bind.pbuf), C.int(*bind.length)) + strings.Repeat(" ", spaces)
+					*dest = stmt.conn.goText((*C.OraText)(bind.pbuf), int(*bind.length)) + strings.Repeat(" ", spaces)
 				case *bind.indicator == 0: // Normal
-					if bind.dataType == C.SQLT_CLOB {
-						lobLocator := (**C.OCILobLocator)(bind.pbuf)
-						var buffer []byte
-						buffer, err = stmt.conn.ociLobRead(*lobLocator, C.SQLCS_IMPLICIT)
-						if err != nil {
-							return err
-						}
-						*dest = string(buffer)
-					} else {
-						*dest = C.GoStringN((*C.char)(bind.pbuf), C.int(*bind.length))
+					*dest, err = stmt.outputBoundString(bind)
+					if err != nil {
+						return fmt.Errorf("output for column %v - error: %v", i, err)
 					}
 				case *bind.indicator == -1: // The selected value is null
 					*dest = "" // best attempt at Go nil string
 				case *bind.indicator == -2: // Item is greater than the length of the output variable; the item has been truncated.
-					*dest = C.GoStringN((*C.char)(bind.pbuf), C.int(*bind.length))
+					*dest = stmt.conn.goText((*C.OraText)(bind.pbuf), int(*bind.length))
 					// TODO: should this be an error?
 				default:
 					return fmt.Errorf("unknown column indicator %d for column %v", *bind.indicator, i)
@@ -805,16 +1682,19 @@ func (stmt *Stmt) outputBoundParameters(binds []bindStruct) error {
 					if spaces < 0 {
 						return fmt.Errorf("spaces less than 0 for column %v", i)
 					}
-					dest.String = C.GoStringN((*C.char)(bind.pbuf), C.int(*bind.length)) + strings.Repeat(" ", spaces)
+					dest.String = stmt.conn.goText((*C.OraText)(bind.pbuf), int(*bind.length)) + strings.Repeat(" ", spaces)
 					dest.Valid = true
 				case *bind.indicator == 0: // Normal
-					dest.String = C.GoStringN((*C.char)(bind.pbuf), C.int(*bind.length))
+					dest.String, err = stmt.outputBoundString(bind)
+					if err != nil {
+						return fmt.Errorf("output for column %v - error: %v", i, err)
+					}
 					dest.Valid = true
 				case *bind.indicator == -1: // The selected value is null
 					dest.String = ""
 					dest.Valid = false
 				case *bind.indicator == -2: // Item is greater than the length of the output variable; the item has been truncated.
-					dest.String = C.GoStringN((*C.char)(bind.pbuf), C.int(*bind.length))
+					dest.String = stmt.conn.goText((*C.OraText)(bind.pbuf), int(*bind.length))
 					dest.Valid = true
 					// TODO: should this be an error?
 				default:
@@ -887,15 +1767,34 @@ func (stmt *Stmt) outputBoundParameters(binds []bindStruct) error {
 				}
 
 			case *bool:
-				buf := (*[1 << 30]byte)(bind.pbuf)[0:1]
-				*dest = buf[0] != 0
+				*dest = outputBoundBool(bind)
 			case *sql.NullBool:
 				if *bind.indicator == -1 {
 					dest.Bool = false
 					dest.Valid = false
 				} else {
-					buf := (*[1 << 30]byte)(bind.pbuf)[0:1]
-					dest.Bool = buf[0] != 0
+					dest.Bool = outputBoundBool(bind)
+					dest.Valid = true
+				}
+
+			case *time.Time:
+				if *bind.indicator == -1 {
+					*dest = time.Time{}
+				} else {
+					*dest, err = stmt.outputBoundTime(bind)
+					if err != nil {
+						return fmt.Errorf("output for column %v - error: %v", i, err)
+					}
+				}
+			case *sql.NullTime:
+				if *bind.indicator == -1 {
+					dest.Time = time.Time{}
+					dest.Valid = false
+				} else {
+					dest.Time, err = stmt.outputBoundTime(bind)
+					if err != nil {
+						return fmt.Errorf("output for column %v - error: %v", i, err)
+					}
 					dest.Valid = true
 				}
 
@@ -985,8 +1884,8 @@ func (stmt *Stmt) ociBindByName(name []byte, bind *bindStruct) error {
 		unsafe.Pointer(bind.indicator), // Pointer to an indicator variable or array
 		bind.length,                    // lengths are in bytes in general
 		nil,                            // Pointer to the array of column-level return codes
-		0,                              // A maximum array length parameter
-		nil,                            // Current array length parameter
+		bind.maxArrayLength,            // A maximum array length parameter, only for PL/SQL array binds
+		bind.currentLength,             // Current array length parameter, only for PL/SQL array binds
 		C.OCI_DEFAULT,                  // The mode. Recommended to set to OCI_DEFAULT, which makes the bind variable have the same encoding as its statement.
 	)
 
@@ -1006,14 +1905,38 @@ func (stmt *Stmt) ociBindByPos(position C.ub4, bind *bindStruct) error {
 		unsafe.Pointer(bind.indicator), // Pointer to an indicator variable or array
 		bind.length,                    // lengths are in bytes in general
 		nil,                            // Pointer to the array of column-level return codes
-		0,                              // A maximum array length parameter
-		nil,                            // Current array length parameter
+		bind.maxArrayLength,            // A maximum array length parameter, only for PL/SQL array binds
+		bind.currentLength,             // Current array length parameter, only for PL/SQL array binds
 		C.OCI_DEFAULT,                  // The mode. Recommended to set to OCI_DEFAULT, which makes the bind variable have the same encoding as its statement.
 	)
 
 	return stmt.conn.getError(result)
 }
 
+// ociDefineByPos calls OCIDefineByPos, then returns error.
+func (stmt *Stmt) ociDefineByPos(position C.ub4, define *defineStruct) error {
+	mode := C.ub4(C.OCI_DEFAULT)
+	if define.piecewise {
+		mode = C.OCI_DYNAMIC_FETCH
+	}
+
+	result := C.OCIDefineByPos(
+		stmt.stmt,                        // statement handle
+		&define.defineHandle,             // pointer to a pointer to a define handle. If NULL, this call implicitly allocates the define handle.
+		stmt.conn.errHandle,              // error handle
+		position,                         // position of this value in the select list. Positions are 1-based and are numbered from left to right.
+		define.pbuf,                      // pointer to a buffer
+		define.maxSize,                   // size of each valuep buffer in bytes
+		define.dataType,                  // datatype
+		unsafe.Pointer(define.indicator), // pointer to an indicator variable or array
+		define.length,                    // pointer to array of length of data fetched
+		nil,                              // pointer to array of column-level return codes
+		mode,                             // mode - OCI_DEFAULT: This is the default mode. OCI_DYNAMIC_FETCH: data is fetched in pieces.
+	)
+
+	return stmt.conn.getError(result)
+}
+
 // ociStmtExecute calls OCIStmtExecute
 func (stmt *Stmt) ociStmtExecute(iters C.ub4, mode C.ub4) error {
 	result := C.OCIStmtExecute(
@@ -1032,5 +1955,5 @@ func (stmt *Stmt) ociStmtExecute(iters C.ub4, mode C.ub4) error {
 		stmt.releaseMode = C.OCI_STRLS_CACHE_DELETE
 	}
 
-	return stmt.conn.getError(result)
+	return stmt.parseError(stmt.conn.getError(result))
 }
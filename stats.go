@@ -0,0 +1,120 @@
+package oci8
+
+import (
+	"database/sql"
+	"sync/atomic"
+)
+
+// the connection statistics counted by the driver, the indexes of connectionCounters
+const (
+	statConnects = iota
+	statPooledConnects
+	statConnectErrors
+	statCloses
+	statValidations
+	statValidationErrors
+	statResets
+	statResetErrors
+	statCount
+)
+
+type (
+	// ConnectionStats are the connection statistics counted by the driver,
+	// the physical side of the logical connections of the database/sql pool
+	ConnectionStats struct {
+		// Connects is the number of connections opened, each a new database session,
+		// or a session taken from the OCI session pool with pool_max
+		Connects int64
+		// PooledConnects is the number of the connects that took a session from the OCI session pool with pool_max
+		PooledConnects int64
+		// ConnectErrors is the number of connects that failed
+		ConnectErrors int64
+		// Closes is the number of connections closed
+		Closes int64
+		// Validations is the number of times a connection was checked by Ping, IsValid, or ResetSession, with OCIPing or the health query
+		Validations int64
+		// ValidationErrors is the number of validations that found the connection bad
+		ValidationErrors int64
+		// Resets is the number of times a connection was reset by ResetSession before it was used again
+		Resets int64
+		// ResetErrors is the number of resets that failed, the connections were discarded
+		ResetErrors int64
+	}
+
+	// ComposedStats is a snapshot of the statistics of the database/sql pool and of the driver connections of a sql.DB,
+	// so a dashboard shows the logical and the physical connections together
+	ComposedStats struct {
+		// DB are the statistics of the database/sql pool
+		DB sql.DBStats
+		// Driver are the statistics the driver counted for the connections of the sql.DB
+		Driver ConnectionStats
+	}
+
+	// connectionCounters are the counters of the connection statistics, updated atomically
+	connectionCounters [statCount]int64
+)
+
+// driverCounters are the counters of all the connections of the driver
+var driverCounters connectionCounters
+
+// GetConnectionStats returns the connection statistics of all the connections of the driver
+func GetConnectionStats() ConnectionStats {
+	return driverCounters.stats()
+}
+
+// Stats returns the connection statistics of the connections opened by the connector.
+// A sql.DB opened with sql.Open has a connector of its own, see GetComposedStats.
+func (connector *Connector) Stats() ConnectionStats {
+	return connector.counters.stats()
+}
+
+// GetComposedStats returns the statistics of the database/sql pool of db together with the driver statistics of connector,
+// the connector db was opened with using sql.OpenDB. A nil connector uses the statistics of all the connections of the driver,
+// like for a sql.DB opened with sql.Open.
+//
+//	connector, err := oci8.NewConnectorWithOptions(oci8.ConnectorDSN(dsn))
+//	db := sql.OpenDB(connector)
+//	stats := oci8.GetComposedStats(db, connector)
+func GetComposedStats(db *sql.DB, connector *Connector) ComposedStats {
+	stats := ComposedStats{DB: db.Stats()}
+	if connector != nil {
+		stats.Driver = connector.Stats()
+	} else {
+		stats.Driver = GetConnectionStats()
+	}
+	return stats
+}
+
+// stats returns a snapshot of the counters
+func (counters *connectionCounters) stats() ConnectionStats {
+	return ConnectionStats{
+		Connects:         atomic.LoadInt64(&counters[statConnects]),
+		PooledConnects:   atomic.LoadInt64(&counters[statPooledConnects]),
+		ConnectErrors:    atomic.LoadInt64(&counters[statConnectErrors]),
+		Closes:           atomic.LoadInt64(&counters[statCloses]),
+		Validations:      atomic.LoadInt64(&counters[statValidations]),
+		ValidationErrors: atomic.LoadInt64(&counters[statValidationErrors]),
+		Resets:           atomic.LoadInt64(&counters[statResets]),
+		ResetErrors:      atomic.LoadInt64(&counters[statResetErrors]),
+	}
+}
+
+// countStat adds one to the statistic in the driver counters and in the counters of the connector of the connection
+func (conn *Conn) countStat(stat int) {
+	atomic.AddInt64(&driverCounters[stat], 1)
+	if conn.counters != nil {
+		atomic.AddInt64(&conn.counters[stat], 1)
+	}
+}
+
+// countConnect counts opening the connection, with the error of open
+func (conn *Conn) countConnect(err error) {
+	if err != nil {
+		conn.countStat(statConnectErrors)
+		return
+	}
+	conn.countStat(statConnects)
+	if conn.sessionPool != nil {
+		conn.countStat(statPooledConnects)
+	}
+}
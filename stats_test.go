@@ -0,0 +1,75 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// TestComposedStats checks the driver statistics of the connections of a connector
+func TestComposedStats(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	connector, err := NewConnectorWithOptions(ConnectorDSN(testGetOpenString("")))
+	if err != nil {
+		t.Fatal("NewConnectorWithOptions error:", err)
+	}
+	db := sql.OpenDB(connector)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		t.Fatal("ping error:", err)
+	}
+
+	stats := GetComposedStats(db, connector)
+	if stats.DB.OpenConnections != 1 || stats.Driver.Connects != 1 || stats.Driver.Validations != 1 || stats.Driver.ValidationErrors != 0 {
+		t.Errorf("stats - received: %+v", stats)
+	}
+
+	err = db.Close()
+	if err != nil {
+		t.Fatal("close error:", err)
+	}
+	stats = GetComposedStats(db, connector)
+	if stats.DB.OpenConnections != 0 || stats.Driver.Closes != 1 {
+		t.Errorf("stats after close - received: %+v", stats)
+	}
+}
+
+// TestConnectionStats tests counting the connection statistics of a connector and of the driver
+func TestConnectionStats(t *testing.T) {
+	connector := &Connector{}
+	conn := &Conn{counters: &connector.counters}
+	before := GetConnectionStats()
+
+	conn.countConnect(nil)
+	conn.countConnect(errors.New("connect error"))
+	conn.sessionPool = &sessionPool{}
+	conn.countConnect(nil)
+	conn.countStat(statValidations)
+	conn.countStat(statValidationErrors)
+	conn.countStat(statCloses)
+	conn.countStat(statResets)
+	conn.countStat(statResets)
+	conn.countStat(statResetErrors)
+
+	expected := ConnectionStats{Connects: 2, PooledConnects: 1, ConnectErrors: 1, Closes: 1, Validations: 1, ValidationErrors: 1, Resets: 2, ResetErrors: 1}
+	stats := connector.Stats()
+	if stats != expected {
+		t.Errorf("connector stats - expected: %+v - received: %+v", expected, stats)
+	}
+
+	// the driver counts the connections of all the connectors, other tests can connect at the same time
+	after := GetConnectionStats()
+	if after.Connects-before.Connects < 2 || after.PooledConnects-before.PooledConnects < 1 || after.ConnectErrors-before.ConnectErrors < 1 {
+		t.Errorf("driver stats - expected at least: %+v - received: %+v", expected, after)
+	}
+}
@@ -0,0 +1,241 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrTempLobFreed is returned when a TempLob is used after it is freed
+var ErrTempLobFreed = errors.New("temporary LOB is freed")
+
+// TempLob is a temporary BLOB or CLOB created on a connection with CreateTempLob, filled with Write,
+// then bound as a value, like for a PL/SQL procedure that takes a BLOB or CLOB parameter:
+//
+//	lob, err := conn.CreateTempLob(true)
+//	...
+//	for _, line := range lines {
+//		_, err = lob.WriteString(line)
+//		...
+//	}
+//	_, err = stmt.ExecContext(ctx, lob)
+//
+// The temporary LOB is freed when the statement it is bound to is closed,
+// which with the Exec and Query methods of sql.DB, sql.Conn, and sql.Tx is right after the call.
+// Close frees a TempLob that is not bound, or before the statement is closed.
+// TempLobs not freed before are freed when the connection is closed.
+// A TempLob can only be bound to statements of the connection it was created on, and can not be an OUT bind.
+type TempLob struct {
+	conn    *Conn
+	locator *C.OCILobLocator
+	text    bool
+	stmt    *Stmt // the statement the LOB is bound to, which frees the LOB on close
+}
+
+// TempLobOptions are the options of the temporary LOBs created by the driver, see WithTempLobOptions and CreateTempLobWithOptions.
+// Compress and Deduplicate need SecureFiles temporary LOBs, and the server can require the Advanced Compression option for them,
+// creating the LOB returns the server error when they are not available.
+type TempLobOptions struct {
+	// NoCache creates the LOB without reading it into the buffer cache, even when the temp_lob_cache DSN parameter is true
+	NoCache bool
+	// Compress turns on SecureFiles compression, to reduce the TEMP I/O of highly compressible values
+	Compress bool
+	// Deduplicate turns on SecureFiles deduplication
+	Deduplicate bool
+}
+
+// CreateTempLob creates a temporary LOB on the connection, a CLOB when text is true, otherwise a BLOB.
+// The temp_lob_cache and temp_tablespace DSN parameters apply, and it is counted in the TempLobStats.
+// Use sql.Conn.Raw to get the underlying *Conn.
+func (conn *Conn) CreateTempLob(text bool) (*TempLob, error) {
+	return conn.CreateTempLobWithOptions(text, TempLobOptions{})
+}
+
+// CreateTempLobWithOptions creates a temporary LOB on the connection like CreateTempLob, with the options
+func (conn *Conn) CreateTempLobWithOptions(text bool, options TempLobOptions) (*TempLob, error) {
+	lobType := C.ub1(C.OCI_TEMP_BLOB)
+	if text {
+		lobType = C.OCI_TEMP_CLOB
+	}
+
+	lobP, _, err := conn.ociDescriptorAlloc(C.OCI_DTYPE_LOB, 0)
+	if err != nil {
+		return nil, err
+	}
+	locator := (*C.OCILobLocator)(*lobP)
+	err = conn.ociLobCreateTemporary(locator, C.SQLCS_IMPLICIT, lobType, options)
+	if err != nil {
+		ociDescriptorFree(unsafe.Pointer(locator), C.OCI_DTYPE_LOB)
+		return nil, err
+	}
+
+	lob := &TempLob{
+		conn:    conn,
+		locator: locator,
+		text:    text,
+	}
+	conn.tempLobsMutex.Lock()
+	if conn.tempLobs == nil {
+		conn.tempLobs = make(map[*TempLob]struct{})
+	}
+	conn.tempLobs[lob] = struct{}{}
+	conn.tempLobsMutex.Unlock()
+	return lob, nil
+}
+
+// IsText returns true for a CLOB, false for a BLOB
+func (lob *TempLob) IsText() bool {
+	return lob.text
+}
+
+// Write implements io.Writer, appending p to the end of the LOB in one round trip.
+// For a CLOB, p is text in the client character set, UTF-8 with the utf16 DSN parameter,
+// and must not end in the middle of a multibyte character.
+func (lob *TempLob) Write(p []byte) (int, error) {
+	if lob.locator == nil {
+		return 0, ErrTempLobFreed
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	conn := lob.conn
+	writeBytes := C.oraub8(len(p))
+	result := C.OCILobWriteAppend2(
+		conn.svc,                // service context handle
+		conn.errHandle,          // error handle
+		lob.locator,             // LOB locator
+		&writeBytes,             // IN - the number of bytes to write. OUT - the number of bytes written
+		nil,                     // maximum number of characters to write
+		unsafe.Pointer(&p[0]),   // buffer of the data
+		C.oraub8(len(p)),        // length of the buffer
+		C.OCI_ONE_PIECE,         // written in one piece
+		nil,                     // context pointer for the callback function
+		nil,                     // no callback function
+		conn.lobCharset(),       // character set ID of the buffer data, see lobCharset
+		C.ub1(C.SQLCS_IMPLICIT), // character set form of the buffer data
+	)
+	if result != C.OCI_SUCCESS {
+		return 0, conn.getError(result)
+	}
+	conn.tempLobStats.BytesWritten += int64(len(p))
+
+	return len(p), nil
+}
+
+// WriteString appends the text s to the end of the LOB, see Write
+func (lob *TempLob) WriteString(s string) (int, error) {
+	return lob.Write([]byte(s))
+}
+
+// Size returns the length of the LOB, in bytes for a BLOB and in characters for a CLOB
+func (lob *TempLob) Size() (int64, error) {
+	if lob.locator == nil {
+		return 0, ErrTempLobFreed
+	}
+
+	var length C.oraub8
+	result := C.OCILobGetLength2(lob.conn.svc, lob.conn.errHandle, lob.locator, &length)
+	if result != C.OCI_SUCCESS {
+		return 0, lob.conn.getError(result)
+	}
+	return int64(length), nil
+}
+
+// Close frees the temporary LOB. Closing a freed TempLob does nothing.
+func (lob *TempLob) Close() error {
+	if lob.stmt != nil {
+		lob.stmt.removeTempLob(lob)
+	}
+	return lob.free()
+}
+
+// free calls OCILobFreeTemporary then frees the LOB locator
+func (lob *TempLob) free() error {
+	if lob.locator == nil {
+		return nil
+	}
+
+	conn := lob.conn
+	result := C.OCILobFreeTemporary(conn.svc, conn.errHandle, lob.locator)
+	ociDescriptorFree(unsafe.Pointer(lob.locator), C.OCI_DTYPE_LOB)
+	lob.locator = nil
+	lob.stmt = nil
+
+	conn.tempLobsMutex.Lock()
+	delete(conn.tempLobs, lob)
+	conn.tempLobsMutex.Unlock()
+
+	return conn.getError(result)
+}
+
+// bindTempLob binds the LOB locator of a TempLob to the statement, the locator stays owned by the TempLob,
+// which is freed when the statement is closed
+func (stmt *Stmt) bindTempLob(sbind *bindStruct, lob *TempLob) error {
+	if lob.locator == nil {
+		return ErrTempLobFreed
+	}
+	if lob.conn != stmt.conn {
+		return errors.New("temporary LOB was created on another connection")
+	}
+
+	sbind.dataType = C.SQLT_BLOB
+	if lob.text {
+		sbind.dataType = C.SQLT_CLOB
+	}
+	// OCI keeps the address of the locator until the execute, so it must not be Go memory
+	locatorP := (**C.OCILobLocator)(C.malloc(C.size_t(sizeOfNilPointer)))
+	*locatorP = lob.locator
+	sbind.pbuf = unsafe.Pointer(locatorP)
+	sbind.maxSize = C.sb4(sizeOfNilPointer)
+	*sbind.length = C.ub2(sizeOfNilPointer)
+	sbind.borrowed = true
+
+	if lob.stmt != stmt {
+		if lob.stmt != nil {
+			lob.stmt.removeTempLob(lob)
+		}
+		lob.stmt = stmt
+		stmt.tempLobs = append(stmt.tempLobs, lob)
+	}
+	return nil
+}
+
+// removeTempLob removes a TempLob from the LOBs freed when the statement is closed
+func (stmt *Stmt) removeTempLob(lob *TempLob) {
+	for i := range stmt.tempLobs {
+		if stmt.tempLobs[i] == lob {
+			stmt.tempLobs = append(stmt.tempLobs[:i], stmt.tempLobs[i+1:]...)
+			return
+		}
+	}
+}
+
+// freeTempLobs frees the TempLobs bound to the statement
+func (stmt *Stmt) freeTempLobs() error {
+	var err error
+	for _, lob := range stmt.tempLobs {
+		freeErr := lob.free()
+		if err == nil {
+			err = freeErr
+		}
+	}
+	stmt.tempLobs = nil
+	return err
+}
+
+// freeTempLobs frees the TempLobs of the connection not freed yet, before the session ends
+func (conn *Conn) freeTempLobs() {
+	conn.tempLobsMutex.Lock()
+	lobs := make([]*TempLob, 0, len(conn.tempLobs))
+	for lob := range conn.tempLobs {
+		lobs = append(lobs, lob)
+	}
+	conn.tempLobsMutex.Unlock()
+
+	for _, lob := range lobs {
+		lob.free()
+	}
+}
@@ -0,0 +1,274 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+// TestTempLobStats tests temporary LOB statistics
+func TestTempLobStats(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	conn, err := TestDB.Conn(ctx)
+	cancel()
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	var length float64
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = conn.QueryRowContext(ctx, "select dbms_lob.getlength(:1) from dual", testByteSlice70000).Scan(&length)
+	cancel()
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if length != 70000 {
+		t.Fatalf("length - expected: %v - received: %v", 70000, length)
+	}
+
+	var stats TempLobStats
+	err = conn.Raw(func(driverConn interface{}) error {
+		stats = driverConn.(*Conn).TempLobStats()
+		return nil
+	})
+	if err != nil {
+		t.Fatal("raw error:", err)
+	}
+	if stats.Created < 1 {
+		t.Errorf("created - expected at least 1 - received: %v", stats.Created)
+	}
+	if stats.BytesWritten < 70000 {
+		t.Errorf("bytes written - expected at least 70000 - received: %v", stats.BytesWritten)
+	}
+
+	// the temporary LOB of the bind is freed after the query, the usage views need select privileges
+	tempLobUsage := func() *TempLobUsage {
+		var usage *TempLobUsage
+		err := conn.Raw(func(driverConn interface{}) error {
+			ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+			defer cancel()
+			var err error
+			usage, err = driverConn.(*Conn).TempLobUsage(ctx)
+			return err
+		})
+		if err != nil {
+			t.Skip("temp LOB usage error:", err)
+		}
+		return usage
+	}
+	before := tempLobUsage()
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = conn.QueryRowContext(ctx, "select dbms_lob.getlength(:1) from dual", testByteSlice70000).Scan(&length)
+	cancel()
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	after := tempLobUsage()
+	if after.CacheLobs+after.NoCacheLobs != before.CacheLobs+before.NoCacheLobs {
+		t.Errorf("temporary LOBs - expected: %+v - received: %+v", before, after)
+	}
+}
+
+// TestTempLob checks filling temporary LOBs in chunks, binding them to a PL/SQL call, and freeing them on statement close
+func TestTempLob(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	var clob *TempLob
+	var blob *TempLob
+	var clobLength int64
+	var blobLength int64
+	err = conn.Raw(func(driverConn interface{}) error {
+		clob, err = driverConn.(*Conn).CreateTempLob(true)
+		if err != nil {
+			return err
+		}
+		blob, err = driverConn.(*Conn).CreateTempLob(false)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 10; i++ {
+			_, err = clob.WriteString(testString1)
+			if err != nil {
+				return err
+			}
+			_, err = blob.Write(testByteSlice70000)
+			if err != nil {
+				return err
+			}
+		}
+
+		size, err := clob.Size()
+		if err != nil {
+			return err
+		}
+		if size != int64(10*1000) {
+			t.Errorf("clob size - expected: %v - received: %v", 10*1000, size)
+		}
+
+		stmt, err := driverConn.(*Conn).PrepareContext(ctx, "begin :1 := dbms_lob.getlength(:2); :3 := dbms_lob.getlength(:4); end;")
+		if err != nil {
+			return err
+		}
+		_, err = stmt.(driver.StmtExecContext).ExecContext(ctx, []driver.NamedValue{
+			{Ordinal: 1, Value: sql.Out{Dest: &clobLength}},
+			{Ordinal: 2, Value: clob},
+			{Ordinal: 3, Value: sql.Out{Dest: &blobLength}},
+			{Ordinal: 4, Value: blob},
+		})
+		if err != nil {
+			stmt.Close()
+			return err
+		}
+		return stmt.Close()
+	})
+	if err != nil {
+		t.Fatal("temp lob error:", err)
+	}
+
+	if clobLength != int64(10*1000) {
+		t.Errorf("clob length - expected: %v - received: %v", 10*1000, clobLength)
+	}
+	if blobLength != 10*70000 {
+		t.Errorf("blob length - expected: %v - received: %v", 10*70000, blobLength)
+	}
+
+	_, err = clob.Size()
+	if err != ErrTempLobFreed {
+		t.Errorf("size after statement close - expected: %v - received: %v", ErrTempLobFreed, err)
+	}
+	err = blob.Close()
+	if err != nil {
+		t.Errorf("close error: %v", err)
+	}
+}
+
+// TestTempLobOptions checks creating temporary LOBs with TempLobOptions, for binds with WithTempLobOptions and with CreateTempLobWithOptions
+func TestTempLobOptions(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	var length float64
+	err = conn.QueryRowContext(WithTempLobOptions(ctx, TempLobOptions{NoCache: true}), "select dbms_lob.getlength(:1) from dual", testByteSlice70000).Scan(&length)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if length != 70000 {
+		t.Errorf("length - expected: %v - received: %v", 70000, length)
+	}
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		lob, err := driverConn.(*Conn).CreateTempLobWithOptions(false, TempLobOptions{NoCache: true})
+		if err != nil {
+			return err
+		}
+		_, err = lob.Write(testByteSlice70000)
+		if err != nil {
+			lob.Close()
+			return err
+		}
+		size, err := lob.Size()
+		if err != nil {
+			lob.Close()
+			return err
+		}
+		if size != 70000 {
+			t.Errorf("size - expected: %v - received: %v", 70000, size)
+		}
+		return lob.Close()
+	})
+	if err != nil {
+		t.Fatal("temp lob error:", err)
+	}
+
+	// compression needs SecureFiles temporary LOBs, which the test database might not have
+	err = conn.Raw(func(driverConn interface{}) error {
+		lob, err := driverConn.(*Conn).CreateTempLobWithOptions(false, TempLobOptions{Compress: true, Deduplicate: true})
+		if err != nil {
+			return err
+		}
+		return lob.Close()
+	})
+	if err != nil {
+		t.Log("compressed temp lob error:", err)
+	}
+}
+
+// TestTempLobFreed tests a freed TempLob returns ErrTempLobFreed and is removed from its statement
+func TestTempLobFreed(t *testing.T) {
+	t.Parallel()
+
+	stmt := &Stmt{}
+	lob := &TempLob{stmt: stmt}
+	other := &TempLob{stmt: stmt}
+	stmt.tempLobs = []*TempLob{lob, other}
+
+	_, err := lob.Write([]byte("a"))
+	if err != ErrTempLobFreed {
+		t.Errorf("write - expected: %v - received: %v", ErrTempLobFreed, err)
+	}
+	_, err = lob.Size()
+	if err != ErrTempLobFreed {
+		t.Errorf("size - expected: %v - received: %v", ErrTempLobFreed, err)
+	}
+	err = lob.Close()
+	if err != nil {
+		t.Errorf("close - expected: %v - received: %v", nil, err)
+	}
+	if len(stmt.tempLobs) != 1 || stmt.tempLobs[0] != other {
+		t.Errorf("statement temp LOBs - expected: %v - received: %v", []*TempLob{other}, stmt.tempLobs)
+	}
+	err = stmt.bindTempLob(&bindStruct{}, lob)
+	if err != ErrTempLobFreed {
+		t.Errorf("bind - expected: %v - received: %v", ErrTempLobFreed, err)
+	}
+}
+
+// TestTempLobOptionsContext tests the temporary LOB options of the context
+func TestTempLobOptionsContext(t *testing.T) {
+	t.Parallel()
+
+	options := tempLobOptionsFromContext(context.Background())
+	if options != (TempLobOptions{}) {
+		t.Errorf("default - expected: %v - received: %v", TempLobOptions{}, options)
+	}
+
+	expected := TempLobOptions{NoCache: true, Compress: true}
+	options = tempLobOptionsFromContext(WithTempLobOptions(context.Background(), expected))
+	if options != expected {
+		t.Errorf("WithTempLobOptions - expected: %v - received: %v", expected, options)
+	}
+}
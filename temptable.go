@@ -0,0 +1,121 @@
+package oci8
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TempColumn is a column of a global temporary table
+type TempColumn struct {
+	// Name is the column name, it must be a valid unquoted identifier
+	Name string
+	// Type is the column data type, like NUMBER or VARCHAR2(100). It is used in the create table as is.
+	Type string
+}
+
+// CreateTempTable creates a global temporary table with ON COMMIT PRESERVE ROWS,
+// so the rows loaded into it are private to the session and are kept until deleted or the session ends.
+// If an object with the table name already exists nothing is done.
+// Note that creating a table is DDL, which commits any open transaction.
+// Use sql.Conn.Raw to get the underlying *Conn, then load and query the table using the same sql.Conn.
+func (conn *Conn) CreateTempTable(ctx context.Context, tableName string, columns ...TempColumn) error {
+	err := conn.ValidateIdentifier(tableName)
+	if err != nil {
+		return err
+	}
+	if len(columns) < 1 {
+		return errors.New("temp table has no columns")
+	}
+
+	var query strings.Builder
+	query.WriteString("create global temporary table ")
+	query.WriteString(tableName)
+	query.WriteString(" ( ")
+	for i, column := range columns {
+		err = conn.ValidateIdentifier(column.Name)
+		if err != nil {
+			return err
+		}
+		if column.Type == "" {
+			return fmt.Errorf("temp table column %v has no type", column.Name)
+		}
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString(column.Name)
+		query.WriteString(" ")
+		query.WriteString(column.Type)
+	}
+	query.WriteString(" ) on commit preserve rows")
+
+	_, err = conn.exec(ctx, query.String(), nil)
+	if isOracleError(err, 955) {
+		// ORA-00955: name is already used by an existing object
+		return nil
+	}
+	return err
+}
+
+// LoadTempRows inserts rows into a temporary table created by CreateTempTable and returns the number of rows inserted.
+// Each row must have a value for each of the columns. The rows are inserted as a Batch,
+// so when any row fails none of the rows are inserted and the error is a *BatchError.
+// Use sql.Conn.Raw to get the underlying *Conn.
+func (conn *Conn) LoadTempRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}) (int64, error) {
+	err := conn.ValidateIdentifier(tableName)
+	if err != nil {
+		return 0, err
+	}
+	if len(columns) < 1 {
+		return 0, errors.New("no temp table columns to load")
+	}
+
+	var query strings.Builder
+	query.WriteString("insert into ")
+	query.WriteString(tableName)
+	query.WriteString(" ( ")
+	for i, column := range columns {
+		err = conn.ValidateIdentifier(column)
+		if err != nil {
+			return 0, err
+		}
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString(column)
+	}
+	query.WriteString(" ) values ( ")
+	for i := range columns {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString(":")
+		query.WriteString(strconv.Itoa(i + 1))
+	}
+	query.WriteString(" )")
+
+	driverStmt, err := conn.PrepareContext(ctx, query.String())
+	if err != nil {
+		return 0, err
+	}
+	stmt := driverStmt.(*Stmt)
+	defer stmt.Close()
+
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("temp table row %v has %v values, expected %v", i, len(row), len(columns))
+		}
+	}
+	if len(rows) < 1 {
+		return 0, nil
+	}
+
+	result, err := stmt.ExecContext(ctx, []driver.NamedValue{{Ordinal: 1, Value: Batch{Rows: rows}}})
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,75 @@
+//go:build go1.13
+// +build go1.13
+
+package oci8
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTempTable(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "TEMP_TABLE_" + TestTimeString
+	defer testDropTable(t, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+	conn, err := TestDB.Conn(ctx)
+	if err != nil {
+		t.Fatal("conn error:", err)
+	}
+	defer conn.Close()
+
+	var count int64
+	err = conn.Raw(func(driverConn interface{}) error {
+		oci8Conn := driverConn.(*Conn)
+		columns := []TempColumn{{Name: "ID", Type: "NUMBER"}, {Name: "NAME", Type: "VARCHAR2(100)"}}
+		err := oci8Conn.CreateTempTable(ctx, tableName, columns...)
+		if err != nil {
+			return err
+		}
+		// already exists is not an error
+		err = oci8Conn.CreateTempTable(ctx, tableName, columns...)
+		if err != nil {
+			return err
+		}
+		count, err = oci8Conn.LoadTempRows(ctx, tableName, []string{"ID", "NAME"}, [][]interface{}{{1, "a"}, {2, "b"}, {3, nil}})
+		return err
+	})
+	if err != nil {
+		t.Fatal("raw error:", err)
+	}
+	if count != 3 {
+		t.Errorf("count - expected: 3 - received: %v", count)
+	}
+
+	var names string
+	err = conn.QueryRowContext(ctx, "select listagg(nvl(NAME, '-'), ',') within group (order by ID) from "+tableName).Scan(&names)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if names != "a,b,-" {
+		t.Errorf("names - expected: a,b,- - received: %v", names)
+	}
+
+	// rows are private to the session
+	err = TestDB.QueryRowContext(ctx, "select count(*) from "+tableName).Scan(&count)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if count != 0 {
+		t.Errorf("other session count - expected: 0 - received: %v", count)
+	}
+
+	// the session still has the rows, so truncate before the drop
+	_, err = conn.ExecContext(ctx, "truncate table "+tableName)
+	if err != nil {
+		t.Fatal("truncate error:", err)
+	}
+}
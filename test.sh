@@ -22,8 +22,10 @@ test_go_dist () {
     export GOPATH=/usr/local/goFiles${VERSION_MAJOR_MINOR}.x
     mkdir -p ${GOPATH}/src/github.com/mattn/go-oci8
     cp -r ${TESTDIR}/* ${GOPATH}/src/github.com/mattn/go-oci8/
+    cd ${GOPATH}/src/github.com/mattn/go-oci8
 
-    go test -v github.com/mattn/go-oci8 -args -disableDatabase=false -hostValid ${DOCKER_IP} -username scott -password tiger
+    go test -v ./oracle ./fakedb
+    go test -v . -args -disableDatabase=false -hostValid ${DOCKER_IP} -username scott -password tiger
 }
 
 echo "installing build tools"
@@ -33,13 +35,13 @@ apt-get -qq -y install git pkg-config gcc wget  2>&1 > /dev/null
 echo "installing go"
 cd /tmp/
 
-setup_go_dist "1.15" "6"
-setup_go_dist "1.14" "13"
-setup_go_dist "1.13" "15"
-setup_go_dist "1.12" "17"
-setup_go_dist "1.11" "13"
-setup_go_dist "1.10" "8"
-setup_go_dist "1.9" "7"
+setup_go_dist "1.22" "12"
+setup_go_dist "1.21" "13"
+setup_go_dist "1.20" "14"
+setup_go_dist "1.19" "13"
+setup_go_dist "1.18" "10"
+setup_go_dist "1.17" "13"
+setup_go_dist "1.16" "15"
 
 echo "starting Oracle"
 /usr/sbin/startup.sh
@@ -81,10 +83,10 @@ PKGCONFIG
 
 export PATH_SAVE=${PATH}
 
-test_go_dist "1.15"
-test_go_dist "1.14"
-test_go_dist "1.13"
-test_go_dist "1.12"
-test_go_dist "1.11"
-test_go_dist "1.10"
-test_go_dist "1.9"
+test_go_dist "1.22"
+test_go_dist "1.21"
+test_go_dist "1.20"
+test_go_dist "1.19"
+test_go_dist "1.18"
+test_go_dist "1.17"
+test_go_dist "1.16"
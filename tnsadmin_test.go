@@ -0,0 +1,54 @@
+package oci8
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestResolveConnect tests net service names are resolved with the tnsnames.ora of tns_admin or oracle_home
+func TestResolveConnect(t *testing.T) {
+	t.Parallel()
+
+	oracleHome, err := ioutil.TempDir("", "oracle_home")
+	if err != nil {
+		t.Fatal("temp dir error:", err)
+	}
+	defer os.RemoveAll(oracleHome)
+	networkAdmin := filepath.Join(oracleHome, "network", "admin")
+	err = os.MkdirAll(networkAdmin, 0700)
+	if err != nil {
+		t.Fatal("mkdir error:", err)
+	}
+	err = ioutil.WriteFile(filepath.Join(networkAdmin, "tnsnames.ora"), []byte("ORCL = (DESCRIPTION = (ADDRESS = (HOST = dbhost)))\n"), 0600)
+	if err != nil {
+		t.Fatal("write error:", err)
+	}
+
+	var resolveTests = []struct {
+		dsn      DSN
+		expected string
+		err      bool
+	}{
+		{dsn: DSN{Connect: "orcl", tnsAdmin: networkAdmin}, expected: "(DESCRIPTION = (ADDRESS = (HOST = dbhost)))"},
+		{dsn: DSN{Connect: "orcl", oracleHome: oracleHome}, expected: "(DESCRIPTION = (ADDRESS = (HOST = dbhost)))"},
+		{dsn: DSN{Connect: "orcl"}, expected: "orcl"},
+		{dsn: DSN{Connect: "dbhost/orcl", tnsAdmin: networkAdmin}, expected: "dbhost/orcl"},
+		{dsn: DSN{Connect: "orcl", tnsAdmin: oracleHome, oracleHome: oracleHome}, err: true},
+		{dsn: DSN{Connect: "missing", tnsAdmin: networkAdmin}, err: true},
+	}
+	for _, tt := range resolveTests {
+		dsn, err := tt.dsn.resolveConnect()
+		if (err != nil) != tt.err {
+			t.Errorf("resolveConnect %+v - expected error: %v - received: %v", tt.dsn, tt.err, err)
+			continue
+		}
+		if err == nil && dsn.Connect != tt.expected {
+			t.Errorf("resolveConnect %+v - expected: %v - received: %v", tt.dsn, tt.expected, dsn.Connect)
+		}
+	}
+	if tt := resolveTests[0]; tt.dsn.Connect != "orcl" {
+		t.Errorf("resolveConnect DSN - expected: %v - received: %v", "orcl", tt.dsn.Connect)
+	}
+}
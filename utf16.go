@@ -0,0 +1,171 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"encoding/binary"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// nativeEndian is the byte order of the platform, the byte order of the UTF-16 text of an OCI_UTF16ID environment
+var nativeEndian binary.ByteOrder = nativeByteOrder()
+
+// nativeByteOrder returns the byte order of the platform
+func nativeByteOrder() binary.ByteOrder {
+	value := uint16(1)
+	if *(*byte)(unsafe.Pointer(&value)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// utf16Encode returns the UTF-16 text of s in the byte order of the platform
+func utf16Encode(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	text := make([]byte, 2*len(units))
+	for i, unit := range units {
+		nativeEndian.PutUint16(text[2*i:], unit)
+	}
+	return text
+}
+
+// utf16Decode returns the string of UTF-16 text in the byte order of the platform.
+// A trailing odd byte is ignored, invalid surrogates are replaced with U+FFFD.
+func utf16Decode(text []byte) string {
+	units := make([]uint16, len(text)/2)
+	for i := range units {
+		units[i] = nativeEndian.Uint16(text[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// utf16Terminator returns the length of UTF-16 text ending with a null character, or the length of the text without one
+func utf16Terminator(text []byte) int {
+	for i := 0; i+1 < len(text); i += 2 {
+		if text[i] == 0 && text[i+1] == 0 {
+			return i
+		}
+	}
+	return len(text) &^ 1
+}
+
+// encodeText returns the text of s in the character set of the environment, UTF-16 with the utf16 DSN parameter
+func (conn *Conn) encodeText(s string) []byte {
+	if conn.utf16 {
+		return utf16Encode(s)
+	}
+	return []byte(s)
+}
+
+// cText converts s to C OraText with a null terminator, UTF-16 with utf16,
+// and returns the length in bytes without the terminator.
+// must be freed
+func cText(s string, utf16 bool) (*C.OraText, C.ub4) {
+	if !utf16 {
+		return cString(s), C.ub4(len(s))
+	}
+	text := utf16Encode(s)
+	return cByteN(append(text, 0, 0), len(text)+2), C.ub4(len(text))
+}
+
+// goText converts C OraText of size bytes in the character set of the environment to Go string
+func (conn *Conn) goText(s *C.OraText, size int) string {
+	if !conn.utf16 {
+		return cGoStringN(s, size)
+	}
+	if size == 0 {
+		return ""
+	}
+	return utf16Decode((*[1 << 30]byte)(unsafe.Pointer(s))[:size:size])
+}
+
+// utf8CharsetID is the Oracle character set id of UTF8, which is UTF-8 for the characters of names
+const utf8CharsetID = 871
+
+// nameCharsetID returns the character set of the environment when it is not UTF-8 or UTF-16,
+// so goName converts names to UTF-8, otherwise 0
+func (conn *Conn) nameCharsetID() C.ub2 {
+	if conn.utf16 {
+		return 0
+	}
+
+	var charsetID C.ub2
+	result := C.OCIAttrGet(
+		unsafe.Pointer(conn.env),   // environment handle
+		C.OCI_HTYPE_ENV,            // handle type
+		unsafe.Pointer(&charsetID), // character set id of the environment
+		nil,                        // size of the attribute value, not needed
+		C.OCI_ATTR_ENV_CHARSET_ID,  // attribute type
+		conn.errHandle,             // error handle
+	)
+	if result != C.OCI_SUCCESS || charsetID == defaultCharset || charsetID == utf8CharsetID {
+		return 0
+	}
+	return charsetID
+}
+
+// goName converts a name of size bytes from OCI_ATTR_NAME, like a column name, to a UTF-8 Go string.
+// Names are in the character set of the environment, which is not UTF-8 when NLS_LANG sets another character set,
+// then the non-ASCII characters of quoted identifiers are converted with OCINlsCharSetConvert.
+func (conn *Conn) goName(s *C.OraText, size int) string {
+	if conn.envCharsetID == 0 || size == 0 {
+		return conn.goText(s, size)
+	}
+
+	name := (*[1 << 30]byte)(unsafe.Pointer(s))[:size:size]
+	ascii := true
+	for _, b := range name {
+		if b >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return string(name)
+	}
+
+	// at most 4 UTF-8 bytes per character
+	buffer := make([]byte, 4*size)
+	var length C.size_t
+	result := C.OCINlsCharSetConvert(
+		unsafe.Pointer(conn.env),   // environment handle
+		conn.errHandle,             // error handle
+		defaultCharset,             // character set of the destination, AL32UTF8
+		unsafe.Pointer(&buffer[0]), // destination buffer
+		C.size_t(len(buffer)),      // size of the destination buffer
+		conn.envCharsetID,          // character set of the source
+		unsafe.Pointer(s),          // source buffer
+		C.size_t(size),             // size of the source
+		&length,                    // number of bytes converted
+	)
+	if result != C.OCI_SUCCESS {
+		return string(name)
+	}
+	return string(buffer[:length])
+}
+
+// goTextTerminated returns the text of a buffer in the character set of the environment ending with a null character
+func (conn *Conn) goTextTerminated(buffer []byte) string {
+	if !conn.utf16 {
+		for i, b := range buffer {
+			if b == 0 {
+				return string(buffer[:i])
+			}
+		}
+		return string(buffer)
+	}
+	return utf16Decode(buffer[:utf16Terminator(buffer)])
+}
+
+// lobCharset returns the character set ID of the CLOB data buffers.
+// 0 is the client character set, with the utf16 DSN parameter the buffers are AL32UTF8 so OCI converts the CLOB data,
+// as LOB data read and written in chunks can split UTF-16 surrogate pairs.
+func (conn *Conn) lobCharset() C.ub2 {
+	if conn.utf16 {
+		return defaultCharset
+	}
+	return 0
+}
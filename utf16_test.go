@@ -0,0 +1,157 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestUTF16Env checks the text of a UTF-16 environment is converted from and to Go strings
+func TestUTF16Env(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?utf16=true")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	longText := strings.Repeat("日本語😀", 5000)
+	var text, nText, clob, out string
+	var length int64
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	err := db.QueryRowContext(ctx, `select :text "ünïcödé", to_nchar(:text), to_clob(:long), length(:long) from dual`,
+		sql.Named("text", "ünïcödé 😀"), sql.Named("long", longText)).Scan(&text, &nText, &clob, &length)
+	cancel()
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	if text != "ünïcödé 😀" || nText != "ünïcödé 😀" {
+		t.Errorf("text - expected: ünïcödé 😀, ünïcödé 😀 - received: %v, %v", text, nText)
+	}
+	if clob != longText || length != int64(len([]rune(longText))+5000) {
+		t.Errorf("clob - expected: %v, %v - received: %v, %v", len(longText), len([]rune(longText))+5000, len(clob), length)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	rows, err := db.QueryContext(ctx, `select 1 "ünïcödé" from dual`)
+	if err != nil {
+		cancel()
+		t.Fatal("query error:", err)
+	}
+	columns, err := rows.Columns()
+	rows.Close()
+	cancel()
+	if err != nil {
+		t.Fatal("columns error:", err)
+	}
+	if len(columns) != 1 || columns[0] != "ünïcödé" {
+		t.Errorf("columns - expected: [ünïcödé] - received: %v", columns)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = db.ExecContext(ctx, "begin :1 := upper(:2); end;", sql.Out{Dest: &out}, "grüße")
+	cancel()
+	if err != nil {
+		t.Fatal("exec error:", err)
+	}
+	if out != "GRÜSSE" && out != "GRÜßE" {
+		t.Errorf("out - expected: GRÜSSE - received: %v", out)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = db.ExecContext(ctx, "select * from not_a_table_ünïcödé")
+	cancel()
+	if err == nil || !strings.Contains(err.Error(), "ORA-00942") {
+		t.Errorf("error - expected: ORA-00942 - received: %v", err)
+	}
+}
+
+// TestColumnNameCharset checks non-ASCII column names are returned as UTF-8 when the client character set is not UTF-8
+func TestColumnNameCharset(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	db := testGetDB("?charset=WE8ISO8859P1")
+	if db == nil {
+		t.Fatal("db is nil")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	// the statement text is in the client character set, \xfc is ü in WE8ISO8859P1
+	rows, err := db.QueryContext(ctx, "select 1 \"gr\xfcn\", 2 \"ascii\" from dual")
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal("columns error:", err)
+	}
+	expected := []string{"grün", "ascii"}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("columns - expected: %q - received: %q", expected, columns)
+	}
+}
+
+// TestUTF16 tests the UTF-16 conversion of the text of an OCI_UTF16ID environment
+func TestUTF16(t *testing.T) {
+	t.Parallel()
+
+	var utf16Tests = []string{
+		"",
+		"abc",
+		"Grüße",
+		"日本語",
+		"emoji 😀 surrogate pair",
+	}
+	for _, text := range utf16Tests {
+		encoded := utf16Encode(text)
+		if len(encoded)%2 != 0 {
+			t.Errorf("utf16Encode(%q) length - expected: even - received: %v", text, len(encoded))
+		}
+		decoded := utf16Decode(encoded)
+		if decoded != text {
+			t.Errorf("utf16Decode - expected: %q - received: %q", text, decoded)
+		}
+		if utf16Terminator(append(encoded, 0, 0, 'x', 0)) != len(encoded) {
+			t.Errorf("utf16Terminator(%q) - expected: %v - received: %v", text, len(encoded), utf16Terminator(append(encoded, 0, 0, 'x', 0)))
+		}
+	}
+
+	encoded := utf16Encode("A😀")
+	if len(encoded) != 6 || nativeEndian.Uint16(encoded) != 'A' || nativeEndian.Uint16(encoded[2:]) != 0xd83d || nativeEndian.Uint16(encoded[4:]) != 0xde00 {
+		t.Errorf("utf16Encode - expected: 0041 d83d de00 - received: % x", encoded)
+	}
+	if utf16Decode(encoded[:3]) != "A" {
+		t.Errorf("utf16Decode odd length - expected: A - received: %q", utf16Decode(encoded[:3]))
+	}
+	if utf16Decode(encoded[2:4]) != "\uFFFD" {
+		t.Errorf("utf16Decode unpaired surrogate - expected: %q - received: %q", "\uFFFD", utf16Decode(encoded[2:4]))
+	}
+	if utf16Terminator([]byte{'a', 0, 'b'}) != 2 {
+		t.Errorf("utf16Terminator without terminator - expected: 2 - received: %v", utf16Terminator([]byte{'a', 0, 'b'}))
+	}
+
+	conn := &Conn{}
+	if string(conn.encodeText("abc")) != "abc" || conn.goTextTerminated([]byte("abc\x00def")) != "abc" {
+		t.Errorf("text without utf16 - expected: abc, abc - received: %q, %q", conn.encodeText("abc"), conn.goTextTerminated([]byte("abc\x00def")))
+	}
+	conn.utf16 = true
+	if conn.goTextTerminated(append(conn.encodeText("日本"), 0, 0, 'x', 0)) != "日本" {
+		t.Errorf("text with utf16 - expected: 日本 - received: %q", conn.goTextTerminated(append(conn.encodeText("日本"), 0, 0, 'x', 0)))
+	}
+}
@@ -0,0 +1,138 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ErrVectorNotSupported is returned when binding a VECTOR with oci8 built against Oracle client headers before 23ai
+var ErrVectorNotSupported = errors.New("VECTOR is not supported by the Oracle client headers oci8 was built with")
+
+// vectorDimension returns the number of dimensions of a []float32 or []float64 vector, -1 for other types
+func vectorDimension(value interface{}) int {
+	switch value := value.(type) {
+	case []float32:
+		return len(value)
+	case []float64:
+		return len(value)
+	}
+	return -1
+}
+
+// ociVectorFromSlice allocates a vector descriptor and sets it to the values of a []float32 or []float64.
+// The descriptor pointer to pointer is returned, it is freed with OCIDescriptorFree OCI_DTYPE_VECTOR.
+func (conn *Conn) ociVectorFromSlice(value interface{}) (*unsafe.Pointer, error) {
+	if C.OCI8_VECTOR_SUPPORTED == 0 {
+		return nil, ErrVectorNotSupported
+	}
+
+	dimension := vectorDimension(value)
+	if dimension < 1 {
+		return nil, fmt.Errorf("vector needs at least one dimension")
+	}
+
+	var format C.ub1
+	var array unsafe.Pointer
+	switch value := value.(type) {
+	case []float32:
+		format = C.OCI_ATTR_VECTOR_FORMAT_FLOAT32
+		array = unsafe.Pointer(&value[0])
+	case []float64:
+		format = C.OCI_ATTR_VECTOR_FORMAT_FLOAT64
+		array = unsafe.Pointer(&value[0])
+	}
+
+	vectorP, _, err := conn.ociDescriptorAlloc(C.OCI_DTYPE_VECTOR, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := C.OCIVectorFromArray(
+		(*C.OCIVector)(*vectorP), // vector descriptor
+		conn.errHandle,           // error handle
+		format,                   // format of the array values
+		C.ub4(dimension),         // number of values in the array
+		array,                    // the array
+		C.OCI_DEFAULT,            // mode
+	)
+	err = conn.getError(result)
+	if err != nil {
+		ociDescriptorFree(*vectorP, C.OCI_DTYPE_VECTOR)
+		return nil, err
+	}
+
+	return vectorP, nil
+}
+
+// ociVectorToSlice returns the values of a vector descriptor.
+// FLOAT64 vectors are returned as []float64, all other vector formats as []float32.
+func (conn *Conn) ociVectorToSlice(vector *C.OCIVector) (interface{}, error) {
+	if C.OCI8_VECTOR_SUPPORTED == 0 {
+		return nil, ErrVectorNotSupported
+	}
+
+	var dimension C.ub4
+	result := C.OCIAttrGet(
+		unsafe.Pointer(vector),      // vector descriptor
+		C.OCI_DTYPE_VECTOR,          // descriptor type
+		unsafe.Pointer(&dimension),  // the number of dimensions
+		nil,                         // size of the attribute
+		C.OCI_ATTR_VECTOR_DIMENSION, // attribute type
+		conn.errHandle,              // error handle
+	)
+	err := conn.getError(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var format C.ub1
+	result = C.OCIAttrGet(
+		unsafe.Pointer(vector),        // vector descriptor
+		C.OCI_DTYPE_VECTOR,            // descriptor type
+		unsafe.Pointer(&format),       // the format of the values
+		nil,                           // size of the attribute
+		C.OCI_ATTR_VECTOR_DATA_FORMAT, // attribute type
+		conn.errHandle,                // error handle
+	)
+	err = conn.getError(result)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == C.OCI_ATTR_VECTOR_FORMAT_FLOAT64 {
+		values := make([]float64, dimension)
+		if dimension > 0 {
+			err = conn.ociVectorToArray(vector, format, &dimension, unsafe.Pointer(&values[0]))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return values[:dimension], nil
+	}
+
+	values := make([]float32, dimension)
+	if dimension > 0 {
+		err = conn.ociVectorToArray(vector, C.OCI_ATTR_VECTOR_FORMAT_FLOAT32, &dimension, unsafe.Pointer(&values[0]))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values[:dimension], nil
+}
+
+// ociVectorToArray calls OCIVectorToArray then returns error
+func (conn *Conn) ociVectorToArray(vector *C.OCIVector, format C.ub1, dimension *C.ub4, array unsafe.Pointer) error {
+	result := C.OCIVectorToArray(
+		vector,         // vector descriptor
+		conn.errHandle, // error handle
+		format,         // format to convert the values to
+		dimension,      // IN: size of the array, OUT: number of values returned
+		array,          // the array
+		C.OCI_DEFAULT,  // mode
+	)
+	return conn.getError(result)
+}
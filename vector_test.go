@@ -0,0 +1,92 @@
+package oci8
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+// TestSelectVector checks binding and selecting VECTOR columns as []float32 and []float64, needs Oracle 23ai
+func TestSelectVector(t *testing.T) {
+	if TestDisableDatabase || TestDisableDestructive {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	tableName := "SELECT_VECTOR_" + TestTimeString
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err := TestDB.ExecContext(ctx, "create table "+tableName+" ( A INTEGER, B VECTOR(3, FLOAT32), C VECTOR(3, FLOAT64) )")
+	cancel()
+	if err != nil {
+		if isOracleError(err, 902) || isOracleError(err, 907) {
+			t.Skip("VECTOR not supported by database:", err)
+		}
+		t.Fatal("create table error:", err)
+	}
+	defer testDropTable(t, tableName)
+
+	float32s := []float32{1.5, -2.25, 3}
+	float64s := []float64{0.1, -0.2, 1e100}
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	_, err = TestDB.ExecContext(ctx, "insert into "+tableName+" ( A, B, C ) values (1, :1, :2)", float32s, float64s)
+	cancel()
+	if err != nil {
+		if strings.Contains(err.Error(), ErrVectorNotSupported.Error()) {
+			t.Skip(err)
+		}
+		t.Fatal("insert error:", err)
+	}
+	testExecQuery(t, "insert into "+tableName+" ( A, B, C ) values (2, :1, null)", []interface{}{[]float32{}})
+
+	queryResults := testQueryResults{
+		query: "select B, C from " + tableName + " order by A",
+		queryResults: []testQueryResult{
+			{
+				results: [][]interface{}{
+					{float32s, float64s},
+					{nil, nil},
+				},
+			},
+		},
+	}
+	testRunQueryResults(t, queryResults)
+
+	var b []float32
+	ctx, cancel = context.WithTimeout(context.Background(), TestContextTimeout)
+	err = TestDB.QueryRowContext(ctx, "select B from "+tableName+" where A = 1").Scan(&b)
+	cancel()
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	if len(b) != len(float32s) || b[1] != float32s[1] {
+		t.Errorf("scan - expected: %v - received: %v", float32s, b)
+	}
+}
+
+// TestVectorCheckNamedValue tests vector values are passed to the driver unconverted
+func TestVectorCheckNamedValue(t *testing.T) {
+	t.Parallel()
+
+	stmt := &Stmt{}
+	var checkTests = []struct {
+		value    interface{}
+		expected error
+	}{
+		{[]float32{1, 2}, nil},
+		{[]float64{1, 2}, nil},
+		{[]int{1, 2}, driver.ErrSkip},
+		{"a", driver.ErrSkip},
+	}
+	for _, tt := range checkTests {
+		err := stmt.CheckNamedValue(&driver.NamedValue{Value: tt.value})
+		if err != tt.expected {
+			t.Errorf("CheckNamedValue(%#v) - expected: %v - received: %v", tt.value, tt.expected, err)
+		}
+	}
+
+	if vectorDimension([]float32{1, 2, 3}) != 3 || vectorDimension([]float64(nil)) != 0 || vectorDimension("a") != -1 {
+		t.Error("vectorDimension unexpected dimension")
+	}
+}
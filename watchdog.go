@@ -0,0 +1,225 @@
+package oci8
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultWatchdogMaxWorkers is the max number of watchdog workers when the options do not set one
+	defaultWatchdogMaxWorkers = 1024
+	// defaultWatchdogIdleTimeout is how long an idle watchdog worker waits for a call before it exits when the options do not set one
+	defaultWatchdogIdleTimeout = time.Minute
+	// defaultWatchdogLeakAge is the age a watch is counted as leaked at when the options do not set one
+	defaultWatchdogLeakAge = time.Hour
+)
+
+type (
+	// WatchdogOptions are the options of the watchdog pool, the goroutines that call OCIBreak when the context of a call is done.
+	// Zero values use the defaults.
+	WatchdogOptions struct {
+		// MaxWorkers is the max number of watchdog goroutines kept for reuse, defaults to 1024.
+		// Calls watched while all of them are busy are watched by a goroutine that exits after the call, counted in Overflows.
+		MaxWorkers int
+		// IdleTimeout is how long an idle watchdog goroutine waits for a call before it exits, defaults to 1 minute
+		IdleTimeout time.Duration
+		// LeakAge is how long a call is watched before it is counted in Leaked, defaults to 1 hour.
+		// Set it above the longest expected call.
+		LeakAge time.Duration
+	}
+
+	// WatchdogStats are the statistics of the watchdog pool, see GetWatchdogStats
+	WatchdogStats struct {
+		// Workers is the number of watchdog goroutines kept for reuse
+		Workers int
+		// IdleWorkers is the number of watchdog goroutines waiting for a call
+		IdleWorkers int
+		// Active is the number of calls being watched, including the overflow ones
+		Active int
+		// Leaked is the number of calls watched for longer than the LeakAge, usually a call that is hung on the network
+		Leaked int
+		// Watches is the total number of calls watched
+		Watches int64
+		// Overflows is the total number of calls watched by a goroutine that exits after the call, as all the workers were busy
+		Overflows int64
+		// Breaks is the total number of OCIBreak calls sent because the context of a call was done
+		Breaks int64
+	}
+
+	// watchdogWork is a call watched by the watchdog pool
+	watchdogWork struct {
+		conn  *Conn
+		ctx   context.Context
+		done  chan struct{}
+		start time.Time
+	}
+
+	// watchdogPool is a pool of goroutines that call OCIBreak when the context of a call is done before the call,
+	// reused instead of starting a goroutine for each call
+	watchdogPool struct {
+		mutex     sync.Mutex
+		options   WatchdogOptions
+		work      chan *watchdogWork // idle workers receive the calls to watch
+		workers   int
+		idle      int
+		active    map[*watchdogWork]struct{}
+		watches   int64
+		overflows int64
+		breaks    int64
+	}
+)
+
+// watchdogs is the watchdog pool of the driver
+var watchdogs = newWatchdogPool(WatchdogOptions{})
+
+// SetWatchdogOptions sets the options of the watchdog pool. Workers above a lowered MaxWorkers exit when they are idle.
+func SetWatchdogOptions(options WatchdogOptions) {
+	watchdogs.setOptions(options)
+}
+
+// GetWatchdogStats returns the statistics of the watchdog pool, like for a metrics exporter
+func GetWatchdogStats() WatchdogStats {
+	return watchdogs.stats()
+}
+
+// newWatchdogPool returns a watchdog pool with the options
+func newWatchdogPool(options WatchdogOptions) *watchdogPool {
+	pool := &watchdogPool{
+		work:   make(chan *watchdogWork),
+		active: make(map[*watchdogWork]struct{}),
+	}
+	pool.setOptions(options)
+	return pool
+}
+
+// setOptions sets the options, with the defaults for the zero values
+func (pool *watchdogPool) setOptions(options WatchdogOptions) {
+	if options.MaxWorkers <= 0 {
+		options.MaxWorkers = defaultWatchdogMaxWorkers
+	}
+	if options.IdleTimeout <= 0 {
+		options.IdleTimeout = defaultWatchdogIdleTimeout
+	}
+	if options.LeakAge <= 0 {
+		options.LeakAge = defaultWatchdogLeakAge
+	}
+
+	pool.mutex.Lock()
+	pool.options = options
+	pool.mutex.Unlock()
+}
+
+// watch calls OCIBreak on the connection if ctx is done before done is closed.
+// Contexts that are never done, like context.Background, are not watched.
+func (pool *watchdogPool) watch(conn *Conn, ctx context.Context, done chan struct{}) {
+	if ctx.Done() == nil {
+		return
+	}
+
+	work := &watchdogWork{conn: conn, ctx: ctx, done: done, start: conn.now()}
+	pool.mutex.Lock()
+	pool.active[work] = struct{}{}
+	pool.watches++
+	pool.mutex.Unlock()
+
+	// hand the call to an idle worker
+	select {
+	case pool.work <- work:
+		return
+	default:
+	}
+
+	pool.mutex.Lock()
+	if pool.workers < pool.options.MaxWorkers {
+		pool.workers++
+		pool.mutex.Unlock()
+		go pool.worker(work)
+		return
+	}
+	pool.overflows++
+	pool.mutex.Unlock()
+
+	go pool.run(work)
+}
+
+// worker watches calls until it is idle for the idle timeout, or above the max workers.
+// The idle timeout is on the clock of the connection of the last call watched.
+func (pool *watchdogPool) worker(work *watchdogWork) {
+	for {
+		pool.run(work)
+
+		pool.mutex.Lock()
+		if pool.workers > pool.options.MaxWorkers {
+			pool.workers--
+			pool.mutex.Unlock()
+			return
+		}
+		pool.idle++
+		idleTimeout := pool.options.IdleTimeout
+		pool.mutex.Unlock()
+
+		timer := work.conn.newTimer(idleTimeout)
+		select {
+		case work = <-pool.work:
+			timer.Stop()
+			pool.mutex.Lock()
+			pool.idle--
+			pool.mutex.Unlock()
+		case <-timer.C():
+			pool.mutex.Lock()
+			pool.idle--
+			pool.workers--
+			pool.mutex.Unlock()
+			return
+		}
+	}
+}
+
+// run watches the call until it is done
+func (pool *watchdogPool) run(work *watchdogWork) {
+	if work.conn.ociBreakDone(work.ctx, work.done) {
+		atomic.AddInt64(&pool.breaks, 1)
+	}
+
+	pool.mutex.Lock()
+	delete(pool.active, work)
+	pool.mutex.Unlock()
+}
+
+// stats returns the statistics of the pool, the leaked calls are the ones watched for at least the leak age
+// on the clock of their connection
+func (pool *watchdogPool) stats() WatchdogStats {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	stats := WatchdogStats{
+		Workers:     pool.workers,
+		IdleWorkers: pool.idle,
+		Active:      len(pool.active),
+		Watches:     pool.watches,
+		Overflows:   pool.overflows,
+		Breaks:      atomic.LoadInt64(&pool.breaks),
+	}
+	for work := range pool.active {
+		if work.conn.since(work.start) >= pool.options.LeakAge {
+			stats.Leaked++
+		}
+	}
+	return stats
+}
+
+// watchBreak calls OCIBreak if ctx is done before stop is called, with a goroutine of the watchdog pool,
+// and sets the call timeout of the connection to the deadline of ctx.
+// stop turns the call timeout off again, so the calls that are not watched,
+// like commits and LOB reads, do not run under the deadline of an earlier call.
+func (conn *Conn) watchBreak(ctx context.Context) (stop func()) {
+	done := make(chan struct{})
+	conn.setCallTimeout(ctx)
+	watchdogs.watch(conn, ctx, done)
+	return func() {
+		close(done)
+		conn.clearCallTimeout()
+	}
+}
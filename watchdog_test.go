@@ -0,0 +1,76 @@
+package oci8
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestWatchdogPool tests the watchdog pool reuses its workers and counts the overflows and leaked calls
+func TestWatchdogPool(t *testing.T) {
+	t.Parallel()
+
+	pool := newWatchdogPool(WatchdogOptions{MaxWorkers: 2})
+	clock := &testClock{now: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+	conn := &Conn{clock: clock}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// never done, not watched
+	pool.watch(conn, context.Background(), make(chan struct{}))
+
+	dones := make([]chan struct{}, 3)
+	for i := range dones {
+		dones[i] = make(chan struct{})
+		pool.watch(conn, ctx, dones[i])
+	}
+
+	stats := pool.stats()
+	expected := WatchdogStats{Workers: 2, Active: 3, Watches: 3, Overflows: 1}
+	if stats != expected {
+		t.Errorf("stats - expected: %+v - received: %+v", expected, stats)
+	}
+	clock.mutex.Lock()
+	clock.now = clock.now.Add(2 * defaultWatchdogLeakAge)
+	clock.mutex.Unlock()
+	stats = pool.stats()
+	if stats.Leaked != 3 {
+		t.Errorf("leaked - expected: %v - received: %v", 3, stats.Leaked)
+	}
+
+	// the idle timeout is on the clock of the connection, the timers of testClock fire right away
+	for _, done := range dones {
+		close(done)
+	}
+	for i := 0; i < 500; i++ {
+		stats = pool.stats()
+		if stats.Active == 0 && stats.Workers == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	expected = WatchdogStats{Watches: 3, Overflows: 1}
+	if stats != expected {
+		t.Errorf("stats - expected: %+v - received: %+v", expected, stats)
+	}
+
+	// reuses an idle worker
+	conn = &Conn{}
+	done := make(chan struct{})
+	pool.watch(conn, ctx, done)
+	close(done)
+	for i := 0; i < 500; i++ {
+		stats = pool.stats()
+		if stats.Active == 0 && stats.IdleWorkers == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	done = make(chan struct{})
+	pool.watch(conn, ctx, done)
+	close(done)
+	stats = pool.stats()
+	if stats.Workers != 1 || stats.Overflows != 1 {
+		t.Errorf("workers, overflows - expected: %v, %v - received: %v, %v", 1, 1, stats.Workers, stats.Overflows)
+	}
+}
@@ -0,0 +1,53 @@
+package oci8
+
+// #include "oci8.go.h"
+import "C"
+
+import (
+	"database/sql/driver"
+)
+
+/*
+With Connector.YesNoBools, the common schema convention of a CHAR(1) or VARCHAR2(1) flag column
+holding Y and N, or 1 and 0, is mapped to bool:
+
+	err = db.QueryRowContext(ctx, "select ACTIVE from USERS where ID = :1", id).Scan(&active)
+	_, err = db.ExecContext(ctx, "update USERS set ACTIVE = :1 where ID = :2", false, id)
+
+The values Y, N, 1, and 0 of one character CHAR, VARCHAR2, NCHAR, and NVARCHAR2 columns are returned as bool,
+case is ignored, and other values are returned as string. Scanning such a bool into a string returns true or false.
+Bool binds are bound as Y and N instead of 1 and 0, after the ConvertBool of the Converter,
+except for PL/SQL BOOLEAN arguments.
+*/
+
+// yesNoColumn returns true when the values of the column are mapped to bool with Connector.YesNoBools
+func (rows *Rows) yesNoColumn(i int) bool {
+	if !rows.stmt.conn.yesNoBools || rows.rawBytes {
+		return false
+	}
+	switch rows.defines[i].columnType {
+	case C.SQLT_AFC, C.SQLT_CHR, C.SQLT_VCS, C.SQLT_AVC:
+		length, _ := rows.ColumnTypeLength(i)
+		return length == 1
+	}
+	return false
+}
+
+// yesNoValue returns the bool of a Y, N, 1, or 0 value, otherwise the text
+func yesNoValue(text string) driver.Value {
+	switch text {
+	case "Y", "y", "1":
+		return true
+	case "N", "n", "0":
+		return false
+	}
+	return text
+}
+
+// yesNoText returns Y for true and N for false
+func yesNoText(value bool) string {
+	if value {
+		return "Y"
+	}
+	return "N"
+}
@@ -0,0 +1,60 @@
+package oci8
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+// TestYesNoBools checks one character Y/N columns are returned as bool and bools are bound as Y and N with YesNoBools
+func TestYesNoBools(t *testing.T) {
+	if TestDisableDatabase {
+		t.SkipNow()
+	}
+
+	t.Parallel()
+
+	connector, err := NewConnectorWithOptions(ConnectorDSN(testGetOpenString("")), ConnectorYesNoBools())
+	if err != nil {
+		t.Fatal("NewConnectorWithOptions error:", err)
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestContextTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "select cast('Y' as CHAR(1)), cast('n' as VARCHAR2(1)), cast('0' as NCHAR(1)), cast('X' as CHAR(1)), cast('YN' as VARCHAR2(2)), cast(:1 as VARCHAR2(1)) from dual", true)
+	if err != nil {
+		t.Fatal("query error:", err)
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal("column types error:", err)
+	}
+	for i, expected := range []reflect.Type{typeBool, typeBool, typeBool, typeBool, typeString, typeBool} {
+		if columnTypes[i].ScanType() != expected {
+			t.Errorf("scan type %v - expected: %v - received: %v", i, expected, columnTypes[i].ScanType())
+		}
+	}
+
+	if !rows.Next() {
+		t.Fatal("no rows:", rows.Err())
+	}
+	values := make([]interface{}, 6)
+	dest := make([]interface{}, 6)
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	err = rows.Scan(dest...)
+	if err != nil {
+		t.Fatal("scan error:", err)
+	}
+	expected := []interface{}{true, false, false, "X", "YN", true}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("values - expected: %#v - received: %#v", expected, values)
+	}
+}